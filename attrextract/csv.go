@@ -0,0 +1,92 @@
+package attrextract
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// ExtractCSV reads the attributions from sbomattr CSV or TSV output (see format.CSVWithOptions), detecting the
+// delimiter from the header. The columns are matched by header, case-insensitively, and the columns that can't be
+// read back (e.g. License Category) are ignored. Multi-valued columns (versions, URLs, and sources) are split on "; ".
+// Returns an error if data is not CSV or has no Name column.
+func ExtractCSV(data []byte) ([]attribution.Attribution, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	header, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.ContainsRune(header, '\t') {
+		reader.Comma = '\t'
+	}
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse sbomattr CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("parse sbomattr CSV: no header")
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, errors.New("parse sbomattr CSV: no Name column")
+	}
+
+	attributions := make([]attribution.Attribution, 0, len(records)-1)
+	for _, record := range records[1:] {
+		attributions = append(attributions, csvAttribution(func(header string) string {
+			if i, ok := columns[header]; ok {
+				return record[i]
+			}
+			return ""
+		}))
+	}
+	return attributions, nil
+}
+
+// csvAttribution returns the attribution of a CSV row, whose values are looked up by lowercase header.
+func csvAttribution(value func(header string) string) attribution.Attribution {
+	a := attribution.Attribution{
+		Name:       value("name"),
+		Purl:       value("purl"),
+		CPE:        value("cpe"),
+		SourceRepo: value("source repository"),
+		Sources:    splitList(value("sources")),
+	}
+	if versions := splitList(value("version")); len(versions) == 1 {
+		a.Version = versions[0]
+	} else {
+		a.Versions = versions
+	}
+	if license := value("license"); license != "" {
+		a.License = &license
+	}
+	if url := value("url"); url != "" {
+		a.URL = &url
+	}
+	for _, pair := range splitList(value("urls")) {
+		kind, url, ok := strings.Cut(pair, ": ")
+		if !ok {
+			continue
+		}
+		url, dead := strings.CutSuffix(url, " (dead)")
+		a.URLs = append(a.URLs, attribution.TypedURL{Kind: attribution.URLKind(kind), URL: url, Dead: dead})
+	}
+	// A leading Source column is written with -group-by-source
+	if source := value("source"); source != "" {
+		a.Sources = []string{source}
+	}
+	return a
+}
+
+// splitList splits a multi-valued column on "; ", returning nil for an empty value.
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "; ")
+}
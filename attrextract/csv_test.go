@@ -0,0 +1,72 @@
+package attrextract_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attrextract"
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/format"
+)
+
+// TestExtractCSV tests that the CSV and TSV output of sbomattr is read back.
+func TestExtractCSV(t *testing.T) {
+	t.Parallel()
+
+	mit := "MIT"
+	lodashURL := "https://lodash.com"
+	attrs := []attribution.Attribution{
+		{
+			Name:    "lodash",
+			Version: "4.17.21",
+			License: &mit,
+			Purl:    "pkg:npm/lodash@4.17.21",
+			URL:     &lodashURL,
+			URLs: []attribution.TypedURL{
+				{Kind: attribution.URLKindHomepage, URL: lodashURL},
+				{Kind: attribution.URLKindRegistry, URL: "https://www.npmjs.com/package/lodash", Dead: true},
+			},
+			Sources: []string{"app.spdx.json", "web.spdx.json"},
+		},
+		{Name: "react", Versions: []string{"17.0.2", "18.2.0"}, Purl: "pkg:npm/react@18.2.0"},
+	}
+	columns := []string{"name", "version", "license", "purl", "url", "urls", "sources", "license-category"}
+
+	var buf bytes.Buffer
+	if err := format.CSVWithOptions(&buf, attrs, format.CSVOptions{Columns: columns}); err != nil {
+		t.Fatalf("CSVWithOptions() unexpected error: %v", err)
+	}
+	got, err := attrextract.ExtractCSV(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ExtractCSV() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, attrs) {
+		t.Errorf("ExtractCSV() = %+v, want %+v", got, attrs)
+	}
+
+	buf.Reset()
+	opts := format.CSVOptions{Delimiter: '\t', Columns: []string{"name", "url"}, GroupBySource: true}
+	if err = format.CSVWithOptions(&buf, attrs[:1], opts); err != nil {
+		t.Fatalf("CSVWithOptions() unexpected error: %v", err)
+	}
+	got, err = attrextract.ExtractCSV(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ExtractCSV() TSV unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[1].Name != "lodash" || got[1].URL == nil || *got[1].URL != lodashURL ||
+		!reflect.DeepEqual(got[1].Sources, []string{"web.spdx.json"}) {
+		t.Errorf("ExtractCSV() TSV = %+v, want lodash once per source", got)
+	}
+}
+
+// TestExtractCSV_Invalid tests that input that is not sbomattr CSV output is rejected.
+func TestExtractCSV_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"", "License,Purl\nMIT,pkg:npm/lodash\n", "Name,URL\nlodash\n", "Name\n\"lodash"} {
+		if _, err := attrextract.ExtractCSV([]byte(input)); err == nil {
+			t.Errorf("ExtractCSV(%q) expected an error", input)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+// Package attrextract provides extraction of attributions from the JSON output of sbomattr itself, so attribution
+// files produced by several teams can be aggregated centrally without access to the original SBOMs.
+//
+// Supported inputs: the output of -format json (a document with run metadata, optionally with -group-by-source), the
+// bare arrays written by format.JSON and format.JSONBySource, and -format ndjson. Every field, including notes and
+// provenance, is read back as written; the run metadata and dependency graph are ignored.
+//
+// ExtractCSV reads back the columns of CSV and TSV output, e.g. to check the URLs of a published notice. It is not
+// used by the processor, since CSV output drops most fields.
+package attrextract
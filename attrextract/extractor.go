@@ -0,0 +1,84 @@
+package attrextract
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// Extract reads the attributions from sbomattr JSON or NDJSON output. Attributions grouped by source are flattened,
+// so an attribution listed under several sources is returned once per source, to be merged by deduplication.
+// Returns an error if data is not valid JSON.
+func Extract(data []byte) ([]attribution.Attribution, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	attributions := []attribution.Attribution{}
+
+	for {
+		var raw json.RawMessage
+		err := decoder.Decode(&raw)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse sbomattr JSON: %w", err)
+		}
+
+		values := []json.RawMessage{raw}
+		if bytes.HasPrefix(raw, []byte("[")) {
+			if err = json.Unmarshal(raw, &values); err != nil {
+				return nil, fmt.Errorf("parse sbomattr JSON: %w", err)
+			}
+		}
+
+		for _, value := range values {
+			attrs, valueErr := extractValue(value)
+			if valueErr != nil {
+				return nil, fmt.Errorf("parse sbomattr JSON: %w", valueErr)
+			}
+			attributions = append(attributions, attrs...)
+		}
+	}
+
+	return attributions, nil
+}
+
+// document is the JSON document written by -format json (see format.Document).
+type document struct {
+	Metadata     json.RawMessage           `json:"metadata"`
+	Attributions []attribution.Attribution `json:"attributions"`
+	Groups       []attribution.SourceGroup `json:"groups"`
+}
+
+// extractValue decodes one attribution, a group of attributions written with -group-by-source, or a JSON document
+// with run metadata.
+func extractValue(value json.RawMessage) ([]attribution.Attribution, error) {
+	var doc document
+	if err := json.Unmarshal(value, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Metadata != nil {
+		attrs := doc.Attributions
+		for _, g := range doc.Groups {
+			attrs = append(attrs, g.Attributions...)
+		}
+		return attrs, nil
+	}
+
+	var group attribution.SourceGroup
+	if err := json.Unmarshal(value, &group); err != nil {
+		return nil, err
+	}
+	if group.Attributions != nil {
+		return group.Attributions, nil
+	}
+
+	var a attribution.Attribution
+	if err := json.Unmarshal(value, &a); err != nil {
+		return nil, err
+	}
+	return []attribution.Attribution{a}, nil
+}
@@ -0,0 +1,101 @@
+package attrextract_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attrextract"
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/format"
+)
+
+// TestExtract tests that the JSON, grouped JSON, and NDJSON output of sbomattr is read back as written.
+func TestExtract(t *testing.T) {
+	t.Parallel()
+
+	mit := "MIT"
+	attrs := []attribution.Attribution{
+		{
+			Name:       "lodash",
+			Version:    "4.17.21",
+			License:    &mit,
+			Purl:       "pkg:npm/lodash@4.17.21",
+			Notes:      []string{"CDXA claim: license verified"},
+			Provenance: map[string]attribution.Provenance{attribution.FieldLicense: attribution.ProvenanceExtracted},
+			Sources:    []string{"team-a.json"},
+		},
+		{Name: "react", Purl: "pkg:npm/react@18.2.0", Sources: []string{"team-a.json", "team-b.json"}},
+	}
+
+	tests := []struct {
+		name  string
+		write func(buf *bytes.Buffer) error
+		want  int
+	}{
+		{name: "json", write: func(buf *bytes.Buffer) error { return format.JSON(buf, attrs) }, want: 2},
+		{
+			name:  "grouped json",
+			write: func(buf *bytes.Buffer) error { return format.JSONBySource(buf, attrs) },
+			want:  3,
+		},
+		{name: "ndjson", write: func(buf *bytes.Buffer) error { return format.NDJSON(buf, attrs) }, want: 2},
+		{
+			name: "document",
+			write: func(buf *bytes.Buffer) error {
+				return format.JSONDocument(buf, attrs, format.JSONOptions{Metadata: format.Metadata{Tool: "sbomattr"}})
+			},
+			want: 2,
+		},
+		{
+			name: "grouped document",
+			write: func(buf *bytes.Buffer) error {
+				return format.JSONDocument(buf, attrs, format.JSONOptions{
+					Metadata:      format.Metadata{Tool: "sbomattr"},
+					GroupBySource: true,
+				})
+			},
+			want: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := tt.write(&buf); err != nil {
+				t.Fatalf("write error = %v", err)
+			}
+
+			got, err := attrextract.Extract(buf.Bytes())
+			if err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+			if len(got) != tt.want {
+				t.Fatalf("Extract() returned %d attributions, want %d: %+v", len(got), tt.want, got)
+			}
+
+			lodash := got[0]
+			if lodash.Name != "lodash" || lodash.License == nil || *lodash.License != "MIT" || len(lodash.Notes) != 1 ||
+				lodash.Provenance[attribution.FieldLicense] != attribution.ProvenanceExtracted {
+				t.Errorf("Extract()[0] = %+v, want lodash as written", lodash)
+			}
+		})
+	}
+}
+
+// TestExtract_Invalid tests that malformed output returns an error.
+func TestExtract_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, data := range []string{`[{"name": "lodash", "purl": `, `[{"name": 1}]`} {
+		if _, err := attrextract.Extract([]byte(data)); err == nil {
+			t.Errorf("Extract(%q) expected error, got nil", data)
+		}
+	}
+
+	got, err := attrextract.Extract([]byte("[]"))
+	if err != nil || got == nil || len(got) != 0 {
+		t.Errorf("Extract([]) = %v, %v, want an empty slice", got, err)
+	}
+}
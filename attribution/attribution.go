@@ -9,10 +9,88 @@ package attribution
 type Attribution struct {
 	// Name is the package name
 	Name string `json:"name"`
+	// Group is the group or namespace of the package, e.g. a Maven groupId or an npm scope (CycloneDX group)
+	Group string `json:"group,omitempty"`
+	// Version is the package version
+	Version string `json:"version,omitempty"`
+	// Versions lists every version of the package, in input order, when a deduplication mode merged several versions
+	// into one attribution (see DedupMode); Version is then the first of them
+	Versions []string `json:"versions,omitempty"`
+	// Description is a short description of what the package does, if the SBOM records one
+	Description string `json:"description,omitempty"`
+	// Type is the kind of component, e.g. "library", "application", "operating-system", or "file" (CycloneDX type)
+	Type string `json:"type,omitempty"`
+	// Scope is whether the component is needed at runtime: "required", "optional", or "excluded" (CycloneDX scope)
+	Scope string `json:"scope,omitempty"`
+	// Supplier is the organization or person that supplied the package (SPDX supplier, CycloneDX supplier, or else
+	// CycloneDX publisher)
+	Supplier string `json:"supplier,omitempty"`
+	// SupplierParty is the structured supplier (kind, name, and email), when the SBOM encodes one (SPDX supplier)
+	SupplierParty *Party `json:"supplierParty,omitempty"`
+	// Author is the person or organization that authored the package (CycloneDX author, SPDX originator), e.g. for
+	// copyright lines
+	Author string `json:"author,omitempty"`
+	// Originator is the structured author (kind, name, and email), when the SBOM encodes one (SPDX originator)
+	Originator *Party `json:"originator,omitempty"`
+	// FirstParty is set on a company's own packages, when they are flagged rather than excluded (see FirstPartyRules)
+	FirstParty bool `json:"firstParty,omitempty"`
 	// License is the declared license
 	License *string `json:"license,omitempty"`
-	// URL is the package URL
+	// LicenseDeclared is the license declared by the package authors, if the SBOM distinguishes it (SPDX)
+	LicenseDeclared *string `json:"licenseDeclared,omitempty"`
+	// LicenseConcluded is the license concluded by the SBOM creator, if the SBOM distinguishes it (SPDX)
+	LicenseConcluded *string `json:"licenseConcluded,omitempty"`
+	// LicenseURL links to the license text, so notices can point at it directly (CycloneDX license reference or license
+	// url, SPDX seeAlso of an extracted license)
+	LicenseURL string `json:"licenseUrl,omitempty"`
+	// LicenseText is the full license text of the package, e.g. its LICENSE file (CycloneDX license text, SPDX
+	// extracted license text, or fetched from upstream, see the licensefetch package)
+	LicenseText string `json:"licenseText,omitempty"`
+	// Exception is the SPDX license exception, set when a "<license> WITH <exception>" expression is split
+	Exception *string `json:"exception,omitempty"`
+	// URL is the primary package URL, the one shown in notices and tabular output
 	URL *string `json:"url,omitempty"`
+	// URLs lists every URL known for the package, with the kind of page each points at (homepage, registry page,
+	// source repository, ...), in order of preference; the primary URL is usually the first of them
+	URLs []TypedURL `json:"urls,omitempty"`
+	// SourceRepo is the source code repository of the package, distinct from URL, for license obligations that require
+	// pointing at the source code (SPDX VCS downloadLocation or sourceInfo, CycloneDX vcs reference)
+	SourceRepo string `json:"sourceRepo,omitempty"`
 	// Purl is the package purl
 	Purl string `json:"purl"`
+	// Hashes maps checksum algorithms (normalized with NormalizeHashAlgorithm, e.g. "SHA256") to hex digests of the
+	// package artifact, tying the attribution to an exact artifact
+	Hashes map[string]string `json:"hashes,omitempty"`
+	// CPE is the package CPE identifier (preferably CPE 2.3), for correlating with vulnerability data
+	CPE string `json:"cpe,omitempty"`
+	// AttributionTexts are the attribution notices the package requires to be reproduced verbatim, e.g. copyright
+	// and credit lines (SPDX attributionTexts)
+	AttributionTexts []string `json:"attributionTexts,omitempty"`
+	// Notes holds compliance remarks shipped with the SBOM, such as licensing claims from CycloneDX attestations
+	Notes []string `json:"notes,omitempty"`
+	// Provenance records which stage produced each field (keyed by field name, e.g. FieldLicense)
+	Provenance map[string]Provenance `json:"provenance,omitempty"`
+	// Sources lists the SBOM files the attribution was found in
+	Sources []string `json:"sources,omitempty"`
+}
+
+// VersionList returns the versions of the package: Versions if deduplication merged several versions, otherwise
+// Version, or nil if the version is unknown.
+func (a Attribution) VersionList() []string {
+	if len(a.Versions) > 0 {
+		return a.Versions
+	}
+	if a.Version == "" {
+		return nil
+	}
+	return []string{a.Version}
+}
+
+// QualifiedName returns the package name qualified by its group, e.g. "org.apache.commons/commons-lang3" or
+// "@angular/core", or just the name if the package has no group.
+func (a Attribution) QualifiedName() string {
+	if a.Group == "" {
+		return a.Name
+	}
+	return a.Group + "/" + a.Name
 }
@@ -0,0 +1,36 @@
+package attribution_test
+
+import (
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestAttribution_QualifiedName tests that the name is qualified by the group when there is one.
+func TestAttribution_QualifiedName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a    attribution.Attribution
+		want string
+	}{
+		{name: "no group", a: attribution.Attribution{Name: "lodash"}, want: "lodash"},
+		{name: "npm scope", a: attribution.Attribution{Name: "core", Group: "@angular"}, want: "@angular/core"},
+		{
+			name: "maven group",
+			a:    attribution.Attribution{Name: "commons-lang3", Group: "org.apache.commons"},
+			want: "org.apache.commons/commons-lang3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.a.QualifiedName(); got != tt.want {
+				t.Errorf("QualifiedName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
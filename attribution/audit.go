@@ -0,0 +1,58 @@
+package attribution
+
+// DedupAudit collects the decisions made by DeduplicateAudited, so compliance auditors can verify that the
+// aggregation did not hide conflicting license claims.
+type DedupAudit struct {
+	// Decisions lists a decision for every attribution that had duplicates, in input order.
+	Decisions []DedupDecision `json:"decisions"`
+}
+
+// DedupDecision records how the duplicates of one attribution were merged.
+type DedupDecision struct {
+	// Key is the deduplication key: the canonical purl, or the qualified name if there is no purl (see DedupKey).
+	Key string `json:"key"`
+	// Kept is the attribution that was kept, as it was before its duplicates were merged into it.
+	Kept Attribution `json:"kept"`
+	// Dropped lists the duplicates that were merged into the kept attribution.
+	Dropped []DroppedDuplicate `json:"dropped"`
+}
+
+// DroppedDuplicate is a duplicate dropped by deduplication.
+type DroppedDuplicate struct {
+	// Attribution is the dropped attribution.
+	Attribution Attribution `json:"attribution"`
+	// DifferingFields lists the fields whose values differ from the kept attribution (named as in JSON output, e.g.
+	// "license"), so conflicting claims stand out.
+	DifferingFields []string `json:"differingFields,omitempty"`
+}
+
+// differingFields returns the names of the fields whose values differ between two attributions.
+// Only the fields describing the package are compared, not bookkeeping such as Sources or Provenance.
+func differingFields(a, b Attribution) []string {
+	fields := []struct {
+		name  string
+		value func(Attribution) string
+	}{
+		{name: "name", value: Attribution.QualifiedName},
+		{name: "version", value: func(a Attribution) string { return a.Version }},
+		{name: "license", value: func(a Attribution) string { return derefString(a.License) }},
+		{name: "licenseDeclared", value: func(a Attribution) string { return derefString(a.LicenseDeclared) }},
+		{name: "licenseConcluded", value: func(a Attribution) string { return derefString(a.LicenseConcluded) }},
+		{name: "licenseUrl", value: func(a Attribution) string { return a.LicenseURL }},
+		{name: "exception", value: func(a Attribution) string { return derefString(a.Exception) }},
+		{name: "url", value: func(a Attribution) string { return derefString(a.URL) }},
+		{name: "sourceRepo", value: func(a Attribution) string { return a.SourceRepo }},
+		{name: "supplier", value: func(a Attribution) string { return a.Supplier }},
+		{name: "author", value: func(a Attribution) string { return a.Author }},
+		{name: "cpe", value: func(a Attribution) string { return a.CPE }},
+		{name: "scope", value: func(a Attribution) string { return a.Scope }},
+	}
+
+	var differing []string
+	for _, f := range fields {
+		if f.value(a) != f.value(b) {
+			differing = append(differing, f.name)
+		}
+	}
+	return differing
+}
@@ -0,0 +1,47 @@
+package attribution_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestDeduplicateAudited tests that every merge decision is recorded with the fields that differ.
+func TestDeduplicateAudited(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "lodash", Purl: "pkg:npm/lodash@4.17.21", License: strPtr("MIT"), Sources: []string{"a.json"}},
+		{Name: "left-pad", Purl: "pkg:npm/left-pad@1.3.0"},
+		{Name: "core"},
+		{Name: "core"},
+		{Name: "lodash", Purl: "pkg:npm/lodash@4.17.21", License: strPtr("GPL-3.0-only"), Sources: []string{"b.json"}},
+	}
+
+	audit := &attribution.DedupAudit{}
+	got := attribution.DeduplicateAudited(input, audit, nil)
+
+	if len(got) != 3 {
+		t.Fatalf("DeduplicateAudited() returned %d attributions, want 3", len(got))
+	}
+	if len(audit.Decisions) != 2 {
+		t.Fatalf("audit has %d decisions, want 2: %+v", len(audit.Decisions), audit.Decisions)
+	}
+
+	lodash := audit.Decisions[0]
+	if lodash.Key != "pkg:npm/lodash@4.17.21" || len(lodash.Dropped) != 1 {
+		t.Fatalf("Decisions[0] = %+v, want the lodash decision first", lodash)
+	}
+	if lodash.Kept.Sources[0] != "a.json" || len(lodash.Kept.Sources) != 1 {
+		t.Errorf("Decisions[0].Kept.Sources = %v, want the kept entry before merging", lodash.Kept.Sources)
+	}
+	if want := []string{"license"}; !slices.Equal(lodash.Dropped[0].DifferingFields, want) {
+		t.Errorf("Decisions[0].Dropped[0].DifferingFields = %v, want %v", lodash.Dropped[0].DifferingFields, want)
+	}
+
+	core := audit.Decisions[1]
+	if core.Key != "core" || core.Dropped[0].DifferingFields != nil {
+		t.Errorf("Decisions[1] = %+v, want identical core duplicates", core)
+	}
+}
@@ -0,0 +1,134 @@
+package attribution
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/package-url/packageurl-go"
+)
+
+// PackageID identifies a package within an ecosystem: the namespace, name, and version components of its purl.
+type PackageID struct {
+	// Namespace is the purl namespace, e.g. a Maven groupId or an npm scope.
+	Namespace string
+	// Name is the package name.
+	Name string
+	// Version is the package version.
+	Version string
+}
+
+// Canonicalizer returns the canonical form of a package identity in one ecosystem, so spellings that the ecosystem
+// treats as the same package (e.g. "Django" and "django" on PyPI) are recognized as one.
+type Canonicalizer func(id PackageID) PackageID
+
+// canonicalizers holds the canonicalizers by purl type, starting with the built-in ones.
+//
+//nolint:gochecknoglobals // RegisterCanonicalizer must be callable from any package, like format.Register.
+var canonicalizers = struct {
+	sync.RWMutex
+	byType map[string]Canonicalizer
+}{
+	byType: map[string]Canonicalizer{
+		"npm":       lowerCanonicalizer,
+		"pypi":      canonicalPyPI,
+		"golang":    canonicalGo,
+		"nuget":     canonicalNuGet,
+		"composer":  lowerCanonicalizer,
+		"cargo":     canonicalCargo,
+		"github":    lowerCanonicalizer,
+		"gitlab":    lowerCanonicalizer,
+		"bitbucket": lowerCanonicalizer,
+		"hex":       lowerCanonicalizer,
+		"pub":       lowerCanonicalizer,
+	},
+}
+
+// RegisterCanonicalizer sets the canonicalizer of a purl type (e.g. "maven"), replacing the built-in one if any, so
+// programs embedding sbomattr can adapt how packages of an ecosystem are recognized across deduplication and
+// baseline comparisons, and which URL PurlToURL builds for them. Built-in canonicalizers exist for npm, pypi, golang,
+// nuget, composer, cargo, github, gitlab, bitbucket, hex, and pub.
+// It panics if the purl type is empty or c is nil.
+func RegisterCanonicalizer(purlType string, c Canonicalizer) {
+	if purlType == "" {
+		panic("attribution: RegisterCanonicalizer purl type is empty")
+	}
+	if c == nil {
+		panic("attribution: RegisterCanonicalizer canonicalizer is nil")
+	}
+
+	canonicalizers.Lock()
+	defer canonicalizers.Unlock()
+	canonicalizers.byType[strings.ToLower(purlType)] = c
+}
+
+// CanonicalPurl returns the canonical form of a purl: its type lowercased, its namespace, name, and version
+// canonicalized by the canonicalizer of its type (see RegisterCanonicalizer), and its qualifiers sorted.
+// Returns the purl unchanged if it cannot be parsed.
+func CanonicalPurl(purl string) string {
+	parsed, err := packageurl.FromString(purl)
+	if err != nil {
+		return purl
+	}
+	canonical := canonicalPackageURL(parsed)
+	return canonical.ToString()
+}
+
+// canonicalPackageURL returns the canonical form of a parsed purl, as described in CanonicalPurl.
+func canonicalPackageURL(purl packageurl.PackageURL) packageurl.PackageURL {
+	purlType := strings.ToLower(purl.Type)
+	id := PackageID{Namespace: purl.Namespace, Name: purl.Name, Version: purl.Version}
+
+	canonicalizers.RLock()
+	canonicalize, ok := canonicalizers.byType[purlType]
+	canonicalizers.RUnlock()
+	if ok {
+		id = canonicalize(id)
+	}
+
+	qualifiers := slices.Clone(purl.Qualifiers)
+	slices.SortFunc(qualifiers, func(a, b packageurl.Qualifier) int { return cmp.Compare(a.Key, b.Key) })
+	return *packageurl.NewPackageURL(purlType, id.Namespace, id.Name, id.Version, qualifiers, purl.Subpath)
+}
+
+// lowerCanonicalizer lowercases the namespace and name, for ecosystems whose package names are case-insensitive
+// (npm, Composer, GitHub, GitLab, Bitbucket, Hex, pub).
+func lowerCanonicalizer(id PackageID) PackageID {
+	id.Namespace = strings.ToLower(id.Namespace)
+	id.Name = strings.ToLower(id.Name)
+	return id
+}
+
+// pypiSeparators matches the runs of separators that PEP 503 treats as equivalent in Python package names.
+var pypiSeparators = regexp.MustCompile(`[-_.]+`)
+
+// canonicalPyPI normalizes the name as in PEP 503 ("Foo.Bar_baz" becomes "foo-bar-baz") and lowercases the version
+// without a "v" prefix, as in PEP 440.
+func canonicalPyPI(id PackageID) PackageID {
+	id.Name = pypiSeparators.ReplaceAllString(strings.ToLower(id.Name), "-")
+	id.Version = strings.TrimPrefix(strings.ToLower(id.Version), "v")
+	return id
+}
+
+// canonicalGo adds the "v" prefix that Go module versions always have, which some generators drop.
+func canonicalGo(id PackageID) PackageID {
+	if id.Version != "" && id.Version[0] >= '0' && id.Version[0] <= '9' {
+		id.Version = "v" + id.Version
+	}
+	return id
+}
+
+// canonicalNuGet lowercases the name and version, since NuGet package IDs and versions are case-insensitive.
+func canonicalNuGet(id PackageID) PackageID {
+	id = lowerCanonicalizer(id)
+	id.Version = strings.ToLower(id.Version)
+	return id
+}
+
+// canonicalCargo lowercases the name and treats "_" like "-", as crates.io does when checking name uniqueness.
+func canonicalCargo(id PackageID) PackageID {
+	id.Name = strings.ReplaceAll(strings.ToLower(id.Name), "_", "-")
+	return id
+}
@@ -0,0 +1,67 @@
+package attribution_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestCanonicalPurl tests that purls are canonicalized by the built-in canonicalizer of their ecosystem.
+func TestCanonicalPurl(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		purl string
+		want string
+	}{
+		{name: "npm scope", purl: "pkg:NPM/%40Angular/Core@17.0.0", want: "pkg:npm/%40angular/core@17.0.0"},
+		{name: "pypi name", purl: "pkg:pypi/Zope.Interface_Foo@V6.0", want: "pkg:pypi/zope-interface-foo@6.0"},
+		{name: "golang version", purl: "pkg:golang/github.com/spf13/cobra@1.8.0",
+			want: "pkg:golang/github.com/spf13/cobra@v1.8.0"},
+		{name: "golang pseudo version", purl: "pkg:golang/golang.org/x/mod@v0.14.0",
+			want: "pkg:golang/golang.org/x/mod@v0.14.0"},
+		{name: "nuget", purl: "pkg:nuget/Newtonsoft.Json@13.0.3-Beta1", want: "pkg:nuget/newtonsoft.json@13.0.3-beta1"},
+		{name: "cargo", purl: "pkg:cargo/Serde_JSON@1.0.0", want: "pkg:cargo/serde-json@1.0.0"},
+		{name: "pypi separators", purl: "pkg:pypi/Foo_Bar@1.0", want: "pkg:pypi/foo-bar@1.0"},
+		{name: "hex", purl: "pkg:hex/Acme/Phoenix@1.7.0", want: "pkg:hex/acme/phoenix@1.7.0"},
+		{name: "pub", purl: "pkg:pub/HTTP@1.1.0", want: "pkg:pub/http@1.1.0"},
+		{name: "gitlab", purl: "pkg:gitlab/GitLab-Org/GitLab@v16.0.0", want: "pkg:gitlab/gitlab-org/gitlab@v16.0.0"},
+		{name: "maven is case sensitive", purl: "pkg:maven/org.Apache/Commons@1.0",
+			want: "pkg:maven/org.Apache/Commons@1.0"},
+		{name: "qualifiers sorted", purl: "pkg:deb/debian/curl@8.0?distro=bookworm&arch=amd64",
+			want: "pkg:deb/debian/curl@8.0?arch=amd64&distro=bookworm"},
+		{name: "invalid", purl: "not a purl", want: "not a purl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := attribution.CanonicalPurl(tt.purl); got != tt.want {
+				t.Errorf("CanonicalPurl(%q) = %q, want %q", tt.purl, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRegisterCanonicalizer tests that a registered canonicalizer is used for its purl type.
+func TestRegisterCanonicalizer(t *testing.T) {
+	t.Parallel()
+
+	attribution.RegisterCanonicalizer("Test-Canonical", func(id attribution.PackageID) attribution.PackageID {
+		id.Version = strings.TrimSuffix(id.Version, ".0")
+		return id
+	})
+
+	if got := attribution.CanonicalPurl("pkg:test-canonical/lib@2.0"); got != "pkg:test-canonical/lib@2" {
+		t.Errorf("CanonicalPurl() = %q, want the registered canonicalizer applied", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterCanonicalizer() with a nil canonicalizer did not panic")
+		}
+	}()
+	attribution.RegisterCanonicalizer("test-nil", nil)
+}
@@ -0,0 +1,91 @@
+package attribution
+
+import (
+	"cmp"
+	"log/slog"
+	"slices"
+)
+
+// Group is the attributions sharing a key, as returned by GroupBy.
+type Group[K comparable] struct {
+	// Key is the key shared by the attributions.
+	Key K `json:"key"`
+	// Attributions are the attributions with the key, in input order.
+	Attributions []Attribution `json:"attributions"`
+}
+
+// Filter returns a copy of attributions with only the attributions for which keep returns true, for exclusion rules
+// not covered by ExcludeScopes and ExcludeFirstParty.
+func Filter(attributions []Attribution, keep func(Attribution) bool) []Attribution {
+	result := make([]Attribution, 0, len(attributions))
+	for _, a := range attributions {
+		if keep(a) {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// SortBy returns a copy of attributions sorted by the key returned by key. The sort is stable, so attributions with
+// equal keys keep their order: sort with Sort first to break ties deterministically.
+func SortBy[K cmp.Ordered](attributions []Attribution, key func(Attribution) K) []Attribution {
+	result := slices.Clone(attributions)
+	slices.SortStableFunc(result, func(a, b Attribution) int { return cmp.Compare(key(a), key(b)) })
+	return result
+}
+
+// GroupBy groups attributions by the key returned by key, in order of first appearance, e.g. by license or supplier.
+// Use GroupBySource to group by SBOM file, since an attribution can be found in several files.
+func GroupBy[K comparable](attributions []Attribution, key func(Attribution) K) []Group[K] {
+	index := make(map[K]int)
+	var groups []Group[K]
+
+	for _, a := range attributions {
+		k := key(a)
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, Group[K]{Key: k})
+		}
+		groups[i].Attributions = append(groups[i].Attributions, a)
+	}
+	return groups
+}
+
+// DedupKey returns the key Deduplicate identifies an attribution by: its canonical purl (see CanonicalPurl), or its
+// name qualified by its group (see QualifiedName) if it has no purl.
+func DedupKey(a Attribution) string {
+	if a.Purl != "" {
+		return CanonicalPurl(a.Purl)
+	}
+	return a.QualifiedName()
+}
+
+// DeduplicateBy removes duplicate attributions sharing the key returned by key, e.g. the name to list every version
+// of a package once. Duplicates are merged into the first occurrence like in Deduplicate.
+// The logger parameter is optional; pass nil to disable logging.
+func DeduplicateBy[K comparable](
+	attributions []Attribution,
+	key func(Attribution) K,
+	logger *slog.Logger,
+) []Attribution {
+	seen := make(map[K]int)
+	result := make([]Attribution, 0, len(attributions))
+
+	for _, a := range attributions {
+		k := key(a)
+		i, ok := seen[k]
+		if !ok {
+			seen[k] = len(result)
+			result = append(result, a)
+			continue
+		}
+
+		if logger != nil {
+			logger.Debug("skipping duplicate attribution", "key", k)
+		}
+		result[i] = mergeDuplicate(result[i], a)
+	}
+	return result
+}
@@ -0,0 +1,147 @@
+package attribution_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// names returns the names of attributions, in order.
+func names(attributions []attribution.Attribution) []string {
+	result := make([]string, 0, len(attributions))
+	for _, a := range attributions {
+		result = append(result, a.Name)
+	}
+	return result
+}
+
+// TestFilter tests the Filter function.
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "react", Type: "library"},
+		{Name: "node", Type: "platform"},
+		{Name: "lodash", Type: "library"},
+	}
+
+	got := attribution.Filter(input, func(a attribution.Attribution) bool { return a.Type == "library" })
+	if want := []string{"react", "lodash"}; !slices.Equal(names(got), want) {
+		t.Errorf("Filter() names = %v, want %v", names(got), want)
+	}
+	if len(input) != 3 {
+		t.Errorf("Filter() modified its input: %v", input)
+	}
+}
+
+// TestSortBy tests the SortBy function.
+func TestSortBy(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "react", Supplier: "Meta"},
+		{Name: "lodash", Supplier: "OpenJS"},
+		{Name: "jest", Supplier: "Meta"},
+		{Name: "manual"},
+	}
+
+	got := attribution.SortBy(input, func(a attribution.Attribution) string { return a.Supplier })
+	if want := []string{"manual", "react", "jest", "lodash"}; !slices.Equal(names(got), want) {
+		t.Errorf("SortBy() names = %v, want %v (stable)", names(got), want)
+	}
+	if input[0].Name != "react" {
+		t.Errorf("SortBy() modified its input: %v", names(input))
+	}
+}
+
+// TestGroupBy tests the GroupBy function.
+func TestGroupBy(t *testing.T) {
+	t.Parallel()
+
+	mit := "MIT"
+	apache := "Apache-2.0"
+	input := []attribution.Attribution{
+		{Name: "react", License: &mit},
+		{Name: "guava", License: &apache},
+		{Name: "lodash", License: &mit},
+	}
+
+	got := attribution.GroupBy(input, func(a attribution.Attribution) string { return *a.License })
+
+	want := []struct {
+		key   string
+		names []string
+	}{
+		{key: "MIT", names: []string{"react", "lodash"}},
+		{key: "Apache-2.0", names: []string{"guava"}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("GroupBy() returned %d groups, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].Key != w.key {
+			t.Errorf("GroupBy()[%d].Key = %q, want %q", i, got[i].Key, w.key)
+		}
+		if !slices.Equal(names(got[i].Attributions), w.names) {
+			t.Errorf("GroupBy()[%d] names = %v, want %v", i, names(got[i].Attributions), w.names)
+		}
+	}
+
+	if got = attribution.GroupBy(nil, attribution.DedupKey); len(got) != 0 {
+		t.Errorf("GroupBy(nil) = %v, want empty", got)
+	}
+}
+
+// TestDedupKey tests the DedupKey function.
+func TestDedupKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a    attribution.Attribution
+		want string
+	}{
+		{
+			name: "purl",
+			a:    attribution.Attribution{Name: "lodash", Purl: "pkg:npm/lodash@4.17.21"},
+			want: "pkg:npm/lodash@4.17.21",
+		},
+		{name: "qualified name", a: attribution.Attribution{Name: "core", Group: "@babel"}, want: "@babel/core"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := attribution.DedupKey(tt.a); got != tt.want {
+				t.Errorf("DedupKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDeduplicateBy tests the DeduplicateBy function.
+func TestDeduplicateBy(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "lodash", Version: "4.17.20", Scope: attribution.ScopeOptional, Sources: []string{"web.json"}},
+		{Name: "react", Version: "18.2.0"},
+		{Name: "lodash", Version: "4.17.21", Sources: []string{"api.json"}},
+	}
+
+	got := attribution.DeduplicateBy(input, func(a attribution.Attribution) string { return a.Name }, nil)
+
+	if want := []string{"lodash", "react"}; !slices.Equal(names(got), want) {
+		t.Fatalf("DeduplicateBy() names = %v, want %v", names(got), want)
+	}
+	if got[0].Version != "4.17.20" {
+		t.Errorf("DeduplicateBy() kept version %q, want the first occurrence", got[0].Version)
+	}
+	if want := []string{"web.json", "api.json"}; !slices.Equal(got[0].Sources, want) {
+		t.Errorf("DeduplicateBy() sources = %v, want %v", got[0].Sources, want)
+	}
+	if got[0].Scope != "" {
+		t.Errorf("DeduplicateBy() scope = %q, want the widest scope (none)", got[0].Scope)
+	}
+}
@@ -0,0 +1,151 @@
+package attribution
+
+import (
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+)
+
+// CorrelateOptions configures CorrelateRepositories.
+type CorrelateOptions struct {
+	// Merge merges correlated attributions into the first one, instead of only linking them with notes.
+	Merge bool
+}
+
+// RepositoryKey returns the source repository of the package, e.g. "github.com/spf13/cobra", derived from a
+// repository purl (github, gitlab, bitbucket), a Go module path or Swift package hosted on one of these services, or a
+// repository URL.
+// Returns ok as false if the repository is unknown.
+func RepositoryKey(a Attribution) (string, bool) {
+	if purl, err := packageurl.FromString(a.Purl); err == nil {
+		switch purl.Type {
+		case "github", "gitlab", "bitbucket":
+			return repositoryKey(repositoryHost(purl.Type), purl.Namespace+"/"+purl.Name)
+		case "golang", "swift":
+			host, repoPath, _ := strings.Cut(purl.Namespace+"/"+purl.Name, "/")
+			return repositoryKey(host, repoPath)
+		}
+	}
+
+	if a.URL != nil {
+		if u, err := url.Parse(*a.URL); err == nil && (u.Scheme == "https" || u.Scheme == "http") {
+			return repositoryKey(u.Host, u.Path)
+		}
+	}
+
+	return "", false
+}
+
+// repositoryHost returns the host of the source hosting service whose repositories a purl type names.
+func repositoryHost(purlType string) string {
+	switch purlType {
+	case "gitlab":
+		return "gitlab.com"
+	case "bitbucket":
+		return "bitbucket.org"
+	default:
+		return "github.com"
+	}
+}
+
+// repositoryKey builds a repository key from a host and the owner/repository path, ignoring anything after the
+// repository. Returns ok as false if the host is not a known hosting service (GitHub, GitLab, or Bitbucket) or the
+// path is incomplete.
+func repositoryKey(host, repoPath string) (string, bool) {
+	host = strings.ToLower(strings.TrimPrefix(host, "www."))
+	if host != "github.com" && host != "gitlab.com" && host != "bitbucket.org" {
+		return "", false
+	}
+
+	const ownerAndRepo = 3 // owner, repository, and the rest
+	parts := strings.SplitN(strings.Trim(repoPath, "/"), "/", ownerAndRepo)
+	if len(parts) < ownerAndRepo-1 || parts[0] == "" || parts[1] == "" {
+		return "", false
+	}
+
+	return strings.ToLower(host + "/" + parts[0] + "/" + strings.TrimSuffix(parts[1], ".git")), true
+}
+
+// CorrelateRepositories returns a copy of attributions where packages listed under several purl types (e.g. a Go
+// module that is also listed as a github purl) are correlated by their source repository (see RepositoryKey).
+// Only attributions with different purl types and the same version (ignoring a "v" prefix, or with no version) are
+// correlated; packages of the same ecosystem are left to Deduplicate.
+//
+// By default, every correlated attribution gets a note linking it to the others ("same repository as <purl>"). With
+// opts.Merge, the later ones are merged into the first: their sources and notes are added to it, along with an "also
+// identified as <purl>" note, and they are dropped.
+// The logger parameter is optional; pass nil to disable logging.
+func CorrelateRepositories(attributions []Attribution, opts CorrelateOptions, logger *slog.Logger) []Attribution {
+	result := make([]Attribution, 0, len(attributions))
+	groups := make(map[string][]int)
+	merged := make(map[int]bool)
+
+	for _, a := range attributions {
+		i := len(result)
+		result = append(result, a)
+
+		key, ok := RepositoryKey(a)
+		if !ok {
+			continue
+		}
+		first, found := correlatedWith(result, groups[key], a)
+		if !found {
+			groups[key] = append(groups[key], i)
+			continue
+		}
+
+		if logger != nil {
+			logger.Debug("correlated packages by repository", "repository", key, "purl", a.Purl,
+				"correlated", result[first].Purl)
+		}
+		if opts.Merge {
+			result[first].Sources = mergeUnique(result[first].Sources, a.Sources)
+			result[first].Notes = mergeUnique(result[first].Notes, []string{"also identified as " + a.Purl})
+			result[first].Notes = mergeUnique(result[first].Notes, a.Notes)
+			merged[i] = true
+			continue
+		}
+		result[first].Notes = mergeUnique(result[first].Notes, []string{"same repository as " + a.Purl})
+		result[i].Notes = mergeUnique(result[i].Notes, []string{"same repository as " + result[first].Purl})
+		groups[key] = append(groups[key], i)
+	}
+
+	if len(merged) == 0 {
+		return result
+	}
+	kept := make([]Attribution, 0, len(result)-len(merged))
+	for i, a := range result {
+		if !merged[i] {
+			kept = append(kept, a)
+		}
+	}
+	return kept
+}
+
+// correlatedWith returns the index of the first attribution of the group that a correlates with: one with a different
+// purl type and a compatible version. Returns found as false if there is none.
+func correlatedWith(attributions []Attribution, group []int, a Attribution) (int, bool) {
+	for _, i := range group {
+		if purlType(attributions[i].Purl) != purlType(a.Purl) && sameVersion(attributions[i].Version, a.Version) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// purlType returns the type of a purl, or an empty string if it has none.
+func purlType(purl string) string {
+	rest, ok := strings.CutPrefix(purl, "pkg:")
+	if !ok {
+		return ""
+	}
+	purlType, _, _ := strings.Cut(rest, "/")
+	return strings.ToLower(purlType)
+}
+
+// sameVersion reports whether two versions are equal ignoring a "v" prefix, or either is unknown.
+func sameVersion(a, b string) bool {
+	return a == "" || b == "" || strings.TrimPrefix(a, "v") == strings.TrimPrefix(b, "v")
+}
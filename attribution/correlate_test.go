@@ -0,0 +1,134 @@
+package attribution_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestRepositoryKey tests deriving the source repository from purls and URLs.
+func TestRepositoryKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		a      attribution.Attribution
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "github purl",
+			a:      attribution.Attribution{Purl: "pkg:github/Spf13/cobra@v1.8.0"},
+			want:   "github.com/spf13/cobra",
+			wantOK: true,
+		},
+		{
+			name:   "go module",
+			a:      attribution.Attribution{Purl: "pkg:golang/github.com/spf13/cobra@v1.8.0"},
+			want:   "github.com/spf13/cobra",
+			wantOK: true,
+		},
+		{
+			name:   "go module in a subdirectory",
+			a:      attribution.Attribution{Purl: "pkg:golang/gitlab.com/acme/tools/cli/v2@v2.0.0"},
+			want:   "gitlab.com/acme/tools",
+			wantOK: true,
+		},
+		{
+			name:   "swift package",
+			a:      attribution.Attribution{Purl: "pkg:swift/github.com/Alamofire/Alamofire@5.6.0"},
+			want:   "github.com/alamofire/alamofire",
+			wantOK: true,
+		},
+		{
+			name: "repository URL",
+			a: attribution.Attribution{
+				Purl: "pkg:npm/lodash@4.17.21",
+				URL:  strPtr("https://www.github.com/lodash/lodash.git"),
+			},
+			want:   "github.com/lodash/lodash",
+			wantOK: true,
+		},
+		{name: "vanity go module", a: attribution.Attribution{Purl: "pkg:golang/golang.org/x/text@v0.14.0"}},
+		{name: "registry URL", a: attribution.Attribution{URL: strPtr("https://www.npmjs.com/package/lodash")}},
+		{name: "owner URL", a: attribution.Attribution{URL: strPtr("https://github.com/lodash")}},
+		{name: "nothing", a: attribution.Attribution{Name: "lodash"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := attribution.RepositoryKey(tt.a)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("RepositoryKey() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestCorrelateRepositories tests linking and merging packages listed under several purl types.
+func TestCorrelateRepositories(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "github.com/spf13/cobra", Version: "v1.8.0", Purl: "pkg:golang/github.com/spf13/cobra@v1.8.0",
+			Sources: []string{"go.json"}},
+		{Name: "lodash", Purl: "pkg:npm/lodash@4.17.21"},
+		{Name: "cobra", Version: "1.8.0", Purl: "pkg:github/spf13/cobra@1.8.0", Sources: []string{"gh.json"}},
+		{Name: "cobra", Version: "1.7.0", Purl: "pkg:github/spf13/cobra@1.7.0"},
+		{Name: "github.com/spf13/cobra/doc", Version: "v1.8.0", Purl: "pkg:golang/github.com/spf13/cobra/doc@v1.8.0"},
+	}
+
+	t.Run("link", func(t *testing.T) {
+		t.Parallel()
+
+		got := attribution.CorrelateRepositories(input, attribution.CorrelateOptions{}, nil)
+
+		if len(got) != len(input) {
+			t.Fatalf("CorrelateRepositories() returned %d attributions, want %d", len(got), len(input))
+		}
+		if want := []string{"same repository as pkg:github/spf13/cobra@1.8.0"}; !slices.Equal(got[0].Notes, want) {
+			t.Errorf("got[0].Notes = %v, want %v", got[0].Notes, want)
+		}
+		want := []string{
+			"same repository as pkg:golang/github.com/spf13/cobra@v1.8.0",
+			"same repository as pkg:golang/github.com/spf13/cobra/doc@v1.8.0",
+		}
+		if !slices.Equal(got[2].Notes, want) {
+			t.Errorf("got[2].Notes = %v, want %v", got[2].Notes, want)
+		}
+		// Go modules of the same repository are linked to the github purl, not to each other
+		if want := []string{"same repository as pkg:github/spf13/cobra@1.8.0"}; !slices.Equal(got[4].Notes, want) {
+			t.Errorf("got[4].Notes = %v, want %v", got[4].Notes, want)
+		}
+		for _, i := range []int{1, 3} {
+			if got[i].Notes != nil {
+				t.Errorf("got[%d].Notes = %v, want none", i, got[i].Notes)
+			}
+		}
+		if input[0].Notes != nil {
+			t.Error("CorrelateRepositories() modified its input")
+		}
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		t.Parallel()
+
+		got := attribution.CorrelateRepositories(input, attribution.CorrelateOptions{Merge: true}, nil)
+
+		if len(got) != len(input)-1 {
+			t.Fatalf("CorrelateRepositories() returned %d attributions, want %d", len(got), len(input)-1)
+		}
+		if want := []string{"also identified as pkg:github/spf13/cobra@1.8.0"}; !slices.Equal(got[0].Notes, want) {
+			t.Errorf("got[0].Notes = %v, want %v", got[0].Notes, want)
+		}
+		if want := []string{"go.json", "gh.json"}; !slices.Equal(got[0].Sources, want) {
+			t.Errorf("got[0].Sources = %v, want %v", got[0].Sources, want)
+		}
+		if got[2].Purl != "pkg:github/spf13/cobra@1.7.0" {
+			t.Errorf("got[2].Purl = %q, want the unmerged 1.7.0 package", got[2].Purl)
+		}
+	})
+}
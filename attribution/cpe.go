@@ -0,0 +1,63 @@
+package attribution
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// ErrInvalidCPE is returned by CPEToURL when the CPE is empty, malformed, or does not name a vendor and product.
+var ErrInvalidCPE = errors.New("invalid CPE")
+
+// CPEToURL constructs a URL for a package known only by its CPE identifier (CPE 2.3 formatted string, e.g.
+// "cpe:2.3:a:openssl:openssl:3.0.2:*:*:*:*:*:*:*", or CPE 2.2 URI, e.g. "cpe:/a:openssl:openssl:3.0.2"): a search of
+// the NVD CPE dictionary for the vendor and product, as a last resort for packages without a purl, such as OS packages.
+// Returns ErrInvalidCPE if the CPE is empty, malformed, or its vendor or product is unspecified.
+func CPEToURL(cpe string) (*string, error) {
+	part, vendor, product, ok := parseCPE(strings.TrimSpace(cpe))
+	if !ok || isUnspecifiedCPEValue(vendor) || isUnspecifiedCPEValue(product) {
+		return nil, ErrInvalidCPE
+	}
+
+	keyword := "cpe:2.3:" + part + ":" + vendor + ":" + product
+	return buildURL("https://nvd.nist.gov/products/cpe/search/results?namingFormat=2.3&keyword=%s",
+		url.QueryEscape(keyword)), nil
+}
+
+// parseCPE returns the part, vendor, and product of a CPE 2.3 formatted string or CPE 2.2 URI.
+// Returns ok as false if the CPE is malformed.
+func parseCPE(cpe string) (string, string, string, bool) {
+	const minComponents = 3 // part, vendor, and product
+
+	var components []string
+	if rest, found := strings.CutPrefix(cpe, "cpe:2.3:"); found {
+		components = splitCPE23(rest)
+	} else if rest, found = strings.CutPrefix(cpe, "cpe:/"); found {
+		components = strings.Split(rest, ":")
+	}
+	if len(components) < minComponents || components[0] == "" {
+		return "", "", "", false
+	}
+	return components[0], components[1], components[2], true
+}
+
+// splitCPE23 splits the components of a CPE 2.3 formatted string on the colons that are not escaped with a backslash.
+func splitCPE23(s string) []string {
+	var components []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ':':
+			components = append(components, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(components, s[start:])
+}
+
+// isUnspecifiedCPEValue reports whether a CPE component is empty, ANY ("*"), or NA ("-").
+func isUnspecifiedCPEValue(value string) bool {
+	return value == "" || value == "*" || value == "-"
+}
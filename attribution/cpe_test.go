@@ -0,0 +1,62 @@
+package attribution_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestCPEToURL tests the CPEToURL function.
+func TestCPEToURL(t *testing.T) {
+	t.Parallel()
+
+	const search = "https://nvd.nist.gov/products/cpe/search/results?namingFormat=2.3&keyword="
+
+	tests := []struct {
+		name    string
+		cpe     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "cpe 2.3",
+			cpe:  "cpe:2.3:a:openssl:openssl:3.0.2:*:*:*:*:*:*:*",
+			want: search + "cpe%3A2.3%3Aa%3Aopenssl%3Aopenssl",
+		},
+		{
+			name: "cpe 2.3 escaped colon",
+			cpe:  `cpe:2.3:a:acme:tool\:kit:1.0:*:*:*:*:*:*:*`,
+			want: search + "cpe%3A2.3%3Aa%3Aacme%3Atool%5C%3Akit",
+		},
+		{
+			name: "cpe 2.2",
+			cpe:  " cpe:/o:debian:debian_linux:12 ",
+			want: search + "cpe%3A2.3%3Ao%3Adebian%3Adebian_linux",
+		},
+		{name: "empty", cpe: "", wantErr: true},
+		{name: "not a cpe", cpe: "pkg:npm/lodash@4.17.21", wantErr: true},
+		{name: "too short", cpe: "cpe:2.3:a:openssl", wantErr: true},
+		{name: "any vendor", cpe: "cpe:2.3:a:*:openssl:3.0.2:*:*:*:*:*:*:*", wantErr: true},
+		{name: "na product", cpe: "cpe:/a:openssl:-", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := attribution.CPEToURL(tt.cpe)
+			if tt.wantErr {
+				if !errors.Is(err, attribution.ErrInvalidCPE) {
+					t.Errorf("CPEToURL(%q) error = %v, want ErrInvalidCPE", tt.cpe, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CPEToURL(%q) unexpected error: %v", tt.cpe, err)
+			}
+			if *got != tt.want {
+				t.Errorf("CPEToURL(%q) = %q, want %q", tt.cpe, *got, tt.want)
+			}
+		})
+	}
+}
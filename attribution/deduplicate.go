@@ -1,28 +1,99 @@
 package attribution
 
-import "log/slog"
+import (
+	"log/slog"
+	"slices"
+)
 
-// Deduplicate removes duplicate attributions based on Purl, falling back to Name.
-// The first occurrence of each unique attribution is kept.
+// Deduplicate removes duplicate attributions based on Purl, canonicalized per ecosystem so different spellings of the
+// same package match, falling back to the name qualified by its group (see DedupKey).
+// The first occurrence of each unique attribution is kept, with the Sources, URLs, AttributionTexts, and Notes of its
+// duplicates merged into it. Its Scope is widened if a duplicate is more required (see ExcludeScopes), so a component
+// required by one SBOM is not dropped because another lists it as optional.
 // The logger parameter is optional; pass nil to disable logging.
 func Deduplicate(attributions []Attribution, logger *slog.Logger) []Attribution {
-	seen := make(map[string]bool)
+	return DeduplicateAudited(attributions, nil, logger)
+}
+
+// DeduplicateAudited removes duplicate attributions like Deduplicate, recording every decision (the kept entry, the
+// dropped entries, and the fields in which they differ) in audit.
+// The audit parameter is optional; pass nil to disable auditing.
+// The logger parameter is optional; pass nil to disable logging.
+func DeduplicateAudited(attributions []Attribution, audit *DedupAudit, logger *slog.Logger) []Attribution {
+	return DeduplicateMode(attributions, DedupPerVersion, audit, logger)
+}
+
+// DeduplicateMode removes duplicate attributions like DeduplicateAudited, merging the attributions that share the key
+// of mode (see DedupMode.Key). With modes that merge several versions of a package, the kept attribution lists them in
+// Versions.
+// The audit parameter is optional; pass nil to disable auditing.
+// The logger parameter is optional; pass nil to disable logging.
+func DeduplicateMode(attributions []Attribution, mode DedupMode, audit *DedupAudit, logger *slog.Logger) []Attribution {
+	seen := make(map[string]int)
 	result := make([]Attribution, 0, len(attributions))
+	var decisions []DedupDecision
+	decisionIndex := make(map[string]int)
 
 	for _, a := range attributions {
-		// Use Purl as primary key, fall back to Name if Purl is empty
-		key := a.Purl
-		if key == "" {
-			key = a.Name
-		}
+		key := mode.Key(a)
 
-		if !seen[key] {
-			seen[key] = true
+		i, ok := seen[key]
+		if !ok {
+			seen[key] = len(result)
 			result = append(result, a)
-		} else if logger != nil {
+			continue
+		}
+
+		if logger != nil {
 			logger.Debug("skipping duplicate attribution", "key", key)
 		}
+		if audit != nil {
+			d, recorded := decisionIndex[key]
+			if !recorded {
+				d = len(decisions)
+				decisionIndex[key] = d
+				decisions = append(decisions, DedupDecision{Key: key, Kept: result[i]})
+			}
+			decisions[d].Dropped = append(decisions[d].Dropped, DroppedDuplicate{
+				Attribution:     a,
+				DifferingFields: differingFields(decisions[d].Kept, a),
+			})
+		}
+		result[i] = mergeDuplicate(result[i], a)
+		if mode.mergesVersions() {
+			result[i] = mergeVersions(result[i], a)
+		}
+	}
+
+	if audit != nil {
+		// Record decisions in the order of the kept attributions
+		slices.SortStableFunc(decisions, func(a, b DedupDecision) int { return seen[a.Key] - seen[b.Key] })
+		audit.Decisions = append(audit.Decisions, decisions...)
 	}
 
 	return result
 }
+
+// mergeDuplicate returns kept with the Sources, URLs, AttributionTexts, and Notes of a duplicate merged into it, and
+// its Scope widened if the duplicate is more required.
+func mergeDuplicate(kept, duplicate Attribution) Attribution {
+	kept.Sources = mergeUnique(kept.Sources, duplicate.Sources)
+	for _, u := range duplicate.URLs {
+		kept = kept.WithURL(u.Kind, u.URL)
+	}
+	kept.AttributionTexts = mergeUnique(kept.AttributionTexts, duplicate.AttributionTexts)
+	kept.Notes = mergeUnique(kept.Notes, duplicate.Notes)
+	kept.Scope = widestScope(kept.Scope, duplicate.Scope)
+	return kept
+}
+
+// mergeUnique returns a new slice with the values of b that are not already in a appended to a.
+func mergeUnique(a, b []string) []string {
+	merged := slices.Clone(a)
+	for _, value := range b {
+		if !slices.Contains(merged, value) {
+			merged = append(merged, value)
+		}
+	}
+	return merged
+}
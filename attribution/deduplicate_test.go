@@ -1,6 +1,7 @@
 package attribution_test
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/boringbin/sbomattr/attribution"
@@ -55,6 +56,20 @@ func TestDeduplicate(t *testing.T) {
 				{Name: "pkg2", Purl: ""},
 			},
 		},
+		{
+			name: "same name in different groups (no purl)",
+			input: []attribution.Attribution{
+				{Name: "core", Group: "@angular"},
+				{Name: "core", Group: "org.example"},
+				{Name: "core", Group: "@angular"},
+				{Name: "core"},
+			},
+			want: []attribution.Attribution{
+				{Name: "core", Group: "@angular"},
+				{Name: "core", Group: "org.example"},
+				{Name: "core"},
+			},
+		},
 		{
 			name: "mixed purl and name keys",
 			input: []attribution.Attribution{
@@ -122,7 +137,126 @@ func TestDeduplicate_NilLogger(t *testing.T) {
 	}
 }
 
+// TestDeduplicate_MergesSources tests that the sources of duplicates are merged into the kept attribution.
+func TestDeduplicate_MergesSources(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "pkg1", Purl: "pkg:npm/pkg1@1.0.0", Sources: []string{"a.json"}},
+		{Name: "pkg2", Purl: "pkg:npm/pkg2@1.0.0", Sources: []string{"a.json"}},
+		{Name: "pkg1", Purl: "pkg:npm/pkg1@1.0.0", Sources: []string{"b.json"}},
+		{Name: "pkg1", Purl: "pkg:npm/pkg1@1.0.0", Sources: []string{"a.json"}},
+	}
+
+	got := attribution.Deduplicate(input, nil)
+
+	if len(got) != 2 {
+		t.Fatalf("Deduplicate() length = %d, want 2", len(got))
+	}
+	if want := []string{"a.json", "b.json"}; !slices.Equal(got[0].Sources, want) {
+		t.Errorf("Deduplicate()[0].Sources = %v, want %v", got[0].Sources, want)
+	}
+	if want := []string{"a.json"}; !slices.Equal(got[1].Sources, want) {
+		t.Errorf("Deduplicate()[1].Sources = %v, want %v", got[1].Sources, want)
+	}
+
+	// The input must not be modified
+	if len(input[0].Sources) != 1 {
+		t.Errorf("Deduplicate() modified its input: %v", input[0].Sources)
+	}
+}
+
 // strPtr converts a string to a pointer to a string.
 func strPtr(s string) *string {
 	return &s
 }
+
+// TestDeduplicate_MergesNotes tests that the notes of duplicates are merged into the kept attribution.
+func TestDeduplicate_MergesNotes(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "pkg1", Purl: "pkg:npm/pkg1@1.0.0", Notes: []string{"CDXA claim: MIT"}},
+		{Name: "pkg1", Purl: "pkg:npm/pkg1@1.0.0", Notes: []string{"CDXA claim: MIT", "CDXA claim: reviewed license"}},
+	}
+
+	got := attribution.Deduplicate(input, nil)
+
+	if want := []string{"CDXA claim: MIT", "CDXA claim: reviewed license"}; !slices.Equal(got[0].Notes, want) {
+		t.Errorf("Deduplicate()[0].Notes = %v, want %v", got[0].Notes, want)
+	}
+}
+
+// TestDeduplicate_MergesAttributionTexts tests that the attribution texts of duplicates are merged without repeats.
+func TestDeduplicate_MergesAttributionTexts(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "pkg1", Purl: "pkg:npm/pkg1@1.0.0", AttributionTexts: []string{"Copyright Acme"}},
+		{Name: "pkg1", Purl: "pkg:npm/pkg1@1.0.0", AttributionTexts: []string{"Copyright Acme", "Portions by Example"}},
+	}
+
+	got := attribution.Deduplicate(input, nil)
+
+	if want := []string{"Copyright Acme", "Portions by Example"}; !slices.Equal(got[0].AttributionTexts, want) {
+		t.Errorf("Deduplicate()[0].AttributionTexts = %v, want %v", got[0].AttributionTexts, want)
+	}
+}
+
+// TestDeduplicate_MergesURLs tests that the URLs of duplicates are merged without repeats, keeping the primary URL.
+func TestDeduplicate_MergesURLs(t *testing.T) {
+	t.Parallel()
+
+	homepage := "https://lodash.com"
+	input := []attribution.Attribution{
+		{
+			Name: "lodash",
+			Purl: "pkg:npm/lodash@4.17.21",
+			URL:  &homepage,
+			URLs: []attribution.TypedURL{{Kind: attribution.URLKindHomepage, URL: homepage}},
+		},
+		{
+			Name: "lodash",
+			Purl: "pkg:npm/lodash@4.17.21",
+			URLs: []attribution.TypedURL{
+				{Kind: attribution.URLKindHomepage, URL: homepage},
+				{Kind: attribution.URLKindSource, URL: "https://github.com/lodash/lodash"},
+			},
+		},
+	}
+
+	got := attribution.Deduplicate(input, nil)
+
+	want := []attribution.TypedURL{
+		{Kind: attribution.URLKindHomepage, URL: homepage},
+		{Kind: attribution.URLKindSource, URL: "https://github.com/lodash/lodash"},
+	}
+	if !slices.Equal(got[0].URLs, want) {
+		t.Errorf("Deduplicate()[0].URLs = %v, want %v", got[0].URLs, want)
+	}
+	if got[0].URL == nil || *got[0].URL != homepage {
+		t.Errorf("Deduplicate()[0].URL = %v, want the homepage", got[0].URL)
+	}
+}
+
+// TestDeduplicate_CanonicalPurls tests that purls spelling the same package differently (see CanonicalPurl) are
+// deduplicated, keeping the first spelling.
+func TestDeduplicate_CanonicalPurls(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "Django", Purl: "pkg:pypi/Django@4.2.0", Sources: []string{"web.json"}},
+		{Name: "django", Purl: "pkg:pypi/django@4.2.0", Sources: []string{"api.json"}},
+		{Name: "cobra", Purl: "pkg:golang/github.com/spf13/cobra@1.8.0"},
+		{Name: "cobra", Purl: "pkg:golang/github.com/spf13/cobra@v1.8.0"},
+	}
+
+	got := attribution.Deduplicate(input, nil)
+
+	if len(got) != 2 {
+		t.Fatalf("Deduplicate() = %+v, want Django and cobra once", got)
+	}
+	if got[0].Purl != "pkg:pypi/Django@4.2.0" || !slices.Equal(got[0].Sources, []string{"web.json", "api.json"}) {
+		t.Errorf("Deduplicate()[0] = %+v, want the first spelling with both sources", got[0])
+	}
+}
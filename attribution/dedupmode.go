@@ -0,0 +1,78 @@
+package attribution
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+)
+
+// DedupMode selects which attributions deduplication merges into one (see DeduplicateMode).
+type DedupMode string
+
+const (
+	// DedupPerVersion merges the attributions of the same package version: the same canonical purl, or the same
+	// qualified name if there is no purl (see DedupKey). Different versions of a package stay separate. It is the
+	// default, and the zero value behaves like it.
+	DedupPerVersion DedupMode = "version"
+	// DedupPerPackage merges every version of the same package: the same canonical purl without its version, or the
+	// same qualified name if there is no purl.
+	DedupPerPackage DedupMode = "package"
+	// DedupPerName merges the attributions with the same qualified name, regardless of their version and ecosystem.
+	DedupPerName DedupMode = "name"
+)
+
+// DedupModes returns the supported deduplication modes.
+func DedupModes() []DedupMode {
+	return []DedupMode{DedupPerVersion, DedupPerPackage, DedupPerName}
+}
+
+// ParseDedupMode parses a deduplication mode name ("version", "package", or "name"), ignoring case. An empty name is
+// DedupPerVersion. Returns an error for unknown modes.
+func ParseDedupMode(s string) (DedupMode, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return DedupPerVersion, nil
+	}
+	for _, mode := range DedupModes() {
+		if strings.EqualFold(string(mode), s) {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("unknown dedup mode %q (valid modes: version, package, name)", s)
+}
+
+// Key returns the key identifying the attributions the mode merges with a.
+func (m DedupMode) Key(a Attribution) string {
+	switch m {
+	case DedupPerPackage:
+		if a.Purl == "" {
+			return a.QualifiedName()
+		}
+		parsed, err := packageurl.FromString(a.Purl)
+		if err != nil {
+			return a.Purl
+		}
+		canonical := canonicalPackageURL(parsed)
+		canonical.Version = ""
+		return canonical.ToString()
+	case DedupPerName:
+		return a.QualifiedName()
+	default:
+		return DedupKey(a)
+	}
+}
+
+// mergesVersions reports whether the mode merges different versions of a package.
+func (m DedupMode) mergesVersions() bool {
+	return m == DedupPerPackage || m == DedupPerName
+}
+
+// mergeVersions returns kept with the versions of a duplicate added to its Versions, if they differ.
+func mergeVersions(kept, duplicate Attribution) Attribution {
+	versions := mergeUnique(kept.VersionList(), duplicate.VersionList())
+	if len(versions) > 1 {
+		kept.Versions = versions
+	}
+	return kept
+}
@@ -0,0 +1,139 @@
+package attribution_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+func TestParseDedupMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    attribution.DedupMode
+		wantErr bool
+	}{
+		{input: "", want: attribution.DedupPerVersion},
+		{input: "version", want: attribution.DedupPerVersion},
+		{input: " Package ", want: attribution.DedupPerPackage},
+		{input: "NAME", want: attribution.DedupPerName},
+		{input: "purl", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := attribution.ParseDedupMode(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDedupMode(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDedupMode(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDedupMode_Key(t *testing.T) {
+	t.Parallel()
+
+	lodash := attribution.Attribution{Name: "lodash", Version: "4.17.21", Purl: "pkg:npm/Lodash@4.17.21"}
+	tests := []struct {
+		name string
+		mode attribution.DedupMode
+		a    attribution.Attribution
+		want string
+	}{
+		{name: "version", mode: attribution.DedupPerVersion, a: lodash, want: "pkg:npm/lodash@4.17.21"},
+		{name: "zero value", mode: "", a: lodash, want: "pkg:npm/lodash@4.17.21"},
+		{name: "package", mode: attribution.DedupPerPackage, a: lodash, want: "pkg:npm/lodash"},
+		{
+			name: "package without purl",
+			mode: attribution.DedupPerPackage,
+			a:    attribution.Attribution{Name: "core", Group: "@angular", Version: "17.0.0"},
+			want: "@angular/core",
+		},
+		{name: "name", mode: attribution.DedupPerName, a: lodash, want: "lodash"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.mode.Key(tt.a); got != tt.want {
+				t.Errorf("Key() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeduplicateMode(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "lodash", Version: "4.17.20", Purl: "pkg:npm/lodash@4.17.20", Sources: []string{"a.json"}},
+		{Name: "lodash", Version: "4.17.21", Purl: "pkg:npm/lodash@4.17.21", Sources: []string{"b.json"}},
+		{Name: "lodash", Version: "4.17.21", Purl: "pkg:npm/lodash@4.17.21", Sources: []string{"c.json"}},
+		{Name: "lodash", Version: "1.0.0", Purl: "pkg:pypi/lodash@1.0.0", Sources: []string{"d.json"}},
+	}
+
+	tests := []struct {
+		mode         attribution.DedupMode
+		wantVersions [][]string
+	}{
+		{
+			mode:         attribution.DedupPerVersion,
+			wantVersions: [][]string{{"4.17.20"}, {"4.17.21"}, {"1.0.0"}},
+		},
+		{
+			mode:         attribution.DedupPerPackage,
+			wantVersions: [][]string{{"4.17.20", "4.17.21"}, {"1.0.0"}},
+		},
+		{
+			mode:         attribution.DedupPerName,
+			wantVersions: [][]string{{"4.17.20", "4.17.21", "1.0.0"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			t.Parallel()
+
+			audit := &attribution.DedupAudit{}
+			got := attribution.DeduplicateMode(input, tt.mode, audit, nil)
+			if len(got) != len(tt.wantVersions) {
+				t.Fatalf("DeduplicateMode() = %v, want %d attributions", got, len(tt.wantVersions))
+			}
+			for i, want := range tt.wantVersions {
+				if !slices.Equal(got[i].VersionList(), want) {
+					t.Errorf("DeduplicateMode()[%d] versions = %v, want %v", i, got[i].VersionList(), want)
+				}
+				if got[i].Version != want[0] {
+					t.Errorf("DeduplicateMode()[%d] version = %q, want the first version %q", i, got[i].Version,
+						want[0])
+				}
+			}
+			if len(audit.Decisions) == 0 {
+				t.Error("DeduplicateMode() recorded no decisions, want the merged duplicates")
+			}
+		})
+	}
+}
+
+func TestAttribution_VersionList(t *testing.T) {
+	t.Parallel()
+
+	if got := (attribution.Attribution{}).VersionList(); got != nil {
+		t.Errorf("VersionList() without a version = %v, want nil", got)
+	}
+	if got := (attribution.Attribution{Version: "1.0.0"}).VersionList(); !slices.Equal(got, []string{"1.0.0"}) {
+		t.Errorf("VersionList() = %v, want [1.0.0]", got)
+	}
+	merged := attribution.Attribution{Version: "1.0.0", Versions: []string{"1.0.0", "2.0.0"}}
+	if got := merged.VersionList(); !slices.Equal(got, merged.Versions) {
+		t.Errorf("VersionList() = %v, want %v", got, merged.Versions)
+	}
+}
@@ -0,0 +1,86 @@
+package attribution
+
+// Diff is the difference between two sets of attributions, e.g. the notices of two releases, as returned by Compare.
+type Diff struct {
+	// Added lists the packages only found in the new set, in its order.
+	Added []Attribution `json:"added"`
+	// Removed lists the packages only found in the old set, in its order.
+	Removed []Attribution `json:"removed"`
+	// Changed lists the packages found in both sets with different values, in the order of the new set.
+	Changed []Change `json:"changed"`
+}
+
+// IsEmpty reports whether the sets of attributions are identical.
+func (d Diff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Change is a package found in both sets of attributions compared by Compare, with different values.
+type Change struct {
+	// Old is the attribution of the package in the old set.
+	Old Attribution `json:"old"`
+	// New is the attribution of the package in the new set.
+	New Attribution `json:"new"`
+	// Fields lists the fields whose values differ (named as in JSON output, e.g. "version" or "license").
+	Fields []string `json:"fields"`
+}
+
+// Compare returns the packages added, removed, and changed between two sets of attributions. Attributions are the
+// same package if they share their DedupKey; the remaining ones are matched by package regardless of version (see
+// DedupPerPackage), so upgrades show up as version changes rather than as an added and a removed package. Only the
+// fields describing the package are compared, not bookkeeping such as Sources or Provenance.
+func Compare(before, after []Attribution) Diff {
+	used := make([]bool, len(before))
+	pairs := make([]int, len(after))
+
+	byVersion := make(map[string][]int)
+	for i, a := range before {
+		byVersion[DedupKey(a)] = append(byVersion[DedupKey(a)], i)
+	}
+	for j, a := range after {
+		pairs[j] = takeUnused(byVersion, DedupKey(a), used)
+	}
+
+	byPackage := make(map[string][]int)
+	for i, a := range before {
+		if !used[i] {
+			key := DedupPerPackage.Key(a)
+			byPackage[key] = append(byPackage[key], i)
+		}
+	}
+	for j, a := range after {
+		if pairs[j] < 0 {
+			pairs[j] = takeUnused(byPackage, DedupPerPackage.Key(a), used)
+		}
+	}
+
+	diff := Diff{Added: []Attribution{}, Removed: []Attribution{}, Changed: []Change{}}
+	for j, a := range after {
+		i := pairs[j]
+		if i < 0 {
+			diff.Added = append(diff.Added, a)
+			continue
+		}
+		if fields := differingFields(before[i], a); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, Change{Old: before[i], New: a, Fields: fields})
+		}
+	}
+	for i, a := range before {
+		if !used[i] {
+			diff.Removed = append(diff.Removed, a)
+		}
+	}
+	return diff
+}
+
+// takeUnused returns the first index listed under key in index that is not used yet, marking it used, or -1 if there
+// is none.
+func takeUnused(index map[string][]int, key string, used []bool) int {
+	for _, i := range index[key] {
+		if !used[i] {
+			used[i] = true
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,67 @@
+package attribution_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestCompare tests that packages are reported as added, removed, or changed, with upgrades matched by package.
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	before := []attribution.Attribution{
+		{Name: "lodash", Version: "4.17.20", Purl: "pkg:npm/lodash@4.17.20", License: strPtr("MIT")},
+		{Name: "express", Version: "4.18.2", Purl: "pkg:npm/express@4.18.2", License: strPtr("MIT"),
+			Sources: []string{"old.json"}},
+		{Name: "left-pad", Version: "1.3.0", License: strPtr("WTFPL")},
+		{Name: "react", Version: "18.2.0", Purl: "pkg:npm/react@18.2.0", License: strPtr("MIT")},
+	}
+	after := []attribution.Attribution{
+		{Name: "express", Version: "4.18.2", Purl: "pkg:npm/express@4.18.2", License: strPtr("Apache-2.0"),
+			Sources: []string{"new.json"}},
+		{Name: "lodash", Version: "4.17.21", Purl: "pkg:npm/lodash@4.17.21", License: strPtr("MIT")},
+		{Name: "react", Version: "18.2.0", Purl: "pkg:npm/react@18.2.0", License: strPtr("MIT"),
+			Sources: []string{"new.json"}},
+		{Name: "zod", Version: "3.22.0", Purl: "pkg:npm/zod@3.22.0", License: strPtr("MIT")},
+	}
+
+	diff := attribution.Compare(before, after)
+
+	if len(diff.Added) != 1 || diff.Added[0].Name != "zod" {
+		t.Errorf("Added = %+v, want zod", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].Name != "left-pad" {
+		t.Errorf("Removed = %+v, want left-pad", diff.Removed)
+	}
+	if len(diff.Changed) != 2 {
+		t.Fatalf("Changed = %+v, want express and lodash", diff.Changed)
+	}
+	if express := diff.Changed[0]; express.New.Name != "express" || !slices.Equal(express.Fields, []string{"license"}) {
+		t.Errorf("Changed[0] = %+v, want the license of express", express)
+	}
+	if lodash := diff.Changed[1]; lodash.Old.Version != "4.17.20" || lodash.New.Version != "4.17.21" ||
+		!slices.Equal(lodash.Fields, []string{"version"}) {
+		t.Errorf("Changed[1] = %+v, want lodash upgraded from 4.17.20 to 4.17.21", lodash)
+	}
+	if diff.IsEmpty() {
+		t.Error("IsEmpty() = true, want false")
+	}
+}
+
+// TestCompare_Identical tests that identical sets have an empty diff with non-nil lists, so it is written as [] in
+// JSON.
+func TestCompare_Identical(t *testing.T) {
+	t.Parallel()
+
+	attrs := []attribution.Attribution{
+		{Name: "lodash", Version: "4.17.20", Purl: "pkg:npm/lodash@4.17.20"},
+		{Name: "lodash", Version: "4.17.21", Purl: "pkg:npm/lodash@4.17.21"},
+	}
+
+	diff := attribution.Compare(attrs, []attribution.Attribution{attrs[1], attrs[0]})
+	if !diff.IsEmpty() || diff.Added == nil || diff.Removed == nil || diff.Changed == nil {
+		t.Errorf("Compare() = %#v, want empty, non-nil lists", diff)
+	}
+}
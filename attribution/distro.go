@@ -0,0 +1,71 @@
+package attribution
+
+import (
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+)
+
+// distroRelease returns the release named by a purl distro qualifier, lowercased and without the distribution
+// prefix: "ubuntu-22.04" becomes "22.04", "debian-bookworm" becomes "bookworm", and "jammy" stays "jammy".
+func distroRelease(distro string) string {
+	distro = strings.ToLower(strings.TrimSpace(distro))
+	if _, release, found := strings.Cut(distro, "-"); found {
+		return release
+	}
+	return distro
+}
+
+// debianSuite returns the Debian or Ubuntu suite (codename) named by a purl distro qualifier, e.g. "bookworm" for
+// "debian-12" or "jammy" for "ubuntu-22.04". Point releases ("debian-12.5") map to their major release. Returns an
+// empty string if the release is unknown.
+func debianSuite(distro string) string {
+	release := distroRelease(distro)
+	if release == "" {
+		return ""
+	}
+	if strings.Trim(release, "abcdefghijklmnopqrstuvwxyz") == "" {
+		return release
+	}
+
+	codenames := debianCodenames()
+	for {
+		if codename, ok := codenames[release]; ok {
+			return codename
+		}
+		dot := strings.LastIndex(release, ".")
+		if dot < 0 {
+			return ""
+		}
+		release = release[:dot]
+	}
+}
+
+// debianCodenames maps Debian and Ubuntu release numbers to their codenames.
+func debianCodenames() map[string]string {
+	return map[string]string{
+		"10":    "buster",
+		"11":    "bullseye",
+		"12":    "bookworm",
+		"13":    "trixie",
+		"16.04": "xenial",
+		"18.04": "bionic",
+		"20.04": "focal",
+		"22.04": "jammy",
+		"23.04": "lunar",
+		"23.10": "mantic",
+		"24.04": "noble",
+		"24.10": "oracular",
+		"25.04": "plucky",
+	}
+}
+
+// rpmDistro returns the distribution of an rpm purl: its namespace (e.g. "fedora" or "opensuse"), or else the
+// distribution named by its distro qualifier (e.g. "fedora" for "fedora-40"), lowercased.
+func rpmDistro(purl packageurl.PackageURL) string {
+	if purl.Namespace != "" {
+		return strings.ToLower(purl.Namespace)
+	}
+	distro, _, _ := strings.Cut(strings.ToLower(purl.Qualifiers.Map()["distro"]), "-")
+	return distro
+}
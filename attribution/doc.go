@@ -1,2 +1,6 @@
 // Package attribution provides types and functions for working with attribution information.
+//
+// The functions operating on attribution slices return copies and can be chained to build custom reports:
+// Deduplicate and DeduplicateBy merge duplicates, Filter, ExcludeScopes, and ExcludeFirstParty drop attributions,
+// Sort and SortBy order them, and GroupBy and GroupBySource split them into groups.
 package attribution
@@ -0,0 +1,44 @@
+package attribution
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+)
+
+// EcosystemUnknown is the ecosystem of attributions whose purl cannot be parsed.
+const EcosystemUnknown = "unknown"
+
+// Ecosystem returns the ecosystem of an attribution: the type of its purl (npm, pypi, golang, ...), lowercased.
+// Returns an empty string if the attribution has no purl, and EcosystemUnknown if its purl cannot be parsed.
+func Ecosystem(a Attribution) string {
+	if a.Purl == "" {
+		return ""
+	}
+	purl, err := packageurl.FromString(a.Purl)
+	if err != nil {
+		return EcosystemUnknown
+	}
+	return strings.ToLower(purl.Type)
+}
+
+// GroupByEcosystem groups attributions by their ecosystem (see Ecosystem), sorted by ecosystem, e.g. to write a
+// notice per package manager. Attributions without a purl are grouped under an empty ecosystem, sorted first.
+func GroupByEcosystem(attributions []Attribution) []Group[string] {
+	groups := GroupBy(attributions, Ecosystem)
+	slices.SortFunc(groups, func(a, b Group[string]) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return groups
+}
+
+// CountByEcosystem returns the number of attributions per ecosystem (see Ecosystem). Attributions without a purl are
+// counted under an empty ecosystem.
+func CountByEcosystem(attributions []Attribution) map[string]int {
+	counts := make(map[string]int)
+	for _, a := range attributions {
+		counts[Ecosystem(a)]++
+	}
+	return counts
+}
@@ -0,0 +1,87 @@
+package attribution_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestEcosystem tests that the ecosystem is the lowercased purl type, empty without a purl, and unknown for unparsable
+// purls.
+func TestEcosystem(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		purl string
+		want string
+	}{
+		{purl: "pkg:npm/lodash@4.17.21", want: "npm"},
+		{purl: "pkg:PyPI/requests@2.31.0", want: "pypi"},
+		{purl: "pkg:golang/github.com/acme/tool@v1.0.0", want: "golang"},
+		{purl: "", want: ""},
+		{purl: "not a purl", want: attribution.EcosystemUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.purl, func(t *testing.T) {
+			t.Parallel()
+
+			if got := attribution.Ecosystem(attribution.Attribution{Purl: tt.purl}); got != tt.want {
+				t.Errorf("Ecosystem() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGroupByEcosystem tests that attributions are grouped by ecosystem, sorted by ecosystem, with attributions
+// without a purl first.
+func TestGroupByEcosystem(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "requests", Purl: "pkg:pypi/requests@2.31.0"},
+		{Name: "lodash", Purl: "pkg:npm/lodash@4.17.21"},
+		{Name: "internal"},
+		{Name: "react", Purl: "pkg:npm/react@18.2.0"},
+	}
+
+	groups := attribution.GroupByEcosystem(input)
+
+	want := []struct {
+		key   string
+		names []string
+	}{
+		{key: "", names: []string{"internal"}},
+		{key: "npm", names: []string{"lodash", "react"}},
+		{key: "pypi", names: []string{"requests"}},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("GroupByEcosystem() = %+v, want %d groups", groups, len(want))
+	}
+	for i, w := range want {
+		var names []string
+		for _, a := range groups[i].Attributions {
+			names = append(names, a.Name)
+		}
+		if groups[i].Key != w.key || !slices.Equal(names, w.names) {
+			t.Errorf("GroupByEcosystem()[%d] = %q %v, want %q %v", i, groups[i].Key, names, w.key, w.names)
+		}
+	}
+}
+
+// TestCountByEcosystem tests counting attributions per ecosystem.
+func TestCountByEcosystem(t *testing.T) {
+	t.Parallel()
+
+	counts := attribution.CountByEcosystem([]attribution.Attribution{
+		{Purl: "pkg:npm/lodash@4.17.21"},
+		{Purl: "pkg:npm/react@18.2.0"},
+		{Purl: "pkg:cargo/serde@1.0.0"},
+		{Name: "internal"},
+	})
+
+	if len(counts) != 3 || counts["npm"] != 2 || counts["cargo"] != 1 || counts[""] != 1 {
+		t.Errorf("CountByEcosystem() = %v, want npm: 2, cargo: 1, and one without a purl", counts)
+	}
+}
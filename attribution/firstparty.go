@@ -0,0 +1,106 @@
+package attribution
+
+import (
+	"log/slog"
+	"path"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+)
+
+// FirstPartyRules identify a company's own packages, which do not belong in third-party notices.
+type FirstPartyRules struct {
+	// Namespaces are glob patterns (see path.Match) matched case-insensitively against the purl namespace and the
+	// group of a package, e.g. "com.acme*" for Maven, "@acme" for npm, or "github.com/acme" for Go.
+	Namespaces []string
+	// Suppliers are organization names matched case-insensitively against the supplier of a package.
+	Suppliers []string
+	// Names are glob patterns (see path.Match) matched case-insensitively against the name of a package, alone and
+	// qualified by its group, e.g. "acme-*" for internal modules without a namespace.
+	Names []string
+	// Purls are prefixes matched case-insensitively against the purl of a package, e.g. "pkg:golang/github.com/acme/"
+	// for every Go module of an organization, however deeply nested.
+	Purls []string
+}
+
+// IsZero reports whether the rules match nothing.
+func (r FirstPartyRules) IsZero() bool {
+	return len(r.Namespaces) == 0 && len(r.Suppliers) == 0 && len(r.Names) == 0 && len(r.Purls) == 0
+}
+
+// Matches reports whether the attribution is a first-party package according to the rules.
+// Invalid glob patterns never match.
+func (r FirstPartyRules) Matches(a Attribution) bool {
+	var namespaces []string
+	if a.Group != "" {
+		namespaces = append(namespaces, a.Group)
+	}
+	if purl, err := packageurl.FromString(a.Purl); err == nil && purl.Namespace != "" {
+		namespaces = append(namespaces, purl.Namespace)
+	}
+	if matchesAny(r.Namespaces, namespaces) || matchesAny(r.Names, []string{a.Name, a.QualifiedName()}) {
+		return true
+	}
+
+	if a.Purl != "" {
+		for _, prefix := range r.Purls {
+			prefix = strings.ToLower(strings.TrimSpace(prefix))
+			if prefix != "" && strings.HasPrefix(strings.ToLower(a.Purl), prefix) {
+				return true
+			}
+		}
+	}
+
+	if a.Supplier != "" {
+		for _, supplier := range r.Suppliers {
+			if strings.EqualFold(strings.TrimSpace(supplier), a.Supplier) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchesAny reports whether any of the glob patterns matches any of the non-empty values, ignoring case.
+func matchesAny(patterns, values []string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		for _, value := range values {
+			if value == "" {
+				continue
+			}
+			if matched, err := path.Match(pattern, strings.ToLower(value)); err == nil && matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FlagFirstParty returns a copy of attributions with FirstParty set on the packages matching the rules.
+func FlagFirstParty(attributions []Attribution, rules FirstPartyRules) []Attribution {
+	result := make([]Attribution, 0, len(attributions))
+	for _, a := range attributions {
+		if rules.Matches(a) {
+			a.FirstParty = true
+		}
+		result = append(result, a)
+	}
+	return result
+}
+
+// ExcludeFirstParty returns a copy of attributions without the packages matching the rules, since attributing
+// yourself is noise. The names of the excluded packages are logged at debug level.
+// The logger parameter is optional; pass nil to disable logging.
+func ExcludeFirstParty(attributions []Attribution, rules FirstPartyRules, logger *slog.Logger) []Attribution {
+	return Filter(attributions, func(a Attribution) bool {
+		if rules.Matches(a) {
+			if logger != nil {
+				logger.Debug("excluding first-party package", "name", a.QualifiedName(), "purl", a.Purl)
+			}
+			return false
+		}
+		return true
+	})
+}
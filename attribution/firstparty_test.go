@@ -0,0 +1,90 @@
+package attribution_test
+
+import (
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestFirstPartyRules_Matches tests matching by purl namespace, group, supplier, name, and purl prefix.
+func TestFirstPartyRules_Matches(t *testing.T) {
+	t.Parallel()
+
+	rules := attribution.FirstPartyRules{
+		Namespaces: []string{"com.acme*", " @ACME ", "github.com/acme", "[invalid"},
+		Suppliers:  []string{"Acme Corp"},
+		Names:      []string{"acme-*", "internal/*"},
+		Purls:      []string{" PKG:golang/github.com/acme-labs/ ", ""},
+	}
+
+	tests := []struct {
+		name string
+		a    attribution.Attribution
+		want bool
+	}{
+		{
+			name: "maven namespace",
+			a:    attribution.Attribution{Purl: "pkg:maven/com.acme.billing/core@1.0.0"},
+			want: true,
+		},
+		{name: "npm scope", a: attribution.Attribution{Purl: "pkg:npm/%40acme/ui@2.0.0"}, want: true},
+		{name: "go module", a: attribution.Attribution{Purl: "pkg:golang/github.com/acme/tool@v1.0.0"}, want: true},
+		{name: "nested go module", a: attribution.Attribution{Purl: "pkg:golang/github.com/acme/tool/v2@v2.0.0"}},
+		{name: "group", a: attribution.Attribution{Name: "ui", Group: "@acme"}, want: true},
+		{name: "supplier", a: attribution.Attribution{Name: "ui", Supplier: "ACME CORP"}, want: true},
+		{name: "name", a: attribution.Attribution{Name: "Acme-Billing", Version: "1.0.0"}, want: true},
+		{name: "qualified name", a: attribution.Attribution{Name: "billing", Group: "internal"}, want: true},
+		{
+			name: "purl prefix",
+			a:    attribution.Attribution{Purl: "pkg:golang/github.com/acme-labs/tool/v2@v2.0.0"},
+			want: true,
+		},
+		{name: "third party", a: attribution.Attribution{Purl: "pkg:npm/lodash@4.17.21", Supplier: "OpenJS"}},
+		{name: "lookalike namespace", a: attribution.Attribution{Purl: "pkg:maven/org.acme/core@1.0.0"}},
+		{name: "no identifiers", a: attribution.Attribution{Name: "acme"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := rules.Matches(tt.a); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestExcludeFirstParty tests that first-party packages are removed without modifying the input.
+func TestExcludeFirstParty(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{{Name: "ui", Supplier: "Acme"}, {Name: "lodash"}}
+	rules := attribution.FirstPartyRules{Suppliers: []string{"acme"}}
+
+	got := attribution.ExcludeFirstParty(input, rules, nil)
+
+	if len(got) != 1 || got[0].Name != "lodash" {
+		t.Errorf("ExcludeFirstParty() = %v, want only lodash", got)
+	}
+	if len(input) != 2 {
+		t.Errorf("ExcludeFirstParty() modified its input: %v", input)
+	}
+}
+
+// TestFlagFirstParty tests that first-party packages are flagged without modifying the input.
+func TestFlagFirstParty(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{{Name: "ui", Supplier: "Acme"}, {Name: "lodash"}}
+	rules := attribution.FirstPartyRules{Suppliers: []string{"acme"}}
+
+	got := attribution.FlagFirstParty(input, rules)
+
+	if !got[0].FirstParty || got[1].FirstParty {
+		t.Errorf("FlagFirstParty() = %v, want only ui flagged", got)
+	}
+	if input[0].FirstParty {
+		t.Error("FlagFirstParty() modified its input")
+	}
+}
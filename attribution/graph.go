@@ -0,0 +1,41 @@
+package attribution
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Edge is a dependency of one attribution on another, declared by an SBOM (SPDX DEPENDS_ON relationships, CycloneDX
+// dependencies). Both ends are identified by their DedupKey.
+type Edge struct {
+	// From is the key of the dependent attribution.
+	From string `json:"from"`
+	// To is the key of the attribution it depends on.
+	To string `json:"to"`
+}
+
+// Graph is a dependency graph between attributions, so visualization tools can render dependency trees annotated
+// with licenses.
+type Graph struct {
+	// Edges are the dependencies between attributions.
+	Edges []Edge `json:"edges"`
+}
+
+// Restrict returns a copy of the graph with only the edges between the given attributions, e.g. after duplicates or
+// first-party packages were removed. Duplicate edges and self-dependencies are dropped, and the edges are sorted by
+// From, then To, so the output does not depend on SBOM file order.
+func (g Graph) Restrict(attributions []Attribution) Graph {
+	keys := make(map[string]bool, len(attributions))
+	for _, a := range attributions {
+		keys[DedupKey(a)] = true
+	}
+
+	edges := make([]Edge, 0, len(g.Edges))
+	for _, e := range g.Edges {
+		if e.From != e.To && keys[e.From] && keys[e.To] {
+			edges = append(edges, e)
+		}
+	}
+	slices.SortFunc(edges, func(a, b Edge) int { return cmp.Or(cmp.Compare(a.From, b.From), cmp.Compare(a.To, b.To)) })
+	return Graph{Edges: slices.Compact(edges)}
+}
@@ -0,0 +1,42 @@
+package attribution_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestGraph_Restrict tests the Restrict method of Graph.
+func TestGraph_Restrict(t *testing.T) {
+	t.Parallel()
+
+	graph := attribution.Graph{Edges: []attribution.Edge{
+		{From: "pkg:npm/app@1.0.0", To: "pkg:npm/react@18.2.0"},
+		{From: "pkg:npm/react@18.2.0", To: "pkg:npm/loose-envify@1.4.0"},
+		{From: "pkg:npm/app@1.0.0", To: "pkg:npm/lodash@4.17.21"},
+		{From: "pkg:npm/app@1.0.0", To: "pkg:npm/react@18.2.0"},
+		{From: "pkg:npm/lodash@4.17.21", To: "pkg:npm/lodash@4.17.21"},
+		{From: "pkg:npm/app@1.0.0", To: "manual"},
+	}}
+	attributions := []attribution.Attribution{
+		{Name: "app", Purl: "pkg:npm/app@1.0.0"},
+		{Name: "react", Purl: "pkg:npm/react@18.2.0"},
+		{Name: "lodash", Purl: "pkg:npm/lodash@4.17.21"},
+		{Name: "manual"},
+	}
+
+	got := graph.Restrict(attributions)
+
+	want := []attribution.Edge{
+		{From: "pkg:npm/app@1.0.0", To: "manual"},
+		{From: "pkg:npm/app@1.0.0", To: "pkg:npm/lodash@4.17.21"},
+		{From: "pkg:npm/app@1.0.0", To: "pkg:npm/react@18.2.0"},
+	}
+	if !slices.Equal(got.Edges, want) {
+		t.Errorf("Restrict() = %v, want %v", got.Edges, want)
+	}
+	if len(graph.Edges) != 6 {
+		t.Errorf("Restrict() modified the graph: %v", graph.Edges)
+	}
+}
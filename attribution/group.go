@@ -0,0 +1,42 @@
+package attribution
+
+import (
+	"slices"
+)
+
+// SourceGroup is the attributions found in one SBOM file.
+type SourceGroup struct {
+	// Source is the SBOM file, or an empty string for attributions without sources.
+	Source string `json:"source"`
+	// Attributions are the attributions found in the source, in input order.
+	Attributions []Attribution `json:"attributions"`
+}
+
+// GroupBySource groups attributions by the SBOM file they were found in, sorted by source.
+// An attribution found in several files appears in each of their groups. Attributions without sources are grouped
+// under an empty source, sorted first.
+func GroupBySource(attributions []Attribution) []SourceGroup {
+	groups := make(map[string][]Attribution)
+
+	for _, a := range attributions {
+		if len(a.Sources) == 0 {
+			groups[""] = append(groups[""], a)
+			continue
+		}
+		for _, source := range a.Sources {
+			groups[source] = append(groups[source], a)
+		}
+	}
+
+	sources := make([]string, 0, len(groups))
+	for source := range groups {
+		sources = append(sources, source)
+	}
+	slices.Sort(sources)
+
+	result := make([]SourceGroup, 0, len(sources))
+	for _, source := range sources {
+		result = append(result, SourceGroup{Source: source, Attributions: groups[source]})
+	}
+	return result
+}
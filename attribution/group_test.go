@@ -0,0 +1,51 @@
+package attribution_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestGroupBySource tests the GroupBySource function.
+func TestGroupBySource(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "react", Sources: []string{"web.json"}},
+		{Name: "lodash", Sources: []string{"web.json", "api.json"}},
+		{Name: "express", Sources: []string{"api.json"}},
+		{Name: "manual"},
+	}
+
+	got := attribution.GroupBySource(input)
+
+	want := []struct {
+		source string
+		names  []string
+	}{
+		{source: "", names: []string{"manual"}},
+		{source: "api.json", names: []string{"lodash", "express"}},
+		{source: "web.json", names: []string{"react", "lodash"}},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("GroupBySource() returned %d groups, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].Source != w.source {
+			t.Errorf("GroupBySource()[%d].Source = %q, want %q", i, got[i].Source, w.source)
+		}
+		var names []string
+		for _, a := range got[i].Attributions {
+			names = append(names, a.Name)
+		}
+		if !slices.Equal(names, w.names) {
+			t.Errorf("GroupBySource()[%d] names = %v, want %v", i, names, w.names)
+		}
+	}
+
+	if got = attribution.GroupBySource(nil); len(got) != 0 {
+		t.Errorf("GroupBySource(nil) = %v, want empty", got)
+	}
+}
@@ -0,0 +1,14 @@
+package attribution
+
+import "strings"
+
+// NormalizeHashAlgorithm returns the canonical name of a checksum algorithm, used as the key of Attribution.Hashes.
+// SPDX and CycloneDX spell algorithms differently (e.g. "SHA256" and "SHA-256"); both normalize to the upper-case
+// SPDX spelling without a hyphen after "SHA", e.g. "SHA256", "SHA3-256", or "BLAKE2B-256".
+func NormalizeHashAlgorithm(algorithm string) string {
+	name := strings.ToUpper(strings.TrimSpace(algorithm))
+	if rest, ok := strings.CutPrefix(name, "SHA-"); ok {
+		return "SHA" + rest
+	}
+	return name
+}
@@ -0,0 +1,34 @@
+package attribution_test
+
+import (
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestNormalizeHashAlgorithm tests that SPDX and CycloneDX algorithm names normalize to the same key.
+func TestNormalizeHashAlgorithm(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "SHA256", want: "SHA256"},
+		{input: "SHA-256", want: "SHA256"},
+		{input: "sha-1", want: "SHA1"},
+		{input: "SHA3-512", want: "SHA3-512"},
+		{input: "BLAKE2b-256", want: "BLAKE2B-256"},
+		{input: " MD5 ", want: "MD5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			if got := attribution.NormalizeHashAlgorithm(tt.input); got != tt.want {
+				t.Errorf("NormalizeHashAlgorithm(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package attribution
+
+import (
+	_ "embed"
+	"log/slog"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+)
+
+// knownLicenses is a small curated dataset of well-known packages and their licenses, one tab-separated
+// "<purl type>\t<namespace/name>\t<license>" entry per line.
+//
+//go:embed data/known-licenses.tsv
+var knownLicenses string
+
+// KnownLicense returns the license of a well-known package identified by its purl, from a small curated dataset
+// shipped with the tool. Returns ok as false if the purl is invalid or the package is not in the dataset.
+//
+// This is a heuristic: it ignores the version, so it cannot detect packages that changed license over time.
+func KnownLicense(purlString string) (string, bool) {
+	purl, err := packageurl.FromString(purlString)
+	if err != nil {
+		return "", false
+	}
+
+	name := purl.Name
+	if purl.Namespace != "" {
+		name = purl.Namespace + "/" + purl.Name
+	}
+
+	for line := range strings.Lines(knownLicenses) {
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		const knownLicenseFields = 3
+		if len(fields) != knownLicenseFields {
+			continue
+		}
+		if fields[0] == purl.Type && strings.EqualFold(fields[1], name) {
+			return fields[2], true
+		}
+	}
+
+	return "", false
+}
+
+// FillKnownLicenses returns a copy of attributions where missing licenses (nil, empty, or NOASSERTION) are filled
+// from the curated dataset used by KnownLicense. It is meant as an opt-in last resort for completely unlicensed
+// entries; licenses explicitly declared as NONE are left untouched.
+// The logger parameter is optional; pass nil to disable logging.
+func FillKnownLicenses(attributions []Attribution, logger *slog.Logger) []Attribution {
+	result := make([]Attribution, 0, len(attributions))
+
+	for _, a := range attributions {
+		if a.Purl != "" && a.LicenseStatus() == LicenseStatusUnknown {
+			if license, ok := KnownLicense(a.Purl); ok {
+				if logger != nil {
+					logger.Debug("filled license from known packages", "purl", a.Purl, "license", license)
+				}
+				a.License = &license
+				a = a.WithProvenance(FieldLicense, ProvenanceHeuristic)
+			}
+		}
+		result = append(result, a)
+	}
+
+	return result
+}
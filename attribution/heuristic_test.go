@@ -0,0 +1,78 @@
+package attribution_test
+
+import (
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestKnownLicense tests the KnownLicense function.
+func TestKnownLicense(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		purl   string
+		want   string
+		wantOK bool
+	}{
+		{name: "npm", purl: "pkg:npm/react@18.2.0", want: "MIT", wantOK: true},
+		{name: "npm scoped", purl: "pkg:npm/%40babel/core@7.22.5", want: "MIT", wantOK: true},
+		{name: "golang", purl: "pkg:golang/github.com/stretchr/testify@v1.8.4", want: "MIT", wantOK: true},
+		{name: "cargo dual", purl: "pkg:cargo/serde@1.0.0", want: "MIT OR Apache-2.0", wantOK: true},
+		{name: "case-insensitive name", purl: "pkg:pypi/Django@4.2.0", want: "BSD-3-Clause", wantOK: true},
+		{name: "wrong ecosystem", purl: "pkg:pypi/react@1.0.0", wantOK: false},
+		{name: "unknown package", purl: "pkg:npm/not-a-known-package@1.0.0", wantOK: false},
+		{name: "invalid purl", purl: "not-a-purl", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := attribution.KnownLicense(tt.purl)
+			if ok != tt.wantOK {
+				t.Fatalf("KnownLicense(%q) ok = %v, want %v", tt.purl, ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("KnownLicense(%q) = %q, want %q", tt.purl, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFillKnownLicenses tests the FillKnownLicenses function.
+func TestFillKnownLicenses(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "react", Purl: "pkg:npm/react@18.2.0"},
+		{Name: "lodash", Purl: "pkg:npm/lodash@4.17.21", License: strPtr("NOASSERTION")},
+		{Name: "express", Purl: "pkg:npm/express@4.18.2", License: strPtr("Apache-2.0")},
+		{Name: "axios", Purl: "pkg:npm/axios@1.0.0", License: strPtr("NONE")},
+		{Name: "unknown", Purl: "pkg:npm/unknown@1.0.0"},
+	}
+
+	got := attribution.FillKnownLicenses(input, nil)
+
+	wantLicenses := []*string{strPtr("MIT"), strPtr("MIT"), strPtr("Apache-2.0"), strPtr("NONE"), nil}
+	for i, want := range wantLicenses {
+		switch {
+		case want == nil && got[i].License != nil:
+			t.Errorf("FillKnownLicenses()[%d].License = %q, want nil", i, *got[i].License)
+		case want != nil && (got[i].License == nil || *got[i].License != *want):
+			t.Errorf("FillKnownLicenses()[%d].License = %v, want %q", i, got[i].License, *want)
+		}
+	}
+
+	if got[0].Provenance[attribution.FieldLicense] != attribution.ProvenanceHeuristic {
+		t.Errorf("FillKnownLicenses()[0].Provenance = %v, want heuristic license", got[0].Provenance)
+	}
+	if _, ok := got[2].Provenance[attribution.FieldLicense]; ok {
+		t.Errorf("FillKnownLicenses()[2].Provenance = %v, want no license provenance", got[2].Provenance)
+	}
+
+	if input[0].License != nil {
+		t.Errorf("FillKnownLicenses() modified input license to %q", *input[0].License)
+	}
+}
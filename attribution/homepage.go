@@ -0,0 +1,252 @@
+package attribution
+
+import (
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// NormalizeURL cleans up a homepage or repository URL copied from an SBOM before it lands in Attribution.URL:
+//   - surrounding whitespace, quotes, and brackets, trailing punctuation, and anything after the first space are
+//     stripped (e.g. "<https://lodash.com>," or "https://lodash.com (homepage)")
+//   - VCS prefixes are stripped ("git+https://..."), and git:// and scp-like ("git@github.com:owner/repo") locations
+//     become https URLs
+//   - a missing scheme defaults to https if the value starts with a domain name ("www.example.com"), and schemes other
+//     than http and https are rejected
+//   - the host is lowercased, internationalized domain names are converted to punycode ("xn--..."), and user
+//     information is dropped
+//   - a ".git" suffix and a "#readme" fragment are stripped
+//
+// Returns ok as false if the value is not a usable web URL, such as NONE, NOASSERTION, or a mailto: link.
+func NormalizeURL(raw string) (string, bool) {
+	s := trimURLGarbage(raw)
+	if s == "" || s == "NONE" || s == "NOASSERTION" {
+		return "", false
+	}
+
+	for _, vcs := range vcsPrefixes() {
+		if rest, ok := strings.CutPrefix(s, vcs); ok {
+			s = rest
+			break
+		}
+	}
+	s = scpToHTTPS(s)
+	if rest, ok := strings.CutPrefix(s, "git://"); ok {
+		s = "https://" + rest
+	}
+	schemeless := !strings.Contains(s, "://")
+	if schemeless {
+		// A colon is either an opaque scheme ("mailto:") or a port after a domain name
+		if before, _, found := strings.Cut(s, ":"); found && !strings.Contains(before, ".") {
+			return "", false
+		}
+		s = "https://" + s
+	}
+
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Hostname() == "" {
+		return "", false
+	}
+	// Without a scheme, only a domain name is a convincing host (not "README" or "see-license")
+	if schemeless && !strings.Contains(u.Hostname(), ".") {
+		return "", false
+	}
+
+	host, ok := asciiHost(u.Hostname())
+	if !ok {
+		return "", false
+	}
+	if port := u.Port(); port != "" {
+		host += ":" + port
+	}
+	u.Host = host
+	// Credentials don't belong in a notice
+	u.User = nil
+
+	u.Path = strings.TrimSuffix(u.Path, ".git")
+	u.RawPath = ""
+	if strings.EqualFold(u.Fragment, "readme") {
+		u.Fragment = ""
+		u.RawFragment = ""
+	}
+
+	return u.String(), true
+}
+
+// NormalizeVCSURL cleans up a VCS location, as found in SPDX download locations and purl vcs_url qualifiers
+// ("<vcs>+<transport>://<host>/<path>[@<revision>][#<subpath>]"), reducing it to its repository URL, e.g.
+// "git+https://github.com/lodash/lodash.git@4.17.21" becomes "https://github.com/lodash/lodash". Other values are
+// normalized like NormalizeURL.
+//
+// Returns ok as false if the value is not a usable web URL.
+func NormalizeVCSURL(location string) (string, bool) {
+	location = strings.TrimSpace(location)
+	for _, vcs := range vcsPrefixes() {
+		if rest, ok := strings.CutPrefix(location, vcs); ok {
+			location, _, _ = strings.Cut(rest, "#")
+			// A revision follows the repository path, so only look for it after the host
+			if scheme, path, found := strings.Cut(location, "://"); found {
+				host, repo, _ := strings.Cut(path, "/")
+				if at := strings.LastIndex(repo, "@"); at >= 0 {
+					repo = repo[:at]
+				}
+				location = scheme + "://" + host + "/" + repo
+			}
+			break
+		}
+	}
+
+	return NormalizeURL(location)
+}
+
+// vcsPrefixes returns the VCS tool prefixes of VCS locations, e.g. "git+" in "git+https://github.com/lodash/lodash".
+func vcsPrefixes() []string {
+	return []string{"git+", "hg+", "svn+", "bzr+"}
+}
+
+// trimURLGarbage returns the first whitespace-separated field of s, without surrounding quotes and brackets and
+// trailing punctuation.
+func trimURLGarbage(s string) string {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return ""
+	}
+	s = strings.TrimLeft(fields[0], `<("'[`)
+	return strings.TrimRight(s, `>)"'],;.`)
+}
+
+// scpToHTTPS converts an scp-like git location ("git@github.com:owner/repo.git") to an https URL.
+// Other values are returned unchanged.
+func scpToHTTPS(s string) string {
+	if strings.Contains(s, "://") {
+		return s
+	}
+	userHost, path, found := strings.Cut(s, ":")
+	if !found {
+		return s
+	}
+	_, host, hasUser := strings.Cut(userHost, "@")
+	if !hasUser || host == "" || strings.Contains(host, "/") {
+		return s
+	}
+	return "https://" + host + "/" + strings.TrimPrefix(path, "/")
+}
+
+// asciiHost returns the host lowercased, with internationalized labels converted to punycode.
+// Returns ok as false if the host contains characters that cannot be part of a host name.
+func asciiHost(host string) (string, bool) {
+	labels := strings.Split(strings.ToLower(host), ".")
+	for i, label := range labels {
+		if !utf8.ValidString(label) || strings.ContainsAny(label, " _/\\") {
+			return "", false
+		}
+		if isASCII(label) {
+			continue
+		}
+		labels[i] = "xn--" + punycode(label)
+	}
+	return strings.Join(labels, "."), true
+}
+
+// isASCII reports whether s only contains ASCII characters.
+func isASCII(s string) bool {
+	for i := range len(s) {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// Punycode parameters, see RFC 3492 section 5.
+const (
+	punyBase        = 36
+	punyTMin        = 1
+	punyTMax        = 26
+	punySkew        = 38
+	punyDamp        = 700
+	punyInitialBias = 72
+	punyInitialN    = 128
+)
+
+// punycode encodes a host label with the Punycode algorithm of RFC 3492, without the "xn--" prefix.
+func punycode(label string) string {
+	runes := []rune(label)
+
+	var out []byte
+	for _, r := range runes {
+		if r < utf8.RuneSelf {
+			out = append(out, byte(r))
+		}
+	}
+	basic := len(out)
+	handled := basic
+	if basic > 0 {
+		out = append(out, '-')
+	}
+
+	n, delta, bias := rune(punyInitialN), 0, punyInitialBias
+	for handled < len(runes) {
+		// The next code point to insert is the smallest one not handled yet
+		next := rune(utf8.MaxRune)
+		for _, r := range runes {
+			if r >= n && r < next {
+				next = r
+			}
+		}
+		delta += int(next-n) * (handled + 1)
+		n = next
+
+		for _, r := range runes {
+			if r < n {
+				delta++
+			}
+			if r != n {
+				continue
+			}
+			q := delta
+			for k := punyBase; ; k += punyBase {
+				t := min(max(k-bias, punyTMin), punyTMax)
+				if q < t {
+					break
+				}
+				out = append(out, punyDigit(t+(q-t)%(punyBase-t)))
+				q = (q - t) / (punyBase - t)
+			}
+			out = append(out, punyDigit(q))
+			bias = punyAdapt(delta, handled+1, handled == basic)
+			delta = 0
+			handled++
+		}
+		delta++
+		n++
+	}
+
+	return string(out)
+}
+
+// punyAdapt returns the new bias after encoding a delta, see RFC 3492 section 6.1.
+func punyAdapt(delta, numPoints int, first bool) int {
+	if first {
+		delta /= punyDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / numPoints
+
+	k := 0
+	for delta > (punyBase-punyTMin)*punyTMax/2 {
+		delta /= punyBase - punyTMin
+		k += punyBase
+	}
+	return k + (punyBase-punyTMin+1)*delta/(delta+punySkew)
+}
+
+// punyDigit returns the character encoding a Punycode digit: a-z for 0-25, 0-9 for 26-35.
+func punyDigit(d int) byte {
+	const letters = 26
+	if d < letters {
+		return byte('a' + d)
+	}
+	return byte('0' + d - letters)
+}
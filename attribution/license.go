@@ -0,0 +1,327 @@
+package attribution
+
+import (
+	_ "embed"
+	"iter"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// licenseList is the list of SPDX license identifiers, including deprecated ones, one per line.
+// See https://spdx.org/licenses/
+//
+//go:embed data/license-ids.txt
+var licenseList string
+
+// licenseExceptions is the list of SPDX license exception identifiers, one per line.
+// See https://spdx.org/licenses/exceptions-index.html
+//
+//go:embed data/exceptions.txt
+var licenseExceptions string
+
+// linkingExceptions is the list of SPDX license exceptions that allow combining the licensed code with software under
+// other terms (linking, runtime libraries, generated output), one per line.
+//
+//go:embed data/linking-exceptions.txt
+var linkingExceptions string
+
+// deprecatedLicenses maps deprecated SPDX license identifiers to their current form, one tab-separated pair per line.
+// See https://spdx.org/licenses/#deprecated
+//
+//go:embed data/deprecated.tsv
+var deprecatedLicenses string
+
+// IsLicenseID reports whether id is an identifier on the SPDX license list, e.g. "MIT" or the deprecated "GPL-2.0".
+// LicenseRef- references, special values (NONE, NOASSERTION), and the "+" operator (as in "GPL-2.0+") are not license
+// identifiers. Matching is case-insensitive, as required by the SPDX specification.
+func IsLicenseID(id string) bool {
+	return containsLine(licenseList, id)
+}
+
+// IsLicenseException reports whether id is a known SPDX license exception identifier.
+// Matching is case-insensitive, as required by the SPDX specification.
+func IsLicenseException(id string) bool {
+	return containsLine(licenseExceptions, id)
+}
+
+// IsLinkingException reports whether id is an SPDX license exception that allows combining the licensed code with
+// software under other terms, such as Classpath-exception-2.0 or GCC-exception-3.1, so a copyleft license with this
+// exception does not extend to the software using the code. Matching is case-insensitive.
+func IsLinkingException(id string) bool {
+	return containsLine(linkingExceptions, id)
+}
+
+// LicenseTerm is a license referenced by a license expression, with the exception applied to it, if any.
+type LicenseTerm struct {
+	// License is the license identifier, e.g. "GPL-2.0-only".
+	License string
+	// Exception is the identifier of the exception applied to the license with WITH, e.g. "Classpath-exception-2.0",
+	// or empty if there is none.
+	Exception string
+}
+
+// String returns the term as it is written in a license expression, e.g. "GPL-2.0-only WITH Classpath-exception-2.0".
+func (t LicenseTerm) String() string {
+	if t.Exception == "" {
+		return t.License
+	}
+	return t.License + " WITH " + t.Exception
+}
+
+// LicenseTerms returns the licenses referenced by a license expression, in order, each with the exception applied to
+// it, if any. Unlike LicenseIDs, exceptions are never returned as licenses.
+func LicenseTerms(expression string) []LicenseTerm {
+	tokens := expressionTokens(expression)
+
+	var terms []LicenseTerm
+	for i := 0; i < len(tokens); i++ {
+		if isLicenseOperator(tokens[i]) {
+			continue
+		}
+		term := LicenseTerm{License: tokens[i]}
+		if i+2 < len(tokens) && strings.EqualFold(tokens[i+1], "WITH") && !isLicenseOperator(tokens[i+2]) {
+			term.Exception = tokens[i+2]
+			i += 2
+		}
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// LicenseExpression returns the license expression of the attribution, with a split exception (see
+// SplitLicenseExceptions) joined back, e.g. "GPL-2.0-only WITH Classpath-exception-2.0". Returns an empty string if
+// the attribution has no license.
+func (a Attribution) LicenseExpression() string {
+	if a.License == nil {
+		return ""
+	}
+	if a.Exception != nil && *a.Exception != "" {
+		return LicenseTerm{License: *a.License, Exception: *a.Exception}.String()
+	}
+	return *a.License
+}
+
+// ParseLicenseException splits a simple "<license> WITH <exception>" expression into its license and exception parts.
+// Returns ok as false if the expression does not use the WITH operator, or if it is a compound expression
+// (using AND/OR), in which case it should be treated as an opaque string.
+func ParseLicenseException(expression string) (string, string, bool) {
+	expr := strings.TrimSpace(expression)
+	if strings.HasPrefix(expr, "(") && strings.HasSuffix(expr, ")") {
+		expr = strings.TrimSpace(expr[1 : len(expr)-1])
+	}
+
+	fields := strings.Fields(expr)
+	const simpleWithLen = 3 // <license> WITH <exception>
+	if len(fields) != simpleWithLen || !strings.EqualFold(fields[1], "WITH") {
+		return "", "", false
+	}
+
+	return fields[0], fields[2], true
+}
+
+// SplitLicenseExceptions returns a copy of attributions where simple "<license> WITH <exception>" license
+// expressions are split, leaving the license in License and moving the exception to Exception.
+// Exceptions that are not in the SPDX exceptions list, in simple and compound expressions alike, are logged as a
+// warning; simple ones are still split.
+// The logger parameter is optional; pass nil to disable logging.
+func SplitLicenseExceptions(attributions []Attribution, logger *slog.Logger) []Attribution {
+	result := make([]Attribution, 0, len(attributions))
+
+	for _, a := range attributions {
+		if a.License != nil {
+			for _, term := range LicenseTerms(*a.License) {
+				if term.Exception != "" && !IsLicenseException(term.Exception) && logger != nil {
+					logger.Warn("unknown SPDX license exception", "name", a.Name, "exception", term.Exception)
+				}
+			}
+			if license, exception, ok := ParseLicenseException(*a.License); ok {
+				a.License = &license
+				a.Exception = &exception
+			}
+		}
+		result = append(result, a)
+	}
+
+	return result
+}
+
+// CurrentLicenseID returns the current SPDX expression for a deprecated license identifier.
+// For example, "GPL-2.0" maps to "GPL-2.0-only" and "GPL-2.0-with-classpath-exception" maps to
+// "GPL-2.0-only WITH Classpath-exception-2.0".
+// Returns ok as false if the identifier is not deprecated.
+func CurrentLicenseID(id string) (string, bool) {
+	for line := range strings.Lines(deprecatedLicenses) {
+		deprecated, current, found := strings.Cut(strings.TrimSpace(line), "\t")
+		if found && strings.EqualFold(deprecated, id) {
+			return current, true
+		}
+	}
+	return "", false
+}
+
+// baseLicenseID returns the current form of a license identifier (see CurrentLicenseID) without its "-only",
+// "-or-later", or "+" suffix, e.g. "GPL-2.0" for "GPL-2.0+". A deprecated identifier whose current form carries an
+// exception (e.g. "GPL-2.0-with-classpath-exception") is reduced to its license.
+func baseLicenseID(id string) string {
+	id = strings.TrimSpace(id)
+	if current, ok := CurrentLicenseID(id); ok {
+		id, _, _ = strings.Cut(current, " ")
+	}
+	for _, suffix := range []string{"-only", "-or-later", "+"} {
+		if base, ok := strings.CutSuffix(id, suffix); ok {
+			return base
+		}
+	}
+	return id
+}
+
+// RemapDeprecatedLicense replaces every deprecated license identifier in a license expression with its current form.
+// Returns the remapped expression and whether anything was changed.
+func RemapDeprecatedLicense(expression string) (string, bool) {
+	var b strings.Builder
+	changed := false
+
+	for token := range licenseTokens(expression) {
+		if current, ok := CurrentLicenseID(token); ok {
+			b.WriteString(current)
+			changed = true
+			continue
+		}
+		b.WriteString(token)
+	}
+
+	if !changed {
+		return expression, false
+	}
+	return b.String(), true
+}
+
+// RemapDeprecatedLicenses returns a copy of attributions with deprecated SPDX license identifiers replaced by their
+// current form, so policies written against modern identifiers match old SBOM data.
+// A single warning listing every remapped value is logged.
+// The logger parameter is optional; pass nil to disable logging.
+func RemapDeprecatedLicenses(attributions []Attribution, logger *slog.Logger) []Attribution {
+	result := make([]Attribution, 0, len(attributions))
+	var remapped []string
+
+	for _, a := range attributions {
+		if a.License != nil {
+			if license, ok := RemapDeprecatedLicense(*a.License); ok {
+				remapped = append(remapped, *a.License+" -> "+license)
+				a.License = &license
+			}
+		}
+		result = append(result, a)
+	}
+
+	if len(remapped) > 0 && logger != nil {
+		slices.Sort(remapped)
+		logger.Warn("remapped deprecated SPDX license identifiers", "remapped", slices.Compact(remapped))
+	}
+
+	return result
+}
+
+// JoinLicenses joins license expressions that all apply to a package with AND, parenthesizing compound expressions,
+// so a package listing several licenses keeps all of them. Empty and repeated expressions are skipped.
+func JoinLicenses(licenses []string) string {
+	parts := make([]string, 0, len(licenses))
+	for _, license := range licenses {
+		license = strings.TrimSpace(license)
+		if license == "" || slices.Contains(parts, license) {
+			continue
+		}
+		parts = append(parts, license)
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+
+	for i, license := range parts {
+		if strings.Contains(license, " ") {
+			parts[i] = "(" + license + ")"
+		}
+	}
+	return strings.Join(parts, " AND ")
+}
+
+// LicenseIDs returns the license and exception identifiers referenced by a license expression, in order,
+// without operators (AND, OR, WITH) or parentheses.
+func LicenseIDs(expression string) []string {
+	var ids []string
+	for token := range licenseTokens(expression) {
+		switch strings.ToUpper(strings.TrimSpace(token)) {
+		case "", "(", ")", "AND", "OR", "WITH":
+			continue
+		}
+		ids = append(ids, token)
+	}
+	return ids
+}
+
+// FindLicenseID returns the first license identifier referenced by a license expression that starts with prefix,
+// ignoring case. For example, the prefix "GPL" finds "GPL-3.0-or-later" in "MIT OR GPL-3.0-or-later", but nothing in
+// "LGPL-2.1-only". Exception identifiers are not matched. Returns ok as false if no identifier matches.
+func FindLicenseID(expression, prefix string) (string, bool) {
+	for _, term := range LicenseTerms(expression) {
+		if HasLicensePrefix(term.License, prefix) {
+			return term.License, true
+		}
+	}
+	return "", false
+}
+
+// HasLicensePrefix reports whether a license identifier starts with prefix, ignoring case.
+func HasLicensePrefix(id, prefix string) bool {
+	return len(id) >= len(prefix) && strings.EqualFold(id[:len(prefix)], prefix)
+}
+
+// expressionTokens returns the tokens of a license expression: identifiers, operators, and parentheses, without
+// whitespace.
+func expressionTokens(expression string) []string {
+	var tokens []string
+	for token := range licenseTokens(expression) {
+		if token = strings.TrimSpace(token); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// licenseTokens yields the tokens of a license expression, alternating between identifiers and the separators
+// (whitespace and parentheses) between them, so that concatenating all tokens yields the original expression.
+func licenseTokens(expression string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		isSeparator := func(r rune) bool {
+			return r == ' ' || r == '\t' || r == '\n' || r == '(' || r == ')'
+		}
+
+		start := 0
+		for i, r := range expression {
+			if !isSeparator(r) {
+				continue
+			}
+			if i > start && !yield(expression[start:i]) {
+				return
+			}
+			if !yield(string(r)) {
+				return
+			}
+			start = i + len(string(r))
+		}
+		if start < len(expression) {
+			yield(expression[start:])
+		}
+	}
+}
+
+// containsLine reports whether the newline-separated list contains s, ignoring case.
+func containsLine(list, s string) bool {
+	for line := range strings.Lines(list) {
+		if strings.EqualFold(strings.TrimSpace(line), s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,370 @@
+package attribution_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestIsLicenseException tests the IsLicenseException function.
+func TestIsLicenseException(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{id: "LLVM-exception", want: true},
+		{id: "Classpath-exception-2.0", want: true},
+		{id: "classpath-exception-2.0", want: true},
+		{id: "MIT", want: false},
+		{id: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			t.Parallel()
+
+			if got := attribution.IsLicenseException(tt.id); got != tt.want {
+				t.Errorf("IsLicenseException(%q) = %v, want %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestIsLicenseID tests that only identifiers on the SPDX license list are license identifiers.
+func TestIsLicenseID(t *testing.T) {
+	t.Parallel()
+
+	for id, want := range map[string]bool{
+		"MIT":                     true,
+		"apache-2.0":              true,
+		"AGPL-3.0-only":           true,
+		"GPL-2.0":                 true,
+		"GPL-2.0+":                false,
+		"Classpath-exception-2.0": false,
+		"LicenseRef-Acme":         false,
+		"NOASSERTION":             false,
+		"Custom":                  false,
+		"":                        false,
+	} {
+		if got := attribution.IsLicenseID(id); got != want {
+			t.Errorf("IsLicenseID(%q) = %v, want %v", id, got, want)
+		}
+	}
+}
+
+// TestIsLinkingException tests that only exceptions lifting copyleft from combined works are linking exceptions.
+func TestIsLinkingException(t *testing.T) {
+	t.Parallel()
+
+	for id, want := range map[string]bool{
+		"Classpath-exception-2.0":   true,
+		"gcc-exception-3.1":         true,
+		"openvpn-openssl-exception": false,
+		"LLVM-exception":            false,
+		"":                          false,
+	} {
+		if got := attribution.IsLinkingException(id); got != want {
+			t.Errorf("IsLinkingException(%q) = %v, want %v", id, got, want)
+		}
+	}
+}
+
+// TestLicenseTerms tests that licenses are paired with the exception applied to them.
+func TestLicenseTerms(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expression string
+		want       []attribution.LicenseTerm
+	}{
+		{expression: "MIT", want: []attribution.LicenseTerm{{License: "MIT"}}},
+		{
+			expression: "(GPL-2.0-only with Classpath-exception-2.0) OR MIT",
+			want: []attribution.LicenseTerm{
+				{License: "GPL-2.0-only", Exception: "Classpath-exception-2.0"},
+				{License: "MIT"},
+			},
+		},
+		{expression: "GPL-2.0-only WITH", want: []attribution.LicenseTerm{{License: "GPL-2.0-only"}}},
+		{expression: "", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			t.Parallel()
+
+			if got := attribution.LicenseTerms(tt.expression); !slices.Equal(got, tt.want) {
+				t.Errorf("LicenseTerms(%q) = %v, want %v", tt.expression, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestAttribution_LicenseExpression tests that a split exception is joined back to the license.
+func TestAttribution_LicenseExpression(t *testing.T) {
+	t.Parallel()
+
+	a := attribution.Attribution{License: strPtr("GPL-2.0-only"), Exception: strPtr("Classpath-exception-2.0")}
+	if got, want := a.LicenseExpression(), "GPL-2.0-only WITH Classpath-exception-2.0"; got != want {
+		t.Errorf("LicenseExpression() = %q, want %q", got, want)
+	}
+	if got := (attribution.Attribution{}).LicenseExpression(); got != "" {
+		t.Errorf("LicenseExpression() without a license = %q, want empty", got)
+	}
+}
+
+// TestParseLicenseException tests the ParseLicenseException function.
+func TestParseLicenseException(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		expression    string
+		wantLicense   string
+		wantException string
+		wantOK        bool
+	}{
+		{
+			name:          "simple WITH",
+			expression:    "Apache-2.0 WITH LLVM-exception",
+			wantLicense:   "Apache-2.0",
+			wantException: "LLVM-exception",
+			wantOK:        true,
+		},
+		{
+			name:          "parenthesized",
+			expression:    "(GPL-2.0-only WITH Classpath-exception-2.0)",
+			wantLicense:   "GPL-2.0-only",
+			wantException: "Classpath-exception-2.0",
+			wantOK:        true,
+		},
+		{
+			name:          "lowercase operator",
+			expression:    "GPL-3.0-or-later with GCC-exception-3.1",
+			wantLicense:   "GPL-3.0-or-later",
+			wantException: "GCC-exception-3.1",
+			wantOK:        true,
+		},
+		{name: "no exception", expression: "MIT", wantOK: false},
+		{name: "compound expression", expression: "Apache-2.0 WITH LLVM-exception OR MIT", wantOK: false},
+		{name: "empty", expression: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			license, exception, ok := attribution.ParseLicenseException(tt.expression)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseLicenseException(%q) ok = %v, want %v", tt.expression, ok, tt.wantOK)
+			}
+			if license != tt.wantLicense {
+				t.Errorf("ParseLicenseException(%q) license = %q, want %q", tt.expression, license, tt.wantLicense)
+			}
+			if exception != tt.wantException {
+				t.Errorf("ParseLicenseException(%q) exception = %q, want %q",
+					tt.expression, exception, tt.wantException)
+			}
+		})
+	}
+}
+
+// TestSplitLicenseExceptions tests the SplitLicenseExceptions function.
+func TestSplitLicenseExceptions(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "llvm", License: strPtr("Apache-2.0 WITH LLVM-exception")},
+		{Name: "custom", License: strPtr("GPL-2.0-only WITH My-Custom-exception")},
+		{Name: "plain", License: strPtr("MIT")},
+		{Name: "none", License: nil},
+	}
+
+	got := attribution.SplitLicenseExceptions(input, nil)
+
+	if len(got) != len(input) {
+		t.Fatalf("SplitLicenseExceptions() length = %d, want %d", len(got), len(input))
+	}
+	if *got[0].License != "Apache-2.0" || got[0].Exception == nil || *got[0].Exception != "LLVM-exception" {
+		t.Errorf("SplitLicenseExceptions()[0] = %+v, want Apache-2.0 + LLVM-exception", got[0])
+	}
+	if got[1].Exception == nil || *got[1].Exception != "My-Custom-exception" {
+		t.Errorf("SplitLicenseExceptions()[1] should split unknown exceptions, got %+v", got[1])
+	}
+	if *got[2].License != "MIT" || got[2].Exception != nil {
+		t.Errorf("SplitLicenseExceptions()[2] = %+v, want unchanged", got[2])
+	}
+	if got[3].License != nil || got[3].Exception != nil {
+		t.Errorf("SplitLicenseExceptions()[3] = %+v, want unchanged", got[3])
+	}
+
+	// Input must not be modified
+	if *input[0].License != "Apache-2.0 WITH LLVM-exception" {
+		t.Errorf("SplitLicenseExceptions() modified input license to %q", *input[0].License)
+	}
+}
+
+// TestRemapDeprecatedLicense tests the RemapDeprecatedLicense function.
+func TestRemapDeprecatedLicense(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		expression  string
+		want        string
+		wantChanged bool
+	}{
+		{name: "GPL-2.0", expression: "GPL-2.0", want: "GPL-2.0-only", wantChanged: true},
+		{name: "GPL-2.0+", expression: "GPL-2.0+", want: "GPL-2.0-or-later", wantChanged: true},
+		{name: "AGPL-3.0", expression: "AGPL-3.0", want: "AGPL-3.0-only", wantChanged: true},
+		{
+			name:        "BSD-2-Clause-FreeBSD",
+			expression:  "BSD-2-Clause-FreeBSD",
+			want:        "BSD-2-Clause-Views",
+			wantChanged: true,
+		},
+		{
+			name:        "with exception",
+			expression:  "GPL-2.0-with-classpath-exception",
+			want:        "GPL-2.0-only WITH Classpath-exception-2.0",
+			wantChanged: true,
+		},
+		{
+			name:        "compound expression",
+			expression:  "(LGPL-2.1 OR MIT) AND GPL-3.0+",
+			want:        "(LGPL-2.1-only OR MIT) AND GPL-3.0-or-later",
+			wantChanged: true,
+		},
+		{name: "current identifier", expression: "GPL-2.0-only", want: "GPL-2.0-only", wantChanged: false},
+		{name: "empty", expression: "", want: "", wantChanged: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, changed := attribution.RemapDeprecatedLicense(tt.expression)
+			if got != tt.want {
+				t.Errorf("RemapDeprecatedLicense(%q) = %q, want %q", tt.expression, got, tt.want)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("RemapDeprecatedLicense(%q) changed = %v, want %v", tt.expression, changed, tt.wantChanged)
+			}
+		})
+	}
+}
+
+// TestRemapDeprecatedLicenses tests the RemapDeprecatedLicenses function.
+func TestRemapDeprecatedLicenses(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "old", License: strPtr("GPL-2.0")},
+		{Name: "current", License: strPtr("MIT")},
+		{Name: "none", License: nil},
+	}
+
+	got := attribution.RemapDeprecatedLicenses(input, nil)
+
+	if *got[0].License != "GPL-2.0-only" {
+		t.Errorf("RemapDeprecatedLicenses()[0].License = %q, want %q", *got[0].License, "GPL-2.0-only")
+	}
+	if *got[1].License != "MIT" {
+		t.Errorf("RemapDeprecatedLicenses()[1].License = %q, want %q", *got[1].License, "MIT")
+	}
+	if got[2].License != nil {
+		t.Errorf("RemapDeprecatedLicenses()[2].License = %q, want nil", *got[2].License)
+	}
+	if *input[0].License != "GPL-2.0" {
+		t.Errorf("RemapDeprecatedLicenses() modified input license to %q", *input[0].License)
+	}
+}
+
+// TestLicenseIDs tests the LicenseIDs function.
+func TestLicenseIDs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expression string
+		want       []string
+	}{
+		{expression: "MIT", want: []string{"MIT"}},
+		{expression: "(MIT OR Apache-2.0) AND BSD-3-Clause", want: []string{"MIT", "Apache-2.0", "BSD-3-Clause"}},
+		{
+			expression: "GPL-2.0-only WITH Classpath-exception-2.0",
+			want:       []string{"GPL-2.0-only", "Classpath-exception-2.0"},
+		},
+		{expression: "", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			t.Parallel()
+
+			if got := attribution.LicenseIDs(tt.expression); !slices.Equal(got, tt.want) {
+				t.Errorf("LicenseIDs(%q) = %v, want %v", tt.expression, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFindLicenseID tests the FindLicenseID function.
+func TestFindLicenseID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expression string
+		prefix     string
+		want       string
+		wantOK     bool
+	}{
+		{expression: "GPL-2.0-only", prefix: "GPL", want: "GPL-2.0-only", wantOK: true},
+		{expression: "MIT OR GPL-3.0-or-later", prefix: "gpl", want: "GPL-3.0-or-later", wantOK: true},
+		{expression: "LGPL-2.1-only", prefix: "GPL", wantOK: false},
+		{expression: "AGPL-3.0-only", prefix: "AGPL-3.0", want: "AGPL-3.0-only", wantOK: true},
+		{expression: "GPL-2.0-only WITH Classpath-exception-2.0", prefix: "Classpath", wantOK: false},
+		{expression: "", prefix: "MIT", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression+"/"+tt.prefix, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := attribution.FindLicenseID(tt.expression, tt.prefix)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("FindLicenseID(%q, %q) = %q, %v, want %q, %v",
+					tt.expression, tt.prefix, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestJoinLicenses tests joining license expressions that all apply to a package.
+func TestJoinLicenses(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		licenses []string
+		want     string
+	}{
+		{name: "none", licenses: nil, want: ""},
+		{name: "single compound", licenses: []string{"MIT OR Apache-2.0"}, want: "MIT OR Apache-2.0"},
+		{name: "several", licenses: []string{"MIT", "Apache-2.0 WITH LLVM-exception"},
+			want: "MIT AND (Apache-2.0 WITH LLVM-exception)"},
+		{name: "empty and repeated", licenses: []string{"MIT", " ", "MIT", "ISC"}, want: "MIT AND ISC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := attribution.JoinLicenses(tt.licenses); got != tt.want {
+				t.Errorf("JoinLicenses() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package attribution
+
+import (
+	_ "embed"
+	"strings"
+)
+
+// licenseCategories maps SPDX license identifiers (without "-only" and "-or-later" suffixes) to their category, one
+// tab-separated pair per line.
+//
+//go:embed data/categories.tsv
+var licenseCategories string
+
+// LicenseCategory classifies a license by the obligations it places on software that uses it.
+type LicenseCategory string
+
+const (
+	// LicenseCategoryPublicDomain means the license waives all rights, e.g. CC0-1.0.
+	LicenseCategoryPublicDomain LicenseCategory = "public-domain"
+	// LicenseCategoryPermissive means the license only requires attribution, e.g. MIT or Apache-2.0.
+	LicenseCategoryPermissive LicenseCategory = "permissive"
+	// LicenseCategoryWeakCopyleft means modifications of the licensed files must be shared under the same license,
+	// but not the software using them, e.g. LGPL-2.1-only or MPL-2.0.
+	LicenseCategoryWeakCopyleft LicenseCategory = "weak-copyleft"
+	// LicenseCategoryStrongCopyleft means software combined with the licensed code must be shared under the same
+	// license, e.g. the GPL and AGPL families.
+	LicenseCategoryStrongCopyleft LicenseCategory = "strong-copyleft"
+	// LicenseCategoryUnknown means the license is missing, NONE, not a standard SPDX license, or not classified.
+	LicenseCategoryUnknown LicenseCategory = "unknown"
+)
+
+// LicenseCategories returns every license category, from the least to the most restrictive.
+func LicenseCategories() []LicenseCategory {
+	return []LicenseCategory{
+		LicenseCategoryPublicDomain,
+		LicenseCategoryPermissive,
+		LicenseCategoryWeakCopyleft,
+		LicenseCategoryUnknown,
+		LicenseCategoryStrongCopyleft,
+	}
+}
+
+// ParseLicenseCategory returns the license category with the given name, ignoring case.
+// Returns ok as false for unknown names.
+func ParseLicenseCategory(name string) (LicenseCategory, bool) {
+	for _, c := range LicenseCategories() {
+		if strings.EqualFold(string(c), strings.TrimSpace(name)) {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+// restrictiveness ranks a category: higher is more restrictive. Unknown licenses rank below strong copyleft, so a
+// strong copyleft license combined with an unknown one is still reported as strong copyleft.
+func (c LicenseCategory) restrictiveness() int {
+	for i, category := range LicenseCategories() {
+		if category == c {
+			return i
+		}
+	}
+	return LicenseCategoryUnknown.restrictiveness()
+}
+
+// LicenseIDCategory returns the category of a single SPDX license identifier, e.g. strong copyleft for
+// "GPL-3.0-or-later". Matching is case-insensitive, deprecated identifiers are resolved (see CurrentLicenseID), and
+// the "-only", "-or-later", and "+" variants of a license share its category.
+func LicenseIDCategory(id string) LicenseCategory {
+	id = baseLicenseID(id)
+	for line := range strings.Lines(licenseCategories) {
+		licenseID, category, found := strings.Cut(strings.TrimSpace(line), "\t")
+		if found && strings.EqualFold(licenseID, id) {
+			return LicenseCategory(category)
+		}
+	}
+	return LicenseCategoryUnknown
+}
+
+// ClassifyLicense returns the category of a license expression, taking the least restrictive choice of an OR and the
+// most restrictive part of an AND: "MIT OR GPL-2.0-only" is permissive, "MIT AND GPL-2.0-only" is strong copyleft.
+// A strong copyleft license with a linking exception (see IsLinkingException) is weak copyleft, e.g.
+// "GPL-2.0-only WITH Classpath-exception-2.0"; other exceptions leave the category of their license unchanged.
+// Returns LicenseCategoryUnknown for malformed expressions and special values (NONE, NOASSERTION).
+func ClassifyLicense(expression string) LicenseCategory {
+	if current, ok := RemapDeprecatedLicense(expression); ok {
+		expression = current
+	}
+	p := &licenseParser{tokens: expressionTokens(expression)}
+	category, ok := p.parseOr()
+	if !ok || p.pos != len(p.tokens) {
+		return LicenseCategoryUnknown
+	}
+	return category
+}
+
+// LicenseCategory returns the category of the license of the attribution, including a split exception (see
+// ClassifyLicense). Returns LicenseCategoryUnknown if the license is unknown or explicitly none.
+func (a Attribution) LicenseCategory() LicenseCategory {
+	if a.LicenseStatus() != LicenseStatusKnown {
+		return LicenseCategoryUnknown
+	}
+	return ClassifyLicense(a.LicenseExpression())
+}
+
+// licenseParser classifies license expressions by recursive descent, following the SPDX precedence: WITH binds
+// tighter than AND, which binds tighter than OR.
+type licenseParser struct {
+	tokens []string
+	pos    int
+}
+
+// peek reports whether the next token is the given operator or parenthesis, ignoring case.
+func (p *licenseParser) peek(op string) bool {
+	return p.pos < len(p.tokens) && strings.EqualFold(p.tokens[p.pos], op)
+}
+
+// parseOr parses "<and> [OR <and>]...", returning the least restrictive category.
+func (p *licenseParser) parseOr() (LicenseCategory, bool) {
+	category, ok := p.parseAnd()
+	for ok && p.peek("OR") {
+		p.pos++
+		var next LicenseCategory
+		if next, ok = p.parseAnd(); ok && next.restrictiveness() < category.restrictiveness() {
+			category = next
+		}
+	}
+	return category, ok
+}
+
+// parseAnd parses "<with> [AND <with>]...", returning the most restrictive category.
+func (p *licenseParser) parseAnd() (LicenseCategory, bool) {
+	category, ok := p.parseWith()
+	for ok && p.peek("AND") {
+		p.pos++
+		var next LicenseCategory
+		if next, ok = p.parseWith(); ok && next.restrictiveness() > category.restrictiveness() {
+			category = next
+		}
+	}
+	return category, ok
+}
+
+// parseWith parses "<primary> [WITH <exception>]", returning the category of the license, lowered to weak copyleft by
+// a linking exception.
+func (p *licenseParser) parseWith() (LicenseCategory, bool) {
+	category, ok := p.parsePrimary()
+	if ok && p.peek("WITH") {
+		p.pos++
+		if p.pos >= len(p.tokens) || isLicenseOperator(p.tokens[p.pos]) {
+			return "", false
+		}
+		if category == LicenseCategoryStrongCopyleft && IsLinkingException(p.tokens[p.pos]) {
+			category = LicenseCategoryWeakCopyleft
+		}
+		p.pos++
+	}
+	return category, ok
+}
+
+// parsePrimary parses a license identifier or a parenthesized expression.
+func (p *licenseParser) parsePrimary() (LicenseCategory, bool) {
+	if p.pos >= len(p.tokens) {
+		return "", false
+	}
+	if p.peek("(") {
+		p.pos++
+		category, ok := p.parseOr()
+		if !ok || !p.peek(")") {
+			return "", false
+		}
+		p.pos++
+		return category, true
+	}
+
+	token := p.tokens[p.pos]
+	if isLicenseOperator(token) {
+		return "", false
+	}
+	p.pos++
+	return LicenseIDCategory(token), true
+}
+
+// isLicenseOperator reports whether a token is an operator or a parenthesis rather than an identifier.
+func isLicenseOperator(token string) bool {
+	switch strings.ToUpper(token) {
+	case "AND", "OR", "WITH", "(", ")":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,100 @@
+package attribution_test
+
+import (
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestClassifyLicense tests that license expressions are classified by their least restrictive choice and their most
+// restrictive requirement.
+func TestClassifyLicense(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expression string
+		want       attribution.LicenseCategory
+	}{
+		{expression: "MIT", want: attribution.LicenseCategoryPermissive},
+		{expression: "cc0-1.0", want: attribution.LicenseCategoryPublicDomain},
+		{expression: "GPL-3.0-or-later", want: attribution.LicenseCategoryStrongCopyleft},
+		{expression: "AGPL-3.0", want: attribution.LicenseCategoryStrongCopyleft},
+		{expression: "GPL-2.0+", want: attribution.LicenseCategoryStrongCopyleft},
+		{expression: "GPL-2.0-with-classpath-exception", want: attribution.LicenseCategoryWeakCopyleft},
+		{expression: "LGPL-2.1-only", want: attribution.LicenseCategoryWeakCopyleft},
+		{expression: "MIT OR GPL-2.0-only", want: attribution.LicenseCategoryPermissive},
+		{expression: "MIT AND GPL-2.0-only", want: attribution.LicenseCategoryStrongCopyleft},
+		{expression: "GPL-3.0-only AND LicenseRef-Acme", want: attribution.LicenseCategoryStrongCopyleft},
+		{expression: "MIT AND LicenseRef-Acme", want: attribution.LicenseCategoryUnknown},
+		{expression: "(MIT OR GPL-2.0-only) AND MPL-2.0", want: attribution.LicenseCategoryWeakCopyleft},
+		{expression: "MIT OR GPL-2.0-only AND LGPL-2.1-only", want: attribution.LicenseCategoryPermissive},
+		{expression: "GPL-2.0-only WITH Classpath-exception-2.0", want: attribution.LicenseCategoryWeakCopyleft},
+		{expression: "GPL-3.0-or-later WITH GCC-exception-3.1", want: attribution.LicenseCategoryWeakCopyleft},
+		{expression: "GPL-2.0-only WITH openvpn-openssl-exception", want: attribution.LicenseCategoryStrongCopyleft},
+		{expression: "GPL-2.0-only WITH Unknown-exception", want: attribution.LicenseCategoryStrongCopyleft},
+		{
+			expression: "MIT AND GPL-2.0-only WITH Classpath-exception-2.0",
+			want:       attribution.LicenseCategoryWeakCopyleft,
+		},
+		{expression: "GPL-2.0-only WITH", want: attribution.LicenseCategoryUnknown},
+		{expression: "(MIT", want: attribution.LicenseCategoryUnknown},
+		{expression: "MIT AND", want: attribution.LicenseCategoryUnknown},
+		{expression: "MIT GPL-2.0-only", want: attribution.LicenseCategoryUnknown},
+		{expression: "NOASSERTION", want: attribution.LicenseCategoryUnknown},
+		{expression: "", want: attribution.LicenseCategoryUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			t.Parallel()
+			if got := attribution.ClassifyLicense(tt.expression); got != tt.want {
+				t.Errorf("ClassifyLicense(%q) = %q, want %q", tt.expression, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestAttribution_LicenseCategory tests that packages without a known license are of unknown category.
+func TestAttribution_LicenseCategory(t *testing.T) {
+	t.Parallel()
+
+	none := attribution.LicenseNone
+	gpl := "GPL-3.0-only"
+	classpath := "Classpath-exception-2.0"
+	tests := []struct {
+		name string
+		a    attribution.Attribution
+		want attribution.LicenseCategory
+	}{
+		{name: "missing", a: attribution.Attribution{}, want: attribution.LicenseCategoryUnknown},
+		{name: "none", a: attribution.Attribution{License: &none}, want: attribution.LicenseCategoryUnknown},
+		{name: "gpl", a: attribution.Attribution{License: &gpl}, want: attribution.LicenseCategoryStrongCopyleft},
+		{
+			name: "split exception",
+			a:    attribution.Attribution{License: &gpl, Exception: &classpath},
+			want: attribution.LicenseCategoryWeakCopyleft,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.a.LicenseCategory(); got != tt.want {
+				t.Errorf("LicenseCategory() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseLicenseCategory tests that categories are parsed by name, ignoring case.
+func TestParseLicenseCategory(t *testing.T) {
+	t.Parallel()
+
+	if got, ok := attribution.ParseLicenseCategory(" Strong-Copyleft "); !ok ||
+		got != attribution.LicenseCategoryStrongCopyleft {
+		t.Errorf("ParseLicenseCategory(Strong-Copyleft) = %q, %v, want strong-copyleft", got, ok)
+	}
+	if _, ok := attribution.ParseLicenseCategory("copyleft"); ok {
+		t.Error("ParseLicenseCategory(copyleft) succeeded, want an unknown category")
+	}
+}
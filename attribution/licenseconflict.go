@@ -0,0 +1,71 @@
+package attribution
+
+import "strings"
+
+// LicenseConflictReport lists the packages found with different licenses in different SBOMs, e.g. by different
+// scanners. Deduplication keeps the license of the first occurrence, so conflicts must be resolved manually, e.g. with
+// a policy.Corrections file.
+type LicenseConflictReport struct {
+	// Conflicts lists a conflict for every package with several licenses, in input order.
+	Conflicts []LicenseConflict `json:"conflicts"`
+}
+
+// LicenseConflict is a package found with different licenses.
+type LicenseConflict struct {
+	// Name is the package name, qualified by its group (see Attribution.QualifiedName).
+	Name string `json:"name"`
+	// Version is the package version.
+	Version string `json:"version,omitempty"`
+	// Purl is the package URL.
+	Purl string `json:"purl,omitempty"`
+	// Licenses lists the conflicting licenses, the one deduplication keeps first, each with the SBOM files claiming it.
+	Licenses []ConflictingLicense `json:"licenses"`
+}
+
+// ConflictingLicense is one of the licenses of a LicenseConflict.
+type ConflictingLicense struct {
+	// License is the license expression (see Attribution.LicenseExpression).
+	License string `json:"license"`
+	// Sources lists the SBOM files claiming the license.
+	Sources []string `json:"sources"`
+}
+
+// ReportLicenseConflicts returns the packages of attributions, before deduplication, that are found with different
+// licenses. Attributions are the same package if they share their DedupKey, and licenses are compared as expressions
+// ignoring case. Unknown licenses (see ReportUnknownLicenses) do not conflict with known ones.
+func ReportLicenseConflicts(attributions []Attribution) LicenseConflictReport {
+	var keys []string
+	byKey := make(map[string]*LicenseConflict)
+	for _, a := range attributions {
+		if a.LicenseStatus() == LicenseStatusUnknown {
+			continue
+		}
+		key := DedupKey(a)
+		conflict, ok := byKey[key]
+		if !ok {
+			conflict = &LicenseConflict{Name: a.QualifiedName(), Version: a.Version, Purl: a.Purl}
+			byKey[key] = conflict
+			keys = append(keys, key)
+		}
+		conflict.add(a.LicenseExpression(), a.Sources)
+	}
+
+	report := LicenseConflictReport{Conflicts: []LicenseConflict{}}
+	for _, key := range keys {
+		if conflict := byKey[key]; len(conflict.Licenses) > 1 {
+			report.Conflicts = append(report.Conflicts, *conflict)
+		}
+	}
+	return report
+}
+
+// add records that the sources claim the license.
+func (c *LicenseConflict) add(license string, sources []string) {
+	for i, claimed := range c.Licenses {
+		if strings.EqualFold(claimed.License, license) {
+			c.Licenses[i].Sources = mergeUnique(claimed.Sources, sources)
+			return
+		}
+	}
+	c.Licenses = append(c.Licenses, ConflictingLicense{License: license, Sources: append([]string{}, sources...)})
+}
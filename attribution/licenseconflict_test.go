@@ -0,0 +1,59 @@
+package attribution_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestReportLicenseConflicts tests that a package found with different licenses is reported with the sources of each
+// license, and that licenses differing only in case and unknown licenses do not conflict.
+func TestReportLicenseConflicts(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "lodash", Version: "4.17.21", Purl: "pkg:npm/lodash@4.17.21", License: strPtr("MIT"),
+			Sources: []string{"syft.json"}},
+		{Name: "lodash", Version: "4.17.21", Purl: "pkg:npm/lodash@4.17.21", License: strPtr("Apache-2.0"),
+			Sources: []string{"trivy.json"}},
+		{Name: "lodash", Version: "4.17.21", Purl: "pkg:npm/lodash@4.17.21", License: strPtr("mit"),
+			Sources: []string{"api.json"}},
+		{Name: "left-pad", License: strPtr("MIT"), Sources: []string{"syft.json"}},
+		{Name: "left-pad", License: strPtr("NOASSERTION"), Sources: []string{"trivy.json"}},
+		{Name: "left-pad", Sources: []string{"api.json"}},
+	}
+
+	got := attribution.ReportLicenseConflicts(input)
+
+	if len(got.Conflicts) != 1 {
+		t.Fatalf("ReportLicenseConflicts() = %+v, want only lodash", got.Conflicts)
+	}
+	lodash := got.Conflicts[0]
+	if lodash.Name != "lodash" || lodash.Version != "4.17.21" || lodash.Purl != "pkg:npm/lodash@4.17.21" ||
+		len(lodash.Licenses) != 2 {
+		t.Fatalf("ReportLicenseConflicts()[0] = %+v, want lodash with two licenses", lodash)
+	}
+	if mit := lodash.Licenses[0]; mit.License != "MIT" ||
+		!slices.Equal(mit.Sources, []string{"syft.json", "api.json"}) {
+		t.Errorf("Licenses[0] = %+v, want MIT from syft.json and api.json", mit)
+	}
+	if apache := lodash.Licenses[1]; apache.License != "Apache-2.0" ||
+		!slices.Equal(apache.Sources, []string{"trivy.json"}) {
+		t.Errorf("Licenses[1] = %+v, want Apache-2.0 from trivy.json", apache)
+	}
+}
+
+// TestReportLicenseConflicts_Empty tests that a report without conflicts has an empty, non-nil conflict list, so it
+// is written as [] in JSON.
+func TestReportLicenseConflicts_Empty(t *testing.T) {
+	t.Parallel()
+
+	got := attribution.ReportLicenseConflicts([]attribution.Attribution{
+		{Name: "lodash", License: strPtr("MIT"), Sources: []string{"syft.json"}},
+		{Name: "lodash", License: strPtr("MIT"), Sources: []string{"trivy.json"}},
+	})
+	if got.Conflicts == nil || len(got.Conflicts) != 0 {
+		t.Errorf("ReportLicenseConflicts() = %#v, want an empty conflict list", got.Conflicts)
+	}
+}
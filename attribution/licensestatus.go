@@ -0,0 +1,42 @@
+package attribution
+
+import (
+	"strings"
+)
+
+// Special SPDX license values, which are not license identifiers.
+const (
+	// LicenseNone states that the package has no license: nobody granted the right to use it.
+	LicenseNone = "NONE"
+	// LicenseNoAssertion states that the SBOM creator makes no assertion about the license, so it is unknown.
+	LicenseNoAssertion = "NOASSERTION"
+)
+
+// LicenseStatus classifies the license of an attribution (see Attribution.LicenseStatus).
+type LicenseStatus string
+
+const (
+	// LicenseStatusKnown means the attribution has a license expression.
+	LicenseStatusKnown LicenseStatus = "known"
+	// LicenseStatusNone means the SBOM explicitly states that the package has no license (SPDX NONE).
+	LicenseStatusNone LicenseStatus = "none"
+	// LicenseStatusUnknown means the license is missing, empty, or NOASSERTION.
+	LicenseStatusUnknown LicenseStatus = "unknown"
+)
+
+// LicenseStatus reports whether the license of the attribution is known, explicitly none, or unknown. The two
+// differ legally: a package without a license grants no rights to use it, while an unknown license still has to be
+// determined.
+func (a Attribution) LicenseStatus() LicenseStatus {
+	if a.License == nil {
+		return LicenseStatusUnknown
+	}
+	switch strings.TrimSpace(*a.License) {
+	case "", LicenseNoAssertion:
+		return LicenseStatusUnknown
+	case LicenseNone:
+		return LicenseStatusNone
+	default:
+		return LicenseStatusKnown
+	}
+}
@@ -0,0 +1,33 @@
+package attribution_test
+
+import (
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestAttribution_LicenseStatus tests the LicenseStatus method.
+func TestAttribution_LicenseStatus(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		license *string
+		want    attribution.LicenseStatus
+	}{
+		{name: "expression", license: strPtr("MIT OR Apache-2.0"), want: attribution.LicenseStatusKnown},
+		{name: "none", license: strPtr("NONE"), want: attribution.LicenseStatusNone},
+		{name: "noassertion", license: strPtr("NOASSERTION"), want: attribution.LicenseStatusUnknown},
+		{name: "empty", license: strPtr(" "), want: attribution.LicenseStatusUnknown},
+		{name: "missing", license: nil, want: attribution.LicenseStatusUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := (attribution.Attribution{License: tt.license}).LicenseStatus(); got != tt.want {
+				t.Errorf("LicenseStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
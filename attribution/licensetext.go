@@ -0,0 +1,189 @@
+package attribution
+
+import (
+	"embed"
+	"io/fs"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// licenseTexts holds the texts of the most common SPDX licenses, one "<id>.txt" file per license. License versions
+// with "-only" and "-or-later" variants are stored once under the base identifier (e.g. "GPL-2.0.txt").
+// See https://github.com/spdx/license-list-data
+//
+//go:embed data/licenses/*.txt
+var licenseTexts embed.FS
+
+// registeredLicenseTexts holds the license texts added with RegisterLicenseText, and their word pairs (see
+// wordPairs), by license identifier.
+//
+//nolint:gochecknoglobals // RegisterLicenseText must be callable from any package, like RegisterCanonicalizer.
+var registeredLicenseTexts = struct {
+	sync.RWMutex
+	texts map[string]string
+	pairs map[string]map[string]bool
+}{
+	texts: make(map[string]string),
+	pairs: make(map[string]map[string]bool),
+}
+
+// RegisterLicenseText adds the text of a license that is not bundled, e.g. one downloaded from the SPDX license list
+// (see licensefetch.Fetcher.LoadLicenseTexts), so LicenseText returns it and IdentifyLicense recognizes it. A
+// registered text replaces the bundled one, if any. Like bundled texts, the "-only", "-or-later", and "+" variants of
+// the license share it.
+// It panics if the identifier or the text is empty.
+func RegisterLicenseText(id, text string) {
+	if strings.TrimSpace(id) == "" {
+		panic("attribution: RegisterLicenseText identifier is empty")
+	}
+	if strings.TrimSpace(text) == "" {
+		panic("attribution: RegisterLicenseText text is empty")
+	}
+
+	id = baseLicenseID(id)
+	pairs := wordPairs(text)
+	registeredLicenseTexts.Lock()
+	defer registeredLicenseTexts.Unlock()
+	for registered := range registeredLicenseTexts.texts {
+		if strings.EqualFold(registered, id) {
+			delete(registeredLicenseTexts.texts, registered)
+			delete(registeredLicenseTexts.pairs, registered)
+		}
+	}
+	registeredLicenseTexts.texts[id] = text
+	registeredLicenseTexts.pairs[id] = pairs
+}
+
+// LicenseText returns the full text of a standard SPDX license, e.g. "MIT" or "GPL-2.0-or-later", so notices can
+// reproduce the license without network access. Copyright lines keep their placeholders (e.g. "<copyright holders>").
+// Matching is case-insensitive, deprecated identifiers are resolved (see CurrentLicenseID), and the "-only",
+// "-or-later", and "+" variants of a license share its text.
+// Returns ok as false for compound expressions and for licenses whose text is neither bundled nor registered; only
+// the most common licenses are bundled (see LicenseTextIDs and RegisterLicenseText).
+func LicenseText(id string) (string, bool) {
+	id = baseLicenseID(id)
+
+	registeredLicenseTexts.RLock()
+	for registered, text := range registeredLicenseTexts.texts {
+		if strings.EqualFold(registered, id) {
+			registeredLicenseTexts.RUnlock()
+			return text, true
+		}
+	}
+	registeredLicenseTexts.RUnlock()
+
+	for _, bundled := range bundledLicenseTextIDs() {
+		if strings.EqualFold(bundled, id) {
+			data, err := licenseTexts.ReadFile("data/licenses/" + bundled + ".txt")
+			return string(data), err == nil
+		}
+	}
+	return "", false
+}
+
+// LicenseTextIDs returns the identifiers of the licenses whose text is bundled or registered (see
+// RegisterLicenseText), sorted.
+func LicenseTextIDs() []string {
+	ids := bundledLicenseTextIDs()
+	registeredLicenseTexts.RLock()
+	for id := range registeredLicenseTexts.texts {
+		if !slices.ContainsFunc(ids, func(bundled string) bool { return strings.EqualFold(bundled, id) }) {
+			ids = append(ids, id)
+		}
+	}
+	registeredLicenseTexts.RUnlock()
+	slices.Sort(ids)
+	return ids
+}
+
+// bundledLicenseTextIDs returns the identifiers of the licenses whose text is bundled, sorted.
+func bundledLicenseTextIDs() []string {
+	// The embedded directory always exists
+	entries, _ := fs.ReadDir(licenseTexts, "data/licenses")
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, strings.TrimSuffix(entry.Name(), ".txt"))
+	}
+	return ids
+}
+
+// minLicenseTextSimilarity is the similarity (see textSimilarity) a text must have with a bundled license text to be
+// identified as that license. It tolerates filled-in copyright lines and omitted appendices, but tells similar
+// licenses apart (e.g. BSD-2-Clause and BSD-3-Clause).
+const minLicenseTextSimilarity = 0.9
+
+// IdentifyLicense identifies the license of a license file, such as a LICENSE file of a repository, by comparing its
+// words with the bundled and registered license texts (see LicenseTextIDs). Returns the current SPDX identifier of
+// the most similar license, e.g. "MIT" or "GPL-2.0-only" (a license file does not tell whether later versions may be
+// used).
+// Returns ok as false if the text is not similar enough to any known license text, e.g. for files that combine
+// several licenses.
+func IdentifyLicense(text string) (string, bool) {
+	words := wordPairs(text)
+	if len(words) == 0 {
+		return "", false
+	}
+
+	best, bestSimilarity := "", 0.0
+	for id, bundled := range bundledWordPairs() {
+		if similarity := textSimilarity(words, bundled); similarity > bestSimilarity {
+			best, bestSimilarity = id, similarity
+		}
+	}
+	registeredLicenseTexts.RLock()
+	for id, registered := range registeredLicenseTexts.pairs {
+		if similarity := textSimilarity(words, registered); similarity > bestSimilarity {
+			best, bestSimilarity = id, similarity
+		}
+	}
+	registeredLicenseTexts.RUnlock()
+	if bestSimilarity < minLicenseTextSimilarity {
+		return "", false
+	}
+
+	if current, ok := CurrentLicenseID(best); ok {
+		return current, true
+	}
+	return best, true
+}
+
+// bundledWordPairs returns the word pairs (see wordPairs) of every bundled license text, by license identifier.
+//
+//nolint:gochecknoglobals // Computed once, since IdentifyLicense may be called for thousands of packages.
+var bundledWordPairs = sync.OnceValue(func() map[string]map[string]bool {
+	pairs := make(map[string]map[string]bool)
+	for _, id := range bundledLicenseTextIDs() {
+		// Bundled texts always exist
+		data, _ := licenseTexts.ReadFile("data/licenses/" + id + ".txt")
+		pairs[id] = wordPairs(string(data))
+	}
+	return pairs
+})
+
+// wordPairs returns the set of pairs of consecutive words of a text, lowercased and without punctuation.
+func wordPairs(text string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	pairs := make(map[string]bool, len(words))
+	for i := 1; i < len(words); i++ {
+		pairs[words[i-1]+" "+words[i]] = true
+	}
+	return pairs
+}
+
+// textSimilarity returns the Dice coefficient of two sets of word pairs: 1 if they are equal, 0 if they share none.
+func textSimilarity(a, b map[string]bool) float64 {
+	if len(a)+len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for pair := range a {
+		if b[pair] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b))
+}
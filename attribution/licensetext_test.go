@@ -0,0 +1,126 @@
+package attribution_test
+
+import (
+	"crypto/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestLicenseText tests that bundled license texts are found by their identifier and its variants.
+func TestLicenseText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		id     string
+		prefix string
+		wantOK bool
+	}{
+		{id: "MIT", prefix: "MIT License", wantOK: true},
+		{id: " apache-2.0 ", prefix: "Apache License", wantOK: true},
+		{id: "GPL-2.0-or-later", prefix: "GNU GENERAL PUBLIC LICENSE", wantOK: true},
+		{id: "LGPL-2.1+", prefix: "GNU LESSER GENERAL PUBLIC LICENSE", wantOK: true},
+		{id: "GPL-3.0", prefix: "GNU GENERAL PUBLIC LICENSE", wantOK: true},
+		{id: "LGPL-2.0-or-later", prefix: "GNU LIBRARY GENERAL PUBLIC LICENSE", wantOK: true},
+		{id: "MPL-1.1", prefix: "MOZILLA PUBLIC LICENSE", wantOK: true},
+		{id: "MIT OR Apache-2.0"},
+		{id: "LicenseRef-Acme"},
+		{id: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			t.Parallel()
+			got, ok := attribution.LicenseText(tt.id)
+			if ok != tt.wantOK || !strings.HasPrefix(strings.TrimSpace(got), tt.prefix) {
+				t.Errorf("LicenseText(%q) = %.40q..., %v, want prefix %q, %v", tt.id, got, ok, tt.prefix, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestLicenseTextIDs tests that every bundled license text can be looked up.
+func TestLicenseTextIDs(t *testing.T) {
+	t.Parallel()
+
+	ids := attribution.LicenseTextIDs()
+	if len(ids) == 0 {
+		t.Fatal("LicenseTextIDs() is empty")
+	}
+	for _, id := range ids {
+		if text, ok := attribution.LicenseText(id); !ok || text == "" {
+			t.Errorf("LicenseText(%q) = %q, %v, want the bundled text", id, text, ok)
+		}
+	}
+}
+
+// TestIdentifyLicense tests that license files are identified by their similarity with the bundled license texts.
+func TestIdentifyLicense(t *testing.T) {
+	t.Parallel()
+
+	mit, _ := attribution.LicenseText("MIT")
+	bsd3, _ := attribution.LicenseText("BSD-3-Clause")
+	gpl2, _ := attribution.LicenseText("GPL-2.0")
+	lgpl2, _ := attribution.LicenseText("LGPL-2.0")
+	apache, _ := attribution.LicenseText("Apache-2.0")
+	apacheWithoutAppendix, _, _ := strings.Cut(apache, "APPENDIX")
+
+	tests := []struct {
+		name   string
+		text   string
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "MIT with copyright",
+			text:   strings.Replace(mit, "<year> <copyright holders>", "2014 Steve Francia", 1),
+			want:   "MIT",
+			wantOK: true,
+		},
+		{
+			name:   "reflowed BSD-3-Clause",
+			text:   strings.Join(strings.Fields(bsd3), " "),
+			want:   "BSD-3-Clause",
+			wantOK: true,
+		},
+		{name: "deprecated identifier", text: gpl2, want: "GPL-2.0-only", wantOK: true},
+		{name: "LGPL-2.0, not LGPL-2.1", text: lgpl2, want: "LGPL-2.0-only", wantOK: true},
+		{name: "Apache without appendix", text: apacheWithoutAppendix, want: "Apache-2.0", wantOK: true},
+		{name: "several licenses", text: mit + "\n" + bsd3},
+		{name: "custom", text: "All rights reserved. Do not copy."},
+		{name: "empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := attribution.IdentifyLicense(tt.text)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("IdentifyLicense() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestRegisterLicenseText tests that registered license texts are looked up and identified like bundled ones.
+func TestRegisterLicenseText(t *testing.T) {
+	t.Parallel()
+
+	// The registry is global, so the identifier is unique per run for the test to pass with -count
+	id := "LicenseRef-Test-" + rand.Text()
+	text := "The " + id + " license. Permission is granted to use this test fixture for any purpose, provided " +
+		"that this notice and the name of the license are kept in every copy of the fixture."
+	attribution.RegisterLicenseText(id, text)
+
+	if got, ok := attribution.LicenseText(strings.ToLower(id) + "-or-later"); !ok || got != text {
+		t.Errorf("LicenseText() = %q, %v, want the registered text", got, ok)
+	}
+	if !slices.Contains(attribution.LicenseTextIDs(), id) {
+		t.Errorf("LicenseTextIDs() = %v, want it to contain %q", attribution.LicenseTextIDs(), id)
+	}
+	if got, ok := attribution.IdentifyLicense("Copyright 2026 Acme\n\n" + text); got != id || !ok {
+		t.Errorf("IdentifyLicense() = %q, %v, want %q, true", got, ok, id)
+	}
+}
@@ -0,0 +1,68 @@
+package attribution
+
+import (
+	"strings"
+)
+
+// PartyKind is the kind of party credited for a package.
+type PartyKind string
+
+const (
+	// PartyPerson is an individual.
+	PartyPerson PartyKind = "person"
+	// PartyOrganization is a company, foundation, or other organization.
+	PartyOrganization PartyKind = "organization"
+)
+
+// Party is a person or organization credited for a package, such as its supplier or originator.
+type Party struct {
+	// Kind is whether the party is a person or an organization, or empty if the SBOM does not say
+	Kind PartyKind `json:"kind,omitempty"`
+	// Name is the name of the party
+	Name string `json:"name"`
+	// Email is the contact email of the party, if the SBOM records one
+	Email string `json:"email,omitempty"`
+}
+
+// ParseSPDXParty parses an SPDX supplier or originator ("Person: <name> (<email>)" or "Organization: <name>
+// (<email>)", the email being optional). A value without an entity type is taken as the name of a party of unknown
+// kind. Returns ok as false for empty values and NOASSERTION.
+func ParseSPDXParty(s string) (Party, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == LicenseNoAssertion {
+		return Party{}, false
+	}
+
+	var party Party
+	if kind, rest, found := strings.Cut(s, ":"); found {
+		switch strings.ToLower(strings.TrimSpace(kind)) {
+		case "person":
+			party.Kind, s = PartyPerson, rest
+		case "organization":
+			party.Kind, s = PartyOrganization, rest
+		}
+	}
+
+	s = strings.TrimSpace(s)
+	if open := strings.LastIndex(s, "("); open >= 0 && strings.HasSuffix(s, ")") {
+		party.Email = strings.TrimSpace(s[open+1 : len(s)-1])
+		s = s[:open]
+	}
+	party.Name = strings.TrimSpace(s)
+	if party.Name == "" {
+		return Party{}, false
+	}
+	return party, true
+}
+
+// SPDX formats the party as an SPDX supplier or originator. A party of unknown kind is written as an organization.
+func (p Party) SPDX() string {
+	kind := "Organization"
+	if p.Kind == PartyPerson {
+		kind = "Person"
+	}
+	if p.Email == "" {
+		return kind + ": " + p.Name
+	}
+	return kind + ": " + p.Name + " (" + p.Email + ")"
+}
@@ -0,0 +1,80 @@
+package attribution_test
+
+import (
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestParseSPDXParty tests the ParseSPDXParty function.
+func TestParseSPDXParty(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		input  string
+		want   attribution.Party
+		wantOK bool
+	}{
+		{
+			name:   "organization",
+			input:  "Organization: Acme Corp",
+			want:   attribution.Party{Kind: attribution.PartyOrganization, Name: "Acme Corp"},
+			wantOK: true,
+		},
+		{
+			name:   "person with email",
+			input:  " Person: Jane Doe (jane@example.com) ",
+			want:   attribution.Party{Kind: attribution.PartyPerson, Name: "Jane Doe", Email: "jane@example.com"},
+			wantOK: true,
+		},
+		{
+			name:   "parentheses in name",
+			input:  "Organization: Acme (Europe) GmbH ()",
+			want:   attribution.Party{Kind: attribution.PartyOrganization, Name: "Acme (Europe) GmbH"},
+			wantOK: true,
+		},
+		{
+			name:   "no entity type",
+			input:  "Acme: The Company",
+			want:   attribution.Party{Name: "Acme: The Company"},
+			wantOK: true,
+		},
+		{name: "noassertion", input: "NOASSERTION"},
+		{name: "empty", input: " "},
+		{name: "type only", input: "Person: "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := attribution.ParseSPDXParty(tt.input)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ParseSPDXParty(%q) = %+v, %v, want %+v, %v", tt.input, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestParty_SPDX tests that parties are formatted as SPDX suppliers and originators.
+func TestParty_SPDX(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		party attribution.Party
+		want  string
+	}{
+		{
+			party: attribution.Party{Kind: attribution.PartyPerson, Name: "Jane Doe", Email: "jane@example.com"},
+			want:  "Person: Jane Doe (jane@example.com)",
+		},
+		{party: attribution.Party{Kind: attribution.PartyOrganization, Name: "Acme"}, want: "Organization: Acme"},
+		{party: attribution.Party{Name: "Acme"}, want: "Organization: Acme"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.party.SPDX(); got != tt.want {
+			t.Errorf("SPDX() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package attribution
+
+import "maps"
+
+// Provenance describes which stage produced the value of an attribution field.
+type Provenance string
+
+// Provenance values recorded by sbomattr.
+const (
+	// ProvenanceExtracted means the value was read from the SBOM.
+	ProvenanceExtracted Provenance = "extracted"
+	// ProvenanceGenerated means the value was derived from other fields, e.g. a URL generated from the purl.
+	ProvenanceGenerated Provenance = "generated"
+	// ProvenanceHeuristic means the value was inferred by a heuristic, e.g. the curated known-license dataset.
+	ProvenanceHeuristic Provenance = "heuristic"
+	// ProvenanceLockfile means the value was read from a package manager lockfile instead of an SBOM, so it is a
+	// first-pass approximation (e.g. it may include development dependencies).
+	ProvenanceLockfile Provenance = "lockfile"
+	// ProvenanceOverridden means the value was overridden by the user, e.g. from a corrections file.
+	ProvenanceOverridden Provenance = "overridden-by-user"
+)
+
+// Field names used as Provenance keys.
+const (
+	// FieldLicense is the License field.
+	FieldLicense = "license"
+	// FieldURL is the URL field.
+	FieldURL = "url"
+	// FieldPurl is the Purl field (and the name and version it identifies).
+	FieldPurl = "purl"
+	// FieldLicenseText is the LicenseText field.
+	FieldLicenseText = "licenseText"
+)
+
+// ProvenanceEnriched returns the provenance for a value enriched from an external source, e.g. "enriched-from-npm".
+func ProvenanceEnriched(source string) Provenance {
+	return Provenance("enriched-from-" + source)
+}
+
+// WithProvenance returns a copy of the attribution with the provenance of field set to p.
+// The provenance map is copied, so the original attribution is never modified.
+func (a Attribution) WithProvenance(field string, p Provenance) Attribution {
+	a.Provenance = maps.Clone(a.Provenance)
+	if a.Provenance == nil {
+		a.Provenance = make(map[string]Provenance)
+	}
+	a.Provenance[field] = p
+	return a
+}
@@ -0,0 +1,27 @@
+package attribution_test
+
+import (
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestWithProvenance tests the WithProvenance method.
+func TestWithProvenance(t *testing.T) {
+	t.Parallel()
+
+	original := attribution.Attribution{Name: "pkg"}.
+		WithProvenance(attribution.FieldLicense, attribution.ProvenanceExtracted)
+
+	updated := original.WithProvenance(attribution.FieldURL, attribution.ProvenanceEnriched("npm"))
+
+	if got := updated.Provenance[attribution.FieldLicense]; got != attribution.ProvenanceExtracted {
+		t.Errorf("Provenance[license] = %q, want %q", got, attribution.ProvenanceExtracted)
+	}
+	if got := updated.Provenance[attribution.FieldURL]; got != "enriched-from-npm" {
+		t.Errorf("Provenance[url] = %q, want %q", got, "enriched-from-npm")
+	}
+	if _, ok := original.Provenance[attribution.FieldURL]; ok {
+		t.Error("WithProvenance() modified the original attribution's provenance")
+	}
+}
@@ -0,0 +1,56 @@
+package attribution
+
+import (
+	"github.com/package-url/packageurl-go"
+)
+
+// PurlParts are the components of a parsed purl, for filtering on them (e.g. the distro of an OS package) and
+// building URLs from them.
+type PurlParts struct {
+	// Type is the package type, e.g. "npm" or "deb"
+	Type string
+	// Namespace is the name prefix, e.g. a Maven groupId, an npm scope, or a Linux distribution vendor
+	Namespace string
+	// Name is the package name
+	Name string
+	// Version is the package version
+	Version string
+	// Qualifiers are the extra qualifying data, e.g. "arch", "distro", or "repository_url", or nil if there are none
+	Qualifiers map[string]string
+	// Subpath is the path of a sub-package within the package, e.g. "src/lib"
+	Subpath string
+}
+
+// ParsePurl returns the components of a purl, with percent-encoded values decoded.
+// Returns ok as false if the purl is empty or invalid.
+func ParsePurl(purl string) (PurlParts, bool) {
+	if purl == "" {
+		return PurlParts{}, false
+	}
+	parsed, err := packageurl.FromString(purl)
+	if err != nil {
+		return PurlParts{}, false
+	}
+	return purlPartsOf(parsed), true
+}
+
+// purlPartsOf returns the components of a parsed purl.
+func purlPartsOf(purl packageurl.PackageURL) PurlParts {
+	parts := PurlParts{
+		Type:      purl.Type,
+		Namespace: purl.Namespace,
+		Name:      purl.Name,
+		Version:   purl.Version,
+		Subpath:   purl.Subpath,
+	}
+	if len(purl.Qualifiers) > 0 {
+		parts.Qualifiers = purl.Qualifiers.Map()
+	}
+	return parts
+}
+
+// PurlParts returns the components of the purl of the attribution (see ParsePurl).
+// Returns ok as false if the attribution has no valid purl.
+func (a Attribution) PurlParts() (PurlParts, bool) {
+	return ParsePurl(a.Purl)
+}
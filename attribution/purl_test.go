@@ -0,0 +1,70 @@
+package attribution_test
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestParsePurl tests the ParsePurl function.
+func TestParsePurl(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		purl   string
+		want   attribution.PurlParts
+		wantOK bool
+	}{
+		{
+			name: "qualifiers",
+			purl: "pkg:deb/debian/curl@7.50.3-1?arch=i386&distro=jessie",
+			want: attribution.PurlParts{
+				Type:       "deb",
+				Namespace:  "debian",
+				Name:       "curl",
+				Version:    "7.50.3-1",
+				Qualifiers: map[string]string{"arch": "i386", "distro": "jessie"},
+			},
+			wantOK: true,
+		},
+		{
+			name: "encoded qualifier and subpath",
+			purl: "pkg:golang/google.golang.org/genproto@abcdedf?repository_url=https%3A%2F%2Fgo.example" +
+				"#googleapis/api",
+			want: attribution.PurlParts{
+				Type:       "golang",
+				Namespace:  "google.golang.org",
+				Name:       "genproto",
+				Version:    "abcdedf",
+				Qualifiers: map[string]string{"repository_url": "https://go.example"},
+				Subpath:    "googleapis/api",
+			},
+			wantOK: true,
+		},
+		{
+			name:   "scoped npm",
+			purl:   "pkg:npm/%40angular/core@17.0.0",
+			want:   attribution.PurlParts{Type: "npm", Namespace: "@angular", Name: "core", Version: "17.0.0"},
+			wantOK: true,
+		},
+		{name: "empty", purl: ""},
+		{name: "invalid", purl: "not-a-purl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := attribution.Attribution{Purl: tt.purl}.PurlParts()
+			if ok != tt.wantOK || got.Type != tt.want.Type || got.Namespace != tt.want.Namespace ||
+				got.Name != tt.want.Name || got.Version != tt.want.Version || got.Subpath != tt.want.Subpath ||
+				!maps.Equal(got.Qualifiers, tt.want.Qualifiers) {
+				t.Errorf("PurlParts() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+			if tt.wantOK && len(tt.want.Qualifiers) == 0 && got.Qualifiers != nil {
+				t.Errorf("PurlParts().Qualifiers = %v, want nil", got.Qualifiers)
+			}
+		})
+	}
+}
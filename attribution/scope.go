@@ -0,0 +1,49 @@
+package attribution
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// Component scopes, as defined by CycloneDX. A component without a scope is required.
+const (
+	// ScopeRequired means the component is needed at runtime.
+	ScopeRequired = "required"
+	// ScopeOptional means the component is not needed at runtime, e.g. an optional plugin.
+	ScopeOptional = "optional"
+	// ScopeExcluded means the component is not part of the delivered product, e.g. a development-only tool.
+	ScopeExcluded = "excluded"
+)
+
+// ExcludeScopes returns a copy of attributions without the components whose scope is one of scopes (compared
+// case-insensitively), e.g. ScopeExcluded and ScopeOptional, so development-only tools stay out of notices.
+// Components without a scope are required, so they are only excluded by ScopeRequired.
+// The logger parameter is optional; pass nil to disable logging.
+func ExcludeScopes(attributions []Attribution, scopes []string, logger *slog.Logger) []Attribution {
+	return Filter(attributions, func(a Attribution) bool {
+		scope := strings.ToLower(a.Scope)
+		if scope == "" {
+			scope = ScopeRequired
+		}
+		if slices.ContainsFunc(scopes, func(s string) bool { return strings.EqualFold(strings.TrimSpace(s), scope) }) {
+			if logger != nil {
+				logger.Debug("excluding component by scope", "name", a.QualifiedName(), "scope", scope)
+			}
+			return false
+		}
+		return true
+	})
+}
+
+// widestScope returns the scope of a component listed with both scopes, so a component required anywhere stays
+// required: required (or no scope) wins over optional, which wins over excluded.
+func widestScope(a, b string) string {
+	rank := func(scope string) int {
+		return max(slices.Index([]string{ScopeRequired, ScopeOptional, ScopeExcluded}, strings.ToLower(scope)), 0)
+	}
+	if rank(b) < rank(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,67 @@
+package attribution_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestExcludeScopes tests that components are skipped by scope, treating components without a scope as required.
+func TestExcludeScopes(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "react", Scope: "required"},
+		{Name: "eslint", Scope: "excluded"},
+		{Name: "fsevents", Scope: "Optional"},
+		{Name: "lodash"},
+	}
+
+	tests := []struct {
+		name   string
+		scopes []string
+		want   []string
+	}{
+		{name: "none", scopes: nil, want: []string{"react", "eslint", "fsevents", "lodash"}},
+		{name: "excluded", scopes: []string{"excluded"}, want: []string{"react", "fsevents", "lodash"}},
+		{name: "excluded and optional", scopes: []string{"EXCLUDED", " optional"}, want: []string{"react", "lodash"}},
+		{name: "required", scopes: []string{"required"}, want: []string{"eslint", "fsevents"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			for _, a := range attribution.ExcludeScopes(input, tt.scopes, nil) {
+				got = append(got, a.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ExcludeScopes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDeduplicate_WidensScope tests that a component required by any SBOM stays required after deduplication.
+func TestDeduplicate_WidensScope(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "a", Scope: "excluded"},
+		{Name: "a", Scope: "optional"},
+		{Name: "b", Scope: "optional"},
+		{Name: "b"},
+		{Name: "c", Scope: "required"},
+		{Name: "c", Scope: "excluded"},
+	}
+
+	got := attribution.Deduplicate(input, nil)
+
+	for i, want := range []string{"optional", "", "required"} {
+		if got[i].Scope != want {
+			t.Errorf("Deduplicate()[%d].Scope = %q, want %q", i, got[i].Scope, want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package attribution
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SortKey selects the field attributions are sorted by.
+type SortKey string
+
+const (
+	// SortByName sorts attributions by name.
+	SortByName SortKey = "name"
+	// SortByLicense sorts attributions by license.
+	SortByLicense SortKey = "license"
+	// SortByPurl sorts attributions by purl.
+	SortByPurl SortKey = "purl"
+)
+
+// SortKeys returns the supported sort keys.
+func SortKeys() []SortKey {
+	return []SortKey{SortByName, SortByLicense, SortByPurl}
+}
+
+// ParseSortKey parses a sort key name, ignoring case.
+// Returns an error for unknown sort keys.
+func ParseSortKey(s string) (SortKey, error) {
+	for _, key := range SortKeys() {
+		if strings.EqualFold(string(key), strings.TrimSpace(s)) {
+			return key, nil
+		}
+	}
+	return "", fmt.Errorf("unknown sort key %q (valid keys: name, license, purl)", s)
+}
+
+// SortOptions configures Sort.
+type SortOptions struct {
+	// Key is the field to sort by.
+	Key SortKey
+	// IgnoreCase compares values case-insensitively.
+	IgnoreCase bool
+}
+
+// Sort returns a copy of attributions sorted by the selected key, so output does not depend on SBOM file order.
+// Ties are broken by name (qualified by group), then purl, then version, to keep the order deterministic.
+// Missing licenses sort first.
+func Sort(attributions []Attribution, opts SortOptions) []Attribution {
+	result := slices.Clone(attributions)
+
+	compare := func(a, b string) int {
+		if opts.IgnoreCase {
+			if c := cmp.Compare(strings.ToLower(a), strings.ToLower(b)); c != 0 {
+				return c
+			}
+		}
+		return cmp.Compare(a, b)
+	}
+
+	slices.SortStableFunc(result, func(a, b Attribution) int {
+		var c int
+		switch opts.Key {
+		case SortByLicense:
+			c = compare(derefString(a.License), derefString(b.License))
+		case SortByPurl:
+			c = compare(a.Purl, b.Purl)
+		case SortByName:
+		}
+		return cmp.Or(
+			c,
+			compare(a.QualifiedName(), b.QualifiedName()),
+			compare(a.Purl, b.Purl),
+			compare(a.Version, b.Version),
+		)
+	})
+
+	return result
+}
+
+// derefString returns the value of s, or an empty string if s is nil.
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
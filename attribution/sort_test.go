@@ -0,0 +1,87 @@
+package attribution_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestSort tests the Sort function.
+func TestSort(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "zlib", License: strPtr("Zlib"), Purl: "pkg:generic/zlib@1.3"},
+		{Name: "Babel", License: strPtr("MIT"), Purl: "pkg:npm/%40babel/core@7.22.5"},
+		{Name: "apache-commons", License: nil, Purl: "pkg:maven/org.apache.commons/commons-lang3@3.12.0"},
+		{Name: "left-pad", License: strPtr("mit"), Purl: "pkg:npm/left-pad@1.3.0"},
+	}
+
+	tests := []struct {
+		name string
+		opts attribution.SortOptions
+		want []string
+	}{
+		{
+			name: "by name",
+			opts: attribution.SortOptions{Key: attribution.SortByName},
+			want: []string{"Babel", "apache-commons", "left-pad", "zlib"},
+		},
+		{
+			name: "by name ignoring case",
+			opts: attribution.SortOptions{Key: attribution.SortByName, IgnoreCase: true},
+			want: []string{"apache-commons", "Babel", "left-pad", "zlib"},
+		},
+		{
+			name: "by license",
+			opts: attribution.SortOptions{Key: attribution.SortByLicense},
+			want: []string{"apache-commons", "Babel", "zlib", "left-pad"},
+		},
+		{
+			name: "by license ignoring case",
+			opts: attribution.SortOptions{Key: attribution.SortByLicense, IgnoreCase: true},
+			want: []string{"apache-commons", "Babel", "left-pad", "zlib"},
+		},
+		{
+			name: "by purl",
+			opts: attribution.SortOptions{Key: attribution.SortByPurl},
+			want: []string{"zlib", "apache-commons", "Babel", "left-pad"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := attribution.Sort(input, tt.opts)
+
+			var got []string
+			for _, a := range result {
+				got = append(got, a.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Sort() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// The input must not be modified
+	if input[0].Name != "zlib" {
+		t.Errorf("Sort() modified its input")
+	}
+}
+
+// TestParseSortKey tests the ParseSortKey function.
+func TestParseSortKey(t *testing.T) {
+	t.Parallel()
+
+	key, err := attribution.ParseSortKey("License")
+	if err != nil || key != attribution.SortByLicense {
+		t.Errorf("ParseSortKey(License) = %q, %v, want %q, nil", key, err, attribution.SortByLicense)
+	}
+
+	if _, err = attribution.ParseSortKey("size"); err == nil {
+		t.Error("ParseSortKey(size) error = nil, want error")
+	}
+}
@@ -0,0 +1,41 @@
+package attribution
+
+// UnknownLicenseReport lists the packages whose license could not be determined, so compliance gaps are visible
+// instead of buried in blank cells.
+type UnknownLicenseReport struct {
+	// Packages lists the packages with an unknown license, in input order.
+	Packages []UnknownLicense `json:"packages"`
+}
+
+// UnknownLicense is a package whose license could not be determined.
+type UnknownLicense struct {
+	// Name is the package name, qualified by its group (see Attribution.QualifiedName).
+	Name string `json:"name"`
+	// Version is the package version.
+	Version string `json:"version,omitempty"`
+	// Purl is the package URL.
+	Purl string `json:"purl,omitempty"`
+	// License is the license value found in the SBOM, e.g. NOASSERTION. Empty if the license is missing.
+	License string `json:"license,omitempty"`
+	// Sources lists the SBOM files the package was found in, so the gap can be fixed at its origin.
+	Sources []string `json:"sources"`
+}
+
+// ReportUnknownLicenses returns the attributions whose license is unknown (see LicenseStatusUnknown): missing, empty,
+// or NOASSERTION. Packages explicitly without a license (NONE) are not listed, since their license is known.
+func ReportUnknownLicenses(attributions []Attribution) UnknownLicenseReport {
+	report := UnknownLicenseReport{Packages: []UnknownLicense{}}
+	for _, a := range attributions {
+		if a.LicenseStatus() != LicenseStatusUnknown {
+			continue
+		}
+		report.Packages = append(report.Packages, UnknownLicense{
+			Name:    a.QualifiedName(),
+			Version: a.Version,
+			Purl:    a.Purl,
+			License: derefString(a.License),
+			Sources: append([]string{}, a.Sources...),
+		})
+	}
+	return report
+}
@@ -0,0 +1,48 @@
+package attribution_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestReportUnknownLicenses tests that missing, empty, and NOASSERTION licenses are reported with their sources, and
+// known and NONE licenses are not.
+func TestReportUnknownLicenses(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "lodash", License: strPtr("MIT"), Sources: []string{"web.json"}},
+		{Name: "left-pad", Version: "1.3.0", Purl: "pkg:npm/left-pad@1.3.0", Sources: []string{"web.json", "api.json"}},
+		{Name: "internal", License: strPtr("NONE"), Sources: []string{"api.json"}},
+		{Group: "com.acme", Name: "util", License: strPtr("NOASSERTION"), Sources: []string{"api.json"}},
+	}
+
+	got := attribution.ReportUnknownLicenses(input)
+
+	if len(got.Packages) != 2 {
+		t.Fatalf("ReportUnknownLicenses() = %+v, want left-pad and util", got.Packages)
+	}
+	leftPad := got.Packages[0]
+	if leftPad.Name != "left-pad" || leftPad.Version != "1.3.0" || leftPad.Purl != "pkg:npm/left-pad@1.3.0" ||
+		leftPad.License != "" || !slices.Equal(leftPad.Sources, []string{"web.json", "api.json"}) {
+		t.Errorf("ReportUnknownLicenses()[0] = %+v, want left-pad from web.json and api.json", leftPad)
+	}
+	util := got.Packages[1]
+	if util.Name != "com.acme/util" || util.License != "NOASSERTION" ||
+		!slices.Equal(util.Sources, []string{"api.json"}) {
+		t.Errorf("ReportUnknownLicenses()[1] = %+v, want com.acme/util with NOASSERTION from api.json", util)
+	}
+}
+
+// TestReportUnknownLicenses_Empty tests that a report without unknown licenses has an empty, non-nil package list,
+// so it is written as [] in JSON.
+func TestReportUnknownLicenses_Empty(t *testing.T) {
+	t.Parallel()
+
+	got := attribution.ReportUnknownLicenses([]attribution.Attribution{{Name: "lodash", License: strPtr("MIT")}})
+	if got.Packages == nil || len(got.Packages) != 0 {
+		t.Errorf("ReportUnknownLicenses() = %#v, want an empty package list", got.Packages)
+	}
+}
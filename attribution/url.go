@@ -1,10 +1,14 @@
 package attribution
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/url"
+	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/package-url/packageurl-go"
 )
@@ -19,7 +23,8 @@ var (
 
 // PurlToURL constructs a package management URL from a purl string.
 // Returns ErrEmptyPurl if the purl string is empty or whitespace-only.
-// Returns ErrUnsupportedPurlType if the purl type is not supported for URL generation.
+// Purls of other types (e.g. generic) link to their download_url or vcs_url qualifier, if they have a usable one.
+// Returns ErrUnsupportedPurlType if the purl type is not supported for URL generation and has neither qualifier.
 // Returns other errors if the purl string is malformed.
 // The logger parameter is optional; pass nil to disable logging.
 func PurlToURL(purlString string, logger *slog.Logger) (*string, error) {
@@ -32,11 +37,47 @@ func PurlToURL(purlString string, logger *slog.Logger) (*string, error) {
 		return nil, fmt.Errorf("parse purl: %w", err)
 	}
 
-	return mapPurlToURL(purl, logger)
+	// Build the URL from the canonical spelling, so every spelling of a package links to the same page
+	return mapPurlToURL(canonicalPackageURL(purl), logger)
+}
+
+// URLBuilder constructs the URL of a package from the components of its purl.
+// It returns an error (e.g. ErrUnsupportedPurlType) if it cannot build one.
+type URLBuilder func(purl PurlParts) (*string, error)
+
+// urlBuilders holds the URL builders registered with RegisterURLBuilder, by purl type.
+//
+//nolint:gochecknoglobals // RegisterURLBuilder must be callable from any package, like format.Register.
+var urlBuilders = struct {
+	sync.RWMutex
+	byType map[string]URLBuilder
+}{byType: make(map[string]URLBuilder)}
+
+// RegisterURLBuilder sets the URL builder of a purl type (e.g. "acme-internal"), taking precedence over the built-in
+// one if any, so programs embedding sbomattr can support proprietary or internal purl types in PurlToURL.
+// It panics if the purl type is empty or b is nil.
+func RegisterURLBuilder(purlType string, b URLBuilder) {
+	if purlType == "" {
+		panic("attribution: RegisterURLBuilder purl type is empty")
+	}
+	if b == nil {
+		panic("attribution: RegisterURLBuilder builder is nil")
+	}
+
+	urlBuilders.Lock()
+	defer urlBuilders.Unlock()
+	urlBuilders.byType[strings.ToLower(purlType)] = b
 }
 
 // mapPurlToURL maps a purl to a package management URL.
 func mapPurlToURL(purl packageurl.PackageURL, logger *slog.Logger) (*string, error) {
+	urlBuilders.RLock()
+	build, ok := urlBuilders.byType[strings.ToLower(purl.Type)]
+	urlBuilders.RUnlock()
+	if ok {
+		return build(purlPartsOf(purl))
+	}
+
 	// See https://github.com/package-url/purl-spec#known-purl-types
 	switch purl.Type {
 	case "cargo":
@@ -75,14 +116,40 @@ func mapPurlToURL(purl packageurl.PackageURL, logger *slog.Logger) (*string, err
 		return buildCondaURL(purl), nil
 	case "bitbucket":
 		return buildBitbucketURL(purl), nil
-	default:
-		if logger != nil {
-			logger.Debug("purl type not supported", "type", purl.Type)
+	case "swift":
+		return buildSwiftURL(purl), nil
+	case "alpm":
+		if strings.EqualFold(purl.Namespace, "arch") {
+			return buildArchURL(purl), nil
 		}
-		return nil, ErrUnsupportedPurlType
+		return unsupportedPurlURL(purl, logger)
+	default:
+		return unsupportedPurlURL(purl, logger)
 	}
 }
 
+// unsupportedPurlURL returns the URL of a purl whose type (or namespace) is not supported: its download_url or vcs_url
+// qualifier (see qualifierURL). Returns ErrUnsupportedPurlType if it has neither.
+func unsupportedPurlURL(purl packageurl.PackageURL, logger *slog.Logger) (*string, error) {
+	if qualifierURL, ok := qualifierURL(purl); ok {
+		return &qualifierURL, nil
+	}
+	if logger != nil {
+		logger.Debug("purl type not supported", "type", purl.Type, "namespace", purl.Namespace)
+	}
+	return nil, ErrUnsupportedPurlType
+}
+
+// qualifierURL returns the download_url qualifier of a purl, or else its vcs_url qualifier reduced to the repository
+// URL (see NormalizeVCSURL). Returns ok as false if the purl has neither, or they are not usable web URLs.
+func qualifierURL(purl packageurl.PackageURL) (string, bool) {
+	qualifiers := purl.Qualifiers.Map()
+	if u, ok := NormalizeURL(qualifiers["download_url"]); ok {
+		return u, true
+	}
+	return NormalizeVCSURL(qualifiers["vcs_url"])
+}
+
 // buildURL constructs a URL from a format string and arguments.
 func buildURL(format string, args ...any) *string {
 	url := fmt.Sprintf(format, args...)
@@ -107,18 +174,57 @@ func buildGemURL(purl packageurl.PackageURL) *string {
 }
 
 // buildGolangURL constructs a Go package URL from a purl.
-// Version is not used, since versions are constructed in the https://pkg.go.dev documentation using tags.
-// Most packages use a prefix like `v1.0.0`, but this isn't always the case.
+// The version is appended ("@v1.2.3") when it is a valid module version for the module path (see goModuleVersion), so
+// the link points at the exact release; otherwise the https://pkg.go.dev page of the latest version is linked.
 func buildGolangURL(purl packageurl.PackageURL) *string {
+	modulePath := purl.Name
 	if purl.Namespace != "" {
-		return buildURL("https://pkg.go.dev/%s/%s", purl.Namespace, purl.Name)
+		modulePath = purl.Namespace + "/" + purl.Name
+	}
+	if version, ok := goModuleVersion(modulePath, purl.Version); ok {
+		return buildURL("https://pkg.go.dev/%s@%s", modulePath, version)
 	}
-	return buildURL("https://pkg.go.dev/%s", purl.Name)
+	return buildURL("https://pkg.go.dev/%s", modulePath)
+}
+
+// goSemver matches a semantic version tag as used by Go modules, capturing the major version.
+// A pre-release ("-rc.1", or a pseudo-version's "-0.20220722155255-886fb9371eb4") and build metadata may follow.
+var goSemver = regexp.MustCompile(`^v(0|[1-9]\d*)\.(?:0|[1-9]\d*)\.(?:0|[1-9]\d*)(?:[-+][0-9A-Za-z.+-]+)?$`)
+
+// goMajorSuffix matches the major version suffix of a module path, "/v2" or gopkg.in's ".v2", capturing the major
+// version.
+var goMajorSuffix = regexp.MustCompile(`[/.]v(0|[1-9]\d*)$`)
+
+// goModuleVersion returns the version of a Go module, with a "v" prefix added if it was dropped (see canonicalGo), if
+// it is a semantic version tag that matches the major version suffix of the module path: "/v2" requires a v2.x.x
+// version, and no suffix a v0 or v1 version (or a "+incompatible" one). Returns ok as false otherwise.
+func goModuleVersion(modulePath, version string) (string, bool) {
+	version = canonicalGo(PackageID{Version: version}).Version
+	m := goSemver.FindStringSubmatch(version)
+	if m == nil {
+		return "", false
+	}
+
+	major := m[1]
+	if suffix := goMajorSuffix.FindStringSubmatch(modulePath); suffix != nil {
+		return version, suffix[1] == major
+	}
+	return version, major == "0" || major == "1" || strings.HasSuffix(version, "+incompatible")
 }
 
 // buildMavenURL constructs a Maven package URL from a purl.
-// Uses the Maven Central repository URL.
+// Uses the Maven Central repository URL, unless the purl names another repository in its repository_url qualifier
+// (e.g. Google Maven, JitPack, or a corporate Nexus), in which case it links to the artifact directory in that
+// repository's standard layout ("<repository>/<group path>/<artifact>/<version>/").
 func buildMavenURL(purl packageurl.PackageURL) *string {
+	if repository, ok := NormalizeURL(purl.Qualifiers.Map()["repository_url"]); ok {
+		artifact := strings.TrimSuffix(repository, "/") + "/" + strings.ReplaceAll(purl.Namespace, ".", "/") + "/" +
+			purl.Name + "/"
+		if purl.Version != "" {
+			artifact += purl.Version + "/"
+		}
+		return &artifact
+	}
 	return buildURL("https://central.sonatype.com/artifact/%s/%s/%s", purl.Namespace, purl.Name, purl.Version)
 }
 
@@ -159,15 +265,47 @@ func buildDockerHubURL(purl packageurl.PackageURL) *string {
 	return buildURL("https://hub.docker.com/_/%s", purl.Name)
 }
 
-// buildDebURL constructs a Debian package URL from a purl.
-// For simplicity, we're not considering the distribution name in the URL.
+// buildDebURL constructs a Debian or Ubuntu package URL from a purl.
+// Ubuntu packages (namespace or distro qualifier "ubuntu") link to packages.ubuntu.com, in the suite named by the
+// distro qualifier (e.g. "ubuntu-22.04" is jammy), or to a search if the suite is unknown. Debian packages with a
+// version link to that version of their source package on sources.debian.org (named by the upstream qualifier, or else
+// the package name), and others to packages.debian.org, in the suite named by the distro qualifier if it is known.
 func buildDebURL(purl packageurl.PackageURL) *string {
+	qualifiers := purl.Qualifiers.Map()
+	distro := strings.ToLower(qualifiers["distro"])
+	suite := debianSuite(distro)
+
+	if strings.EqualFold(purl.Namespace, "ubuntu") || strings.HasPrefix(distro, "ubuntu") {
+		if suite != "" {
+			return buildURL("https://packages.ubuntu.com/%s/%s", suite, purl.Name)
+		}
+		return buildURL("https://packages.ubuntu.com/search?keywords=%s&searchon=names", url.QueryEscape(purl.Name))
+	}
+
+	if purl.Version != "" {
+		// The upstream qualifier names the source package, with its version if it differs ("openssl@3.0.11-1")
+		source, version, _ := strings.Cut(qualifiers["upstream"], "@")
+		return buildURL("https://sources.debian.org/src/%s/%s/", cmp.Or(source, purl.Name),
+			url.PathEscape(cmp.Or(version, purl.Version)))
+	}
+	if suite != "" {
+		return buildURL("https://packages.debian.org/%s/%s", suite, purl.Name)
+	}
 	return buildURL("https://packages.debian.org/%s", purl.Name)
 }
 
 // buildRpmURL constructs a RPM package URL from a purl.
+// Fedora and openSUSE packages (chosen by the namespace, or else the distro qualifier) link to the distribution's
+// package site; packages of other distributions to an rpmfind search as a last resort.
 func buildRpmURL(purl packageurl.PackageURL) *string {
-	return buildURL("https://rpmfind.net/linux/rpm2html/search.php?query=%s", purl.Name)
+	switch rpmDistro(purl) {
+	case "fedora":
+		return buildURL("https://packages.fedoraproject.org/pkgs/%s/", purl.Name)
+	case "opensuse":
+		return buildURL("https://software.opensuse.org/package/%s", purl.Name)
+	default:
+		return buildURL("https://rpmfind.net/linux/rpm2html/search.php?query=%s", purl.Name)
+	}
 }
 
 // buildApkURL constructs an APK package URL from a purl.
@@ -199,3 +337,22 @@ func buildCondaURL(purl packageurl.PackageURL) *string {
 func buildBitbucketURL(purl packageurl.PackageURL) *string {
 	return buildURL("https://bitbucket.org/%s/%s/src/%s", purl.Namespace, purl.Name, purl.Version)
 }
+
+// buildSwiftURL constructs a Swift package URL from a purl.
+// Swift packages are identified by their repository: the namespace is the source host and owner (e.g.
+// "github.com/Alamofire"), so it links to the repository. The version is not used, since tag names vary, and the
+// Swift Package Index is not used either, since it only lists the packages submitted to it.
+func buildSwiftURL(purl packageurl.PackageURL) *string {
+	return buildURL("https://%s/%s", purl.Namespace, purl.Name)
+}
+
+// buildArchURL constructs an Arch Linux package URL from a purl.
+// It links to the exact package page when the repo and arch qualifiers name it (e.g. core/x86_64), or else to a
+// package search for the name.
+func buildArchURL(purl packageurl.PackageURL) *string {
+	qualifiers := purl.Qualifiers.Map()
+	if repo, arch := qualifiers["repo"], qualifiers["arch"]; repo != "" && arch != "" {
+		return buildURL("https://archlinux.org/packages/%s/%s/%s/", strings.ToLower(repo), arch, purl.Name)
+	}
+	return buildURL("https://archlinux.org/packages/?q=%s", url.QueryEscape(purl.Name))
+}
@@ -62,6 +62,21 @@ func TestPurlToURL_OtherPackageTypes(t *testing.T) {
 		purl     string
 		expected string
 	}{
+		{
+			name:     "maven with repository_url",
+			purl:     "pkg:maven/androidx.core/core@1.12.0?repository_url=https://dl.google.com/android/maven2/",
+			expected: "https://dl.google.com/android/maven2/androidx/core/core/1.12.0/",
+		},
+		{
+			name:     "maven with schemeless repository_url",
+			purl:     "pkg:maven/com.github.PhilJay/MPAndroidChart@v3.1.0?repository_url=jitpack.io",
+			expected: "https://jitpack.io/com/github/PhilJay/MPAndroidChart/v3.1.0/",
+		},
+		{
+			name:     "maven with unusable repository_url",
+			purl:     "pkg:maven/org.acme/lib@1.0?repository_url=file%3A%2F%2F%2Fhome%2Fm2",
+			expected: "https://central.sonatype.com/artifact/org.acme/lib/1.0",
+		},
 		{
 			name:     "cargo",
 			purl:     "pkg:cargo/tokio@1.0.0",
@@ -80,12 +95,12 @@ func TestPurlToURL_OtherPackageTypes(t *testing.T) {
 		{
 			name:     "golang without namespace",
 			purl:     "pkg:golang/github.com/gin-gonic/gin@v1.9.0",
-			expected: "https://pkg.go.dev/github.com/gin-gonic/gin",
+			expected: "https://pkg.go.dev/github.com/gin-gonic/gin@v1.9.0",
 		},
 		{
 			name:     "nuget",
 			purl:     "pkg:nuget/Newtonsoft.Json@13.0.1",
-			expected: "https://www.nuget.org/packages/Newtonsoft.Json/13.0.1",
+			expected: "https://www.nuget.org/packages/newtonsoft.json/13.0.1",
 		},
 		{
 			name:     "pub",
@@ -115,7 +130,37 @@ func TestPurlToURL_OtherPackageTypes(t *testing.T) {
 		{
 			name:     "golang with namespace",
 			purl:     "pkg:golang/google.golang.org/grpc@v1.56.0",
-			expected: "https://pkg.go.dev/google.golang.org/grpc",
+			expected: "https://pkg.go.dev/google.golang.org/grpc@v1.56.0",
+		},
+		{
+			name:     "golang major version subpath",
+			purl:     "pkg:golang/github.com/go-chi/chi/v5@v5.0.10",
+			expected: "https://pkg.go.dev/github.com/go-chi/chi/v5@v5.0.10",
+		},
+		{
+			name:     "golang gopkg.in",
+			purl:     "pkg:golang/gopkg.in/yaml.v3@v3.0.1",
+			expected: "https://pkg.go.dev/gopkg.in/yaml.v3@v3.0.1",
+		},
+		{
+			name:     "golang pseudo-version without v prefix",
+			purl:     "pkg:golang/golang.org/x/sync@0.0.0-20220722155255-886fb9371eb4",
+			expected: "https://pkg.go.dev/golang.org/x/sync@v0.0.0-20220722155255-886fb9371eb4",
+		},
+		{
+			name:     "golang incompatible",
+			purl:     "pkg:golang/github.com/docker/docker@v24.0.7%2Bincompatible",
+			expected: "https://pkg.go.dev/github.com/docker/docker@v24.0.7+incompatible",
+		},
+		{
+			name:     "golang major version mismatch",
+			purl:     "pkg:golang/github.com/go-chi/chi@v5.0.10",
+			expected: "https://pkg.go.dev/github.com/go-chi/chi",
+		},
+		{
+			name:     "golang invalid version",
+			purl:     "pkg:golang/github.com/acme/tool@(devel)",
+			expected: "https://pkg.go.dev/github.com/acme/tool",
 		},
 		{
 			name:     "docker with namespace",
@@ -144,14 +189,74 @@ func TestPurlToURL_OtherPackageTypes(t *testing.T) {
 		},
 		{
 			name:     "deb",
-			purl:     "pkg:deb/debian/curl@7.88.1",
+			purl:     "pkg:deb/debian/curl@7.88.1-10%2Bdeb12u5",
+			expected: "https://sources.debian.org/src/curl/7.88.1-10+deb12u5/",
+		},
+		{
+			name:     "deb with upstream source package",
+			purl:     "pkg:deb/debian/libssl3@3.0.11-1~deb12u2?arch=amd64&upstream=openssl&distro=debian-12",
+			expected: "https://sources.debian.org/src/openssl/3.0.11-1~deb12u2/",
+		},
+		{
+			name:     "deb with upstream source version",
+			purl:     "pkg:deb/debian/libpam0g@1.5.2-6?upstream=pam%401.5.2-6%2Bdeb12u1",
+			expected: "https://sources.debian.org/src/pam/1.5.2-6+deb12u1/",
+		},
+		{
+			name:     "deb without version",
+			purl:     "pkg:deb/debian/curl?distro=debian-12.5",
+			expected: "https://packages.debian.org/bookworm/curl",
+		},
+		{
+			name:     "deb without version or distro",
+			purl:     "pkg:deb/debian/curl",
 			expected: "https://packages.debian.org/curl",
 		},
+		{
+			name:     "ubuntu",
+			purl:     "pkg:deb/ubuntu/openssl@3.0.2-0ubuntu1.10?arch=amd64&distro=ubuntu-22.04",
+			expected: "https://packages.ubuntu.com/jammy/openssl",
+		},
+		{
+			name:     "ubuntu codename",
+			purl:     "pkg:deb/ubuntu/openssl@3.0.2-0ubuntu1.10?distro=noble",
+			expected: "https://packages.ubuntu.com/noble/openssl",
+		},
+		{
+			name:     "ubuntu unknown release",
+			purl:     "pkg:deb/ubuntu/openssl@3.0.2?distro=ubuntu-99.04",
+			expected: "https://packages.ubuntu.com/search?keywords=openssl&searchon=names",
+		},
 		{
 			name:     "rpm",
 			purl:     "pkg:rpm/redhat/openssl@1.1.1",
 			expected: "https://rpmfind.net/linux/rpm2html/search.php?query=openssl",
 		},
+		{
+			name:     "alpm",
+			purl:     "pkg:alpm/arch/pacman@6.0.2-9?arch=x86_64",
+			expected: "https://archlinux.org/packages/?q=pacman",
+		},
+		{
+			name:     "alpm with repo",
+			purl:     "pkg:alpm/arch/python-requests@2.31.0-1?arch=any&repo=Extra",
+			expected: "https://archlinux.org/packages/extra/any/python-requests/",
+		},
+		{
+			name:     "rpm fedora",
+			purl:     "pkg:rpm/fedora/curl@8.6.0-7.fc40?arch=x86_64&distro=fedora-40",
+			expected: "https://packages.fedoraproject.org/pkgs/curl/",
+		},
+		{
+			name:     "rpm opensuse",
+			purl:     "pkg:rpm/opensuse/libopenssl3@3.1.4-9.1?distro=opensuse-tumbleweed",
+			expected: "https://software.opensuse.org/package/libopenssl3",
+		},
+		{
+			name:     "rpm distro without namespace",
+			purl:     "pkg:rpm/bash@5.2.26?distro=Fedora-40",
+			expected: "https://packages.fedoraproject.org/pkgs/bash/",
+		},
 		{
 			name:     "apk",
 			purl:     "pkg:apk/alpine/curl@8.0.0",
@@ -182,6 +287,16 @@ func TestPurlToURL_OtherPackageTypes(t *testing.T) {
 			purl:     "pkg:bitbucket/atlassian/python-bitbucket@0.1.0",
 			expected: "https://bitbucket.org/atlassian/python-bitbucket/src/0.1.0",
 		},
+		{
+			name:     "swift",
+			purl:     "pkg:swift/github.com/Alamofire/Alamofire@5.6.0",
+			expected: "https://github.com/Alamofire/Alamofire",
+		},
+		{
+			name:     "swift on gitlab",
+			purl:     "pkg:swift/gitlab.com/acme/swift-kit@1.0.0",
+			expected: "https://gitlab.com/acme/swift-kit",
+		},
 	}
 
 	for _, tt := range tests {
@@ -204,6 +319,29 @@ func TestPurlToURL_OtherPackageTypes(t *testing.T) {
 	}
 }
 
+// TestPurlToURL_CanonicalSpelling tests that every spelling of a package links to the same page (see CanonicalPurl).
+func TestPurlToURL_CanonicalSpelling(t *testing.T) {
+	t.Parallel()
+
+	for _, spellings := range [][]string{
+		{"pkg:pypi/Foo_Bar@1.0", "pkg:pypi/foo-bar@1.0", "pkg:pypi/foo.bar@1.0"},
+		{"pkg:cargo/Serde_JSON@1.0.0", "pkg:cargo/serde-json@1.0.0"},
+		{"pkg:golang/github.com/spf13/cobra@1.8.0", "pkg:golang/github.com/spf13/cobra@v1.8.0"},
+		{"pkg:pub/HTTP@1.1.0", "pkg:pub/http@1.1.0"},
+	} {
+		want, err := attribution.PurlToURL(spellings[0], nil)
+		if err != nil {
+			t.Fatalf("PurlToURL(%q) unexpected error: %v", spellings[0], err)
+		}
+		for _, purl := range spellings[1:] {
+			got, err := attribution.PurlToURL(purl, nil)
+			if err != nil || *got != *want {
+				t.Errorf("PurlToURL(%q) = %v, %v, want %q like %q", purl, got, err, *want, spellings[0])
+			}
+		}
+	}
+}
+
 // TestPurlToURL_InvalidPurl tests the PurlToURL function with an invalid purl.
 func TestPurlToURL_InvalidPurl(t *testing.T) {
 	t.Parallel()
@@ -248,7 +386,7 @@ func TestPurlToURL_UnsupportedType(t *testing.T) {
 		name string
 		purl string
 	}{
-		{name: "alpm", purl: "pkg:alpm/arch/pacman@6.0.0"},
+		{name: "alpm outside Arch Linux", purl: "pkg:alpm/msys2/pacman@6.0.0"},
 		{name: "bitnami", purl: "pkg:bitnami/nginx@1.0.0"},
 		{name: "conan", purl: "pkg:conan/boost@1.76.0"},
 		{name: "cran", purl: "pkg:cran/dplyr@1.0.0"},
@@ -275,6 +413,84 @@ func TestPurlToURL_UnsupportedType(t *testing.T) {
 	}
 }
 
+// TestPurlToURL_QualifierURL tests that purls of unsupported types link to their download_url or vcs_url qualifier.
+func TestPurlToURL_QualifierURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		purl     string
+		expected string
+	}{
+		{
+			name:     "download_url",
+			purl:     "pkg:generic/openssl@3.0.2?download_url=https%3A%2F%2Fwww.openssl.org%2Fsource%2Fopenssl.tar.gz",
+			expected: "https://www.openssl.org/source/openssl.tar.gz",
+		},
+		{
+			name:     "vcs_url",
+			purl:     "pkg:generic/bitwarderl?vcs_url=git%2Bhttps%3A%2F%2Fgit.fsfe.org%2Fdxtr%2Fbitwarderl%40cc55108",
+			expected: "https://git.fsfe.org/dxtr/bitwarderl",
+		},
+		{
+			name:     "download_url preferred",
+			purl:     "pkg:conan/zlib@1.3?download_url=https%3A%2F%2Fzlib.net&vcs_url=https%3A%2F%2Fgit.example",
+			expected: "https://zlib.net",
+		},
+		{
+			name:     "unusable download_url",
+			purl:     "pkg:generic/acme@1.0?download_url=NOASSERTION&vcs_url=https%3A%2F%2Fgithub.com%2Facme%2Facme",
+			expected: "https://github.com/acme/acme",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := attribution.PurlToURL(tt.purl, nil)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if *result != tt.expected {
+				t.Errorf("Expected URL %q, got %q", tt.expected, *result)
+			}
+		})
+	}
+}
+
+// TestRegisterURLBuilder tests that a registered URL builder is used for its purl type.
+func TestRegisterURLBuilder(t *testing.T) {
+	t.Parallel()
+
+	attribution.RegisterURLBuilder("Test-Internal", func(purl attribution.PurlParts) (*string, error) {
+		if purl.Version == "" {
+			return nil, attribution.ErrUnsupportedPurlType
+		}
+		u := "https://artifacts.acme.example/" + purl.Namespace + "/" + purl.Name + "/" + purl.Version
+		return &u, nil
+	})
+
+	result, err := attribution.PurlToURL("pkg:test-internal/team/lib@2.0", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if *result != "https://artifacts.acme.example/team/lib/2.0" {
+		t.Errorf("Expected the registered builder's URL, got %q", *result)
+	}
+	_, err = attribution.PurlToURL("pkg:test-internal/team/lib", nil)
+	if !errors.Is(err, attribution.ErrUnsupportedPurlType) {
+		t.Errorf("Expected the registered builder's error, got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterURLBuilder() with a nil builder did not panic")
+		}
+	}()
+	attribution.RegisterURLBuilder("test-nil", nil)
+}
+
 // TestPurlToURL_UnknownType tests the PurlToURL function with a completely unknown purl type.
 func TestPurlToURL_UnknownType(t *testing.T) {
 	t.Parallel()
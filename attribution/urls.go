@@ -0,0 +1,84 @@
+package attribution
+
+// URLKind is the kind of page a package URL points at.
+type URLKind string
+
+const (
+	// URLKindHomepage is the project website (SPDX homepage, CycloneDX website reference).
+	URLKindHomepage URLKind = "homepage"
+	// URLKindDownload is where the package can be downloaded (SPDX downloadLocation, CycloneDX distribution
+	// reference).
+	URLKindDownload URLKind = "download"
+	// URLKindDocumentation is the package documentation (CycloneDX documentation reference).
+	URLKindDocumentation URLKind = "documentation"
+	// URLKindSource is the source code repository (see Attribution.SourceRepo).
+	URLKindSource URLKind = "source"
+	// URLKindRegistry is the package registry page generated from the purl (see PurlToURL).
+	URLKindRegistry URLKind = "registry"
+	// URLKindSearch is a search generated from the CPE, as a last resort (see CPEToURL).
+	URLKindSearch URLKind = "search"
+)
+
+// TypedURL is a URL of a package, with the kind of page it points at.
+type TypedURL struct {
+	// Kind is the kind of page, e.g. URLKindHomepage.
+	Kind URLKind `json:"kind"`
+	// URL is the URL.
+	URL string `json:"url"`
+	// Dead is set if the URL was found not to exist when it was checked (see the linkcheck package).
+	Dead bool `json:"dead,omitempty"`
+}
+
+// WithURL returns a copy of the attribution with a URL of the given kind added to URLs, unless it is already listed.
+// The URLs are copied, so the original attribution is never modified. The primary URL is left unchanged.
+func (a Attribution) WithURL(kind URLKind, url string) Attribution {
+	if url == "" {
+		return a
+	}
+	for _, u := range a.URLs {
+		if u.URL == url {
+			return a
+		}
+	}
+	a.URLs = append(append([]TypedURL(nil), a.URLs...), TypedURL{Kind: kind, URL: url})
+	return a
+}
+
+// WithGeneratedURLs returns a copy of the attribution with the URLs generated from its purl (URLKindRegistry, see
+// PurlToURL) and its CPE (URLKindSearch, see CPEToURL) added to URLs. If the attribution has no primary URL yet, the
+// first generated one becomes the primary URL, with generated provenance.
+// URL generation is best-effort: purls and CPEs that no URL can be generated for are ignored.
+func (a Attribution) WithGeneratedURLs() Attribution {
+	if a.Purl != "" {
+		if url, err := PurlToURL(a.Purl, nil); err == nil {
+			a = a.withGeneratedURL(URLKindRegistry, *url)
+		}
+	}
+	if a.CPE != "" {
+		if url, err := CPEToURL(a.CPE); err == nil {
+			a = a.withGeneratedURL(URLKindSearch, *url)
+		}
+	}
+	return a
+}
+
+// withGeneratedURL returns a copy of the attribution with a generated URL added, made the primary URL if there is
+// none.
+func (a Attribution) withGeneratedURL(kind URLKind, url string) Attribution {
+	a = a.WithURL(kind, url)
+	if a.URL == nil {
+		a.URL = &url
+		a = a.WithProvenance(FieldURL, ProvenanceGenerated)
+	}
+	return a
+}
+
+// URLOf returns the first URL of the given kind, or an empty string if there is none.
+func (a Attribution) URLOf(kind URLKind) string {
+	for _, u := range a.URLs {
+		if u.Kind == kind {
+			return u.URL
+		}
+	}
+	return ""
+}
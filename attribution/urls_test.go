@@ -0,0 +1,78 @@
+package attribution_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestAttribution_WithURL tests that URLs are added once, without modifying the original attribution.
+func TestAttribution_WithURL(t *testing.T) {
+	t.Parallel()
+
+	original := attribution.Attribution{
+		Name: "lodash",
+		URLs: []attribution.TypedURL{{Kind: attribution.URLKindHomepage, URL: "https://lodash.com"}},
+	}
+
+	got := original.
+		WithURL(attribution.URLKindSource, "https://github.com/lodash/lodash").
+		WithURL(attribution.URLKindDownload, "https://lodash.com").
+		WithURL(attribution.URLKindDocumentation, "")
+
+	want := []attribution.TypedURL{
+		{Kind: attribution.URLKindHomepage, URL: "https://lodash.com"},
+		{Kind: attribution.URLKindSource, URL: "https://github.com/lodash/lodash"},
+	}
+	if !slices.Equal(got.URLs, want) {
+		t.Errorf("WithURL() URLs = %v, want %v", got.URLs, want)
+	}
+	if len(original.URLs) != 1 {
+		t.Errorf("WithURL() modified the original URLs: %v", original.URLs)
+	}
+	if got.URL != nil {
+		t.Errorf("WithURL() set the primary URL to %q, want it unchanged", *got.URL)
+	}
+	if url := got.URLOf(attribution.URLKindSource); url != "https://github.com/lodash/lodash" {
+		t.Errorf("URLOf(source) = %q, want the repository", url)
+	}
+	if url := got.URLOf(attribution.URLKindRegistry); url != "" {
+		t.Errorf("URLOf(registry) = %q, want empty", url)
+	}
+}
+
+// TestAttribution_WithGeneratedURLs tests that the purl and CPE URLs are added, and the first one becomes the primary
+// URL only if there is none.
+func TestAttribution_WithGeneratedURLs(t *testing.T) {
+	t.Parallel()
+
+	a := attribution.Attribution{
+		Name: "lodash",
+		Purl: "pkg:npm/lodash@4.17.21",
+		CPE:  "cpe:2.3:a:lodash:lodash:4.17.21:*:*:*:*:node.js:*:*",
+	}
+
+	got := a.WithGeneratedURLs()
+
+	if len(got.URLs) != 2 || got.URLs[0].Kind != attribution.URLKindRegistry ||
+		got.URLs[1].Kind != attribution.URLKindSearch {
+		t.Fatalf("WithGeneratedURLs() URLs = %v, want a registry and a search URL", got.URLs)
+	}
+	if got.URL == nil || *got.URL != got.URLs[0].URL {
+		t.Errorf("WithGeneratedURLs() URL = %v, want the registry URL", got.URL)
+	}
+	if p := got.Provenance[attribution.FieldURL]; p != attribution.ProvenanceGenerated {
+		t.Errorf("WithGeneratedURLs() URL provenance = %q, want %q", p, attribution.ProvenanceGenerated)
+	}
+
+	homepage := "https://lodash.com"
+	a.URL = &homepage
+	if got = a.WithGeneratedURLs(); *got.URL != homepage {
+		t.Errorf("WithGeneratedURLs() replaced the primary URL with %q", *got.URL)
+	}
+
+	if got = (attribution.Attribution{Name: "local"}).WithGeneratedURLs(); got.URL != nil || got.URLs != nil {
+		t.Errorf("WithGeneratedURLs() without purl or CPE = %v, %v, want no URLs", got.URL, got.URLs)
+	}
+}
@@ -0,0 +1,115 @@
+package capabilities
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"github.com/boringbin/sbomattr/format"
+	"github.com/boringbin/sbomattr/lockfileextract"
+)
+
+// SchemaVersion is the version of the capabilities document layout. It changes only on incompatible changes, so
+// consumers can reject documents they don't understand.
+const SchemaVersion = 1
+
+// Document is a capabilities document.
+type Document struct {
+	// SchemaVersion is the document layout version (see SchemaVersion).
+	SchemaVersion int `json:"schemaVersion"`
+	// Tool identifies the program the document describes.
+	Tool Tool `json:"tool"`
+	// InputFormats lists the supported SBOM input formats.
+	InputFormats []InputFormat `json:"inputFormats"`
+	// Lockfiles lists the supported lockfile names, for first-pass extraction without an SBOM.
+	Lockfiles []string `json:"lockfiles"`
+	// OutputFormats lists the names of the supported output formats, including registered ones.
+	OutputFormats []string `json:"outputFormats"`
+	// OutputFeatures maps the output formats supporting optional features (see format.Features) to those features.
+	OutputFeatures map[string][]format.Feature `json:"outputFeatures,omitempty"`
+	// Commands lists the supported subcommands or operations, if any.
+	Commands []string `json:"commands,omitempty"`
+	// Limits are the processing limits.
+	Limits Limits `json:"limits"`
+}
+
+// Tool identifies a program.
+type Tool struct {
+	// Name is the program name, e.g. "sbomattr".
+	Name string `json:"name"`
+	// Version is the program version, e.g. "v0.1.0" or "dev".
+	Version string `json:"version"`
+}
+
+// InputFormat is a supported input format.
+type InputFormat struct {
+	// Name is the format name, as reported by format detection (e.g. "spdx").
+	Name string `json:"name"`
+	// Versions lists the supported specification versions, if the format is versioned.
+	Versions []string `json:"versions,omitempty"`
+	// Encodings lists the supported encodings, e.g. "json".
+	Encodings []string `json:"encodings,omitempty"`
+}
+
+// Limits are processing limits. Zero means no limit.
+type Limits struct {
+	// MaxInputBytes is the maximum size of a single input file.
+	MaxInputBytes int64 `json:"maxInputBytes"`
+	// MaxInputFiles is the maximum number of input files per run.
+	MaxInputFiles int `json:"maxInputFiles"`
+}
+
+// New returns the capabilities document of this build of sbomattr, with the given tool version.
+// Output formats include the formatters registered with format.Register at the time of the call.
+func New(version string) Document {
+	lockfiles := make([]string, 0, len(lockfileextract.Kinds()))
+	for _, kind := range lockfileextract.Kinds() {
+		lockfiles = append(lockfiles, string(kind))
+	}
+
+	outputFeatures := make(map[string][]format.Feature)
+	for _, name := range format.Names() {
+		if features := format.Features(name); len(features) > 0 {
+			outputFeatures[name] = features
+		}
+	}
+
+	return Document{
+		SchemaVersion: SchemaVersion,
+		Tool:          Tool{Name: "sbomattr", Version: version},
+		InputFormats: []InputFormat{
+			{Name: "spdx", Versions: []string{"2.3"}, Encodings: []string{"json"}},
+			{Name: "cyclonedx", Versions: []string{"1.4"}, Encodings: []string{"json"}},
+			{Name: "go-binary"},
+			{Name: "sbomattr", Encodings: []string{"json", "ndjson"}},
+		},
+		Lockfiles:      lockfiles,
+		OutputFormats:  format.Names(),
+		OutputFeatures: outputFeatures,
+	}
+}
+
+// SupportsInput reports whether the document lists an input format by name.
+func (d Document) SupportsInput(name string) bool {
+	return slices.ContainsFunc(d.InputFormats, func(f InputFormat) bool { return f.Name == name })
+}
+
+// SupportsOutput reports whether the document lists an output format by name.
+func (d Document) SupportsOutput(name string) bool {
+	return slices.Contains(d.OutputFormats, name)
+}
+
+// ServeHTTP writes the document as JSON, so it can be mounted as a /capabilities endpoint.
+func (d Document) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == http.MethodHead {
+		return
+	}
+	_ = json.NewEncoder(w).Encode(d)
+}
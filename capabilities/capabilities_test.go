@@ -0,0 +1,89 @@
+package capabilities_test
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/capabilities"
+	"github.com/boringbin/sbomattr/format"
+)
+
+// TestNew tests the capabilities document of this build.
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	// The registry is global, so the name is unique per run for the test to pass with -count
+	name := "test-capabilities-" + rand.Text()
+	format.Register(name, format.FormatterFunc(func(io.Writer, []attribution.Attribution) error {
+		return nil
+	}))
+
+	doc := capabilities.New("v1.2.3")
+
+	if doc.SchemaVersion != capabilities.SchemaVersion || doc.Tool.Version != "v1.2.3" {
+		t.Errorf("New() = %+v, want schema version %d and tool version v1.2.3", doc, capabilities.SchemaVersion)
+	}
+	for _, input := range []string{"spdx", "cyclonedx", "go-binary"} {
+		if !doc.SupportsInput(input) {
+			t.Errorf("SupportsInput(%q) = false, want true", input)
+		}
+	}
+	if doc.SupportsInput("swid") {
+		t.Error(`SupportsInput("swid") = true, want false`)
+	}
+	for _, output := range []string{"csv", "cyclonedx", name} {
+		if !doc.SupportsOutput(output) {
+			t.Errorf("SupportsOutput(%q) = false, want true", output)
+		}
+	}
+	if !slices.Contains(doc.Lockfiles, "go.sum") {
+		t.Errorf("Lockfiles = %v, want it to contain go.sum", doc.Lockfiles)
+	}
+	if !slices.Contains(doc.OutputFeatures["csv"], format.FeatureDelimiter) {
+		t.Errorf("OutputFeatures[csv] = %v, want it to contain %s", doc.OutputFeatures["csv"], format.FeatureDelimiter)
+	}
+	if features, ok := doc.OutputFeatures["ndjson"]; ok {
+		t.Errorf("OutputFeatures[ndjson] = %v, want no entry", features)
+	}
+}
+
+// TestDocument_ServeHTTP tests serving the document as a /capabilities endpoint.
+func TestDocument_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(capabilities.New("dev"))
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL + "/capabilities")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("GET status = %d, content type = %q, want 200 application/json",
+			resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	var got capabilities.Document
+	if err = json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode capabilities: %v", err)
+	}
+	if got.Tool.Name != "sbomattr" || !got.SupportsOutput("json") {
+		t.Errorf("GET capabilities = %+v, want the sbomattr document", got)
+	}
+
+	postResp, err := http.Post(server.URL+"/capabilities", "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	postResp.Body.Close()
+	if postResp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want %d", postResp.StatusCode, http.StatusMethodNotAllowed)
+	}
+}
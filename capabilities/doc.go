@@ -0,0 +1,4 @@
+// Package capabilities describes what a build of sbomattr supports (input formats, output formats, commands, and
+// limits) as a machine-readable document, so orchestrating systems, servers, and plugins can adapt to each other
+// without parsing version strings.
+package capabilities
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/boringbin/sbomattr/capabilities"
+)
+
+// runCapabilities runs the capabilities command, which writes the machine-readable capabilities document of this
+// build to w as JSON. Returns the process exit code.
+func runCapabilities(args []string, w io.Writer) int {
+	fs := flag.NewFlagSet("sbomattr capabilities", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: sbomattr capabilities\n\n")
+		fmt.Fprintf(fs.Output(), "Print the supported input formats, output formats, commands, and limits as JSON.\n")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitSuccess
+		}
+		return exitInvalidArgs
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return exitInvalidArgs
+	}
+
+	doc := capabilities.New(version)
+	doc.OutputFormats = outputFormats()
+	doc.Commands = slices.Sorted(maps.Keys(commands()))
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		setupLogger(false).Error("failed to write capabilities", "error", err)
+		return exitRuntimeError
+	}
+
+	return exitSuccess
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/capabilities"
+)
+
+// TestRunCapabilities tests that the capabilities command writes the document with the CLI commands.
+func TestRunCapabilities(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if code := runCapabilities(nil, &buf); code != exitSuccess {
+		t.Fatalf("runCapabilities() = %d, want %d", code, exitSuccess)
+	}
+
+	var doc capabilities.Document
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("failed to decode capabilities: %v", err)
+	}
+	wantCommands := []string{"capabilities", "diff", "fetch", "github-org", "merge", "query", "stats", "validate",
+		"verify-urls"}
+	if !slices.Equal(doc.Commands, wantCommands) {
+		t.Errorf("Commands = %v, want the subcommands", doc.Commands)
+	}
+	if len(doc.OutputFormats) == 0 || doc.OutputFormats[0] != "csv" {
+		t.Errorf("OutputFormats = %v, want the CLI formats starting with csv", doc.OutputFormats)
+	}
+
+	if code := runCapabilities([]string{"extra"}, &buf); code != exitInvalidArgs {
+		t.Errorf("runCapabilities(extra) = %d, want %d", code, exitInvalidArgs)
+	}
+}
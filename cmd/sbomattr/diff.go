@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/boringbin/sbomattr"
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// runDiff runs the diff command, which compares the attributions of two sets of SBOMs (or of two previous JSON
+// outputs) and writes the added, removed, and changed packages to w. Returns the process exit code.
+func runDiff(args []string, w io.Writer) int {
+	fs := flag.NewFlagSet("sbomattr diff", flag.ContinueOnError)
+	verbose := fs.Bool("v", false, "Verbose output (debug mode)")
+	outputFormat := fs.String("format", "text", "Output format: text, json")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: sbomattr diff [OPTIONS] <old> <new>\n\n")
+		fmt.Fprintf(fs.Output(), "Compare the packages of two SBOM files or directories, or of two JSON outputs.\n\n")
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitSuccess
+		}
+		return exitInvalidArgs
+	}
+
+	logger := setupLogger(*verbose)
+
+	// The old and the new attributions
+	var sets [2][]attribution.Attribution
+	if fs.NArg() != len(sets) {
+		logger.Error("expected an old and a new SBOM file or directory")
+		fs.Usage()
+		return exitInvalidArgs
+	}
+	if *outputFormat != "text" && *outputFormat != "json" {
+		logger.Error("unsupported output format", "format", *outputFormat)
+		return exitInvalidArgs
+	}
+
+	for i, path := range fs.Args() {
+		attrs, err := processDiffInput(context.Background(), path, logger)
+		if err != nil {
+			logger.Error("failed to process SBOM files", "path", path, "error", err)
+			return exitInvalidSBOM
+		}
+		sets[i] = attrs
+	}
+
+	if err := writeDiff(w, *outputFormat, attribution.Compare(sets[0], sets[1])); err != nil {
+		logger.Error("failed to write output", "format", *outputFormat, "error", err)
+		return exitRuntimeError
+	}
+	return exitSuccess
+}
+
+// processDiffInput returns the deduplicated attributions of the SBOM file, or of the SBOM files in the directory, at
+// path.
+func processDiffInput(ctx context.Context, path string, logger *slog.Logger) ([]attribution.Attribution, error) {
+	files := expandPaths([]string{path}, false, logger)
+	if len(files) == 0 {
+		return nil, errors.New("no SBOM files found")
+	}
+	return sbomattr.NewProcessor(sbomattr.WithLogger(logger)).ProcessFiles(ctx, files)
+}
+
+// writeDiff writes the diff to the provided writer as JSON, or as text listing the added, removed, and changed
+// packages.
+func writeDiff(w io.Writer, outputFormat string, diff attribution.Diff) error {
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(diff)
+	}
+
+	if diff.IsEmpty() {
+		_, err := fmt.Fprintln(w, "No changes")
+		return err
+	}
+
+	var lines []string
+	section := func(title string, entries []string) {
+		if len(entries) == 0 {
+			return
+		}
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, fmt.Sprintf("%s (%d):", title, len(entries)))
+		for _, entry := range entries {
+			lines = append(lines, "  "+entry)
+		}
+	}
+
+	var added, removed, changed []string
+	for _, a := range diff.Added {
+		added = append(added, describePackage(a))
+	}
+	for _, a := range diff.Removed {
+		removed = append(removed, describePackage(a))
+	}
+	for _, c := range diff.Changed {
+		changed = append(changed, describeChange(c))
+	}
+	section("Added", added)
+	section("Removed", removed)
+	section("Changed", changed)
+
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+// describePackage describes an added or removed package as "<name> <version> (<license>)".
+func describePackage(a attribution.Attribution) string {
+	return fmt.Sprintf("%s (%s)", strings.TrimSpace(a.QualifiedName()+" "+a.Version), displayLicense(a))
+}
+
+// describeChange describes a changed package as "<name> <version>: <changes>", showing a version change as
+// "<old> -> <new>", a license change as "license <old> -> <new>", and other changed fields by name.
+func describeChange(c attribution.Change) string {
+	name := strings.TrimSpace(c.New.QualifiedName() + " " + c.New.Version)
+	var changes []string
+	for _, field := range c.Fields {
+		switch field {
+		case "version":
+			name = fmt.Sprintf("%s %s -> %s", c.New.QualifiedName(), c.Old.Version, c.New.Version)
+		case "license":
+			changes = append(changes, fmt.Sprintf("license %s -> %s", displayLicense(c.Old), displayLicense(c.New)))
+		default:
+			changes = append(changes, field)
+		}
+	}
+	if len(changes) == 0 {
+		return name
+	}
+	return name + ": " + strings.Join(changes, ", ")
+}
+
+// displayLicense returns the license expression of an attribution, or "unknown" if it has none.
+func displayLicense(a attribution.Attribution) string {
+	if license := a.LicenseExpression(); license != "" {
+		return license
+	}
+	return "unknown"
+}
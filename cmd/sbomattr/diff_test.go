@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// The diff fixtures are an old and a new SPDX document: lodash is upgraded, express changes license, left-pad is
+// removed, and zod is added.
+const (
+	diffOldFile = "testdata/diff/old.json"
+	diffNewFile = "testdata/diff/new.json"
+)
+
+// TestRunDiff tests that the diff command lists the added, removed, and changed packages.
+func TestRunDiff(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if code := runDiff([]string{diffOldFile, diffNewFile}, &buf); code != exitSuccess {
+		t.Fatalf("runDiff() exit code = %d, want %d", code, exitSuccess)
+	}
+
+	want := "Added (1):\n" +
+		"  zod 3.22.0 (unknown)\n" +
+		"\n" +
+		"Removed (1):\n" +
+		"  left-pad 1.3.0 (WTFPL)\n" +
+		"\n" +
+		"Changed (2):\n" +
+		"  express 4.18.2: license MIT -> Apache-2.0, licenseConcluded\n" +
+		"  lodash 4.17.20 -> 4.17.21\n"
+	if buf.String() != want {
+		t.Errorf("runDiff() output = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	code := runDiff([]string{diffOldFile, diffOldFile}, &buf)
+	if code != exitSuccess || buf.String() != "No changes\n" {
+		t.Errorf("runDiff() of identical inputs = %d, %q, want no changes", code, buf.String())
+	}
+}
+
+// TestRunDiff_JSON tests that the diff command writes the diff as JSON with -format json.
+func TestRunDiff_JSON(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if code := runDiff([]string{"-format", "json", diffOldFile, diffNewFile}, &buf); code != exitSuccess {
+		t.Fatalf("runDiff() exit code = %d, want %d", code, exitSuccess)
+	}
+
+	var diff attribution.Diff
+	if err := json.Unmarshal(buf.Bytes(), &diff); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if len(diff.Added) != 1 || len(diff.Removed) != 1 || len(diff.Changed) != 2 {
+		t.Errorf("diff = %+v, want one added, one removed, and two changed packages", diff)
+	}
+}
+
+// TestRunDiff_InvalidArgs tests that the diff command requires two inputs and a supported format.
+func TestRunDiff_InvalidArgs(t *testing.T) {
+	t.Parallel()
+
+	missing := filepath.Join(t.TempDir(), "missing.json")
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "one input", args: []string{diffOldFile}, want: exitInvalidArgs},
+		{name: "unsupported format", args: []string{"-format", "csv", diffOldFile, diffNewFile}, want: exitInvalidArgs},
+		{name: "missing input", args: []string{diffOldFile, missing}, want: exitInvalidSBOM},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if code := runDiff(tt.args, &bytes.Buffer{}); code != tt.want {
+				t.Errorf("runDiff() exit code = %d, want %d", code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/boringbin/sbomattr"
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/internal/github"
+	"github.com/boringbin/sbomattr/internal/oci"
+)
+
+const (
+	// maxDownloadBytes is the size limit of an SBOM downloaded from a URL.
+	maxDownloadBytes = 256 << 20
+	// fetchTimeout is the time limit of each request of the fetch command, including reading the response, so it
+	// allows for large SBOMs.
+	fetchTimeout = 2 * time.Minute
+)
+
+// fetchedSBOM is an SBOM downloaded by the fetch command.
+type fetchedSBOM struct {
+	// source is the source the SBOM was fetched from, e.g. "github:acme/app".
+	source string
+	// name is the base name of the file the SBOM is written to.
+	name string
+	// data is the SBOM.
+	data []byte
+}
+
+// fetcher downloads SBOMs from the sources of the fetch command.
+type fetcher struct {
+	github   *github.Client
+	registry *oci.Client
+	http     *http.Client
+}
+
+// runFetch runs the fetch command, which downloads SBOMs from GitHub dependency graphs, container registries, and
+// URLs, and either writes them to the -o directory, listing the written files on w, or processes them like the main
+// command, writing the result to w. Returns the process exit code.
+func runFetch(args []string, w io.Writer) int {
+	fs := flag.NewFlagSet("sbomattr fetch", flag.ContinueOnError)
+	opts := registerFlags(fs)
+	outputDir := fs.String("o", "", "Write the fetched SBOMs to this directory instead of processing them")
+	token := fs.String("token", "", "GitHub token for github: sources (default $GITHUB_TOKEN)")
+	apiURL := fs.String("api-url", github.DefaultBaseURL, "GitHub API base URL (for GitHub Enterprise Server)")
+	registryUser := fs.String("registry-username", "",
+		"Username for oci: sources, with the password in $REGISTRY_PASSWORD (default anonymous)")
+	plainHTTP := fs.Bool("registry-plain-http", false, "Talk to registries over HTTP, e.g. a local registry")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: sbomattr fetch [OPTIONS] <source>...\n\n")
+		fmt.Fprintf(fs.Output(), "Download SBOMs and process them, or write them to a directory with -o.\n\n")
+		fmt.Fprintf(fs.Output(), "Sources:\n")
+		fmt.Fprintf(fs.Output(), "  github:<owner>/<repo>  Dependency graph SBOM of a GitHub repository\n")
+		fmt.Fprintf(fs.Output(), "  oci:<image>            SBOMs attached to a container image (referrers or cosign)\n")
+		fmt.Fprintf(fs.Output(), "  https://<url>          SBOM at a URL\n\n")
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitSuccess
+		}
+		return exitInvalidArgs
+	}
+
+	logger := setupLogger(opts.verbose)
+
+	if fs.NArg() == 0 {
+		logger.Error("no SBOM sources provided")
+		fs.Usage()
+		return exitInvalidArgs
+	}
+	if opts.offline {
+		logger.Error("invalid options", "error", "-offline is not supported by fetch, which downloads SBOMs")
+		return exitInvalidArgs
+	}
+
+	client := &http.Client{Timeout: fetchTimeout}
+	f := &fetcher{
+		github: github.NewClient(cmp.Or(*token, os.Getenv("GITHUB_TOKEN"))),
+		registry: &oci.Client{
+			HTTPClient: client,
+			Username:   *registryUser,
+			Password:   os.Getenv("REGISTRY_PASSWORD"),
+			PlainHTTP:  *plainHTTP,
+			Logger:     logger,
+		},
+		http: client,
+	}
+	f.github.BaseURL = *apiURL
+	f.github.HTTPClient = client
+	f.github.Logger = logger
+
+	var sboms []fetchedSBOM
+	for _, source := range fs.Args() {
+		fetched, err := f.fetch(context.Background(), source)
+		if err != nil {
+			logger.Error("failed to fetch SBOM", "source", source, "error", err)
+			return exitRuntimeError
+		}
+		logger.Info("fetched SBOMs", "source", source, "count", len(fetched))
+		sboms = append(sboms, fetched...)
+	}
+
+	if *outputDir != "" {
+		return writeFetched(w, *outputDir, sboms, logger)
+	}
+	return opts.processFetched(w, sboms, logger)
+}
+
+// fetch downloads the SBOMs of a source: "github:<owner>/<repo>", "oci:<image>", or an http(s) URL.
+func (f *fetcher) fetch(ctx context.Context, source string) ([]fetchedSBOM, error) {
+	kind, target, _ := strings.Cut(source, ":")
+	switch kind {
+	case "github":
+		owner, repo, ok := strings.Cut(target, "/")
+		if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+			return nil, fmt.Errorf("invalid GitHub repository %q: want <owner>/<repo>", target)
+		}
+		data, err := f.github.DependencyGraphSBOM(ctx, target)
+		if err != nil {
+			return nil, fmt.Errorf("fetch dependency graph SBOM: %w", err)
+		}
+		return []fetchedSBOM{{source: source, name: fileName("github-"+target) + ".spdx.json", data: data}}, nil
+	case "oci":
+		return f.fetchImage(ctx, target)
+	case "http", "https":
+		return f.download(ctx, source)
+	default:
+		return nil, fmt.Errorf("unsupported source %q: want github:<owner>/<repo>, oci:<image>, or an https URL",
+			source)
+	}
+}
+
+// fetchImage downloads the SBOMs attached to an image. Their sources are "oci:<image>@<SBOM digest>".
+func (f *fetcher) fetchImage(ctx context.Context, image string) ([]fetchedSBOM, error) {
+	// The SBOM digest prefix that tells the files of the SBOMs of an image apart
+	const digestPrefixLen = 12
+
+	ref, err := oci.ParseReference(image)
+	if err != nil {
+		return nil, err
+	}
+	attached, err := f.registry.SBOMs(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	sboms := make([]fetchedSBOM, 0, len(attached))
+	for _, sbom := range attached {
+		ext := ".spdx.json"
+		if strings.Contains(sbom.MediaType, "cyclonedx") {
+			ext = ".cdx.json"
+		}
+		_, hash, _ := strings.Cut(sbom.Digest, ":")
+		name := "oci-" + ref.Repository + "-" + cmp.Or(ref.Tag, "digest") + "-" + hash[:min(len(hash), digestPrefixLen)]
+		sboms = append(sboms, fetchedSBOM{source: "oci:" + image + "@" + sbom.Digest, name: fileName(name) + ext,
+			data: sbom.Data})
+	}
+	return sboms, nil
+}
+
+// download downloads the SBOM at a URL.
+func (f *fetcher) download(ctx context.Context, rawURL string) ([]fetchedSBOM, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid URL %q", rawURL)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	resp, err := f.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download: unexpected response status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("download: %w", err)
+	}
+	if len(data) > maxDownloadBytes {
+		return nil, fmt.Errorf("download: larger than %d bytes", maxDownloadBytes)
+	}
+
+	name := fileName(u.Host + "-" + strings.TrimSuffix(path.Base(u.Path), ".json"))
+	return []fetchedSBOM{{source: rawURL, name: name + ".json", data: data}}, nil
+}
+
+// fileName returns s with every character other than letters, digits, ".", "_", and "-" replaced by "-", to name the
+// file of a fetched SBOM.
+func fileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', strings.ContainsRune("._-", r):
+			return r
+		default:
+			return '-'
+		}
+	}, s)
+}
+
+// saveFetched writes the SBOMs to dir, which is created if needed, and returns the paths they were written to. SBOMs
+// with the same file name get a numeric suffix.
+func saveFetched(dir string, sboms []fetchedSBOM) ([]string, error) {
+	const (
+		dirPerm  = 0o755
+		filePerm = 0o644
+	)
+	if err := os.MkdirAll(dir, dirPerm); err != nil {
+		return nil, fmt.Errorf("create directory: %w", err)
+	}
+
+	paths := make([]string, 0, len(sboms))
+	used := make(map[string]bool)
+	for _, sbom := range sboms {
+		name := sbom.name
+		base, ext := splitExt(sbom.name)
+		for i := 2; used[name]; i++ {
+			name = base + "-" + strconv.Itoa(i) + ext
+		}
+		used[name] = true
+
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, sbom.data, filePerm); err != nil {
+			return nil, fmt.Errorf("write SBOM: %w", err)
+		}
+		paths = append(paths, p)
+	}
+	return paths, nil
+}
+
+// splitExt splits a fetched SBOM file name into its base and its extension: ".spdx.json", ".cdx.json", or else the
+// last extension, so dots in host names or versions stay in the base.
+func splitExt(name string) (string, string) {
+	for _, ext := range []string{".spdx.json", ".cdx.json"} {
+		if base, ok := strings.CutSuffix(name, ext); ok {
+			return base, ext
+		}
+	}
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext), ext
+}
+
+// writeFetched writes the SBOMs to dir and lists the written files on w. Returns the process exit code.
+func writeFetched(w io.Writer, dir string, sboms []fetchedSBOM, logger *slog.Logger) int {
+	paths, err := saveFetched(dir, sboms)
+	if err != nil {
+		logger.Error("failed to write fetched SBOMs", "dir", dir, "error", err)
+		return exitRuntimeError
+	}
+	for _, p := range paths {
+		if _, err = fmt.Fprintln(w, p); err != nil {
+			logger.Error("failed to write output", "error", err)
+			return exitRuntimeError
+		}
+	}
+	return exitSuccess
+}
+
+// processFetched processes the SBOMs like the main command processes files, writing the result to w, with the
+// sources they were fetched from as the sources of the attributions. Returns the process exit code.
+func (o *options) processFetched(w io.Writer, sboms []fetchedSBOM, logger *slog.Logger) int {
+	dir, err := os.MkdirTemp("", "sbomattr-fetch-")
+	if err != nil {
+		logger.Error("failed to create temporary directory", "error", err)
+		return exitRuntimeError
+	}
+	defer os.RemoveAll(dir)
+
+	paths, err := saveFetched(dir, sboms)
+	if err != nil {
+		logger.Error("failed to write fetched SBOMs", "dir", dir, "error", err)
+		return exitRuntimeError
+	}
+	o.sourceNames = make(map[string]string, len(paths))
+	for i, p := range paths {
+		o.sourceNames[p] = sboms[i].source
+	}
+	return o.runInputs(paths, w, logger)
+}
+
+// renameSources returns a transform replacing the file names in the sources of attributions with their names in
+// names, if any.
+func renameSources(names map[string]string) sbomattr.Transform {
+	return func(a attribution.Attribution) (attribution.Attribution, bool) {
+		sources := make([]string, len(a.Sources))
+		for i, source := range a.Sources {
+			sources[i] = cmp.Or(names[source], source)
+		}
+		a.Sources = sources
+		return a, true
+	}
+}
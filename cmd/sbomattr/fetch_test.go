@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newSBOMServer starts a server serving a CycloneDX BOM at /sboms/app.cdx.json.
+func newSBOMServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sboms/app.cdx.json", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"bomFormat": "CycloneDX", "specVersion": "1.5", "components": [`+
+			`{"type": "library", "name": "requests", "version": "2.31.0", "purl": "pkg:pypi/requests@2.31.0", `+
+			`"licenses": [{"license": {"id": "Apache-2.0"}}]}]}`)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestRunFetch tests processing SBOMs fetched from GitHub and a URL, with the sources as attribution sources.
+func TestRunFetch(t *testing.T) {
+	t.Parallel()
+
+	github := newGitHubServer(t)
+	sboms := newSBOMServer(t)
+	sbomURL := sboms.URL + "/sboms/app.cdx.json"
+
+	var buf bytes.Buffer
+	args := []string{
+		"-api-url", github.URL, "-token", "test", "-columns", "name,license,sources", "github:acme/web", sbomURL,
+	}
+	if code := runFetch(args, &buf); code != exitSuccess {
+		t.Fatalf("runFetch() exit code = %d, want %d", code, exitSuccess)
+	}
+
+	want := "Name,License,Sources\n" +
+		"lodash,MIT,github:acme/web\n" +
+		"requests,Apache-2.0," + sbomURL + "\n"
+	if buf.String() != want {
+		t.Errorf("runFetch() output = %q, want %q", buf.String(), want)
+	}
+}
+
+// TestRunFetch_OutputDir tests writing fetched SBOMs to a directory.
+func TestRunFetch_OutputDir(t *testing.T) {
+	t.Parallel()
+
+	github := newGitHubServer(t)
+	dir := filepath.Join(t.TempDir(), "sboms")
+
+	var buf bytes.Buffer
+	args := []string{"-api-url", github.URL, "-o", dir, "github:acme/api", "github:acme/web"}
+	if code := runFetch(args, &buf); code != exitSuccess {
+		t.Fatalf("runFetch() exit code = %d, want %d", code, exitSuccess)
+	}
+
+	want := filepath.Join(dir, "github-acme-api.spdx.json") + "\n" +
+		filepath.Join(dir, "github-acme-web.spdx.json") + "\n"
+	if buf.String() != want {
+		t.Errorf("runFetch() output = %q, want %q", buf.String(), want)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "github-acme-web.spdx.json"))
+	if err != nil {
+		t.Fatalf("failed to read fetched SBOM: %v", err)
+	}
+	if !strings.Contains(string(data), "lodash") {
+		t.Errorf("fetched SBOM = %s, want the dependency graph SBOM", data)
+	}
+}
+
+// TestSaveFetched tests that SBOMs with the same file name get numeric suffixes before their extension, keeping the
+// dots of host names and versions.
+func TestSaveFetched(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	sboms := []fetchedSBOM{
+		{name: "app.spdx.json", data: []byte("1")},
+		{name: "app.spdx.json", data: []byte("2")},
+		{name: "app.spdx.json", data: []byte("3")},
+		{name: "github.com-sbom.v2.json", data: []byte("4")},
+		{name: "github.com-sbom.v2.json", data: []byte("5")},
+		{name: "oci-registry.io-app-1.0.cdx.json", data: []byte("6")},
+		{name: "oci-registry.io-app-1.0.cdx.json", data: []byte("7")},
+	}
+	paths, err := saveFetched(dir, sboms)
+	if err != nil {
+		t.Fatalf("saveFetched() unexpected error: %v", err)
+	}
+
+	for i, name := range []string{
+		"app.spdx.json", "app-2.spdx.json", "app-3.spdx.json",
+		"github.com-sbom.v2.json", "github.com-sbom.v2-2.json",
+		"oci-registry.io-app-1.0.cdx.json", "oci-registry.io-app-1.0-2.cdx.json",
+	} {
+		if paths[i] != filepath.Join(dir, name) {
+			t.Errorf("saveFetched() path %d = %q, want %q", i, paths[i], filepath.Join(dir, name))
+		}
+	}
+}
+
+// TestFileName tests the sanitizing of fetched SBOM file names.
+func TestFileName(t *testing.T) {
+	t.Parallel()
+
+	if got, want := fileName("oci-acme/app-v1.0_rc:1"), "oci-acme-app-v1.0_rc-1"; got != want {
+		t.Errorf("fileName() = %q, want %q", got, want)
+	}
+}
+
+// TestRunFetch_Errors tests the fetch command with invalid arguments and failing sources.
+func TestRunFetch_Errors(t *testing.T) {
+	t.Parallel()
+
+	github := newGitHubServer(t)
+	sboms := newSBOMServer(t)
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "no sources", args: nil, want: exitInvalidArgs},
+		{name: "unknown flag", args: []string{"-unknown", "github:acme/web"}, want: exitInvalidArgs},
+		{name: "offline", args: []string{"-offline", "github:acme/web"}, want: exitInvalidArgs},
+		{name: "unsupported source", args: []string{"ftp://example.com/sbom.json"}, want: exitRuntimeError},
+		{name: "invalid repository", args: []string{"github:acme"}, want: exitRuntimeError},
+		{name: "invalid image", args: []string{"oci:Acme/App"}, want: exitRuntimeError},
+		{
+			name: "missing dependency graph",
+			args: []string{"-api-url", github.URL, "github:acme/nograph"},
+			want: exitRuntimeError,
+		},
+		{name: "missing URL", args: []string{sboms.URL + "/missing.json"}, want: exitRuntimeError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if code := runFetch(tt.args, &buf); code != tt.want {
+				t.Errorf("runFetch() exit code = %d, want %d", code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+
+	"github.com/boringbin/sbomattr"
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/internal/github"
+)
+
+// repoFilter selects the repositories included in an organization sweep.
+type repoFilter struct {
+	// includeForks includes forked repositories.
+	includeForks bool
+	// includeArchived includes archived repositories.
+	includeArchived bool
+	// visibility only includes repositories with this visibility, unless it is "all".
+	visibility string
+	// match only includes repositories whose name matches, if set.
+	match *regexp.Regexp
+}
+
+// matches reports whether the repository passes the filter.
+func (f repoFilter) matches(repo github.Repository) bool {
+	switch {
+	case repo.Fork && !f.includeForks:
+		return false
+	case repo.Archived && !f.includeArchived:
+		return false
+	case f.visibility != "all" && repo.Visibility != f.visibility:
+		return false
+	case f.match != nil && !f.match.MatchString(repo.Name):
+		return false
+	default:
+		return true
+	}
+}
+
+// runGitHubOrg runs the github-org command, which aggregates the dependency graph SBOMs of the repositories of a
+// GitHub organization into a single report written to w. Returns the process exit code.
+func runGitHubOrg(args []string, w io.Writer) int {
+	fs := flag.NewFlagSet("sbomattr github-org", flag.ContinueOnError)
+	opts := registerFlags(fs)
+	token := fs.String("token", "", "GitHub token (default $GITHUB_TOKEN)")
+	apiURL := fs.String("api-url", github.DefaultBaseURL, "GitHub API base URL (for GitHub Enterprise Server)")
+	filter := repoFilter{}
+	fs.BoolVar(&filter.includeForks, "include-forks", false, "Include forked repositories")
+	fs.BoolVar(&filter.includeArchived, "include-archived", false, "Include archived repositories")
+	fs.StringVar(&filter.visibility, "visibility", "all", "Only include repositories with this visibility: "+
+		"all, public, private, internal")
+	match := fs.String("match", "", "Only include repositories whose name matches this regular expression")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: sbomattr github-org [OPTIONS] <org>\n\n")
+		fmt.Fprintf(fs.Output(), "Aggregate the dependency graph SBOMs of a GitHub organization's repositories.\n\n")
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitSuccess
+		}
+		return exitInvalidArgs
+	}
+
+	logger := setupLogger(opts.verbose)
+
+	if fs.NArg() != 1 {
+		logger.Error("expected exactly one organization")
+		fs.Usage()
+		return exitInvalidArgs
+	}
+
+	if err := opts.validate(); err != nil {
+		logger.Error("invalid options", "error", err)
+		return exitInvalidArgs
+	}
+	if opts.includeGraph || opts.offline {
+		logger.Error("invalid options", "error", "-include-graph and -offline are not supported by github-org")
+		return exitInvalidArgs
+	}
+	csvOpts, err := opts.csvOptions()
+	if err != nil {
+		logger.Error("invalid output options", "error", err)
+		return exitInvalidArgs
+	}
+	tmpl, err := opts.readTemplate()
+	if err != nil {
+		logger.Error("failed to read template file", "file", opts.templateFile, "error", err)
+		return exitInvalidArgs
+	}
+	if *match != "" {
+		if filter.match, err = regexp.Compile(*match); err != nil {
+			logger.Error("invalid -match expression", "error", err)
+			return exitInvalidArgs
+		}
+	}
+
+	client := github.NewClient(*token)
+	if client.Token == "" {
+		client.Token = os.Getenv("GITHUB_TOKEN")
+	}
+	client.BaseURL = *apiURL
+	client.Logger = logger
+
+	ctx := context.Background()
+	attributions, swept, err := sweepOrg(ctx, client, fs.Arg(0), filter, opts.dedupMode(), logger)
+	if err != nil {
+		logger.Error("failed to sweep organization", "org", fs.Arg(0), "error", err)
+		return exitRuntimeError
+	}
+	opts.inputs = swept
+
+	return opts.emit(ctx, w, attributions, nil, tmpl, csvOpts, logger)
+}
+
+// sweepOrg fetches the dependency graph SBOM of every repository of the organization that passes the filter, and
+// returns their attributions deduplicated with the mode, with the repository full names as sources, and the number of
+// repositories whose SBOM was processed.
+// Repositories whose SBOM cannot be fetched or processed (e.g. with the dependency graph disabled) are logged and
+// skipped.
+func sweepOrg(
+	ctx context.Context,
+	client *github.Client,
+	org string,
+	filter repoFilter,
+	mode attribution.DedupMode,
+	logger *slog.Logger,
+) ([]attribution.Attribution, int, error) {
+	repos, err := client.OrgRepositories(ctx, org)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list repositories: %w", err)
+	}
+
+	var all []attribution.Attribution
+	swept := 0
+	for _, repo := range repos {
+		if !filter.matches(repo) {
+			logger.Debug("skipping repository", "repo", repo.FullName)
+			continue
+		}
+
+		data, fetchErr := client.DependencyGraphSBOM(ctx, repo.FullName)
+		if fetchErr != nil {
+			logger.Warn("failed to fetch dependency graph SBOM", "repo", repo.FullName, "error", fetchErr)
+			continue
+		}
+
+		attrs, processErr := sbomattr.Process(ctx, data, logger)
+		if processErr != nil {
+			logger.Warn("failed to process dependency graph SBOM", "repo", repo.FullName, "error", processErr)
+			continue
+		}
+		for i := range attrs {
+			attrs[i].Sources = []string{repo.FullName}
+		}
+		all = append(all, attrs...)
+		swept++
+	}
+
+	if len(all) == 0 {
+		return nil, 0, errors.New("no attributions extracted from any repository")
+	}
+
+	deduplicated := attribution.DeduplicateMode(all, mode, nil, logger)
+	return attribution.Sort(deduplicated, attribution.SortOptions{Key: attribution.SortByPurl}), swept, nil
+}
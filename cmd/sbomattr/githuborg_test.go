@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/boringbin/sbomattr/internal/github"
+)
+
+// TestRepoFilter_Matches tests the repository filter.
+func TestRepoFilter_Matches(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		filter repoFilter
+		repo   github.Repository
+		want   bool
+	}{
+		{name: "default", filter: repoFilter{visibility: "all"}, repo: github.Repository{Name: "api"}, want: true},
+		{name: "fork", filter: repoFilter{visibility: "all"}, repo: github.Repository{Fork: true}, want: false},
+		{
+			name:   "included fork",
+			filter: repoFilter{visibility: "all", includeForks: true},
+			repo:   github.Repository{Fork: true},
+			want:   true,
+		},
+		{name: "archived", filter: repoFilter{visibility: "all"}, repo: github.Repository{Archived: true}, want: false},
+		{
+			name:   "visibility",
+			filter: repoFilter{visibility: "public"},
+			repo:   github.Repository{Visibility: "private"},
+			want:   false,
+		},
+		{
+			name:   "match",
+			filter: repoFilter{visibility: "all", match: regexp.MustCompile(`^svc-`)},
+			repo:   github.Repository{Name: "web"},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.filter.matches(tt.repo); got != tt.want {
+				t.Errorf("matches(%+v) = %v, want %v", tt.repo, got, tt.want)
+			}
+		})
+	}
+}
+
+// newGitHubServer starts a fake GitHub API with an organization "acme" whose "api" and "web" repositories have
+// dependency graph SBOMs, a fork, and a repository with the dependency graph disabled.
+func newGitHubServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	sbom := func(pkgs string) string {
+		return `{"sbom": {"spdxVersion": "SPDX-2.3", "packages": [` + pkgs + `]}}`
+	}
+	lodash := `{"name": "lodash", "licenseConcluded": "MIT", "externalRefs": [` +
+		`{"referenceType": "purl", "referenceLocator": "pkg:npm/lodash@4.17.21"}]}`
+	express := `{"name": "express", "licenseConcluded": "MIT", "externalRefs": [` +
+		`{"referenceType": "purl", "referenceLocator": "pkg:npm/express@4.18.2"}]}`
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orgs/acme/repos", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[
+			{"name": "api", "full_name": "acme/api", "visibility": "private"},
+			{"name": "web", "full_name": "acme/web", "visibility": "public"},
+			{"name": "fork", "full_name": "acme/fork", "fork": true, "visibility": "public"},
+			{"name": "nograph", "full_name": "acme/nograph", "visibility": "public"}
+		]`)
+	})
+	mux.HandleFunc("/repos/acme/api/dependency-graph/sbom", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, sbom(lodash+","+express))
+	})
+	mux.HandleFunc("/repos/acme/web/dependency-graph/sbom", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, sbom(lodash))
+	})
+	mux.HandleFunc("/repos/acme/fork/dependency-graph/sbom", func(_ http.ResponseWriter, _ *http.Request) {
+		t.Error("fork should be skipped")
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestRunGitHubOrg tests the github-org command against a fake GitHub API.
+func TestRunGitHubOrg(t *testing.T) {
+	t.Parallel()
+
+	server := newGitHubServer(t)
+
+	var buf bytes.Buffer
+	args := []string{"-api-url", server.URL, "-token", "test", "-columns", "name,license,sources", "acme"}
+	if code := runGitHubOrg(args, &buf); code != exitSuccess {
+		t.Fatalf("runGitHubOrg() exit code = %d, want %d", code, exitSuccess)
+	}
+
+	want := "Name,License,Sources\n" +
+		"express,MIT,acme/api\n" +
+		"lodash,MIT,acme/api; acme/web\n"
+	if buf.String() != want {
+		t.Errorf("runGitHubOrg() output = %q, want %q", buf.String(), want)
+	}
+}
+
+// TestRunGitHubOrg_InvalidArgs tests the github-org command with invalid arguments.
+func TestRunGitHubOrg_InvalidArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "no org", args: nil},
+		{name: "invalid match", args: []string{"-match", "(", "acme"}},
+		{name: "invalid format", args: []string{"-format", "xml", "acme"}},
+		{name: "offline", args: []string{"-offline", "acme"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if code := runGitHubOrg(tt.args, &buf); code != exitInvalidArgs {
+				t.Errorf("runGitHubOrg() exit code = %d, want %d", code, exitInvalidArgs)
+			}
+		})
+	}
+}
@@ -2,16 +2,22 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/boringbin/sbomattr"
+	"github.com/boringbin/sbomattr/attribution"
 	"github.com/boringbin/sbomattr/format"
+	"github.com/boringbin/sbomattr/lockfileextract"
+	"github.com/boringbin/sbomattr/manifest"
 )
 
 // version is the version of the `sbomattr` CLI.
@@ -28,6 +34,12 @@ const (
 	exitInvalidSBOM = 2
 	// exitRuntimeError is the exit code for runtime error.
 	exitRuntimeError = 3
+	// exitPinDeviation is the exit code for attributions deviating from the -pins file.
+	exitPinDeviation = 4
+	// exitForbiddenLicense is the exit code for packages whose license falls in a -forbid category.
+	exitForbiddenLicense = 5
+	// exitDeadLinks is the exit code for dead URLs found by the verify-urls command.
+	exitDeadLinks = 6
 )
 
 func main() {
@@ -35,10 +47,14 @@ func main() {
 }
 
 func run() int {
-	var (
-		verbose     = flag.Bool("v", false, "Verbose output (debug mode)")
-		showVersion = flag.Bool("version", false, "Show version and exit")
-	)
+	// Dispatch subcommands
+	if len(os.Args) > 1 {
+		if command, ok := commands()[os.Args[1]]; ok {
+			return command(os.Args[2:], os.Stdout)
+		}
+	}
+
+	opts := registerFlags(flag.CommandLine)
 
 	// Customize usage message
 	printUsageFunc := func() {
@@ -49,13 +65,13 @@ func run() int {
 	flag.Parse()
 
 	// Handle version flag
-	if *showVersion {
+	if opts.showVersion {
 		fmt.Fprintf(os.Stdout, "sbomattr version %s\n", version)
 		return exitSuccess
 	}
 
 	// Setup logger based on verbose flag
-	logger := setupLogger(*verbose)
+	logger := setupLogger(opts.verbose)
 
 	// Get the input paths from the arguments
 	args := flag.Args()
@@ -67,38 +83,288 @@ func run() int {
 		return exitInvalidArgs
 	}
 
+	return opts.runInputs(args, os.Stdout, logger)
+}
+
+// runInputs validates the options, then processes the SBOM files and directories at paths and writes the result to
+// w. Returns the process exit code.
+func (o *options) runInputs(paths []string, w io.Writer, logger *slog.Logger) int {
+	if err := o.validate(); err != nil {
+		logger.Error("invalid options", "error", err)
+		return exitInvalidArgs
+	}
+
+	csvOpts, err := o.csvOptions()
+	if err != nil {
+		logger.Error("invalid output options", "error", err)
+		return exitInvalidArgs
+	}
+
+	if err = o.makeCacheDir(); err != nil {
+		logger.Error("failed to create cache directory", "dir", o.cacheDir, "error", err)
+		return exitInvalidArgs
+	}
+
 	// Expand paths to get list of files
-	files := expandPaths(args, logger)
+	files, err := o.inputFiles(paths, logger)
+	if err != nil {
+		logger.Error("invalid inputs", "error", err)
+		return exitInvalidArgs
+	}
 
-	if len(files) == 0 {
-		logger.Error("no SBOM files found")
+	// Read the template up front so a bad path fails before processing
+	tmpl, err := o.readTemplate()
+	if err != nil {
+		logger.Error("failed to read template file", "file", o.templateFile, "error", err)
 		return exitInvalidArgs
 	}
 
 	// Process all files using the library
+	o.inputs = len(files)
 	ctx := context.Background()
-	attributions, err := sbomattr.ProcessFiles(ctx, files, logger)
+	var graph *attribution.Graph
+	if o.includeGraph {
+		graph = &attribution.Graph{}
+	}
+	attributions, err := o.processInputs(ctx, files, graph, logger)
 	if err != nil {
 		logger.Error("failed to process SBOM files", "error", err)
 		return exitInvalidSBOM
 	}
 
-	// Output as CSV
-	err = format.CSV(os.Stdout, attributions)
+	return o.emit(ctx, w, attributions, graph, tmpl, csvOpts, logger)
+}
+
+// commands returns the subcommands by name. Each takes the arguments after its name and the output writer, and
+// returns the process exit code.
+func commands() map[string]func(args []string, w io.Writer) int {
+	return map[string]func(args []string, w io.Writer) int{
+		"query":        runQuery,
+		"github-org":   runGitHubOrg,
+		"diff":         runDiff,
+		"merge":        runMerge,
+		"fetch":        runFetch,
+		"verify-urls":  runVerifyURLs,
+		"stats":        runStats,
+		"validate":     runValidate,
+		"capabilities": runCapabilities,
+	}
+}
+
+// processInputs processes the input files. If -lockfiles is set, supported lockfiles are processed as such and
+// aggregated with the SBOMs; otherwise every file is processed as an SBOM. If -manifest is set, the SBOM results are
+// recorded in the manifest, and with -changed-only unchanged SBOMs reuse the results cached by the previous run. With
+// -strict, an SBOM file that cannot be read or parsed fails processing.
+// The graph is optional; if set, it collects the dependencies declared by the SBOMs.
+func (o *options) processInputs(
+	ctx context.Context,
+	files []string,
+	graph *attribution.Graph,
+	logger *slog.Logger,
+) ([]attribution.Attribution, error) {
+	var sbomFiles, lockFiles []string
+	for _, file := range files {
+		if _, ok := lockfileextract.Detect(file); o.lockfiles && ok {
+			lockFiles = append(lockFiles, file)
+		} else {
+			sbomFiles = append(sbomFiles, file)
+		}
+	}
+
+	var audit *attribution.DedupAudit
+	if o.dedupAuditFile != "" {
+		audit = &attribution.DedupAudit{Decisions: []attribution.DedupDecision{}}
+	}
+
+	var all []attribution.Attribution
+	for _, group := range []struct {
+		files   []string
+		process func(context.Context, []string, *slog.Logger) ([]attribution.Attribution, error)
+	}{
+		{
+			files: sbomFiles,
+			process: func(ctx context.Context, files []string, logger *slog.Logger) ([]attribution.Attribution, error) {
+				return o.processSBOMs(ctx, files, audit, graph, logger)
+			},
+		},
+		{files: lockFiles, process: sbomattr.ProcessLockfiles},
+	} {
+		if len(group.files) == 0 {
+			continue
+		}
+		attrs, err := group.process(ctx, group.files, logger)
+		if errors.Is(err, sbomattr.ErrInputFailed) {
+			// Only returned with -strict
+			return nil, err
+		}
+		if err != nil {
+			logger.Error("failed to process files", "files", group.files, "error", err)
+			continue
+		}
+		all = append(all, attrs...)
+	}
+
+	if len(all) == 0 {
+		return nil, errors.New("no attributions extracted from any file")
+	}
+
+	// Sorted like the results of sbomattr.ProcessFiles, so lockfile attributions do not end up after SBOM ones
+	deduplicated := attribution.Sort(attribution.DeduplicateMode(all, o.dedupMode(), audit, logger),
+		attribution.SortOptions{Key: attribution.SortByPurl})
+	if audit != nil {
+		if err := writeJSONFile(o.dedupAuditFile, audit); err != nil {
+			logger.Error("failed to write dedup audit", "file", o.dedupAuditFile, "error", err)
+		}
+	}
+	return deduplicated, nil
+}
+
+// processSBOMs processes SBOM files, incrementally if -manifest is set, writing the outcome of each file to the
+// -file-report file if selected, even if processing fails. The audit and graph are optional; pass nil to disable
+// auditing deduplication decisions and collecting dependencies.
+func (o *options) processSBOMs(
+	ctx context.Context,
+	files []string,
+	audit *attribution.DedupAudit,
+	graph *attribution.Graph,
+	logger *slog.Logger,
+) ([]attribution.Attribution, error) {
+	report := &sbomattr.ProcessReport{}
+	defer o.writeFileReport(report, logger)
+	conflicts := &attribution.LicenseConflictReport{}
+	m := o.loadManifest(logger)
+	processorOpts := append(o.processorOptions(audit, graph, logger),
+		sbomattr.WithProgress(o.progressFunc(os.Stderr)),
+		sbomattr.WithReport(report),
+		sbomattr.WithLicenseConflicts(conflicts),
+		sbomattr.WithManifest(m),
+	)
+
+	attrs, err := sbomattr.NewProcessor(processorOpts...).ProcessFiles(ctx, files)
 	if err != nil {
-		logger.Error("failed to write CSV output", "error", err)
-		return exitRuntimeError
+		return nil, err
+	}
+
+	if m != nil {
+		if saveErr := m.Save(o.manifestFile); saveErr != nil {
+			logger.Error("failed to save manifest", "file", o.manifestFile, "error", saveErr)
+		}
+	}
+	if reportErr := o.reportLicenseConflicts(conflicts, logger); reportErr != nil {
+		logger.Error("failed to write license conflict report", "file", o.conflictsFile, "error", reportErr)
+	}
+	return attrs, nil
+}
+
+// loadManifest returns the manifest the SBOM results are recorded in with -manifest: with -changed-only, the one
+// written by the previous run, if readable; otherwise an empty one. Returns nil without -manifest.
+func (o *options) loadManifest(logger *slog.Logger) *manifest.Manifest {
+	if o.manifestFile == "" {
+		return nil
+	}
+	if !o.changedOnly {
+		return manifest.New()
 	}
 
-	return exitSuccess
+	m, err := manifest.Load(o.manifestFile)
+	if err != nil {
+		logger.Warn("ignoring unreadable manifest, processing every file", "file", o.manifestFile, "error", err)
+		return manifest.New()
+	}
+	return m
+}
+
+// writeJSONFile writes v as indented JSON to the file at path.
+func writeJSONFile(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	const filePerm = 0o644
+	return os.WriteFile(path, append(data, '\n'), filePerm)
+}
+
+// outputFormats returns the names of the supported output formats: the built-in ones, then any registered with
+// format.Register.
+func outputFormats() []string {
+	names := []string{"csv", "tsv", "markdown", "json", "ndjson", "spdx", "spdx-lite", "cyclonedx", "summary"}
+	for _, name := range format.Names() {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// writeOutput writes the attributions to the provided writer in the given output format.
+func writeOutput(
+	w io.Writer,
+	outputFormat string,
+	attributions []attribution.Attribution,
+	csvOpts format.CSVOptions,
+	jsonOpts format.JSONOptions,
+) error {
+	switch outputFormat {
+	case "csv":
+		return format.CSVWithOptions(w, attributions, csvOpts)
+	case "tsv":
+		csvOpts.Delimiter = '\t'
+		return format.CSVWithOptions(w, attributions, csvOpts)
+	case "markdown":
+		return format.Markdown(w, attributions, format.MarkdownOptions{
+			Columns:       csvOpts.Columns,
+			GroupBySource: csvOpts.GroupBySource,
+		})
+	case "json":
+		jsonOpts.GroupBySource = csvOpts.GroupBySource
+		return format.JSONDocument(w, attributions, jsonOpts)
+	case "ndjson":
+		return format.NDJSON(w, attributions)
+	case "spdx":
+		return format.SPDX(w, attributions, format.SPDXOptions{Tool: "sbomattr-" + version, Graph: jsonOpts.Graph})
+	case "spdx-lite":
+		return format.SPDX(w, attributions, format.SPDXOptions{
+			Tool:  "sbomattr-" + version,
+			Lite:  true,
+			Graph: jsonOpts.Graph,
+		})
+	case "cyclonedx":
+		return format.CycloneDX(w, attributions, format.CycloneDXOptions{ToolVersion: version, Graph: jsonOpts.Graph})
+	case "summary":
+		return format.Summary(w, attributions)
+	default:
+		if f, ok := format.Lookup(outputFormat); ok {
+			return f.Format(w, attributions)
+		}
+		return fmt.Errorf("unsupported output format: %s", outputFormat)
+	}
 }
 
 // printUsage prints the usage message to the provided writer.
 func printUsage(w io.Writer, progName string) {
-	fmt.Fprintf(w, "Usage: %s [OPTIONS] <file-or-directory>...\n\n", progName)
+	fmt.Fprintf(w, "Usage: %s [OPTIONS] <file-or-directory>...\n", progName)
+	fmt.Fprintf(w, "       %s query [OPTIONS]\n", progName)
+	fmt.Fprintf(w, "       %s github-org [OPTIONS] <org>\n", progName)
+	fmt.Fprintf(w, "       %s diff [OPTIONS] <old> <new>\n", progName)
+	fmt.Fprintf(w, "       %s merge [OPTIONS] <file-or-directory>...\n", progName)
+	fmt.Fprintf(w, "       %s validate [OPTIONS] <file-or-directory>...\n", progName)
+	fmt.Fprintf(w, "       %s stats [OPTIONS] <file-or-directory>...\n", progName)
+	fmt.Fprintf(w, "       %s fetch [OPTIONS] <source>...\n", progName)
+	fmt.Fprintf(w, "       %s verify-urls [OPTIONS] <attribution-file>...\n", progName)
+	fmt.Fprintf(w, "       %s capabilities\n\n", progName)
 	fmt.Fprintf(w, "Create an aggregated notice for one or more SBOMs.\n\n")
 	fmt.Fprintf(w, "Arguments:\n")
 	fmt.Fprintf(w, "  file-or-directory   SBOM files or directories containing SBOM files\n\n")
+	fmt.Fprintf(w, "Commands:\n")
+	fmt.Fprintf(w, "  query               Search results saved with -store (\"query -h\" for options)\n")
+	fmt.Fprintf(w, "  github-org          Aggregate a GitHub organization's dependency graphs (\"github-org -h\")\n")
+	fmt.Fprintf(w, "  diff                Compare the packages of two SBOM sets (\"diff -h\" for options)\n")
+	fmt.Fprintf(w, "  merge               Merge SBOMs into a single SPDX or CycloneDX SBOM (\"merge -h\")\n")
+	fmt.Fprintf(w, "  validate            Check SBOMs against their schemas and lint packages (\"validate -h\")\n")
+	fmt.Fprintf(w, "  stats               Print file, license, and ecosystem statistics of SBOMs (\"stats -h\")\n")
+	fmt.Fprintf(w, "  fetch               Download and process SBOMs from GitHub, registries, or URLs (\"fetch -h\")\n")
+	fmt.Fprintf(w, "  verify-urls         Report dead URLs in JSON or CSV attribution files (\"verify-urls -h\")\n")
+	fmt.Fprintf(w, "  capabilities        Print the supported formats, commands, and limits as JSON\n\n")
 	fmt.Fprintf(w, "Options:\n")
 	flag.PrintDefaults()
 }
@@ -116,8 +382,27 @@ func setupLogger(verbose bool) *slog.Logger {
 	}))
 }
 
+// inputFiles returns the files of the input paths (see expandPaths). Returns an error if no file is found, or with
+// -strict if a path cannot be accessed.
+func (o *options) inputFiles(paths []string, logger *slog.Logger) ([]string, error) {
+	if o.strict {
+		for _, path := range paths {
+			if _, err := os.Stat(path); err != nil {
+				return nil, fmt.Errorf("cannot access path with -strict: %w", err)
+			}
+		}
+	}
+
+	files := expandPaths(paths, o.lockfiles, logger)
+	if len(files) == 0 {
+		return nil, errors.New("no SBOM files found")
+	}
+	return files, nil
+}
+
 // expandPaths takes a mix of files and directories and returns a list of SBOM file paths.
-func expandPaths(paths []string, logger *slog.Logger) []string {
+// If lockfiles is true, supported lockfiles found in directories are included too.
+func expandPaths(paths []string, lockfiles bool, logger *slog.Logger) []string {
 	var files []string
 
 	for _, path := range paths {
@@ -139,8 +424,9 @@ func expandPaths(paths []string, logger *slog.Logger) []string {
 				if entry.IsDir() {
 					continue
 				}
-				// Only consider JSON files (SBOM files are typically JSON)
-				if strings.HasSuffix(entry.Name(), ".json") {
+				// Only consider JSON files (SBOM files are typically JSON), and lockfiles if requested
+				_, isLockfile := lockfileextract.Detect(entry.Name())
+				if strings.HasSuffix(entry.Name(), ".json") || (lockfiles && isLockfile) {
 					files = append(files, filepath.Join(path, entry.Name()))
 				}
 			}
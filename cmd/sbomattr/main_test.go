@@ -2,13 +2,30 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
 	"flag"
+	"fmt"
 	"io"
 	"log/slog"
+	"maps"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"testing"
+
+	"github.com/boringbin/sbomattr"
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/format"
+	"github.com/boringbin/sbomattr/manifest"
+	"github.com/boringbin/sbomattr/notify"
+	"github.com/boringbin/sbomattr/store"
 )
 
 // TestPrintUsage tests the printUsage function.
@@ -84,7 +101,7 @@ func TestExpandPaths_SingleFile(t *testing.T) {
 	tmpFile.Close()
 
 	logger := setupLogger(false)
-	files := expandPaths([]string{tmpFile.Name()}, logger)
+	files := expandPaths([]string{tmpFile.Name()}, false, logger)
 
 	if len(files) != 1 {
 		t.Errorf("expandPaths() returned %d files, want 1", len(files))
@@ -114,7 +131,7 @@ func TestExpandPaths_Directory(t *testing.T) {
 	}
 
 	logger := setupLogger(false)
-	files := expandPaths([]string{tmpDir}, logger)
+	files := expandPaths([]string{tmpDir}, false, logger)
 
 	// Should only include .json files
 	expectedCount := 2
@@ -142,7 +159,7 @@ func TestExpandPaths_NonExistentPath(t *testing.T) {
 	t.Parallel()
 
 	logger := setupLogger(false)
-	files := expandPaths([]string{"/nonexistent/path/to/file.json"}, logger)
+	files := expandPaths([]string{"/nonexistent/path/to/file.json"}, false, logger)
 
 	// Should return empty slice for non-existent paths
 	if len(files) != 0 {
@@ -158,7 +175,7 @@ func TestExpandPaths_EmptyDirectory(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	logger := setupLogger(false)
-	files := expandPaths([]string{tmpDir}, logger)
+	files := expandPaths([]string{tmpDir}, false, logger)
 
 	if len(files) != 0 {
 		t.Errorf("expandPaths() with empty directory returned %d files, want 0", len(files))
@@ -187,7 +204,7 @@ func TestExpandPaths_MixedPaths(t *testing.T) {
 	tmpFile.Close()
 
 	logger := setupLogger(false)
-	files := expandPaths([]string{tmpDir, tmpFile.Name()}, logger)
+	files := expandPaths([]string{tmpDir, tmpFile.Name()}, false, logger)
 
 	// Should return both the file from directory and the standalone file
 	expectedCount := 2
@@ -586,7 +603,7 @@ func TestExpandPaths_DirectoryWithSubdirectories(t *testing.T) {
 	}
 
 	logger := setupLogger(false)
-	files := expandPaths([]string{tmpDir}, logger)
+	files := expandPaths([]string{tmpDir}, false, logger)
 
 	// Should only include root.json, not sub.json (non-recursive)
 	if len(files) != 1 {
@@ -644,3 +661,1351 @@ func TestRun_NoFilesFoundAfterExpansion(t *testing.T) {
 		t.Errorf("run() stderr should mention no SBOM files found, got: %s", output)
 	}
 }
+
+// TestRun_Template tests the run function with the --template flag.
+func TestRun_Template(t *testing.T) {
+	// Note: Cannot use t.Parallel() because run() modifies global flag.CommandLine
+
+	// Save and restore os.Args and flag.CommandLine
+	oldArgs := os.Args
+	oldCommandLine := flag.CommandLine
+	t.Cleanup(func() {
+		os.Args = oldArgs
+		flag.CommandLine = oldCommandLine
+	})
+
+	// Reset flag.CommandLine for this test
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+
+	// Create a template file
+	tmplFile := filepath.Join(t.TempDir(), "notice.tmpl")
+	tmpl := "ACME Corp Third-Party Notices\n{{range .}}* {{.Name}}{{\"\\n\"}}{{end}}"
+	if createErr := os.WriteFile(tmplFile, []byte(tmpl), 0600); createErr != nil {
+		t.Fatalf("failed to create template file: %v", createErr)
+	}
+
+	os.Args = []string{"sbomattr", "--template", tmplFile, "../../testdata/example-spdx.json"}
+
+	// Capture stdout
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	exitCode := run()
+
+	_ = w.Close()
+	os.Stdout = oldStdout
+
+	if exitCode != exitSuccess {
+		t.Errorf("run() with template returned exit code %d, want %d", exitCode, exitSuccess)
+	}
+
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r)
+	output := buf.String()
+
+	if !strings.HasPrefix(output, "ACME Corp Third-Party Notices\n* ") {
+		t.Errorf("run() output should be rendered from template, got: %s", output)
+	}
+	if strings.Contains(output, "Name,License,Purl,URL") {
+		t.Errorf("run() output should not contain CSV header when using a template, got: %s", output)
+	}
+}
+
+// TestRun_TemplateMissingFile tests the run function with a non-existent template file.
+func TestRun_TemplateMissingFile(t *testing.T) {
+	// Note: Cannot use t.Parallel() because run() modifies global flag.CommandLine
+
+	// Save and restore os.Args and flag.CommandLine
+	oldArgs := os.Args
+	oldCommandLine := flag.CommandLine
+	t.Cleanup(func() {
+		os.Args = oldArgs
+		flag.CommandLine = oldCommandLine
+	})
+
+	// Reset flag.CommandLine for this test
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+
+	os.Args = []string{"sbomattr", "--template", "/nonexistent/notice.tmpl", "../../testdata/example-spdx.json"}
+
+	// Capture stderr
+	oldStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stderr = w
+
+	exitCode := run()
+
+	_ = w.Close()
+	os.Stderr = oldStderr
+
+	if exitCode != exitInvalidArgs {
+		t.Errorf("run() with missing template returned exit code %d, want %d", exitCode, exitInvalidArgs)
+	}
+}
+
+// TestRun_SplitExceptions tests the run function with the --split-exceptions flag.
+func TestRun_SplitExceptions(t *testing.T) {
+	// Note: Cannot use t.Parallel() because run() modifies global flag.CommandLine
+
+	// Save and restore os.Args and flag.CommandLine
+	oldArgs := os.Args
+	oldCommandLine := flag.CommandLine
+	t.Cleanup(func() {
+		os.Args = oldArgs
+		flag.CommandLine = oldCommandLine
+	})
+
+	// Reset flag.CommandLine for this test
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+
+	// Create an SBOM with a license exception
+	sbomFile := filepath.Join(t.TempDir(), "sbom.json")
+	sbomData := `{"spdxVersion": "SPDX-2.3", "packages": [` +
+		`{"name": "llvm", "licenseConcluded": "Apache-2.0 WITH LLVM-exception"}]}`
+	if createErr := os.WriteFile(sbomFile, []byte(sbomData), 0600); createErr != nil {
+		t.Fatalf("failed to create SBOM file: %v", createErr)
+	}
+
+	os.Args = []string{"sbomattr", "--split-exceptions", sbomFile}
+
+	// Capture stdout
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	exitCode := run()
+
+	_ = w.Close()
+	os.Stdout = oldStdout
+
+	if exitCode != exitSuccess {
+		t.Errorf("run() with split exceptions returned exit code %d, want %d", exitCode, exitSuccess)
+	}
+
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r)
+	output := buf.String()
+
+	want := "Name,License,Exception,Purl,URL\nllvm,Apache-2.0,LLVM-exception,,\n"
+	if output != want {
+		t.Errorf("run() output = %q, want %q", output, want)
+	}
+}
+
+// TestRun_FormatSPDX tests the run function with the --format spdx flag.
+func TestRun_FormatSPDX(t *testing.T) {
+	// Note: Cannot use t.Parallel() because run() modifies global flag.CommandLine
+
+	// Save and restore os.Args and flag.CommandLine
+	oldArgs := os.Args
+	oldCommandLine := flag.CommandLine
+	t.Cleanup(func() {
+		os.Args = oldArgs
+		flag.CommandLine = oldCommandLine
+	})
+
+	// Reset flag.CommandLine for this test
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+
+	os.Args = []string{"sbomattr", "--format", "spdx", "../../testdata/example-cyclonedx.json"}
+
+	// Capture stdout
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	exitCode := run()
+
+	_ = w.Close()
+	os.Stdout = oldStdout
+
+	if exitCode != exitSuccess {
+		t.Errorf("run() with SPDX format returned exit code %d, want %d", exitCode, exitSuccess)
+	}
+
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r)
+	output := buf.String()
+
+	if !strings.Contains(output, `"spdxVersion": "SPDX-2.3"`) {
+		t.Errorf("run() output should be an SPDX document, got: %s", output)
+	}
+	if !strings.Contains(output, `"Tool: sbomattr-dev"`) {
+		t.Errorf("run() output should name the creator tool, got: %s", output)
+	}
+}
+
+// TestRun_InvalidFormat tests the run function with an unsupported --format value.
+func TestRun_InvalidFormat(t *testing.T) {
+	// Note: Cannot use t.Parallel() because run() modifies global flag.CommandLine
+
+	// Save and restore os.Args and flag.CommandLine
+	oldArgs := os.Args
+	oldCommandLine := flag.CommandLine
+	t.Cleanup(func() {
+		os.Args = oldArgs
+		flag.CommandLine = oldCommandLine
+	})
+
+	// Reset flag.CommandLine for this test
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+
+	os.Args = []string{"sbomattr", "--format", "xml", "../../testdata/example-spdx.json"}
+
+	// Capture stderr
+	oldStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	exitCode := run()
+
+	_ = w.Close()
+	os.Stderr = oldStderr
+
+	if exitCode != exitInvalidArgs {
+		t.Errorf("run() with invalid format returned exit code %d, want %d", exitCode, exitInvalidArgs)
+	}
+
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r)
+	if !strings.Contains(buf.String(), "unsupported output format") {
+		t.Errorf("run() stderr should mention unsupported output format, got: %s", buf.String())
+	}
+}
+
+// TestWriteOutput tests the writeOutput function with each supported output format.
+func TestWriteOutput(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{format: "csv", want: "Name,License,Purl,URL"},
+		{format: "tsv", want: "Name\tLicense\tPurl\tURL"},
+		{format: "markdown", want: "| Name | License | Purl | URL |"},
+		{format: "json", want: `"name": "lodash"`},
+		{format: "ndjson", want: `{"name":"lodash",`},
+		{format: "spdx", want: `"spdxVersion": "SPDX-2.3"`},
+		{format: "spdx-lite", want: `"filesAnalyzed": false`},
+		{format: "cyclonedx", want: `"bomFormat": "CycloneDX"`},
+		{format: "summary", want: "Packages:"},
+	}
+
+	attrs := []attribution.Attribution{{Name: "lodash", Purl: "pkg:npm/lodash@4.17.21"}}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := writeOutput(&buf, tt.format, attrs, format.CSVOptions{}, format.JSONOptions{}); err != nil {
+				t.Fatalf("writeOutput() unexpected error: %v", err)
+			}
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("writeOutput() output missing %q, got: %s", tt.want, buf.String())
+			}
+		})
+	}
+}
+
+// TestWriteOutput_Registered tests that formatters registered with format.Register are selectable.
+func TestWriteOutput_Registered(t *testing.T) {
+	t.Parallel()
+
+	// The registry is global, so the name is unique per run for the test to pass with -count
+	name := "test-purls-" + rand.Text()
+	format.Register(name, format.FormatterFunc(func(w io.Writer, attrs []attribution.Attribution) error {
+		for _, a := range attrs {
+			fmt.Fprintln(w, a.Purl)
+		}
+		return nil
+	}))
+
+	if !slices.Contains(outputFormats(), name) {
+		t.Fatalf("outputFormats() = %v, want it to contain %s", outputFormats(), name)
+	}
+
+	var buf bytes.Buffer
+	attrs := []attribution.Attribution{{Name: "lodash", Purl: "pkg:npm/lodash@4.17.21"}}
+	if err := writeOutput(&buf, name, attrs, format.CSVOptions{}, format.JSONOptions{}); err != nil {
+		t.Fatalf("writeOutput() unexpected error: %v", err)
+	}
+	if want := "pkg:npm/lodash@4.17.21\n"; buf.String() != want {
+		t.Errorf("writeOutput() = %q, want %q", buf.String(), want)
+	}
+
+	if err := writeOutput(&buf, "unknown", attrs, format.CSVOptions{}, format.JSONOptions{}); err == nil {
+		t.Error("writeOutput() error = nil, want error for unknown format")
+	}
+}
+
+// TestParseDelimiter tests the parseDelimiter function.
+func TestParseDelimiter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    rune
+		wantErr bool
+	}{
+		{input: ",", want: ','},
+		{input: ";", want: ';'},
+		{input: "tab", want: '\t'},
+		{input: `\t`, want: '\t'},
+		{input: "\t", want: '\t'},
+		{input: "", wantErr: true},
+		{input: ";;", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseDelimiter(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDelimiter(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseDelimiter(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestOptions_CSVOptions tests building CSV options from the command-line flags.
+func TestOptions_CSVOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantColumns []string
+		wantErr     bool
+	}{
+		{name: "defaults", args: nil, wantColumns: nil},
+		{name: "columns", args: []string{"-columns", "name,version,license,purl"},
+			wantColumns: []string{"name", "version", "license", "purl"}},
+		{name: "unknown column", args: []string{"-columns", "name,copyright"}, wantErr: true},
+		{name: "invalid delimiter", args: []string{"-delimiter", ";;"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := flag.NewFlagSet("sbomattr", flag.ContinueOnError)
+			opts := registerFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+
+			csvOpts, err := opts.csvOptions()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("csvOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(csvOpts.Columns, tt.wantColumns) {
+				t.Errorf("csvOptions().Columns = %v, want %v", csvOpts.Columns, tt.wantColumns)
+			}
+		})
+	}
+}
+
+// TestRun_Store tests the run function with the --store flag.
+func TestRun_Store(t *testing.T) {
+	// Note: Cannot use t.Parallel() because run() modifies global flag.CommandLine
+
+	// Save and restore os.Args and flag.CommandLine
+	oldArgs := os.Args
+	oldCommandLine := flag.CommandLine
+	t.Cleanup(func() {
+		os.Args = oldArgs
+		flag.CommandLine = oldCommandLine
+	})
+
+	// Reset flag.CommandLine for this test
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+
+	storeDir := t.TempDir()
+	os.Args = []string{
+		"sbomattr", "--store", storeDir, "--product", "acme", "--product-version", "v1.0.0",
+		"../../testdata/example-spdx.json",
+	}
+
+	// Discard stdout
+	oldStdout := os.Stdout
+	devNull, _ := os.Open(os.DevNull)
+	os.Stdout = devNull
+	exitCode := run()
+	os.Stdout = oldStdout
+	_ = devNull.Close()
+
+	if exitCode != exitSuccess {
+		t.Fatalf("run() with store returned exit code %d, want %d", exitCode, exitSuccess)
+	}
+
+	runs, err := store.NewFileStore(storeDir).Runs(context.Background(), "acme")
+	if err != nil {
+		t.Fatalf("Runs() error = %v", err)
+	}
+	if len(runs) != 1 || runs[0].Version != "v1.0.0" || len(runs[0].Attributions) == 0 {
+		t.Errorf("Runs() = %+v, want one v1.0.0 run with attributions", runs)
+	}
+}
+
+// TestRun_StoreWithoutProduct tests that --store requires --product.
+func TestRun_StoreWithoutProduct(t *testing.T) {
+	// Note: Cannot use t.Parallel() because run() modifies global flag.CommandLine
+
+	// Save and restore os.Args and flag.CommandLine
+	oldArgs := os.Args
+	oldCommandLine := flag.CommandLine
+	t.Cleanup(func() {
+		os.Args = oldArgs
+		flag.CommandLine = oldCommandLine
+	})
+
+	// Reset flag.CommandLine for this test
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+
+	os.Args = []string{"sbomattr", "--store", t.TempDir(), "../../testdata/example-spdx.json"}
+
+	if exitCode := run(); exitCode != exitInvalidArgs {
+		t.Errorf("run() without product returned exit code %d, want %d", exitCode, exitInvalidArgs)
+	}
+}
+
+// TestRun_Pins tests that deviations from the -pins file fail the run, unless -pins-warn is set.
+func TestRun_Pins(t *testing.T) {
+	// Note: Cannot use t.Parallel() because run() modifies global flag.CommandLine
+
+	// Save and restore os.Args and flag.CommandLine
+	oldArgs := os.Args
+	oldCommandLine := flag.CommandLine
+	t.Cleanup(func() {
+		os.Args = oldArgs
+		flag.CommandLine = oldCommandLine
+	})
+
+	pinsFile := filepath.Join(t.TempDir(), "pins.json")
+	pins := `{"pins": [{"purl": "pkg:npm/lodash@4.17.21", "license": "Apache-2.0"}]}`
+	if err := os.WriteFile(pinsFile, []byte(pins), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	// Discard stdout
+	oldStdout := os.Stdout
+	devNull, _ := os.Open(os.DevNull)
+	os.Stdout = devNull
+	t.Cleanup(func() {
+		os.Stdout = oldStdout
+		_ = devNull.Close()
+	})
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "deviation", args: []string{"--pins", pinsFile}, want: exitPinDeviation},
+		{name: "warn", args: []string{"--pins", pinsFile, "--pins-warn"}, want: exitSuccess},
+		{name: "missing pins file", args: []string{"--pins", pinsFile + ".missing"}, want: exitInvalidArgs},
+	}
+
+	for _, tt := range tests {
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+		os.Args = append(append([]string{"sbomattr"}, tt.args...), "../../testdata/example-spdx.json")
+
+		if exitCode := run(); exitCode != tt.want {
+			t.Errorf("%s: run() returned exit code %d, want %d", tt.name, exitCode, tt.want)
+		}
+	}
+}
+
+// TestRun_Corrections tests that the -corrections file overrides extracted licenses, and that an unreadable file is an
+// invalid argument.
+func TestRun_Corrections(t *testing.T) {
+	// Note: Cannot use t.Parallel() because run() modifies global flag.CommandLine
+
+	// Save and restore os.Args and flag.CommandLine
+	oldArgs := os.Args
+	oldCommandLine := flag.CommandLine
+	t.Cleanup(func() {
+		os.Args = oldArgs
+		flag.CommandLine = oldCommandLine
+	})
+
+	correctionsFile := filepath.Join(t.TempDir(), "corrections.json")
+	corrections := `{"corrections": [{"purl": "pkg:npm/lodash", "license": "CC0-1.0", "reason": "relicensed"}]}`
+	if err := os.WriteFile(correctionsFile, []byte(corrections), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	os.Args = []string{
+		"sbomattr", "-format", "json", "-corrections", correctionsFile, "../../testdata/example-spdx.json",
+	}
+
+	// Capture stdout
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	exitCode := run()
+
+	_ = w.Close()
+	os.Stdout = oldStdout
+
+	if exitCode != exitSuccess {
+		t.Fatalf("run() returned exit code %d, want %d", exitCode, exitSuccess)
+	}
+
+	var doc format.Document
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	i := slices.IndexFunc(doc.Attributions, func(a attribution.Attribution) bool { return a.Name == "lodash" })
+	if i < 0 {
+		t.Fatal("output has no lodash attribution")
+	}
+	lodash := doc.Attributions[i]
+	if lodash.License == nil || *lodash.License != "CC0-1.0" ||
+		lodash.Provenance[attribution.FieldLicense] != attribution.ProvenanceOverridden {
+		t.Errorf("lodash license = %v (%s), want the overridden CC0-1.0", lodash.License,
+			lodash.Provenance[attribution.FieldLicense])
+	}
+
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	os.Args = []string{"sbomattr", "-corrections", correctionsFile + ".missing", "../../testdata/example-spdx.json"}
+	if exitCode = run(); exitCode != exitInvalidArgs {
+		t.Errorf("run() with a missing corrections file returned exit code %d, want %d", exitCode, exitInvalidArgs)
+	}
+}
+
+// TestRun_Forbid tests that packages with a license of a -forbid category fail the run.
+func TestRun_Forbid(t *testing.T) {
+	// Note: Cannot use t.Parallel() because run() modifies global flag.CommandLine
+
+	// Save and restore os.Args and flag.CommandLine
+	oldArgs := os.Args
+	oldCommandLine := flag.CommandLine
+	t.Cleanup(func() {
+		os.Args = oldArgs
+		flag.CommandLine = oldCommandLine
+	})
+
+	// Discard stdout
+	oldStdout := os.Stdout
+	devNull, _ := os.Open(os.DevNull)
+	os.Stdout = devNull
+	t.Cleanup(func() {
+		os.Stdout = oldStdout
+		_ = devNull.Close()
+	})
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "no copyleft", args: []string{"--forbid", "copyleft"}, want: exitSuccess},
+		{name: "permissive", args: []string{"--forbid", "copyleft,permissive"}, want: exitForbiddenLicense},
+		{name: "unknown category", args: []string{"--forbid", "gpl"}, want: exitInvalidArgs},
+	}
+
+	for _, tt := range tests {
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+		os.Args = append(append([]string{"sbomattr"}, tt.args...), "../../testdata/example-spdx.json")
+
+		if exitCode := run(); exitCode != tt.want {
+			t.Errorf("%s: run() returned exit code %d, want %d", tt.name, exitCode, tt.want)
+		}
+	}
+}
+
+// TestRun_IncludeGraph tests that -include-graph embeds the dependency graph in JSON output.
+func TestRun_IncludeGraph(t *testing.T) {
+	// Note: Cannot use t.Parallel() because run() modifies global flag.CommandLine
+
+	// Save and restore os.Args and flag.CommandLine
+	oldArgs := os.Args
+	oldCommandLine := flag.CommandLine
+	t.Cleanup(func() {
+		os.Args = oldArgs
+		flag.CommandLine = oldCommandLine
+	})
+
+	sbomFile := filepath.Join(t.TempDir(), "bom.json")
+	bom := `{"bomFormat": "CycloneDX", "specVersion": "1.5", ` +
+		`"metadata": {"component": {"bom-ref": "app", "name": "app", "purl": "pkg:npm/app@1.0.0"}}, ` +
+		`"components": [{"bom-ref": "express", "name": "express", "purl": "pkg:npm/express@4.18.2"}, ` +
+		`{"bom-ref": "body-parser", "name": "body-parser", "purl": "pkg:npm/body-parser@1.20.1"}], ` +
+		`"dependencies": [{"ref": "app", "dependsOn": ["express"]}, {"ref": "express", "dependsOn": ["body-parser"]}]}`
+	if err := os.WriteFile(sbomFile, []byte(bom), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	os.Args = []string{"sbomattr", "-format", "json", "-include-graph", "-root-component", "include", sbomFile}
+
+	// Capture stdout
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	exitCode := run()
+
+	_ = w.Close()
+	os.Stdout = oldStdout
+
+	if exitCode != exitSuccess {
+		t.Fatalf("run() returned exit code %d, want %d", exitCode, exitSuccess)
+	}
+
+	var doc format.Document
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if len(doc.Attributions) != 3 {
+		t.Errorf("output has %d attributions, want 3", len(doc.Attributions))
+	}
+	want := []attribution.Edge{
+		{From: "pkg:npm/app@1.0.0", To: "pkg:npm/express@4.18.2"},
+		{From: "pkg:npm/express@4.18.2", To: "pkg:npm/body-parser@1.20.1"},
+	}
+	if doc.Graph == nil || !slices.Equal(doc.Graph.Edges, want) {
+		t.Errorf("output graph = %v, want %v", doc.Graph.Edges, want)
+	}
+}
+
+// TestOptions_Validate tests the validate method.
+func TestOptions_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "defaults", args: nil},
+		{name: "sort", args: []string{"-sort", "License", "-sort-ignore-case"}},
+		{name: "unknown sort key", args: []string{"-sort", "size"}, wantErr: true},
+		{name: "unknown format", args: []string{"-format", "xml"}, wantErr: true},
+		{name: "store without product", args: []string{"-store", "history"}, wantErr: true},
+		{name: "store with product", args: []string{"-store", "history", "-product", "acme"}},
+		{name: "webhook without store", args: []string{"-webhook", "http://example.com", "-deny-licenses", "GPL"},
+			wantErr: true},
+		{name: "group by source", args: []string{"-group-by-source", "-format", "markdown"}},
+		{name: "group by source with spdx", args: []string{"-group-by-source", "-format", "spdx"}, wantErr: true},
+		{name: "group by source with template", args: []string{"-group-by-source", "-template", "t.tmpl"},
+			wantErr: true},
+		{name: "columns with markdown", args: []string{"-columns", "name,purl", "-format", "markdown"}},
+		{name: "columns with json", args: []string{"-columns", "name,purl", "-format", "json"}, wantErr: true},
+		{name: "license details with markdown", args: []string{"-license-details", "-format", "markdown"},
+			wantErr: true},
+		{name: "delimiter", args: []string{"-delimiter", ";"}},
+		{name: "delimiter with tsv", args: []string{"-delimiter", ";", "-format", "tsv"}, wantErr: true},
+		{name: "crlf with tsv", args: []string{"-crlf", "-format", "tsv"}},
+		{name: "crlf with summary", args: []string{"-crlf", "-format", "summary"}, wantErr: true},
+		{name: "unknown webhook format", args: []string{"-webhook-format", "teams"}, wantErr: true},
+		{name: "changed only without manifest", args: []string{"-changed-only"}, wantErr: true},
+		{name: "changed only", args: []string{"-changed-only", "-manifest", "manifest.json"}},
+		{name: "skip scopes", args: []string{"-skip-scopes", "Excluded, optional"}},
+		{name: "unknown skip scope", args: []string{"-skip-scopes", "dev"}, wantErr: true},
+		{name: "correlate repos", args: []string{"-correlate-repos", "merge"}},
+		{name: "unknown correlate repos mode", args: []string{"-correlate-repos", "join"}, wantErr: true},
+		{name: "keep first party without rules", args: []string{"-keep-first-party"}, wantErr: true},
+		{name: "keep first party", args: []string{"-keep-first-party", "-first-party-supplier", "Acme"}},
+		{name: "keep first party by name", args: []string{"-keep-first-party", "-first-party-name", "acme-*"}},
+		{name: "root component", args: []string{"-root-component", "Exclude"}},
+		{name: "unknown root component mode", args: []string{"-root-component", "skip"}, wantErr: true},
+		{name: "spdx relationships", args: []string{"-spdx-relationships", "depends-on"}},
+		{name: "unknown spdx relationships filter", args: []string{"-spdx-relationships", "contains"}, wantErr: true},
+		{name: "cyclonedx dependencies", args: []string{"-cyclonedx-dependencies", "direct"}},
+		{name: "unknown cyclonedx dependencies filter", args: []string{"-cyclonedx-dependencies", "all"},
+			wantErr: true},
+		{name: "pins warn without pins", args: []string{"-pins-warn"}, wantErr: true},
+		{name: "pins warn", args: []string{"-pins", "pins.json", "-pins-warn"}},
+		{name: "verify urls", args: []string{"-verify-urls", "-verify-urls-fallback", "-verify-urls-cache", "c.json"}},
+		{name: "verify urls fallback without verify", args: []string{"-verify-urls-fallback"}, wantErr: true},
+		{name: "fetch license texts", args: []string{"-fetch-license-texts", "-license-text-cache", "t.json"}},
+		{name: "license text cache without fetch", args: []string{"-license-text-cache", "t.json"}, wantErr: true},
+		{name: "forbid", args: []string{"-forbid", "copyleft, weak-copyleft,UNKNOWN"}},
+		{name: "unknown forbid category", args: []string{"-forbid", "proprietary"}, wantErr: true},
+		{name: "enrich", args: []string{"-enrich", "npm, PyPI"}},
+		{name: "enrich all", args: []string{"-enrich", "all"}},
+		{name: "cache dir", args: []string{"-cache-dir", ".cache", "-cache-ttl", "24h"}},
+		{
+			name: "cache ttl of a cache file",
+			args: []string{"-verify-urls", "-verify-urls-cache", "c.json", "-cache-ttl", "1h"},
+		},
+		{name: "cache ttl without cache", args: []string{"-cache-ttl", "24h"}, wantErr: true},
+		{name: "offline", args: []string{"-offline", "-enrich", "all", "-verify-urls", "-cache-dir", ".cache"}},
+		{
+			name: "offline webhook",
+			args: []string{
+				"-offline", "-webhook", "https://hooks.example.com", "-store", "s", "-product", "p",
+				"-deny-licenses", "GPL",
+			},
+			wantErr: true,
+		},
+		{name: "negative cache ttl", args: []string{"-cache-dir", ".cache", "-cache-ttl", "-1h"}, wantErr: true},
+		{name: "unsupported enrich type", args: []string{"-enrich", "npm,maven"}, wantErr: true},
+		{name: "jobs", args: []string{"-jobs", "8"}},
+		{name: "dedup per package", args: []string{"-dedup", "package"}},
+		{name: "unknown dedup mode", args: []string{"-dedup", "purl"}, wantErr: true},
+		{name: "negative jobs", args: []string{"-jobs", "-1"}, wantErr: true},
+		{name: "negative verify urls rate", args: []string{"-verify-urls", "-verify-urls-rate", "-1"}, wantErr: true},
+		{name: "include graph", args: []string{"-include-graph", "-format", "json"}},
+		{name: "include graph with csv", args: []string{"-include-graph"}, wantErr: true},
+		{name: "preview", args: []string{"-preview", "10"}},
+		{name: "negative preview", args: []string{"-preview", "-1"}, wantErr: true},
+		{name: "preview with store", args: []string{"-preview", "10", "-store", "history", "-product", "acme"},
+			wantErr: true},
+		{name: "reproducible", args: []string{"-reproducible", "-format", "json"}},
+		{name: "reproducible with csv", args: []string{"-reproducible"}, wantErr: true},
+		{name: "include graph grouped by source",
+			args: []string{"-include-graph", "-format", "json", "-group-by-source"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := flag.NewFlagSet("sbomattr", flag.ContinueOnError)
+			opts := registerFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+
+			if err := opts.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestOptions_TransformSort tests that the transform method sorts when -sort is set.
+func TestOptions_TransformSort(t *testing.T) {
+	t.Parallel()
+
+	fs := flag.NewFlagSet("sbomattr", flag.ContinueOnError)
+	opts := registerFlags(fs)
+	if err := fs.Parse([]string{"-sort", "name", "-sort-ignore-case"}); err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+
+	result := opts.transform([]attribution.Attribution{{Name: "zlib"}, {Name: "Babel"}, {Name: "acorn"}}, nil)
+
+	var got []string
+	for _, a := range result {
+		got = append(got, a.Name)
+	}
+	if want := []string{"acorn", "Babel", "zlib"}; !slices.Equal(got, want) {
+		t.Errorf("transform() = %v, want %v", got, want)
+	}
+}
+
+// TestOptions_JSONOptions tests that the JSON output metadata records the flags set, with secrets redacted, and
+// omits the timestamp with -reproducible.
+func TestOptions_JSONOptions(t *testing.T) {
+	t.Parallel()
+
+	fs := flag.NewFlagSet("sbomattr", flag.ContinueOnError)
+	opts := registerFlags(fs)
+	args := []string{
+		"-format", "json", "-sort", "name", "-webhook", "https://hooks.example.com/secret", "-reproducible",
+	}
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+	opts.inputs = 2
+
+	got := opts.jsonOptions(nil).Metadata
+	if got.Tool != "sbomattr" || got.Version != version || got.Inputs != 2 || got.Timestamp != "" {
+		t.Errorf("jsonOptions() metadata = %+v, want sbomattr %s with 2 inputs and no timestamp", got, version)
+	}
+	want := map[string]string{"format": "json", "sort": "name", "webhook": "REDACTED", "reproducible": "true"}
+	if !maps.Equal(got.Options, want) {
+		t.Errorf("jsonOptions() options = %v, want %v", got.Options, want)
+	}
+
+	opts.reproducible = false
+	if got = opts.jsonOptions(nil).Metadata; got.Timestamp == "" {
+		t.Error("jsonOptions() timestamp is empty, want the current time without -reproducible")
+	}
+}
+
+// TestOptions_ProgressFunc tests that -progress writes a line per processed SBOM file.
+func TestOptions_ProgressFunc(t *testing.T) {
+	t.Parallel()
+
+	if (&options{}).progressFunc(io.Discard) != nil {
+		t.Error("progressFunc() without -progress is not nil")
+	}
+
+	var buf bytes.Buffer
+	report := (&options{progress: true}).progressFunc(&buf)
+	report(sbomattr.Progress{Files: 2})
+	report(sbomattr.Progress{Files: 2, Processed: 1, Attributions: 5, File: "a.json"})
+
+	want := "processing 2 SBOM files\n[1/2] a.json (5 attributions so far)\n"
+	if got := buf.String(); got != want {
+		t.Errorf("progress output = %q, want %q", got, want)
+	}
+}
+
+// TestOptions_EmitPreview tests that -preview writes the first attributions followed by the summary statistics of
+// all of them.
+func TestOptions_EmitPreview(t *testing.T) {
+	t.Parallel()
+
+	mit := "MIT"
+	attrs := []attribution.Attribution{
+		{Name: "lodash", License: &mit, Purl: "pkg:npm/lodash@4.17.21"},
+		{Name: "react", Purl: "pkg:npm/react@18.2.0"},
+		{Name: "express", Purl: "pkg:npm/express@4.18.2"},
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "csv",
+			args:    []string{"-preview", "1"},
+			want:    []string{"lodash", "Showing 1 of 3 attributions.", "Packages:", "npm  3"},
+			notWant: []string{"react", "express"},
+		},
+		{
+			name: "more than available",
+			args: []string{"-preview", "5"},
+			want: []string{"lodash", "react", "express", "Showing 3 of 3 attributions."},
+		},
+		{
+			name:    "summary",
+			args:    []string{"-preview", "1", "-format", "summary"},
+			want:    []string{"Packages:", "npm  3"},
+			notWant: []string{"Showing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := flag.NewFlagSet("sbomattr", flag.ContinueOnError)
+			opts := registerFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+
+			var buf bytes.Buffer
+			logger := slog.New(slog.DiscardHandler)
+			code := opts.emit(context.Background(), &buf, attrs, nil, "", format.CSVOptions{}, logger)
+			if code != exitSuccess {
+				t.Fatalf("emit() = %d, want %d", code, exitSuccess)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("emit() output missing %q, got:\n%s", want, buf.String())
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(buf.String(), notWant) {
+					t.Errorf("emit() output contains %q, got:\n%s", notWant, buf.String())
+				}
+			}
+		})
+	}
+}
+
+// TestOptions_EmitUnknownLicenses tests that -unknown-licenses writes the packages whose license could not be
+// determined, with their sources, to a JSON file.
+func TestOptions_EmitUnknownLicenses(t *testing.T) {
+	t.Parallel()
+
+	reportFile := filepath.Join(t.TempDir(), "unknown.json")
+	fs := flag.NewFlagSet("sbomattr", flag.ContinueOnError)
+	opts := registerFlags(fs)
+	if err := fs.Parse([]string{"-unknown-licenses", reportFile}); err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+
+	mit := "MIT"
+	attrs := []attribution.Attribution{
+		{Name: "lodash", License: &mit, Sources: []string{"web.json"}},
+		{Name: "left-pad", Purl: "pkg:npm/left-pad@1.3.0", Sources: []string{"web.json", "api.json"}},
+	}
+	logger := slog.New(slog.DiscardHandler)
+	code := opts.emit(context.Background(), io.Discard, attrs, nil, "", format.CSVOptions{}, logger)
+	if code != exitSuccess {
+		t.Fatalf("emit() = %d, want %d", code, exitSuccess)
+	}
+
+	data, err := os.ReadFile(reportFile)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	var report attribution.UnknownLicenseReport
+	if err = json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("report is not valid JSON: %v", err)
+	}
+	if len(report.Packages) != 1 || report.Packages[0].Name != "left-pad" ||
+		!slices.Equal(report.Packages[0].Sources, []string{"web.json", "api.json"}) {
+		t.Errorf("report = %+v, want left-pad from web.json and api.json", report.Packages)
+	}
+}
+
+// TestOptions_TransformFirstParty tests that first-party packages are excluded, or flagged with -keep-first-party.
+func TestOptions_TransformFirstParty(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "billing", Purl: "pkg:maven/com.acme.internal/billing@1.0.0"},
+		{Name: "ui", Supplier: "Acme Corp"},
+		{Name: "acme-tools"},
+		{Name: "sdk", Purl: "pkg:golang/github.com/acme/sdk/v2@v2.0.0"},
+		{Name: "lodash", Purl: "pkg:npm/lodash@4.17.21"},
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantNames   []string
+		wantFlagged []string
+	}{
+		{name: "no rules", args: nil, wantNames: []string{"billing", "ui", "acme-tools", "sdk", "lodash"}},
+		{
+			name:      "exclude",
+			args:      []string{"-first-party", "com.acme*", "-first-party-supplier", "acme corp"},
+			wantNames: []string{"acme-tools", "sdk", "lodash"},
+		},
+		{
+			name:      "exclude by name and purl",
+			args:      []string{"-first-party-name", "acme-*", "-first-party-purl", "pkg:golang/github.com/acme/"},
+			wantNames: []string{"billing", "ui", "lodash"},
+		},
+		{
+			name:        "flag",
+			args:        []string{"-first-party", "com.acme*", "-keep-first-party"},
+			wantNames:   []string{"billing", "ui", "acme-tools", "sdk", "lodash"},
+			wantFlagged: []string{"billing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := flag.NewFlagSet("sbomattr", flag.ContinueOnError)
+			opts := registerFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+
+			var names, flagged []string
+			for _, a := range opts.transform(input, nil) {
+				names = append(names, a.Name)
+				if a.FirstParty {
+					flagged = append(flagged, a.Name)
+				}
+			}
+			if !slices.Equal(names, tt.wantNames) {
+				t.Errorf("transform() names = %v, want %v", names, tt.wantNames)
+			}
+			if !slices.Equal(flagged, tt.wantFlagged) {
+				t.Errorf("transform() flagged = %v, want %v", flagged, tt.wantFlagged)
+			}
+		})
+	}
+}
+
+// TestOptions_CheckPolicy tests that only violations new compared to the last stored run are posted to the webhook.
+func TestOptions_CheckPolicy(t *testing.T) {
+	t.Parallel()
+
+	var posted []notify.Notification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var n notify.Notification
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+			t.Errorf("invalid webhook payload: %v", err)
+		}
+		posted = append(posted, n)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	storeDir := t.TempDir()
+	gpl, agpl := "GPL-3.0-only", "AGPL-3.0-only"
+	baseline := store.Run{
+		Product:      "acme",
+		Version:      "v1",
+		Attributions: []attribution.Attribution{{Name: "readline", License: &gpl}},
+	}
+	if err := store.NewFileStore(storeDir).Save(context.Background(), baseline); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	fs := flag.NewFlagSet("sbomattr", flag.ContinueOnError)
+	opts := registerFlags(fs)
+	args := []string{
+		"-store", storeDir, "-product", "acme", "-product-version", "v2",
+		"-deny-licenses", "GPL,AGPL", "-webhook", server.URL,
+	}
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+	if err := opts.validate(); err != nil {
+		t.Fatalf("validate() unexpected error: %v", err)
+	}
+
+	logger := slog.New(slog.DiscardHandler)
+
+	// Only the baseline violation: nothing is posted
+	if err := opts.checkPolicy(context.Background(), baseline.Attributions, logger); err != nil {
+		t.Fatalf("checkPolicy() error = %v", err)
+	}
+	if len(posted) != 0 {
+		t.Fatalf("webhook called %d times, want 0", len(posted))
+	}
+
+	// A new violation is posted
+	current := append(slices.Clone(baseline.Attributions), attribution.Attribution{Name: "ghostscript", License: &agpl})
+	if err := opts.checkPolicy(context.Background(), current, logger); err != nil {
+		t.Fatalf("checkPolicy() error = %v", err)
+	}
+	if len(posted) != 1 {
+		t.Fatalf("webhook called %d times, want 1", len(posted))
+	}
+	if n := posted[0]; n.Version != "v2" || len(n.Violations) != 1 ||
+		n.Violations[0].Attribution.Name != "ghostscript" {
+		t.Errorf("webhook payload = %+v, want the ghostscript violation of v2", n)
+	}
+}
+
+// TestOptions_Verify tests that -verify-urls notes dead URLs and records the results in the cache file.
+func TestOptions_Verify(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/alive" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	cacheFile := filepath.Join(t.TempDir(), "links.json")
+	fs := flag.NewFlagSet("sbomattr", flag.ContinueOnError)
+	opts := registerFlags(fs)
+	args := []string{"-verify-urls", "-verify-urls-cache", cacheFile, "-verify-urls-rate", "0"}
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+
+	alive, missing := server.URL+"/alive", server.URL+"/missing"
+	input := []attribution.Attribution{{Name: "lodash", URL: &alive}, {Name: "left-pad", URL: &missing}}
+
+	got, err := opts.verify(context.Background(), input, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("verify() unexpected error: %v", err)
+	}
+	if len(got[0].Notes) != 0 || !slices.Equal(got[1].Notes, []string{"dead URL: " + missing}) {
+		t.Errorf("verify() notes = %v, %v, want only the missing URL noted", got[0].Notes, got[1].Notes)
+	}
+	if data, readErr := os.ReadFile(cacheFile); readErr != nil || !strings.Contains(string(data), missing) {
+		t.Errorf("cache file = %q, %v, want the checked URLs", data, readErr)
+	}
+}
+
+// TestOptions_Verify_CacheDir tests that -cache-dir creates the cache directory and keeps the URL checks in it.
+func TestOptions_Verify_CacheDir(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	t.Cleanup(server.Close)
+
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+	fs := flag.NewFlagSet("sbomattr", flag.ContinueOnError)
+	opts := registerFlags(fs)
+	if err := fs.Parse([]string{"-verify-urls", "-cache-dir", cacheDir, "-cache-ttl", "1h"}); err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+	if err := opts.makeCacheDir(); err != nil {
+		t.Fatalf("makeCacheDir() unexpected error: %v", err)
+	}
+
+	alive := server.URL + "/alive"
+	input := []attribution.Attribution{{Name: "lodash", URL: &alive}}
+	if _, err := opts.verify(context.Background(), input, slog.New(slog.DiscardHandler)); err != nil {
+		t.Fatalf("verify() unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(cacheDir, "links.json"))
+	if err != nil || !strings.Contains(string(data), alive) {
+		t.Errorf("cache file = %q, %v, want the checked URL", data, err)
+	}
+}
+
+// TestOptions_Verify_Offline tests that -offline URL checks send no requests and only use the cache.
+func TestOptions_Verify_Offline(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+
+	fs := flag.NewFlagSet("sbomattr", flag.ContinueOnError)
+	opts := registerFlags(fs)
+	if err := fs.Parse([]string{"-offline", "-verify-urls"}); err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+
+	missing := server.URL + "/missing"
+	got, err := opts.verify(context.Background(), []attribution.Attribution{{Name: "left-pad", URL: &missing}},
+		slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("verify() unexpected error: %v", err)
+	}
+	if len(got[0].Notes) != 0 {
+		t.Errorf("verify() notes = %v, want none for an unchecked URL", got[0].Notes)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("server received %d requests offline, want none", n)
+	}
+}
+
+// TestProcessInputs_Lockfiles tests aggregating SBOMs and lockfiles found in a directory.
+func TestProcessInputs_Lockfiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"sbom.json": `{"spdxVersion": "SPDX-2.3", "packages": [{"name": "react", "externalRefs": [` +
+			`{"referenceType": "purl", "referenceLocator": "pkg:npm/react@18.2.0"}]}]}`,
+		"requirements.txt": "requests==2.31.0\n",
+		"notes.txt":        "not a lockfile\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	logger := slog.New(slog.DiscardHandler)
+
+	if got := expandPaths([]string{dir}, false, logger); len(got) != 1 {
+		t.Errorf("expandPaths() without lockfiles = %v, want only sbom.json", got)
+	}
+
+	paths := expandPaths([]string{dir}, true, logger)
+	if len(paths) != 2 {
+		t.Fatalf("expandPaths() with lockfiles = %v, want sbom.json and requirements.txt", paths)
+	}
+
+	attrs, err := (&options{lockfiles: true}).processInputs(context.Background(), paths, nil, logger)
+	if err != nil {
+		t.Fatalf("processInputs() error = %v", err)
+	}
+
+	var purls []string
+	for _, a := range attrs {
+		purls = append(purls, a.Purl)
+	}
+	slices.Sort(purls)
+	if want := []string{"pkg:npm/react@18.2.0", "pkg:pypi/requests@2.31.0"}; !slices.Equal(purls, want) {
+		t.Errorf("processInputs() purls = %v, want %v", purls, want)
+	}
+}
+
+// TestProcessInputs_ChangedOnly tests that -changed-only reuses the results cached in the manifest.
+func TestProcessInputs_ChangedOnly(t *testing.T) {
+	t.Parallel()
+
+	manifestFile := filepath.Join(t.TempDir(), "manifest.json")
+	files := []string{"../../testdata/example-spdx.json"}
+	logger := slog.New(slog.DiscardHandler)
+	ctx := context.Background()
+
+	// The first run has no manifest yet, so it processes every file and writes one
+	opts := &options{manifestFile: manifestFile, changedOnly: true}
+	first, err := opts.processInputs(ctx, files, nil, logger)
+	if err != nil {
+		t.Fatalf("processInputs() error = %v", err)
+	}
+
+	m, err := manifest.Load(manifestFile)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	entry, ok := m.Files[files[0]]
+	if !ok || len(entry.Attributions) != len(first) {
+		t.Fatalf("manifest entry = %+v, want the results of %s", entry, files[0])
+	}
+
+	// Tamper with the cached results: an unchanged file must reuse them instead of being processed again
+	entry.Attributions = entry.Attributions[:1]
+	m.Files[files[0]] = entry
+	if saveErr := m.Save(manifestFile); saveErr != nil {
+		t.Fatalf("Save() error = %v", saveErr)
+	}
+
+	second, err := opts.processInputs(ctx, files, nil, logger)
+	if err != nil {
+		t.Fatalf("processInputs() error = %v", err)
+	}
+	if len(second) != 1 {
+		t.Errorf("processInputs() with -changed-only returned %d attributions, want the 1 cached", len(second))
+	}
+
+	// Without -changed-only every file is processed again
+	full, err := (&options{manifestFile: manifestFile}).processInputs(ctx, files, nil, logger)
+	if err != nil {
+		t.Fatalf("processInputs() error = %v", err)
+	}
+	if len(full) != len(first) {
+		t.Errorf("processInputs() without -changed-only returned %d attributions, want %d", len(full), len(first))
+	}
+}
+
+// TestProcessInputs_FileReport tests that -file-report writes the outcome of each SBOM file, even if no file could
+// be processed.
+func TestProcessInputs_FileReport(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	reportFile := filepath.Join(dir, "report.json")
+	broken := filepath.Join(dir, "broken.json")
+	if err := os.WriteFile(broken, []byte(`{"spdxVersion": "SPDX-2.3", "packages": 1}`), 0600); err != nil {
+		t.Fatalf("failed to write SBOM: %v", err)
+	}
+
+	opts := &options{fileReport: reportFile}
+	logger := slog.New(slog.DiscardHandler)
+	if _, err := opts.processInputs(context.Background(), []string{broken}, nil, logger); err == nil {
+		t.Fatal("processInputs() expected an error for a broken SBOM")
+	}
+
+	data, err := os.ReadFile(reportFile)
+	if err != nil {
+		t.Fatalf("failed to read file report: %v", err)
+	}
+	var report sbomattr.ProcessReport
+	if err = json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("failed to parse file report: %v", err)
+	}
+	if len(report.Files) != 1 || report.Files[0].File != broken || report.Files[0].Status != sbomattr.FileFailed ||
+		report.Files[0].Format != "spdx" {
+		t.Errorf("file report = %+v, want %s failed as spdx", report, broken)
+	}
+}
+
+// TestProcessInputs_Strict tests that -strict fails on a broken SBOM file that is otherwise skipped.
+func TestProcessInputs_Strict(t *testing.T) {
+	t.Parallel()
+
+	broken := filepath.Join(t.TempDir(), "broken.json")
+	if err := os.WriteFile(broken, []byte(`{"bomFormat": `), 0600); err != nil {
+		t.Fatalf("failed to write SBOM: %v", err)
+	}
+	files := []string{"../../testdata/example-spdx.json", broken}
+	logger := slog.New(slog.DiscardHandler)
+
+	if _, err := (&options{}).processInputs(context.Background(), files, nil, logger); err != nil {
+		t.Errorf("processInputs() without -strict error = %v, want the broken file skipped", err)
+	}
+	_, err := (&options{strict: true}).processInputs(context.Background(), files, nil, logger)
+	if !errors.Is(err, sbomattr.ErrInputFailed) || !strings.Contains(err.Error(), broken) {
+		t.Errorf("processInputs() with -strict error = %v, want ErrInputFailed for %s", err, broken)
+	}
+}
+
+// TestOptions_InputFiles tests that -strict fails on input paths that cannot be accessed.
+func TestOptions_InputFiles(t *testing.T) {
+	t.Parallel()
+
+	paths := []string{"../../testdata/example-spdx.json", filepath.Join(t.TempDir(), "missing.json")}
+	logger := slog.New(slog.DiscardHandler)
+
+	files, err := (&options{}).inputFiles(paths, logger)
+	if err != nil || len(files) != 1 {
+		t.Errorf("inputFiles() = %v, %v, want the existing file", files, err)
+	}
+	if _, err = (&options{strict: true}).inputFiles(paths, logger); err == nil {
+		t.Error("inputFiles() with -strict expected an error for the missing path")
+	}
+	if _, err = (&options{}).inputFiles(paths[1:], logger); err == nil {
+		t.Error("inputFiles() expected an error without files")
+	}
+}
+
+// TestProcessInputs_DedupAudit tests that -dedup-audit writes the deduplication decisions to a JSON file.
+func TestProcessInputs_DedupAudit(t *testing.T) {
+	t.Parallel()
+
+	auditFile := filepath.Join(t.TempDir(), "audit.json")
+	files := []string{"../../testdata/example-spdx.json", "../../testdata/example-spdx.json"}
+
+	opts := &options{dedupAuditFile: auditFile}
+	if _, err := opts.processInputs(context.Background(), files, nil, slog.New(slog.DiscardHandler)); err != nil {
+		t.Fatalf("processInputs() error = %v", err)
+	}
+
+	data, err := os.ReadFile(auditFile)
+	if err != nil {
+		t.Fatalf("failed to read audit file: %v", err)
+	}
+	var audit attribution.DedupAudit
+	if unmarshalErr := json.Unmarshal(data, &audit); unmarshalErr != nil {
+		t.Fatalf("audit file is not valid JSON: %v", unmarshalErr)
+	}
+	if len(audit.Decisions) == 0 {
+		t.Errorf("audit file has no decisions, got: %s", data)
+	}
+}
+
+// TestProcessInputs_LicenseConflicts tests that -license-conflicts writes the packages found with different licenses
+// in different SBOMs to a JSON file.
+func TestProcessInputs_LicenseConflicts(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	reportFile := filepath.Join(dir, "conflicts.json")
+	write := func(name, license string) string {
+		path := filepath.Join(dir, name)
+		content := `{"spdxVersion": "SPDX-2.3", "SPDXID": "SPDXRef-DOCUMENT", "packages": [` +
+			`{"SPDXID": "SPDXRef-a", "name": "lodash", "versionInfo": "4.17.21", ` +
+			`"licenseConcluded": "` + license + `"}]}`
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write SBOM: %v", err)
+		}
+		return path
+	}
+	syft := write("syft.json", "MIT")
+	trivy := write("trivy.json", "Apache-2.0")
+
+	opts := &options{conflictsFile: reportFile}
+	logger := slog.New(slog.DiscardHandler)
+	if _, err := opts.processInputs(context.Background(), []string{syft, trivy}, nil, logger); err != nil {
+		t.Fatalf("processInputs() unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(reportFile)
+	if err != nil {
+		t.Fatalf("failed to read license conflict report: %v", err)
+	}
+	var report attribution.LicenseConflictReport
+	if err = json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("failed to parse license conflict report: %v", err)
+	}
+	if len(report.Conflicts) != 1 || len(report.Conflicts[0].Licenses) != 2 ||
+		!slices.Equal(report.Conflicts[0].Licenses[1].Sources, []string{trivy}) {
+		t.Errorf("license conflicts = %+v, want lodash with Apache-2.0 from %s", report.Conflicts, trivy)
+	}
+}
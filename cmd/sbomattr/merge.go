@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// mergeFormats returns the output formats of the merge command: the SBOM formats.
+func mergeFormats() []string {
+	return []string{"cyclonedx", "spdx", "spdx-lite"}
+}
+
+// runMerge runs the merge command, which merges the packages of SBOM files and directories, with the dependencies
+// they declare, into a single deduplicated SBOM written to w. Returns the process exit code.
+func runMerge(args []string, w io.Writer) int {
+	fs := flag.NewFlagSet("sbomattr merge", flag.ContinueOnError)
+	opts := registerFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: sbomattr merge [OPTIONS] <file-or-directory>...\n\n")
+		fmt.Fprintf(fs.Output(), "Merge SPDX and CycloneDX SBOMs into a single SBOM with deduplicated packages.\n\n")
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitSuccess
+		}
+		return exitInvalidArgs
+	}
+
+	logger := setupLogger(opts.verbose)
+
+	if fs.NArg() == 0 {
+		logger.Error("no SBOM files or directories provided")
+		fs.Usage()
+		return exitInvalidArgs
+	}
+	if err := opts.mergeOptions(fs); err != nil {
+		logger.Error("invalid options", "error", err)
+		return exitInvalidArgs
+	}
+
+	return opts.runInputs(fs.Args(), w, logger)
+}
+
+// mergeOptions adapts the options parsed by fs to the merge command: the output format defaults to CycloneDX and
+// must be an SBOM format, and the dependency graph is always included.
+func (o *options) mergeOptions(fs *flag.FlagSet) error {
+	formatSet := false
+	fs.Visit(func(f *flag.Flag) {
+		formatSet = formatSet || f.Name == "format"
+	})
+	if !formatSet {
+		o.outputFormat = "cyclonedx"
+	}
+
+	if !slices.Contains(mergeFormats(), o.outputFormat) {
+		return fmt.Errorf("unsupported merge format: %s (want %s)", o.outputFormat, strings.Join(mergeFormats(), ", "))
+	}
+	if o.templateFile != "" || o.preview > 0 {
+		return errors.New("-template and -preview are not supported by merge")
+	}
+	o.includeGraph = true
+	return nil
+}
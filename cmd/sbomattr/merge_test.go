@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/cyclonedxextract"
+	"github.com/boringbin/sbomattr/spdxextract"
+)
+
+// mergeInputs is the directory of the merge fixtures: an SPDX document and a CycloneDX BOM that both list express,
+// each declaring one dependency.
+const mergeInputs = "testdata/merge"
+
+// TestRunMerge tests that merge writes a CycloneDX BOM with the deduplicated packages and the dependencies declared
+// by every input.
+func TestRunMerge(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if code := runMerge([]string{mergeInputs}, &buf); code != exitSuccess {
+		t.Fatalf("runMerge() exit code = %d, want %d", code, exitSuccess)
+	}
+
+	bom, err := cyclonedxextract.ParseSBOM(buf.Bytes())
+	if err != nil {
+		t.Fatalf("output is not a CycloneDX BOM: %v", err)
+	}
+	var names []string
+	for _, a := range cyclonedxextract.ExtractPackages(bom) {
+		names = append(names, a.Name+"@"+a.Version)
+	}
+	if want := []string{"debug@2.6.9", "express@4.18.2", "ms@2.0.0"}; !slices.Equal(names, want) {
+		t.Errorf("components = %v, want %v", names, want)
+	}
+	want := []attribution.Edge{
+		{From: "pkg:npm/express@4.18.2", To: "pkg:npm/debug@2.6.9"},
+		{From: "pkg:npm/express@4.18.2", To: "pkg:npm/ms@2.0.0"},
+	}
+	if got := cyclonedxextract.Dependencies(bom); !slices.Equal(got, want) {
+		t.Errorf("dependencies = %v, want %v", got, want)
+	}
+}
+
+// TestRunMerge_SPDX tests that merge writes an SPDX document with -format spdx.
+func TestRunMerge_SPDX(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if code := runMerge([]string{"-format", "spdx", mergeInputs}, &buf); code != exitSuccess {
+		t.Fatalf("runMerge() exit code = %d, want %d", code, exitSuccess)
+	}
+
+	doc, err := spdxextract.ParseSBOM(buf.Bytes())
+	if err != nil {
+		t.Fatalf("output is not an SPDX document: %v", err)
+	}
+	if packages := spdxextract.ExtractPackages(doc); len(packages) != 3 {
+		t.Errorf("packages = %v, want debug, express, and ms", packages)
+	}
+	if edges := spdxextract.Dependencies(doc); len(edges) != 2 {
+		t.Errorf("dependencies = %v, want express on debug and ms", edges)
+	}
+}
+
+// TestRunMerge_InvalidArgs tests that merge requires inputs and an SBOM output format.
+func TestRunMerge_InvalidArgs(t *testing.T) {
+	t.Parallel()
+
+	dir := mergeInputs
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "no inputs", args: nil},
+		{name: "csv format", args: []string{"-format", "csv", dir}},
+		{name: "preview", args: []string{"-preview", "5", dir}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if code := runMerge(tt.args, &bytes.Buffer{}); code != exitInvalidArgs {
+				t.Errorf("runMerge() exit code = %d, want %d", code, exitInvalidArgs)
+			}
+		})
+	}
+}
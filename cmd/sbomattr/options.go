@@ -0,0 +1,1056 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/boringbin/sbomattr"
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/cyclonedxextract"
+	"github.com/boringbin/sbomattr/enrich"
+	"github.com/boringbin/sbomattr/format"
+	"github.com/boringbin/sbomattr/internal/cache"
+	"github.com/boringbin/sbomattr/licensefetch"
+	"github.com/boringbin/sbomattr/linkcheck"
+	"github.com/boringbin/sbomattr/notify"
+	"github.com/boringbin/sbomattr/policy"
+	"github.com/boringbin/sbomattr/spdxextract"
+	"github.com/boringbin/sbomattr/store"
+	"github.com/package-url/packageurl-go"
+)
+
+// defaultVerifyRate is the default of -verify-urls-rate, in requests per second.
+const defaultVerifyRate = 10
+
+// options holds the values of the command-line flags.
+type options struct {
+	verbose         bool
+	showVersion     bool
+	outputFormat    string
+	templateFile    string
+	columns         string
+	delimiter       string
+	useCRLF         bool
+	licenseDetails  bool
+	guessLicenses   bool
+	corrections     string
+	remapDeprecated bool
+	splitExceptions bool
+	skipScopes      string
+	correlateRepos  string
+	rootComponent   string
+	spdxRelations   string
+	cdxDependencies string
+	firstParty      string
+	firstSuppliers  string
+	firstNames      string
+	firstPurls      string
+	keepFirstParty  bool
+	groupBySource   bool
+	includeGraph    bool
+	lockfiles       bool
+	jobs            int
+	progress        bool
+	manifestFile    string
+	changedOnly     bool
+	dedup           string
+	dedupAuditFile  string
+	unknownFile     string
+	conflictsFile   string
+	fileReport      string
+	strict          bool
+	sortKey         string
+	sortIgnoreCase  bool
+	storeDir        string
+	product         string
+	productVersion  string
+	denyLicenses    string
+	forbid          string
+	webhookURL      string
+	webhookFormat   string
+	pinsFile        string
+	pinsWarn        bool
+	verifyURLs      bool
+	verifyFallback  bool
+	verifyCache     string
+	verifyRate      int
+	fetchTexts      bool
+	textCache       string
+	enrich          string
+	cacheDir        string
+	cacheTTL        time.Duration
+	offline         bool
+	reproducible    bool
+	preview         int
+
+	// flags is the flag set the options were registered on, to record the flags set in the JSON output metadata.
+	flags *flag.FlagSet
+	// inputs is the number of inputs processed, recorded in the JSON output metadata.
+	inputs int
+	// sourceNames replaces input file names in the sources of attributions, e.g. with the sources of fetched SBOMs.
+	sourceNames map[string]string
+}
+
+// registerFlags registers the command-line flags on the flag set and returns the options they populate.
+func registerFlags(fs *flag.FlagSet) *options {
+	opts := &options{flags: fs}
+
+	fs.BoolVar(&opts.verbose, "v", false, "Verbose output (debug mode)")
+	fs.BoolVar(&opts.showVersion, "version", false, "Show version and exit")
+	fs.StringVar(&opts.outputFormat, "format", "csv", "Output format: "+strings.Join(outputFormats(), ", "))
+	fs.StringVar(&opts.templateFile, "template", "", "Render output using a Go text/template file (overrides -format)")
+	fs.StringVar(&opts.columns, "columns", "",
+		"Comma-separated CSV/TSV/Markdown columns: "+strings.Join(format.ColumnNames(), ","))
+	fs.StringVar(&opts.delimiter, "delimiter", ",", "CSV field delimiter (a single character, or \"tab\")")
+	fs.BoolVar(&opts.useCRLF, "crlf", false, "Use CRLF line endings in CSV/TSV output")
+	fs.BoolVar(&opts.licenseDetails, "license-details", false,
+		"Add declared and concluded license columns to CSV output")
+	fs.BoolVar(&opts.guessLicenses, "guess-licenses", false,
+		"Fill missing licenses of well-known packages (heuristic)")
+	fs.StringVar(&opts.corrections, "corrections", "",
+		"Replace the licenses of packages (by purl, or name and version) with the verified ones from this JSON file")
+	fs.BoolVar(&opts.remapDeprecated, "remap-deprecated", false,
+		"Replace deprecated SPDX license IDs with current ones")
+	fs.BoolVar(&opts.splitExceptions, "split-exceptions", false,
+		"Split \"<license> WITH <exception>\" into separate columns")
+	fs.StringVar(&opts.skipScopes, "skip-scopes", "",
+		"Comma-separated CycloneDX component scopes to skip: excluded, optional (e.g. dev-only tools)")
+	fs.StringVar(&opts.correlateRepos, "correlate-repos", "",
+		"Correlate packages listed under several purl types by source repository: link (add notes), merge")
+	fs.StringVar(&opts.rootComponent, "root-component", "",
+		"Attribute the root component of CycloneDX SBOMs: include, exclude (default: only if listed as a component)")
+	fs.StringVar(&opts.spdxRelations, "spdx-relationships", "",
+		"Only attribute SPDX packages related to the described package: reachable (any relationship), depends-on")
+	fs.StringVar(&opts.cdxDependencies, "cyclonedx-dependencies", "",
+		"Only attribute CycloneDX components in the root's dependency graph: reachable, direct")
+	registerFirstPartyFlags(fs, opts)
+	fs.BoolVar(&opts.lockfiles, "lockfiles", false,
+		"Also read lockfiles (package-lock.json, go.mod, go.sum, requirements.txt, Cargo.lock) as a first pass")
+	fs.IntVar(&opts.jobs, "jobs", 0, "Number of SBOM files parsed in parallel (default: number of CPUs)")
+	fs.BoolVar(&opts.progress, "progress", false, "Report each processed SBOM file on stderr, for long runs")
+	fs.StringVar(&opts.manifestFile, "manifest", "",
+		"Record input file digests and results to this manifest file, for -changed-only runs")
+	fs.BoolVar(&opts.changedOnly, "changed-only", false,
+		"Only process SBOMs changed since the -manifest was written, reusing cached results for the rest")
+	fs.StringVar(&opts.dedup, "dedup", "",
+		"Merge duplicates per: version (default), package (one row listing every version), name (across ecosystems)")
+	registerReportFlags(fs, opts)
+	fs.BoolVar(&opts.strict, "strict", false,
+		"Fail if any input path or SBOM file cannot be read or parsed, instead of skipping it")
+	fs.BoolVar(&opts.groupBySource, "group-by-source", false,
+		"Group output by originating SBOM file (csv, tsv, markdown, json)")
+	fs.BoolVar(&opts.includeGraph, "include-graph", false,
+		"Embed the dependency graph declared by the SBOMs (edges between purls) in JSON, SPDX, or CycloneDX output")
+	fs.BoolVar(&opts.reproducible, "reproducible", false,
+		"Omit the timestamp from the JSON output metadata, so identical inputs produce identical output")
+	fs.IntVar(&opts.preview, "preview", 0,
+		"Only write the first N attributions and the summary statistics of all of them, to sanity-check a run")
+	registerNetworkFlags(fs, opts)
+	fs.StringVar(&opts.sortKey, "sort", "", "Sort output by: name, license, purl (default: purl, then name)")
+	fs.BoolVar(&opts.sortIgnoreCase, "sort-ignore-case", false, "Sort case-insensitively")
+	fs.StringVar(&opts.storeDir, "store", "", "Also save the result to this history directory (requires -product)")
+	fs.StringVar(&opts.product, "product", "", "Product name the result is stored under")
+	fs.StringVar(&opts.productVersion, "product-version", "", "Product version the result is stored under")
+	fs.StringVar(&opts.denyLicenses, "deny-licenses", "",
+		"Comma-separated denied license IDs or ID prefixes (e.g. GPL,AGPL), NONE, or NOASSERTION; violations are "+
+			"logged")
+	fs.StringVar(&opts.forbid, "forbid", "",
+		"Fail if any package has a license of these comma-separated categories: copyleft (strong copyleft, e.g. GPL, "+
+			"AGPL), "+categoryNames())
+	fs.StringVar(&opts.webhookURL, "webhook", "",
+		"Post new policy violations compared to the last stored run to this URL (requires -store, -deny-licenses)")
+	fs.StringVar(&opts.webhookFormat, "webhook-format", "json", "Webhook payload format: json, slack")
+	fs.StringVar(&opts.pinsFile, "pins", "",
+		"Fail if the attributions deviate from the values pinned in this JSON file (e.g. upstream license changes)")
+	fs.BoolVar(&opts.pinsWarn, "pins-warn", false, "Only warn about -pins deviations instead of failing")
+
+	return opts
+}
+
+// registerFirstPartyFlags registers the flags describing your own packages on the flag set.
+func registerFirstPartyFlags(fs *flag.FlagSet, opts *options) {
+	fs.StringVar(&opts.firstParty, "first-party", "",
+		"Comma-separated purl namespace/group glob patterns of your own packages to exclude (e.g. com.acme*,@acme)")
+	fs.StringVar(&opts.firstSuppliers, "first-party-supplier", "",
+		"Comma-separated supplier names of your own packages to exclude")
+	fs.StringVar(&opts.firstNames, "first-party-name", "",
+		"Comma-separated name glob patterns of your own packages to exclude (e.g. acme-*)")
+	fs.StringVar(&opts.firstPurls, "first-party-purl", "",
+		"Comma-separated purl prefixes of your own packages to exclude (e.g. pkg:golang/github.com/acme/)")
+	fs.BoolVar(&opts.keepFirstParty, "keep-first-party", false,
+		"Flag first-party packages (firstParty field, first-party column) instead of excluding them")
+}
+
+// registerReportFlags registers the flags of the JSON reports written alongside the output on the flag set.
+func registerReportFlags(fs *flag.FlagSet, opts *options) {
+	fs.StringVar(&opts.dedupAuditFile, "dedup-audit", "",
+		"Write every deduplication decision (kept entry, dropped entries, differing fields) to this JSON file")
+	fs.StringVar(&opts.unknownFile, "unknown-licenses", "",
+		"Write every package whose license could not be determined, with its source files, to this JSON file")
+	fs.StringVar(&opts.conflictsFile, "license-conflicts", "",
+		"Write every package found with different licenses in different SBOMs, with their sources, to this JSON file")
+	fs.StringVar(&opts.fileReport, "file-report", "",
+		"Write the outcome of each SBOM file (status, format, package count, errors) to this JSON file")
+}
+
+// registerNetworkFlags registers the flags of the steps querying remote services on the flag set.
+func registerNetworkFlags(fs *flag.FlagSet, opts *options) {
+	fs.BoolVar(&opts.verifyURLs, "verify-urls", false,
+		"Check that the URLs resolve (HEAD/GET requests) and note dead links, e.g. missing registry pages")
+	fs.BoolVar(&opts.verifyFallback, "verify-urls-fallback", false,
+		"Replace dead URLs with the next known URL of the package that is alive (requires -verify-urls)")
+	fs.StringVar(&opts.verifyCache, "verify-urls-cache", "",
+		"Cache URL check results in this JSON file, so later runs only check new URLs (requires -verify-urls)")
+	fs.IntVar(&opts.verifyRate, "verify-urls-rate", defaultVerifyRate,
+		"Maximum URL check requests per second, 0 for no limit")
+	fs.BoolVar(&opts.fetchTexts, "fetch-license-texts", false,
+		"Fetch missing license texts from the GitHub repository or npm tarball of the packages")
+	fs.StringVar(&opts.textCache, "license-text-cache", "",
+		"Cache fetched license texts in this JSON file, so later runs only fetch new packages (requires "+
+			"-fetch-license-texts)")
+	fs.StringVar(&opts.enrich, "enrich", "",
+		"Backfill missing licenses and homepages from the package registries of these comma-separated purl types: "+
+			strings.Join(enrich.SourceTypes(), ", ")+", or all")
+	registerCacheFlags(fs, opts)
+}
+
+// registerCacheFlags registers the flags of the caches of the network steps on the flag set, shared by the main
+// command and the verify-urls command.
+func registerCacheFlags(fs *flag.FlagSet, opts *options) {
+	fs.StringVar(&opts.cacheDir, "cache-dir", "",
+		"Cache registry lookups, fetched license texts, and URL checks in this directory, so later runs only query "+
+			"new packages")
+	fs.DurationVar(&opts.cacheTTL, "cache-ttl", 0,
+		"How long cached results are reused (e.g. 168h), 0 for no expiry")
+	fs.BoolVar(&opts.offline, "offline", false,
+		"Make no network requests: -enrich, -fetch-license-texts, and -verify-urls only use cached results")
+}
+
+// validate checks the flag values that do not depend on the input files.
+func (o *options) validate() error {
+	if !slices.Contains(outputFormats(), o.outputFormat) {
+		return fmt.Errorf("unsupported output format: %s", o.outputFormat)
+	}
+	if err := o.validateFeatures(); err != nil {
+		return err
+	}
+	if o.preview < 0 {
+		return fmt.Errorf("invalid -preview count: %d (want a positive number)", o.preview)
+	}
+	if o.preview > 0 && o.storeDir != "" {
+		return errors.New("-preview is not supported with -store")
+	}
+	if o.sortKey != "" {
+		if _, err := attribution.ParseSortKey(o.sortKey); err != nil {
+			return err
+		}
+	}
+	for _, scope := range o.scopes() {
+		if scope != attribution.ScopeOptional && scope != attribution.ScopeExcluded {
+			return fmt.Errorf("unsupported -skip-scopes scope: %s (want excluded or optional)", scope)
+		}
+	}
+	if o.correlateRepos != "" && o.correlateRepos != "link" && o.correlateRepos != "merge" {
+		return fmt.Errorf("unsupported -correlate-repos mode: %s (want link or merge)", o.correlateRepos)
+	}
+	if _, err := sbomattr.ParseRootMode(o.rootComponent); err != nil {
+		return err
+	}
+	if _, err := spdxextract.ParseFilter(o.spdxRelations); err != nil {
+		return err
+	}
+	if _, err := cyclonedxextract.ParseFilter(o.cdxDependencies); err != nil {
+		return err
+	}
+	if _, err := attribution.ParseDedupMode(o.dedup); err != nil {
+		return err
+	}
+	if o.jobs < 0 {
+		return fmt.Errorf("invalid -jobs count: %d (want 0 or a positive number)", o.jobs)
+	}
+	if o.cacheTTL < 0 {
+		return fmt.Errorf("invalid -cache-ttl: %s (want 0 or a positive duration)", o.cacheTTL)
+	}
+	if o.verifyRate < 0 {
+		return fmt.Errorf("invalid -verify-urls-rate: %d (want 0 or a positive number)", o.verifyRate)
+	}
+	if _, err := o.forbiddenCategories(); err != nil {
+		return err
+	}
+	if _, err := o.enrichSources(); err != nil {
+		return err
+	}
+	if _, err := notify.ParseFormat(o.webhookFormat); err != nil {
+		return err
+	}
+	return o.validateRequirements()
+}
+
+// validateRequirements checks that the flags depending on other flags are only set along with them.
+func (o *options) validateRequirements() error {
+	if o.keepFirstParty && o.firstPartyRules().IsZero() {
+		return errors.New("-keep-first-party requires -first-party, -first-party-supplier, -first-party-name, or " +
+			"-first-party-purl")
+	}
+	if o.pinsWarn && o.pinsFile == "" {
+		return errors.New("-pins-warn requires -pins")
+	}
+	if (o.verifyFallback || o.verifyCache != "") && !o.verifyURLs {
+		return errors.New("-verify-urls-fallback and -verify-urls-cache require -verify-urls")
+	}
+	if o.textCache != "" && !o.fetchTexts {
+		return errors.New("-license-text-cache requires -fetch-license-texts")
+	}
+	if o.cacheTTL != 0 && o.cacheDir == "" && o.verifyCache == "" && o.textCache == "" {
+		return errors.New("-cache-ttl requires -cache-dir, -verify-urls-cache, or -license-text-cache")
+	}
+	if o.changedOnly && o.manifestFile == "" {
+		return errors.New("-changed-only requires -manifest")
+	}
+	if o.storeDir != "" && o.product == "" {
+		return errors.New("-store requires -product")
+	}
+	if o.webhookURL != "" && (o.storeDir == "" || o.denyLicenses == "") {
+		return errors.New("-webhook requires -store and -deny-licenses")
+	}
+	if o.webhookURL != "" && o.offline {
+		return errors.New("-webhook is not supported with -offline")
+	}
+	return nil
+}
+
+// processorOptions returns the SBOM processor options selected by the flags, logging to logger and recording
+// deduplication decisions in audit and dependencies in graph (both optional; pass nil to disable them). The manifest
+// and progress reporting are left to the caller.
+func (o *options) processorOptions(
+	audit *attribution.DedupAudit,
+	graph *attribution.Graph,
+	logger *slog.Logger,
+) []sbomattr.ProcessorOption {
+	// The modes were checked by validate
+	rootMode, _ := sbomattr.ParseRootMode(o.rootComponent)
+	spdxFilter, _ := spdxextract.ParseFilter(o.spdxRelations)
+	cdxFilter, _ := cyclonedxextract.ParseFilter(o.cdxDependencies)
+	processorOpts := []sbomattr.ProcessorOption{
+		sbomattr.WithLogger(logger),
+		sbomattr.WithAudit(audit),
+		sbomattr.WithGraph(graph),
+		sbomattr.WithRootMode(rootMode),
+		sbomattr.WithFilters(spdxFilter, cdxFilter),
+		sbomattr.WithConcurrency(o.jobs),
+		sbomattr.WithDedupStrategy(sbomattr.DedupByMode(o.dedupMode())),
+	}
+	if o.strict {
+		processorOpts = append(processorOpts, sbomattr.WithStrict())
+	}
+	if len(o.sourceNames) > 0 {
+		processorOpts = append(processorOpts, sbomattr.WithTransforms(renameSources(o.sourceNames)))
+	}
+	return processorOpts
+}
+
+// dedupMode returns the deduplication mode selected by -dedup.
+func (o *options) dedupMode() attribution.DedupMode {
+	// The mode was checked by validate
+	mode, _ := attribution.ParseDedupMode(o.dedup)
+	return mode
+}
+
+// progressFunc returns the progress function of -progress, which writes a line per processed SBOM file to w. Returns
+// nil if -progress is not set.
+func (o *options) progressFunc(w io.Writer) func(sbomattr.Progress) {
+	if !o.progress {
+		return nil
+	}
+	return func(p sbomattr.Progress) {
+		if p.File == "" {
+			fmt.Fprintf(w, "processing %d SBOM files\n", p.Files)
+			return
+		}
+		fmt.Fprintf(w, "[%d/%d] %s (%d attributions so far)\n", p.Processed, p.Files, p.File, p.Attributions)
+	}
+}
+
+// validateFeatures checks that the output format supports the optional features selected by the flags (see
+// format.Features), rather than silently ignoring them. Templates support none of them.
+func (o *options) validateFeatures() error {
+	for _, selected := range []struct {
+		set     bool
+		flag    string
+		feature format.Feature
+	}{
+		{set: o.columns != "", flag: "-columns", feature: format.FeatureColumns},
+		{set: o.licenseDetails, flag: "-license-details", feature: format.FeatureLicenseDetails},
+		{set: o.groupBySource, flag: "-group-by-source", feature: format.FeatureGroupBySource},
+		{set: o.delimiter != ",", flag: "-delimiter", feature: format.FeatureDelimiter},
+		{set: o.useCRLF, flag: "-crlf", feature: format.FeatureCRLF},
+		{set: o.includeGraph, flag: "-include-graph", feature: format.FeatureGraph},
+		{set: o.reproducible, flag: "-reproducible", feature: format.FeatureMetadata},
+	} {
+		if !selected.set {
+			continue
+		}
+		if o.templateFile != "" {
+			return fmt.Errorf("%s is not supported with -template", selected.flag)
+		}
+		if !format.Supports(o.outputFormat, selected.feature) {
+			return fmt.Errorf("%s is not supported with the %s format (supported by: %s)", selected.flag,
+				o.outputFormat, strings.Join(format.FormatsSupporting(selected.feature), ", "))
+		}
+	}
+	return nil
+}
+
+// csvOptions builds the tabular output options from the flags.
+// Returns an error if the delimiter or a selected column is invalid.
+func (o *options) csvOptions() (format.CSVOptions, error) {
+	csvOpts := format.CSVOptions{UseCRLF: o.useCRLF, LicenseDetails: o.licenseDetails, GroupBySource: o.groupBySource}
+
+	delimiter, err := parseDelimiter(o.delimiter)
+	if err != nil {
+		return format.CSVOptions{}, err
+	}
+	csvOpts.Delimiter = delimiter
+
+	if o.columns != "" {
+		csvOpts.Columns = strings.Split(o.columns, ",")
+		for _, c := range csvOpts.Columns {
+			if !slices.Contains(format.ColumnNames(), strings.ToLower(strings.TrimSpace(c))) {
+				return format.CSVOptions{}, fmt.Errorf(
+					"unknown column %q (valid columns: %s)", c, strings.Join(format.ColumnNames(), ", "))
+			}
+		}
+	}
+
+	return csvOpts, nil
+}
+
+// emit applies the selected transformations and policy checks to the aggregated attributions, saves them to the
+// history store if selected, and writes them to w using the template or output format. With -include-graph, the
+// dependency graph between the attributions is written too (graph is optional; nil means no dependencies are known).
+// JSON output records the run metadata, so o.inputs must be set. With -preview, only a sample is written, followed by
+// the summary statistics, and the policy checks are skipped.
+// Returns the process exit code.
+func (o *options) emit(
+	ctx context.Context,
+	w io.Writer,
+	attributions []attribution.Attribution,
+	graph *attribution.Graph,
+	tmpl string,
+	csvOpts format.CSVOptions,
+	logger *slog.Logger,
+) int {
+	attributions, err := o.correct(attributions, logger)
+	if err != nil {
+		logger.Error("failed to read corrections", "corrections", o.corrections, "error", err)
+		return exitInvalidArgs
+	}
+	if err = o.loadLicenseTexts(ctx, attributions, logger); err != nil {
+		logger.Error("failed to load license texts", "error", err)
+		return exitRuntimeError
+	}
+	if attributions, err = o.enrichFromRegistries(ctx, attributions, logger); err != nil {
+		logger.Error("failed to enrich attributions", "error", err)
+		return exitRuntimeError
+	}
+	attributions = o.transform(attributions, logger)
+
+	if o.preview > 0 {
+		if err := o.writePreview(w, attributions, graph, tmpl, csvOpts); err != nil {
+			logger.Error("failed to write output", "format", o.outputFormat, "error", err)
+			return exitRuntimeError
+		}
+		return exitSuccess
+	}
+
+	attributions, err = o.verify(ctx, attributions, logger)
+	if err != nil {
+		logger.Error("failed to verify URLs", "error", err)
+		return exitRuntimeError
+	}
+
+	if attributions, err = o.fetchLicenseTexts(ctx, attributions, logger); err != nil {
+		logger.Error("failed to fetch license texts", "error", err)
+		return exitRuntimeError
+	}
+
+	if code := o.checkGates(ctx, attributions, logger); code != exitSuccess {
+		return code
+	}
+
+	if err := o.reportUnknownLicenses(attributions, logger); err != nil {
+		logger.Error("failed to write unknown license report", "file", o.unknownFile, "error", err)
+		return exitRuntimeError
+	}
+
+	if err := o.saveRun(ctx, attributions); err != nil {
+		logger.Error("failed to save result to store", "store", o.storeDir, "error", err)
+		return exitRuntimeError
+	}
+
+	if err := o.write(w, attributions, graph, tmpl, csvOpts); err != nil {
+		logger.Error("failed to write output", "format", o.outputFormat, "error", err)
+		return exitRuntimeError
+	}
+
+	return exitSuccess
+}
+
+// checkGates runs the policy checks selected by the flags: the -deny-licenses policy, the -pins, and the -forbid
+// gate. Returns the process exit code, exitSuccess if the attributions pass.
+func (o *options) checkGates(ctx context.Context, attributions []attribution.Attribution, logger *slog.Logger) int {
+	if err := o.checkPolicy(ctx, attributions, logger); err != nil {
+		logger.Error("failed to notify policy violations", "webhook", o.webhookURL, "error", err)
+		return exitRuntimeError
+	}
+
+	deviations, err := o.checkPins(attributions, logger)
+	if err != nil {
+		logger.Error("failed to read pins", "pins", o.pinsFile, "error", err)
+		return exitInvalidArgs
+	}
+	if len(deviations) > 0 && !o.pinsWarn {
+		logger.Error("attributions deviate from pins", "pins", o.pinsFile, "deviations", len(deviations))
+		return exitPinDeviation
+	}
+
+	if forbidden := o.checkForbidden(attributions, logger); len(forbidden) > 0 {
+		logger.Error("forbidden licenses found", "forbid", o.forbid, "packages", len(forbidden))
+		return exitForbiddenLicense
+	}
+	return exitSuccess
+}
+
+// write writes the attributions to w using the template if one is selected, or else the output format.
+func (o *options) write(
+	w io.Writer,
+	attributions []attribution.Attribution,
+	graph *attribution.Graph,
+	tmpl string,
+	csvOpts format.CSVOptions,
+) error {
+	if o.templateFile != "" {
+		return format.Template(w, attributions, tmpl)
+	}
+	return writeOutput(w, o.outputFormat, attributions, csvOpts, o.jsonOptions(graph))
+}
+
+// writePreview writes the first -preview attributions like write, then the summary statistics of all of them, so
+// format detection and field mapping can be checked on huge inputs without reading the full output.
+func (o *options) writePreview(
+	w io.Writer,
+	attributions []attribution.Attribution,
+	graph *attribution.Graph,
+	tmpl string,
+	csvOpts format.CSVOptions,
+) error {
+	if o.templateFile != "" || o.outputFormat != "summary" {
+		sample := attributions[:min(o.preview, len(attributions))]
+		if err := o.write(w, sample, graph, tmpl, csvOpts); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "\nShowing %d of %d attributions.\n\n", len(sample), len(attributions))
+	}
+	return format.Summary(w, attributions)
+}
+
+// jsonOptions builds the JSON output options from the flags: the run metadata, and with -include-graph the
+// dependency graph (nil means no dependencies are known).
+func (o *options) jsonOptions(graph *attribution.Graph) format.JSONOptions {
+	metadata := format.Metadata{Tool: "sbomattr", Version: version, Inputs: o.inputs, Options: o.setFlags()}
+	if !o.reproducible {
+		metadata.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	jsonOpts := format.JSONOptions{Metadata: metadata}
+	if o.includeGraph {
+		if graph == nil {
+			graph = &attribution.Graph{}
+		}
+		jsonOpts.Graph = graph
+	}
+	return jsonOpts
+}
+
+// setFlags returns the values of the flags set on the command line, by flag name. Secrets (the webhook URL and the
+// GitHub token) are redacted, since the output is meant to be shared.
+func (o *options) setFlags() map[string]string {
+	if o.flags == nil {
+		return nil
+	}
+	set := make(map[string]string)
+	o.flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "webhook", "token":
+			set[f.Name] = "REDACTED"
+		default:
+			set[f.Name] = f.Value.String()
+		}
+	})
+	return set
+}
+
+// readTemplate reads the -template file. Returns an empty string if no template is selected.
+func (o *options) readTemplate() (string, error) {
+	if o.templateFile == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(o.templateFile)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// transform applies the optional attribution transformations selected by the flags.
+func (o *options) transform(attributions []attribution.Attribution, logger *slog.Logger) []attribution.Attribution {
+	if scopes := o.scopes(); len(scopes) > 0 {
+		attributions = attribution.ExcludeScopes(attributions, scopes, logger)
+	}
+	if o.guessLicenses {
+		attributions = attribution.FillKnownLicenses(attributions, logger)
+	}
+	if o.remapDeprecated {
+		attributions = attribution.RemapDeprecatedLicenses(attributions, logger)
+	}
+	if o.splitExceptions {
+		attributions = attribution.SplitLicenseExceptions(attributions, logger)
+	}
+	if o.correlateRepos != "" {
+		opts := attribution.CorrelateOptions{Merge: o.correlateRepos == "merge"}
+		attributions = attribution.CorrelateRepositories(attributions, opts, logger)
+	}
+	if rules := o.firstPartyRules(); !rules.IsZero() {
+		if o.keepFirstParty {
+			attributions = attribution.FlagFirstParty(attributions, rules)
+		} else {
+			attributions = attribution.ExcludeFirstParty(attributions, rules, logger)
+		}
+	}
+	if o.sortKey != "" {
+		// The key was checked by validate
+		key, _ := attribution.ParseSortKey(o.sortKey)
+		attributions = attribution.Sort(attributions, attribution.SortOptions{Key: key, IgnoreCase: o.sortIgnoreCase})
+	}
+	return attributions
+}
+
+// correct applies the -corrections file, if selected, to the attributions.
+func (o *options) correct(
+	attributions []attribution.Attribution,
+	logger *slog.Logger,
+) ([]attribution.Attribution, error) {
+	if o.corrections == "" {
+		return attributions, nil
+	}
+
+	corrections, err := policy.LoadCorrections(o.corrections)
+	if err != nil {
+		return nil, err
+	}
+	return corrections.Apply(attributions, logger), nil
+}
+
+// scopes returns the lower-cased scopes selected by -skip-scopes.
+func (o *options) scopes() []string {
+	if o.skipScopes == "" {
+		return nil
+	}
+	scopes := strings.Split(strings.ToLower(o.skipScopes), ",")
+	for i, scope := range scopes {
+		scopes[i] = strings.TrimSpace(scope)
+	}
+	return scopes
+}
+
+// firstPartyRules builds the first-party rules from the -first-party, -first-party-supplier, -first-party-name, and
+// -first-party-purl flags.
+func (o *options) firstPartyRules() attribution.FirstPartyRules {
+	var rules attribution.FirstPartyRules
+	if o.firstParty != "" {
+		rules.Namespaces = strings.Split(o.firstParty, ",")
+	}
+	if o.firstSuppliers != "" {
+		rules.Suppliers = strings.Split(o.firstSuppliers, ",")
+	}
+	if o.firstNames != "" {
+		rules.Names = strings.Split(o.firstNames, ",")
+	}
+	if o.firstPurls != "" {
+		rules.Purls = strings.Split(o.firstPurls, ",")
+	}
+	return rules
+}
+
+// saveRun saves the attributions to the history store selected by the flags, if any.
+func (o *options) saveRun(ctx context.Context, attributions []attribution.Attribution) error {
+	if o.storeDir == "" {
+		return nil
+	}
+	return store.NewFileStore(o.storeDir).Save(ctx, store.Run{
+		Product:      o.product,
+		Version:      o.productVersion,
+		Timestamp:    time.Now().UTC(),
+		Attributions: attributions,
+	})
+}
+
+// verify checks the URLs of the attributions if -verify-urls is set (see linkcheck.Verifier), reusing and updating
+// the -verify-urls-cache file, or the cache in -cache-dir, if selected.
+func (o *options) verify(
+	ctx context.Context,
+	attributions []attribution.Attribution,
+	logger *slog.Logger,
+) ([]attribution.Attribution, error) {
+	if !o.verifyURLs {
+		return attributions, nil
+	}
+
+	verifier, cachePath := o.linkVerifier(logger)
+	verified, err := verifier.Verify(ctx, attributions)
+	if err != nil {
+		return nil, err
+	}
+	saveCache(verifier.Cache, cachePath, logger)
+	return verified, nil
+}
+
+// linkVerifier returns a verifier with the -verify-urls-rate limit, reusing the results cached in the
+// -verify-urls-cache file, or the cache in -cache-dir, if selected, and the path of that cache file.
+func (o *options) linkVerifier(logger *slog.Logger) (*linkcheck.Verifier, string) {
+	verifier := &linkcheck.Verifier{
+		Fallback: o.verifyFallback,
+		MaxAge:   o.cacheTTL,
+		Offline:  o.offline,
+		Logger:   logger,
+	}
+	if o.verifyRate > 0 {
+		verifier.Interval = time.Second / time.Duration(o.verifyRate)
+	}
+	cachePath := o.cachePath(o.verifyCache, "links.json")
+	verifier.Cache = openCache(cachePath, linkcheck.LoadCache, linkcheck.NewCache, logger)
+	return verifier, cachePath
+}
+
+// fetchLicenseTexts fetches the missing license texts of the attributions if -fetch-license-texts is set (see
+// licensefetch.Fetcher), reusing and updating the -license-text-cache file, or the cache in -cache-dir, if selected.
+func (o *options) fetchLicenseTexts(
+	ctx context.Context,
+	attributions []attribution.Attribution,
+	logger *slog.Logger,
+) ([]attribution.Attribution, error) {
+	if !o.fetchTexts {
+		return attributions, nil
+	}
+
+	fetcher := &licensefetch.Fetcher{MaxAge: o.cacheTTL, Offline: o.offline, Logger: logger}
+	cachePath := o.cachePath(o.textCache, "license-texts.json")
+	fetcher.Cache = openCache(cachePath, licensefetch.LoadCache, licensefetch.NewCache, logger)
+
+	filled, err := fetcher.Fill(ctx, attributions)
+	if err != nil {
+		return nil, err
+	}
+	saveCache(fetcher.Cache, cachePath, logger)
+	return filled, nil
+}
+
+// loadLicenseTexts downloads the texts of the standard licenses that are not bundled from the SPDX license list (see
+// licensefetch.Fetcher.LoadLicenseTexts) if -fetch-license-texts is set, or -enrich looks up Go modules, whose license
+// files are identified by their text: the common licenses, and the licenses of the attributions, so notices can
+// reproduce them. The texts are cached like fetched license texts.
+func (o *options) loadLicenseTexts(
+	ctx context.Context,
+	attributions []attribution.Attribution,
+	logger *slog.Logger,
+) error {
+	// The purl types were checked by validate
+	sources, _ := o.enrichSources()
+	if _, goModules := sources[packageurl.TypeGolang]; !o.fetchTexts && !goModules {
+		return nil
+	}
+
+	ids := licensefetch.CommonLicenseIDs()
+	for _, a := range attributions {
+		if a.License != nil {
+			ids = appendUnique(ids, attribution.LicenseIDs(*a.License)...)
+		}
+	}
+
+	fetcher := &licensefetch.Fetcher{MaxAge: o.cacheTTL, Offline: o.offline, Logger: logger}
+	cachePath := o.cachePath(o.textCache, "license-texts.json")
+	fetcher.Cache = openCache(cachePath, licensefetch.LoadCache, licensefetch.NewCache, logger)
+	if err := fetcher.LoadLicenseTexts(ctx, ids); err != nil {
+		return err
+	}
+	saveCache(fetcher.Cache, cachePath, logger)
+	return nil
+}
+
+// enrichSources returns the registry sources selected by -enrich, keyed by purl type.
+// Returns an error for unsupported purl types.
+func (o *options) enrichSources() (map[string]enrich.Source, error) {
+	sources := make(map[string]enrich.Source)
+	if o.enrich == "" {
+		return sources, nil
+	}
+
+	all := enrich.DefaultSources()
+	if strings.EqualFold(strings.TrimSpace(o.enrich), "all") {
+		return all, nil
+	}
+	for purlType := range strings.SplitSeq(strings.ToLower(o.enrich), ",") {
+		purlType = strings.TrimSpace(purlType)
+		source, ok := all[purlType]
+		if !ok {
+			return nil, fmt.Errorf("unsupported -enrich purl type: %s (want %s, or all)", purlType,
+				strings.Join(enrich.SourceTypes(), ", "))
+		}
+		sources[purlType] = source
+	}
+	return sources, nil
+}
+
+// enrichFromRegistries backfills the missing licenses and homepages of the attributions from the package registries
+// selected by -enrich, if any (see enrich.Enricher). The GitHub license API is queried with the GITHUB_TOKEN
+// environment variable, if set.
+func (o *options) enrichFromRegistries(
+	ctx context.Context,
+	attributions []attribution.Attribution,
+	logger *slog.Logger,
+) ([]attribution.Attribution, error) {
+	// The purl types were checked by validate
+	sources, _ := o.enrichSources()
+	if len(sources) == 0 {
+		return attributions, nil
+	}
+	if gh, ok := sources[packageurl.TypeGithub].(*enrich.GitHub); ok {
+		gh.Token = os.Getenv("GITHUB_TOKEN")
+		gh.Logger = logger
+	}
+
+	enricher := &enrich.Enricher{Sources: sources, MaxAge: o.cacheTTL, Offline: o.offline, Logger: logger}
+	cachePath := o.cachePath("", "registries.json")
+	enricher.Cache = openCache(cachePath, enrich.LoadCache, enrich.NewCache, logger)
+
+	enriched, err := enricher.Enrich(ctx, attributions)
+	if err != nil {
+		return nil, err
+	}
+	saveCache(enricher.Cache, cachePath, logger)
+	return enriched, nil
+}
+
+// makeCacheDir creates the -cache-dir directory, if selected and missing.
+func (o *options) makeCacheDir() error {
+	if o.cacheDir == "" {
+		return nil
+	}
+	const dirPerm = 0o755
+	return os.MkdirAll(o.cacheDir, dirPerm)
+}
+
+// cachePath returns the path of a cache file: the file selected by its own flag if set, or else the file with the
+// given name in the -cache-dir directory. Returns an empty string if neither is selected.
+func (o *options) cachePath(file, name string) string {
+	if file != "" || o.cacheDir == "" {
+		return file
+	}
+	return filepath.Join(o.cacheDir, name)
+}
+
+// openCache loads the cache file at path with load, or returns nil if path is empty. An unreadable cache file is
+// replaced by an empty cache from newCache, with a warning.
+func openCache[T any](
+	path string,
+	load func(string) (*cache.Cache[T], error),
+	newCache func() *cache.Cache[T],
+	logger *slog.Logger,
+) *cache.Cache[T] {
+	if path == "" {
+		return nil
+	}
+	c, err := load(path)
+	if err != nil {
+		logger.Warn("ignoring unreadable cache", "file", path, "error", err)
+		return newCache()
+	}
+	return c
+}
+
+// saveCache writes c, if not nil, to the cache file at path, logging failures.
+func saveCache[T any](c *cache.Cache[T], path string, logger *slog.Logger) {
+	if c == nil {
+		return
+	}
+	if err := c.Save(path); err != nil {
+		logger.Error("failed to save cache", "file", path, "error", err)
+	}
+}
+
+// reportUnknownLicenses writes the attributions whose license could not be determined to the -unknown-licenses file,
+// if selected, and logs how many there are.
+func (o *options) reportUnknownLicenses(attributions []attribution.Attribution, logger *slog.Logger) error {
+	if o.unknownFile == "" {
+		return nil
+	}
+
+	report := attribution.ReportUnknownLicenses(attributions)
+	if len(report.Packages) > 0 {
+		logger.Warn("packages with unknown licenses", "count", len(report.Packages), "report", o.unknownFile)
+	}
+	return writeJSONFile(o.unknownFile, report)
+}
+
+// reportLicenseConflicts writes the packages found with different licenses to the -license-conflicts file, if
+// selected, and logs how many there are.
+func (o *options) reportLicenseConflicts(report *attribution.LicenseConflictReport, logger *slog.Logger) error {
+	if o.conflictsFile == "" {
+		return nil
+	}
+
+	if len(report.Conflicts) > 0 {
+		logger.Warn("packages with conflicting licenses", "count", len(report.Conflicts), "report", o.conflictsFile)
+	}
+	return writeJSONFile(o.conflictsFile, report)
+}
+
+// writeFileReport writes the outcome of each SBOM file to the -file-report file, if selected.
+func (o *options) writeFileReport(report *sbomattr.ProcessReport, logger *slog.Logger) {
+	if o.fileReport == "" {
+		return
+	}
+	if err := writeJSONFile(o.fileReport, report); err != nil {
+		logger.Error("failed to write file report", "file", o.fileReport, "error", err)
+	}
+}
+
+// checkPins logs and returns the deviations of the attributions from the -pins file, if any.
+func (o *options) checkPins(attributions []attribution.Attribution, logger *slog.Logger) ([]policy.Deviation, error) {
+	if o.pinsFile == "" {
+		return nil, nil
+	}
+
+	pins, err := policy.LoadPins(o.pinsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	deviations := pins.Check(attributions)
+	for _, d := range deviations {
+		logger.Error("attribution deviates from pin", "purl", d.Purl, "field", d.Field, "want", d.Want, "got", d.Got)
+	}
+	return deviations, nil
+}
+
+// forbiddenCategories returns the license categories selected by -forbid, where "copyleft" stands for strong
+// copyleft. Returns an error for unknown categories.
+func (o *options) forbiddenCategories() ([]attribution.LicenseCategory, error) {
+	if o.forbid == "" {
+		return nil, nil
+	}
+
+	var categories []attribution.LicenseCategory
+	for name := range strings.SplitSeq(o.forbid, ",") {
+		if strings.EqualFold(strings.TrimSpace(name), "copyleft") {
+			categories = append(categories, attribution.LicenseCategoryStrongCopyleft)
+			continue
+		}
+		category, ok := attribution.ParseLicenseCategory(name)
+		if !ok {
+			return nil, fmt.Errorf("unsupported -forbid category: %s (want copyleft, %s)", name, categoryNames())
+		}
+		categories = append(categories, category)
+	}
+	return categories, nil
+}
+
+// categoryNames returns the license category names, comma-separated.
+func categoryNames() string {
+	names := make([]string, 0, len(attribution.LicenseCategories()))
+	for _, c := range attribution.LicenseCategories() {
+		names = append(names, string(c))
+	}
+	return strings.Join(names, ", ")
+}
+
+// checkForbidden logs the attributions whose license falls in a -forbid category, and returns them.
+func (o *options) checkForbidden(attributions []attribution.Attribution, logger *slog.Logger) []policy.Violation {
+	// The categories were checked by validate
+	categories, _ := o.forbiddenCategories()
+	if len(categories) == 0 {
+		return nil
+	}
+
+	violations := policy.Forbid{Categories: categories}.Check(attributions)
+	for _, v := range violations {
+		logger.Error("forbidden license", "name", v.Attribution.QualifiedName(), "version", v.Attribution.Version,
+			"purl", v.Attribution.Purl, "license", v.License, "category", v.Category)
+	}
+	return violations
+}
+
+// checkPolicy logs the attributions violating the -deny-licenses policy, if any. With -webhook, the violations that
+// are new compared to the last stored run of the product are posted to the webhook. It must be called before saveRun,
+// so the current run is not its own baseline.
+func (o *options) checkPolicy(ctx context.Context, attributions []attribution.Attribution, logger *slog.Logger) error {
+	if o.denyLicenses == "" {
+		return nil
+	}
+
+	p := policy.Policy{DenyLicenses: strings.Split(o.denyLicenses, ",")}
+	violations := p.Check(attributions)
+	for _, v := range violations {
+		logger.Error("license policy violation", "name", v.Attribution.Name, "purl", v.Attribution.Purl,
+			"license", v.License)
+	}
+
+	if o.webhookURL == "" {
+		return nil
+	}
+
+	runs, err := store.NewFileStore(o.storeDir).Runs(ctx, o.product)
+	if err != nil {
+		return fmt.Errorf("read baseline: %w", err)
+	}
+	var baseline []policy.Violation
+	if len(runs) > 0 {
+		baseline = p.Check(runs[len(runs)-1].Attributions)
+	}
+
+	newViolations := policy.NewViolations(violations, baseline)
+	if len(newViolations) == 0 {
+		return nil
+	}
+
+	// The format was checked by validate
+	webhookFormat, _ := notify.ParseFormat(o.webhookFormat)
+	webhook := notify.Webhook{URL: o.webhookURL, Format: webhookFormat}
+	return webhook.Notify(ctx, notify.Notification{
+		Product:    o.product,
+		Version:    o.productVersion,
+		Violations: newViolations,
+	})
+}
+
+// parseDelimiter parses the -delimiter flag value into a single rune.
+// The value "tab" (or a literal "\t") selects a tab character.
+func parseDelimiter(s string) (rune, error) {
+	if s == "tab" || s == `\t` {
+		return '\t', nil
+	}
+	runes := []rune(s)
+	if len(runes) != 1 {
+		return 0, errors.New("delimiter must be a single character")
+	}
+	return runes[0], nil
+}
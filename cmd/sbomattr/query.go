@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/boringbin/sbomattr/store"
+)
+
+// allProducts is the -product value that searches every stored product.
+const allProducts = "all"
+
+// runQuery runs the query command, which searches the runs saved with -store and writes matching components with
+// their product and version to w. Returns the process exit code.
+func runQuery(args []string, w io.Writer) int {
+	fs := flag.NewFlagSet("sbomattr query", flag.ContinueOnError)
+	verbose := fs.Bool("v", false, "Verbose output (debug mode)")
+	storeDir := fs.String("store", "", "History directory the results were saved to (required)")
+	license := fs.String("license", "", "License ID (or ID prefix, e.g. GPL) to search for (required)")
+	product := fs.String("product", allProducts, "Product to search, or \"all\"")
+	outputFormat := fs.String("format", "csv", "Output format: csv, json")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: sbomattr query [OPTIONS]\n\n")
+		fmt.Fprintf(fs.Output(), "Search stored results for components matching a license.\n\n")
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitSuccess
+		}
+		return exitInvalidArgs
+	}
+
+	logger := setupLogger(*verbose)
+
+	if *storeDir == "" || *license == "" {
+		logger.Error("query requires -store and -license")
+		fs.Usage()
+		return exitInvalidArgs
+	}
+	if *outputFormat != "csv" && *outputFormat != "json" {
+		logger.Error("unsupported output format", "format", *outputFormat)
+		return exitInvalidArgs
+	}
+
+	searchProduct := *product
+	if searchProduct == allProducts {
+		searchProduct = ""
+	}
+
+	matches, err := store.Search(
+		context.Background(), store.NewFileStore(*storeDir), searchProduct, store.MatchLicense(*license))
+	if err != nil {
+		logger.Error("failed to search store", "store", *storeDir, "error", err)
+		return exitRuntimeError
+	}
+
+	if err = writeMatches(w, *outputFormat, matches); err != nil {
+		logger.Error("failed to write output", "format", *outputFormat, "error", err)
+		return exitRuntimeError
+	}
+
+	return exitSuccess
+}
+
+// writeMatches writes query matches to the provided writer as CSV or JSON.
+func writeMatches(w io.Writer, outputFormat string, matches []store.Match) error {
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(matches)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"Product", "Version", "Name", "License", "Purl"}); err != nil {
+		return fmt.Errorf("write CSV header: %w", err)
+	}
+	for _, m := range matches {
+		license := ""
+		if m.Attribution.License != nil {
+			license = *m.Attribution.License
+		}
+		record := []string{m.Product, m.Version, m.Attribution.Name, license, m.Attribution.Purl}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("write CSV record: %w", err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
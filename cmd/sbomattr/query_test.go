@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/store"
+)
+
+// newQueryStore creates a store with runs of two products for the query tests.
+func newQueryStore(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	s := store.NewFileStore(dir)
+	mit, agpl := "MIT", "AGPL-3.0-only"
+
+	runs := []store.Run{
+		{Product: "api", Version: "v1", Attributions: []attribution.Attribution{
+			{Name: "express", License: &mit, Purl: "pkg:npm/express@4.18.2"},
+			{Name: "ghostscript", License: &agpl, Purl: "pkg:generic/ghostscript@10.0"},
+		}},
+		{Product: "web", Version: "v2", Attributions: []attribution.Attribution{
+			{Name: "react", License: &mit, Purl: "pkg:npm/react@18.2.0"},
+		}},
+	}
+	for _, run := range runs {
+		if err := s.Save(context.Background(), run); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	return dir
+}
+
+// TestRunQuery tests the query command.
+func TestRunQuery(t *testing.T) {
+	t.Parallel()
+
+	dir := newQueryStore(t)
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		want     string
+	}{
+		{
+			name:     "all products",
+			args:     []string{"-store", dir, "-license", "AGPL-3.0", "-product", "all"},
+			wantCode: exitSuccess,
+			want: "Product,Version,Name,License,Purl\n" +
+				"api,v1,ghostscript,AGPL-3.0-only,pkg:generic/ghostscript@10.0\n",
+		},
+		{
+			name:     "single product",
+			args:     []string{"-store", dir, "-license", "MIT", "-product", "web"},
+			wantCode: exitSuccess,
+			want:     "Product,Version,Name,License,Purl\nweb,v2,react,MIT,pkg:npm/react@18.2.0\n",
+		},
+		{
+			name:     "no matches",
+			args:     []string{"-store", dir, "-license", "GPL"},
+			wantCode: exitSuccess,
+			want:     "Product,Version,Name,License,Purl\n",
+		},
+		{name: "missing license", args: []string{"-store", dir}, wantCode: exitInvalidArgs},
+		{name: "missing store", args: []string{"-license", "MIT"}, wantCode: exitInvalidArgs},
+		{
+			name:     "invalid format",
+			args:     []string{"-store", dir, "-license", "MIT", "-format", "xml"},
+			wantCode: exitInvalidArgs,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if code := runQuery(tt.args, &buf); code != tt.wantCode {
+				t.Fatalf("runQuery() exit code = %d, want %d", code, tt.wantCode)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("runQuery() output = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+// TestRunQuery_JSON tests the query command with JSON output.
+func TestRunQuery_JSON(t *testing.T) {
+	t.Parallel()
+
+	dir := newQueryStore(t)
+
+	var buf bytes.Buffer
+	if code := runQuery([]string{"-store", dir, "-license", "MIT", "-format", "json"}, &buf); code != exitSuccess {
+		t.Fatalf("runQuery() exit code = %d, want %d", code, exitSuccess)
+	}
+
+	var matches []store.Match
+	if err := json.Unmarshal(buf.Bytes(), &matches); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("got %d matches, want 2", len(matches))
+	}
+	if matches[0].Product != "api" || matches[0].Attribution.Name != "express" {
+		t.Errorf("matches[0] = %+v, want api/express", matches[0])
+	}
+}
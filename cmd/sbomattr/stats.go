@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/boringbin/sbomattr"
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/format"
+)
+
+// fileStats counts the input files of the stats command by outcome and SBOM format.
+type fileStats struct {
+	Total     int            `json:"total"`
+	Processed int            `json:"processed"`
+	Skipped   int            `json:"skipped"`
+	Failed    int            `json:"failed"`
+	Formats   map[string]int `json:"formats"`
+}
+
+// sbomStats is the output of the stats command: the input files, and the statistics of the deduplicated
+// attributions.
+type sbomStats struct {
+	Files fileStats `json:"files"`
+	format.Stats
+	// UnknownLicensePercent and MissingPurlPercent are the percentages of packages with an unknown license and
+	// without a purl.
+	UnknownLicensePercent float64 `json:"unknownLicensePercent"`
+	MissingPurlPercent    float64 `json:"missingPurlPercent"`
+}
+
+// runStats runs the stats command, which processes SBOM files and directories and writes statistics about the input
+// files and their deduplicated packages to w, without producing a notice. Returns the process exit code.
+func runStats(args []string, w io.Writer) int {
+	fs := flag.NewFlagSet("sbomattr stats", flag.ContinueOnError)
+	verbose := fs.Bool("v", false, "Verbose output (debug mode)")
+	outputFormat := fs.String("format", "text", "Output format: text, json")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: sbomattr stats [OPTIONS] <file-or-directory>...\n\n")
+		fmt.Fprintf(fs.Output(), "Print statistics about SBOMs and their packages to assess their quality.\n\n")
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitSuccess
+		}
+		return exitInvalidArgs
+	}
+
+	logger := setupLogger(*verbose)
+
+	if fs.NArg() == 0 {
+		logger.Error("expected at least one SBOM file or directory")
+		fs.Usage()
+		return exitInvalidArgs
+	}
+	if *outputFormat != "text" && *outputFormat != "json" {
+		logger.Error("unsupported output format", "format", *outputFormat)
+		return exitInvalidArgs
+	}
+
+	files := expandPaths(fs.Args(), false, logger)
+	if len(files) == 0 {
+		logger.Error("no SBOM files found")
+		return exitInvalidArgs
+	}
+
+	var report sbomattr.ProcessReport
+	processor := sbomattr.NewProcessor(sbomattr.WithLogger(logger), sbomattr.WithReport(&report))
+	attributions, err := processor.ProcessFiles(context.Background(), files)
+	if err != nil {
+		logger.Error("failed to process SBOM files", "error", err)
+		return exitInvalidSBOM
+	}
+
+	if err = writeStats(w, *outputFormat, summarizeSBOMs(report, attributions)); err != nil {
+		logger.Error("failed to write output", "format", *outputFormat, "error", err)
+		return exitRuntimeError
+	}
+	return exitSuccess
+}
+
+// summarizeSBOMs computes the statistics of the input files described by report and of their deduplicated
+// attributions.
+func summarizeSBOMs(report sbomattr.ProcessReport, attributions []attribution.Attribution) sbomStats {
+	files := fileStats{Total: len(report.Files), Formats: make(map[string]int)}
+	for _, file := range report.Files {
+		switch file.Status {
+		case sbomattr.FileProcessed, sbomattr.FileCached:
+			files.Processed++
+		case sbomattr.FileSkipped:
+			files.Skipped++
+		case sbomattr.FileFailed:
+			files.Failed++
+		}
+		if file.Format != "" {
+			files.Formats[file.Format]++
+		}
+	}
+
+	stats := format.Summarize(attributions)
+	return sbomStats{
+		Files:                 files,
+		Stats:                 stats,
+		UnknownLicensePercent: percent(stats.UnknownLicenses, stats.Packages),
+		MissingPurlPercent:    percent(stats.MissingPurls, stats.Packages),
+	}
+}
+
+// percent returns n as a percentage of total, or 0 if total is 0.
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(total)
+}
+
+// writeStats writes the statistics to the provided writer as JSON, or as text: the file and package totals, followed
+// by the counts per SBOM format, license, and ecosystem, with their share of the packages.
+func writeStats(w io.Writer, outputFormat string, stats sbomStats) error {
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(stats)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Files:\t%d\n", stats.Files.Total)
+	fmt.Fprintf(tw, "  Processed:\t%d\n", stats.Files.Processed)
+	fmt.Fprintf(tw, "  Skipped:\t%d\n", stats.Files.Skipped)
+	fmt.Fprintf(tw, "  Failed:\t%d\n", stats.Files.Failed)
+	fmt.Fprintf(tw, "Packages:\t%d\n", stats.Packages)
+	fmt.Fprintf(tw, "Unknown licenses:\t%d (%.1f%%)\n", stats.UnknownLicenses, stats.UnknownLicensePercent)
+	fmt.Fprintf(tw, "No license (NONE):\t%d\n", stats.NoLicense)
+	fmt.Fprintf(tw, "Missing purls:\t%d (%.1f%%)\n", stats.MissingPurls, stats.MissingPurlPercent)
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("write stats: %w", err)
+	}
+
+	formats := make([]format.Count, 0, len(stats.Files.Formats))
+	for _, name := range slices.Sorted(maps.Keys(stats.Files.Formats)) {
+		formats = append(formats, format.Count{Value: name, Count: stats.Files.Formats[name]})
+	}
+	sections := []struct {
+		title  string
+		counts []format.Count
+		total  int
+	}{
+		{title: "Formats", counts: formats, total: stats.Files.Total},
+		{title: "Licenses", counts: stats.Licenses, total: stats.Packages},
+		{title: "Ecosystems", counts: stats.Ecosystems, total: stats.Packages},
+	}
+	for _, section := range sections {
+		if len(section.counts) == 0 {
+			continue
+		}
+		fmt.Fprintf(tw, "\n%s:\n", section.title)
+		for _, c := range section.counts {
+			fmt.Fprintf(tw, "  %s\t%d\t(%.1f%%)\n", c.Value, c.Count, percent(c.Count, section.total))
+		}
+		if err := tw.Flush(); err != nil {
+			return fmt.Errorf("write stats: %w", err)
+		}
+	}
+	return nil
+}
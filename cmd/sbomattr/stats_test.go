@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// statsInputs is the directory of the stats fixtures: an SPDX document, a CycloneDX BOM, and a file that is not an
+// SBOM.
+const statsInputs = "testdata/stats"
+
+// TestRunStats tests the text statistics of the stats command.
+func TestRunStats(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if code := runStats([]string{statsInputs}, &buf); code != exitSuccess {
+		t.Fatalf("runStats() exit code = %d, want %d", code, exitSuccess)
+	}
+
+	want := "Files:              3\n" +
+		"  Processed:        2\n" +
+		"  Skipped:          0\n" +
+		"  Failed:           1\n" +
+		"Packages:           3\n" +
+		"Unknown licenses:   1 (33.3%)\n" +
+		"No license (NONE):  0\n" +
+		"Missing purls:      1 (33.3%)\n" +
+		"\n" +
+		"Formats:\n" +
+		"  cyclonedx  1  (33.3%)\n" +
+		"  spdx       1  (33.3%)\n" +
+		"\n" +
+		"Licenses:\n" +
+		"  Apache-2.0  1  (33.3%)\n" +
+		"  MIT         1  (33.3%)\n" +
+		"\n" +
+		"Ecosystems:\n" +
+		"  npm   1  (33.3%)\n" +
+		"  pypi  1  (33.3%)\n"
+	if got := buf.String(); got != want {
+		t.Errorf("runStats() output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// TestRunStats_JSON tests the JSON statistics of the stats command.
+func TestRunStats_JSON(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if code := runStats([]string{"-format", "json", statsInputs}, &buf); code != exitSuccess {
+		t.Fatalf("runStats() exit code = %d, want %d", code, exitSuccess)
+	}
+
+	var stats sbomStats
+	if err := json.Unmarshal(buf.Bytes(), &stats); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	if stats.Files.Total != 3 || stats.Files.Failed != 1 || stats.Files.Formats["spdx"] != 1 {
+		t.Errorf("files = %+v, want 3 files with 1 failed and 1 SPDX document", stats.Files)
+	}
+	if stats.Packages != 3 || stats.MissingPurls != 1 || int(stats.MissingPurlPercent) != 33 {
+		t.Errorf("stats = %+v, want 3 packages with 1 missing purl", stats)
+	}
+}
+
+// TestRunStats_InvalidArgs tests the argument errors of the stats command.
+func TestRunStats_InvalidArgs(t *testing.T) {
+	t.Parallel()
+
+	dir := statsInputs
+	for _, args := range [][]string{
+		{},
+		{"-format", "csv", dir},
+		{filepath.Join(dir, "missing")},
+	} {
+		var buf bytes.Buffer
+		if code := runStats(args, &buf); code != exitInvalidArgs {
+			t.Errorf("runStats(%s) exit code = %d, want %d", strings.Join(args, " "), code, exitInvalidArgs)
+		}
+	}
+
+	var buf bytes.Buffer
+	if code := runStats([]string{filepath.Join(dir, "broken.json")}, &buf); code != exitInvalidSBOM {
+		t.Errorf("runStats(broken.json) exit code = %d, want %d", code, exitInvalidSBOM)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/boringbin/sbomattr/sbomcheck"
+)
+
+// fileCheck is the result of checking one input file.
+type fileCheck struct {
+	File string `json:"file"`
+	sbomcheck.Result
+}
+
+// runValidate runs the validate command, which checks SBOM files against the schemas of their formats and lints them
+// for problems that degrade the notice, writing a report per file to w. Returns the process exit code.
+func runValidate(args []string, w io.Writer) int {
+	fs := flag.NewFlagSet("sbomattr validate", flag.ContinueOnError)
+	verbose := fs.Bool("v", false, "Verbose output (debug mode)")
+	outputFormat := fs.String("format", "text", "Output format: text, json")
+	strict := fs.Bool("strict", false, "Fail on warnings (e.g. missing purls or NOASSERTION licenses), not only errors")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: sbomattr validate [OPTIONS] <file-or-directory>...\n\n")
+		fmt.Fprintf(fs.Output(), "Check SBOMs against the SPDX and CycloneDX JSON schemas and lint their packages.\n\n")
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitSuccess
+		}
+		return exitInvalidArgs
+	}
+
+	logger := setupLogger(*verbose)
+
+	if fs.NArg() == 0 {
+		logger.Error("expected at least one SBOM file or directory")
+		fs.Usage()
+		return exitInvalidArgs
+	}
+	if *outputFormat != "text" && *outputFormat != "json" {
+		logger.Error("unsupported output format", "format", *outputFormat)
+		return exitInvalidArgs
+	}
+
+	files := expandPaths(fs.Args(), false, logger)
+	if len(files) == 0 {
+		logger.Error("no SBOM files found")
+		return exitInvalidArgs
+	}
+
+	checks, readFailed := checkFiles(files, logger)
+	if err := writeChecks(w, *outputFormat, checks); err != nil {
+		logger.Error("failed to write output", "format", *outputFormat, "error", err)
+		return exitRuntimeError
+	}
+
+	for _, check := range checks {
+		if check.Errors() > 0 || (*strict && check.Warnings() > 0) {
+			return exitInvalidSBOM
+		}
+	}
+	if readFailed {
+		return exitRuntimeError
+	}
+	return exitSuccess
+}
+
+// checkFiles checks the SBOM files, also reporting whether any of them could not be read.
+func checkFiles(files []string, logger *slog.Logger) ([]fileCheck, bool) {
+	checks := make([]fileCheck, 0, len(files))
+	readFailed := false
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			logger.Error("failed to read file", "file", file, "error", err)
+			readFailed = true
+			continue
+		}
+		checks = append(checks, fileCheck{File: file, Result: sbomcheck.Check(data)})
+	}
+	return checks, readFailed
+}
+
+// writeChecks writes the results of the checked files to the provided writer as JSON, or as text listing the issues
+// of each file under a "<file>: <format>, <n> errors, <n> warnings" line.
+func writeChecks(w io.Writer, outputFormat string, checks []fileCheck) error {
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(checks)
+	}
+
+	for _, check := range checks {
+		summary := "valid"
+		if len(check.Issues) > 0 {
+			summary = fmt.Sprintf("%s, %s", plural(check.Errors(), "error"), plural(check.Warnings(), "warning"))
+		}
+		if check.Format != "" {
+			summary = check.Format + ", " + summary
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s\n", check.File, summary); err != nil {
+			return err
+		}
+		for _, issue := range check.Issues {
+			if _, err := fmt.Fprintf(w, "  %s\n", issue); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// plural returns n followed by noun, pluralized unless n is 1, e.g. "2 errors".
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// validateInputs is the directory of the validate fixtures: a valid CycloneDX BOM, one whose component has no purl,
+// and one whose component has no name.
+const validateInputs = "testdata/validate"
+
+// TestRunValidate tests that the validate command reports the issues of each file and fails on errors.
+func TestRunValidate(t *testing.T) {
+	t.Parallel()
+
+	dir := validateInputs
+
+	var buf bytes.Buffer
+	if code := runValidate([]string{dir}, &buf); code != exitInvalidSBOM {
+		t.Fatalf("runValidate() exit code = %d, want %d", code, exitInvalidSBOM)
+	}
+
+	want := filepath.Join(dir, "a-valid.json") + ": cyclonedx, valid\n" +
+		filepath.Join(dir, "b-warning.json") + ": cyclonedx, 0 errors, 1 warning\n" +
+		`  warning /components/0: component "lodash" has no purl (missing-purl)` + "\n" +
+		filepath.Join(dir, "c-error.json") + ": cyclonedx, 1 error, 0 warnings\n" +
+		`  error /components/0: missing required property "name" (schema)` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("runValidate() output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// TestRunValidate_Strict tests that warnings only fail validation with -strict.
+func TestRunValidate_Strict(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(validateInputs, "b-warning.json")
+
+	var buf bytes.Buffer
+	if code := runValidate([]string{file}, &buf); code != exitSuccess {
+		t.Errorf("runValidate() exit code = %d, want %d", code, exitSuccess)
+	}
+	if code := runValidate([]string{"-strict", file}, &buf); code != exitInvalidSBOM {
+		t.Errorf("runValidate(-strict) exit code = %d, want %d", code, exitInvalidSBOM)
+	}
+}
+
+// TestRunValidate_JSON tests the JSON report of the validate command.
+func TestRunValidate_JSON(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(validateInputs, "c-error.json")
+
+	var buf bytes.Buffer
+	if code := runValidate([]string{"-format", "json", file}, &buf); code != exitInvalidSBOM {
+		t.Fatalf("runValidate() exit code = %d, want %d", code, exitInvalidSBOM)
+	}
+
+	var checks []struct {
+		File   string `json:"file"`
+		Format string `json:"format"`
+		Issues []struct {
+			Severity string `json:"severity"`
+			Rule     string `json:"rule"`
+			Path     string `json:"path"`
+		} `json:"issues"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &checks); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	if len(checks) != 1 || checks[0].File != file || checks[0].Format != "cyclonedx" || len(checks[0].Issues) != 1 {
+		t.Fatalf("runValidate() = %+v, want one issue in %s", checks, file)
+	}
+	issue := checks[0].Issues[0]
+	if issue.Severity != "error" || issue.Rule != "schema" || issue.Path != "/components/0" {
+		t.Errorf("issue = %+v, want a schema error at /components/0", issue)
+	}
+}
+
+// TestRunValidate_InvalidArgs tests the argument errors of the validate command.
+func TestRunValidate_InvalidArgs(t *testing.T) {
+	t.Parallel()
+
+	dir := validateInputs
+	for _, args := range [][]string{
+		{},
+		{"-format", "csv", dir},
+		{filepath.Join(dir, "missing")},
+	} {
+		var buf bytes.Buffer
+		if code := runValidate(args, &buf); code != exitInvalidArgs {
+			t.Errorf("runValidate(%s) exit code = %d, want %d", strings.Join(args, " "), code, exitInvalidArgs)
+		}
+	}
+}
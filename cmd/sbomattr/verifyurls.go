@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/boringbin/sbomattr/attrextract"
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/linkcheck"
+)
+
+// defaultVerifyConcurrency is the default of the verify-urls -concurrency flag.
+const defaultVerifyConcurrency = 4
+
+// attributionFile is an attribution file read by the verify-urls command.
+type attributionFile struct {
+	path         string
+	attributions []attribution.Attribution
+}
+
+// brokenLink is a dead URL found by the verify-urls command.
+type brokenLink struct {
+	// File is the attribution file listing the URL.
+	File    string `json:"file"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Purl    string `json:"purl,omitempty"`
+	URL     string `json:"url"`
+	// Code is the HTTP status code of the response, or 0 if the host does not resolve.
+	Code int `json:"code,omitempty"`
+	// Alternatives are the other URLs of the package that are alive, including its registry page, if any.
+	Alternatives []string `json:"alternatives"`
+}
+
+// linkReport is the report of the verify-urls command.
+type linkReport struct {
+	// Checked is the number of unique URLs listed in the files.
+	Checked int `json:"checked"`
+	// Inconclusive is the number of those URLs whose check was inconclusive, e.g. because of a timeout.
+	Inconclusive int          `json:"inconclusive"`
+	Broken       []brokenLink `json:"broken"`
+}
+
+// runVerifyURLs runs the verify-urls command, which checks the URLs of previously generated attribution files (JSON
+// or CSV output) and writes a report of the dead ones, with alternatives, to w. Returns the process exit code.
+func runVerifyURLs(args []string, w io.Writer) int {
+	fs := flag.NewFlagSet("sbomattr verify-urls", flag.ContinueOnError)
+	opts := &options{}
+	fs.BoolVar(&opts.verbose, "v", false, "Verbose output (debug mode)")
+	fs.StringVar(&opts.outputFormat, "format", "text", "Output format: text, json")
+	concurrency := fs.Int("concurrency", defaultVerifyConcurrency, "Number of URLs checked in parallel")
+	fs.IntVar(&opts.verifyRate, "rate", defaultVerifyRate, "Maximum requests per second, 0 for no limit")
+	fs.StringVar(&opts.verifyCache, "cache", "",
+		"Cache check results in this JSON file instead of links.json in -cache-dir, so later runs only check new URLs")
+	registerCacheFlags(fs, opts)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: sbomattr verify-urls [OPTIONS] <attribution-file>...\n\n")
+		fmt.Fprintf(fs.Output(), "Check the URLs of JSON or CSV attribution files and report the dead ones.\n\n")
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitSuccess
+		}
+		return exitInvalidArgs
+	}
+
+	logger := setupLogger(opts.verbose)
+
+	if fs.NArg() == 0 {
+		logger.Error("expected at least one attribution file")
+		fs.Usage()
+		return exitInvalidArgs
+	}
+	if err := opts.validateVerifyURLs(*concurrency); err != nil {
+		logger.Error("invalid options", "error", err)
+		return exitInvalidArgs
+	}
+	if err := opts.makeCacheDir(); err != nil {
+		logger.Error("failed to create cache directory", "dir", opts.cacheDir, "error", err)
+		return exitInvalidArgs
+	}
+
+	files, err := readAttributionFiles(fs.Args())
+	if err != nil {
+		logger.Error("failed to read attribution file", "error", err)
+		return exitInvalidSBOM
+	}
+
+	verifier, cachePath := opts.linkVerifier(logger)
+	verifier.Concurrency = *concurrency
+	report := checkLinks(context.Background(), verifier, files)
+	saveCache(verifier.Cache, cachePath, logger)
+
+	if err = writeLinkReport(w, opts.outputFormat, report); err != nil {
+		logger.Error("failed to write output", "format", opts.outputFormat, "error", err)
+		return exitRuntimeError
+	}
+	if len(report.Broken) > 0 {
+		return exitDeadLinks
+	}
+	return exitSuccess
+}
+
+// validateVerifyURLs checks the flag values of the verify-urls command.
+func (o *options) validateVerifyURLs(concurrency int) error {
+	switch {
+	case o.outputFormat != "text" && o.outputFormat != "json":
+		return fmt.Errorf("unsupported output format: %s", o.outputFormat)
+	case concurrency < 1:
+		return fmt.Errorf("invalid -concurrency: %d (want a positive number)", concurrency)
+	case o.verifyRate < 0:
+		return fmt.Errorf("invalid -rate: %d (want 0 or a positive number)", o.verifyRate)
+	case o.cacheTTL < 0:
+		return fmt.Errorf("invalid -cache-ttl: %s (want 0 or a positive duration)", o.cacheTTL)
+	case o.cacheTTL != 0 && o.cacheDir == "" && o.verifyCache == "":
+		return errors.New("-cache-ttl requires -cache-dir or -cache")
+	}
+	return nil
+}
+
+// readAttributionFiles reads the attribution files at paths.
+func readAttributionFiles(paths []string) ([]attributionFile, error) {
+	files := make([]attributionFile, 0, len(paths))
+	for _, path := range paths {
+		attrs, err := readAttributionFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		files = append(files, attributionFile{path: path, attributions: attrs})
+	}
+	return files, nil
+}
+
+// readAttributionFile reads the attributions of an attribution file: JSON or NDJSON output, or CSV or TSV output.
+func readAttributionFile(path string) ([]attribution.Attribution, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte("{")) ||
+		bytes.HasPrefix(trimmed, []byte("[")) {
+		return attrextract.Extract(data)
+	}
+	return attrextract.ExtractCSV(data)
+}
+
+// checkLinks checks the URLs listed in the files, i.e. the primary URLs and the typed URLs of the attributions, and
+// returns the dead ones. For each dead URL, the other URLs of the package, and the registry page generated from its
+// purl, are checked for alternatives.
+func checkLinks(ctx context.Context, verifier *linkcheck.Verifier, files []attributionFile) linkReport {
+	var listed []string
+	for _, f := range files {
+		for _, a := range f.attributions {
+			listed = appendUnique(listed, attributionURLs(a)...)
+		}
+	}
+	results := verifier.CheckAll(ctx, listed)
+
+	report := linkReport{Checked: len(listed), Broken: []brokenLink{}}
+	for _, url := range listed {
+		if results[url].Status == linkcheck.StatusUnknown {
+			report.Inconclusive++
+		}
+	}
+
+	// The candidate alternatives of each broken link
+	var candidates [][]string
+	for _, f := range files {
+		for _, a := range f.attributions {
+			for _, url := range attributionURLs(a) {
+				if results[url].Status != linkcheck.StatusDead {
+					continue
+				}
+				report.Broken = append(report.Broken, brokenLink{
+					File:    f.path,
+					Name:    a.QualifiedName(),
+					Version: a.Version,
+					Purl:    a.Purl,
+					URL:     url,
+					Code:    results[url].Code,
+				})
+				candidates = append(candidates, attributionURLs(a.WithGeneratedURLs()))
+			}
+		}
+	}
+
+	// Only the alternatives of packages with dead URLs are checked, so healthy notices cost no registry requests
+	var unchecked []string
+	for _, urls := range candidates {
+		for _, url := range urls {
+			if _, ok := results[url]; !ok {
+				unchecked = appendUnique(unchecked, url)
+			}
+		}
+	}
+	for url, r := range verifier.CheckAll(ctx, unchecked) {
+		results[url] = r
+	}
+
+	for i, urls := range candidates {
+		report.Broken[i].Alternatives = []string{}
+		for _, url := range urls {
+			if results[url].Status == linkcheck.StatusAlive {
+				report.Broken[i].Alternatives = append(report.Broken[i].Alternatives, url)
+			}
+		}
+	}
+	return report
+}
+
+// attributionURLs returns the unique URLs of an attribution: its primary URL, followed by its typed URLs.
+func attributionURLs(a attribution.Attribution) []string {
+	var urls []string
+	if a.URL != nil && *a.URL != "" {
+		urls = append(urls, *a.URL)
+	}
+	for _, u := range a.URLs {
+		urls = appendUnique(urls, u.URL)
+	}
+	return urls
+}
+
+// appendUnique appends the values to s that it does not contain yet.
+func appendUnique(s []string, values ...string) []string {
+	for _, v := range values {
+		if !slices.Contains(s, v) {
+			s = append(s, v)
+		}
+	}
+	return s
+}
+
+// writeLinkReport writes the report to the provided writer as JSON, or as text listing each dead URL with its
+// alternatives.
+func writeLinkReport(w io.Writer, outputFormat string, report linkReport) error {
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	if _, err := fmt.Fprintf(w, "Checked %s: %d broken, %d inconclusive\n", plural(report.Checked, "URL"),
+		len(report.Broken), report.Inconclusive); err != nil {
+		return err
+	}
+	for _, link := range report.Broken {
+		name := link.Name
+		if link.Version != "" {
+			name += " " + link.Version
+		}
+		status := "host not found"
+		if link.Code != 0 {
+			status = strconv.Itoa(link.Code) + " " + http.StatusText(link.Code)
+		}
+		if _, err := fmt.Fprintf(w, "\n%s: %s\n  %s (%s)\n", link.File, name, link.URL, status); err != nil {
+			return err
+		}
+		if len(link.Alternatives) == 0 {
+			if _, err := fmt.Fprintf(w, "  no alternative found\n"); err != nil {
+				return err
+			}
+		}
+		for _, alternative := range link.Alternatives {
+			if _, err := fmt.Fprintf(w, "  suggested: %s\n", alternative); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newLinkServer starts a server whose /alive path exists and every other path does not.
+func newLinkServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/alive" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// writeAttributionFile writes content to a file named name and returns its path.
+func writeAttributionFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+// TestRunVerifyURLs tests reporting the dead URLs of a CSV notice, with the other URLs of the package that are alive
+// as alternatives.
+func TestRunVerifyURLs(t *testing.T) {
+	t.Parallel()
+
+	server := newLinkServer(t)
+	notice := writeAttributionFile(t, "notice.csv", "Name,Version,License,URL,URLs\n"+
+		"lodash,4.17.21,MIT,"+server.URL+"/alive,\n"+
+		"left-pad,1.3.0,WTFPL,"+server.URL+"/gone,homepage: "+server.URL+"/gone; source: "+server.URL+"/alive\n"+
+		"zod,3.22.0,MIT,"+server.URL+"/missing,\n")
+
+	var buf bytes.Buffer
+	if code := runVerifyURLs([]string{notice}, &buf); code != exitDeadLinks {
+		t.Fatalf("runVerifyURLs() exit code = %d, want %d", code, exitDeadLinks)
+	}
+
+	want := "Checked 3 URLs: 2 broken, 0 inconclusive\n" +
+		"\n" + notice + ": left-pad 1.3.0\n" +
+		"  " + server.URL + "/gone (404 Not Found)\n" +
+		"  suggested: " + server.URL + "/alive\n" +
+		"\n" + notice + ": zod 3.22.0\n" +
+		"  " + server.URL + "/missing (404 Not Found)\n" +
+		"  no alternative found\n"
+	if buf.String() != want {
+		t.Errorf("runVerifyURLs() output = %q, want %q", buf.String(), want)
+	}
+}
+
+// TestRunVerifyURLs_JSON tests the JSON report of a JSON attribution file, and that nothing is reported for a
+// healthy file.
+func TestRunVerifyURLs_JSON(t *testing.T) {
+	t.Parallel()
+
+	server := newLinkServer(t)
+	healthy := writeAttributionFile(t, "healthy.json", `[{"name": "lodash", "url": "`+server.URL+`/alive"}]`)
+	broken := writeAttributionFile(t, "broken.json", `{"name": "zod", "purl": "pkg:npm/zod@3.22.0", "url": "`+
+		server.URL+`/missing"}`)
+
+	var buf bytes.Buffer
+	if code := runVerifyURLs([]string{"-format", "json", healthy}, &buf); code != exitSuccess {
+		t.Fatalf("runVerifyURLs() exit code = %d, want %d", code, exitSuccess)
+	}
+	if want := "{\n  \"checked\": 1,\n  \"inconclusive\": 0,\n  \"broken\": []\n}\n"; buf.String() != want {
+		t.Errorf("runVerifyURLs() output = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	// The registry page generated from the purl is an alternative, cached as alive so no request is made to it
+	registryURL := "https://www.npmjs.com/package/zod/v/3.22.0"
+	cache := writeAttributionFile(t, "links.json", `{"version": 1, "entries": {"`+registryURL+`": `+
+		`{"status": "alive", "code": 200, "checked": "2026-01-01T00:00:00Z"}}}`)
+	if code := runVerifyURLs([]string{"-format", "json", "-cache", cache, broken}, &buf); code != exitDeadLinks {
+		t.Fatalf("runVerifyURLs() exit code = %d, want %d", code, exitDeadLinks)
+	}
+
+	var report linkReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("failed to parse output %q: %v", buf.String(), err)
+	}
+	want := []brokenLink{{
+		File:         broken,
+		Name:         "zod",
+		Purl:         "pkg:npm/zod@3.22.0",
+		URL:          server.URL + "/missing",
+		Code:         http.StatusNotFound,
+		Alternatives: []string{registryURL},
+	}}
+	if report.Checked != 1 || !reflect.DeepEqual(report.Broken, want) {
+		t.Errorf("runVerifyURLs() report = %+v, want the dead URL with the registry page as alternative", report)
+	}
+}
+
+// TestRunVerifyURLs_CacheDir tests that results are cached in -cache-dir, and that -offline only uses cached
+// results.
+func TestRunVerifyURLs_CacheDir(t *testing.T) {
+	t.Parallel()
+
+	server := newLinkServer(t)
+	notice := writeAttributionFile(t, "notice.csv", "Name,URL\nzod,"+server.URL+"/missing\n")
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+
+	var buf bytes.Buffer
+	if code := runVerifyURLs([]string{"-cache-dir", cacheDir, notice}, &buf); code != exitDeadLinks {
+		t.Fatalf("runVerifyURLs() exit code = %d, want %d", code, exitDeadLinks)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "links.json")); err != nil {
+		t.Errorf("runVerifyURLs() did not write the cache: %v", err)
+	}
+
+	buf.Reset()
+	if code := runVerifyURLs([]string{"-offline", "-cache-dir", cacheDir, notice}, &buf); code != exitDeadLinks {
+		t.Errorf("runVerifyURLs() offline exit code = %d, want %d from the cached result", code, exitDeadLinks)
+	}
+
+	buf.Reset()
+	if code := runVerifyURLs([]string{"-offline", notice}, &buf); code != exitSuccess {
+		t.Fatalf("runVerifyURLs() offline without cache exit code = %d, want %d", code, exitSuccess)
+	}
+	if want := "Checked 1 URL: 0 broken, 1 inconclusive\n"; buf.String() != want {
+		t.Errorf("runVerifyURLs() offline without cache output = %q, want %q", buf.String(), want)
+	}
+}
+
+// TestRunVerifyURLs_InvalidArgs tests the verify-urls command with invalid arguments and unreadable files.
+func TestRunVerifyURLs_InvalidArgs(t *testing.T) {
+	t.Parallel()
+
+	notice := writeAttributionFile(t, "notice.csv", "Name,URL\nlodash,\n")
+	invalid := writeAttributionFile(t, "invalid.txt", "not an attribution file")
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "no files", args: nil, want: exitInvalidArgs},
+		{name: "invalid format", args: []string{"-format", "xml", notice}, want: exitInvalidArgs},
+		{name: "invalid concurrency", args: []string{"-concurrency", "0", notice}, want: exitInvalidArgs},
+		{name: "invalid rate", args: []string{"-rate", "-1", notice}, want: exitInvalidArgs},
+		{name: "invalid cache TTL", args: []string{"-cache-ttl", "-1h", notice}, want: exitInvalidArgs},
+		{name: "cache TTL without cache", args: []string{"-cache-ttl", "1h", notice}, want: exitInvalidArgs},
+		{name: "missing file", args: []string{filepath.Join(t.TempDir(), "missing.json")}, want: exitInvalidSBOM},
+		{name: "invalid file", args: []string{invalid}, want: exitInvalidSBOM},
+		{name: "no URLs", args: []string{notice}, want: exitSuccess},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if code := runVerifyURLs(tt.args, &buf); code != tt.want {
+				t.Errorf("runVerifyURLs() exit code = %d, want %d (output %q)", code, tt.want,
+					strings.TrimSpace(buf.String()))
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package cyclonedxextract
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// Filter selects which components ExtractPackagesWithOptions keeps, based on the dependency graph of the BOM.
+type Filter string
+
+const (
+	// FilterAll keeps every component.
+	FilterAll Filter = ""
+	// FilterReachable keeps the components reachable from the root component (metadata.component) in the dependency
+	// graph, i.e. the shipped code rather than everything a scan found.
+	FilterReachable Filter = "reachable"
+	// FilterDirect keeps the direct dependencies of the root component only.
+	FilterDirect Filter = "direct"
+)
+
+// ParseFilter parses a filter name ("reachable" or "direct"), ignoring case. An empty name is FilterAll.
+// Returns an error for unknown filters.
+func ParseFilter(s string) (Filter, error) {
+	s = strings.TrimSpace(s)
+	for _, filter := range []Filter{FilterAll, FilterReachable, FilterDirect} {
+		if strings.EqualFold(string(filter), s) {
+			return filter, nil
+		}
+	}
+	return "", fmt.Errorf("unknown CycloneDX dependency filter %q (valid filters: reachable, direct)", s)
+}
+
+// ExtractOptions configures ExtractPackagesWithOptions.
+type ExtractOptions struct {
+	// Filter limits the components to the dependencies of the root component. Defaults to FilterAll.
+	Filter Filter
+}
+
+// ExtractPackagesWithOptions extracts packages like ExtractPackages, keeping only the components selected by the
+// filter. Components are matched to the dependency graph by bom-ref. A component repeating the root is kept, so
+// Root handling stays with the caller. If the BOM does not record a root bom-ref or a dependency graph, the filter has
+// no starting point, so every component is kept.
+func ExtractPackagesWithOptions(bom *BOM, opts ExtractOptions) []attribution.Attribution {
+	if bom == nil || opts.Filter == FilterAll || bom.Metadata == nil || bom.Metadata.Component == nil ||
+		bom.Metadata.Component.BOMRef == "" || len(bom.Dependencies) == 0 {
+		return ExtractPackages(bom)
+	}
+
+	graph := make(map[string][]string, len(bom.Dependencies))
+	for _, dep := range bom.Dependencies {
+		graph[dep.Ref] = append(graph[dep.Ref], dep.DependsOn...)
+	}
+
+	root := bom.Metadata.Component.BOMRef
+	kept := map[string]bool{root: true}
+	if opts.Filter == FilterDirect {
+		for _, ref := range graph[root] {
+			kept[ref] = true
+		}
+	} else {
+		queue := slices.Clone(graph[root])
+		for len(queue) > 0 {
+			ref := queue[0]
+			queue = queue[1:]
+			if kept[ref] {
+				continue
+			}
+			kept[ref] = true
+			queue = append(queue, graph[ref]...)
+		}
+	}
+
+	filtered := *bom
+	filtered.Components = slices.DeleteFunc(slices.Clone(bom.Components), func(component Component) bool {
+		return !kept[component.BOMRef]
+	})
+	return ExtractPackages(&filtered)
+}
+
+// Dependencies returns the dependencies between the components of the BOM (including the root component) declared by
+// its dependency graph, identified by the attribution.DedupKey of the attributions ExtractPackages and Root return for
+// them. Dependencies on bom-refs that are not components are skipped.
+func Dependencies(bom *BOM) []attribution.Edge {
+	if bom == nil || len(bom.Dependencies) == 0 {
+		return nil
+	}
+
+	keys := make(map[string]string, len(bom.Components)+1)
+	for i, a := range ExtractPackages(bom) {
+		if ref := bom.Components[i].BOMRef; ref != "" {
+			keys[ref] = attribution.DedupKey(a)
+		}
+	}
+	if root := Root(bom); root != nil && bom.Metadata.Component.BOMRef != "" {
+		keys[bom.Metadata.Component.BOMRef] = attribution.DedupKey(*root)
+	}
+
+	var edges []attribution.Edge
+	for _, dep := range bom.Dependencies {
+		from, ok := keys[dep.Ref]
+		if !ok {
+			continue
+		}
+		for _, ref := range dep.DependsOn {
+			if to, found := keys[ref]; found {
+				edges = append(edges, attribution.Edge{From: from, To: to})
+			}
+		}
+	}
+	return edges
+}
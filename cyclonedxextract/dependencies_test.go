@@ -0,0 +1,128 @@
+package cyclonedxextract_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/cyclonedxextract"
+)
+
+// TestExtractPackagesWithOptions tests limiting components to the dependency graph of the root component.
+func TestExtractPackagesWithOptions(t *testing.T) {
+	t.Parallel()
+
+	bom := &cyclonedxextract.BOM{
+		Metadata: &cyclonedxextract.Metadata{Component: &cyclonedxextract.Component{BOMRef: "app", Name: "app"}},
+		Components: []cyclonedxextract.Component{
+			{BOMRef: "express", Name: "express"},
+			{BOMRef: "body-parser", Name: "body-parser"},
+			{BOMRef: "jest", Name: "jest"},
+			{Name: "no-ref"},
+		},
+		Dependencies: []cyclonedxextract.Dependency{
+			{Ref: "app", DependsOn: []string{"express"}},
+			{Ref: "express", DependsOn: []string{"body-parser"}},
+			{Ref: "body-parser", DependsOn: []string{"express"}},
+			{Ref: "jest"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		filter cyclonedxextract.Filter
+		want   []string
+	}{
+		{name: "all", filter: cyclonedxextract.FilterAll, want: []string{"express", "body-parser", "jest", "no-ref"}},
+		{name: "reachable", filter: cyclonedxextract.FilterReachable, want: []string{"express", "body-parser"}},
+		{name: "direct", filter: cyclonedxextract.FilterDirect, want: []string{"express"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := cyclonedxextract.ExtractOptions{Filter: tt.filter}
+			attrs := cyclonedxextract.ExtractPackagesWithOptions(bom, opts)
+			var got []string
+			for _, a := range attrs {
+				got = append(got, a.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ExtractPackagesWithOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestExtractPackagesWithOptions_NoGraph tests that every component is kept without a root bom-ref or a dependency
+// graph.
+func TestExtractPackagesWithOptions_NoGraph(t *testing.T) {
+	t.Parallel()
+
+	opts := cyclonedxextract.ExtractOptions{Filter: cyclonedxextract.FilterReachable}
+	components := []cyclonedxextract.Component{{BOMRef: "a", Name: "a"}, {BOMRef: "b", Name: "b"}}
+
+	for name, bom := range map[string]*cyclonedxextract.BOM{
+		"no root": {Components: components, Dependencies: []cyclonedxextract.Dependency{{Ref: "a"}}},
+		"no dependencies": {
+			Metadata:   &cyclonedxextract.Metadata{Component: &cyclonedxextract.Component{BOMRef: "app"}},
+			Components: components,
+		},
+	} {
+		if got := cyclonedxextract.ExtractPackagesWithOptions(bom, opts); len(got) != len(components) {
+			t.Errorf("%s: ExtractPackagesWithOptions() = %+v, want every component", name, got)
+		}
+	}
+}
+
+// TestDependencies tests the Dependencies function.
+func TestDependencies(t *testing.T) {
+	t.Parallel()
+
+	bom := &cyclonedxextract.BOM{
+		Metadata: &cyclonedxextract.Metadata{
+			Component: &cyclonedxextract.Component{BOMRef: "app", Name: "app", Purl: "pkg:npm/app@1.0.0"},
+		},
+		Components: []cyclonedxextract.Component{
+			{BOMRef: "express", Name: "express", Purl: "pkg:npm/express@4.18.2"},
+			{BOMRef: "body-parser", Name: "body-parser"},
+		},
+		Dependencies: []cyclonedxextract.Dependency{
+			{Ref: "app", DependsOn: []string{"express"}},
+			{Ref: "express", DependsOn: []string{"body-parser", "unknown"}},
+			{Ref: "unknown", DependsOn: []string{"express"}},
+		},
+	}
+
+	got := cyclonedxextract.Dependencies(bom)
+
+	want := []attribution.Edge{
+		{From: "pkg:npm/app@1.0.0", To: "pkg:npm/express@4.18.2"},
+		{From: "pkg:npm/express@4.18.2", To: "body-parser"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Dependencies() = %v, want %v", got, want)
+	}
+	if got := cyclonedxextract.Dependencies(&cyclonedxextract.BOM{Components: bom.Components}); len(got) != 0 {
+		t.Errorf("Dependencies() without a dependency graph = %v, want none", got)
+	}
+}
+
+// TestParseFilter tests the ParseFilter function.
+func TestParseFilter(t *testing.T) {
+	t.Parallel()
+
+	for input, want := range map[string]cyclonedxextract.Filter{
+		"":          cyclonedxextract.FilterAll,
+		"Reachable": cyclonedxextract.FilterReachable,
+		" direct ":  cyclonedxextract.FilterDirect,
+	} {
+		if got, err := cyclonedxextract.ParseFilter(input); err != nil || got != want {
+			t.Errorf("ParseFilter(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := cyclonedxextract.ParseFilter("transitive"); err == nil {
+		t.Error(`ParseFilter("transitive") expected error, got nil`)
+	}
+}
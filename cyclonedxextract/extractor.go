@@ -1,6 +1,11 @@
 package cyclonedxextract
 
 import (
+	"cmp"
+	"encoding/base64"
+	"slices"
+	"strings"
+
 	"github.com/boringbin/sbomattr/attribution"
 )
 
@@ -12,86 +17,276 @@ func ExtractPackages(bom *BOM) []attribution.Attribution {
 	}
 
 	packages := make([]attribution.Attribution, 0, len(bom.Components))
+	claims := licensingClaims(bom.Declarations)
+
+	for i := range bom.Components {
+		packages = append(packages, extractComponent(&bom.Components[i], claims))
+	}
+
+	return packages
+}
+
+// Root returns the attribution of the component the CycloneDX BOM describes (metadata.component), extracted like the
+// components of the BOM. ExtractPackages does not include it, since it is usually the product itself.
+// Returns nil if the BOM does not record one.
+func Root(bom *BOM) *attribution.Attribution {
+	if bom == nil || bom.Metadata == nil || bom.Metadata.Component == nil {
+		return nil
+	}
+	root := extractComponent(bom.Metadata.Component, licensingClaims(bom.Declarations))
+	return &root
+}
+
+// extractComponent extracts the attribution of one component. The claims are the licensing claims of the BOM, as
+// returned by licensingClaims.
+func extractComponent(component *Component, claims map[string][]string) attribution.Attribution {
+	p := attribution.Attribution{
+		Name:        component.Name,
+		Group:       component.Group,
+		Type:        component.Type,
+		Scope:       component.Scope,
+		Version:     component.Version,
+		Description: component.Description,
+		CPE:         component.CPE,
+		Author:      strings.TrimSpace(component.Author),
+	}
+
+	if component.Supplier != nil {
+		p.Supplier = component.Supplier.Name
+	}
+	// The publisher is the closest thing to a supplier when the component has none
+	if p.Supplier == "" {
+		p.Supplier = strings.TrimSpace(component.Publisher)
+	}
+
+	// Extract purl if available
+	if component.Purl != "" {
+		p.Purl = component.Purl
+	}
 
-	for _, component := range bom.Components {
-		p := attribution.Attribution{
-			Name: component.Name,
+	// Construct URLs: prefer external references, fall back to purl conversion, and to the CPE as a last resort
+	for _, ref := range externalRefURLs(component.ExternalReferences) {
+		p = p.WithURL(ref.Kind, ref.URL)
+	}
+	if len(p.URLs) > 0 {
+		url := p.URLs[0].URL
+		p.URL = &url
+		p = p.WithProvenance(attribution.FieldURL, attribution.ProvenanceExtracted)
+	}
+	p = p.WithGeneratedURLs()
+
+	if repo, ok := findExternalRef(component.ExternalReferences, "vcs"); ok {
+		p.SourceRepo = repo
+	}
+	if licenseURL, ok := findLicenseURL(component); ok {
+		p.LicenseURL = licenseURL
+	}
+	if text, ok := findLicenseText(component.Licenses); ok {
+		p.LicenseText = text
+		p = p.WithProvenance(attribution.FieldLicenseText, attribution.ProvenanceExtracted)
+	}
+
+	// Extract license information
+	if component.Licenses != nil {
+		license := extractLicense(component.Licenses)
+		if license != nil {
+			p.License = license
+			p = p.WithProvenance(attribution.FieldLicense, attribution.ProvenanceExtracted)
 		}
+	}
 
-		// Extract purl if available
-		if component.Purl != "" {
-			p.Purl = component.Purl
+	for _, hash := range component.Hashes {
+		if hash.Algorithm == "" || hash.Content == "" {
+			continue
+		}
+		if p.Hashes == nil {
+			p.Hashes = make(map[string]string)
 		}
+		p.Hashes[attribution.NormalizeHashAlgorithm(hash.Algorithm)] = hash.Content
+	}
 
-		// Construct URL: prefer external references, fall back to purl conversion
-		if refURL := findBestExternalRefURL(component.ExternalReferences); refURL != nil {
-			p.URL = refURL
-		} else if p.Purl != "" {
-			// URL generation is best-effort - ignore expected errors (empty purl, unsupported types)
-			url, err := attribution.PurlToURL(p.Purl, nil)
-			if err == nil {
-				p.URL = url
-			}
+	// Reflect licensing claims from the BOM attestations
+	if component.BOMRef != "" {
+		p.Notes = slices.Clone(claims[component.BOMRef])
+	}
+
+	// Restore fields recorded by a previous sbomattr run
+	p = applyProperties(p, component.Properties)
+
+	return p
+}
+
+// propertyPrefix namespaces the component properties written by format.CycloneDX.
+const propertyPrefix = "sbomattr:"
+
+// applyProperties returns a copy of the attribution with the fields recorded as component properties by a previous
+// sbomattr run restored: the normalized license, the resolved URL, the attribution texts, the notes, and the
+// provenance of each field.
+// This way enrichment survives a round-trip through CycloneDX.
+func applyProperties(p attribution.Attribution, properties []Property) attribution.Attribution {
+	for _, property := range properties {
+		name, ok := strings.CutPrefix(property.Name, propertyPrefix)
+		if !ok {
+			continue
 		}
 
-		// Extract license information
-		if component.Licenses != nil {
-			license := extractLicense(component.Licenses)
-			if license != nil {
-				p.License = license
+		value := property.Value
+		switch name {
+		case "license":
+			p.License = &value
+		case "url":
+			p.URL = &value
+		case "attributionText":
+			if !slices.Contains(p.AttributionTexts, value) {
+				p.AttributionTexts = append(p.AttributionTexts, value)
+			}
+		case "note":
+			if !slices.Contains(p.Notes, value) {
+				p.Notes = append(p.Notes, value)
+			}
+		default:
+			if field, isProvenance := strings.CutPrefix(name, "provenance:"); isProvenance {
+				p = p.WithProvenance(field, attribution.Provenance(value))
 			}
 		}
+	}
+	return p
+}
 
-		packages = append(packages, p)
+// licensingClaims returns the licensing claims of CDXA declarations as attribution notes, keyed by the bom-ref of
+// the claim target. A claim is about licensing if its predicate mentions a license (or licence).
+// Returns nil if there are no declarations.
+func licensingClaims(declarations *Declarations) map[string][]string {
+	if declarations == nil {
+		return nil
 	}
 
-	return packages
+	claims := make(map[string][]string)
+	for _, claim := range declarations.Claims {
+		predicate := strings.TrimSpace(claim.Predicate)
+		if claim.Target == "" || !strings.Contains(strings.ToLower(predicate), "licen") {
+			continue
+		}
+		claims[claim.Target] = append(claims[claim.Target], "CDXA claim: "+predicate)
+	}
+	return claims
 }
 
 // extractLicense extracts license information from CycloneDX Licenses structure.
-// It prefers license expressions, then license IDs, then license names.
+// Every license choice is kept, preferring its expression, then its license ID, then its license name; several
+// choices are joined into one expression with AND (see attribution.JoinLicenses), so secondary licenses of
+// multi-licensed components are not dropped.
 func extractLicense(licenses *Licenses) *string {
-	if licenses == nil || len(*licenses) == 0 {
+	if licenses == nil {
 		return nil
 	}
 
-	firstLicense := (*licenses)[0]
-
-	// Prefer expression (e.g., "MIT OR Apache-2.0")
-	if firstLicense.License != nil {
-		if firstLicense.License.Expression != "" {
-			return &firstLicense.License.Expression
+	var expressions []string
+	for _, choice := range *licenses {
+		if choice.License == nil {
+			continue
 		}
+		if expression := cmp.Or(choice.License.Expression, choice.License.ID, choice.License.Name); expression != "" {
+			expressions = append(expressions, expression)
+		}
+	}
+
+	if len(expressions) == 0 {
+		return nil
+	}
+	license := attribution.JoinLicenses(expressions)
+	return &license
+}
 
-		// Fall back to License ID or Name
-		if firstLicense.License.ID != "" {
-			return &firstLicense.License.ID
+// Subject returns the name of the component the CycloneDX BOM describes (metadata.component).
+// Returns an empty string if the BOM does not record one.
+func Subject(bom *BOM) string {
+	if bom == nil || bom.Metadata == nil || bom.Metadata.Component == nil {
+		return ""
+	}
+	return bom.Metadata.Component.Name
+}
+
+// findLicenseURL returns the link to the license text of a component: its license external reference, or else the
+// url of one of its licenses, normalized (see attribution.NormalizeURL). Returns ok as false if there is none.
+func findLicenseURL(component *Component) (string, bool) {
+	if licenseURL, ok := findExternalRef(component.ExternalReferences, "license"); ok {
+		return licenseURL, true
+	}
+	if component.Licenses == nil {
+		return "", false
+	}
+	for _, choice := range *component.Licenses {
+		if choice.License == nil {
+			continue
 		}
-		if firstLicense.License.Name != "" {
-			return &firstLicense.License.Name
+		if licenseURL, ok := attribution.NormalizeURL(choice.License.URL); ok {
+			return licenseURL, true
 		}
 	}
+	return "", false
+}
 
-	return nil
+// findLicenseText returns the first license text of a component, decoded if it is base64-encoded. Returns ok as false
+// if there is none.
+func findLicenseText(licenses *Licenses) (string, bool) {
+	if licenses == nil {
+		return "", false
+	}
+	for _, choice := range *licenses {
+		if choice.License == nil || choice.License.Text == nil || strings.TrimSpace(choice.License.Text.Content) == "" {
+			continue
+		}
+		text := choice.License.Text
+		if !strings.EqualFold(text.Encoding, "base64") {
+			return text.Content, true
+		}
+		if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(text.Content)); err == nil {
+			return string(decoded), true
+		}
+	}
+	return "", false
 }
 
-// findBestExternalRefURL finds the best URL from external references.
-// Priority order: website > distribution > documentation > vcs.
-func findBestExternalRefURL(refs []ExternalReference) *string {
-	if len(refs) == 0 {
-		return nil
+// findExternalRef returns the URL of the first external reference of the given type that is a usable web URL,
+// normalized (see attribution.NormalizeURL). Returns ok as false if there is none.
+func findExternalRef(refs []ExternalReference, refType string) (string, bool) {
+	for _, ref := range refs {
+		if ref.Type != refType {
+			continue
+		}
+		if refURL, ok := attribution.NormalizeURL(ref.URL); ok {
+			return refURL, true
+		}
 	}
+	return "", false
+}
 
+// externalRefURLs returns the URLs of external references with their kind, normalized (see attribution.NormalizeURL).
+// They are ordered by reference type: website > distribution > documentation > vcs, so the first is the best primary
+// URL. References whose URL is not a usable web URL are skipped.
+func externalRefURLs(refs []ExternalReference) []attribution.TypedURL {
 	// Priority order for reference types
-	priorityOrder := []string{"website", "distribution", "documentation", "vcs"}
+	priorityOrder := []struct {
+		refType string
+		kind    attribution.URLKind
+	}{
+		{refType: "website", kind: attribution.URLKindHomepage},
+		{refType: "distribution", kind: attribution.URLKindDownload},
+		{refType: "documentation", kind: attribution.URLKindDocumentation},
+		{refType: "vcs", kind: attribution.URLKindSource},
+	}
 
-	for _, refType := range priorityOrder {
+	var urls []attribution.TypedURL
+	for _, priority := range priorityOrder {
 		for _, ref := range refs {
-			if ref.Type == refType && ref.URL != "" {
-				return &ref.URL
+			if ref.Type != priority.refType {
+				continue
+			}
+			if refURL, ok := attribution.NormalizeURL(ref.URL); ok {
+				urls = append(urls, attribution.TypedURL{Kind: priority.kind, URL: refURL})
 			}
 		}
 	}
-
-	return nil
+	return urls
 }
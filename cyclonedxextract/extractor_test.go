@@ -1,8 +1,11 @@
 package cyclonedxextract_test
 
 import (
+	"maps"
+	"slices"
 	"testing"
 
+	"github.com/boringbin/sbomattr/attribution"
 	"github.com/boringbin/sbomattr/cyclonedxextract"
 )
 
@@ -100,6 +103,10 @@ func TestExtractPackages_ComponentWithAllFields(t *testing.T) {
 		t.Errorf("Expected name 'lodash', got %q", attr.Name)
 	}
 
+	if attr.Version != "4.17.21" {
+		t.Errorf("Expected version '4.17.21', got %q", attr.Version)
+	}
+
 	if attr.Purl != "pkg:npm/lodash@4.17.21" {
 		t.Errorf("Expected purl 'pkg:npm/lodash@4.17.21', got %q", attr.Purl)
 	}
@@ -545,6 +552,40 @@ func TestExtractPackages_WithMultipleExternalRefs(t *testing.T) {
 	}
 }
 
+// TestExtractPackages_URLs tests that every external reference URL is kept with its kind, in priority order, along
+// with the URL generated from the purl.
+func TestExtractPackages_URLs(t *testing.T) {
+	t.Parallel()
+
+	bom := &cyclonedxextract.BOM{
+		Components: []cyclonedxextract.Component{
+			{
+				Name: "flask",
+				Purl: "pkg:pypi/flask@2.3.0",
+				ExternalReferences: []cyclonedxextract.ExternalReference{
+					{Type: "vcs", URL: "git+https://github.com/pallets/flask.git"},
+					{Type: "documentation", URL: "https://flask.palletsprojects.com/"},
+					{Type: "website", URL: "https://palletsprojects.com/p/flask/"},
+					{Type: "distribution", URL: "https://palletsprojects.com/p/flask/"},
+					{Type: "issue-tracker", URL: "https://github.com/pallets/flask/issues"},
+				},
+			},
+		},
+	}
+
+	got := cyclonedxextract.ExtractPackages(bom)[0].URLs
+
+	want := []attribution.TypedURL{
+		{Kind: attribution.URLKindHomepage, URL: "https://palletsprojects.com/p/flask/"},
+		{Kind: attribution.URLKindDocumentation, URL: "https://flask.palletsprojects.com/"},
+		{Kind: attribution.URLKindSource, URL: "https://github.com/pallets/flask"},
+		{Kind: attribution.URLKindRegistry, URL: "https://pypi.org/project/flask/2.3.0/"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected URLs %v, got %v", want, got)
+	}
+}
+
 // TestExtractPackages_WithExternalRefVCS tests that "vcs" external ref is used when website is not available.
 func TestExtractPackages_WithExternalRefVCS(t *testing.T) {
 	t.Parallel()
@@ -582,6 +623,167 @@ func TestExtractPackages_WithExternalRefVCS(t *testing.T) {
 	if *attr.URL != "https://github.com/numpy/numpy" {
 		t.Errorf("Expected URL to be vcs ref 'https://github.com/numpy/numpy', got %q", *attr.URL)
 	}
+	if attr.SourceRepo != "https://github.com/numpy/numpy" {
+		t.Errorf("Expected source repository 'https://github.com/numpy/numpy', got %q", attr.SourceRepo)
+	}
+}
+
+// TestExtractPackages_SourceRepo tests that the vcs reference is extracted as the source repository, separately from
+// the website.
+func TestExtractPackages_SourceRepo(t *testing.T) {
+	t.Parallel()
+
+	bom := &cyclonedxextract.BOM{Components: []cyclonedxextract.Component{
+		{
+			Name: "lodash",
+			ExternalReferences: []cyclonedxextract.ExternalReference{
+				{Type: "website", URL: "https://lodash.com"},
+				{Type: "vcs", URL: "git+https://github.com/lodash/lodash.git"},
+			},
+		},
+		{Name: "website-only", ExternalReferences: []cyclonedxextract.ExternalReference{
+			{Type: "website", URL: "https://example.com"},
+		}},
+	}}
+
+	result := cyclonedxextract.ExtractPackages(bom)
+
+	if result[0].SourceRepo != "https://github.com/lodash/lodash" {
+		t.Errorf("Expected source repository 'https://github.com/lodash/lodash', got %q", result[0].SourceRepo)
+	}
+	if result[0].URL == nil || *result[0].URL != "https://lodash.com" {
+		t.Errorf("Expected the website to stay the URL, got %v", result[0].URL)
+	}
+	if result[1].SourceRepo != "" {
+		t.Errorf("Expected no source repository, got %q", result[1].SourceRepo)
+	}
+}
+
+// TestExtractPackages_AuthorPublisher tests that the author is extracted, and the publisher is the supplier of
+// components without one.
+func TestExtractPackages_AuthorPublisher(t *testing.T) {
+	t.Parallel()
+
+	bom := &cyclonedxextract.BOM{Components: []cyclonedxextract.Component{
+		{Name: "lodash", Author: " John-David Dalton ", Publisher: "OpenJS Foundation"},
+		{
+			Name:      "guava",
+			Author:    "Google",
+			Publisher: "Maven Central",
+			Supplier:  &cyclonedxextract.Supplier{Name: "Google LLC"},
+		},
+		{Name: "anonymous"},
+	}}
+
+	result := cyclonedxextract.ExtractPackages(bom)
+
+	want := []struct{ author, supplier string }{
+		{author: "John-David Dalton", supplier: "OpenJS Foundation"},
+		{author: "Google", supplier: "Google LLC"},
+		{},
+	}
+	for i := range want {
+		if result[i].Author != want[i].author || result[i].Supplier != want[i].supplier {
+			t.Errorf("Expected author/supplier %q/%q for %s, got %q/%q", want[i].author, want[i].supplier,
+				result[i].Name, result[i].Author, result[i].Supplier)
+		}
+	}
+}
+
+// TestExtractPackages_LicenseURL tests that the license reference is extracted as the license URL, falling back to
+// the url of a license.
+func TestExtractPackages_LicenseURL(t *testing.T) {
+	t.Parallel()
+
+	bom := &cyclonedxextract.BOM{Components: []cyclonedxextract.Component{
+		{
+			Name: "lodash",
+			ExternalReferences: []cyclonedxextract.ExternalReference{
+				{Type: "license", URL: "https://github.com/lodash/lodash/blob/main/LICENSE"},
+			},
+			Licenses: &cyclonedxextract.Licenses{
+				{License: &cyclonedxextract.License{ID: "MIT", URL: "https://opensource.org/licenses/MIT"}},
+			},
+		},
+		{Name: "acme", Licenses: &cyclonedxextract.Licenses{
+			{License: &cyclonedxextract.License{Name: "Acme EULA", URL: "https://acme.example/eula"}},
+		}},
+		{Name: "none", Licenses: &cyclonedxextract.Licenses{
+			{License: &cyclonedxextract.License{ID: "MIT", URL: "NOASSERTION"}},
+		}},
+	}}
+
+	result := cyclonedxextract.ExtractPackages(bom)
+
+	want := []string{"https://github.com/lodash/lodash/blob/main/LICENSE", "https://acme.example/eula", ""}
+	for i := range want {
+		if result[i].LicenseURL != want[i] {
+			t.Errorf("Expected license URL %q for %s, got %q", want[i], result[i].Name, result[i].LicenseURL)
+		}
+	}
+}
+
+// TestExtractPackages_LicenseText tests that the first license text of a component is extracted, decoding base64.
+func TestExtractPackages_LicenseText(t *testing.T) {
+	t.Parallel()
+
+	bom := &cyclonedxextract.BOM{Components: []cyclonedxextract.Component{
+		{Name: "plain", Licenses: &cyclonedxextract.Licenses{
+			{License: &cyclonedxextract.License{ID: "MIT"}},
+			{License: &cyclonedxextract.License{
+				Name: "Acme EULA",
+				Text: &cyclonedxextract.LicenseText{Content: "Acme may be used freely."},
+			}},
+		}},
+		{Name: "encoded", Licenses: &cyclonedxextract.Licenses{
+			{License: &cyclonedxextract.License{
+				ID:   "MIT",
+				Text: &cyclonedxextract.LicenseText{Content: "TUlUIExpY2Vuc2U=", Encoding: "base64"},
+			}},
+		}},
+		{Name: "none", Licenses: &cyclonedxextract.Licenses{
+			{License: &cyclonedxextract.License{ID: "MIT"}},
+		}},
+	}}
+
+	result := cyclonedxextract.ExtractPackages(bom)
+
+	want := []string{"Acme may be used freely.", "MIT License", ""}
+	for i := range want {
+		if result[i].LicenseText != want[i] {
+			t.Errorf("Expected license text %q for %s, got %q", want[i], result[i].Name, result[i].LicenseText)
+		}
+	}
+	if got := result[1].Provenance[attribution.FieldLicenseText]; got != attribution.ProvenanceExtracted {
+		t.Errorf("Expected license text provenance %q, got %q", attribution.ProvenanceExtracted, got)
+	}
+}
+
+// TestExtractPackages_WithExternalRefNormalized tests that external reference URLs are normalized, and unusable ones
+// are skipped.
+func TestExtractPackages_WithExternalRefNormalized(t *testing.T) {
+	t.Parallel()
+
+	bom := &cyclonedxextract.BOM{
+		Components: []cyclonedxextract.Component{
+			{
+				Name: "numpy",
+				ExternalReferences: []cyclonedxextract.ExternalReference{
+					{Type: "website", URL: "mailto:numpy-discussion@python.org"},
+					{Type: "vcs", URL: "git+https://github.com/numpy/numpy.git"},
+				},
+			},
+		},
+	}
+
+	result := cyclonedxextract.ExtractPackages(bom)
+
+	if len(result) != 1 || result[0].URL == nil {
+		t.Fatalf("Expected 1 attribution with a URL, got %+v", result)
+	}
+	if *result[0].URL != "https://github.com/numpy/numpy" {
+		t.Errorf("Expected normalized vcs URL 'https://github.com/numpy/numpy', got %q", *result[0].URL)
+	}
 }
 
 // TestExtractPackages_WithExternalRefDistribution tests that "distribution" ref is preferred over "documentation".
@@ -743,3 +945,351 @@ func TestExtractPackages_WithoutExternalRefs(t *testing.T) {
 		t.Errorf("Expected URL to be purl-generated %q, got %q", expectedURL, *attr.URL)
 	}
 }
+
+// TestExtractPackages_Provenance tests that the provenance of extracted and generated fields is recorded.
+func TestExtractPackages_Provenance(t *testing.T) {
+	t.Parallel()
+
+	bom := &cyclonedxextract.BOM{
+		Components: []cyclonedxextract.Component{
+			{
+				Name:     "with-website",
+				Licenses: &cyclonedxextract.Licenses{{License: &cyclonedxextract.License{ID: "MIT"}}},
+				ExternalReferences: []cyclonedxextract.ExternalReference{
+					{Type: "website", URL: "https://example.com"},
+				},
+			},
+			{
+				Name: "with-purl",
+				Purl: "pkg:npm/lodash@4.17.21",
+			},
+		},
+	}
+
+	result := cyclonedxextract.ExtractPackages(bom)
+
+	if got := result[0].Provenance[attribution.FieldLicense]; got != attribution.ProvenanceExtracted {
+		t.Errorf("Expected extracted license provenance, got %q", got)
+	}
+	if got := result[0].Provenance[attribution.FieldURL]; got != attribution.ProvenanceExtracted {
+		t.Errorf("Expected extracted URL provenance, got %q", got)
+	}
+	if got := result[1].Provenance[attribution.FieldURL]; got != attribution.ProvenanceGenerated {
+		t.Errorf("Expected generated URL provenance, got %q", got)
+	}
+}
+
+// TestSubject tests reading the described component from the BOM metadata.
+func TestSubject(t *testing.T) {
+	t.Parallel()
+
+	if got := cyclonedxextract.Subject(nil); got != "" {
+		t.Errorf("Subject(nil) = %q, want empty", got)
+	}
+	if got := cyclonedxextract.Subject(&cyclonedxextract.BOM{Metadata: &cyclonedxextract.Metadata{}}); got != "" {
+		t.Errorf("Subject() without component = %q, want empty", got)
+	}
+
+	bom := &cyclonedxextract.BOM{
+		Metadata: &cyclonedxextract.Metadata{Component: &cyclonedxextract.Component{Name: "my-app"}},
+	}
+	if got := cyclonedxextract.Subject(bom); got != "my-app" {
+		t.Errorf("Subject() = %q, want %q", got, "my-app")
+	}
+}
+
+// TestRoot tests the Root function.
+func TestRoot(t *testing.T) {
+	t.Parallel()
+
+	if got := cyclonedxextract.Root(nil); got != nil {
+		t.Errorf("Root(nil) = %+v, want nil", got)
+	}
+	if got := cyclonedxextract.Root(&cyclonedxextract.BOM{Metadata: &cyclonedxextract.Metadata{}}); got != nil {
+		t.Errorf("Root() without component = %+v, want nil", got)
+	}
+
+	bom := &cyclonedxextract.BOM{
+		Metadata: &cyclonedxextract.Metadata{Component: &cyclonedxextract.Component{
+			Name:     "my-app",
+			Version:  "1.0.0",
+			Purl:     "pkg:npm/my-app@1.0.0",
+			Licenses: &cyclonedxextract.Licenses{{License: &cyclonedxextract.License{ID: "Apache-2.0"}}},
+		}},
+		Components: []cyclonedxextract.Component{{Name: "lodash", Purl: "pkg:npm/lodash@4.17.21"}},
+	}
+	root := cyclonedxextract.Root(bom)
+	if root == nil || root.Name != "my-app" || root.License == nil || *root.License != "Apache-2.0" {
+		t.Fatalf("Root() = %+v, want my-app under Apache-2.0", root)
+	}
+	if root.URL == nil || *root.URL != "https://www.npmjs.com/package/my-app/v/1.0.0" {
+		t.Errorf("Root().URL = %v, want the npm URL generated from the purl", root.URL)
+	}
+	if got := cyclonedxextract.ExtractPackages(bom); len(got) != 1 || got[0].Name != "lodash" {
+		t.Errorf("ExtractPackages() = %+v, want only lodash", got)
+	}
+}
+
+// TestExtractPackages_Properties tests that sbomattr properties override extracted fields and others are ignored.
+func TestExtractPackages_Properties(t *testing.T) {
+	t.Parallel()
+
+	bom := &cyclonedxextract.BOM{
+		Components: []cyclonedxextract.Component{
+			{
+				Name:     "lodash",
+				Purl:     "pkg:npm/lodash@4.17.21",
+				Licenses: &cyclonedxextract.Licenses{{License: &cyclonedxextract.License{Name: "MIT License"}}},
+				Properties: []cyclonedxextract.Property{
+					{Name: "sbomattr:license", Value: "MIT"},
+					{Name: "sbomattr:url", Value: "https://lodash.com"},
+					{Name: "sbomattr:provenance:license", Value: "enriched-from-npm"},
+					{Name: "syft:package:type", Value: "npm"},
+				},
+			},
+		},
+	}
+
+	got := cyclonedxextract.ExtractPackages(bom)[0]
+
+	if got.License == nil || *got.License != "MIT" {
+		t.Errorf("Expected license MIT from properties, got %v", got.License)
+	}
+	if got.URL == nil || *got.URL != "https://lodash.com" {
+		t.Errorf("Expected URL from properties, got %v", got.URL)
+	}
+	if p := got.Provenance[attribution.FieldLicense]; p != attribution.ProvenanceEnriched("npm") {
+		t.Errorf("Expected license provenance enriched-from-npm, got %q", p)
+	}
+	if p := got.Provenance[attribution.FieldURL]; p != attribution.ProvenanceGenerated {
+		t.Errorf("Expected URL provenance to stay generated, got %q", p)
+	}
+	if len(got.Provenance) != 2 {
+		t.Errorf("Expected only license and URL provenance, got %v", got.Provenance)
+	}
+}
+
+// TestExtractPackages_Description tests that the component description is extracted.
+func TestExtractPackages_Description(t *testing.T) {
+	t.Parallel()
+
+	bom := &cyclonedxextract.BOM{
+		Components: []cyclonedxextract.Component{{Name: "lodash", Description: "Lodash modular utilities."}},
+	}
+
+	result := cyclonedxextract.ExtractPackages(bom)
+
+	if result[0].Description != "Lodash modular utilities." {
+		t.Errorf("Expected description to be extracted, got %q", result[0].Description)
+	}
+}
+
+// TestExtractPackages_CPE tests that the component CPE is extracted.
+func TestExtractPackages_CPE(t *testing.T) {
+	t.Parallel()
+
+	cpe := "cpe:2.3:a:openssl:openssl:3.0.0:*:*:*:*:*:*:*"
+	bom := &cyclonedxextract.BOM{Components: []cyclonedxextract.Component{{Name: "openssl", CPE: cpe}}}
+
+	if got := cyclonedxextract.ExtractPackages(bom)[0].CPE; got != cpe {
+		t.Errorf("Expected CPE %q, got %q", cpe, got)
+	}
+}
+
+// TestExtractPackages_CPEURL tests that components without an external reference or supported purl get a URL
+// generated from their CPE.
+func TestExtractPackages_CPEURL(t *testing.T) {
+	t.Parallel()
+
+	cpe := "cpe:2.3:a:gnu:bash:5.2:*:*:*:*:*:*:*"
+	bom := &cyclonedxextract.BOM{Components: []cyclonedxextract.Component{
+		{Name: "bash", CPE: cpe, Purl: "pkg:generic/bash@5.2"},
+		{Name: "npm", CPE: cpe, Purl: "pkg:npm/bash@5.2"},
+	}}
+
+	result := cyclonedxextract.ExtractPackages(bom)
+
+	want := "https://nvd.nist.gov/products/cpe/search/results?namingFormat=2.3&keyword=cpe%3A2.3%3Aa%3Agnu%3Abash"
+	if result[0].URL == nil || *result[0].URL != want {
+		t.Errorf("Expected CPE URL %q, got %v", want, result[0].URL)
+	}
+	if result[1].URL == nil || *result[1].URL != "https://www.npmjs.com/package/bash/v/5.2" {
+		t.Errorf("Expected the purl URL to win over the CPE, got %v", result[1].URL)
+	}
+}
+
+// TestExtractPackages_Hashes tests that hashes are extracted with normalized algorithm names.
+func TestExtractPackages_Hashes(t *testing.T) {
+	t.Parallel()
+
+	bom := &cyclonedxextract.BOM{
+		Components: []cyclonedxextract.Component{
+			{
+				Name: "lodash",
+				Hashes: []cyclonedxextract.Hash{
+					{Algorithm: "SHA-256", Content: "abc123"},
+					{Algorithm: "SHA3-512", Content: "def456"},
+				},
+			},
+		},
+	}
+
+	got := cyclonedxextract.ExtractPackages(bom)[0].Hashes
+	if want := map[string]string{"SHA256": "abc123", "SHA3-512": "def456"}; !maps.Equal(got, want) {
+		t.Errorf("Expected hashes %v, got %v", want, got)
+	}
+}
+
+// TestExtractPackages_AttestationClaims tests that licensing claims from CDXA declarations become notes.
+func TestExtractPackages_AttestationClaims(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{
+		"bomFormat": "CycloneDX",
+		"specVersion": "1.6",
+		"components": [
+			{"bom-ref": "lodash", "name": "lodash"},
+			{"bom-ref": "express", "name": "express"},
+			{"name": "untargeted"}
+		],
+		"declarations": {
+			"claims": [
+				{"bom-ref": "claim-1", "target": "lodash", "predicate": "Distributed under the MIT License."},
+				{"bom-ref": "claim-2", "target": "lodash", "predicate": "Passed the security review."},
+				{"bom-ref": "claim-3", "target": "lodash", "predicate": "Licence texts reviewed by legal."},
+				{"bom-ref": "claim-4", "predicate": "All licenses approved."}
+			]
+		}
+	}`)
+
+	bom, err := cyclonedxextract.ParseSBOM(data)
+	if err != nil {
+		t.Fatalf("ParseSBOM() error = %v", err)
+	}
+	result := cyclonedxextract.ExtractPackages(bom)
+
+	want := []string{"CDXA claim: Distributed under the MIT License.", "CDXA claim: Licence texts reviewed by legal."}
+	if !slices.Equal(result[0].Notes, want) {
+		t.Errorf("Expected notes %v, got %v", want, result[0].Notes)
+	}
+	for _, a := range result[1:] {
+		if a.Notes != nil {
+			t.Errorf("Expected no notes for %s, got %v", a.Name, a.Notes)
+		}
+	}
+}
+
+// TestExtractPackages_Group tests that the component group is extracted, so equally named components from different
+// groups stay apart.
+func TestExtractPackages_Group(t *testing.T) {
+	t.Parallel()
+
+	bom := &cyclonedxextract.BOM{
+		Components: []cyclonedxextract.Component{
+			{Group: "org.example.a", Name: "core"},
+			{Group: "org.example.b", Name: "core"},
+		},
+	}
+
+	result := attribution.Deduplicate(cyclonedxextract.ExtractPackages(bom), nil)
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 attributions after deduplication, got %d", len(result))
+	}
+	if result[0].Group != "org.example.a" || result[0].QualifiedName() != "org.example.a/core" {
+		t.Errorf("Expected group org.example.a, got %q (%q)", result[0].Group, result[0].QualifiedName())
+	}
+}
+
+// TestExtractPackages_Supplier tests that the supplier name is extracted.
+func TestExtractPackages_Supplier(t *testing.T) {
+	t.Parallel()
+
+	bom := &cyclonedxextract.BOM{
+		Components: []cyclonedxextract.Component{
+			{Name: "ui", Supplier: &cyclonedxextract.Supplier{Name: "Acme Corp"}},
+			{Name: "lodash"},
+		},
+	}
+
+	result := cyclonedxextract.ExtractPackages(bom)
+
+	if result[0].Supplier != "Acme Corp" || result[1].Supplier != "" {
+		t.Errorf("Expected suppliers [Acme Corp, \"\"], got [%q, %q]", result[0].Supplier, result[1].Supplier)
+	}
+}
+
+// TestExtractPackages_TypeAndScope tests that the component type and scope are extracted.
+func TestExtractPackages_TypeAndScope(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{"bomFormat": "CycloneDX", "specVersion": "1.5", "components": [
+		{"type": "library", "scope": "excluded", "name": "eslint"},
+		{"type": "operating-system", "name": "alpine"}
+	]}`)
+
+	bom, err := cyclonedxextract.ParseSBOM(data)
+	if err != nil {
+		t.Fatalf("ParseSBOM() error = %v", err)
+	}
+	result := cyclonedxextract.ExtractPackages(bom)
+
+	if result[0].Type != "library" || result[0].Scope != "excluded" {
+		t.Errorf("Expected library/excluded, got %q/%q", result[0].Type, result[0].Scope)
+	}
+	if result[1].Type != "operating-system" || result[1].Scope != "" {
+		t.Errorf("Expected operating-system without scope, got %q/%q", result[1].Type, result[1].Scope)
+	}
+}
+
+// TestExtractPackages_MultipleLicenses tests that every license choice is kept, joined into one expression.
+func TestExtractPackages_MultipleLicenses(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		licenses cyclonedxextract.Licenses
+		want     string
+	}{
+		{
+			name: "ids",
+			licenses: cyclonedxextract.Licenses{
+				{License: &cyclonedxextract.License{ID: "MIT"}},
+				{License: &cyclonedxextract.License{ID: "Apache-2.0"}},
+			},
+			want: "MIT AND Apache-2.0",
+		},
+		{
+			name: "expression, id, and name",
+			licenses: cyclonedxextract.Licenses{
+				{License: &cyclonedxextract.License{Expression: "MIT OR Apache-2.0"}},
+				{License: &cyclonedxextract.License{ID: "BSD-3-Clause"}},
+				{},
+				{License: &cyclonedxextract.License{Name: "Zlib"}},
+			},
+			want: "(MIT OR Apache-2.0) AND BSD-3-Clause AND Zlib",
+		},
+		{
+			name: "repeated",
+			licenses: cyclonedxextract.Licenses{
+				{License: &cyclonedxextract.License{ID: "MIT"}},
+				{License: &cyclonedxextract.License{ID: "MIT"}},
+			},
+			want: "MIT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			bom := &cyclonedxextract.BOM{
+				Components: []cyclonedxextract.Component{{Name: "pkg", Licenses: &tt.licenses}},
+			}
+			got := cyclonedxextract.ExtractPackages(bom)[0].License
+			if got == nil || *got != tt.want {
+				t.Errorf("Expected license %q, got %v", tt.want, got)
+			}
+		})
+	}
+}
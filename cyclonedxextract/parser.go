@@ -1,6 +1,7 @@
 package cyclonedxextract
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 )
@@ -16,3 +17,47 @@ func ParseSBOM(data []byte) (*BOM, error) {
 
 	return &bom, nil
 }
+
+// UnmarshalJSON decodes the licenses of a component, which generators encode as an array of license choices (as the
+// specification requires), a single license choice, or a plain string holding a license ID or expression.
+func (l *Licenses) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	switch {
+	case bytes.HasPrefix(data, []byte("[")):
+		var choices []LicenseChoice
+		if err := json.Unmarshal(data, &choices); err != nil {
+			return err
+		}
+		*l = choices
+	case bytes.HasPrefix(data, []byte("{")):
+		var choice LicenseChoice
+		if err := json.Unmarshal(data, &choice); err != nil {
+			return err
+		}
+		*l = Licenses{choice}
+	default:
+		var expression string
+		if err := json.Unmarshal(data, &expression); err != nil {
+			return fmt.Errorf("licenses: %w", err)
+		}
+		*l = Licenses{{License: &License{Expression: expression}}}
+	}
+
+	return nil
+}
+
+// UnmarshalJSON decodes license text, accepting a plain string as well as the {"content": ...} object.
+func (t *LicenseText) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		return json.Unmarshal(data, &t.Content)
+	}
+
+	type plainLicenseText LicenseText
+	var plain plainLicenseText
+	if err := json.Unmarshal(data, &plain); err != nil {
+		return err
+	}
+	*t = LicenseText(plain)
+	return nil
+}
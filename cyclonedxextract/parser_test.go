@@ -193,3 +193,56 @@ func TestParseSBOM_NullBytes(t *testing.T) {
 		t.Errorf("Expected nil BOM for empty bytes, got %+v", bom)
 	}
 }
+
+// TestParseSBOM_LicenseShapes tests that licenses encoded as a string or a single object are accepted.
+func TestParseSBOM_LicenseShapes(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{
+		"bomFormat": "CycloneDX",
+		"specVersion": "1.4",
+		"components": [
+			{"name": "array", "licenses": [{"license": {"id": "MIT"}}]},
+			{"name": "object", "licenses": {"license": {"id": "ISC", "text": "ISC license text"}}},
+			{"name": "string", "licenses": "Apache-2.0 OR MIT"},
+			{"name": "text-object", "licenses": [{"license": {"name": "Custom", "text": {"content": "custom text"}}}]}
+		]
+	}`)
+
+	bom, err := cyclonedxextract.ParseSBOM(data)
+	if err != nil {
+		t.Fatalf("ParseSBOM failed: %v", err)
+	}
+
+	license := func(i int) *cyclonedxextract.License {
+		t.Helper()
+		licenses := bom.Components[i].Licenses
+		if licenses == nil || len(*licenses) != 1 || (*licenses)[0].License == nil {
+			t.Fatalf("Expected one license for component %d, got %v", i, licenses)
+		}
+		return (*licenses)[0].License
+	}
+
+	if got := license(0).ID; got != "MIT" {
+		t.Errorf("Expected array license ID MIT, got %q", got)
+	}
+	if got := license(1); got.ID != "ISC" || got.Text == nil || got.Text.Content != "ISC license text" {
+		t.Errorf("Expected object license ISC with string text, got %+v", got)
+	}
+	if got := license(2).Expression; got != "Apache-2.0 OR MIT" {
+		t.Errorf("Expected string license expression, got %q", got)
+	}
+	if got := license(3).Text; got == nil || got.Content != "custom text" {
+		t.Errorf("Expected text object content, got %+v", got)
+	}
+}
+
+// TestParseSBOM_InvalidLicenses tests that licenses of an unsupported type are rejected.
+func TestParseSBOM_InvalidLicenses(t *testing.T) {
+	t.Parallel()
+
+	_, err := cyclonedxextract.ParseSBOM([]byte(`{"bomFormat": "CycloneDX", "components": [{"licenses": 42}]}`))
+	if err == nil {
+		t.Error("Expected error for numeric licenses, got nil")
+	}
+}
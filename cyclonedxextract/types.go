@@ -6,16 +6,72 @@ package cyclonedxextract
 type BOM struct {
 	BOMFormat   string      `json:"bomFormat"`
 	SpecVersion string      `json:"specVersion"`
+	Metadata    *Metadata   `json:"metadata"`
 	Components  []Component `json:"components"`
+	// Dependencies holds the dependency graph, keyed by component bom-ref
+	Dependencies []Dependency `json:"dependencies"`
+	// Declarations holds the CycloneDX 1.6 attestations (CDXA), if the BOM carries any
+	Declarations *Declarations `json:"declarations"`
+}
+
+// Declarations represents the CycloneDX attestations (CDXA) declarations, with only the claims we need.
+type Declarations struct {
+	Claims []Claim `json:"claims"`
+}
+
+// Claim represents a CDXA claim: a statement (predicate) made about a target, such as a component.
+type Claim struct {
+	BOMRef    string `json:"bom-ref"`
+	Target    string `json:"target"`
+	Predicate string `json:"predicate"`
+}
+
+// Dependency represents the direct dependencies of a component in the dependency graph.
+type Dependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// Metadata represents the BOM metadata, including the component the BOM describes.
+type Metadata struct {
+	Component *Component `json:"component"`
 }
 
 // Component represents a minimal CycloneDX component with only the fields we need.
 type Component struct {
+	BOMRef             string              `json:"bom-ref"`
+	Type               string              `json:"type"`
+	Scope              string              `json:"scope"`
+	Group              string              `json:"group"`
 	Name               string              `json:"name"`
 	Version            string              `json:"version"`
+	Description        string              `json:"description"`
+	Supplier           *Supplier           `json:"supplier"`
+	Author             string              `json:"author"`
+	Publisher          string              `json:"publisher"`
 	Purl               string              `json:"purl"`
+	CPE                string              `json:"cpe"`
 	Licenses           *Licenses           `json:"licenses"`
 	ExternalReferences []ExternalReference `json:"externalReferences"`
+	Properties         []Property          `json:"properties"`
+	Hashes             []Hash              `json:"hashes"`
+}
+
+// Supplier represents the organization that supplied a component.
+type Supplier struct {
+	Name string `json:"name"`
+}
+
+// Hash represents a component hash.
+type Hash struct {
+	Algorithm string `json:"alg"`
+	Content   string `json:"content"`
+}
+
+// Property represents a component name-value property.
+type Property struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
 
 // ExternalReference represents an external reference with a URL and type.
@@ -38,9 +94,11 @@ type License struct {
 	Name       string       `json:"name"`
 	Expression string       `json:"expression"`
 	Text       *LicenseText `json:"text"`
+	URL        string       `json:"url"`
 }
 
 // LicenseText represents license text content.
 type LicenseText struct {
-	Content string `json:"content"`
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
 }
@@ -0,0 +1,42 @@
+package enrich
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/boringbin/sbomattr/internal/cache"
+)
+
+// Cache remembers registry lookups across runs, so unchanged packages are not looked up again. Its keys are
+// "<source> <purl>".
+// It is safe for concurrent use.
+type Cache = cache.Cache[CacheEntry]
+
+// CacheEntry is a cached lookup result.
+type CacheEntry struct {
+	// Metadata is what the registry returned, or empty if it does not know the package version.
+	Metadata
+
+	// Looked is when the package was looked up.
+	Looked time.Time `json:"looked"`
+}
+
+// NewCache returns an empty cache.
+func NewCache() *Cache {
+	return cache.New(looked)
+}
+
+// LoadCache reads a cache from a JSON file.
+// Returns an empty cache if the file does not exist or was written by an incompatible version of sbomattr.
+func LoadCache(path string) (*Cache, error) {
+	c, err := cache.Load(path, looked)
+	if err != nil {
+		return nil, fmt.Errorf("load enrichment cache: %w", err)
+	}
+	return c, nil
+}
+
+// looked returns when the package of an entry was looked up.
+func looked(e CacheEntry) time.Time {
+	return e.Looked
+}
@@ -0,0 +1,55 @@
+package enrich
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/package-url/packageurl-go"
+)
+
+// DefaultCratesURL is the base URL of the crates.io API.
+const DefaultCratesURL = "https://crates.io"
+
+// Crates looks up packages with the crates.io API.
+type Crates struct {
+	// BaseURL is the API base URL, without a trailing slash. Defaults to DefaultCratesURL.
+	BaseURL string
+	// Client is the HTTP client used for requests. Defaults to a client with a 30 second timeout.
+	Client *http.Client
+}
+
+// cratesVersion is the part of the API document of a crate version that is needed.
+type cratesVersion struct {
+	Version struct {
+		License string `json:"license"`
+	} `json:"version"`
+}
+
+// cratesCrate is the part of the API document of a crate that is needed.
+type cratesCrate struct {
+	Crate struct {
+		Homepage string `json:"homepage"`
+	} `json:"crate"`
+}
+
+// Name returns "crates.io".
+func (s *Crates) Name() string {
+	return "crates.io"
+}
+
+// Lookup returns the license of a crate version (an SPDX expression, as required by crates.io) and the homepage of
+// the crate, which is not versioned.
+func (s *Crates) Lookup(ctx context.Context, purl packageurl.PackageURL) (Metadata, error) {
+	endpoint := baseURL(s.BaseURL, DefaultCratesURL) + "/api/v1/crates/" + url.PathEscape(purl.Name)
+
+	var v cratesVersion
+	if err := getJSON(ctx, s.Client, endpoint+"/"+url.PathEscape(purl.Version), &v); err != nil {
+		return Metadata{}, err
+	}
+	var c cratesCrate
+	if err := getJSON(ctx, s.Client, endpoint, &c); err != nil {
+		return Metadata{}, err
+	}
+	return Metadata{License: v.Version.License, Homepage: c.Crate.Homepage}, nil
+}
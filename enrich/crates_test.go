@@ -0,0 +1,33 @@
+package enrich_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/boringbin/sbomattr/enrich"
+)
+
+// TestCrates_Lookup tests that the license of the version and the homepage of the crate are looked up.
+func TestCrates_Lookup(t *testing.T) {
+	t.Parallel()
+
+	server := newJSONServer(t, map[string]string{
+		"/api/v1/crates/serde/1.0.190": `{"version": {"license": "MIT OR Apache-2.0"}}`,
+		"/api/v1/crates/serde":         `{"crate": {"homepage": "https://serde.rs"}}`,
+	})
+	source := &enrich.Crates{BaseURL: server.URL}
+
+	got, err := source.Lookup(context.Background(), mustPurl(t, "pkg:cargo/serde@1.0.190"))
+	if err != nil {
+		t.Fatalf("Lookup() unexpected error: %v", err)
+	}
+	if want := (enrich.Metadata{License: "MIT OR Apache-2.0", Homepage: "https://serde.rs"}); got != want {
+		t.Errorf("Lookup() = %+v, want %+v", got, want)
+	}
+
+	_, err = source.Lookup(context.Background(), mustPurl(t, "pkg:cargo/serde@9.9.9"))
+	if !errors.Is(err, enrich.ErrNotFound) {
+		t.Errorf("Lookup() of an unknown version error = %v, want %v", err, enrich.ErrNotFound)
+	}
+}
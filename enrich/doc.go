@@ -0,0 +1,3 @@
+// Package enrich backfills missing attribution fields (license and homepage) from package registries, looking up the
+// exact package version named by the purl: the npm registry, the PyPI JSON API, crates.io, and RubyGems.
+package enrich
@@ -0,0 +1,235 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/package-url/packageurl-go"
+)
+
+// Metadata is what a registry knows about a package version.
+type Metadata struct {
+	// License is the license of the package version as published, ideally an SPDX expression.
+	License string `json:"license,omitempty"`
+	// Homepage is the project website.
+	Homepage string `json:"homepage,omitempty"`
+}
+
+// Source looks up package metadata in a registry.
+type Source interface {
+	// Name identifies the source in provenance, e.g. "npm" for "enriched-from-npm".
+	Name() string
+	// Lookup returns the metadata of the package version identified by a purl.
+	// Returns ErrNotFound if the registry does not know the package version.
+	Lookup(ctx context.Context, purl packageurl.PackageURL) (Metadata, error)
+}
+
+// LicenseOnlySource is a Source that only looks up licenses, such as one with a low rate limit. Packages that have a
+// license are not looked up in it, even if they miss a homepage.
+type LicenseOnlySource interface {
+	Source
+	// LicenseOnly marks the source as only looking up licenses.
+	LicenseOnly()
+}
+
+// DefaultSources returns the sources of the public registries, keyed by the purl type they look up.
+func DefaultSources() map[string]Source {
+	return NewSources(nil)
+}
+
+// NewSources returns the sources of the public registries like DefaultSources, sending their requests with the given
+// HTTP client, e.g. one whose transport adds tracing or goes through a corporate proxy. A nil client means each
+// source uses its default client.
+func NewSources(client *http.Client) map[string]Source {
+	return map[string]Source{
+		packageurl.TypeNPM:    &NPM{Client: client},
+		packageurl.TypePyPi:   &PyPI{Client: client},
+		packageurl.TypeCargo:  &Crates{Client: client},
+		packageurl.TypeGem:    &RubyGems{Client: client},
+		packageurl.TypeGithub: &GitHub{Client: client},
+		packageurl.TypeGolang: &GoProxy{Client: client},
+	}
+}
+
+// SourceTypes returns the purl types that the default sources look up, sorted.
+func SourceTypes() []string {
+	return slices.Sorted(maps.Keys(DefaultSources()))
+}
+
+// Enricher backfills missing licenses and homepages from package registries.
+type Enricher struct {
+	// Sources maps purl types to the source looking up their packages. Defaults to DefaultSources.
+	Sources map[string]Source
+	// Concurrency is the number of packages looked up in parallel. Defaults to 4.
+	Concurrency int
+	// Cache is optional; if set, cached lookups are reused and new ones are recorded in it.
+	Cache *Cache
+	// MaxAge is how long cached lookups are reused. Zero means they never expire.
+	MaxAge time.Duration
+	// Offline guarantees that no requests are made: only cached lookups are used (see ErrOffline).
+	Offline bool
+	// Logger is optional; set to nil to disable logging.
+	Logger *slog.Logger
+}
+
+// Enrich returns a copy of attributions where the packages missing a license (see
+// attribution.LicenseStatusUnknown) or a homepage are looked up in the registry of their purl type, for the exact
+// version of the purl. Found values are added with their provenance set to the registry (e.g. "enriched-from-npm"); a
+// found homepage also replaces a primary URL generated from the purl. Packages without a versioned purl, or whose
+// lookup fails, are left unchanged, and the failures are logged.
+// Returns an error only if the context is canceled.
+func (e *Enricher) Enrich(
+	ctx context.Context,
+	attributions []attribution.Attribution,
+) ([]attribution.Attribution, error) {
+	const defaultConcurrency = 4
+	concurrency := e.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	sources := e.Sources
+	if sources == nil {
+		sources = DefaultSources()
+	}
+
+	enriched := make([]attribution.Attribution, len(attributions))
+	copy(enriched, attributions)
+
+	var wg sync.WaitGroup
+	queue := make(chan int)
+	for range min(concurrency, len(attributions)) {
+		wg.Go(func() {
+			for i := range queue {
+				enriched[i] = e.enrich(ctx, sources, enriched[i])
+			}
+		})
+	}
+	for i, a := range attributions {
+		if ctx.Err() != nil {
+			break
+		}
+		if needsEnrichment(a) {
+			queue <- i
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("enrich attributions: %w", err)
+	}
+	return enriched, nil
+}
+
+// needsEnrichment reports whether an attribution misses a field that registries know, and has a purl to look it up.
+func needsEnrichment(a attribution.Attribution) bool {
+	missing := a.LicenseStatus() == attribution.LicenseStatusUnknown || a.URLOf(attribution.URLKindHomepage) == ""
+	return missing && a.Purl != ""
+}
+
+// enrich returns the attribution with the fields it misses filled from the source of its purl type, or unchanged if
+// there is no source or the lookup fails.
+func (e *Enricher) enrich(
+	ctx context.Context,
+	sources map[string]Source,
+	a attribution.Attribution,
+) attribution.Attribution {
+	purl, err := parsePurl(a.Purl)
+	if err != nil || purl.Version == "" {
+		return a
+	}
+	source, ok := sources[strings.ToLower(purl.Type)]
+	if !ok {
+		return a
+	}
+	if _, licenseOnly := source.(LicenseOnlySource); licenseOnly &&
+		a.LicenseStatus() != attribution.LicenseStatusUnknown {
+		return a
+	}
+
+	m, err := e.lookup(ctx, source, purl)
+	if err != nil {
+		if e.Logger != nil && ctx.Err() == nil {
+			e.Logger.DebugContext(ctx, "registry lookup failed", "source", source.Name(), "purl", a.Purl, "error", err)
+		}
+		return a
+	}
+	if e.Logger != nil {
+		e.Logger.DebugContext(ctx, "looked up package in registry", "source", source.Name(), "purl", a.Purl,
+			"license", m.License, "homepage", m.Homepage)
+	}
+	return Apply(a, m, attribution.ProvenanceEnriched(source.Name()))
+}
+
+// lookup returns the metadata of a package version from the cache, or looks it up in the source and caches it.
+// Packages unknown to the registry are cached too, so they are not looked up again; other failures are not. Offline,
+// uncached packages are not looked up.
+func (e *Enricher) lookup(ctx context.Context, source Source, purl packageurl.PackageURL) (Metadata, error) {
+	key := source.Name() + " " + purl.ToString()
+	if e.Cache != nil {
+		if entry, ok := e.Cache.Lookup(key, e.MaxAge, time.Now()); ok {
+			return entry.Metadata, nil
+		}
+	}
+	if e.Offline {
+		return Metadata{}, fmt.Errorf("%w: %s", ErrOffline, key)
+	}
+
+	m, err := source.Lookup(ctx, purl)
+	if e.Cache != nil && ctx.Err() == nil && (err == nil || errors.Is(err, ErrNotFound)) {
+		e.Cache.Store(key, CacheEntry{Metadata: m, Looked: time.Now()})
+	}
+	return m, err
+}
+
+// parsePurl parses a purl, keeping the case of Go module paths: module proxies need it (e.g.
+// "github.com/Azure/go-autorest"), but packageurl-go lowercases golang namespaces.
+func parsePurl(raw string) (packageurl.PackageURL, error) {
+	purl, err := packageurl.FromString(raw)
+	if err != nil || purl.Type != packageurl.TypeGolang {
+		return purl, err
+	}
+
+	rest, _ := strings.CutPrefix(strings.TrimPrefix(raw, "pkg:"), purl.Type+"/")
+	if i := strings.IndexAny(rest, "@?#"); i >= 0 {
+		rest = rest[:i]
+	}
+	if modulePath, unescapeErr := url.PathUnescape(strings.Trim(rest, "/")); unescapeErr == nil &&
+		strings.EqualFold(modulePath, path.Join(purl.Namespace, purl.Name)) {
+		purl.Namespace, purl.Name = path.Split(modulePath)
+		purl.Namespace = strings.TrimSuffix(purl.Namespace, "/")
+	}
+	return purl, nil
+}
+
+// Apply returns a copy of the attribution with the metadata filling the fields it misses: the license if it is
+// unknown, and the homepage if it has none, which also replaces a missing or generated primary URL. Filled fields get
+// the given provenance. Sources outside this package can use it to enrich attributions the same way.
+func Apply(a attribution.Attribution, m Metadata, p attribution.Provenance) attribution.Attribution {
+	if license := strings.TrimSpace(m.License); license != "" && a.LicenseStatus() == attribution.LicenseStatusUnknown {
+		a.License = &license
+		a = a.WithProvenance(attribution.FieldLicense, p)
+	}
+
+	homepage, ok := attribution.NormalizeURL(m.Homepage)
+	if !ok || a.URLOf(attribution.URLKindHomepage) != "" {
+		return a
+	}
+	a = a.WithURL(attribution.URLKindHomepage, homepage)
+	if a.URL == nil || a.Provenance[attribution.FieldURL] == attribution.ProvenanceGenerated {
+		a.URL = &homepage
+		a = a.WithProvenance(attribution.FieldURL, p)
+	}
+	return a
+}
@@ -0,0 +1,313 @@
+package enrich_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/enrich"
+	"github.com/package-url/packageurl-go"
+)
+
+// fakeSource is a source returning fixed metadata, keyed by purl.
+type fakeSource struct {
+	metadata map[string]enrich.Metadata
+	lookups  atomic.Int32
+}
+
+func (s *fakeSource) Name() string { return "fake" }
+
+func (s *fakeSource) Lookup(_ context.Context, purl packageurl.PackageURL) (enrich.Metadata, error) {
+	s.lookups.Add(1)
+	m, ok := s.metadata[purl.ToString()]
+	if !ok {
+		return enrich.Metadata{}, enrich.ErrNotFound
+	}
+	return m, nil
+}
+
+func ptr(s string) *string { return &s }
+
+// TestEnricher_Enrich tests that only missing fields are filled, with the provenance of the source.
+func TestEnricher_Enrich(t *testing.T) {
+	t.Parallel()
+
+	source := &fakeSource{metadata: map[string]enrich.Metadata{
+		"pkg:npm/lodash@4.17.21": {License: "MIT", Homepage: "https://lodash.com/"},
+		"pkg:npm/react@18.2.0":   {License: "Apache-2.0", Homepage: "https://react.dev/"},
+	}}
+	enricher := &enrich.Enricher{Sources: map[string]enrich.Source{"npm": source}}
+
+	generated := (attribution.Attribution{
+		Name: "lodash",
+		Purl: "pkg:npm/lodash@4.17.21",
+		URL:  ptr("https://www.npmjs.com/package/lodash"),
+	}).WithProvenance(attribution.FieldURL, attribution.ProvenanceGenerated)
+	input := []attribution.Attribution{
+		generated,
+		{Name: "react", Purl: "pkg:npm/react@18.2.0", License: ptr("MIT")},
+		{Name: "unversioned", Purl: "pkg:npm/unversioned"},
+		{Name: "missing", Purl: "pkg:npm/missing@1.0.0"},
+		{Name: "unsupported", Purl: "pkg:golang/example.com/mod@v1.0.0"},
+	}
+
+	got, err := enricher.Enrich(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Enrich() unexpected error: %v", err)
+	}
+	if len(got) != len(input) {
+		t.Fatalf("Enrich() returned %d attributions, want %d", len(got), len(input))
+	}
+
+	lodash := got[0]
+	if lodash.License == nil || *lodash.License != "MIT" {
+		t.Errorf("lodash License = %v, want MIT", lodash.License)
+	}
+	if lodash.URL == nil || *lodash.URL != "https://lodash.com/" {
+		t.Errorf("lodash URL = %v, want the homepage to replace the generated URL", lodash.URL)
+	}
+	want := attribution.ProvenanceEnriched("fake")
+	if lodash.Provenance[attribution.FieldLicense] != want || lodash.Provenance[attribution.FieldURL] != want {
+		t.Errorf("lodash Provenance = %v, want %q for license and url", lodash.Provenance, want)
+	}
+
+	react := got[1]
+	if *react.License != "MIT" {
+		t.Errorf("react License = %q, want the known license kept", *react.License)
+	}
+	if react.URL == nil || *react.URL != "https://react.dev/" {
+		t.Errorf("react URL = %v, want the homepage", react.URL)
+	}
+	if _, ok := react.Provenance[attribution.FieldLicense]; ok {
+		t.Errorf("react license provenance set for a kept license")
+	}
+
+	if !slices.EqualFunc(got[2:], input[2:], func(a, b attribution.Attribution) bool {
+		return a.License == b.License && a.URL == b.URL
+	}) {
+		t.Errorf("Enrich() changed attributions that cannot be enriched")
+	}
+	if input[0].License != nil || *input[0].URL != "https://www.npmjs.com/package/lodash" {
+		t.Errorf("Enrich() modified its input")
+	}
+	if n := source.lookups.Load(); n != 3 {
+		t.Errorf("lookups = %d, want 3 (versionless and unsupported purls skipped)", n)
+	}
+}
+
+// licenseOnlySource is a fakeSource that only looks up licenses.
+type licenseOnlySource struct {
+	fakeSource
+}
+
+func (s *licenseOnlySource) LicenseOnly() {}
+
+// TestEnricher_Enrich_LicenseOnly tests that packages with a license are not looked up in a license-only source.
+func TestEnricher_Enrich_LicenseOnly(t *testing.T) {
+	t.Parallel()
+
+	source := &licenseOnlySource{fakeSource{metadata: map[string]enrich.Metadata{
+		"pkg:github/octo/a@v1.0.0": {License: "MIT"},
+	}}}
+	enricher := &enrich.Enricher{Sources: map[string]enrich.Source{"github": source}}
+
+	got, err := enricher.Enrich(context.Background(), []attribution.Attribution{
+		{Name: "a", Purl: "pkg:github/octo/a@v1.0.0"},
+		{Name: "b", Purl: "pkg:github/octo/b@v1.0.0", License: ptr("Apache-2.0")},
+	})
+	if err != nil {
+		t.Fatalf("Enrich() unexpected error: %v", err)
+	}
+	if got[0].License == nil || *got[0].License != "MIT" {
+		t.Errorf("a License = %v, want MIT", got[0].License)
+	}
+	if n := source.lookups.Load(); n != 1 {
+		t.Errorf("lookups = %d, want 1 (the licensed package skipped)", n)
+	}
+}
+
+// TestEnricher_Enrich_GoModuleCase tests that the case of Go module paths is kept, since module proxies need it.
+func TestEnricher_Enrich_GoModuleCase(t *testing.T) {
+	t.Parallel()
+
+	source := &fakeSource{metadata: map[string]enrich.Metadata{
+		"pkg:golang/github.com/Azure/go-autorest@v14.2.0%2Bincompatible": {License: "Apache-2.0"},
+	}}
+	enricher := &enrich.Enricher{Sources: map[string]enrich.Source{"golang": source}}
+
+	got, err := enricher.Enrich(context.Background(), []attribution.Attribution{
+		{Name: "go-autorest", Purl: "pkg:golang/github.com/Azure/go-autorest@v14.2.0%2Bincompatible"},
+	})
+	if err != nil {
+		t.Fatalf("Enrich() unexpected error: %v", err)
+	}
+	if got[0].License == nil || *got[0].License != "Apache-2.0" {
+		t.Errorf("License = %v, want Apache-2.0 looked up with the module path case", got[0].License)
+	}
+}
+
+// TestEnricher_Enrich_Cache tests that cached lookups, including unknown packages, are reused.
+func TestEnricher_Enrich_Cache(t *testing.T) {
+	t.Parallel()
+
+	source := &fakeSource{metadata: map[string]enrich.Metadata{
+		"pkg:npm/lodash@4.17.21": {License: "MIT"},
+	}}
+	enricher := &enrich.Enricher{Sources: map[string]enrich.Source{"npm": source}, Cache: enrich.NewCache()}
+	input := []attribution.Attribution{
+		{Name: "lodash", Purl: "pkg:npm/lodash@4.17.21"},
+		{Name: "missing", Purl: "pkg:npm/missing@1.0.0"},
+	}
+
+	for range 2 {
+		got, err := enricher.Enrich(context.Background(), input)
+		if err != nil {
+			t.Fatalf("Enrich() unexpected error: %v", err)
+		}
+		if got[0].License == nil || *got[0].License != "MIT" {
+			t.Errorf("lodash License = %v, want MIT", got[0].License)
+		}
+	}
+	if n := source.lookups.Load(); n != 2 {
+		t.Errorf("lookups = %d, want 2 (the second run served from the cache)", n)
+	}
+}
+
+// TestEnricher_Enrich_CacheExpired tests that lookups cached longer than MaxAge ago are looked up again.
+func TestEnricher_Enrich_CacheExpired(t *testing.T) {
+	t.Parallel()
+
+	source := &fakeSource{metadata: map[string]enrich.Metadata{
+		"pkg:npm/lodash@4.17.21": {License: "MIT"},
+	}}
+	cache := enrich.NewCache()
+	cache.Store("fake pkg:npm/lodash@4.17.21", enrich.CacheEntry{
+		Metadata: enrich.Metadata{License: "ISC"},
+		Looked:   time.Now().Add(-2 * time.Hour),
+	})
+	enricher := &enrich.Enricher{Sources: map[string]enrich.Source{"npm": source}, Cache: cache, MaxAge: time.Hour}
+
+	got, err := enricher.Enrich(context.Background(), []attribution.Attribution{
+		{Name: "lodash", Purl: "pkg:npm/lodash@4.17.21"},
+	})
+	if err != nil {
+		t.Fatalf("Enrich() unexpected error: %v", err)
+	}
+	if got[0].License == nil || *got[0].License != "MIT" || source.lookups.Load() != 1 {
+		t.Errorf("Enrich() License = %v after %d lookups, want MIT looked up again", got[0].License,
+			source.lookups.Load())
+	}
+}
+
+// TestEnricher_Enrich_Offline tests that offline enrichers only use cached lookups.
+func TestEnricher_Enrich_Offline(t *testing.T) {
+	t.Parallel()
+
+	source := &fakeSource{metadata: map[string]enrich.Metadata{
+		"pkg:npm/lodash@4.17.21": {License: "MIT"},
+		"pkg:npm/react@18.2.0":   {License: "MIT"},
+	}}
+	cache := enrich.NewCache()
+	cache.Store("fake pkg:npm/lodash@4.17.21", enrich.CacheEntry{Metadata: enrich.Metadata{License: "MIT"}})
+	enricher := &enrich.Enricher{Sources: map[string]enrich.Source{"npm": source}, Cache: cache, Offline: true}
+
+	got, err := enricher.Enrich(context.Background(), []attribution.Attribution{
+		{Name: "lodash", Purl: "pkg:npm/lodash@4.17.21"},
+		{Name: "react", Purl: "pkg:npm/react@18.2.0"},
+	})
+	if err != nil {
+		t.Fatalf("Enrich() unexpected error: %v", err)
+	}
+	if got[0].License == nil || *got[0].License != "MIT" || got[1].License != nil {
+		t.Errorf("Enrich() licenses = %v, %v, want only the cached one", got[0].License, got[1].License)
+	}
+	if n := source.lookups.Load(); n != 0 {
+		t.Errorf("lookups = %d offline, want none", n)
+	}
+}
+
+// roundTripperFunc is an http.RoundTripper implemented by a function.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+// TestNewSources tests that the sources send their requests with the given client.
+func TestNewSources(t *testing.T) {
+	t.Parallel()
+
+	var requested []string
+	client := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		requested = append(requested, r.URL.String())
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"license": "MIT"}`)),
+			Request:    r,
+		}, nil
+	})}
+
+	sources := enrich.NewSources(client)
+	if !slices.Equal(slices.Sorted(maps.Keys(sources)), enrich.SourceTypes()) {
+		t.Errorf("NewSources() types = %v, want %v", slices.Sorted(maps.Keys(sources)), enrich.SourceTypes())
+	}
+
+	enricher := &enrich.Enricher{Sources: sources, Concurrency: 1}
+	got, err := enricher.Enrich(context.Background(), []attribution.Attribution{
+		{Name: "lodash", Purl: "pkg:npm/lodash@4.17.21"},
+	})
+	if err != nil {
+		t.Fatalf("Enrich() unexpected error: %v", err)
+	}
+	if got[0].License == nil || *got[0].License != "MIT" {
+		t.Errorf("License = %v, want MIT from the client's transport", got[0].License)
+	}
+	if want := []string{enrich.DefaultNPMURL + "/lodash/4.17.21"}; !slices.Equal(requested, want) {
+		t.Errorf("requested = %v, want %v", requested, want)
+	}
+}
+
+// TestEnricher_Enrich_Canceled tests that a canceled context is reported.
+func TestEnricher_Enrich_Canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	enricher := &enrich.Enricher{Sources: map[string]enrich.Source{"npm": &fakeSource{}}}
+	_, err := enricher.Enrich(ctx, []attribution.Attribution{{Name: "lodash", Purl: "pkg:npm/lodash@4.17.21"}})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Enrich() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+// TestApply tests that metadata only fills an unknown license and a missing homepage.
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	p := attribution.ProvenanceEnriched("npm")
+	m := enrich.Metadata{License: "MIT", Homepage: "git+https://github.com/lodash/lodash.git"}
+
+	got := enrich.Apply(attribution.Attribution{Name: "lodash", License: ptr("NOASSERTION")}, m, p)
+	if *got.License != "MIT" || got.Provenance[attribution.FieldLicense] != p {
+		t.Errorf("Apply() License = %q (%q), want MIT (%q)", *got.License, got.Provenance[attribution.FieldLicense], p)
+	}
+	if got.URL == nil || *got.URL != "https://github.com/lodash/lodash" {
+		t.Errorf("Apply() URL = %v, want the normalized homepage", got.URL)
+	}
+
+	got = enrich.Apply(attribution.Attribution{Name: "lodash", License: ptr("NONE"), URL: ptr("https://x.test")}, m, p)
+	if *got.License != "NONE" {
+		t.Errorf("Apply() License = %q, want NONE kept", *got.License)
+	}
+	if *got.URL != "https://x.test" {
+		t.Errorf("Apply() URL = %q, want an extracted URL kept", *got.URL)
+	}
+}
@@ -0,0 +1,59 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/boringbin/sbomattr/internal/github"
+	"github.com/package-url/packageurl-go"
+)
+
+// GitHub looks up the licenses of github purls ("pkg:github/<owner>/<repo>@<ref>") with the GitHub license API, which
+// detects the license file of a repository at a ref. Only licenses are looked up, so packages that already have one
+// are not (see LicenseOnlySource).
+type GitHub struct {
+	// BaseURL is the API base URL, without a trailing slash. Defaults to github.DefaultBaseURL.
+	BaseURL string
+	// Token is the optional GitHub token. Unauthenticated requests are limited to 60 per hour.
+	Token string
+	// Client is the HTTP client used for requests. Defaults to a client with a 30 second timeout.
+	Client *http.Client
+	// Logger is optional; set to nil to disable logging.
+	Logger *slog.Logger
+}
+
+// Name returns "github".
+func (s *GitHub) Name() string {
+	return "github"
+}
+
+// LicenseOnly marks GitHub as a LicenseOnlySource.
+func (s *GitHub) LicenseOnly() {}
+
+// Lookup returns the SPDX ID of the license GitHub detects in the repository at the ref of the purl version. Licenses
+// GitHub does not recognize (NOASSERTION) are ignored. Rate limits are waited for (see the internal GitHub client).
+func (s *GitHub) Lookup(ctx context.Context, purl packageurl.PackageURL) (Metadata, error) {
+	client := github.NewClient(s.Token)
+	client.BaseURL = baseURL(s.BaseURL, github.DefaultBaseURL)
+	client.HTTPClient = s.Client
+	if client.HTTPClient == nil {
+		client.HTTPClient = &http.Client{Timeout: defaultTimeout}
+	}
+	client.Logger = s.Logger
+
+	license, err := client.License(ctx, purl.Namespace+"/"+purl.Name, purl.Version)
+	if errors.Is(err, github.ErrNotFound) {
+		return Metadata{}, fmt.Errorf("%w: %s", ErrNotFound, err)
+	}
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	if license.License.SPDXID == "NOASSERTION" {
+		return Metadata{}, nil
+	}
+	return Metadata{License: license.License.SPDXID}, nil
+}
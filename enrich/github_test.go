@@ -0,0 +1,57 @@
+package enrich_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/boringbin/sbomattr/enrich"
+)
+
+// TestGitHub_Lookup tests that the license detected at the ref of the purl is looked up with the token.
+func TestGitHub_Lookup(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("Authorization = %q, want the token", got)
+		}
+		switch r.URL.Path + "@" + r.URL.Query().Get("ref") {
+		case "/repos/octo/mit/license@v1.0.0":
+			fmt.Fprint(w, `{"license": {"key": "mit", "spdx_id": "MIT"}}`)
+		case "/repos/octo/custom/license@v1.0.0":
+			fmt.Fprint(w, `{"license": {"key": "other", "spdx_id": "NOASSERTION"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	source := &enrich.GitHub{BaseURL: server.URL, Token: "test-token"}
+
+	tests := []struct {
+		purl    string
+		want    enrich.Metadata
+		wantErr error
+	}{
+		{purl: "pkg:github/octo/mit@v1.0.0", want: enrich.Metadata{License: "MIT"}},
+		{purl: "pkg:github/octo/custom@v1.0.0", want: enrich.Metadata{}},
+		{purl: "pkg:github/octo/mit@v9.9.9", wantErr: enrich.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.purl, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := source.Lookup(context.Background(), mustPurl(t, tt.purl))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Lookup() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Lookup() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package enrich
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/package-url/packageurl-go"
+)
+
+// DefaultGoProxyURL is the base URL of the public Go module proxy.
+const DefaultGoProxyURL = "https://proxy.golang.org"
+
+const (
+	// maxModuleZipBytes is the size limit of a downloaded module zip.
+	maxModuleZipBytes = 64 << 20
+	// maxLicenseFileBytes is the size limit of a license file read from a module zip.
+	maxLicenseFileBytes = 1 << 20
+)
+
+// GoProxy looks up the licenses of Go modules by downloading the module zip from a Go module proxy and identifying
+// the license file at the module root, as pkg.go.dev does. Only licenses are looked up, so modules that already have
+// one are not downloaded (see LicenseOnlySource).
+type GoProxy struct {
+	// BaseURL is the proxy base URL, without a trailing slash. Defaults to DefaultGoProxyURL.
+	BaseURL string
+	// Client is the HTTP client used for requests. Defaults to a client with a 30 second timeout.
+	Client *http.Client
+}
+
+// Name returns "goproxy".
+func (s *GoProxy) Name() string {
+	return "goproxy"
+}
+
+// LicenseOnly marks GoProxy as a LicenseOnlySource.
+func (s *GoProxy) LicenseOnly() {}
+
+// Lookup returns the license of a Go module version, identified from its LICENSE (or COPYING) file among the
+// bundled license texts (see attribution.IdentifyLicense). Modules without a license file, or whose license file is
+// not identified, get no license.
+func (s *GoProxy) Lookup(ctx context.Context, purl packageurl.PackageURL) (Metadata, error) {
+	modulePath := purl.Name
+	if purl.Namespace != "" {
+		modulePath = purl.Namespace + "/" + purl.Name
+	}
+	u := baseURL(s.BaseURL, DefaultGoProxyURL) + "/" + escapeModulePath(modulePath) + "/@v/" +
+		escapeModulePath(purl.Version) + ".zip"
+
+	resp, err := get(ctx, s.Client, u, "application/zip")
+	if err != nil {
+		return Metadata{}, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxModuleZipBytes+1))
+	if err != nil {
+		return Metadata{}, fmt.Errorf("read %s: %w", u, err)
+	}
+	if len(data) > maxModuleZipBytes {
+		return Metadata{}, fmt.Errorf("read %s: module zip larger than %d bytes", u, maxModuleZipBytes)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return Metadata{}, fmt.Errorf("open %s: %w", u, err)
+	}
+
+	return moduleLicense(archive, modulePath+"@"+purl.Version)
+}
+
+// moduleLicense returns the license identified from the first license file at the root of a module zip, whose
+// files are all under "<module>@<version>/".
+func moduleLicense(archive *zip.Reader, root string) (Metadata, error) {
+	files := slices.Clone(archive.File)
+	slices.SortFunc(files, func(a, b *zip.File) int { return strings.Compare(a.Name, b.Name) })
+
+	for _, f := range files {
+		dir, name := path.Split(f.Name)
+		if !strings.EqualFold(strings.TrimSuffix(dir, "/"), root) || !isLicenseFile(name) {
+			continue
+		}
+
+		r, err := f.Open()
+		if err != nil {
+			return Metadata{}, fmt.Errorf("open %s: %w", f.Name, err)
+		}
+		text, err := io.ReadAll(io.LimitReader(r, maxLicenseFileBytes))
+		r.Close()
+		if err != nil {
+			return Metadata{}, fmt.Errorf("read %s: %w", f.Name, err)
+		}
+
+		if license, ok := attribution.IdentifyLicense(string(text)); ok {
+			return Metadata{License: license}, nil
+		}
+	}
+	return Metadata{}, nil
+}
+
+// isLicenseFile reports whether a file name is a conventional license file name, e.g. "LICENSE" or "COPYING.md".
+func isLicenseFile(name string) bool {
+	name = strings.ToUpper(name)
+	for _, prefix := range []string{"LICENSE", "LICENCE", "COPYING"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeModulePath escapes a module path or version for a module proxy URL: upper-case letters are replaced by an
+// exclamation mark followed by the lower-case letter, e.g. "github.com/Azure/azure-sdk-for-go" becomes
+// "github.com/!azure/azure-sdk-for-go".
+func escapeModulePath(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,101 @@
+package enrich_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/enrich"
+	"github.com/package-url/packageurl-go"
+)
+
+// moduleZip returns a module zip with the given files, by path relative to the module root.
+func moduleZip(t *testing.T, root string, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(root + "/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// TestGoProxy_Lookup tests that the license file at the module root is identified.
+func TestGoProxy_Lookup(t *testing.T) {
+	t.Parallel()
+
+	mit, _ := attribution.LicenseText("MIT")
+	bsd, _ := attribution.LicenseText("BSD-3-Clause")
+	zips := map[string][]byte{
+		"/github.com/!azure/go-autorest/@v/v14.2.0+incompatible.zip": moduleZip(t,
+			"github.com/Azure/go-autorest@v14.2.0+incompatible", map[string]string{
+				"LICENSE":        mit,
+				"autorest.go":    "package autorest",
+				"vendor/LICENSE": bsd,
+			}),
+		"/example.com/nested/@v/v1.0.0.zip": moduleZip(t, "example.com/nested@v1.0.0", map[string]string{
+			"third_party/COPYING": bsd,
+		}),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := zips[r.URL.EscapedPath()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/zip")
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	source := &enrich.GoProxy{BaseURL: server.URL}
+
+	tests := []struct {
+		modulePath string
+		version    string
+		want       enrich.Metadata
+		wantErr    error
+	}{
+		{
+			modulePath: "github.com/Azure/go-autorest",
+			version:    "v14.2.0+incompatible",
+			want:       enrich.Metadata{License: "MIT"},
+		},
+		{modulePath: "example.com/nested", version: "v1.0.0", want: enrich.Metadata{}},
+		{modulePath: "example.com/missing", version: "v1.0.0", wantErr: enrich.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.modulePath, func(t *testing.T) {
+			t.Parallel()
+
+			// Built directly, since parsing lowercases the module path (see Enricher.Enrich)
+			namespace, name := path.Split(tt.modulePath)
+			purl := packageurl.NewPackageURL(packageurl.TypeGolang, strings.TrimSuffix(namespace, "/"), name,
+				tt.version, nil, "")
+			got, err := source.Lookup(context.Background(), *purl)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Lookup() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Lookup() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
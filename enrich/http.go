@@ -0,0 +1,77 @@
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when a registry does not know the package version.
+	ErrNotFound = errors.New("package not found")
+	// ErrUnexpectedStatus is returned when a registry responds with an unexpected status code.
+	ErrUnexpectedStatus = errors.New("unexpected response status")
+	// ErrOffline is returned in offline mode when a lookup is not cached.
+	ErrOffline = errors.New("lookup not cached in offline mode")
+)
+
+// defaultTimeout is the timeout of the HTTP client used by sources without a client.
+const defaultTimeout = 30 * time.Second
+
+// getJSON sends a GET request and decodes the JSON response into v.
+// Returns ErrNotFound for 404 responses.
+func getJSON(ctx context.Context, client *http.Client, url string, v any) error {
+	resp, err := get(ctx, client, url, "application/json")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err = json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode %s: %w", url, err)
+	}
+	return nil
+}
+
+// get sends a GET request accepting the given media type, and returns the response if its status is 200 OK. The
+// caller must close the response body.
+// Returns ErrNotFound for 404 (and 410) responses.
+func get(ctx context.Context, client *http.Client, url, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	// Some registries (crates.io) reject requests without a User-Agent
+	req.Header.Set("User-Agent", "sbomattr (https://github.com/boringbin/sbomattr)")
+	req.Header.Set("Accept", accept)
+
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", url, err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, url)
+	case resp.StatusCode != http.StatusOK:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: GET %s: %s", ErrUnexpectedStatus, url, resp.Status)
+	}
+	return resp, nil
+}
+
+// baseURL returns the configured base URL of a source without a trailing slash, or its default.
+func baseURL(configured, fallback string) string {
+	if configured == "" {
+		return fallback
+	}
+	return strings.TrimRight(configured, "/")
+}
@@ -0,0 +1,84 @@
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/package-url/packageurl-go"
+)
+
+// DefaultNPMURL is the base URL of the public npm registry.
+const DefaultNPMURL = "https://registry.npmjs.org"
+
+// NPM looks up packages in the npm registry.
+type NPM struct {
+	// BaseURL is the registry base URL, without a trailing slash. Defaults to DefaultNPMURL.
+	BaseURL string
+	// Client is the HTTP client used for requests. Defaults to a client with a 30 second timeout.
+	Client *http.Client
+}
+
+// npmVersion is the part of the registry document of a package version that is needed.
+type npmVersion struct {
+	License  json.RawMessage `json:"license"`
+	Licenses []npmLicense    `json:"licenses"`
+	Homepage string          `json:"homepage"`
+}
+
+// npmLicense is a license object, as used by the deprecated "license": {"type": ...} and "licenses" fields.
+type npmLicense struct {
+	Type string `json:"type"`
+}
+
+// Name returns "npm".
+func (s *NPM) Name() string {
+	return "npm"
+}
+
+// Lookup returns the license and homepage of an npm package version, from its package.json as published.
+// The UNLICENSED license of private packages is returned as NONE, and "SEE LICENSE IN <file>" licenses are ignored.
+func (s *NPM) Lookup(ctx context.Context, purl packageurl.PackageURL) (Metadata, error) {
+	name := purl.Name
+	if purl.Namespace != "" {
+		name = purl.Namespace + "/" + purl.Name
+	}
+
+	var v npmVersion
+	endpoint := baseURL(s.BaseURL, DefaultNPMURL) + "/" + name + "/" + url.PathEscape(purl.Version)
+	if err := getJSON(ctx, s.Client, endpoint, &v); err != nil {
+		return Metadata{}, err
+	}
+	return Metadata{License: v.license(), Homepage: v.Homepage}, nil
+}
+
+// license returns the license of the package version: the SPDX expression of "license", or else the type of the
+// deprecated license objects, which all apply.
+func (v npmVersion) license() string {
+	var license string
+	var object npmLicense
+	switch {
+	case json.Unmarshal(v.License, &license) == nil && strings.TrimSpace(license) != "":
+	case json.Unmarshal(v.License, &object) == nil && strings.TrimSpace(object.Type) != "":
+		license = object.Type
+	default:
+		types := make([]string, 0, len(v.Licenses))
+		for _, l := range v.Licenses {
+			types = append(types, l.Type)
+		}
+		license = attribution.JoinLicenses(types)
+	}
+
+	license = strings.TrimSpace(license)
+	switch {
+	case strings.EqualFold(license, "UNLICENSED"):
+		return attribution.LicenseNone
+	case strings.HasPrefix(strings.ToUpper(license), "SEE LICENSE IN"):
+		return ""
+	default:
+		return license
+	}
+}
@@ -0,0 +1,83 @@
+package enrich_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/boringbin/sbomattr/enrich"
+	"github.com/package-url/packageurl-go"
+)
+
+// newJSONServer returns a test server responding to GET requests of the given paths with their JSON document, and
+// with 404 to any other path.
+func newJSONServer(t *testing.T, documents map[string]string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		doc, ok := documents[r.URL.EscapedPath()]
+		if !ok || r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, doc)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// mustPurl parses a purl, failing the test if it is invalid.
+func mustPurl(t *testing.T, s string) packageurl.PackageURL {
+	t.Helper()
+
+	purl, err := packageurl.FromString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return purl
+}
+
+// TestNPM_Lookup tests that licenses are read from the license field or the deprecated license objects.
+func TestNPM_Lookup(t *testing.T) {
+	t.Parallel()
+
+	server := newJSONServer(t, map[string]string{
+		"/lodash/4.17.21":       `{"license": "MIT", "homepage": "https://lodash.com/"}`,
+		"/@angular/core/17.0.0": `{"license": {"type": "MIT"}}`,
+		"/legacy/1.0.0":         `{"licenses": [{"type": "MIT"}, {"type": "Apache-2.0"}]}`,
+		"/private/1.0.0":        `{"license": "UNLICENSED"}`,
+		"/custom/1.0.0":         `{"license": "SEE LICENSE IN LICENSE.md"}`,
+	})
+	source := &enrich.NPM{BaseURL: server.URL + "/"}
+
+	tests := []struct {
+		purl    string
+		want    enrich.Metadata
+		wantErr error
+	}{
+		{purl: "pkg:npm/lodash@4.17.21", want: enrich.Metadata{License: "MIT", Homepage: "https://lodash.com/"}},
+		{purl: "pkg:npm/%40angular/core@17.0.0", want: enrich.Metadata{License: "MIT"}},
+		{purl: "pkg:npm/legacy@1.0.0", want: enrich.Metadata{License: "MIT AND Apache-2.0"}},
+		{purl: "pkg:npm/private@1.0.0", want: enrich.Metadata{License: "NONE"}},
+		{purl: "pkg:npm/custom@1.0.0", want: enrich.Metadata{}},
+		{purl: "pkg:npm/missing@1.0.0", wantErr: enrich.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.purl, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := source.Lookup(context.Background(), mustPurl(t, tt.purl))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Lookup() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Lookup() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package enrich
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+)
+
+// DefaultPyPIURL is the base URL of the public Python Package Index.
+const DefaultPyPIURL = "https://pypi.org"
+
+// maxPyPILicenseLen is the longest free-form license field accepted as a license name. Longer values are usually the
+// full license text.
+const maxPyPILicenseLen = 64
+
+// PyPI looks up packages with the PyPI JSON API.
+type PyPI struct {
+	// BaseURL is the index base URL, without a trailing slash. Defaults to DefaultPyPIURL.
+	BaseURL string
+	// Client is the HTTP client used for requests. Defaults to a client with a 30 second timeout.
+	Client *http.Client
+}
+
+// pypiRelease is the part of the JSON API document of a release that is needed.
+type pypiRelease struct {
+	Info struct {
+		LicenseExpression string            `json:"license_expression"`
+		License           string            `json:"license"`
+		Classifiers       []string          `json:"classifiers"`
+		HomePage          string            `json:"home_page"`
+		ProjectURLs       map[string]string `json:"project_urls"`
+	} `json:"info"`
+}
+
+// Name returns "pypi".
+func (s *PyPI) Name() string {
+	return "pypi"
+}
+
+// Lookup returns the license and homepage of a PyPI release. The license is the SPDX license expression of the
+// release (PEP 639) if it has one, or else its free-form license field if it is short and on one line, or else the
+// SPDX identifier of its license classifier, if unambiguous.
+func (s *PyPI) Lookup(ctx context.Context, purl packageurl.PackageURL) (Metadata, error) {
+	var r pypiRelease
+	endpoint := baseURL(s.BaseURL, DefaultPyPIURL) + "/pypi/" + url.PathEscape(purl.Name) + "/" +
+		url.PathEscape(purl.Version) + "/json"
+	if err := getJSON(ctx, s.Client, endpoint, &r); err != nil {
+		return Metadata{}, err
+	}
+
+	homepage := r.Info.HomePage
+	for key, u := range r.Info.ProjectURLs {
+		if homepage == "" && strings.EqualFold(key, "homepage") {
+			homepage = u
+		}
+	}
+	return Metadata{License: r.license(), Homepage: homepage}, nil
+}
+
+// license returns the license of the release, or an empty string if it is unknown.
+func (r pypiRelease) license() string {
+	if expression := strings.TrimSpace(r.Info.LicenseExpression); expression != "" {
+		return expression
+	}
+	if license := strings.TrimSpace(r.Info.License); license != "" && len(license) <= maxPyPILicenseLen &&
+		!strings.Contains(license, "\n") && !strings.EqualFold(license, "UNKNOWN") {
+		return license
+	}
+	for _, classifier := range r.Info.Classifiers {
+		if id, ok := pypiClassifierLicenses()[strings.TrimSpace(classifier)]; ok {
+			return id
+		}
+	}
+	return ""
+}
+
+// pypiClassifierLicenses maps the trove license classifiers that name a single license version to its SPDX identifier.
+// Classifiers naming a license family without a version (e.g. "BSD License") are left out.
+func pypiClassifierLicenses() map[string]string {
+	return map[string]string{
+		"License :: OSI Approved :: MIT License":                                             "MIT",
+		"License :: OSI Approved :: ISC License (ISCL)":                                      "ISC",
+		"License :: OSI Approved :: Mozilla Public License 2.0 (MPL 2.0)":                    "MPL-2.0",
+		"License :: OSI Approved :: GNU General Public License v2 (GPLv2)":                   "GPL-2.0-only",
+		"License :: OSI Approved :: GNU General Public License v2 or later (GPLv2+)":         "GPL-2.0-or-later",
+		"License :: OSI Approved :: GNU General Public License v3 (GPLv3)":                   "GPL-3.0-only",
+		"License :: OSI Approved :: GNU General Public License v3 or later (GPLv3+)":         "GPL-3.0-or-later",
+		"License :: OSI Approved :: GNU Lesser General Public License v3 (LGPLv3)":           "LGPL-3.0-only",
+		"License :: OSI Approved :: GNU Affero General Public License v3":                    "AGPL-3.0-only",
+		"License :: OSI Approved :: The Unlicense (Unlicense)":                               "Unlicense",
+		"License :: OSI Approved :: Python Software Foundation License":                      "PSF-2.0",
+		"License :: OSI Approved :: Boost Software License 1.0 (BSL-1.0)":                    "BSL-1.0",
+		"License :: OSI Approved :: zlib/libpng License":                                     "Zlib",
+		"License :: CC0 1.0 Universal (CC0 1.0) Public Domain Dedication":                    "CC0-1.0",
+		"License :: OSI Approved :: Eclipse Public License 2.0 (EPL-2.0)":                    "EPL-2.0",
+		"License :: OSI Approved :: GNU Lesser General Public License v2 or later (LGPLv2+)": "LGPL-2.0-or-later",
+	}
+}
@@ -0,0 +1,55 @@
+package enrich_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/boringbin/sbomattr/enrich"
+)
+
+// TestPyPI_Lookup tests that the license expression, the short license field, or the license classifier is used, in
+// that order.
+func TestPyPI_Lookup(t *testing.T) {
+	t.Parallel()
+
+	server := newJSONServer(t, map[string]string{
+		"/pypi/requests/2.31.0/json": `{"info": {"license": "Apache 2.0", "license_expression": "Apache-2.0", ` +
+			`"project_urls": {"Homepage": "https://requests.readthedocs.io"}}}`,
+		"/pypi/six/1.16.0/json": `{"info": {"license": "MIT", "home_page": "https://github.com/benjaminp/six"}}`,
+		"/pypi/numpy/1.26.0/json": `{"info": {"license": "Copyright (c) 2005-2023, NumPy.\nAll rights...", ` +
+			`"classifiers": ["Programming Language :: C", "License :: OSI Approved :: BSD License"]}}`,
+		"/pypi/attrs/23.1.0/json": `{"info": {"license": "UNKNOWN", ` +
+			`"classifiers": ["License :: OSI Approved :: MIT License"]}}`,
+	})
+	source := &enrich.PyPI{BaseURL: server.URL}
+
+	tests := []struct {
+		purl string
+		want enrich.Metadata
+	}{
+		{
+			purl: "pkg:pypi/requests@2.31.0",
+			want: enrich.Metadata{License: "Apache-2.0", Homepage: "https://requests.readthedocs.io"},
+		},
+		{
+			purl: "pkg:pypi/six@1.16.0",
+			want: enrich.Metadata{License: "MIT", Homepage: "https://github.com/benjaminp/six"},
+		},
+		{purl: "pkg:pypi/numpy@1.26.0", want: enrich.Metadata{}},
+		{purl: "pkg:pypi/attrs@23.1.0", want: enrich.Metadata{License: "MIT"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.purl, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := source.Lookup(context.Background(), mustPurl(t, tt.purl))
+			if err != nil {
+				t.Fatalf("Lookup() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Lookup() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package enrich
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/package-url/packageurl-go"
+)
+
+// DefaultRubyGemsURL is the base URL of the RubyGems.org API.
+const DefaultRubyGemsURL = "https://rubygems.org"
+
+// RubyGems looks up packages with the RubyGems.org API.
+type RubyGems struct {
+	// BaseURL is the API base URL, without a trailing slash. Defaults to DefaultRubyGemsURL.
+	BaseURL string
+	// Client is the HTTP client used for requests. Defaults to a client with a 30 second timeout.
+	Client *http.Client
+}
+
+// rubyGemsVersion is the part of the API document of a gem version that is needed.
+type rubyGemsVersion struct {
+	Licenses    []string `json:"licenses"`
+	HomepageURI string   `json:"homepage_uri"`
+}
+
+// Name returns "rubygems".
+func (s *RubyGems) Name() string {
+	return "rubygems"
+}
+
+// Lookup returns the licenses of a gem version, which all apply, and its homepage.
+func (s *RubyGems) Lookup(ctx context.Context, purl packageurl.PackageURL) (Metadata, error) {
+	var v rubyGemsVersion
+	endpoint := baseURL(s.BaseURL, DefaultRubyGemsURL) + "/api/v2/rubygems/" + url.PathEscape(purl.Name) +
+		"/versions/" + url.PathEscape(purl.Version) + ".json"
+	if err := getJSON(ctx, s.Client, endpoint, &v); err != nil {
+		return Metadata{}, err
+	}
+	return Metadata{License: attribution.JoinLicenses(v.Licenses), Homepage: v.HomepageURI}, nil
+}
@@ -0,0 +1,41 @@
+package enrich_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/boringbin/sbomattr/enrich"
+)
+
+// TestRubyGems_Lookup tests that the licenses of a gem version are joined and its homepage is looked up.
+func TestRubyGems_Lookup(t *testing.T) {
+	t.Parallel()
+
+	server := newJSONServer(t, map[string]string{
+		"/api/v2/rubygems/rails/versions/7.1.0.json": `{"licenses": ["MIT"], "homepage_uri": "https://rails.org"}`,
+		"/api/v2/rubygems/dual/versions/1.0.0.json":  `{"licenses": ["MIT", "GPL-2.0-only"]}`,
+	})
+	source := &enrich.RubyGems{BaseURL: server.URL}
+
+	tests := []struct {
+		purl string
+		want enrich.Metadata
+	}{
+		{purl: "pkg:gem/rails@7.1.0", want: enrich.Metadata{License: "MIT", Homepage: "https://rails.org"}},
+		{purl: "pkg:gem/dual@1.0.0", want: enrich.Metadata{License: "MIT AND GPL-2.0-only"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.purl, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := source.Lookup(context.Background(), mustPurl(t, tt.purl))
+			if err != nil {
+				t.Fatalf("Lookup() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Lookup() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package format
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// column is a selectable output column of the tabular formatters (CSV, TSV, and Markdown).
+type column struct {
+	// name is the identifier used to select the column, e.g. "license".
+	name string
+	// header is the column header, e.g. "License".
+	header string
+	// value returns the column value for an attribution.
+	value func(a attribution.Attribution) string
+	// notice, if set, returns the value written by notice-oriented formatters (Markdown) instead of value, e.g. a
+	// readable label instead of a special SPDX license value.
+	notice func(a attribution.Attribution) string
+}
+
+// noticeValue returns the column value for an attribution in notice-oriented output.
+func (c column) noticeValue(a attribution.Attribution) string {
+	if c.notice != nil {
+		return c.notice(a)
+	}
+	return c.value(a)
+}
+
+// allColumns returns every selectable column, in their canonical order.
+func allColumns() []column {
+	return []column{
+		{name: "name", header: "Name", value: func(a attribution.Attribution) string { return a.QualifiedName() }},
+		{
+			name:   "version",
+			header: "Version",
+			value:  func(a attribution.Attribution) string { return strings.Join(a.VersionList(), "; ") },
+		},
+		{
+			name:   "description",
+			header: "Description",
+			value:  func(a attribution.Attribution) string { return a.Description },
+		},
+		{name: "type", header: "Type", value: func(a attribution.Attribution) string { return a.Type }},
+		{name: "scope", header: "Scope", value: func(a attribution.Attribution) string { return a.Scope }},
+		{name: "supplier", header: "Supplier", value: func(a attribution.Attribution) string { return a.Supplier }},
+		{name: "author", header: "Author", value: func(a attribution.Attribution) string { return a.Author }},
+		{
+			name:   "first-party",
+			header: "First Party",
+			value:  func(a attribution.Attribution) string { return strconv.FormatBool(a.FirstParty) },
+		},
+		{
+			name:   "license",
+			header: "License",
+			value:  func(a attribution.Attribution) string { return deref(a.License) },
+			notice: licenseNotice,
+		},
+		{
+			name:   "declared-license",
+			header: "Declared License",
+			value:  func(a attribution.Attribution) string { return deref(a.LicenseDeclared) },
+		},
+		{
+			name:   "concluded-license",
+			header: "Concluded License",
+			value:  func(a attribution.Attribution) string { return deref(a.LicenseConcluded) },
+		},
+		{
+			name:   "license-url",
+			header: "License URL",
+			value:  func(a attribution.Attribution) string { return a.LicenseURL },
+		},
+		{
+			name:   "exception",
+			header: "Exception",
+			value:  func(a attribution.Attribution) string { return deref(a.Exception) },
+		},
+		{
+			name:   "license-category",
+			header: "License Category",
+			value:  func(a attribution.Attribution) string { return string(a.LicenseCategory()) },
+		},
+		{name: "purl", header: "Purl", value: func(a attribution.Attribution) string { return a.Purl }},
+		{name: "hashes", header: "Hashes", value: formatHashes},
+		{name: "cpe", header: "CPE", value: func(a attribution.Attribution) string { return a.CPE }},
+		{name: "url", header: "URL", value: func(a attribution.Attribution) string { return deref(a.URL) }},
+		{name: "urls", header: "URLs", value: formatURLs},
+		{
+			name:   "source-repo",
+			header: "Source Repository",
+			value:  func(a attribution.Attribution) string { return a.SourceRepo },
+		},
+		{
+			name:   "attribution-texts",
+			header: "Attribution Texts",
+			value:  func(a attribution.Attribution) string { return strings.Join(a.AttributionTexts, "\n") },
+		},
+		{
+			name:   "notes",
+			header: "Notes",
+			value:  func(a attribution.Attribution) string { return strings.Join(a.Notes, "; ") },
+		},
+		{
+			name:   "sources",
+			header: "Sources",
+			value:  func(a attribution.Attribution) string { return strings.Join(a.Sources, "; ") },
+		},
+	}
+}
+
+// ColumnNames returns the names of the columns that can be selected for tabular output.
+func ColumnNames() []string {
+	cols := allColumns()
+	names := make([]string, 0, len(cols))
+	for _, c := range cols {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// selectColumns returns the columns with the given names, in the given order.
+// Names are matched case-insensitively. Returns an error for unknown column names.
+func selectColumns(names []string) ([]column, error) {
+	cols := allColumns()
+	selected := make([]column, 0, len(names))
+
+	for _, name := range names {
+		found := false
+		for _, c := range cols {
+			if strings.EqualFold(c.name, strings.TrimSpace(name)) {
+				selected = append(selected, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown column %q (valid columns: %s)", name, strings.Join(ColumnNames(), ", "))
+		}
+	}
+
+	return selected, nil
+}
+
+// defaultColumns returns the columns used when none are selected: Name, License, Purl, URL.
+// The license detail columns are added when requested, and the Exception column when any attribution has a split
+// license exception. For notice-oriented formatters (Markdown), the Attribution Texts column is added when any
+// attribution carries attribution texts, since a notice must reproduce them; the data formats (CSV, TSV) keep a fixed
+// header, with the texts available through the attribution-texts column.
+func defaultColumns(attributions []attribution.Attribution, licenseDetails, notice bool) []column {
+	names := []string{"name", "license"}
+	if licenseDetails {
+		names = append(names, "declared-license", "concluded-license")
+	}
+	if hasException(attributions) {
+		names = append(names, "exception")
+	}
+	names = append(names, "purl", "url")
+	if notice &&
+		slices.ContainsFunc(attributions, func(a attribution.Attribution) bool { return len(a.AttributionTexts) > 0 }) {
+		names = append(names, "attribution-texts")
+	}
+
+	// All names are known, so this never fails
+	cols, _ := selectColumns(names)
+	return cols
+}
+
+// columnsFor returns the selected columns, or the default columns if none are selected (see defaultColumns).
+func columnsFor(attributions []attribution.Attribution, names []string, licenseDetails, notice bool) ([]column, error) {
+	if len(names) == 0 {
+		return defaultColumns(attributions, licenseDetails, notice), nil
+	}
+	return selectColumns(names)
+}
+
+// formatHashes formats the hashes of an attribution as "<algorithm>:<digest>" pairs sorted by algorithm and joined
+// with "; ".
+func formatHashes(a attribution.Attribution) string {
+	pairs := make([]string, 0, len(a.Hashes))
+	for _, algorithm := range slices.Sorted(maps.Keys(a.Hashes)) {
+		pairs = append(pairs, algorithm+":"+a.Hashes[algorithm])
+	}
+	return strings.Join(pairs, "; ")
+}
+
+// formatURLs formats the URLs of an attribution as "<kind>: <url>" pairs joined with "; ", with dead URLs suffixed
+// by " (dead)".
+func formatURLs(a attribution.Attribution) string {
+	pairs := make([]string, 0, len(a.URLs))
+	for _, u := range a.URLs {
+		pair := string(u.Kind) + ": " + u.URL
+		if u.Dead {
+			pair += " (dead)"
+		}
+		pairs = append(pairs, pair)
+	}
+	return strings.Join(pairs, "; ")
+}
+
+// licenseNotice returns the license of an attribution as shown in a notice, telling a package without a license
+// (NONE) apart from one whose license is unknown (missing or NOASSERTION).
+func licenseNotice(a attribution.Attribution) string {
+	switch a.LicenseStatus() {
+	case attribution.LicenseStatusNone:
+		return "None (no license granted)"
+	case attribution.LicenseStatusUnknown:
+		return "Unknown"
+	default:
+		return deref(a.License)
+	}
+}
+
+// hasException reports whether any attribution has a split license exception.
+func hasException(attributions []attribution.Attribution) bool {
+	for _, a := range attributions {
+		if a.Exception != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package format_test
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/format"
+)
+
+// TestColumnNames tests the ColumnNames function.
+func TestColumnNames(t *testing.T) {
+	t.Parallel()
+
+	names := format.ColumnNames()
+	for _, want := range []string{
+		"name", "version", "description", "license", "purl", "hashes", "cpe", "url", "source-repo", "notes",
+		"license-url", "author", "urls", "license-category",
+	} {
+		if !slices.Contains(names, want) {
+			t.Errorf("ColumnNames() = %v, missing %q", names, want)
+		}
+	}
+}
+
+// TestCSV_QualifiedName tests that the name column is qualified by the group.
+func TestCSV_QualifiedName(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{{Name: "commons-lang3", Group: "org.apache.commons"}}
+
+	var buf bytes.Buffer
+	if err := format.CSVWithOptions(&buf, input, format.CSVOptions{Columns: []string{"name"}}); err != nil {
+		t.Fatalf("CSVWithOptions() unexpected error: %v", err)
+	}
+	if want := "Name\norg.apache.commons/commons-lang3\n"; buf.String() != want {
+		t.Errorf("CSVWithOptions() = %q, want %q", buf.String(), want)
+	}
+}
+
+// TestCSV_AttributionTexts tests that the default CSV header does not depend on attribution texts, which are written
+// when the attribution-texts column is selected.
+func TestCSV_AttributionTexts(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "acme", AttributionTexts: []string{"Copyright Acme", "Portions by Example Corp."}},
+		{Name: "plain"},
+	}
+
+	var buf bytes.Buffer
+	if err := format.CSV(&buf, input); err != nil {
+		t.Fatalf("CSV() unexpected error: %v", err)
+	}
+	if want := "Name,License,Purl,URL\nacme,,,\nplain,,,\n"; buf.String() != want {
+		t.Errorf("CSV() = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	opts := format.CSVOptions{Columns: []string{"name", "attribution-texts"}}
+	if err := format.CSVWithOptions(&buf, input, opts); err != nil {
+		t.Fatalf("CSVWithOptions() unexpected error: %v", err)
+	}
+	want := "Name,Attribution Texts\n" +
+		"acme,\"Copyright Acme\nPortions by Example Corp.\"\n" +
+		"plain,\n"
+	if buf.String() != want {
+		t.Errorf("CSVWithOptions() = %q, want %q", buf.String(), want)
+	}
+}
+
+// TestCSVWithOptions_Versions tests that the version column lists every version merged by deduplication.
+func TestCSVWithOptions_Versions(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "lodash", Version: "4.17.20", Versions: []string{"4.17.20", "4.17.21"}},
+	}
+
+	var buf bytes.Buffer
+	if err := format.CSVWithOptions(&buf, input, format.CSVOptions{Columns: []string{"name", "version"}}); err != nil {
+		t.Fatalf("CSVWithOptions() error = %v", err)
+	}
+	if want := "Name,Version\nlodash,4.17.20; 4.17.21\n"; buf.String() != want {
+		t.Errorf("CSVWithOptions() = %q, want %q", buf.String(), want)
+	}
+}
+
+// TestCSVWithOptions_Columns tests the CSVWithOptions function with selected columns.
+func TestCSVWithOptions_Columns(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{
+			Name:      "llvm",
+			Version:   "17.0.0",
+			License:   strPtr("Apache-2.0"),
+			Exception: strPtr("LLVM-exception"),
+			Purl:      "pkg:github/llvm/llvm-project@17.0.0",
+			Hashes:    map[string]string{"SHA256": "abc123", "SHA1": "def456"},
+			URLs: []attribution.TypedURL{
+				{Kind: attribution.URLKindHomepage, URL: "https://llvm.org"},
+				{Kind: attribution.URLKindRegistry, URL: "https://github.com/llvm/llvm-project"},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name    string
+		columns []string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "reordered subset",
+			columns: []string{"purl", "Name", " version "},
+			want:    "Purl,Name,Version\npkg:github/llvm/llvm-project@17.0.0,llvm,17.0.0\n",
+		},
+		{
+			name:    "selected columns drop the automatic exception column",
+			columns: []string{"name", "license"},
+			want:    "Name,License\nllvm,Apache-2.0\n",
+		},
+		{
+			name:    "hashes sorted by algorithm",
+			columns: []string{"name", "hashes"},
+			want:    "Name,Hashes\nllvm,SHA1:def456; SHA256:abc123\n",
+		},
+		{
+			name:    "urls with their kind",
+			columns: []string{"name", "urls"},
+			want:    "Name,URLs\nllvm,homepage: https://llvm.org; registry: https://github.com/llvm/llvm-project\n",
+		},
+		{
+			name:    "license category",
+			columns: []string{"name", "license-category"},
+			want:    "Name,License Category\nllvm,permissive\n",
+		},
+		{
+			name:    "unknown column",
+			columns: []string{"name", "copyright"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			err := format.CSVWithOptions(&buf, input, format.CSVOptions{Columns: tc.columns})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("CSVWithOptions() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && buf.String() != tc.want {
+				t.Errorf("CSVWithOptions() = %q, want %q", buf.String(), tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package format
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// CycloneDXOptions configures the CycloneDX BOM written by CycloneDX.
+type CycloneDXOptions struct {
+	// SerialNumber is the BOM serial number URN. Defaults to a UUID URN derived from the attributions.
+	SerialNumber string
+	// ToolVersion is the version of sbomattr recorded in the BOM metadata. Defaults to "dev".
+	ToolVersion string
+	// Created is the BOM creation time. Defaults to the current time.
+	Created time.Time
+	// Graph, if set, records the dependencies between the attributions in the BOM dependencies. Edges with an end
+	// that is not one of the attributions are skipped.
+	Graph *attribution.Graph
+}
+
+// cdxBOM is a minimal CycloneDX 1.5 BOM.
+type cdxBOM struct {
+	BOMFormat    string          `json:"bomFormat"`
+	SpecVersion  string          `json:"specVersion"`
+	SerialNumber string          `json:"serialNumber"`
+	Version      int             `json:"version"`
+	Metadata     cdxMetadata     `json:"metadata"`
+	Components   []cdxComponent  `json:"components"`
+	Dependencies []cdxDependency `json:"dependencies,omitempty"`
+}
+
+// cdxDependency lists the components a component depends on.
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// cdxMetadata is the CycloneDX BOM metadata.
+type cdxMetadata struct {
+	Timestamp string   `json:"timestamp"`
+	Tools     cdxTools `json:"tools"`
+}
+
+// cdxTools lists the tools used to create the BOM.
+type cdxTools struct {
+	Components []cdxComponent `json:"components"`
+}
+
+// cdxComponent is a minimal CycloneDX component.
+type cdxComponent struct {
+	Type               string           `json:"type"`
+	Scope              string           `json:"scope,omitempty"`
+	BOMRef             string           `json:"bom-ref,omitempty"`
+	Group              string           `json:"group,omitempty"`
+	Name               string           `json:"name"`
+	Version            string           `json:"version,omitempty"`
+	Description        string           `json:"description,omitempty"`
+	Supplier           *cdxSupplier     `json:"supplier,omitempty"`
+	Author             string           `json:"author,omitempty"`
+	Purl               string           `json:"purl,omitempty"`
+	CPE                string           `json:"cpe,omitempty"`
+	Licenses           []cdxLicense     `json:"licenses,omitempty"`
+	ExternalReferences []cdxExternalRef `json:"externalReferences,omitempty"`
+	Hashes             []cdxHash        `json:"hashes,omitempty"`
+	Properties         []cdxProperty    `json:"properties,omitempty"`
+}
+
+// cdxSupplier is the organization that supplied a CycloneDX component.
+type cdxSupplier struct {
+	Name string `json:"name"`
+}
+
+// cdxHash is a CycloneDX component hash.
+type cdxHash struct {
+	Algorithm string `json:"alg"`
+	Content   string `json:"content"`
+}
+
+// cdxProperty is a CycloneDX name-value property.
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// cycloneDXPropertyPrefix namespaces the component properties recording sbomattr-derived fields.
+// cyclonedxextract reads the provenance properties back, so the BOM round-trips.
+const cycloneDXPropertyPrefix = "sbomattr:"
+
+// cdxLicense is a CycloneDX license choice: either a license or an expression.
+type cdxLicense struct {
+	License    *cdxLicenseID `json:"license,omitempty"`
+	Expression string        `json:"expression,omitempty"`
+}
+
+// cdxLicenseID identifies a license by SPDX ID or by name.
+type cdxLicenseID struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// cdxExternalRef is a CycloneDX external reference.
+type cdxExternalRef struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// CycloneDX writes attributions as a CycloneDX 1.5 JSON BOM to the provided io.Writer.
+// Each attribution becomes one library component.
+// Fields derived by sbomattr are recorded as component properties, so the enrichment is not lost when the BOM is read
+// by other tools: "sbomattr:license" (the normalized license), "sbomattr:url" (the resolved URL), and
+// "sbomattr:provenance:<field>" (which stage produced each field, e.g. "generated" or "heuristic").
+func CycloneDX(w io.Writer, attributions []attribution.Attribution, opts CycloneDXOptions) error {
+	if opts.SerialNumber == "" {
+		opts.SerialNumber = "urn:uuid:" + digestUUID(attributionsDigest(attributions))
+	}
+	if opts.ToolVersion == "" {
+		opts.ToolVersion = "dev"
+	}
+	if opts.Created.IsZero() {
+		opts.Created = time.Now()
+	}
+
+	bom := cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: opts.SerialNumber,
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp: opts.Created.UTC().Format(time.RFC3339),
+			Tools: cdxTools{
+				Components: []cdxComponent{{Type: "application", Name: "sbomattr", Version: opts.ToolVersion}},
+			},
+		},
+		Components: make([]cdxComponent, 0, len(attributions)),
+	}
+
+	refs := make(map[string]string, len(attributions))
+	for i, a := range attributions {
+		component := cdxComponent{
+			Type:        cmp.Or(a.Type, "library"),
+			Scope:       a.Scope,
+			BOMRef:      "component-" + strconv.Itoa(i+1),
+			Group:       a.Group,
+			Name:        a.Name,
+			Version:     a.Version,
+			Description: a.Description,
+			Author:      a.Author,
+			Purl:        a.Purl,
+			CPE:         a.CPE,
+		}
+		if a.Supplier != "" {
+			component.Supplier = &cdxSupplier{Name: a.Supplier}
+		}
+		// CycloneDX has no NONE or NOASSERTION: those stay in the sbomattr:license property only
+		if license := licenseExpression(a); a.LicenseStatus() == attribution.LicenseStatusKnown {
+			component.Licenses = []cdxLicense{cycloneDXLicense(license)}
+		}
+		component.ExternalReferences = cycloneDXExternalRefs(a)
+		for _, algorithm := range slices.Sorted(maps.Keys(a.Hashes)) {
+			component.Hashes = append(component.Hashes, cdxHash{
+				Algorithm: cycloneDXHashAlgorithm(algorithm),
+				Content:   a.Hashes[algorithm],
+			})
+		}
+		component.Properties = cycloneDXProperties(a)
+		bom.Components = append(bom.Components, component)
+		refs[attribution.DedupKey(a)] = component.BOMRef
+	}
+
+	for _, edge := range graphEdges(opts.Graph, refs) {
+		last := len(bom.Dependencies) - 1
+		if last < 0 || bom.Dependencies[last].Ref != refs[edge.From] {
+			bom.Dependencies = append(bom.Dependencies, cdxDependency{Ref: refs[edge.From]})
+			last++
+		}
+		bom.Dependencies[last].DependsOn = append(bom.Dependencies[last].DependsOn, refs[edge.To])
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(bom); err != nil {
+		return fmt.Errorf("encode CycloneDX: %w", err)
+	}
+	return nil
+}
+
+// cycloneDXExternalRefs returns the external references of an attribution: its primary URL as the website, the
+// download and documentation URLs that differ from it, the license URL, and the source repository.
+func cycloneDXExternalRefs(a attribution.Attribution) []cdxExternalRef {
+	var refs []cdxExternalRef
+	if a.URL != nil {
+		refs = append(refs, cdxExternalRef{Type: "website", URL: *a.URL})
+	}
+	for _, u := range a.URLs {
+		if a.URL != nil && u.URL == *a.URL {
+			continue
+		}
+		switch u.Kind {
+		case attribution.URLKindDownload:
+			refs = append(refs, cdxExternalRef{Type: "distribution", URL: u.URL})
+		case attribution.URLKindDocumentation:
+			refs = append(refs, cdxExternalRef{Type: "documentation", URL: u.URL})
+		}
+	}
+	if a.LicenseURL != "" {
+		refs = append(refs, cdxExternalRef{Type: "license", URL: a.LicenseURL})
+	}
+	if a.SourceRepo != "" {
+		refs = append(refs, cdxExternalRef{Type: "vcs", URL: a.SourceRepo})
+	}
+	return refs
+}
+
+// cycloneDXProperties returns the component properties recording the sbomattr-derived fields of an attribution.
+func cycloneDXProperties(a attribution.Attribution) []cdxProperty {
+	var properties []cdxProperty
+	if license := licenseExpression(a); license != "" {
+		properties = append(properties, cdxProperty{Name: cycloneDXPropertyPrefix + "license", Value: license})
+	}
+	if a.URL != nil {
+		properties = append(properties, cdxProperty{Name: cycloneDXPropertyPrefix + "url", Value: *a.URL})
+	}
+	for _, text := range a.AttributionTexts {
+		properties = append(properties, cdxProperty{Name: cycloneDXPropertyPrefix + "attributionText", Value: text})
+	}
+	for _, note := range a.Notes {
+		properties = append(properties, cdxProperty{Name: cycloneDXPropertyPrefix + "note", Value: note})
+	}
+	for _, field := range slices.Sorted(maps.Keys(a.Provenance)) {
+		properties = append(properties, cdxProperty{
+			Name:  cycloneDXPropertyPrefix + "provenance:" + field,
+			Value: string(a.Provenance[field]),
+		})
+	}
+	return properties
+}
+
+// cycloneDXHashAlgorithm converts a normalized hash algorithm name (see attribution.NormalizeHashAlgorithm) to the
+// CycloneDX spelling, e.g. "SHA256" to "SHA-256" and "BLAKE2B-256" to "BLAKE2b-256".
+func cycloneDXHashAlgorithm(algorithm string) string {
+	if rest, ok := strings.CutPrefix(algorithm, "SHA"); ok && !strings.HasPrefix(rest, "3") {
+		return "SHA-" + rest
+	}
+	return strings.Replace(algorithm, "BLAKE2B", "BLAKE2b", 1)
+}
+
+// cycloneDXLicense converts a license string to a CycloneDX license choice.
+// Identifiers on the SPDX license list become license IDs, since CycloneDX restricts IDs to that list, other valid
+// SPDX expressions (e.g. "GPL-2.0+" or "LicenseRef-Acme") become expressions, and anything else (e.g. "Custom") a
+// license name.
+func cycloneDXLicense(license string) cdxLicense {
+	switch {
+	case attribution.IsLicenseID(license):
+		return cdxLicense{License: &cdxLicenseID{ID: license}}
+	case isSPDXExpression(license):
+		return cdxLicense{Expression: license}
+	default:
+		return cdxLicense{License: &cdxLicenseID{Name: license}}
+	}
+}
+
+// digestUUID formats a hex digest (at least 32 characters) as a version 4 style UUID string.
+func digestUUID(digest string) string {
+	return digest[0:8] + "-" + digest[8:12] + "-4" + digest[13:16] + "-8" + digest[17:20] + "-" + digest[20:32]
+}
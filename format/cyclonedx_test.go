@@ -0,0 +1,386 @@
+package format_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/cyclonedxextract"
+	"github.com/boringbin/sbomattr/format"
+	"github.com/boringbin/sbomattr/internal/sbom"
+)
+
+// TestCycloneDX tests the CycloneDX function produces a BOM that round-trips through cyclonedxextract.
+func TestCycloneDX(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{
+			Name:        "lodash",
+			Description: "Lodash modular utilities.",
+			Hashes:      map[string]string{"SHA256": "abc123", "SHA1": "def456", "BLAKE2B-256": "fed789"},
+			License:     strPtr("MIT"),
+			Purl:        "pkg:npm/lodash@4.17.21",
+			URL:         strPtr("https://lodash.com"),
+		},
+		{
+			Name:    "custom",
+			License: strPtr("Custom License"),
+			CPE:     "cpe:2.3:a:example:custom:1.0:*:*:*:*:*:*:*",
+		},
+		{
+			Name: "unknown",
+		},
+	}
+
+	var buf bytes.Buffer
+	opts := format.CycloneDXOptions{ToolVersion: "v1.2.3", Created: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}
+	if err := format.CycloneDX(&buf, input, opts); err != nil {
+		t.Fatalf("CycloneDX() unexpected error: %v", err)
+	}
+
+	detected, err := sbom.DetectFormat(buf.Bytes())
+	if err != nil || detected != "cyclonedx" {
+		t.Fatalf("DetectFormat() = %q, %v, want cyclonedx", detected, err)
+	}
+
+	bom, err := cyclonedxextract.ParseSBOM(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ParseSBOM() unexpected error: %v", err)
+	}
+	if bom.SpecVersion != "1.5" {
+		t.Errorf("SpecVersion = %q, want 1.5", bom.SpecVersion)
+	}
+
+	got := cyclonedxextract.ExtractPackages(bom)
+	if len(got) != len(input) {
+		t.Fatalf("ExtractPackages() length = %d, want %d", len(got), len(input))
+	}
+	if got[0].Purl != "pkg:npm/lodash@4.17.21" || *got[0].License != "MIT" || *got[0].URL != "https://lodash.com" {
+		t.Errorf("round-tripped component[0] = %+v", got[0])
+	}
+	if want := map[string]string{"SHA256": "abc123", "SHA1": "def456", "BLAKE2B-256": "fed789"}; !maps.Equal(
+		got[0].Hashes, want) {
+		t.Errorf("round-tripped component[0].Hashes = %v, want %v", got[0].Hashes, want)
+	}
+	if !strings.Contains(buf.String(), `"alg": "SHA-256"`) || !strings.Contains(buf.String(), `"alg": "BLAKE2b-256"`) {
+		t.Errorf("CycloneDX() should use CycloneDX hash algorithm names, got: %s", buf.String())
+	}
+	if got[0].Description != "Lodash modular utilities." {
+		t.Errorf("round-tripped component[0].Description = %q, want the description", got[0].Description)
+	}
+	if got[1].CPE != "cpe:2.3:a:example:custom:1.0:*:*:*:*:*:*:*" {
+		t.Errorf("round-tripped component[1].CPE = %q, want the CPE", got[1].CPE)
+	}
+	if got[1].License == nil || *got[1].License != "Custom License" {
+		t.Errorf("round-tripped component[1].License = %v, want license name", got[1].License)
+	}
+	if got[2].License != nil {
+		t.Errorf("round-tripped component[2].License = %q, want nil", *got[2].License)
+	}
+
+	var raw map[string]any
+	if unmarshalErr := json.Unmarshal(buf.Bytes(), &raw); unmarshalErr != nil {
+		t.Fatalf("failed to unmarshal CycloneDX output: %v", unmarshalErr)
+	}
+	serial, _ := raw["serialNumber"].(string)
+	if !strings.HasPrefix(serial, "urn:uuid:") || len(serial) != len("urn:uuid:")+36 {
+		t.Errorf("serialNumber = %q, want a UUID URN", serial)
+	}
+	metadata, _ := raw["metadata"].(map[string]any)
+	if metadata["timestamp"] != "2025-01-02T03:04:05Z" {
+		t.Errorf("metadata.timestamp = %v, want 2025-01-02T03:04:05Z", metadata["timestamp"])
+	}
+}
+
+// TestCycloneDX_Version tests that component versions are written, so the BOM can be matched against vulnerability
+// data, e.g. in Dependency-Track.
+func TestCycloneDX_Version(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "express", Version: "4.18.2", Purl: "pkg:npm/express@4.18.2"},
+		{Name: "internal-tool"},
+	}
+
+	var buf bytes.Buffer
+	if err := format.CycloneDX(&buf, input, format.CycloneDXOptions{}); err != nil {
+		t.Fatalf("CycloneDX() unexpected error: %v", err)
+	}
+
+	var bom struct {
+		Components []struct {
+			Name    string  `json:"name"`
+			Version *string `json:"version"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &bom); err != nil {
+		t.Fatalf("failed to unmarshal CycloneDX output: %v", err)
+	}
+	if len(bom.Components) != 2 {
+		t.Fatalf("components = %+v, want 2", bom.Components)
+	}
+	if v := bom.Components[0].Version; v == nil || *v != "4.18.2" {
+		t.Errorf("components[0].version = %v, want 4.18.2", v)
+	}
+	if v := bom.Components[1].Version; v != nil {
+		t.Errorf("components[1].version = %q, want it omitted", *v)
+	}
+
+	parsed, err := cyclonedxextract.ParseSBOM(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ParseSBOM() unexpected error: %v", err)
+	}
+	if got := cyclonedxextract.ExtractPackages(parsed); got[0].Version != "4.18.2" {
+		t.Errorf("round-tripped component[0].Version = %q, want 4.18.2", got[0].Version)
+	}
+}
+
+// TestCycloneDX_Expression tests that compound license expressions are written as CycloneDX expressions.
+func TestCycloneDX_Expression(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{{Name: "dual", License: strPtr("MIT OR Apache-2.0")}}
+
+	var buf bytes.Buffer
+	if err := format.CycloneDX(&buf, input, format.CycloneDXOptions{}); err != nil {
+		t.Fatalf("CycloneDX() unexpected error: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), `"expression": "MIT OR Apache-2.0"`) {
+		t.Errorf("CycloneDX() output should contain license expression, got: %s", buf.String())
+	}
+}
+
+// TestCycloneDX_License tests that only identifiers on the SPDX license list are written as license IDs, and that
+// licenses that are not valid SPDX expressions are written as license names.
+func TestCycloneDX_License(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		license string
+		want    string
+	}{
+		{license: "MIT", want: `{"license":{"id":"MIT"}}`},
+		{license: "GPL-2.0+", want: `{"expression":"GPL-2.0+"}`},
+		{license: "LicenseRef-Acme", want: `{"expression":"LicenseRef-Acme"}`},
+		{license: "Custom", want: `{"license":{"name":"Custom"}}`},
+		{license: "GPL2", want: `{"license":{"name":"GPL2"}}`},
+		{license: "MIT OR Custom", want: `{"license":{"name":"MIT OR Custom"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.license, func(t *testing.T) {
+			t.Parallel()
+
+			input := []attribution.Attribution{{Name: "pkg", License: strPtr(tt.license)}}
+			var buf bytes.Buffer
+			if err := format.CycloneDX(&buf, input, format.CycloneDXOptions{}); err != nil {
+				t.Fatalf("CycloneDX() unexpected error: %v", err)
+			}
+
+			var bom struct {
+				Components []struct {
+					Licenses []json.RawMessage `json:"licenses"`
+				} `json:"components"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &bom); err != nil {
+				t.Fatalf("failed to unmarshal CycloneDX output: %v", err)
+			}
+			if len(bom.Components) != 1 || len(bom.Components[0].Licenses) != 1 {
+				t.Fatalf("CycloneDX() components = %+v, want one component with one license", bom.Components)
+			}
+			var got bytes.Buffer
+			if err := json.Compact(&got, bom.Components[0].Licenses[0]); err != nil || got.String() != tt.want {
+				t.Errorf("CycloneDX() license = %s, want %s", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+// TestCycloneDX_Properties tests that sbomattr-derived fields are recorded as properties and restored when read back.
+func TestCycloneDX_Properties(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		attribution.Attribution{
+			Name:             "classpath",
+			License:          strPtr("GPL-2.0-only"),
+			Exception:        strPtr("Classpath-exception-2.0"),
+			Purl:             "pkg:maven/org.example/classpath@1.0.0",
+			URL:              strPtr("https://central.sonatype.com/artifact/org.example/classpath/1.0.0"),
+			Notes:            []string{"CDXA claim: Distributed under the GPL."},
+			AttributionTexts: []string{"Copyright (c) Example Corp."},
+		}.WithProvenance(attribution.FieldLicense, attribution.ProvenanceHeuristic).
+			WithProvenance(attribution.FieldURL, attribution.ProvenanceGenerated),
+		{Name: "bare"},
+	}
+
+	var buf bytes.Buffer
+	if err := format.CycloneDX(&buf, input, format.CycloneDXOptions{}); err != nil {
+		t.Fatalf("CycloneDX() unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		`"name": "sbomattr:license",`,
+		`"value": "GPL-2.0-only WITH Classpath-exception-2.0"`,
+		`"name": "sbomattr:provenance:license",`,
+		`"value": "heuristic"`,
+		`"name": "sbomattr:provenance:url",`,
+		`"name": "sbomattr:note",`,
+		`"name": "sbomattr:attributionText",`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("CycloneDX() output missing %s, got: %s", want, buf.String())
+		}
+	}
+
+	bom, err := cyclonedxextract.ParseSBOM(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ParseSBOM() unexpected error: %v", err)
+	}
+	if len(bom.Components[1].Properties) != 0 {
+		t.Errorf("component without derived fields has properties %v", bom.Components[1].Properties)
+	}
+
+	got := cyclonedxextract.ExtractPackages(bom)[0]
+	if got.Provenance[attribution.FieldLicense] != attribution.ProvenanceHeuristic ||
+		got.Provenance[attribution.FieldURL] != attribution.ProvenanceGenerated {
+		t.Errorf("round-tripped provenance = %v, want heuristic license and generated URL", got.Provenance)
+	}
+	if got.License == nil || *got.License != "GPL-2.0-only WITH Classpath-exception-2.0" {
+		t.Errorf("round-tripped license = %v, want the normalized expression", got.License)
+	}
+	if want := []string{"CDXA claim: Distributed under the GPL."}; !slices.Equal(got.Notes, want) {
+		t.Errorf("round-tripped notes = %v, want %v", got.Notes, want)
+	}
+	if want := []string{"Copyright (c) Example Corp."}; !slices.Equal(got.AttributionTexts, want) {
+		t.Errorf("round-tripped attribution texts = %v, want %v", got.AttributionTexts, want)
+	}
+}
+
+// TestCycloneDX_SpecialLicenses tests that NONE and NOASSERTION are not written as licenses, but still round-trip.
+func TestCycloneDX_SpecialLicenses(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "proprietary", License: strPtr("NONE")},
+		{Name: "mystery", License: strPtr("NOASSERTION")},
+	}
+
+	var buf bytes.Buffer
+	if err := format.CycloneDX(&buf, input, format.CycloneDXOptions{}); err != nil {
+		t.Fatalf("CycloneDX() unexpected error: %v", err)
+	}
+
+	bom, err := cyclonedxextract.ParseSBOM(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ParseSBOM() unexpected error: %v", err)
+	}
+	for i, component := range bom.Components {
+		if component.Licenses != nil {
+			t.Errorf("component %d licenses = %+v, want none", i, component.Licenses)
+		}
+	}
+	for i, a := range cyclonedxextract.ExtractPackages(bom) {
+		if a.LicenseStatus() != input[i].LicenseStatus() {
+			t.Errorf("round-tripped %s license status = %q, want %q", a.Name, a.LicenseStatus(),
+				input[i].LicenseStatus())
+		}
+	}
+}
+
+// TestCycloneDX_Group tests that the component group, supplier, type, and scope are written and read back.
+func TestCycloneDX_Group(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{
+			Name:       "core",
+			Group:      "@angular",
+			Supplier:   "Google",
+			Author:     "Angular Team",
+			Purl:       "pkg:npm/%40angular/core@17.0.0",
+			SourceRepo: "https://github.com/angular/angular",
+			LicenseURL: "https://github.com/angular/angular/blob/main/LICENSE",
+			URL:        strPtr("https://angular.dev"),
+			URLs: []attribution.TypedURL{
+				{Kind: attribution.URLKindHomepage, URL: "https://angular.dev"},
+				{Kind: attribution.URLKindDocumentation, URL: "https://angular.dev/overview"},
+				{Kind: attribution.URLKindDownload, URL: "https://registry.npmjs.org/@angular/core/-/core-17.0.0.tgz"},
+			},
+		},
+		{Name: "alpine", Type: "operating-system", Scope: "optional"},
+	}
+
+	var buf bytes.Buffer
+	if err := format.CycloneDX(&buf, input, format.CycloneDXOptions{}); err != nil {
+		t.Fatalf("CycloneDX() unexpected error: %v", err)
+	}
+
+	bom, err := cyclonedxextract.ParseSBOM(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ParseSBOM() unexpected error: %v", err)
+	}
+	if got := cyclonedxextract.ExtractPackages(bom)[1]; got.Type != "operating-system" || got.Scope != "optional" {
+		t.Errorf("round-tripped type/scope = %q/%q, want operating-system/optional", got.Type, got.Scope)
+	}
+	if bom.Components[0].Type != "library" {
+		t.Errorf("component without a type = %q, want library", bom.Components[0].Type)
+	}
+	if got := cyclonedxextract.ExtractPackages(bom)[0]; got.Group != "@angular" || got.Name != "core" {
+		t.Errorf("round-tripped group/name = %q/%q, want @angular/core", got.Group, got.Name)
+	} else if got.Supplier != "Google" || got.Author != "Angular Team" {
+		t.Errorf("round-tripped supplier/author = %q/%q, want Google/Angular Team", got.Supplier, got.Author)
+	} else if got.SourceRepo != "https://github.com/angular/angular" {
+		t.Errorf("round-tripped source repository = %q, want https://github.com/angular/angular", got.SourceRepo)
+	} else if got.LicenseURL != "https://github.com/angular/angular/blob/main/LICENSE" {
+		t.Errorf("round-tripped license URL = %q, want the license reference", got.LicenseURL)
+	} else if got.URLOf(attribution.URLKindDocumentation) != "https://angular.dev/overview" ||
+		got.URLOf(attribution.URLKindDownload) != "https://registry.npmjs.org/@angular/core/-/core-17.0.0.tgz" {
+		t.Errorf("round-tripped URLs = %v, want the documentation and download URLs", got.URLs)
+	}
+}
+
+// TestCycloneDX_Graph tests that the dependencies between the attributions are written as BOM dependencies that
+// cyclonedxextract reads back.
+func TestCycloneDX_Graph(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "express", Purl: "pkg:npm/express@4.18.2"},
+		{Name: "debug", Purl: "pkg:npm/debug@2.6.9"},
+		{Name: "ms", Purl: "pkg:npm/ms@2.0.0"},
+	}
+	want := []attribution.Edge{
+		{From: "pkg:npm/debug@2.6.9", To: "pkg:npm/ms@2.0.0"},
+		{From: "pkg:npm/express@4.18.2", To: "pkg:npm/debug@2.6.9"},
+		{From: "pkg:npm/express@4.18.2", To: "pkg:npm/ms@2.0.0"},
+	}
+
+	var buf bytes.Buffer
+	opts := format.CycloneDXOptions{Graph: &attribution.Graph{Edges: want}}
+	if err := format.CycloneDX(&buf, input, opts); err != nil {
+		t.Fatalf("CycloneDX() unexpected error: %v", err)
+	}
+
+	bom, err := cyclonedxextract.ParseSBOM(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ParseSBOM() unexpected error: %v", err)
+	}
+	got := cyclonedxextract.Dependencies(bom)
+	slices.SortFunc(got, func(a, b attribution.Edge) int {
+		return strings.Compare(a.From+" "+a.To, b.From+" "+b.To)
+	})
+	if !slices.Equal(got, want) {
+		t.Errorf("Dependencies() = %v, want %v", got, want)
+	}
+	if len(bom.Dependencies) != 2 {
+		t.Errorf("dependencies = %+v, want one entry per dependent component", bom.Dependencies)
+	}
+}
@@ -0,0 +1,63 @@
+package format
+
+import (
+	"slices"
+)
+
+// Feature is an optional feature of an output format, such as selectable columns or grouping.
+type Feature string
+
+const (
+	// FeatureColumns selects the output columns (see ColumnNames).
+	FeatureColumns Feature = "columns"
+	// FeatureLicenseDetails adds the declared and concluded license columns.
+	FeatureLicenseDetails Feature = "license-details"
+	// FeatureGroupBySource groups the output by the SBOM file the attributions were found in.
+	FeatureGroupBySource Feature = "group-by-source"
+	// FeatureDelimiter selects the field delimiter.
+	FeatureDelimiter Feature = "delimiter"
+	// FeatureCRLF terminates lines with \r\n.
+	FeatureCRLF Feature = "crlf"
+	// FeatureGraph embeds the dependency graph between the attributions.
+	FeatureGraph Feature = "graph"
+	// FeatureMetadata records the run metadata (tool version, timestamp, inputs, and options) in the output.
+	FeatureMetadata Feature = "metadata"
+)
+
+// Features returns the optional features the named output format supports, so callers can reject option
+// combinations up front instead of producing silently degraded output. Formatters registered with Register take no
+// options, so they support none.
+func Features(name string) []Feature {
+	switch name {
+	case "csv":
+		return []Feature{
+			FeatureColumns, FeatureLicenseDetails, FeatureGroupBySource, FeatureDelimiter, FeatureCRLF,
+		}
+	case "tsv":
+		return []Feature{FeatureColumns, FeatureLicenseDetails, FeatureGroupBySource, FeatureCRLF}
+	case "markdown":
+		return []Feature{FeatureColumns, FeatureGroupBySource}
+	case "json":
+		return []Feature{FeatureGroupBySource, FeatureGraph, FeatureMetadata}
+	case "spdx", "spdx-lite", "cyclonedx":
+		return []Feature{FeatureGraph}
+	default:
+		return nil
+	}
+}
+
+// Supports reports whether the named output format supports a feature.
+func Supports(name string, feature Feature) bool {
+	return slices.Contains(Features(name), feature)
+}
+
+// FormatsSupporting returns the names of the registered output formats that support a feature, sorted.
+func FormatsSupporting(feature Feature) []string {
+	var names []string
+	for _, name := range Names() {
+		if Supports(name, feature) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
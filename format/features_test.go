@@ -0,0 +1,58 @@
+package format_test
+
+import (
+	"crypto/rand"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/format"
+)
+
+// TestSupports tests the Supports function.
+func TestSupports(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		feature format.Feature
+		want    bool
+	}{
+		{name: "csv", feature: format.FeatureDelimiter, want: true},
+		{name: "tsv", feature: format.FeatureDelimiter, want: false},
+		{name: "tsv", feature: format.FeatureCRLF, want: true},
+		{name: "markdown", feature: format.FeatureColumns, want: true},
+		{name: "markdown", feature: format.FeatureLicenseDetails, want: false},
+		{name: "json", feature: format.FeatureGroupBySource, want: true},
+		{name: "json", feature: format.FeatureColumns, want: false},
+		{name: "json", feature: format.FeatureGraph, want: true},
+		{name: "json", feature: format.FeatureMetadata, want: true},
+		{name: "ndjson", feature: format.FeatureMetadata, want: false},
+		{name: "cyclonedx", feature: format.FeatureGroupBySource, want: false},
+		{name: "cyclonedx", feature: format.FeatureGraph, want: true},
+		{name: "spdx-lite", feature: format.FeatureGraph, want: true},
+		{name: "unknown", feature: format.FeatureColumns, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := format.Supports(tt.name, tt.feature); got != tt.want {
+			t.Errorf("Supports(%q, %q) = %v, want %v", tt.name, tt.feature, got, tt.want)
+		}
+	}
+}
+
+// TestFormatsSupporting tests that FormatsSupporting lists built-in formats only, since registered formatters take
+// no options.
+func TestFormatsSupporting(t *testing.T) {
+	t.Parallel()
+
+	// The registry is global, so the name is unique per run for the test to pass with -count
+	noop := format.FormatterFunc(func(io.Writer, []attribution.Attribution) error { return nil })
+	format.Register("test-features-"+rand.Text(), noop)
+
+	got := format.FormatsSupporting(format.FeatureGroupBySource)
+	if want := []string{"csv", "json", "markdown", "tsv"}; !slices.Equal(got, want) {
+		t.Errorf("FormatsSupporting(%q) = %v, want %v", format.FeatureGroupBySource, got, want)
+	}
+}
@@ -9,38 +9,87 @@ import (
 	"github.com/boringbin/sbomattr/attribution"
 )
 
+// CSVOptions configures the CSV output written by CSVWithOptions.
+type CSVOptions struct {
+	// Delimiter is the field delimiter. Defaults to ','. Use '\t' for TSV.
+	Delimiter rune
+	// UseCRLF terminates lines with \r\n instead of \n.
+	UseCRLF bool
+	// LicenseDetails adds "Declared License" and "Concluded License" columns after License.
+	// Ignored if Columns is set.
+	LicenseDetails bool
+	// Columns selects the output columns by name, in order (see ColumnNames). Defaults to Name, License, Purl, URL.
+	Columns []string
+	// GroupBySource adds a leading Source column and writes the attributions grouped by the SBOM file they were found
+	// in (see attribution.GroupBySource), so an attribution found in several files is written once per file.
+	GroupBySource bool
+}
+
 // CSV writes attributions as CSV to the provided io.Writer.
 // The CSV has columns: Name, License, Purl, URL.
+// If any attribution has a split license exception, an Exception column is added after License.
 func CSV(w io.Writer, attributions []attribution.Attribution) error {
+	return CSVWithOptions(w, attributions, CSVOptions{})
+}
+
+// TSV writes attributions as tab-separated values to the provided io.Writer, with the same columns as CSV.
+func TSV(w io.Writer, attributions []attribution.Attribution) error {
+	return CSVWithOptions(w, attributions, CSVOptions{Delimiter: '\t'})
+}
+
+// CSVWithOptions writes attributions as CSV to the provided io.Writer, using the given options.
+// Returns an error if the delimiter is invalid (e.g. a quote, newline, or the Unicode replacement character).
+func CSVWithOptions(w io.Writer, attributions []attribution.Attribution, opts CSVOptions) error {
 	writer := csv.NewWriter(w)
+	if opts.Delimiter != 0 {
+		writer.Comma = opts.Delimiter
+	}
+	writer.UseCRLF = opts.UseCRLF
 	defer writer.Flush()
 
+	cols, err := columnsFor(attributions, opts.Columns, opts.LicenseDetails, false)
+	if err != nil {
+		return err
+	}
+
 	// Write header
-	if err := writer.Write([]string{"Name", "License", "Purl", "URL"}); err != nil {
-		return fmt.Errorf("write CSV header: %w", err)
+	header := make([]string, 0, len(cols)+1)
+	if opts.GroupBySource {
+		header = append(header, "Source")
+	}
+	for _, c := range cols {
+		header = append(header, c.header)
+	}
+	if writeErr := writer.Write(header); writeErr != nil {
+		return fmt.Errorf("write CSV header: %w", writeErr)
 	}
 
 	// Write rows
-	for _, a := range attributions {
-		license := ""
-		if a.License != nil {
-			license = *a.License
-		}
-
-		url := ""
-		if a.URL != nil {
-			url = *a.URL
-		}
+	groups := []attribution.SourceGroup{{Attributions: attributions}}
+	if opts.GroupBySource {
+		groups = attribution.GroupBySource(attributions)
+	}
+	for _, g := range groups {
+		for _, a := range g.Attributions {
+			row := make([]string, 0, len(header))
+			if opts.GroupBySource {
+				row = append(row, g.Source)
+			}
+			for _, c := range cols {
+				row = append(row, c.value(a))
+			}
 
-		if err := writer.Write([]string{a.Name, license, a.Purl, url}); err != nil {
-			return fmt.Errorf("write CSV row: %w", err)
+			if writeErr := writer.Write(row); writeErr != nil {
+				return fmt.Errorf("write CSV row: %w", writeErr)
+			}
 		}
 	}
 
 	return nil
 }
 
-// JSON writes attributions as pretty-printed JSON to the provided io.Writer.
+// JSON writes attributions as a bare pretty-printed JSON array to the provided io.Writer (see JSONDocument for output
+// with run metadata).
 func JSON(w io.Writer, attributions []attribution.Attribution) error {
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
@@ -49,3 +98,85 @@ func JSON(w io.Writer, attributions []attribution.Attribution) error {
 	}
 	return nil
 }
+
+// JSONBySource writes attributions grouped by the SBOM file they were found in (see attribution.GroupBySource) as
+// pretty-printed JSON to the provided io.Writer.
+func JSONBySource(w io.Writer, attributions []attribution.Attribution) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(attribution.GroupBySource(attributions)); err != nil {
+		return fmt.Errorf("encode JSON: %w", err)
+	}
+	return nil
+}
+
+// Metadata describes the run that produced a JSON document, so consumers receive the attributions with their
+// context.
+type Metadata struct {
+	// Tool is the name of the tool that wrote the document.
+	Tool string `json:"tool"`
+	// Version is the version of the tool.
+	Version string `json:"version"`
+	// Timestamp is when the document was written, in RFC 3339 format. Empty in reproducible output.
+	Timestamp string `json:"timestamp,omitempty"`
+	// Inputs is the number of inputs processed (SBOM files, lockfiles, or repositories).
+	Inputs int `json:"inputs"`
+	// Options lists the options the run was invoked with, by name.
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// Document is the JSON output written by JSONDocument: the run metadata and the attributions, either as a list or
+// grouped by source.
+type Document struct {
+	// Metadata describes the run.
+	Metadata Metadata `json:"metadata"`
+	// Attributions are the attributions, unless they are grouped by source.
+	Attributions []attribution.Attribution `json:"attributions,omitempty"`
+	// Groups are the attributions grouped by the SBOM file they were found in, if selected.
+	Groups []attribution.SourceGroup `json:"groups,omitempty"`
+	// Graph is the dependency graph between the attributions, if selected.
+	Graph *attribution.Graph `json:"graph,omitempty"`
+}
+
+// JSONOptions configures the JSON document written by JSONDocument.
+type JSONOptions struct {
+	// Metadata describes the run.
+	Metadata Metadata
+	// GroupBySource writes the attributions grouped by the SBOM file they were found in (see
+	// attribution.GroupBySource) instead of as a list.
+	GroupBySource bool
+	// Graph, if set, embeds the dependency graph between the attributions (see attribution.Graph.Restrict), so
+	// visualization tools can render dependency trees annotated with licenses.
+	Graph *attribution.Graph
+}
+
+// JSONDocument writes attributions wrapped in a document with the run metadata as a pretty-printed JSON object to the
+// provided io.Writer. Unlike JSON and JSONBySource, which write bare lists, the output tells consumers which tool,
+// version, inputs, and options produced it.
+func JSONDocument(w io.Writer, attributions []attribution.Attribution, opts JSONOptions) error {
+	doc := Document{Metadata: opts.Metadata}
+	if opts.GroupBySource {
+		doc.Groups = attribution.GroupBySource(attributions)
+	} else {
+		doc.Attributions = attributions
+	}
+	if opts.Graph != nil {
+		graph := opts.Graph.Restrict(attributions)
+		doc.Graph = &graph
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("encode JSON: %w", err)
+	}
+	return nil
+}
+
+// deref returns the value of a string pointer, or an empty string if it is nil.
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -2,6 +2,7 @@ package format_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"strings"
 	"testing"
@@ -63,6 +64,25 @@ func TestCSV(t *testing.T) {
 			want: "Name,License,Purl,URL\n" +
 				"\"package, with, commas\",MIT,pkg:npm/package-with-commas@1.0.0,\n",
 		},
+		{
+			name: "attribution with split license exception",
+			input: []attribution.Attribution{
+				{
+					Name:      "llvm",
+					License:   strPtr("Apache-2.0"),
+					Exception: strPtr("LLVM-exception"),
+					Purl:      "pkg:github/llvm/llvm-project@17.0.0",
+				},
+				{
+					Name:    "test-package",
+					License: strPtr("MIT"),
+					Purl:    "pkg:npm/test-package@1.0.0",
+				},
+			},
+			want: "Name,License,Exception,Purl,URL\n" +
+				"llvm,Apache-2.0,LLVM-exception,pkg:github/llvm/llvm-project@17.0.0,\n" +
+				"test-package,MIT,,pkg:npm/test-package@1.0.0,\n",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -82,6 +102,129 @@ func TestCSV(t *testing.T) {
 	}
 }
 
+// TestCSVWithOptions_LicenseDetails tests the CSVWithOptions function with license detail columns.
+func TestCSVWithOptions_LicenseDetails(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{
+			Name:             "test-package",
+			License:          strPtr("MIT"),
+			LicenseDeclared:  strPtr("Apache-2.0"),
+			LicenseConcluded: strPtr("MIT"),
+			Purl:             "pkg:npm/test-package@1.0.0",
+		},
+	}
+
+	var buf bytes.Buffer
+	err := format.CSVWithOptions(&buf, input, format.CSVOptions{LicenseDetails: true})
+	if err != nil {
+		t.Fatalf("CSVWithOptions() unexpected error: %v", err)
+	}
+
+	want := "Name,License,Declared License,Concluded License,Purl,URL\n" +
+		"test-package,MIT,Apache-2.0,MIT,pkg:npm/test-package@1.0.0,\n"
+	if buf.String() != want {
+		t.Errorf("CSVWithOptions() = %q, want %q", buf.String(), want)
+	}
+}
+
+// TestCSVWithOptions_GroupBySource tests the CSVWithOptions function grouping attributions by source file.
+func TestCSVWithOptions_GroupBySource(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "react", License: strPtr("MIT"), Purl: "pkg:npm/react@18.2.0", Sources: []string{"web.json"}},
+		{
+			Name:    "lodash",
+			License: strPtr("MIT"),
+			Purl:    "pkg:npm/lodash@4.17.21",
+			Sources: []string{"web.json", "api.json"},
+		},
+	}
+
+	var buf bytes.Buffer
+	opts := format.CSVOptions{GroupBySource: true, Columns: []string{"name", "license"}}
+	if err := format.CSVWithOptions(&buf, input, opts); err != nil {
+		t.Fatalf("CSVWithOptions() unexpected error: %v", err)
+	}
+
+	want := "Source,Name,License\n" +
+		"api.json,lodash,MIT\n" +
+		"web.json,react,MIT\n" +
+		"web.json,lodash,MIT\n"
+	if buf.String() != want {
+		t.Errorf("CSVWithOptions() = %q, want %q", buf.String(), want)
+	}
+}
+
+// TestCSVWithOptions_Delimiter tests the CSVWithOptions function with custom delimiters and line endings.
+func TestCSVWithOptions_Delimiter(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "pkg; with; semicolons", License: strPtr("MIT"), Purl: "pkg:npm/pkg@1.0.0"},
+	}
+
+	testCases := []struct {
+		name string
+		opts format.CSVOptions
+		want string
+	}{
+		{
+			name: "semicolon",
+			opts: format.CSVOptions{Delimiter: ';'},
+			want: "Name;License;Purl;URL\n\"pkg; with; semicolons\";MIT;pkg:npm/pkg@1.0.0;\n",
+		},
+		{
+			name: "tab with CRLF",
+			opts: format.CSVOptions{Delimiter: '\t', UseCRLF: true},
+			want: "Name\tLicense\tPurl\tURL\r\npkg; with; semicolons\tMIT\tpkg:npm/pkg@1.0.0\t\r\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := format.CSVWithOptions(&buf, input, tc.opts); err != nil {
+				t.Fatalf("CSVWithOptions() unexpected error: %v", err)
+			}
+			if buf.String() != tc.want {
+				t.Errorf("CSVWithOptions() = %q, want %q", buf.String(), tc.want)
+			}
+		})
+	}
+}
+
+// TestCSVWithOptions_InvalidDelimiter tests the CSVWithOptions function rejects invalid delimiters.
+func TestCSVWithOptions_InvalidDelimiter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := format.CSVWithOptions(&buf, nil, format.CSVOptions{Delimiter: '"'}); err == nil {
+		t.Error("CSVWithOptions() with quote delimiter should return error")
+	}
+}
+
+// TestTSV tests the TSV function.
+func TestTSV(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{{Name: "pkg", License: strPtr("MIT"), Purl: "pkg:npm/pkg@1.0.0"}}
+
+	var buf bytes.Buffer
+	if err := format.TSV(&buf, input); err != nil {
+		t.Fatalf("TSV() unexpected error: %v", err)
+	}
+
+	want := "Name\tLicense\tPurl\tURL\npkg\tMIT\tpkg:npm/pkg@1.0.0\t\n"
+	if buf.String() != want {
+		t.Errorf("TSV() = %q, want %q", buf.String(), want)
+	}
+}
+
 // TestJSON tests the JSON function.
 func TestJSON(t *testing.T) {
 	t.Parallel()
@@ -121,6 +264,106 @@ func (w *failingWriter) Write(_ []byte) (int, error) {
 	return 0, errors.New("mock write error")
 }
 
+// TestJSONBySource tests the JSONBySource function.
+func TestJSONBySource(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "react", Purl: "pkg:npm/react@18.2.0", Sources: []string{"web.json"}},
+		{Name: "express", Purl: "pkg:npm/express@4.18.2", Sources: []string{"api.json"}},
+	}
+
+	var buf bytes.Buffer
+	if err := format.JSONBySource(&buf, input); err != nil {
+		t.Fatalf("JSONBySource() unexpected error: %v", err)
+	}
+
+	var got []attribution.SourceGroup
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("JSONBySource() output is not valid JSON: %v", err)
+	}
+	if len(got) != 2 || got[0].Source != "api.json" || got[0].Attributions[0].Name != "express" {
+		t.Errorf("JSONBySource() = %+v, want api.json/express first", got)
+	}
+}
+
+// TestJSONDocument tests the JSONDocument function.
+func TestJSONDocument(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "express", Purl: "pkg:npm/express@4.18.2", Sources: []string{"api.json"}},
+		{Name: "body-parser", Purl: "pkg:npm/body-parser@1.20.1", Sources: []string{"api.json"}},
+	}
+	graph := attribution.Graph{Edges: []attribution.Edge{
+		{From: "pkg:npm/express@4.18.2", To: "pkg:npm/body-parser@1.20.1"},
+		{From: "pkg:npm/express@4.18.2", To: "pkg:npm/debug@2.6.9"},
+	}}
+	metadata := format.Metadata{
+		Tool:      "sbomattr",
+		Version:   "1.2.3",
+		Timestamp: "2024-01-02T03:04:05Z",
+		Inputs:    1,
+		Options:   map[string]string{"format": "json"},
+	}
+
+	tests := []struct {
+		name       string
+		opts       format.JSONOptions
+		wantAttrs  int
+		wantGroups int
+		wantEdges  int
+	}{
+		{name: "list", opts: format.JSONOptions{Metadata: metadata}, wantAttrs: 2},
+		{name: "grouped", opts: format.JSONOptions{Metadata: metadata, GroupBySource: true}, wantGroups: 1},
+		{name: "graph", opts: format.JSONOptions{Metadata: metadata, Graph: &graph}, wantAttrs: 2, wantEdges: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := format.JSONDocument(&buf, input, tt.opts); err != nil {
+				t.Fatalf("JSONDocument() unexpected error: %v", err)
+			}
+
+			var got format.Document
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("JSONDocument() output is not valid JSON: %v", err)
+			}
+			if got.Metadata.Tool != "sbomattr" || got.Metadata.Version != "1.2.3" || got.Metadata.Inputs != 1 ||
+				got.Metadata.Timestamp != metadata.Timestamp || got.Metadata.Options["format"] != "json" {
+				t.Errorf("JSONDocument() metadata = %+v, want %+v", got.Metadata, metadata)
+			}
+			if len(got.Attributions) != tt.wantAttrs || len(got.Groups) != tt.wantGroups {
+				t.Errorf("JSONDocument() = %d attributions, %d groups, want %d, %d", len(got.Attributions),
+					len(got.Groups), tt.wantAttrs, tt.wantGroups)
+			}
+			if (got.Graph != nil) != (tt.opts.Graph != nil) {
+				t.Fatalf("JSONDocument() graph = %+v, want it only if selected", got.Graph)
+			}
+			if got.Graph != nil && (len(got.Graph.Edges) != tt.wantEdges || got.Graph.Edges[0] != graph.Edges[0]) {
+				t.Errorf("JSONDocument() edges = %+v, want only the edge between written attributions", got.Graph.Edges)
+			}
+		})
+	}
+}
+
+// TestJSONDocument_Reproducible tests that a document without a timestamp omits it, so the output is stable.
+func TestJSONDocument_Reproducible(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	opts := format.JSONOptions{Metadata: format.Metadata{Tool: "sbomattr", Version: "dev"}}
+	if err := format.JSONDocument(&buf, nil, opts); err != nil {
+		t.Fatalf("JSONDocument() unexpected error: %v", err)
+	}
+	if strings.Contains(buf.String(), "timestamp") {
+		t.Errorf("JSONDocument() = %s, want no timestamp", buf.String())
+	}
+}
+
 // TestJSON_WriteError tests JSON error handling when writer fails.
 func TestJSON_WriteError(t *testing.T) {
 	t.Parallel()
@@ -147,3 +390,11 @@ func TestJSON_WriteError(t *testing.T) {
 func strPtr(s string) *string {
 	return &s
 }
+
+// strValue returns the string a pointer points to, or an empty string for nil.
+func strValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,87 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// MarkdownOptions configures the Markdown output written by Markdown.
+type MarkdownOptions struct {
+	// Columns selects the output columns by name, in order (see ColumnNames). Defaults to Name, License, Purl, URL, and
+	// Attribution Texts when any attribution carries them.
+	Columns []string
+	// GroupBySource writes one table per SBOM file the attributions were found in, each under a "## <file>" heading
+	// (see attribution.GroupBySource).
+	GroupBySource bool
+}
+
+// Markdown writes attributions as a Markdown table to the provided io.Writer.
+// Since the table is meant for notices, packages without a license (NONE) and packages whose license is unknown
+// (missing or NOASSERTION) are labeled as such in the License column.
+func Markdown(w io.Writer, attributions []attribution.Attribution, opts MarkdownOptions) error {
+	cols, err := columnsFor(attributions, opts.Columns, false, true)
+	if err != nil {
+		return err
+	}
+
+	if !opts.GroupBySource {
+		return markdownTable(w, attributions, cols)
+	}
+
+	for i, g := range attribution.GroupBySource(attributions) {
+		source := g.Source
+		if source == "" {
+			source = "(unknown source)"
+		}
+		separator := "\n"
+		if i == 0 {
+			separator = ""
+		}
+		if _, writeErr := fmt.Fprintf(w, "%s## %s\n\n", separator, source); writeErr != nil {
+			return fmt.Errorf("write Markdown heading: %w", writeErr)
+		}
+		if err = markdownTable(w, g.Attributions, cols); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// markdownTable writes attributions as a Markdown table with the given columns.
+func markdownTable(w io.Writer, attributions []attribution.Attribution, cols []column) error {
+	header := make([]string, 0, len(cols))
+	separator := make([]string, 0, len(cols))
+	for _, c := range cols {
+		header = append(header, c.header)
+		separator = append(separator, "---")
+	}
+	if _, writeErr := fmt.Fprintf(w, "%s\n%s\n", markdownRow(header), markdownRow(separator)); writeErr != nil {
+		return fmt.Errorf("write Markdown header: %w", writeErr)
+	}
+
+	for _, a := range attributions {
+		row := make([]string, 0, len(cols))
+		for _, c := range cols {
+			row = append(row, markdownEscape(c.noticeValue(a)))
+		}
+		if _, writeErr := fmt.Fprintln(w, markdownRow(row)); writeErr != nil {
+			return fmt.Errorf("write Markdown row: %w", writeErr)
+		}
+	}
+
+	return nil
+}
+
+// markdownRow formats cells as a Markdown table row.
+func markdownRow(cells []string) string {
+	return "| " + strings.Join(cells, " | ") + " |"
+}
+
+// markdownEscape escapes characters that would break a Markdown table cell.
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ").Replace(s)
+}
@@ -0,0 +1,129 @@
+package format_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/format"
+)
+
+// TestMarkdown tests the Markdown function.
+func TestMarkdown(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{
+			Name:    "pkg|pipe",
+			Version: "1.0.0",
+			License: strPtr("MIT"),
+			Purl:    "pkg:npm/pkg@1.0.0",
+			URL:     strPtr("https://example.com"),
+		},
+	}
+
+	testCases := []struct {
+		name string
+		opts format.MarkdownOptions
+		want string
+	}{
+		{
+			name: "default columns",
+			opts: format.MarkdownOptions{},
+			want: "| Name | License | Purl | URL |\n" +
+				"| --- | --- | --- | --- |\n" +
+				"| pkg\\|pipe | MIT | pkg:npm/pkg@1.0.0 | https://example.com |\n",
+		},
+		{
+			name: "selected columns",
+			opts: format.MarkdownOptions{Columns: []string{"name", "version"}},
+			want: "| Name | Version |\n" +
+				"| --- | --- |\n" +
+				"| pkg\\|pipe | 1.0.0 |\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := format.Markdown(&buf, input, tc.opts); err != nil {
+				t.Fatalf("Markdown() unexpected error: %v", err)
+			}
+			if buf.String() != tc.want {
+				t.Errorf("Markdown() = %q, want %q", buf.String(), tc.want)
+			}
+		})
+	}
+}
+
+// TestMarkdown_SpecialLicenses tests that packages without a license and with an unknown license are labeled
+// differently.
+func TestMarkdown_SpecialLicenses(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "proprietary", License: strPtr("NONE")},
+		{Name: "mystery", License: strPtr("NOASSERTION")},
+		{Name: "vendored"},
+	}
+
+	var buf bytes.Buffer
+	if err := format.Markdown(&buf, input, format.MarkdownOptions{Columns: []string{"name", "license"}}); err != nil {
+		t.Fatalf("Markdown() unexpected error: %v", err)
+	}
+	want := "| Name | License |\n" +
+		"| --- | --- |\n" +
+		"| proprietary | None (no license granted) |\n" +
+		"| mystery | Unknown |\n" +
+		"| vendored | Unknown |\n"
+	if buf.String() != want {
+		t.Errorf("Markdown() = %q, want %q", buf.String(), want)
+	}
+}
+
+// TestMarkdown_AttributionTexts tests that the Attribution Texts column is added by default when any attribution
+// carries attribution texts, since a notice must reproduce them.
+func TestMarkdown_AttributionTexts(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "acme", AttributionTexts: []string{"Copyright Acme"}},
+		{Name: "plain"},
+	}
+
+	var buf bytes.Buffer
+	if err := format.Markdown(&buf, input, format.MarkdownOptions{}); err != nil {
+		t.Fatalf("Markdown() unexpected error: %v", err)
+	}
+	want := "| Name | License | Purl | URL | Attribution Texts |\n" +
+		"| --- | --- | --- | --- | --- |\n" +
+		"| acme | Unknown |  |  | Copyright Acme |\n" +
+		"| plain | Unknown |  |  |  |\n"
+	if buf.String() != want {
+		t.Errorf("Markdown() = %q, want %q", buf.String(), want)
+	}
+}
+
+// TestMarkdown_GroupBySource tests the Markdown function grouping attributions by source file.
+func TestMarkdown_GroupBySource(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "react", Sources: []string{"web.json"}},
+		{Name: "manual"},
+	}
+
+	var buf bytes.Buffer
+	opts := format.MarkdownOptions{Columns: []string{"name"}, GroupBySource: true}
+	if err := format.Markdown(&buf, input, opts); err != nil {
+		t.Fatalf("Markdown() unexpected error: %v", err)
+	}
+
+	want := "## (unknown source)\n\n| Name |\n| --- |\n| manual |\n" +
+		"\n## web.json\n\n| Name |\n| --- |\n| react |\n"
+	if buf.String() != want {
+		t.Errorf("Markdown() = %q, want %q", buf.String(), want)
+	}
+}
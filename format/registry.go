@@ -0,0 +1,89 @@
+package format
+
+import (
+	"io"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// Formatter writes attributions in an output format.
+type Formatter interface {
+	// Format writes attributions to the provided io.Writer.
+	Format(w io.Writer, attributions []attribution.Attribution) error
+}
+
+// FormatterFunc adapts an ordinary function to the Formatter interface.
+type FormatterFunc func(w io.Writer, attributions []attribution.Attribution) error
+
+// Format calls f(w, attributions).
+func (f FormatterFunc) Format(w io.Writer, attributions []attribution.Attribution) error {
+	return f(w, attributions)
+}
+
+// registry holds the formatters selectable by name, starting with the built-in formats.
+//
+//nolint:gochecknoglobals // Register must be callable from any package, like database/sql.Register.
+var registry = struct {
+	sync.RWMutex
+	formatters map[string]Formatter
+}{
+	formatters: map[string]Formatter{
+		"csv": FormatterFunc(CSV),
+		"tsv": FormatterFunc(TSV),
+		"markdown": FormatterFunc(func(w io.Writer, a []attribution.Attribution) error {
+			return Markdown(w, a, MarkdownOptions{})
+		}),
+		"json":   FormatterFunc(JSON),
+		"ndjson": FormatterFunc(NDJSON),
+		"spdx": FormatterFunc(func(w io.Writer, a []attribution.Attribution) error {
+			return SPDX(w, a, SPDXOptions{})
+		}),
+		"spdx-lite": FormatterFunc(func(w io.Writer, a []attribution.Attribution) error {
+			return SPDX(w, a, SPDXOptions{Lite: true})
+		}),
+		"cyclonedx": FormatterFunc(func(w io.Writer, a []attribution.Attribution) error {
+			return CycloneDX(w, a, CycloneDXOptions{})
+		}),
+		"summary": FormatterFunc(Summary),
+	},
+}
+
+// Register makes a formatter available by name, so programs embedding sbomattr can plug in their own output
+// encoders next to the built-in ones (csv, tsv, markdown, json, ndjson, spdx, spdx-lite, cyclonedx, summary).
+// It panics if the name is empty, f is nil, or a formatter is already registered under the name.
+func Register(name string, f Formatter) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if name == "" {
+		panic("format: Register name is empty")
+	}
+	if f == nil {
+		panic("format: Register formatter is nil")
+	}
+	if _, dup := registry.formatters[name]; dup {
+		panic("format: Register called twice for formatter " + name)
+	}
+	registry.formatters[name] = f
+}
+
+// Lookup returns the formatter registered under name, built-in or not.
+// Returns ok as false if no formatter is registered under the name.
+func Lookup(name string) (Formatter, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	f, ok := registry.formatters[name]
+	return f, ok
+}
+
+// Names returns the names of all registered formatters, sorted.
+func Names() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	return slices.Sorted(maps.Keys(registry.formatters))
+}
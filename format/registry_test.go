@@ -0,0 +1,101 @@
+package format_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/format"
+)
+
+// TestLookup_BuiltIn tests that the built-in formats are registered.
+func TestLookup_BuiltIn(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{
+		"csv", "tsv", "markdown", "json", "ndjson", "spdx", "spdx-lite", "cyclonedx", "summary",
+	} {
+		if _, ok := format.Lookup(name); !ok {
+			t.Errorf("Lookup(%q) ok = false, want true", name)
+		}
+	}
+
+	if _, ok := format.Lookup("unknown"); ok {
+		t.Error(`Lookup("unknown") ok = true, want false`)
+	}
+
+	f, _ := format.Lookup("csv")
+	var buf bytes.Buffer
+	if err := f.Format(&buf, nil); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if want := "Name,License,Purl,URL\n"; buf.String() != want {
+		t.Errorf("Format() = %q, want %q", buf.String(), want)
+	}
+}
+
+// TestRegister tests registering a custom formatter.
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	// The registry is global, so the name is unique per run for the test to pass with -count
+	name := "test-names-" + rand.Text()
+	format.Register(name, format.FormatterFunc(func(w io.Writer, attrs []attribution.Attribution) error {
+		for _, a := range attrs {
+			if _, err := fmt.Fprintln(w, a.Name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	if !slices.Contains(format.Names(), name) {
+		t.Errorf("Names() = %v, want it to contain %s", format.Names(), name)
+	}
+
+	f, ok := format.Lookup(name)
+	if !ok {
+		t.Fatalf("Lookup(%q) ok = false, want true", name)
+	}
+	var buf bytes.Buffer
+	if err := f.Format(&buf, []attribution.Attribution{{Name: "a"}, {Name: "b"}}); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if want := "a\nb\n"; buf.String() != want {
+		t.Errorf("Format() = %q, want %q", buf.String(), want)
+	}
+}
+
+// TestRegister_Panics tests that invalid registrations panic.
+func TestRegister_Panics(t *testing.T) {
+	t.Parallel()
+
+	noop := format.FormatterFunc(func(io.Writer, []attribution.Attribution) error { return nil })
+
+	tests := []struct {
+		name      string
+		register  string
+		formatter format.Formatter
+	}{
+		{name: "empty name", register: "", formatter: noop},
+		{name: "nil formatter", register: "test-nil", formatter: nil},
+		{name: "duplicate", register: "csv", formatter: noop},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Register(%q) did not panic", tt.register)
+				}
+			}()
+			format.Register(tt.register, tt.formatter)
+		})
+	}
+}
@@ -0,0 +1,411 @@
+package format
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// SPDXOptions configures the SPDX document written by SPDX.
+type SPDXOptions struct {
+	// Name is the document name. Defaults to "sbomattr-aggregate".
+	Name string
+	// Namespace is the unique document namespace URI. Defaults to a namespace derived from the attributions.
+	Namespace string
+	// Tool is the creator tool, e.g. "sbomattr-v0.1.0". Defaults to "sbomattr".
+	Tool string
+	// Created is the document creation time. Defaults to the current time.
+	Created time.Time
+	// Lite restricts the document to the SPDX-Lite profile field subset (SPDX 2.3 Annex G) and validates it against
+	// the profile's mandatory fields.
+	Lite bool
+	// Graph, if set, records the dependencies between the attributions as DEPENDS_ON relationships. Edges with an end
+	// that is not one of the attributions are skipped.
+	Graph *attribution.Graph
+}
+
+// spdxDocument is a minimal SPDX 2.3 document.
+type spdxDocument struct {
+	SPDXVersion       string                 `json:"spdxVersion"`
+	DataLicense       string                 `json:"dataLicense"`
+	SPDXID            string                 `json:"SPDXID"`
+	Name              string                 `json:"name"`
+	DocumentNamespace string                 `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo       `json:"creationInfo"`
+	Packages          []spdxPackage          `json:"packages"`
+	Relationships     []spdxRelationship     `json:"relationships"`
+	ExtractedLicenses []spdxExtractedLicense `json:"hasExtractedLicensingInfos,omitempty"`
+}
+
+// spdxCreationInfo is the SPDX document creation information.
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+// spdxPackage is a minimal SPDX 2.3 package.
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	Supplier         string            `json:"supplier,omitempty"`
+	Originator       string            `json:"originator,omitempty"`
+	Homepage         string            `json:"homepage,omitempty"`
+	SourceInfo       string            `json:"sourceInfo,omitempty"`
+	Description      string            `json:"description,omitempty"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	AttributionTexts []string          `json:"attributionTexts,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+	Checksums        []spdxChecksum    `json:"checksums,omitempty"`
+}
+
+// spdxChecksum is an SPDX package checksum.
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+// spdxExternalRef is an SPDX package external reference.
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+// spdxRelationship is an SPDX relationship between two elements.
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// spdxExtractedLicense is a non-SPDX license referenced by a LicenseRef- identifier.
+type spdxExtractedLicense struct {
+	LicenseID     string   `json:"licenseId"`
+	ExtractedText string   `json:"extractedText"`
+	Name          string   `json:"name"`
+	SeeAlsos      []string `json:"seeAlsos,omitempty"`
+}
+
+// SPDX writes attributions as a minimal SPDX 2.3 JSON document to the provided io.Writer.
+// Each attribution becomes one package described by the document.
+// Licenses that are not valid SPDX expressions (see isSPDXExpression) are emitted as LicenseRef- identifiers with
+// extracted licensing info.
+func SPDX(w io.Writer, attributions []attribution.Attribution, opts SPDXOptions) error {
+	if opts.Name == "" {
+		opts.Name = "sbomattr-aggregate"
+	}
+	if opts.Tool == "" {
+		opts.Tool = "sbomattr"
+	}
+	if opts.Namespace == "" {
+		opts.Namespace = "https://spdx.org/spdxdocs/" + opts.Name + "-" + attributionsDigest(attributions)[:16]
+	}
+	if opts.Created.IsZero() {
+		opts.Created = time.Now()
+	}
+
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              opts.Name,
+		DocumentNamespace: opts.Namespace,
+		CreationInfo: spdxCreationInfo{
+			Created:  opts.Created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: " + opts.Tool},
+		},
+		Packages:      make([]spdxPackage, 0, len(attributions)),
+		Relationships: make([]spdxRelationship, 0, len(attributions)),
+	}
+
+	licenses := newSPDXLicenseRefs()
+	ids := make(map[string]string, len(attributions))
+
+	for i, a := range attributions {
+		pkg := spdxPackage{
+			Name:             a.QualifiedName(),
+			SPDXID:           "SPDXRef-Package-" + strconv.Itoa(i+1),
+			VersionInfo:      a.Version,
+			DownloadLocation: "NOASSERTION",
+			Supplier:         spdxSupplier(a),
+			Originator:       spdxOriginator(a),
+			Homepage:         deref(a.URL),
+			SourceInfo:       spdxSourceInfo(a.SourceRepo),
+			Description:      a.Description,
+			LicenseConcluded: "NOASSERTION",
+			LicenseDeclared:  "NOASSERTION",
+			CopyrightText:    "NOASSERTION",
+			AttributionTexts: a.AttributionTexts,
+			ExternalRefs:     spdxExternalRefs(a),
+			Checksums:        spdxChecksums(a),
+		}
+
+		// Keep the declared and concluded licenses of the source SBOM apart, falling back to the license for the
+		// declared one
+		if declared := cmp.Or(strings.TrimSpace(deref(a.LicenseDeclared)), licenseExpression(a)); declared != "" {
+			pkg.LicenseDeclared = licenses.expression(declared, a.LicenseURL)
+		}
+		if concluded := strings.TrimSpace(deref(a.LicenseConcluded)); concluded != "" {
+			pkg.LicenseConcluded = licenses.expression(concluded, a.LicenseURL)
+		}
+
+		doc.Packages = append(doc.Packages, pkg)
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID:      doc.SPDXID,
+			RelationshipType:   "DESCRIBES",
+			RelatedSPDXElement: pkg.SPDXID,
+		})
+		ids[attribution.DedupKey(a)] = pkg.SPDXID
+	}
+
+	doc.ExtractedLicenses = licenses.extracted
+
+	for _, edge := range graphEdges(opts.Graph, ids) {
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID:      ids[edge.From],
+			RelationshipType:   "DEPENDS_ON",
+			RelatedSPDXElement: ids[edge.To],
+		})
+	}
+
+	if opts.Lite {
+		doc = spdxLite(doc)
+		if err := validateSPDXLite(doc); err != nil {
+			return err
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("encode SPDX: %w", err)
+	}
+	return nil
+}
+
+// spdxExternalRefs returns the external references of an attribution: its purl and CPE.
+func spdxExternalRefs(a attribution.Attribution) []spdxExternalRef {
+	var refs []spdxExternalRef
+	if a.Purl != "" {
+		refs = append(refs, spdxExternalRef{
+			ReferenceCategory: "PACKAGE-MANAGER",
+			ReferenceType:     "purl",
+			ReferenceLocator:  a.Purl,
+		})
+	}
+	if a.CPE != "" {
+		refs = append(refs, spdxExternalRef{
+			ReferenceCategory: "SECURITY",
+			ReferenceType:     cpeReferenceType(a.CPE),
+			ReferenceLocator:  a.CPE,
+		})
+	}
+	return refs
+}
+
+// spdxChecksums returns the checksums of an attribution, sorted by algorithm.
+func spdxChecksums(a attribution.Attribution) []spdxChecksum {
+	var checksums []spdxChecksum
+	for _, algorithm := range slices.Sorted(maps.Keys(a.Hashes)) {
+		checksums = append(checksums, spdxChecksum{
+			Algorithm:     strings.Replace(algorithm, "BLAKE2B", "BLAKE2b", 1),
+			ChecksumValue: a.Hashes[algorithm],
+		})
+	}
+	return checksums
+}
+
+// cpeReferenceType returns the SPDX external reference type of a CPE identifier: cpe23Type for CPE 2.3 formatted
+// strings ("cpe:2.3:..."), cpe22Type otherwise.
+func cpeReferenceType(cpe string) string {
+	if strings.HasPrefix(cpe, "cpe:2.3:") {
+		return "cpe23Type"
+	}
+	return "cpe22Type"
+}
+
+// licenseExpression returns the full license expression of an attribution, rejoining a split license exception.
+func licenseExpression(a attribution.Attribution) string {
+	license := deref(a.License)
+	if license != "" && a.Exception != nil {
+		license += " WITH " + *a.Exception
+	}
+	return license
+}
+
+// spdxLicenseRefs converts licenses to SPDX license expressions, assigning LicenseRef- identifiers to the licenses
+// that are not valid expressions and collecting their extracted licensing info.
+type spdxLicenseRefs struct {
+	// refs maps each license to its LicenseRef- identifier.
+	refs map[string]string
+	// used holds the assigned identifiers, lowercased since SPDX identifiers are matched case-insensitively.
+	used map[string]bool
+	// extracted is the extracted licensing info of the assigned identifiers, in assignment order.
+	extracted []spdxExtractedLicense
+}
+
+// newSPDXLicenseRefs returns an empty spdxLicenseRefs.
+func newSPDXLicenseRefs() *spdxLicenseRefs {
+	return &spdxLicenseRefs{refs: make(map[string]string), used: make(map[string]bool)}
+}
+
+// expression returns a license as an SPDX license expression: unchanged if it is a valid expression or a special
+// value (NONE, NOASSERTION), otherwise a LicenseRef- identifier whose extracted licensing info holds the license and
+// links to licenseURL. Licenses that sanitize to the same identifier (e.g. "Foo Bar" and "Foo/Bar") get a numeric
+// suffix, so a package never points at the text of another license.
+func (r *spdxLicenseRefs) expression(license, licenseURL string) string {
+	if license == attribution.LicenseNone || license == attribution.LicenseNoAssertion || isSPDXExpression(license) {
+		return license
+	}
+	if ref, ok := r.refs[license]; ok {
+		return ref
+	}
+
+	base := "LicenseRef-" + sanitizeSPDXID(license)
+	ref := base
+	for n := 2; r.used[strings.ToLower(ref)]; n++ {
+		ref = base + "-" + strconv.Itoa(n)
+	}
+	r.refs[license] = ref
+	r.used[strings.ToLower(ref)] = true
+	r.extracted = append(r.extracted, spdxExtractedLicense{
+		LicenseID:     ref,
+		ExtractedText: license,
+		Name:          license,
+		SeeAlsos:      seeAlsos(licenseURL),
+	})
+	return ref
+}
+
+// isSPDXExpression reports whether s is a valid SPDX license expression: identifiers and operators alternate, every
+// license is on the SPDX license list (see attribution.IsLicenseID) or a LicenseRef- or DocumentRef- reference, and
+// every exception following WITH is on the SPDX exception list.
+func isSPDXExpression(s string) bool {
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("-.+:() ", r):
+		default:
+			return false
+		}
+	}
+
+	expectOperand, exception := true, false
+	for _, token := range strings.Fields(strings.NewReplacer("(", " ", ")", " ").Replace(s)) {
+		isOperator := token == "AND" || token == "OR" || token == "WITH" ||
+			token == "and" || token == "or" || token == "with"
+		if isOperator == expectOperand || (!isOperator && !isSPDXOperand(token, exception)) {
+			return false
+		}
+		expectOperand, exception = isOperator, strings.EqualFold(token, "WITH")
+	}
+	return !expectOperand
+}
+
+// isSPDXOperand reports whether token is a known SPDX license exception identifier if exception is set, and otherwise
+// a known SPDX license identifier (with an optional "+" operator) or a LicenseRef- or DocumentRef- reference.
+func isSPDXOperand(token string, exception bool) bool {
+	if exception {
+		return attribution.IsLicenseException(token)
+	}
+	if document, ref, ok := strings.Cut(token, ":"); ok {
+		return attribution.HasLicensePrefix(document, "DocumentRef-") &&
+			attribution.HasLicensePrefix(ref, "LicenseRef-")
+	}
+	return attribution.HasLicensePrefix(token, "LicenseRef-") ||
+		attribution.IsLicenseID(strings.TrimSuffix(token, "+"))
+}
+
+// sanitizeSPDXID replaces characters that are not allowed in SPDX identifiers with "-".
+func sanitizeSPDXID(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-':
+			return r
+		default:
+			return '-'
+		}
+	}, s)
+}
+
+// attributionsDigest returns a stable hex digest of the attributions, used to build unique document identifiers.
+func attributionsDigest(attributions []attribution.Attribution) string {
+	h := sha256.New()
+	for _, a := range attributions {
+		_, _ = io.WriteString(h, a.Purl+"\x00"+a.Name+"\x00")
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// graphEdges returns the edges of the graph between the attributions identified in ids (by DedupKey), without
+// duplicates and self-dependencies, sorted by From, then To, so the dependencies of each attribution are consecutive.
+// Returns nil if the graph is nil.
+func graphEdges(graph *attribution.Graph, ids map[string]string) []attribution.Edge {
+	if graph == nil {
+		return nil
+	}
+
+	var edges []attribution.Edge
+	for _, e := range graph.Edges {
+		if e.From != e.To && ids[e.From] != "" && ids[e.To] != "" {
+			edges = append(edges, e)
+		}
+	}
+	slices.SortFunc(edges, func(a, b attribution.Edge) int {
+		return cmp.Or(strings.Compare(a.From, b.From), strings.Compare(a.To, b.To))
+	})
+	return slices.Compact(edges)
+}
+
+// seeAlsos returns the seeAlso list of an extracted license with the given license URL, or nil if there is none.
+func seeAlsos(licenseURL string) []string {
+	if licenseURL == "" {
+		return nil
+	}
+	return []string{licenseURL}
+}
+
+// spdxSourceInfo describes a source code repository in an SPDX sourceInfo field, or returns an empty string if there
+// is none. spdxextract reads the repository back from it.
+func spdxSourceInfo(repo string) string {
+	if repo == "" {
+		return ""
+	}
+	return "Source code repository: " + repo
+}
+
+// spdxSupplier formats the supplier of an attribution as an SPDX supplier, or returns an empty string if there is none.
+// A supplier known by name only is written as an organization.
+func spdxSupplier(a attribution.Attribution) string {
+	if a.Supplier == "" {
+		return ""
+	}
+	if a.SupplierParty != nil && a.SupplierParty.Name == a.Supplier {
+		return a.SupplierParty.SPDX()
+	}
+	return attribution.Party{Name: a.Supplier}.SPDX()
+}
+
+// spdxOriginator formats the structured author of an attribution as an SPDX originator, or returns an empty string if
+// there is none. An author known by name only is left out, since it may be a person or an organization.
+func spdxOriginator(a attribution.Attribution) string {
+	if a.Originator == nil || a.Author != a.Originator.Name {
+		return ""
+	}
+	return a.Originator.SPDX()
+}
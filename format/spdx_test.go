@@ -0,0 +1,290 @@
+package format_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/format"
+	"github.com/boringbin/sbomattr/internal/sbom"
+	"github.com/boringbin/sbomattr/spdxextract"
+)
+
+// TestSPDX tests the SPDX function produces a document that round-trips through spdxextract.
+func TestSPDX(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{
+			Name:             "lodash",
+			Description:      "Lodash modular utilities.",
+			Supplier:         "OpenJS Foundation",
+			Hashes:           map[string]string{"SHA256": "abc123", "BLAKE2B-256": "fed789"},
+			License:          strPtr("MIT"),
+			Purl:             "pkg:npm/lodash@4.17.21",
+			URL:              strPtr("https://lodash.com"),
+			SourceRepo:       "https://github.com/lodash/lodash",
+			AttributionTexts: []string{"Copyright OpenJS Foundation and other contributors"},
+		},
+		{
+			Name:     "llvm",
+			Supplier: "Jane Doe",
+			SupplierParty: &attribution.Party{
+				Kind: attribution.PartyPerson, Name: "Jane Doe", Email: "jane@example.com",
+			},
+			Author:     "LLVM Foundation",
+			Originator: &attribution.Party{Kind: attribution.PartyOrganization, Name: "LLVM Foundation"},
+			License:    strPtr("Apache-2.0"),
+			Exception:  strPtr("LLVM-exception"),
+			CPE:        "cpe:2.3:a:llvm:llvm:17.0.0:*:*:*:*:*:*:*",
+		},
+		{
+			Name:       "custom",
+			License:    strPtr("Custom License, see LICENSE"),
+			LicenseURL: "https://example.com/custom/LICENSE",
+		},
+		{
+			Name: "unknown",
+		},
+	}
+
+	var buf bytes.Buffer
+	opts := format.SPDXOptions{Tool: "sbomattr-test", Created: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}
+	if err := format.SPDX(&buf, input, opts); err != nil {
+		t.Fatalf("SPDX() unexpected error: %v", err)
+	}
+
+	detected, err := sbom.DetectFormat(buf.Bytes())
+	if err != nil || detected != "spdx" {
+		t.Fatalf("DetectFormat() = %q, %v, want spdx", detected, err)
+	}
+
+	doc, err := spdxextract.ParseSBOM(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ParseSBOM() unexpected error: %v", err)
+	}
+	if doc.SPDXVersion != "SPDX-2.3" {
+		t.Errorf("SPDXVersion = %q, want SPDX-2.3", doc.SPDXVersion)
+	}
+
+	got := spdxextract.ExtractPackages(doc)
+	if len(got) != len(input) {
+		t.Fatalf("ExtractPackages() length = %d, want %d", len(got), len(input))
+	}
+	if got[0].Purl != "pkg:npm/lodash@4.17.21" || *got[0].License != "MIT" || *got[0].URL != "https://lodash.com" {
+		t.Errorf("round-tripped package[0] = %+v", got[0])
+	}
+	if want := map[string]string{"SHA256": "abc123", "BLAKE2B-256": "fed789"}; !maps.Equal(got[0].Hashes, want) {
+		t.Errorf("round-tripped package[0].Hashes = %v, want %v", got[0].Hashes, want)
+	}
+	if !strings.Contains(buf.String(), `"algorithm": "BLAKE2b-256"`) {
+		t.Errorf("SPDX() should use SPDX checksum algorithm names, got: %s", buf.String())
+	}
+	if got[0].Supplier != "OpenJS Foundation" {
+		t.Errorf("round-tripped package[0].Supplier = %q, want OpenJS Foundation", got[0].Supplier)
+	}
+	if got[0].Description != "Lodash modular utilities." {
+		t.Errorf("round-tripped package[0].Description = %q, want the description", got[0].Description)
+	}
+	if want := input[0].AttributionTexts; !slices.Equal(got[0].AttributionTexts, want) {
+		t.Errorf("round-tripped package[0].AttributionTexts = %q, want %q", got[0].AttributionTexts, want)
+	}
+	if got[0].SourceRepo != "https://github.com/lodash/lodash" {
+		t.Errorf("round-tripped package[0].SourceRepo = %q, want the repository", got[0].SourceRepo)
+	}
+	if got[1].SupplierParty == nil || *got[1].SupplierParty != *input[1].SupplierParty {
+		t.Errorf("round-tripped package[1].SupplierParty = %+v, want %+v", got[1].SupplierParty, input[1].SupplierParty)
+	}
+	if got[1].Originator == nil || *got[1].Originator != *input[1].Originator || got[1].Author != "LLVM Foundation" {
+		t.Errorf("round-tripped package[1] author = %q, %+v, want LLVM Foundation", got[1].Author, got[1].Originator)
+	}
+	if got[1].CPE != "cpe:2.3:a:llvm:llvm:17.0.0:*:*:*:*:*:*:*" {
+		t.Errorf("round-tripped package[1].CPE = %q, want the CPE", got[1].CPE)
+	}
+	if *got[1].License != "Apache-2.0 WITH LLVM-exception" {
+		t.Errorf("round-tripped package[1].License = %q, want rejoined exception", *got[1].License)
+	}
+	if *got[2].License != "LicenseRef-Custom-License--see-LICENSE" {
+		t.Errorf("round-tripped package[2].License = %q, want LicenseRef", *got[2].License)
+	}
+	if got[2].LicenseURL != "https://example.com/custom/LICENSE" {
+		t.Errorf("round-tripped package[2].LicenseURL = %q, want the seeAlso", got[2].LicenseURL)
+	}
+
+	var raw map[string]any
+	if unmarshalErr := json.Unmarshal(buf.Bytes(), &raw); unmarshalErr != nil {
+		t.Fatalf("failed to unmarshal SPDX output: %v", unmarshalErr)
+	}
+	creationInfo, _ := raw["creationInfo"].(map[string]any)
+	if creationInfo["created"] != "2025-01-02T03:04:05Z" {
+		t.Errorf("creationInfo.created = %v, want 2025-01-02T03:04:05Z", creationInfo["created"])
+	}
+	if extracted, _ := raw["hasExtractedLicensingInfos"].([]any); len(extracted) != 1 {
+		t.Errorf("hasExtractedLicensingInfos length = %d, want 1", len(extracted))
+	}
+	if relationships, _ := raw["relationships"].([]any); len(relationships) != len(input) {
+		t.Errorf("relationships length = %d, want %d", len(relationships), len(input))
+	}
+}
+
+// TestSPDX_LicenseRefs tests that licenses that are not valid SPDX expressions, including single words that are not on
+// the SPDX license list, are written as LicenseRef- identifiers, and that licenses sanitizing to the same identifier
+// keep their own extracted text.
+func TestSPDX_LicenseRefs(t *testing.T) {
+	t.Parallel()
+
+	licenses := []string{
+		"MIT", "GPL-2.0+", "MIT OR LicenseRef-Acme", "Apache-2.0 WITH LLVM-exception", "NONE",
+		"Custom", "GPL2", "MIT OR Custom", "Foo Bar", "Foo/Bar", "Custom",
+	}
+	input := make([]attribution.Attribution, 0, len(licenses))
+	for _, license := range licenses {
+		input = append(input, attribution.Attribution{Name: license, License: strPtr(license)})
+	}
+
+	var buf bytes.Buffer
+	if err := format.SPDX(&buf, input, format.SPDXOptions{}); err != nil {
+		t.Fatalf("SPDX() unexpected error: %v", err)
+	}
+	var doc struct {
+		Packages []struct {
+			LicenseDeclared string `json:"licenseDeclared"`
+		} `json:"packages"`
+		ExtractedLicenses []struct {
+			LicenseID     string `json:"licenseId"`
+			ExtractedText string `json:"extractedText"`
+		} `json:"hasExtractedLicensingInfos"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("failed to unmarshal SPDX output: %v", err)
+	}
+
+	want := []string{
+		"MIT", "GPL-2.0+", "MIT OR LicenseRef-Acme", "Apache-2.0 WITH LLVM-exception", "NONE",
+		"LicenseRef-Custom", "LicenseRef-GPL2", "LicenseRef-MIT-OR-Custom", "LicenseRef-Foo-Bar",
+		"LicenseRef-Foo-Bar-2", "LicenseRef-Custom",
+	}
+	for i, pkg := range doc.Packages {
+		if pkg.LicenseDeclared != want[i] {
+			t.Errorf("packages[%d].licenseDeclared = %q, want %q", i, pkg.LicenseDeclared, want[i])
+		}
+	}
+
+	texts := make(map[string]string)
+	for _, extracted := range doc.ExtractedLicenses {
+		texts[extracted.LicenseID] = extracted.ExtractedText
+	}
+	wantTexts := map[string]string{
+		"LicenseRef-Custom":        "Custom",
+		"LicenseRef-GPL2":          "GPL2",
+		"LicenseRef-MIT-OR-Custom": "MIT OR Custom",
+		"LicenseRef-Foo-Bar":       "Foo Bar",
+		"LicenseRef-Foo-Bar-2":     "Foo/Bar",
+	}
+	if len(doc.ExtractedLicenses) != len(wantTexts) || !maps.Equal(texts, wantTexts) {
+		t.Errorf("hasExtractedLicensingInfos = %+v, want %v", doc.ExtractedLicenses, wantTexts)
+	}
+}
+
+// TestSPDX_DeclaredConcluded tests that the declared and concluded licenses survive an SPDX round trip, and that the
+// license is the declared license when the SBOM did not distinguish them.
+func TestSPDX_DeclaredConcluded(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{
+			Name:             "both",
+			License:          strPtr("Apache-2.0"),
+			LicenseDeclared:  strPtr("MIT"),
+			LicenseConcluded: strPtr("Apache-2.0"),
+		},
+		{Name: "concluded", License: strPtr("MIT"), LicenseConcluded: strPtr("Custom")},
+		{Name: "license", License: strPtr("BSD-3-Clause")},
+	}
+
+	var buf bytes.Buffer
+	if err := format.SPDX(&buf, input, format.SPDXOptions{}); err != nil {
+		t.Fatalf("SPDX() unexpected error: %v", err)
+	}
+	doc, err := spdxextract.ParseSBOM(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ParseSBOM() unexpected error: %v", err)
+	}
+
+	want := []struct{ declared, concluded string }{
+		{declared: "MIT", concluded: "Apache-2.0"},
+		{declared: "MIT", concluded: "LicenseRef-Custom"},
+		{declared: "BSD-3-Clause", concluded: attribution.LicenseNoAssertion},
+	}
+	for i, pkg := range spdxextract.ExtractPackages(doc) {
+		if strValue(pkg.LicenseDeclared) != want[i].declared || strValue(pkg.LicenseConcluded) != want[i].concluded {
+			t.Errorf("package[%d] declared, concluded = %q, %q, want %q, %q", i,
+				strValue(pkg.LicenseDeclared), strValue(pkg.LicenseConcluded), want[i].declared, want[i].concluded)
+		}
+	}
+}
+
+// TestSPDX_DeterministicNamespace tests that the default namespace only depends on the attributions.
+func TestSPDX_DeterministicNamespace(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{{Name: "lodash", Purl: "pkg:npm/lodash@4.17.21"}}
+
+	namespace := func() string {
+		var buf bytes.Buffer
+		if err := format.SPDX(&buf, input, format.SPDXOptions{}); err != nil {
+			t.Fatalf("SPDX() unexpected error: %v", err)
+		}
+		var raw map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &raw); err != nil {
+			t.Fatalf("failed to unmarshal SPDX output: %v", err)
+		}
+		ns, _ := raw["documentNamespace"].(string)
+		return ns
+	}
+
+	first, second := namespace(), namespace()
+	if first == "" || first != second {
+		t.Errorf("documentNamespace = %q then %q, want identical non-empty values", first, second)
+	}
+}
+
+// TestSPDX_Graph tests that the dependencies between the attributions are written as DEPENDS_ON relationships that
+// spdxextract reads back, skipping edges to packages that are not written.
+func TestSPDX_Graph(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "express", Purl: "pkg:npm/express@4.18.2"},
+		{Name: "debug", Purl: "pkg:npm/debug@2.6.9"},
+		{Name: "ms", Purl: "pkg:npm/ms@2.0.0"},
+	}
+	graph := &attribution.Graph{Edges: []attribution.Edge{
+		{From: "pkg:npm/debug@2.6.9", To: "pkg:npm/ms@2.0.0"},
+		{From: "pkg:npm/express@4.18.2", To: "pkg:npm/debug@2.6.9"},
+		{From: "pkg:npm/express@4.18.2", To: "pkg:npm/left-pad@1.3.0"},
+		{From: "pkg:npm/debug@2.6.9", To: "pkg:npm/ms@2.0.0"},
+	}}
+
+	var buf bytes.Buffer
+	if err := format.SPDX(&buf, input, format.SPDXOptions{Graph: graph}); err != nil {
+		t.Fatalf("SPDX() unexpected error: %v", err)
+	}
+
+	doc, err := spdxextract.ParseSBOM(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ParseSBOM() unexpected error: %v", err)
+	}
+	want := []attribution.Edge{
+		{From: "pkg:npm/debug@2.6.9", To: "pkg:npm/ms@2.0.0"},
+		{From: "pkg:npm/express@4.18.2", To: "pkg:npm/debug@2.6.9"},
+	}
+	if got := spdxextract.Dependencies(doc); !slices.Equal(got, want) {
+		t.Errorf("Dependencies() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,91 @@
+package format
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidSPDXLite is returned by SPDX when the document does not meet the SPDX-Lite profile requirements.
+var ErrInvalidSPDXLite = errors.New("invalid SPDX-Lite document")
+
+// spdxLite returns a copy of the document restricted to the SPDX-Lite profile: document creation information,
+// package information (name, SPDX ID, version, download location, files analyzed, home page, concluded and declared
+// licenses, and copyright text), and other licensing information. Package external references, checksums,
+// originators, descriptions, attribution texts, source information, and license cross references (seeAlso) are not
+// part of the profile and are dropped; the DESCRIBES relationships are kept, as SPDX 2.3 requires them.
+func spdxLite(doc spdxDocument) spdxDocument {
+	packages := make([]spdxPackage, 0, len(doc.Packages))
+	for _, pkg := range doc.Packages {
+		pkg.ExternalRefs = nil
+		pkg.Checksums = nil
+		pkg.Originator = ""
+		pkg.Description = ""
+		pkg.AttributionTexts = nil
+		pkg.SourceInfo = ""
+		packages = append(packages, pkg)
+	}
+	doc.Packages = packages
+
+	extracted := make([]spdxExtractedLicense, 0, len(doc.ExtractedLicenses))
+	for _, license := range doc.ExtractedLicenses {
+		license.SeeAlsos = nil
+		extracted = append(extracted, license)
+	}
+	doc.ExtractedLicenses = extracted
+	return doc
+}
+
+// spdxField is a named field value checked by validateSPDXLite.
+type spdxField struct {
+	name  string
+	value string
+}
+
+// validateSPDXLite checks that the document has every field the SPDX-Lite profile makes mandatory.
+// Returns an error wrapping ErrInvalidSPDXLite that lists every problem found.
+func validateSPDXLite(doc spdxDocument) error {
+	creators := ""
+	if len(doc.CreationInfo.Creators) > 0 {
+		creators = doc.CreationInfo.Creators[0]
+	}
+
+	problems := missingSPDXFields("document", []spdxField{
+		{name: "spdxVersion", value: doc.SPDXVersion},
+		{name: "dataLicense", value: doc.DataLicense},
+		{name: "SPDXID", value: doc.SPDXID},
+		{name: "name", value: doc.Name},
+		{name: "documentNamespace", value: doc.DocumentNamespace},
+		{name: "creationInfo.creators", value: creators},
+		{name: "creationInfo.created", value: doc.CreationInfo.Created},
+	})
+
+	for _, pkg := range doc.Packages {
+		problems = append(problems, missingSPDXFields("package "+pkg.SPDXID, []spdxField{
+			{name: "name", value: pkg.Name},
+			{name: "SPDXID", value: pkg.SPDXID},
+			{name: "downloadLocation", value: pkg.DownloadLocation},
+			{name: "licenseConcluded", value: pkg.LicenseConcluded},
+			{name: "licenseDeclared", value: pkg.LicenseDeclared},
+			{name: "copyrightText", value: pkg.CopyrightText},
+		})...)
+		if pkg.FilesAnalyzed {
+			problems = append(problems, fmt.Errorf("package %s: filesAnalyzed must be false", pkg.SPDXID))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidSPDXLite, errors.Join(problems...))
+	}
+	return nil
+}
+
+// missingSPDXFields returns an error for each empty field of an SPDX element.
+func missingSPDXFields(element string, fields []spdxField) []error {
+	var problems []error
+	for _, f := range fields {
+		if f.value == "" {
+			problems = append(problems, fmt.Errorf("%s: %s is required", element, f.name))
+		}
+	}
+	return problems
+}
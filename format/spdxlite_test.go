@@ -0,0 +1,84 @@
+package format_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/format"
+	"github.com/boringbin/sbomattr/spdxextract"
+)
+
+// TestSPDX_Lite tests that the SPDX-Lite profile keeps only the profile's fields.
+func TestSPDX_Lite(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{
+			Name:        "lodash",
+			Version:     "4.17.21",
+			Description: "Lodash modular utilities.",
+			License:     strPtr("MIT"),
+			Purl:        "pkg:npm/lodash@4.17.21",
+			CPE:         "cpe:2.3:a:lodash:lodash:4.17.21:*:*:*:*:*:*:*",
+			URL:         strPtr("https://lodash.com"),
+			SourceRepo:  "https://github.com/lodash/lodash",
+			Author:      "John-David Dalton",
+			Originator:  &attribution.Party{Kind: attribution.PartyPerson, Name: "John-David Dalton"},
+		},
+		{Name: "custom", License: strPtr("Custom License"), LicenseURL: "https://example.com/LICENSE"},
+	}
+
+	var buf bytes.Buffer
+	if err := format.SPDX(&buf, input, format.SPDXOptions{Lite: true}); err != nil {
+		t.Fatalf("SPDX() unexpected error: %v", err)
+	}
+
+	for _, unwanted := range []string{
+		"externalRefs", "description", "pkg:npm/lodash", "sourceInfo", "seeAlsos", "originator",
+	} {
+		if strings.Contains(buf.String(), unwanted) {
+			t.Errorf("SPDX-Lite output should not contain %q, got: %s", unwanted, buf.String())
+		}
+	}
+	for _, want := range []string{`"versionInfo": "4.17.21"`, `"homepage": "https://lodash.com"`,
+		`"hasExtractedLicensingInfos"`, `"copyrightText": "NOASSERTION"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("SPDX-Lite output missing %s, got: %s", want, buf.String())
+		}
+	}
+
+	doc, err := spdxextract.ParseSBOM(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ParseSBOM() unexpected error: %v", err)
+	}
+	if got := spdxextract.ExtractPackages(doc); len(got) != len(input) || got[0].Version != "4.17.21" {
+		t.Errorf("round-tripped packages = %+v, want %d packages with versions", got, len(input))
+	}
+}
+
+// TestSPDX_LiteInvalid tests that packages missing mandatory SPDX-Lite fields are rejected.
+func TestSPDX_LiteInvalid(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{{Name: "ok"}, {Name: ""}}
+
+	var buf bytes.Buffer
+	err := format.SPDX(&buf, input, format.SPDXOptions{Lite: true})
+	if !errors.Is(err, format.ErrInvalidSPDXLite) {
+		t.Fatalf("SPDX() error = %v, want ErrInvalidSPDXLite", err)
+	}
+	if !strings.Contains(err.Error(), "package SPDXRef-Package-2: name is required") {
+		t.Errorf("SPDX() error = %v, want it to name the package and field", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("SPDX() wrote output for an invalid document: %s", buf.String())
+	}
+
+	// The full profile has no such requirement
+	if err = format.SPDX(&buf, input, format.SPDXOptions{}); err != nil {
+		t.Errorf("SPDX() without Lite unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package format
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// ErrGroupingNotStreamable is returned when creating a streaming writer with CSVOptions.GroupBySource set, since
+// grouping needs every attribution before the first row can be written.
+var ErrGroupingNotStreamable = errors.New("grouping by source is not supported when streaming")
+
+// Writer writes attributions one at a time, so large aggregations can be emitted with constant memory.
+// Begin must be called once before the first WriteAttribution, and End once after the last one.
+type Writer interface {
+	// Begin writes anything that precedes the attributions, such as a header.
+	Begin() error
+	// WriteAttribution writes a single attribution.
+	WriteAttribution(a attribution.Attribution) error
+	// End writes anything that follows the attributions and flushes buffered output.
+	End() error
+}
+
+// WriteAll writes every attribution of seq to w, calling Begin and End around them.
+// Use slices.Values to write a slice.
+func WriteAll(w Writer, seq iter.Seq[attribution.Attribution]) error {
+	if err := w.Begin(); err != nil {
+		return err
+	}
+	for a := range seq {
+		if err := w.WriteAttribution(a); err != nil {
+			return err
+		}
+	}
+	return w.End()
+}
+
+// CSVWriter is a streaming Writer for CSV output.
+type CSVWriter struct {
+	writer *csv.Writer
+	cols   []column
+}
+
+// NewCSVWriter returns a streaming CSV writer using the given options.
+// Since the attributions are not known in advance, the Exception column is only written if selected in
+// opts.Columns. Returns ErrGroupingNotStreamable if opts.GroupBySource is set, or an error for unknown columns.
+func NewCSVWriter(w io.Writer, opts CSVOptions) (*CSVWriter, error) {
+	if opts.GroupBySource {
+		return nil, ErrGroupingNotStreamable
+	}
+
+	cols, err := columnsFor(nil, opts.Columns, opts.LicenseDetails, false)
+	if err != nil {
+		return nil, err
+	}
+
+	writer := csv.NewWriter(w)
+	if opts.Delimiter != 0 {
+		writer.Comma = opts.Delimiter
+	}
+	writer.UseCRLF = opts.UseCRLF
+
+	return &CSVWriter{writer: writer, cols: cols}, nil
+}
+
+// Begin writes the CSV header.
+func (w *CSVWriter) Begin() error {
+	header := make([]string, 0, len(w.cols))
+	for _, c := range w.cols {
+		header = append(header, c.header)
+	}
+	if err := w.writer.Write(header); err != nil {
+		return fmt.Errorf("write CSV header: %w", err)
+	}
+	return nil
+}
+
+// WriteAttribution writes a CSV row.
+func (w *CSVWriter) WriteAttribution(a attribution.Attribution) error {
+	row := make([]string, 0, len(w.cols))
+	for _, c := range w.cols {
+		row = append(row, c.value(a))
+	}
+	if err := w.writer.Write(row); err != nil {
+		return fmt.Errorf("write CSV row: %w", err)
+	}
+	return nil
+}
+
+// End flushes the buffered CSV output.
+func (w *CSVWriter) End() error {
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		return fmt.Errorf("flush CSV: %w", err)
+	}
+	return nil
+}
+
+// NDJSONWriter is a streaming Writer for newline-delimited JSON output, one attribution object per line.
+type NDJSONWriter struct {
+	encoder *json.Encoder
+}
+
+// NewNDJSONWriter returns a streaming newline-delimited JSON writer.
+func NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	return &NDJSONWriter{encoder: json.NewEncoder(w)}
+}
+
+// Begin does nothing, since NDJSON has no header.
+func (w *NDJSONWriter) Begin() error {
+	return nil
+}
+
+// WriteAttribution writes an attribution as a single line of JSON.
+func (w *NDJSONWriter) WriteAttribution(a attribution.Attribution) error {
+	if err := w.encoder.Encode(a); err != nil {
+		return fmt.Errorf("encode NDJSON: %w", err)
+	}
+	return nil
+}
+
+// End does nothing, since the output is not buffered.
+func (w *NDJSONWriter) End() error {
+	return nil
+}
+
+// NDJSON writes attributions as newline-delimited JSON to the provided io.Writer, one attribution object per line.
+func NDJSON(w io.Writer, attributions []attribution.Attribution) error {
+	return WriteAll(NewNDJSONWriter(w), slices.Values(attributions))
+}
@@ -0,0 +1,114 @@
+package format_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/format"
+)
+
+// TestCSVWriter tests streaming attributions with the CSV writer.
+func TestCSVWriter(t *testing.T) {
+	t.Parallel()
+
+	attrs := []attribution.Attribution{
+		{Name: "a", License: strPtr("MIT"), Purl: "pkg:npm/a@1.0.0"},
+		{Name: "b", License: strPtr("GPL-2.0-only"), Exception: strPtr("Classpath-exception-2.0")},
+	}
+
+	var buf bytes.Buffer
+	w, err := format.NewCSVWriter(&buf, format.CSVOptions{Delimiter: '\t'})
+	if err != nil {
+		t.Fatalf("NewCSVWriter() error = %v", err)
+	}
+	if writeErr := format.WriteAll(w, slices.Values(attrs)); writeErr != nil {
+		t.Fatalf("WriteAll() error = %v", writeErr)
+	}
+
+	want := "Name\tLicense\tPurl\tURL\n" +
+		"a\tMIT\tpkg:npm/a@1.0.0\t\n" +
+		"b\tGPL-2.0-only\t\t\n"
+	if got := buf.String(); got != want {
+		t.Errorf("WriteAll() = %q, want %q", got, want)
+	}
+}
+
+// TestCSVWriter_Columns tests that selected columns are written when streaming.
+func TestCSVWriter_Columns(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w, err := format.NewCSVWriter(&buf, format.CSVOptions{Columns: []string{"purl", "exception"}})
+	if err != nil {
+		t.Fatalf("NewCSVWriter() error = %v", err)
+	}
+	attrs := []attribution.Attribution{{Name: "b", Purl: "pkg:npm/b@1.0.0", Exception: strPtr("LLVM-exception")}}
+	if writeErr := format.WriteAll(w, slices.Values(attrs)); writeErr != nil {
+		t.Fatalf("WriteAll() error = %v", writeErr)
+	}
+
+	if want := "Purl,Exception\npkg:npm/b@1.0.0,LLVM-exception\n"; buf.String() != want {
+		t.Errorf("WriteAll() = %q, want %q", buf.String(), want)
+	}
+}
+
+// TestNewCSVWriter_Errors tests the options that cannot be streamed.
+func TestNewCSVWriter_Errors(t *testing.T) {
+	t.Parallel()
+
+	if _, err := format.NewCSVWriter(&bytes.Buffer{}, format.CSVOptions{GroupBySource: true}); !errors.Is(
+		err, format.ErrGroupingNotStreamable,
+	) {
+		t.Errorf("NewCSVWriter(GroupBySource) error = %v, want ErrGroupingNotStreamable", err)
+	}
+	if _, err := format.NewCSVWriter(&bytes.Buffer{}, format.CSVOptions{Columns: []string{"bogus"}}); err == nil {
+		t.Error("NewCSVWriter(unknown column) error = nil, want error")
+	}
+}
+
+// TestNDJSON tests that each attribution is written as one JSON object per line.
+func TestNDJSON(t *testing.T) {
+	t.Parallel()
+
+	attrs := []attribution.Attribution{
+		{Name: "a", License: strPtr("MIT"), Purl: "pkg:npm/a@1.0.0"},
+		{Name: "b", Purl: "pkg:npm/b@2.0.0"},
+	}
+
+	var buf bytes.Buffer
+	if err := format.NDJSON(&buf, attrs); err != nil {
+		t.Fatalf("NDJSON() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(attrs) {
+		t.Fatalf("NDJSON() wrote %d lines, want %d: %q", len(lines), len(attrs), buf.String())
+	}
+	for i, line := range lines {
+		var got attribution.Attribution
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", i, err)
+		}
+		if got.Purl != attrs[i].Purl {
+			t.Errorf("line %d purl = %q, want %q", i, got.Purl, attrs[i].Purl)
+		}
+	}
+}
+
+// TestNDJSON_Empty tests that no attributions produce no output.
+func TestNDJSON_Empty(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := format.NDJSON(&buf, nil); err != nil {
+		t.Fatalf("NDJSON() error = %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("NDJSON() = %q, want empty output", buf.String())
+	}
+}
@@ -0,0 +1,112 @@
+package format
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// Count is a value and the number of attributions that have it.
+type Count struct {
+	// Value is the counted value, e.g. a license or a purl type.
+	Value string `json:"value"`
+	// Count is the number of attributions with the value.
+	Count int `json:"count"`
+}
+
+// Stats holds summary statistics of a list of attributions.
+type Stats struct {
+	// Packages is the total number of attributions.
+	Packages int `json:"packages"`
+	// UnknownLicenses is the number of attributions whose license is unknown (missing, empty, or NOASSERTION).
+	UnknownLicenses int `json:"unknownLicenses"`
+	// NoLicense is the number of attributions explicitly without a license (NONE), which grant no rights to use them.
+	NoLicense int `json:"noLicense"`
+	// MissingPurls is the number of attributions without a purl.
+	MissingPurls int `json:"missingPurls"`
+	// Licenses counts the attributions per license, most common first. Unknown licenses and NONE are not included.
+	Licenses []Count `json:"licenses"`
+	// Ecosystems counts the attributions per purl type (npm, maven, ...), most common first (see
+	// attribution.CountByEcosystem). Attributions without a purl are not included; unparsable purls are counted as
+	// "unknown".
+	Ecosystems []Count `json:"ecosystems"`
+}
+
+// Summarize computes summary statistics of attributions: totals, and counts per license and per purl ecosystem.
+func Summarize(attributions []attribution.Attribution) Stats {
+	stats := Stats{Packages: len(attributions)}
+	licenses := make(map[string]int)
+
+	for _, a := range attributions {
+		switch a.LicenseStatus() {
+		case attribution.LicenseStatusUnknown:
+			stats.UnknownLicenses++
+		case attribution.LicenseStatusNone:
+			stats.NoLicense++
+		case attribution.LicenseStatusKnown:
+			licenses[licenseExpression(a)]++
+		}
+	}
+
+	ecosystems := attribution.CountByEcosystem(attributions)
+	stats.MissingPurls = ecosystems[""]
+	delete(ecosystems, "")
+
+	stats.Licenses = sortedCounts(licenses)
+	stats.Ecosystems = sortedCounts(ecosystems)
+	return stats
+}
+
+// Summary writes a plain-text compliance overview of attributions to the provided io.Writer: totals (packages,
+// unknown licenses, packages without a license, missing purls), followed by counts per license and per purl ecosystem.
+func Summary(w io.Writer, attributions []attribution.Attribution) error {
+	stats := Summarize(attributions)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Packages:\t%d\n", stats.Packages)
+	fmt.Fprintf(tw, "Unknown licenses:\t%d\n", stats.UnknownLicenses)
+	fmt.Fprintf(tw, "No license (NONE):\t%d\n", stats.NoLicense)
+	fmt.Fprintf(tw, "Missing purls:\t%d\n", stats.MissingPurls)
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("write summary: %w", err)
+	}
+
+	sections := []struct {
+		title  string
+		counts []Count
+	}{
+		{title: "Licenses", counts: stats.Licenses},
+		{title: "Ecosystems", counts: stats.Ecosystems},
+	}
+	for _, section := range sections {
+		if len(section.counts) == 0 {
+			continue
+		}
+		fmt.Fprintf(tw, "\n%s:\n", section.title)
+		for _, c := range section.counts {
+			fmt.Fprintf(tw, "  %s\t%d\n", c.Value, c.Count)
+		}
+		if err := tw.Flush(); err != nil {
+			return fmt.Errorf("write summary: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// sortedCounts converts a count map to a slice sorted by count (descending), then value.
+func sortedCounts(m map[string]int) []Count {
+	counts := make([]Count, 0, len(m))
+	for _, value := range slices.Sorted(maps.Keys(m)) {
+		counts = append(counts, Count{Value: value, Count: m[value]})
+	}
+	slices.SortStableFunc(counts, func(a, b Count) int {
+		return cmp.Compare(b.Count, a.Count)
+	})
+	return counts
+}
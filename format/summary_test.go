@@ -0,0 +1,86 @@
+package format_test
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/format"
+)
+
+// summaryInput returns the attributions used by the summary tests.
+func summaryInput() []attribution.Attribution {
+	return []attribution.Attribution{
+		{Name: "react", License: strPtr("MIT"), Purl: "pkg:npm/react@18.2.0"},
+		{Name: "lodash", License: strPtr("MIT"), Purl: "pkg:npm/lodash@4.17.21"},
+		{Name: "guava", License: strPtr("Apache-2.0"), Purl: "pkg:maven/com.google.guava/guava@32.0.0"},
+		{Name: "mystery", License: strPtr("NOASSERTION"), Purl: "not-a-purl"},
+		{Name: "vendored", License: nil},
+		{Name: "proprietary", License: strPtr("NONE"), Purl: "pkg:npm/proprietary@1.0.0"},
+	}
+}
+
+// TestSummarize tests the Summarize function.
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+
+	stats := format.Summarize(summaryInput())
+
+	if stats.Packages != 6 || stats.UnknownLicenses != 2 || stats.NoLicense != 1 || stats.MissingPurls != 1 {
+		t.Errorf("Summarize() totals = %d/%d/%d/%d, want 6/2/1/1",
+			stats.Packages, stats.UnknownLicenses, stats.NoLicense, stats.MissingPurls)
+	}
+
+	wantLicenses := []format.Count{{Value: "MIT", Count: 2}, {Value: "Apache-2.0", Count: 1}}
+	if !slices.Equal(stats.Licenses, wantLicenses) {
+		t.Errorf("Summarize().Licenses = %v, want %v", stats.Licenses, wantLicenses)
+	}
+
+	wantEcosystems := []format.Count{{Value: "npm", Count: 3}, {Value: "maven", Count: 1}, {Value: "unknown", Count: 1}}
+	if !slices.Equal(stats.Ecosystems, wantEcosystems) {
+		t.Errorf("Summarize().Ecosystems = %v, want %v", stats.Ecosystems, wantEcosystems)
+	}
+}
+
+// TestSummary tests the Summary function.
+func TestSummary(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := format.Summary(&buf, summaryInput()); err != nil {
+		t.Fatalf("Summary() error = %v", err)
+	}
+
+	want := "Packages:           6\n" +
+		"Unknown licenses:   2\n" +
+		"No license (NONE):  1\n" +
+		"Missing purls:      1\n" +
+		"\n" +
+		"Licenses:\n" +
+		"  MIT         2\n" +
+		"  Apache-2.0  1\n" +
+		"\n" +
+		"Ecosystems:\n" +
+		"  npm      3\n" +
+		"  maven    1\n" +
+		"  unknown  1\n"
+	if buf.String() != want {
+		t.Errorf("Summary() output =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+// TestSummary_Empty tests the Summary function with no attributions.
+func TestSummary_Empty(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := format.Summary(&buf, nil); err != nil {
+		t.Fatalf("Summary() error = %v", err)
+	}
+
+	want := "Packages:           0\nUnknown licenses:   0\nNo license (NONE):  0\nMissing purls:      0\n"
+	if buf.String() != want {
+		t.Errorf("Summary() output = %q, want %q", buf.String(), want)
+	}
+}
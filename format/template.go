@@ -0,0 +1,60 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// Template renders attributions using a Go text/template to the provided io.Writer.
+// The template receives the attribution slice as its data (dot), so a typical template ranges over it:
+//
+//	{{range .}}{{.Name}} ({{deref .License}}){{"\n"}}{{end}}
+//
+// The "deref" function returns the value of an optional (*string) field, or an empty string if it is nil, and the
+// "purl" function returns the components of a purl (see attribution.ParsePurl):
+//
+//	{{range .}}{{.Name}} ({{index (purl .Purl).Qualifiers "distro"}}){{"\n"}}{{end}}
+//
+// The "licenseIDs" function returns the identifiers of a license expression (see attribution.LicenseIDs), and the
+// "licenseText" function the full text of a standard license, or an empty string if it is not bundled (see
+// attribution.LicenseText):
+//
+//	{{range .}}{{range licenseIDs (deref .License)}}{{licenseText .}}{{end}}{{end}}
+func Template(w io.Writer, attributions []attribution.Attribution, tmpl string) error {
+	t, err := template.New("notice").Funcs(templateFuncs()).Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("parse template: %w", err)
+	}
+
+	if execErr := t.Execute(w, attributions); execErr != nil {
+		return fmt.Errorf("execute template: %w", execErr)
+	}
+
+	return nil
+}
+
+// templateFuncs returns the helper functions available to templates.
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"deref":       deref,
+		"purl":        purlParts,
+		"licenseIDs":  attribution.LicenseIDs,
+		"licenseText": licenseText,
+	}
+}
+
+// licenseText returns the bundled text of a standard license, or an empty string if it is not bundled (see
+// attribution.LicenseText).
+func licenseText(id string) string {
+	text, _ := attribution.LicenseText(id)
+	return text
+}
+
+// purlParts returns the components of a purl, or empty components if it is invalid.
+func purlParts(purl string) attribution.PurlParts {
+	parts, _ := attribution.ParsePurl(purl)
+	return parts
+}
@@ -0,0 +1,85 @@
+package format_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/format"
+)
+
+// TestTemplate tests the Template function.
+func TestTemplate(t *testing.T) {
+	t.Parallel()
+
+	input := []attribution.Attribution{
+		{Name: "pkg1", License: strPtr("MIT"), Purl: "pkg:npm/pkg1@1.0.0"},
+		{Name: "pkg2", License: nil, Purl: "pkg:deb/debian/pkg2@2.0.0?distro=bookworm"},
+	}
+
+	testCases := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{
+			name: "range with deref",
+			tmpl: `{{range .}}{{.Name}}: {{deref .License}}{{"\n"}}{{end}}`,
+			want: "pkg1: MIT\npkg2: \n",
+		},
+		{
+			name: "header and count",
+			tmpl: `Third-party notices ({{len .}} packages)`,
+			want: "Third-party notices (2 packages)",
+		},
+		{
+			name: "purl components",
+			tmpl: `{{range .}}{{with purl .Purl}}{{.Type}} {{.Version}} {{index .Qualifiers "distro"}}{{end}};{{end}}`,
+			want: "npm 1.0.0 ;deb 2.0.0 bookworm;",
+		},
+		{
+			name: "license texts",
+			tmpl: `{{range licenseIDs "MIT OR Foo"}}{{with licenseText .}}{{slice . 0 11}}{{else}}-{{end}};{{end}}`,
+			want: "MIT License;-;",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := format.Template(&buf, input, tc.tmpl); err != nil {
+				t.Fatalf("Template() unexpected error: %v", err)
+			}
+
+			if buf.String() != tc.want {
+				t.Errorf("Template() = %q, want %q", buf.String(), tc.want)
+			}
+		})
+	}
+}
+
+// TestTemplate_Errors tests Template error handling for invalid templates.
+func TestTemplate_Errors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		tmpl string
+	}{
+		{name: "parse error", tmpl: `{{range .}}`},
+		{name: "execute error", tmpl: `{{.Missing}}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := format.Template(&buf, []attribution.Attribution{}, tc.tmpl); err == nil {
+				t.Error("Template() expected error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// Package gobinextract provides extraction of attributions from the module metadata Go embeds in binaries
+// (see debug/buildinfo), for binaries that ship without an SBOM.
+package gobinextract
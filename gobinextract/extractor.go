@@ -0,0 +1,79 @@
+package gobinextract
+
+import (
+	"bytes"
+	"debug/buildinfo"
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// ParseBinary reads the build information embedded in a Go binary.
+// Returns an error if data is not a Go executable or was built without module support.
+func ParseBinary(data []byte) (*debug.BuildInfo, error) {
+	info, err := buildinfo.Read(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("read build info: %w", err)
+	}
+	return info, nil
+}
+
+// ExtractPackages extracts the dependencies of a Go binary as attributions, with golang purls synthesized from the
+// module paths and versions. The main module (the binary itself) is not included.
+// Replaced modules are reported as their replacement, since that is the code compiled into the binary.
+// Licenses are not recorded in build information, so they are left unset.
+func ExtractPackages(info *debug.BuildInfo) []attribution.Attribution {
+	if info == nil {
+		return []attribution.Attribution{}
+	}
+
+	packages := make([]attribution.Attribution, 0, len(info.Deps))
+
+	for _, dep := range info.Deps {
+		module := dep
+		if dep.Replace != nil {
+			module = dep.Replace
+		}
+
+		p := attribution.Attribution{
+			Name:    module.Path,
+			Version: module.Version,
+			Purl:    ModulePurl(module.Path, module.Version),
+		}
+
+		// URL generation is best-effort - local replacements without a purl get no URL
+		p = p.WithGeneratedURLs()
+
+		packages = append(packages, p)
+	}
+
+	return packages
+}
+
+// Subject returns the main module path of a Go binary, i.e. the program it was built from.
+// Returns an empty string if the build information does not record one.
+func Subject(info *debug.BuildInfo) string {
+	if info == nil {
+		return ""
+	}
+	return info.Main.Path
+}
+
+// ModulePurl returns the golang purl of a Go module, e.g. "pkg:golang/github.com/foo/bar@v1.2.3".
+// Returns an empty string for local filesystem paths (e.g. "../fork" in a replace directive).
+func ModulePurl(path, version string) string {
+	if path == "" || strings.HasPrefix(path, ".") || strings.HasPrefix(path, "/") {
+		return ""
+	}
+
+	namespace, name := "", path
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		namespace, name = path[:i], path[i+1:]
+	}
+
+	return packageurl.NewPackageURL(packageurl.TypeGolang, namespace, name, version, nil, "").ToString()
+}
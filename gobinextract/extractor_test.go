@@ -0,0 +1,133 @@
+package gobinextract_test
+
+import (
+	"os"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/boringbin/sbomattr/gobinextract"
+)
+
+// TestParseBinary tests reading the build information of the test binary itself.
+func TestParseBinary(t *testing.T) {
+	t.Parallel()
+
+	path, err := os.Executable()
+	if err != nil {
+		t.Fatalf("failed to locate test binary: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read test binary: %v", err)
+	}
+
+	info, err := gobinextract.ParseBinary(data)
+	if err != nil {
+		t.Fatalf("ParseBinary() unexpected error: %v", err)
+	}
+
+	// The package imports packageurl-go, so it must be a dependency
+	found := false
+	for _, a := range gobinextract.ExtractPackages(info) {
+		if a.Name == "github.com/package-url/packageurl-go" {
+			found = true
+			if !strings.HasPrefix(a.Purl, "pkg:golang/github.com/package-url/packageurl-go@v") {
+				t.Errorf("packageurl-go purl = %q, want a versioned golang purl", a.Purl)
+			}
+		}
+	}
+	if !found {
+		t.Error("ExtractPackages() did not include github.com/package-url/packageurl-go")
+	}
+}
+
+// TestParseBinary_NotGo tests that non-Go data is rejected.
+func TestParseBinary_NotGo(t *testing.T) {
+	t.Parallel()
+
+	if _, err := gobinextract.ParseBinary([]byte("\x7fELF not really")); err == nil {
+		t.Error("ParseBinary() error = nil, want error")
+	}
+}
+
+// TestExtractPackages tests extracting attributions from build information.
+func TestExtractPackages(t *testing.T) {
+	t.Parallel()
+
+	info := &debug.BuildInfo{
+		Main: debug.Module{Path: "example.com/app", Version: "(devel)"},
+		Deps: []*debug.Module{
+			{Path: "golang.org/x/text", Version: "v0.14.0"},
+			{
+				Path:    "github.com/old/lib",
+				Version: "v1.0.0",
+				Replace: &debug.Module{Path: "github.com/fork/lib", Version: "v1.0.1"},
+			},
+			{Path: "example.com/local", Version: "v0.0.0", Replace: &debug.Module{Path: "../local"}},
+		},
+	}
+
+	got := gobinextract.ExtractPackages(info)
+
+	if len(got) != 3 {
+		t.Fatalf("ExtractPackages() returned %d attributions, want 3", len(got))
+	}
+	if got[0].Name != "golang.org/x/text" || got[0].Version != "v0.14.0" ||
+		got[0].Purl != "pkg:golang/golang.org/x/text@v0.14.0" {
+		t.Errorf("ExtractPackages()[0] = %+v, want golang.org/x/text v0.14.0", got[0])
+	}
+	if got[0].URL == nil || got[0].License != nil {
+		t.Errorf("ExtractPackages()[0] URL = %v, License = %v, want a URL and no license", got[0].URL, got[0].License)
+	}
+	if got[1].Name != "github.com/fork/lib" || got[1].Purl != "pkg:golang/github.com/fork/lib@v1.0.1" {
+		t.Errorf("ExtractPackages()[1] = %+v, want the replacement github.com/fork/lib", got[1])
+	}
+	if got[2].Name != "../local" || got[2].Purl != "" || got[2].URL != nil {
+		t.Errorf("ExtractPackages()[2] = %+v, want a local replacement without purl", got[2])
+	}
+
+	if got = gobinextract.ExtractPackages(nil); len(got) != 0 {
+		t.Errorf("ExtractPackages(nil) = %v, want empty", got)
+	}
+}
+
+// TestModulePurl tests the ModulePurl function.
+func TestModulePurl(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path    string
+		version string
+		want    string
+	}{
+		{path: "github.com/foo/bar", version: "v1.2.3", want: "pkg:golang/github.com/foo/bar@v1.2.3"},
+		{path: "github.com/foo/bar/v2", version: "v2.0.0", want: "pkg:golang/github.com/foo/bar/v2@v2.0.0"},
+		{path: "gopkg.in/yaml.v3", version: "", want: "pkg:golang/gopkg.in/yaml.v3"},
+		{path: "../local", version: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			if got := gobinextract.ModulePurl(tt.path, tt.version); got != tt.want {
+				t.Errorf("ModulePurl(%q, %q) = %q, want %q", tt.path, tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSubject tests that the main module path is the subject of a Go binary.
+func TestSubject(t *testing.T) {
+	t.Parallel()
+
+	if got := gobinextract.Subject(nil); got != "" {
+		t.Errorf("Subject(nil) = %q, want empty", got)
+	}
+
+	info := &debug.BuildInfo{Main: debug.Module{Path: "example.com/app", Version: "v1.0.0"}}
+	if got := gobinextract.Subject(info); got != "example.com/app" {
+		t.Errorf("Subject() = %q, want %q", got, "example.com/app")
+	}
+}
@@ -0,0 +1,101 @@
+// Package cache provides the JSON file cache shared by the network lookups (enrichment, license text fetching, and
+// URL checks), so later runs only look up what is new or expired.
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+// version is the cache format version. Caches with another version are discarded on Load.
+const version = 1
+
+// Cache maps keys to entries of type T, each stamped with the time it was stored.
+// It is safe for concurrent use.
+type Cache[T any] struct {
+	mu      sync.Mutex
+	entries map[string]T
+	// stored returns when an entry was stored, for expiring it.
+	stored func(T) time.Time
+}
+
+// file is the on-disk layout of a Cache.
+type file[T any] struct {
+	Version int          `json:"version"`
+	Entries map[string]T `json:"entries"`
+}
+
+// New returns an empty cache whose entries were stored at the time returned by stored.
+func New[T any](stored func(T) time.Time) *Cache[T] {
+	return &Cache[T]{entries: make(map[string]T), stored: stored}
+}
+
+// Load reads a cache from a JSON file.
+// Returns an empty cache if the file does not exist or was written by an incompatible version of sbomattr, so the
+// first run looks everything up.
+func Load[T any](path string, stored func(T) time.Time) (*Cache[T], error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return New(stored), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cache: %w", err)
+	}
+
+	var f file[T]
+	if err = json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("decode cache: %w", err)
+	}
+	if f.Version != version || f.Entries == nil {
+		return New(stored), nil
+	}
+	return &Cache[T]{entries: f.Entries, stored: stored}, nil
+}
+
+// Save writes the cache to a JSON file, replacing it atomically.
+func (c *Cache[T]) Save(path string) error {
+	c.mu.Lock()
+	data, err := json.MarshalIndent(file[T]{Version: version, Entries: c.entries}, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("encode cache: %w", err)
+	}
+
+	// Write to a temporary file first so a failed write never leaves a truncated cache behind
+	tmp := path + ".tmp"
+	const filePerm = 0o644
+	if err = os.WriteFile(tmp, data, filePerm); err != nil {
+		return fmt.Errorf("write cache: %w", err)
+	}
+	if err = os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("write cache: %w", err)
+	}
+	return nil
+}
+
+// Lookup returns the cached entry of a key, unless it was stored more than maxAge before now (zero means cached
+// entries never expire).
+// Returns ok as false if the key is not cached or its entry expired.
+func (c *Cache[T]) Lookup(key string, maxAge time.Duration, now time.Time) (T, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || (maxAge > 0 && now.Sub(c.stored(e)) > maxAge) {
+		var zero T
+		return zero, false
+	}
+	return e, true
+}
+
+// Store records the entry of a key.
+func (c *Cache[T]) Store(key string, e T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = e
+}
@@ -0,0 +1,95 @@
+package cache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/boringbin/sbomattr/internal/cache"
+)
+
+// entry is a cached test value.
+type entry struct {
+	Value  string    `json:"value"`
+	Stored time.Time `json:"stored"`
+}
+
+// stored returns when an entry was stored.
+func stored(e entry) time.Time {
+	return e.Stored
+}
+
+// TestCache_SaveLoad tests that entries survive a save and load.
+func TestCache_SaveLoad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "cache.json")
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	c := cache.New(stored)
+	c.Store("a", entry{Value: "1", Stored: now})
+	if err := c.Save(path); err != nil {
+		t.Fatalf("Save() unexpected error: %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); err == nil {
+		t.Error("Save() left the temporary file behind")
+	}
+
+	loaded, err := cache.Load(path, stored)
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if e, ok := loaded.Lookup("a", 0, time.Now()); !ok || e.Value != "1" || !e.Stored.Equal(now) {
+		t.Errorf("Lookup(a) = %+v, %v, want the stored entry", e, ok)
+	}
+	if _, ok := loaded.Lookup("b", 0, time.Now()); ok {
+		t.Error("Lookup(b) found an entry that was never stored")
+	}
+}
+
+// TestCache_Lookup_Expired tests that entries older than the maximum age are not reused.
+func TestCache_Lookup_Expired(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	c := cache.New(stored)
+	c.Store("a", entry{Value: "1", Stored: now})
+
+	if _, ok := c.Lookup("a", time.Hour, now.Add(30*time.Minute)); !ok {
+		t.Error("Lookup() within max age found nothing")
+	}
+	if _, ok := c.Lookup("a", time.Hour, now.Add(2*time.Hour)); ok {
+		t.Error("Lookup() after max age found an expired entry")
+	}
+}
+
+// TestLoad tests that a missing or outdated file loads as an empty cache, and an invalid file is an error.
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"outdated.json": `{"version": 0, "entries": {"a": {"value": "1"}}}`,
+		"old.json":      `{"version": 1, "results": {"a": {"value": "1"}}}`,
+		"invalid.json":  `not JSON`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{"missing.json", "outdated.json", "old.json"} {
+		c, err := cache.Load(filepath.Join(dir, name), stored)
+		if err != nil {
+			t.Fatalf("Load(%s) unexpected error: %v", name, err)
+		}
+		if _, ok := c.Lookup("a", 0, time.Now()); ok {
+			t.Errorf("Load(%s) returned a non-empty cache", name)
+		}
+	}
+	if _, err := cache.Load(filepath.Join(dir, "invalid.json"), stored); err == nil {
+		t.Error("Load(invalid.json) expected an error")
+	}
+}
@@ -0,0 +1,258 @@
+// Package github provides a small GitHub REST API client shared by the GitHub integrations. It handles token
+// authentication, primary and secondary rate limits (waiting for Retry-After or the rate limit reset), retries of
+// transient server errors, and pagination.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the base URL of the public GitHub REST API.
+const DefaultBaseURL = "https://api.github.com"
+
+// defaultSecondaryRateLimitWait is how long to wait after hitting a secondary rate limit without a Retry-After header,
+// as recommended by the GitHub documentation. It doubles with each retry.
+const defaultSecondaryRateLimitWait = time.Minute
+
+// maxPeekBytes is how much of the body of a 403 response is read to tell a secondary rate limit from a permission
+// error.
+const maxPeekBytes = 1024
+
+var (
+	// ErrNotFound is returned when the requested resource does not exist (or is not visible to the token).
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited is returned when a rate limit would require waiting longer than Client.MaxWait, or persists after
+	// all retries.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrUnexpectedStatus is returned when the API responds with an unexpected status code.
+	ErrUnexpectedStatus = errors.New("unexpected response status")
+)
+
+// Client is a GitHub REST API client.
+type Client struct {
+	// BaseURL is the API base URL, without a trailing slash. Defaults to DefaultBaseURL.
+	BaseURL string
+	// Token is the optional token sent as a Bearer token. Unauthenticated requests have much lower rate limits.
+	Token string
+	// HTTPClient is the HTTP client used for requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// MaxRetries is the number of times a rate-limited or failed (5xx) request is retried.
+	MaxRetries int
+	// RetryBackoff is the wait before the first retry of a failed (5xx) request; it doubles on each retry.
+	RetryBackoff time.Duration
+	// MaxWait is the longest the client waits for a rate limit to reset before giving up with ErrRateLimited.
+	MaxWait time.Duration
+	// Logger is optional; set to nil to disable logging.
+	Logger *slog.Logger
+}
+
+// NewClient returns a client for the public GitHub API authenticating with token (which may be empty), retrying
+// 3 times and waiting at most 15 minutes for rate limits to reset.
+func NewClient(token string) *Client {
+	const (
+		defaultMaxRetries   = 3
+		defaultRetryBackoff = time.Second
+		defaultMaxWait      = 15 * time.Minute
+	)
+	return &Client{
+		BaseURL:      DefaultBaseURL,
+		Token:        token,
+		MaxRetries:   defaultMaxRetries,
+		RetryBackoff: defaultRetryBackoff,
+		MaxWait:      defaultMaxWait,
+	}
+}
+
+// Get fetches path (relative to BaseURL, or an absolute URL) and decodes the JSON response into v.
+// Returns ErrNotFound for 404 responses.
+func (c *Client) Get(ctx context.Context, path string, v any) error {
+	_, err := c.get(ctx, c.url(path), v)
+	return err
+}
+
+// GetAll fetches every page of a list endpoint, following the Link rel="next" headers, and returns all items.
+func GetAll[T any](ctx context.Context, c *Client, path string) ([]T, error) {
+	var all []T
+	next := c.url(path)
+
+	for next != "" {
+		var page []T
+		resp, err := c.get(ctx, next, &page)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		next = nextPageURL(resp.Header.Get("Link"))
+	}
+
+	return all, nil
+}
+
+// get performs a GET request with retries and decodes the JSON response into v.
+// The returned response has an already closed body; only its headers can be used.
+func (c *Client) get(ctx context.Context, url string, v any) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.do(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+
+		wait, retry := c.retryWait(resp, attempt)
+		if !retry {
+			return resp, c.decode(resp, v)
+		}
+		_ = resp.Body.Close()
+
+		if attempt >= c.MaxRetries {
+			if resp.StatusCode >= http.StatusInternalServerError {
+				return nil, fmt.Errorf("%w: %s", ErrUnexpectedStatus, resp.Status)
+			}
+			return nil, ErrRateLimited
+		}
+		if c.MaxWait > 0 && wait > c.MaxWait {
+			return nil, fmt.Errorf("%w: reset in %s", ErrRateLimited, wait.Round(time.Second))
+		}
+
+		if c.Logger != nil {
+			c.Logger.WarnContext(ctx, "retrying GitHub API request", "url", url, "status", resp.StatusCode,
+				"wait", wait, "attempt", attempt+1)
+		}
+		if err = sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// do sends an authenticated GET request.
+func (c *Client) do(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", url, err)
+	}
+	return resp, nil
+}
+
+// retryWait reports whether a response should be retried, and how long to wait first.
+// Rate-limited responses (429, 403 with rate limit headers, or 403 whose body tells of a secondary rate limit) wait
+// for Retry-After or the rate limit reset, or else back off exponentially from a minute; server errors back off
+// exponentially.
+func (c *Client) retryWait(resp *http.Response, attempt int) (time.Duration, bool) {
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusForbidden:
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+			if err != nil {
+				return defaultSecondaryRateLimitWait, true
+			}
+			return max(time.Until(time.Unix(reset, 0)), 0), true
+		}
+		if resp.StatusCode == http.StatusTooManyRequests || isSecondaryRateLimit(resp) {
+			return defaultSecondaryRateLimitWait << attempt, true
+		}
+		// Any other 403 is a permission error, not a rate limit
+		return 0, false
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return c.RetryBackoff << attempt, true
+	default:
+		return 0, false
+	}
+}
+
+// isSecondaryRateLimit reports whether the body of a response tells of a secondary rate limit, which GitHub may
+// signal with a plain 403 without rate limit headers, e.g. "You have exceeded a secondary rate limit". The start of
+// the body is read, and the body is left readable from its start.
+func isSecondaryRateLimit(resp *http.Response) bool {
+	peek, _ := io.ReadAll(io.LimitReader(resp.Body, maxPeekBytes))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(peek), resp.Body), resp.Body}
+
+	message := strings.ToLower(string(peek))
+	return strings.Contains(message, "secondary rate limit") || strings.Contains(message, "abuse detection")
+}
+
+// decode checks the response status and decodes the JSON body into v, closing the body.
+func (c *Client) decode(resp *http.Response, v any) error {
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%w: %s", ErrNotFound, resp.Request.URL)
+	case resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%w: %s: %s", ErrUnexpectedStatus, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// url resolves path against the base URL. Absolute URLs are returned as is.
+func (c *Client) url(path string) string {
+	if strings.HasPrefix(path, "https://") || strings.HasPrefix(path, "http://") {
+		return path
+	}
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// nextPageURL returns the rel="next" URL of a Link header, or an empty string if there is no next page.
+func nextPageURL(link string) string {
+	for part := range strings.SplitSeq(link, ",") {
+		target, params, ok := strings.Cut(part, ";")
+		if !ok || !strings.Contains(params, `rel="next"`) {
+			continue
+		}
+		return strings.Trim(strings.TrimSpace(target), "<>")
+	}
+	return ""
+}
+
+// sleep waits for d, or until the context is canceled.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,218 @@
+package github_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/boringbin/sbomattr/internal/github"
+)
+
+// newTestClient returns a client for the test server with fast retries.
+func newTestClient(server *httptest.Server) *github.Client {
+	c := github.NewClient("test-token")
+	c.BaseURL = server.URL
+	c.HTTPClient = server.Client()
+	c.RetryBackoff = time.Millisecond
+	return c
+}
+
+// TestClient_Get tests a simple authenticated request.
+func TestClient_Get(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("Authorization = %q, want Bearer test-token", got)
+		}
+		if r.URL.Path != "/repos/octo/hello" {
+			t.Errorf("path = %q, want /repos/octo/hello", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"full_name": "octo/hello"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	var repo struct {
+		FullName string `json:"full_name"`
+	}
+	if err := newTestClient(server).Get(context.Background(), "/repos/octo/hello", &repo); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if repo.FullName != "octo/hello" {
+		t.Errorf("FullName = %q, want octo/hello", repo.FullName)
+	}
+}
+
+// TestClient_GetNotFound tests that 404 responses return ErrNotFound.
+func TestClient_GetNotFound(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+
+	var v any
+	if err := newTestClient(server).Get(context.Background(), "/missing", &v); !errors.Is(err, github.ErrNotFound) {
+		t.Errorf("Get() error = %v, want ErrNotFound", err)
+	}
+}
+
+// TestClient_GetPermissionDenied tests that a plain 403 is not retried, and its message is reported.
+func TestClient_GetPermissionDenied(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		http.Error(w, `{"message": "Resource not accessible"}`, http.StatusForbidden)
+	}))
+	t.Cleanup(server.Close)
+
+	var v any
+	err := newTestClient(server).Get(context.Background(), "/private", &v)
+	if !errors.Is(err, github.ErrUnexpectedStatus) || !strings.Contains(err.Error(), "Resource not accessible") {
+		t.Errorf("Get() error = %v, want ErrUnexpectedStatus with the message", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("server called %d times, want 1", calls.Load())
+	}
+}
+
+// TestClient_RateLimits tests that rate-limited and failed requests are retried.
+func TestClient_RateLimits(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		respond func(w http.ResponseWriter)
+	}{
+		{
+			name: "secondary rate limit with retry-after",
+			respond: func(w http.ResponseWriter) {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusForbidden)
+			},
+		},
+		{
+			name: "primary rate limit",
+			respond: func(w http.ResponseWriter) {
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(-time.Second).Unix(), 10))
+				w.WriteHeader(http.StatusForbidden)
+			},
+		},
+		{
+			name: "server error",
+			respond: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if calls.Add(1) == 1 {
+					tt.respond(w)
+					return
+				}
+				fmt.Fprint(w, `{}`)
+			}))
+			t.Cleanup(server.Close)
+
+			var v any
+			if err := newTestClient(server).Get(context.Background(), "/x", &v); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if calls.Load() != 2 {
+				t.Errorf("server called %d times, want 2", calls.Load())
+			}
+		})
+	}
+}
+
+// TestClient_SecondaryRateLimitBody tests that a 403 without rate limit headers whose body tells of a secondary rate
+// limit is retried after at least a minute, rather than reported as a permission error.
+func TestClient_SecondaryRateLimitBody(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message": "You have exceeded a secondary rate limit. Please wait a few minutes before you `+
+			`try again.", "documentation_url": "https://docs.github.com/rest/overview/rate-limits-for-the-rest-api"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	c := newTestClient(server)
+	c.MaxWait = time.Minute - time.Second
+
+	var v any
+	err := c.Get(context.Background(), "/x", &v)
+	if !errors.Is(err, github.ErrRateLimited) || !strings.Contains(err.Error(), "1m0s") {
+		t.Errorf("Get() error = %v, want ErrRateLimited with a one minute wait", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("server called %d times, want 1", calls.Load())
+	}
+}
+
+// TestClient_RateLimitTooLong tests that the client gives up when the rate limit resets after MaxWait.
+func TestClient_RateLimitTooLong(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(server.Close)
+
+	c := newTestClient(server)
+	c.MaxWait = time.Minute
+
+	var v any
+	if err := c.Get(context.Background(), "/x", &v); !errors.Is(err, github.ErrRateLimited) {
+		t.Errorf("Get() error = %v, want ErrRateLimited", err)
+	}
+}
+
+// TestGetAll tests following pagination links.
+func TestGetAll(t *testing.T) {
+	t.Parallel()
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/items?page=2>; rel="next", <%s/items?page=2>; rel="last"`,
+				server.URL, server.URL))
+			fmt.Fprint(w, `[1, 2]`)
+		case "2":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/items>; rel="prev", <%s/items>; rel="first"`,
+				server.URL, server.URL))
+			fmt.Fprint(w, `[3]`)
+		default:
+			t.Errorf("unexpected page %q", r.URL.Query().Get("page"))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	items, err := github.GetAll[int](context.Background(), newTestClient(server), "/items")
+	if err != nil {
+		t.Fatalf("GetAll() error = %v", err)
+	}
+	if len(items) != 3 || items[0] != 1 || items[2] != 3 {
+		t.Errorf("GetAll() = %v, want [1 2 3]", items)
+	}
+}
@@ -0,0 +1,72 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+)
+
+// Repository is a GitHub repository, as returned by the repository list endpoints.
+type Repository struct {
+	// Name is the repository name, e.g. "hello-world".
+	Name string `json:"name"`
+	// FullName is the owner and name, e.g. "octo/hello-world".
+	FullName string `json:"full_name"`
+	// Fork reports whether the repository is a fork.
+	Fork bool `json:"fork"`
+	// Archived reports whether the repository is archived.
+	Archived bool `json:"archived"`
+	// Visibility is "public", "private", or "internal".
+	Visibility string `json:"visibility"`
+}
+
+// RepositoryLicense is the license file of a repository detected by GitHub, as returned by the repository license
+// endpoint.
+type RepositoryLicense struct {
+	// HTMLURL is the web page of the license file.
+	HTMLURL string `json:"html_url"`
+	// License is the detected license.
+	License License `json:"license"`
+}
+
+// License is a license detected by GitHub.
+type License struct {
+	// Key is the GitHub license key, e.g. "mit", or "other" for unrecognized licenses.
+	Key string `json:"key"`
+	// Name is the license name, e.g. "MIT License".
+	Name string `json:"name"`
+	// SPDXID is the SPDX license ID, e.g. "MIT", or "NOASSERTION" for unrecognized licenses.
+	SPDXID string `json:"spdx_id"`
+}
+
+// OrgRepositories returns every repository of an organization visible to the token.
+func (c *Client) OrgRepositories(ctx context.Context, org string) ([]Repository, error) {
+	return GetAll[Repository](ctx, c, "/orgs/"+url.PathEscape(org)+"/repos?type=all&per_page=100")
+}
+
+// DependencyGraphSBOM returns the SPDX SBOM GitHub generates from a repository's dependency graph, as the raw
+// GitHub-wrapped JSON ({"sbom": {...}}) accepted by sbomattr.Process.
+// Returns ErrNotFound if the repository does not exist or its dependency graph is disabled.
+func (c *Client) DependencyGraphSBOM(ctx context.Context, fullName string) ([]byte, error) {
+	var raw json.RawMessage
+	if err := c.Get(ctx, "/repos/"+fullName+"/dependency-graph/sbom", &raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// License returns the license file GitHub detects in a repository at ref (a branch, tag, or commit), or on the default
+// branch if ref is empty.
+// Returns ErrNotFound if the repository or ref does not exist, or has no license file.
+func (c *Client) License(ctx context.Context, fullName, ref string) (RepositoryLicense, error) {
+	path := "/repos/" + fullName + "/license"
+	if ref != "" {
+		path += "?ref=" + url.QueryEscape(ref)
+	}
+
+	var license RepositoryLicense
+	if err := c.Get(ctx, path, &license); err != nil {
+		return RepositoryLicense{}, err
+	}
+	return license, nil
+}
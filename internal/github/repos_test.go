@@ -0,0 +1,91 @@
+package github_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/boringbin/sbomattr/internal/github"
+)
+
+// TestClient_OrgRepositories tests listing the repositories of an organization.
+func TestClient_OrgRepositories(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/orgs/octo/repos" {
+			t.Errorf("path = %q, want /orgs/octo/repos", r.URL.Path)
+		}
+		fmt.Fprint(w, `[{"name": "a", "full_name": "octo/a", "fork": true, "visibility": "public"}]`)
+	}))
+	t.Cleanup(server.Close)
+
+	repos, err := newTestClient(server).OrgRepositories(context.Background(), "octo")
+	if err != nil {
+		t.Fatalf("OrgRepositories() error = %v", err)
+	}
+	if len(repos) != 1 || repos[0].FullName != "octo/a" || !repos[0].Fork || repos[0].Visibility != "public" {
+		t.Errorf("OrgRepositories() = %+v, want the octo/a fork", repos)
+	}
+}
+
+// TestClient_DependencyGraphSBOM tests fetching a repository's dependency graph SBOM.
+func TestClient_DependencyGraphSBOM(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/octo/a/dependency-graph/sbom" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"sbom": {"spdxVersion": "SPDX-2.3"}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	client := newTestClient(server)
+
+	data, err := client.DependencyGraphSBOM(context.Background(), "octo/a")
+	if err != nil {
+		t.Fatalf("DependencyGraphSBOM() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"spdxVersion": "SPDX-2.3"`) {
+		t.Errorf("DependencyGraphSBOM() = %s, want the raw SBOM", data)
+	}
+
+	if _, err = client.DependencyGraphSBOM(context.Background(), "octo/b"); !errors.Is(err, github.ErrNotFound) {
+		t.Errorf("DependencyGraphSBOM(octo/b) error = %v, want ErrNotFound", err)
+	}
+}
+
+// TestClient_License tests fetching the license GitHub detects in a repository at a ref.
+func TestClient_License(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/octo/a/license" || r.URL.Query().Get("ref") != "v1.0.0" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"html_url": "https://github.com/octo/a/blob/v1.0.0/LICENSE", `+
+			`"license": {"key": "mit", "name": "MIT License", "spdx_id": "MIT"}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	client := newTestClient(server)
+
+	license, err := client.License(context.Background(), "octo/a", "v1.0.0")
+	if err != nil {
+		t.Fatalf("License() error = %v", err)
+	}
+	if license.License.SPDXID != "MIT" || license.HTMLURL != "https://github.com/octo/a/blob/v1.0.0/LICENSE" {
+		t.Errorf("License() = %+v, want the MIT license file", license)
+	}
+
+	if _, err = client.License(context.Background(), "octo/a", "v2.0.0"); !errors.Is(err, github.ErrNotFound) {
+		t.Errorf("License(v2.0.0) error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,415 @@
+// Package oci provides a minimal client for OCI distribution registries, to download the SBOMs attached to container
+// images. SBOMs are found with the referrers API (e.g. attached with `oras attach`), or with the tag scheme of
+// `cosign attach sbom` (sha256-<digest>.sbom). Registries requiring token authentication (Docker Hub, GHCR, ...) are
+// supported anonymously or with a username and password.
+package oci
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	// maxManifestBytes is the size limit of a downloaded manifest or index.
+	maxManifestBytes = 4 << 20
+	// maxBlobBytes is the size limit of a downloaded SBOM.
+	maxBlobBytes = 256 << 20
+	// maxTokenBytes is the size limit of a token response.
+	maxTokenBytes = 1 << 20
+)
+
+var (
+	// ErrNotFound is returned when the image does not exist (or is not visible to the credentials).
+	ErrNotFound = errors.New("not found")
+	// ErrNoSBOM is returned when no SBOM is attached to the image.
+	ErrNoSBOM = errors.New("no SBOM attached to the image")
+	// ErrUnauthorized is returned when the registry rejects the credentials, or requires some and none are set.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrUnexpectedStatus is returned when the registry responds with an unexpected status code.
+	ErrUnexpectedStatus = errors.New("unexpected response status")
+)
+
+// mediaTypeIndex is the media type of OCI image indexes, which the referrers API responds with.
+const mediaTypeIndex = "application/vnd.oci.image.index.v1+json"
+
+// manifestMediaTypes returns the media types of the image manifests and indexes the client accepts.
+func manifestMediaTypes() []string {
+	return []string{
+		mediaTypeIndex,
+		"application/vnd.oci.image.manifest.v1+json",
+		"application/vnd.docker.distribution.manifest.list.v2+json",
+		"application/vnd.docker.distribution.manifest.v2+json",
+	}
+}
+
+// sbomMediaTypes returns the media types of the JSON SBOMs the client downloads.
+func sbomMediaTypes() []string {
+	return []string{"application/spdx+json", "text/spdx+json", "application/vnd.cyclonedx+json"}
+}
+
+// Client downloads the SBOMs attached to images. The zero value is ready to use.
+type Client struct {
+	// HTTPClient is the HTTP client used for requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// Username and Password are the optional credentials sent to the registry, or to its token service.
+	Username string
+	Password string
+	// PlainHTTP talks to registries over HTTP instead of HTTPS, e.g. for a local registry.
+	PlainHTTP bool
+	// Logger is optional; set to nil to disable logging.
+	Logger *slog.Logger
+
+	// mu guards auth.
+	mu sync.Mutex
+	// auth holds the Authorization header of each repository ("<registry>/<repository>") that required one.
+	auth map[string]string
+}
+
+// SBOM is an SBOM attached to an image.
+type SBOM struct {
+	// MediaType is the media type of the SBOM, e.g. "application/spdx+json".
+	MediaType string
+	// Digest is the digest of the SBOM blob.
+	Digest string
+	// Data is the SBOM.
+	Data []byte
+}
+
+// descriptor describes a manifest or blob in a manifest or index.
+type descriptor struct {
+	MediaType    string `json:"mediaType"`
+	ArtifactType string `json:"artifactType"`
+	Digest       string `json:"digest"`
+}
+
+// manifest is an image manifest (with layers) or an index (with manifests).
+type manifest struct {
+	Layers    []descriptor `json:"layers"`
+	Manifests []descriptor `json:"manifests"`
+}
+
+// SBOMs returns the JSON SBOMs attached to the image: those of its referrers with an SBOM artifact type, or, if there
+// are none, those attached by `cosign attach sbom`. Returns ErrNoSBOM if there are neither.
+func (c *Client) SBOMs(ctx context.Context, ref Reference) ([]SBOM, error) {
+	digest := ref.Digest
+	if digest == "" {
+		data, header, err := c.get(ctx, ref, "manifests/"+ref.Tag, manifestMediaTypes(), maxManifestBytes)
+		if err != nil {
+			return nil, fmt.Errorf("resolve %s: %w", ref, err)
+		}
+		digest = header.Get("Docker-Content-Digest")
+		if digest == "" {
+			sum := sha256.Sum256(data)
+			digest = "sha256:" + hex.EncodeToString(sum[:])
+		}
+	}
+	if c.Logger != nil {
+		c.Logger.DebugContext(ctx, "looking for SBOMs attached to image", "image", ref.String(), "digest", digest)
+	}
+
+	sboms, err := c.referrerSBOMs(ctx, ref, digest)
+	if err != nil {
+		return nil, err
+	}
+	if len(sboms) == 0 {
+		if sboms, err = c.cosignSBOMs(ctx, ref, digest); err != nil {
+			return nil, err
+		}
+	}
+	if len(sboms) == 0 {
+		return nil, fmt.Errorf("%s: %w", ref, ErrNoSBOM)
+	}
+	return sboms, nil
+}
+
+// referrerSBOMs returns the SBOMs of the referrers of the manifest with digest whose artifact type is an SBOM media
+// type. Registries without the referrers API have none.
+func (c *Client) referrerSBOMs(ctx context.Context, ref Reference, digest string) ([]SBOM, error) {
+	var index manifest
+	err := c.getJSON(ctx, ref, "referrers/"+digest, []string{mediaTypeIndex}, &index)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("list referrers of %s: %w", ref, err)
+	}
+
+	var sboms []SBOM
+	for _, referrer := range index.Manifests {
+		if !slices.Contains(sbomMediaTypes(), referrer.ArtifactType) {
+			continue
+		}
+		var m manifest
+		if err = c.getJSON(ctx, ref, "manifests/"+referrer.Digest, manifestMediaTypes(), &m); err != nil {
+			return nil, fmt.Errorf("get referrer %s: %w", referrer.Digest, err)
+		}
+		// The layers of an SBOM artifact are the SBOM, whatever their media type
+		layerSBOMs, layerErr := c.layerSBOMs(ctx, ref, m.Layers, referrer.ArtifactType)
+		if layerErr != nil {
+			return nil, layerErr
+		}
+		sboms = append(sboms, layerSBOMs...)
+	}
+	return sboms, nil
+}
+
+// cosignSBOMs returns the SBOMs attached to the manifest with digest by `cosign attach sbom`, which pushes them as
+// the layers of the sha256-<hex>.sbom tag.
+func (c *Client) cosignSBOMs(ctx context.Context, ref Reference, digest string) ([]SBOM, error) {
+	tag := strings.Replace(digest, ":", "-", 1) + ".sbom"
+	var m manifest
+	err := c.getJSON(ctx, ref, "manifests/"+tag, manifestMediaTypes(), &m)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get cosign SBOM manifest %s: %w", tag, err)
+	}
+	return c.layerSBOMs(ctx, ref, m.Layers, "")
+}
+
+// layerSBOMs downloads the layers with an SBOM media type, or every layer with mediaType as their media type if it
+// is set.
+func (c *Client) layerSBOMs(ctx context.Context, ref Reference, layers []descriptor, mediaType string) ([]SBOM, error) {
+	var sboms []SBOM
+	for _, layer := range layers {
+		layerType := mediaType
+		if layerType == "" {
+			if !slices.Contains(sbomMediaTypes(), layer.MediaType) {
+				continue
+			}
+			layerType = layer.MediaType
+		}
+		data, _, err := c.get(ctx, ref, "blobs/"+layer.Digest, nil, maxBlobBytes)
+		if err != nil {
+			return nil, fmt.Errorf("get SBOM blob %s: %w", layer.Digest, err)
+		}
+		if err = verifyDigest(data, layer.Digest); err != nil {
+			return nil, err
+		}
+		sboms = append(sboms, SBOM{MediaType: layerType, Digest: layer.Digest, Data: data})
+	}
+	return sboms, nil
+}
+
+// verifyDigest checks that data matches a sha256 digest. Digests of other algorithms are not verified.
+func verifyDigest(data []byte, digest string) error {
+	want, ok := strings.CutPrefix(digest, "sha256:")
+	if !ok {
+		return nil
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return fmt.Errorf("blob %s has digest sha256:%s", digest, got)
+	}
+	return nil
+}
+
+// getJSON gets path in the repository of ref like get, and decodes the JSON response into v.
+func (c *Client) getJSON(ctx context.Context, ref Reference, path string, accept []string, v any) error {
+	data, _, err := c.get(ctx, ref, path, accept, maxManifestBytes)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decode %s: %w", path, err)
+	}
+	return nil
+}
+
+// get gets path (e.g. "manifests/<tag>") in the repository of ref, authenticating as the registry requires, and
+// returns the body of the response, up to limit bytes, and its headers. Returns ErrNotFound for 404 responses.
+func (c *Client) get(
+	ctx context.Context,
+	ref Reference,
+	path string,
+	accept []string,
+	limit int64,
+) ([]byte, http.Header, error) {
+	scheme := "https"
+	if c.PlainHTTP {
+		scheme = "http"
+	}
+	u := scheme + "://" + ref.host() + "/v2/" + ref.Repository + "/" + path
+
+	resp, err := c.do(ctx, ref, u, accept)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		_ = resp.Body.Close()
+		if err = c.authenticate(ctx, ref, challenge); err != nil {
+			return nil, nil, err
+		}
+		if resp, err = c.do(ctx, ref, u, accept); err != nil {
+			return nil, nil, err
+		}
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil, ErrNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnauthorized, resp.Status)
+	default:
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnexpectedStatus, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, nil, fmt.Errorf("read %s: %w", u, err)
+	}
+	if int64(len(data)) > limit {
+		return nil, nil, fmt.Errorf("read %s: larger than %d bytes", u, limit)
+	}
+	return data, resp.Header, nil
+}
+
+// do sends a GET request, with the Authorization header of the repository of ref if it required one.
+func (c *Client) do(ctx context.Context, ref Reference, u string, accept []string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	if len(accept) > 0 {
+		req.Header.Set("Accept", strings.Join(accept, ", "))
+	}
+	c.mu.Lock()
+	auth := c.auth[ref.Registry+"/"+ref.Repository]
+	c.mu.Unlock()
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("get %s: %w", u, err)
+	}
+	return resp, nil
+}
+
+// authenticate answers the WWW-Authenticate challenge of the registry for the repository of ref: with the
+// credentials for Basic authentication, or with a token from the token service for Bearer authentication.
+func (c *Client) authenticate(ctx context.Context, ref Reference, challenge string) error {
+	scheme, params := parseChallenge(challenge)
+	var auth string
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if c.Username == "" {
+			return fmt.Errorf("%w: %s requires credentials", ErrUnauthorized, ref.Registry)
+		}
+		auth = "Basic " + base64.StdEncoding.EncodeToString([]byte(c.Username+":"+c.Password))
+	case "bearer":
+		token, err := c.token(ctx, ref, params)
+		if err != nil {
+			return err
+		}
+		auth = "Bearer " + token
+	default:
+		return fmt.Errorf("%w: unsupported authentication challenge %q", ErrUnauthorized, challenge)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.auth == nil {
+		c.auth = make(map[string]string)
+	}
+	c.auth[ref.Registry+"/"+ref.Repository] = auth
+	return nil
+}
+
+// token requests a token to pull the repository of ref from the token service of a Bearer challenge, authenticating
+// with the credentials if they are set.
+func (c *Client) token(ctx context.Context, ref Reference, params map[string]string) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Scheme == "" {
+		return "", fmt.Errorf("%w: invalid token realm %q", ErrUnauthorized, params["realm"])
+	}
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	query.Set("scope", "repository:"+ref.Repository+":pull")
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("create token request: %w", err)
+	}
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("get token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: token service responded %s", ErrUnauthorized, resp.Status)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxTokenBytes))
+	if err != nil {
+		return "", fmt.Errorf("read token: %w", err)
+	}
+	if err = json.Unmarshal(data, &body); err != nil {
+		return "", fmt.Errorf("decode token: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	if body.AccessToken != "" {
+		return body.AccessToken, nil
+	}
+	return "", fmt.Errorf("%w: token service returned no token", ErrUnauthorized)
+}
+
+// httpClient returns the HTTP client used for requests.
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// parseChallenge parses a WWW-Authenticate challenge such as `Bearer realm="https://ghcr.io/token",service="ghcr.io"`
+// into its scheme and parameters.
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := make(map[string]string)
+	for rest != "" {
+		var key string
+		key, rest, _ = strings.Cut(rest, "=")
+		key = strings.ToLower(strings.TrimSpace(strings.TrimLeft(key, ", ")))
+
+		var value string
+		if quoted, ok := strings.CutPrefix(rest, `"`); ok {
+			value, rest, _ = strings.Cut(quoted, `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		if key != "" {
+			params[key] = strings.TrimSpace(value)
+		}
+	}
+	return scheme, params
+}
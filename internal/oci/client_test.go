@@ -0,0 +1,181 @@
+package oci_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/boringbin/sbomattr/internal/oci"
+)
+
+// registry is a fake registry serving the acme/app repository.
+type registry struct {
+	// manifests are the manifests by tag or digest.
+	manifests map[string][]byte
+	// blobs are the blobs by digest.
+	blobs map[string][]byte
+	// referrers are the referrers indexes by subject digest. Without any, the referrers API is not supported.
+	referrers map[string][]byte
+	// token, if set, is the Bearer token required by the registry, issued by /token to user:secret.
+	token string
+}
+
+// digest returns the sha256 digest of data.
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// marshal returns the JSON encoding of v.
+func marshal(t *testing.T, v any) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to marshal %v: %v", v, err)
+	}
+	return data
+}
+
+// serve starts the registry and returns the reference of acme/app with the tag.
+func (r *registry) serve(t *testing.T, tag string) oci.Reference {
+	t.Helper()
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/token" {
+			if user, password, ok := req.BasicAuth(); !ok || user != "user" || password != "secret" ||
+				req.URL.Query().Get("scope") != "repository:acme/app:pull" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"token": "` + r.token + `"}`))
+			return
+		}
+		if r.token != "" && req.Header.Get("Authorization") != "Bearer "+r.token {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		kind, name, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, "/v2/acme/app/"), "/")
+		content, ok := map[string]map[string][]byte{
+			"manifests": r.manifests, "blobs": r.blobs, "referrers": r.referrers,
+		}[kind][name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(content)
+	}))
+	t.Cleanup(server.Close)
+
+	return oci.Reference{Registry: strings.TrimPrefix(server.URL, "http://"), Repository: "acme/app", Tag: tag}
+}
+
+func TestClient_SBOMs_Referrers(t *testing.T) {
+	t.Parallel()
+
+	image := []byte(`{"schemaVersion": 2, "layers": []}`)
+	sbom := []byte(`{"spdxVersion": "SPDX-2.3"}`)
+	sbomManifest := marshal(t, map[string]any{
+		"artifactType": "application/spdx+json",
+		"layers":       []map[string]string{{"mediaType": "application/json", "digest": digest(sbom)}},
+	})
+	signatureManifest := []byte(`{"layers": []}`)
+	index := marshal(t, map[string]any{"manifests": []map[string]string{
+		{"artifactType": "application/vnd.dev.cosign.artifact.sig.v1+json", "digest": digest(signatureManifest)},
+		{"artifactType": "application/spdx+json", "digest": digest(sbomManifest)},
+	}})
+
+	r := &registry{
+		manifests: map[string][]byte{"1.0": image, digest(sbomManifest): sbomManifest},
+		blobs:     map[string][]byte{digest(sbom): sbom},
+		referrers: map[string][]byte{digest(image): index},
+		token:     "t0k3n",
+	}
+	ref := r.serve(t, "1.0")
+
+	client := &oci.Client{Username: "user", Password: "secret", PlainHTTP: true}
+	sboms, err := client.SBOMs(context.Background(), ref)
+	if err != nil {
+		t.Fatalf("SBOMs() unexpected error: %v", err)
+	}
+	if len(sboms) != 1 || string(sboms[0].Data) != string(sbom) || sboms[0].MediaType != "application/spdx+json" {
+		t.Errorf("SBOMs() = %+v, want the SPDX referrer", sboms)
+	}
+
+	// Without credentials, the token service rejects the request
+	if _, err = (&oci.Client{PlainHTTP: true}).SBOMs(context.Background(), ref); !errors.Is(err, oci.ErrUnauthorized) {
+		t.Errorf("SBOMs() without credentials error = %v, want %v", err, oci.ErrUnauthorized)
+	}
+}
+
+func TestClient_SBOMs_Cosign(t *testing.T) {
+	t.Parallel()
+
+	image := []byte(`{"schemaVersion": 2, "layers": []}`)
+	sbom := []byte(`{"bomFormat": "CycloneDX"}`)
+	other := []byte(`<bom/>`)
+	sbomManifest := marshal(t, map[string]any{"layers": []map[string]string{
+		{"mediaType": "application/vnd.cyclonedx+xml", "digest": digest(other)},
+		{"mediaType": "application/vnd.cyclonedx+json", "digest": digest(sbom)},
+	}})
+	tag := strings.Replace(digest(image), ":", "-", 1) + ".sbom"
+
+	r := &registry{
+		manifests: map[string][]byte{digest(image): image, tag: sbomManifest},
+		blobs:     map[string][]byte{digest(sbom): sbom, digest(other): other},
+	}
+	ref := r.serve(t, "")
+	ref.Digest = digest(image)
+
+	sboms, err := (&oci.Client{PlainHTTP: true}).SBOMs(context.Background(), ref)
+	if err != nil {
+		t.Fatalf("SBOMs() unexpected error: %v", err)
+	}
+	if len(sboms) != 1 || string(sboms[0].Data) != string(sbom) || sboms[0].Digest != digest(sbom) {
+		t.Errorf("SBOMs() = %+v, want the CycloneDX JSON layer", sboms)
+	}
+}
+
+func TestClient_SBOMs_Errors(t *testing.T) {
+	t.Parallel()
+
+	image := []byte(`{"schemaVersion": 2, "layers": []}`)
+	corrupt := []byte(`{"bomFormat": "CycloneDX"}`)
+	corruptImage := []byte(`{"schemaVersion": 2, "layers": [], "annotations": {}}`)
+	corruptManifest := marshal(t, map[string]any{"layers": []map[string]string{
+		{"mediaType": "application/vnd.cyclonedx+json", "digest": digest([]byte("something else"))},
+	}})
+	r := &registry{
+		manifests: map[string][]byte{
+			"1.0":     image,
+			"corrupt": corruptImage,
+			strings.Replace(digest(corruptImage), ":", "-", 1) + ".sbom": corruptManifest,
+		},
+		blobs: map[string][]byte{digest([]byte("something else")): corrupt},
+	}
+	ref := r.serve(t, "1.0")
+	client := &oci.Client{PlainHTTP: true}
+
+	if _, err := client.SBOMs(context.Background(), ref); !errors.Is(err, oci.ErrNoSBOM) {
+		t.Errorf("SBOMs() error = %v, want %v", err, oci.ErrNoSBOM)
+	}
+
+	ref.Tag = "missing"
+	if _, err := client.SBOMs(context.Background(), ref); !errors.Is(err, oci.ErrNotFound) {
+		t.Errorf("SBOMs() error = %v, want %v", err, oci.ErrNotFound)
+	}
+
+	ref.Tag = "corrupt"
+	if _, err := client.SBOMs(context.Background(), ref); err == nil || !strings.Contains(err.Error(), "digest") {
+		t.Errorf("SBOMs() error = %v, want a digest mismatch", err)
+	}
+}
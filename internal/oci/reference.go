@@ -0,0 +1,83 @@
+package oci
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// dockerHub is the name of the default registry in image references.
+	dockerHub = "docker.io"
+	// dockerHubHost is the host serving the registry API of Docker Hub.
+	dockerHubHost = "registry-1.docker.io"
+	// defaultTag is the tag of image references without a tag or digest.
+	defaultTag = "latest"
+)
+
+// Reference is a reference to an image in a registry, e.g. "ghcr.io/acme/app:1.2.0".
+type Reference struct {
+	// Registry is the registry host, with its port if any (e.g. "ghcr.io" or "localhost:5000").
+	Registry string
+	// Repository is the repository of the image in the registry (e.g. "acme/app").
+	Repository string
+	// Tag is the tag of the image, if it is referenced by tag.
+	Tag string
+	// Digest is the digest of the image manifest, if it is referenced by digest (e.g. "sha256:...").
+	Digest string
+}
+
+// ParseReference parses an image reference like docker does: "[registry/]repository[:tag][@digest]". The registry
+// defaults to Docker Hub (with the "library/" namespace for official images), and the tag to "latest".
+func ParseReference(s string) (Reference, error) {
+	var ref Reference
+	rest := s
+	if before, digest, ok := strings.Cut(rest, "@"); ok {
+		if !strings.Contains(digest, ":") {
+			return Reference{}, fmt.Errorf("invalid image reference %q: digest must be <algorithm>:<hex>", s)
+		}
+		rest, ref.Digest = before, digest
+	}
+
+	// A tag follows the last colon, unless that colon separates a registry port in the first path component
+	if i := strings.LastIndex(rest, ":"); i > strings.LastIndex(rest, "/") {
+		rest, ref.Tag = rest[:i], rest[i+1:]
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = defaultTag
+	}
+
+	ref.Registry = dockerHub
+	ref.Repository = rest
+	if first, remainder, ok := strings.Cut(rest, "/"); ok &&
+		(strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.Registry, ref.Repository = first, remainder
+	}
+	if ref.Repository == "" || ref.Repository != strings.ToLower(ref.Repository) {
+		return Reference{}, fmt.Errorf("invalid image reference %q: the repository must be non-empty and lowercase", s)
+	}
+
+	if ref.Registry == dockerHub && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+	return ref, nil
+}
+
+// String returns the reference as "registry/repository[:tag][@digest]".
+func (r Reference) String() string {
+	s := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// host returns the host serving the registry API of the reference's registry.
+func (r Reference) host() string {
+	if r.Registry == dockerHub {
+		return dockerHubHost
+	}
+	return r.Registry
+}
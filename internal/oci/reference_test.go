@@ -0,0 +1,63 @@
+package oci_test
+
+import (
+	"testing"
+
+	"github.com/boringbin/sbomattr/internal/oci"
+)
+
+func TestParseReference(t *testing.T) {
+	t.Parallel()
+
+	digest := "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	tests := []struct {
+		input string
+		want  oci.Reference
+	}{
+		{input: "alpine", want: oci.Reference{Registry: "docker.io", Repository: "library/alpine", Tag: "latest"}},
+		{input: "acme/app:1.2.0", want: oci.Reference{Registry: "docker.io", Repository: "acme/app", Tag: "1.2.0"}},
+		{
+			input: "ghcr.io/acme/app:1.2.0",
+			want:  oci.Reference{Registry: "ghcr.io", Repository: "acme/app", Tag: "1.2.0"},
+		},
+		{
+			input: "localhost:5000/app",
+			want:  oci.Reference{Registry: "localhost:5000", Repository: "app", Tag: "latest"},
+		},
+		{
+			input: "localhost/app@" + digest,
+			want:  oci.Reference{Registry: "localhost", Repository: "app", Digest: digest},
+		},
+		{
+			input: "registry.example.com:8443/team/app:v1@" + digest,
+			want: oci.Reference{
+				Registry: "registry.example.com:8443", Repository: "team/app", Tag: "v1", Digest: digest,
+			},
+		},
+	}
+	for _, tt := range tests {
+		got, err := oci.ParseReference(tt.input)
+		if err != nil {
+			t.Errorf("ParseReference(%q) unexpected error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseReference(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{"", "ghcr.io/", "Acme/App", "app@1234"} {
+		if _, err := oci.ParseReference(input); err == nil {
+			t.Errorf("ParseReference(%q) expected an error", input)
+		}
+	}
+}
+
+func TestReference_String(t *testing.T) {
+	t.Parallel()
+
+	ref := oci.Reference{Registry: "ghcr.io", Repository: "acme/app", Tag: "1.2.0", Digest: "sha256:abc"}
+	if got, want := ref.String(), "ghcr.io/acme/app:1.2.0@sha256:abc"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
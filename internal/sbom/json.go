@@ -0,0 +1,31 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// UnmarshalStrings decodes a JSON value that SBOM generators encode either as a single string or as an array of
+// strings, depending on the generator. A single string decodes to a one-element slice, and null (or no data, for
+// a missing field) to nil.
+func UnmarshalStrings(data []byte) ([]string, error) {
+	data = bytes.TrimSpace(data)
+
+	switch {
+	case len(data) == 0, bytes.Equal(data, []byte("null")):
+		return nil, nil
+	case bytes.HasPrefix(data, []byte("[")):
+		var values []string
+		if err := json.Unmarshal(data, &values); err != nil {
+			return nil, fmt.Errorf("decode string array: %w", err)
+		}
+		return values, nil
+	default:
+		var value string
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, fmt.Errorf("decode string or string array: %w", err)
+		}
+		return []string{value}, nil
+	}
+}
@@ -0,0 +1,42 @@
+package sbom_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/internal/sbom"
+)
+
+// TestUnmarshalStrings tests decoding values encoded as a string or an array of strings.
+func TestUnmarshalStrings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{name: "string", input: `"MIT"`, want: []string{"MIT"}},
+		{name: "array", input: `["MIT", "Apache-2.0"]`, want: []string{"MIT", "Apache-2.0"}},
+		{name: "empty array", input: ` [] `, want: []string{}},
+		{name: "null", input: `null`, want: nil},
+		{name: "missing", input: ``, want: nil},
+		{name: "number", input: `42`, wantErr: true},
+		{name: "array of objects", input: `[{"id": "MIT"}]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := sbom.UnmarshalStrings([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalStrings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) || (got == nil) != (tt.want == nil) {
+				t.Errorf("UnmarshalStrings() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
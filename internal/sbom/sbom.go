@@ -2,14 +2,26 @@
 package sbom
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"net/http"
+	"unicode/utf8"
 )
 
 // DetectFormat analyzes the SBOM data and returns the detected format string.
 // It returns either "spdx" or "cyclonedx" based on format-specific markers in the JSON data.
 // It supports both standard formats and GitHub-wrapped formats (e.g., {"sbom": {...}}).
+// Executables (ELF, Mach-O, PE, or WebAssembly) are reported as "go-binary", to be read with debug/buildinfo.
+// The JSON or NDJSON output of sbomattr itself is reported as "sbomattr" (see IsAttributionJSON).
 func DetectFormat(data []byte) (string, error) {
+	if IsExecutable(data) {
+		return "go-binary", nil
+	}
+	if IsAttributionJSON(data) {
+		return "sbomattr", nil
+	}
+
 	var raw map[string]any
 	if err := json.Unmarshal(data, &raw); err != nil {
 		return "", err
@@ -37,3 +49,114 @@ func DetectFormat(data []byte) (string, error) {
 
 	return "", errors.New("unknown SBOM format: could not detect SPDX or CycloneDX markers")
 }
+
+// IsAttributionJSON reports whether data is attribution output written by sbomattr: a JSON document with run metadata
+// (see format.Document), a JSON array of attributions or of source groups (-group-by-source), or a stream of
+// attributions (NDJSON). Attributions are recognized by their name and purl keys, which sbomattr always writes; an
+// empty array is accepted too.
+func IsAttributionJSON(data []byte) bool {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	var first any
+	if err := decoder.Decode(&first); err != nil {
+		return false
+	}
+	if list, ok := first.([]any); ok {
+		return !decoder.More() && (len(list) == 0 || isAttributionValue(list[0]))
+	}
+	if isAttributionDocument(first) {
+		return !decoder.More()
+	}
+	if !isAttributionValue(first) {
+		return false
+	}
+
+	// The rest of a stream must be attributions too
+	for decoder.More() {
+		var next any
+		if err := decoder.Decode(&next); err != nil || !isAttributionValue(next) {
+			return false
+		}
+	}
+	return true
+}
+
+// isAttributionDocument reports whether a decoded JSON value is a JSON document written by sbomattr: an object with
+// a metadata object naming sbomattr as the tool.
+func isAttributionDocument(v any) bool {
+	object, ok := v.(map[string]any)
+	if !ok {
+		return false
+	}
+	metadata, ok := object["metadata"].(map[string]any)
+	return ok && metadata["tool"] == "sbomattr"
+}
+
+// isAttributionValue reports whether a decoded JSON value is an attribution written by sbomattr, or a group of them.
+func isAttributionValue(v any) bool {
+	object, ok := v.(map[string]any)
+	if !ok {
+		return false
+	}
+	if _, grouped := object["attributions"].([]any); grouped {
+		_, hasSource := object["source"].(string)
+		return hasSource
+	}
+	_, hasName := object["name"].(string)
+	_, hasPurl := object["purl"].(string)
+	return hasName && hasPurl
+}
+
+// IsExecutable reports whether data starts with the magic number of an executable format Go can build:
+// ELF, Mach-O (32/64-bit, either byte order, or universal), PE, or WebAssembly.
+func IsExecutable(data []byte) bool {
+	magics := [][]byte{
+		[]byte("\x7fELF"),
+		{0xfe, 0xed, 0xfa, 0xce}, {0xce, 0xfa, 0xed, 0xfe}, // Mach-O 32-bit
+		{0xfe, 0xed, 0xfa, 0xcf}, {0xcf, 0xfa, 0xed, 0xfe}, // Mach-O 64-bit
+		{0xca, 0xfe, 0xba, 0xbe}, // Mach-O universal
+		[]byte("MZ"),             // PE
+		[]byte("\x00asm"),        // WebAssembly
+	}
+	for _, magic := range magics {
+		if bytes.HasPrefix(data, magic) {
+			return true
+		}
+	}
+	return false
+}
+
+// sniffLen is the number of leading bytes DetectBinary inspects, like git does to tell text from binary files.
+const sniffLen = 8000
+
+// DetectBinary reports whether data is binary content other than an executable, such as a PDF, an image, or an
+// encrypted blob, which can never be an SBOM. Content is binary if its first bytes contain a NUL byte or are not valid
+// UTF-8. The detected MIME type (see http.DetectContentType) is returned for diagnostics.
+func DetectBinary(data []byte) (string, bool) {
+	if IsExecutable(data) {
+		return "", false
+	}
+
+	sniff := data
+	if len(data) > sniffLen {
+		// A multi-byte character cut off at the end of the window is not a sign of binary content
+		sniff = trimPartialRune(data[:sniffLen])
+	}
+	if bytes.IndexByte(sniff, 0) < 0 && utf8.Valid(sniff) {
+		return "", false
+	}
+	return http.DetectContentType(data), true
+}
+
+// trimPartialRune returns data without its last UTF-8 sequence if that sequence is incomplete.
+func trimPartialRune(data []byte) []byte {
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				return data[:i]
+			}
+			break
+		}
+	}
+	return data
+}
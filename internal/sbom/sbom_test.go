@@ -1,6 +1,7 @@
 package sbom_test
 
 import (
+	"bytes"
 	"os"
 	"testing"
 
@@ -76,6 +77,79 @@ func TestDetectFormat_InvalidJSON(t *testing.T) {
 	}
 }
 
+// TestDetectFormat_Executable tests that executables are detected as Go binaries.
+func TestDetectFormat_Executable(t *testing.T) {
+	t.Parallel()
+
+	// The test binary itself is a Go executable
+	path, err := os.Executable()
+	if err != nil {
+		t.Fatalf("failed to locate test binary: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read test binary: %v", err)
+	}
+
+	format, err := sbom.DetectFormat(data)
+	if err != nil {
+		t.Fatalf("DetectFormat() unexpected error: %v", err)
+	}
+	if format != "go-binary" {
+		t.Errorf("DetectFormat() = %q, want %q", format, "go-binary")
+	}
+}
+
+// TestDetectFormat_Attributions tests that sbomattr's own JSON and NDJSON output is detected.
+func TestDetectFormat_Attributions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "json", data: `[{"name": "lodash", "purl": "pkg:npm/lodash@4.17.21"}]`, want: "sbomattr"},
+		{name: "empty json", data: "[]\n", want: "sbomattr"},
+		{
+			name: "grouped json",
+			data: `[{"source": "sbom.json", "attributions": [{"name": "lodash", "purl": ""}]}]`,
+			want: "sbomattr",
+		},
+		{
+			name: "ndjson",
+			data: `{"name": "lodash", "purl": "pkg:npm/lodash@4.17.21"}` + "\n" + `{"name": "react", "purl": ""}`,
+			want: "sbomattr",
+		},
+		{
+			name: "document",
+			data: `{"metadata": {"tool": "sbomattr"}, "attributions": [{"name": "lodash", "purl": ""}]}`,
+			want: "sbomattr",
+		},
+		{name: "document of another tool", data: `{"metadata": {"tool": "syft"}, "attributions": []}`},
+		{name: "array of other objects", data: `[{"name": "lodash"}]`},
+		{name: "ndjson with other objects", data: `{"name": "lodash", "purl": ""}` + "\n" + `{"id": 1}`},
+		{name: "spdx", data: `{"spdxVersion": "SPDX-2.3", "name": "doc"}`, want: "spdx"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			format, err := sbom.DetectFormat([]byte(tt.data))
+			if tt.want == "" {
+				if err == nil {
+					t.Errorf("DetectFormat() = %q, want error", format)
+				}
+				return
+			}
+			if err != nil || format != tt.want {
+				t.Errorf("DetectFormat() = %q, %v, want %q", format, err, tt.want)
+			}
+		})
+	}
+}
+
 // TestDetectFormat_UnknownFormat tests that unknown format returns an error.
 func TestDetectFormat_UnknownFormat(t *testing.T) {
 	t.Parallel()
@@ -131,3 +205,55 @@ func TestDetectFormat_GitHubWrappedEmptySBOM(t *testing.T) {
 		t.Fatal("Expected error for empty SBOM, got nil")
 	}
 }
+
+// TestDetectBinary tests that binary junk is told apart from text and executables.
+func TestDetectBinary(t *testing.T) {
+	t.Parallel()
+
+	// A multi-byte character straddling the end of the inspected window must not count as binary
+	straddling := append(bytes.Repeat([]byte(" "), 7999), []byte("é{}")...)
+
+	tests := []struct {
+		name            string
+		data            []byte
+		wantBinary      bool
+		wantContentType string
+	}{
+		{name: "json", data: []byte(`{"spdxVersion": "SPDX-2.3", "name": "café"}`)},
+		{name: "empty", data: nil},
+		{name: "utf-8 straddling the window", data: straddling},
+		{name: "executable", data: []byte("\x7fELF\x02\x01\x01\x00\x00\x00")},
+		{
+			name:            "pdf",
+			data:            []byte("%PDF-1.7\n\x00\xe2\xe3\xcf\xd3"),
+			wantBinary:      true,
+			wantContentType: "application/pdf",
+		},
+		{
+			name:            "png",
+			data:            []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"),
+			wantBinary:      true,
+			wantContentType: "image/png",
+		},
+		{
+			name:            "encrypted",
+			data:            []byte{0x8c, 0x0d, 0x04, 0x09, 0x03, 0x02, 0xb1, 0xff, 0x7a, 0xc3},
+			wantBinary:      true,
+			wantContentType: "application/octet-stream",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			contentType, binary := sbom.DetectBinary(tt.data)
+			if binary != tt.wantBinary {
+				t.Fatalf("DetectBinary() binary = %v, want %v", binary, tt.wantBinary)
+			}
+			if contentType != tt.wantContentType {
+				t.Errorf("DetectBinary() content type = %q, want %q", contentType, tt.wantContentType)
+			}
+		})
+	}
+}
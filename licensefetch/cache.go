@@ -0,0 +1,43 @@
+package licensefetch
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/boringbin/sbomattr/internal/cache"
+)
+
+// Cache remembers fetched license texts across runs, so each upstream source is only fetched once. Its keys are the
+// upstream sources, e.g. "github.com/owner/repo".
+// It is safe for concurrent use.
+type Cache = cache.Cache[Entry]
+
+// Entry is a cached fetch result.
+type Entry struct {
+	// Text is the fetched license text, or empty if the source has no license file.
+	Text string `json:"text,omitempty"`
+	// Source is the kind of source the text was fetched from, e.g. "github" or "npm".
+	Source string `json:"source"`
+	// Fetched is when the text was fetched.
+	Fetched time.Time `json:"fetched"`
+}
+
+// NewCache returns an empty cache.
+func NewCache() *Cache {
+	return cache.New(fetched)
+}
+
+// LoadCache reads a cache from a JSON file.
+// Returns an empty cache if the file does not exist or was written by an incompatible version of sbomattr.
+func LoadCache(path string) (*Cache, error) {
+	c, err := cache.Load(path, fetched)
+	if err != nil {
+		return nil, fmt.Errorf("load license text cache: %w", err)
+	}
+	return c, nil
+}
+
+// fetched returns when the text of an entry was fetched.
+func fetched(e Entry) time.Time {
+	return e.Fetched
+}
@@ -0,0 +1,4 @@
+// Package licensefetch fills in the license texts that SBOMs lack from upstream sources: the LICENSE file of the
+// package's GitHub repository, or of its npm tarball. It also downloads the texts of standard licenses that are not
+// bundled with sbomattr from the SPDX license list. Fetching is opt-in, since it hits the network.
+package licensefetch
@@ -0,0 +1,325 @@
+package licensefetch
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/package-url/packageurl-go"
+)
+
+const (
+	// DefaultGitHubRawURL is the base URL of raw GitHub repository files.
+	DefaultGitHubRawURL = "https://raw.githubusercontent.com"
+	// DefaultNPMRegistryURL is the base URL of the public npm registry.
+	DefaultNPMRegistryURL = "https://registry.npmjs.org"
+)
+
+// Upstream source kinds, recorded in the license text provenance (e.g. "enriched-from-github").
+const (
+	// SourceGitHub is the default branch of the package's GitHub repository.
+	SourceGitHub = "github"
+	// SourceNPM is the npm tarball of the package version.
+	SourceNPM = "npm"
+)
+
+var (
+	// ErrNotFound is returned when a package has no known upstream source, or its source has no license file.
+	ErrNotFound = errors.New("license text not found")
+	// ErrUnexpectedStatus is returned when an upstream source responds with an unexpected status code.
+	ErrUnexpectedStatus = errors.New("unexpected response status")
+	// ErrOffline is returned in offline mode when a license text is not cached.
+	ErrOffline = errors.New("license text not cached in offline mode")
+)
+
+// licenseFileNames lists the license file names looked for in GitHub repositories, in order of preference.
+func licenseFileNames() []string {
+	return []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "LICENCE", "COPYING"}
+}
+
+// Limits on downloaded content, so a huge tarball or file cannot exhaust memory.
+const (
+	maxTarballBytes = 50 << 20
+	maxTextBytes    = 1 << 20
+)
+
+// Fetcher fetches missing license texts from upstream sources.
+type Fetcher struct {
+	// Client is the HTTP client used for requests. Defaults to a client with a 30 second timeout.
+	Client *http.Client
+	// GitHubRawURL is the base URL of raw GitHub files, without a trailing slash. Defaults to DefaultGitHubRawURL.
+	GitHubRawURL string
+	// NPMRegistryURL is the base URL of the npm registry, without a trailing slash. Defaults to
+	// DefaultNPMRegistryURL.
+	NPMRegistryURL string
+	// LicenseListURL is the base URL of the SPDX license list texts, without a trailing slash. Defaults to
+	// DefaultLicenseListURL.
+	LicenseListURL string
+	// Concurrency is the number of packages fetched in parallel. Defaults to 4.
+	Concurrency int
+	// Cache is optional; if set, cached texts are reused and new ones are recorded in it.
+	Cache *Cache
+	// MaxAge is how long cached texts are reused. Zero means they never expire.
+	MaxAge time.Duration
+	// Offline guarantees that no requests are made: only cached texts are used (see ErrOffline).
+	Offline bool
+	// Logger is optional; set to nil to disable logging.
+	Logger *slog.Logger
+}
+
+// Fill returns a copy of attributions where the packages without a license text get the one fetched from upstream,
+// with its provenance set to the source it was fetched from (e.g. "enriched-from-npm"). Packages whose text cannot
+// be fetched are left unchanged, and the failures are logged.
+// Returns an error only if the context is canceled.
+func (f *Fetcher) Fill(ctx context.Context, attributions []attribution.Attribution) ([]attribution.Attribution, error) {
+	const defaultConcurrency = 4
+	concurrency := f.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	filled := make([]attribution.Attribution, len(attributions))
+	copy(filled, attributions)
+
+	var wg sync.WaitGroup
+	queue := make(chan int)
+	for range min(concurrency, len(attributions)) {
+		wg.Go(func() {
+			for i := range queue {
+				filled[i] = f.fill(ctx, filled[i])
+			}
+		})
+	}
+	for i, a := range attributions {
+		if ctx.Err() != nil {
+			break
+		}
+		if a.LicenseText == "" {
+			queue <- i
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("fetch license texts: %w", err)
+	}
+	return filled, nil
+}
+
+// fill returns the attribution with its license text fetched, or unchanged if it cannot be fetched.
+func (f *Fetcher) fill(ctx context.Context, a attribution.Attribution) attribution.Attribution {
+	text, source, err := f.Fetch(ctx, a)
+	if err != nil {
+		if f.Logger != nil && ctx.Err() == nil {
+			f.Logger.DebugContext(ctx, "no upstream license text", "name", a.Name, "purl", a.Purl, "error", err)
+		}
+		return a
+	}
+	a.LicenseText = text
+	return a.WithProvenance(attribution.FieldLicenseText, attribution.ProvenanceEnriched(source))
+}
+
+// Fetch returns the license text of a package from upstream, and the kind of source it was fetched from
+// (SourceNPM or SourceGitHub): the npm tarball of npm packages, or else the default branch of the GitHub repository
+// of the package (see attribution.RepositoryKey).
+// Returns ErrNotFound if the package has no known upstream source, or its source has no license file.
+func (f *Fetcher) Fetch(ctx context.Context, a attribution.Attribution) (string, string, error) {
+	if purl, err := packageurl.FromString(a.Purl); err == nil && purl.Type == packageurl.TypeNPM {
+		text, err := f.cached(ctx, attribution.CanonicalPurl(a.Purl), SourceNPM, func() (string, error) {
+			return f.fetchNPM(ctx, purl)
+		})
+		if !errors.Is(err, ErrNotFound) && !errors.Is(err, ErrOffline) {
+			return text, SourceNPM, err
+		}
+	}
+
+	if repo, ok := attribution.RepositoryKey(a); ok && strings.HasPrefix(repo, "github.com/") {
+		text, err := f.cached(ctx, repo, SourceGitHub, func() (string, error) {
+			return f.fetchGitHub(ctx, strings.TrimPrefix(repo, "github.com/"))
+		})
+		return text, SourceGitHub, err
+	}
+
+	return "", "", ErrNotFound
+}
+
+// cached returns the license text cached for a source key, or fetches and caches it. Missing license files are
+// cached too, so they are not looked for again; other failures are not. Offline, uncached texts are not fetched.
+func (f *Fetcher) cached(ctx context.Context, key, source string, fetch func() (string, error)) (string, error) {
+	if f.Cache != nil {
+		if e, ok := f.Cache.Lookup(key, f.MaxAge, time.Now()); ok {
+			if e.Text == "" {
+				return "", ErrNotFound
+			}
+			return e.Text, nil
+		}
+	}
+	if f.Offline {
+		return "", fmt.Errorf("%w: %s", ErrOffline, key)
+	}
+
+	text, err := fetch()
+	if f.Cache != nil && ctx.Err() == nil && (err == nil || errors.Is(err, ErrNotFound)) {
+		f.Cache.Store(key, Entry{Text: text, Source: source, Fetched: time.Now()})
+	}
+	return text, err
+}
+
+// fetchGitHub returns the first license file found on the default branch of a GitHub repository ("owner/repo").
+func (f *Fetcher) fetchGitHub(ctx context.Context, repo string) (string, error) {
+	base := strings.TrimSuffix(f.GitHubRawURL, "/")
+	if base == "" {
+		base = DefaultGitHubRawURL
+	}
+
+	for _, name := range licenseFileNames() {
+		body, err := f.get(ctx, base+"/"+repo+"/HEAD/"+name)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		defer body.Close()
+		return readText(body)
+	}
+	return "", ErrNotFound
+}
+
+// npmVersion is the part of the npm registry metadata of a package version that is needed to find its tarball.
+type npmVersion struct {
+	Dist struct {
+		Tarball string `json:"tarball"`
+	} `json:"dist"`
+}
+
+// fetchNPM returns the license file of the npm tarball of a package version (or the latest version if the purl has
+// none).
+func (f *Fetcher) fetchNPM(ctx context.Context, purl packageurl.PackageURL) (string, error) {
+	base := strings.TrimSuffix(f.NPMRegistryURL, "/")
+	if base == "" {
+		base = DefaultNPMRegistryURL
+	}
+	name := purl.Name
+	if purl.Namespace != "" {
+		name = purl.Namespace + "/" + purl.Name
+	}
+	version := purl.Version
+	if version == "" {
+		version = "latest"
+	}
+
+	body, err := f.get(ctx, base+"/"+name+"/"+version)
+	if err != nil {
+		return "", err
+	}
+	var metadata npmVersion
+	err = json.NewDecoder(body).Decode(&metadata)
+	_ = body.Close()
+	if err != nil {
+		return "", fmt.Errorf("decode npm metadata: %w", err)
+	}
+	if metadata.Dist.Tarball == "" {
+		return "", ErrNotFound
+	}
+
+	tarball, err := f.get(ctx, metadata.Dist.Tarball)
+	if err != nil {
+		return "", err
+	}
+	defer tarball.Close()
+	return tarballLicense(io.LimitReader(tarball, maxTarballBytes))
+}
+
+// tarballLicense returns the license file at the root of the package directory of an npm tarball (a gzipped tar
+// archive whose files are in a single top-level directory, usually "package").
+func tarballLicense(r io.Reader) (string, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return "", fmt.Errorf("read npm tarball: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return "", ErrNotFound
+		}
+		if err != nil {
+			return "", fmt.Errorf("read npm tarball: %w", err)
+		}
+
+		dir, file := path.Split(strings.TrimPrefix(header.Name, "./"))
+		if header.Typeflag != tar.TypeReg || strings.Count(dir, "/") != 1 || !isLicenseFile(file) {
+			continue
+		}
+		return readText(tr)
+	}
+}
+
+// isLicenseFile reports whether a file name is a license file name, e.g. "LICENSE", "license.md", or "LICENCE-MIT".
+func isLicenseFile(name string) bool {
+	name = strings.ToUpper(name)
+	for _, prefix := range []string{"LICENSE", "LICENCE", "COPYING"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// readText reads a license text, up to a size limit.
+func readText(r io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxTextBytes))
+	if err != nil {
+		return "", fmt.Errorf("read license text: %w", err)
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		return "", ErrNotFound
+	}
+	return string(data), nil
+}
+
+// get sends a GET request and returns the response body, which the caller must close.
+// Returns ErrNotFound for 404 responses.
+func (f *Fetcher) get(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "sbomattr")
+
+	client := f.Client
+	if client == nil {
+		const defaultTimeout = 30 * time.Second
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", url, err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, url)
+	case resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices:
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%w: GET %s: %s", ErrUnexpectedStatus, url, resp.Status)
+	}
+	return resp.Body, nil
+}
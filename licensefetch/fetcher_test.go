@@ -0,0 +1,260 @@
+package licensefetch_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/licensefetch"
+)
+
+// npmTarball returns a gzipped tar archive holding the given files.
+func npmTarball(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		header := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// newServer returns a test server acting as both raw GitHub and the npm registry. The owner/repo repository has a
+// LICENSE.md file, owner/unlicensed has no license file, and the left-pad@1.3.0 npm package has a LICENSE file.
+// It counts the requests it receives.
+func newServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	tarball := npmTarball(t, map[string]string{
+		"package/package.json":         `{"name": "left-pad"}`,
+		"package/lib/LICENSE":          "nested license",
+		"package/LICENSE":              "WTFPL from npm",
+		"package/README.md":            "left-pad",
+		"package/node_modules/LICENSE": "bundled license",
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /owner/repo/HEAD/LICENSE.md", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, "MIT License from GitHub")
+	})
+	mux.HandleFunc("GET /left-pad/1.3.0", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"dist": {"tarball": "http://%s/left-pad/-/left-pad-1.3.0.tgz"}}`, r.Host)
+	})
+	mux.HandleFunc("GET /left-pad/-/left-pad-1.3.0.tgz", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(tarball)
+	})
+	mux.HandleFunc("GET /broken/repo/HEAD/", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newFetcher returns a fetcher whose GitHub and npm sources are the test server.
+func newFetcher(server *httptest.Server, cache *licensefetch.Cache) *licensefetch.Fetcher {
+	return &licensefetch.Fetcher{GitHubRawURL: server.URL, NPMRegistryURL: server.URL, Cache: cache}
+}
+
+// TestFetcher_Fetch tests that license texts are fetched from npm tarballs and GitHub repositories.
+func TestFetcher_Fetch(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	fetcher := newFetcher(newServer(t, &requests), nil)
+	repoURL := "https://github.com/Owner/Repo"
+
+	tests := []struct {
+		name       string
+		a          attribution.Attribution
+		wantText   string
+		wantSource string
+		wantErr    error
+	}{
+		{
+			name:       "npm tarball",
+			a:          attribution.Attribution{Name: "left-pad", Purl: "pkg:npm/left-pad@1.3.0"},
+			wantText:   "WTFPL from npm",
+			wantSource: licensefetch.SourceNPM,
+		},
+		{
+			name:       "GitHub repository",
+			a:          attribution.Attribution{Name: "repo", URL: &repoURL},
+			wantText:   "MIT License from GitHub",
+			wantSource: licensefetch.SourceGitHub,
+		},
+		{
+			name:       "npm package falls back to its repository",
+			a:          attribution.Attribution{Name: "gone", Purl: "pkg:npm/gone@1.0.0", URL: &repoURL},
+			wantText:   "MIT License from GitHub",
+			wantSource: licensefetch.SourceGitHub,
+		},
+		{
+			name:    "no license file",
+			a:       attribution.Attribution{Name: "unlicensed", Purl: "pkg:github/owner/unlicensed@1.0.0"},
+			wantErr: licensefetch.ErrNotFound,
+		},
+		{
+			name:    "no upstream source",
+			a:       attribution.Attribution{Name: "serde", Purl: "pkg:cargo/serde@1.0.0"},
+			wantErr: licensefetch.ErrNotFound,
+		},
+		{
+			name:    "server error",
+			a:       attribution.Attribution{Name: "broken", Purl: "pkg:github/broken/repo@1.0.0"},
+			wantErr: licensefetch.ErrUnexpectedStatus,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			text, source, err := fetcher.Fetch(context.Background(), tt.a)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Fetch() error = %v, want %v", err, tt.wantErr)
+			}
+			if text != tt.wantText || (tt.wantErr == nil && source != tt.wantSource) {
+				t.Errorf("Fetch() = %q, %q, want %q, %q", text, source, tt.wantText, tt.wantSource)
+			}
+		})
+	}
+}
+
+// TestFetcher_Fill tests that only missing texts are filled, with their provenance, and that the cache saves
+// requests, including for sources without a license file.
+func TestFetcher_Fill(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := newServer(t, &requests)
+	cache := licensefetch.NewCache()
+	fetcher := newFetcher(server, cache)
+
+	attrs := []attribution.Attribution{
+		{Name: "left-pad", Purl: "pkg:npm/left-pad@1.3.0"},
+		{Name: "unlicensed", Purl: "pkg:github/owner/unlicensed@1.0.0"},
+		{Name: "known", Purl: "pkg:npm/known@1.0.0", LicenseText: "embedded text"},
+	}
+
+	filled, err := fetcher.Fill(context.Background(), attrs)
+	if err != nil {
+		t.Fatalf("Fill() unexpected error: %v", err)
+	}
+	if filled[0].LicenseText != "WTFPL from npm" {
+		t.Errorf("Fill() text = %q, want the npm text", filled[0].LicenseText)
+	}
+	want := attribution.ProvenanceEnriched(licensefetch.SourceNPM)
+	if got := filled[0].Provenance[attribution.FieldLicenseText]; got != want {
+		t.Errorf("Fill() provenance = %q, want %q", got, want)
+	}
+	if filled[1].LicenseText != "" || filled[2].LicenseText != "embedded text" {
+		t.Errorf("Fill() changed texts it could not or should not fetch: %+v", filled)
+	}
+	if attrs[0].LicenseText != "" {
+		t.Error("Fill() modified its input")
+	}
+
+	before := requests.Load()
+	if _, err = newFetcher(server, cache).Fill(context.Background(), attrs); err != nil {
+		t.Fatalf("Fill() unexpected error: %v", err)
+	}
+	if got := requests.Load(); got != before {
+		t.Errorf("Fill() sent %d requests with a warm cache, want none", got-before)
+	}
+}
+
+// TestFetcher_Fill_Offline tests that offline fetchers only use cached texts and send no requests.
+func TestFetcher_Fill_Offline(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := newServer(t, &requests)
+	cache := licensefetch.NewCache()
+	cache.Store("github.com/owner/repo", licensefetch.Entry{Text: "cached MIT", Source: licensefetch.SourceGitHub})
+	fetcher := newFetcher(server, cache)
+	fetcher.Offline = true
+
+	repoURL := "https://github.com/owner/repo"
+	filled, err := fetcher.Fill(context.Background(), []attribution.Attribution{
+		{Name: "repo", Purl: "pkg:npm/repo@1.0.0", URL: &repoURL},
+		{Name: "left-pad", Purl: "pkg:npm/left-pad@1.3.0"},
+	})
+	if err != nil {
+		t.Fatalf("Fill() unexpected error: %v", err)
+	}
+	if filled[0].LicenseText != "cached MIT" || filled[1].LicenseText != "" {
+		t.Errorf("Fill() texts = %q, %q, want only the cached text", filled[0].LicenseText, filled[1].LicenseText)
+	}
+	if got := requests.Load(); got != 0 {
+		t.Errorf("server received %d requests offline, want none", got)
+	}
+}
+
+// TestFetcher_Fill_CacheExpired tests that texts cached longer than MaxAge ago are fetched again.
+func TestFetcher_Fill_CacheExpired(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := newServer(t, &requests)
+	cache := licensefetch.NewCache()
+	cache.Store("github.com/owner/repo", licensefetch.Entry{
+		Text:    "stale MIT",
+		Source:  licensefetch.SourceGitHub,
+		Fetched: time.Now().Add(-2 * time.Hour),
+	})
+	fetcher := newFetcher(server, cache)
+	fetcher.MaxAge = time.Hour
+
+	repoURL := "https://github.com/owner/repo"
+	filled, err := fetcher.Fill(context.Background(), []attribution.Attribution{
+		{Name: "repo", Purl: "pkg:github/owner/repo@1.0.0", URL: &repoURL},
+	})
+	if err != nil {
+		t.Fatalf("Fill() unexpected error: %v", err)
+	}
+	if filled[0].LicenseText != "MIT License from GitHub" {
+		t.Errorf("Fill() text = %q, want the text fetched again", filled[0].LicenseText)
+	}
+}
+
+// TestFetcher_Fill_Canceled tests that a canceled context is reported.
+func TestFetcher_Fill_Canceled(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	fetcher := newFetcher(newServer(t, &requests), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := fetcher.Fill(ctx, []attribution.Attribution{{Purl: "pkg:npm/left-pad@1.3.0"}}); err == nil {
+		t.Error("Fill() with a canceled context succeeded, want an error")
+	}
+}
@@ -0,0 +1,89 @@
+package licensefetch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// DefaultLicenseListURL is the base URL of the plain text license texts of the SPDX license list.
+const DefaultLicenseListURL = "https://raw.githubusercontent.com/spdx/license-list-data/main/text"
+
+// SourceSPDX is the SPDX license list, the source of the texts of standard licenses (see LoadLicenseTexts).
+const SourceSPDX = "spdx"
+
+// CommonLicenseIDs returns the identifiers of common SPDX licenses whose text is not bundled with sbomattr (see
+// attribution.LicenseTextIDs), e.g. AGPL-3.0-only and EPL-2.0. Loading them with LoadLicenseTexts lets
+// attribution.IdentifyLicense recognize their license files.
+func CommonLicenseIDs() []string {
+	return []string{
+		"AGPL-3.0-only", "Artistic-2.0", "BlueOak-1.0.0", "BSD-3-Clause-Clear", "BSL-1.0", "CC-BY-4.0", "CC-BY-SA-4.0",
+		"CDDL-1.0", "CDDL-1.1", "EPL-1.0", "EPL-2.0", "EUPL-1.2", "MIT-0", "MulanPSL-2.0", "NCSA", "OFL-1.1",
+		"PostgreSQL", "Python-2.0", "Unicode-3.0", "Unicode-DFS-2016", "UPL-1.0", "WTFPL",
+	}
+}
+
+// LoadLicenseTexts downloads the texts of the given standard licenses that are not bundled with sbomattr from the
+// SPDX license list and registers them (see attribution.RegisterLicenseText), so notices can reproduce them and
+// attribution.IdentifyLicense recognizes them. License expressions, exceptions, and LicenseRef- identifiers are
+// skipped. Texts are cached like package license texts, and identifiers missing from the list are cached too.
+// Licenses whose text cannot be downloaded are skipped, and the failures are logged.
+// Returns an error only if the context is canceled.
+func (f *Fetcher) LoadLicenseTexts(ctx context.Context, ids []string) error {
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			break
+		}
+		if !isListedLicenseID(id) {
+			continue
+		}
+		if _, ok := attribution.LicenseText(id); ok {
+			continue
+		}
+		if current, ok := attribution.CurrentLicenseID(id); ok {
+			id = current
+		}
+
+		text, err := f.cached(ctx, SourceSPDX+":"+id, SourceSPDX, func() (string, error) {
+			return f.fetchLicenseList(ctx, id)
+		})
+		if err != nil {
+			if f.Logger != nil && ctx.Err() == nil {
+				f.Logger.DebugContext(ctx, "no SPDX license list text", "license", id, "error", err)
+			}
+			continue
+		}
+		attribution.RegisterLicenseText(id, text)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("load license texts: %w", err)
+	}
+	return nil
+}
+
+// isListedLicenseID reports whether id may be on the SPDX license list: a single license identifier, rather than an
+// expression, an exception, a special value (NONE, NOASSERTION), or a LicenseRef- or DocumentRef- reference.
+func isListedLicenseID(id string) bool {
+	upper := strings.ToUpper(id)
+	return id != "" && !strings.ContainsAny(id, " ()/") && upper != "NONE" && upper != "NOASSERTION" &&
+		!strings.HasPrefix(upper, "LICENSEREF-") && !strings.HasPrefix(upper, "DOCUMENTREF-") &&
+		!attribution.IsLicenseException(id)
+}
+
+// fetchLicenseList returns the text of a license from the SPDX license list.
+func (f *Fetcher) fetchLicenseList(ctx context.Context, id string) (string, error) {
+	base := strings.TrimSuffix(f.LicenseListURL, "/")
+	if base == "" {
+		base = DefaultLicenseListURL
+	}
+
+	body, err := f.get(ctx, base+"/"+id+".txt")
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	return readText(body)
+}
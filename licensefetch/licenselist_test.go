@@ -0,0 +1,71 @@
+package licensefetch_test
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/licensefetch"
+)
+
+// TestFetcher_LoadLicenseTexts tests that the texts of licenses that are not bundled are downloaded from the SPDX
+// license list, registered, and cached, and that bundled licenses and non-license identifiers are skipped.
+func TestFetcher_LoadLicenseTexts(t *testing.T) {
+	t.Parallel()
+
+	// The license text registry is global, so the identifiers are unique per run for the test to pass with -count
+	listed, missing := "Test-"+rand.Text(), "Test-"+rand.Text()
+	text := "The " + listed + " license. Permission is granted to use this test fixture for any purpose, provided " +
+		"that this notice and the name of the license are kept in every copy of the fixture."
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/"+listed+".txt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = fmt.Fprint(w, text)
+	}))
+	t.Cleanup(server.Close)
+
+	fetcher := &licensefetch.Fetcher{LicenseListURL: server.URL, Cache: licensefetch.NewCache()}
+	ids := []string{listed, missing, "MIT", "MIT OR Apache-2.0", "LicenseRef-Acme", "Classpath-exception-2.0", "NONE"}
+	if err := fetcher.LoadLicenseTexts(context.Background(), ids); err != nil {
+		t.Fatalf("LoadLicenseTexts() unexpected error: %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("server received %d requests, want 2 (the listed and the missing license)", got)
+	}
+	if got, ok := attribution.LicenseText(listed); !ok || got != text {
+		t.Errorf("LicenseText(%q) = %q, %v, want the downloaded text", listed, got, ok)
+	}
+	if got, ok := attribution.IdentifyLicense("Copyright 2026 Acme\n\n" + text); got != listed || !ok {
+		t.Errorf("IdentifyLicense() = %q, %v, want %q, true", got, ok, listed)
+	}
+
+	// The missing license is cached as missing, so it is not requested again
+	if err := fetcher.LoadLicenseTexts(context.Background(), []string{missing}); err != nil {
+		t.Fatalf("LoadLicenseTexts() cached unexpected error: %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("server received %d requests, want no more for the cached missing license", got)
+	}
+}
+
+// TestFetcher_LoadLicenseTexts_Canceled tests that a canceled context is reported.
+func TestFetcher_LoadLicenseTexts_Canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	fetcher := &licensefetch.Fetcher{LicenseListURL: "http://127.0.0.1:0"}
+	if err := fetcher.LoadLicenseTexts(ctx, []string{"Test-" + rand.Text()}); err == nil {
+		t.Error("LoadLicenseTexts() expected an error for a canceled context")
+	}
+}
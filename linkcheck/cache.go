@@ -0,0 +1,34 @@
+package linkcheck
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/boringbin/sbomattr/internal/cache"
+)
+
+// Cache remembers the results of URL checks across runs, so unchanged URLs are not requested again. The Verifier only
+// stores conclusive results (alive or dead), so inconclusive ones are retried next time.
+// It is safe for concurrent use.
+type Cache = cache.Cache[Result]
+
+// NewCache returns an empty cache.
+func NewCache() *Cache {
+	return cache.New(checked)
+}
+
+// LoadCache reads a cache from a JSON file.
+// Returns an empty cache if the file does not exist or was written by an incompatible version of sbomattr, so the
+// first run checks every URL.
+func LoadCache(path string) (*Cache, error) {
+	c, err := cache.Load(path, checked)
+	if err != nil {
+		return nil, fmt.Errorf("load link cache: %w", err)
+	}
+	return c, nil
+}
+
+// checked returns when a result was checked.
+func checked(r Result) time.Time {
+	return r.Checked
+}
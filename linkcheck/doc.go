@@ -0,0 +1,3 @@
+// Package linkcheck verifies that the URLs of attributions resolve, so notices don't ship dead links (e.g. registry
+// pages generated for packages that were never published there). Checks are opt-in, since they hit the network.
+package linkcheck
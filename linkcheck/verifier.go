@@ -0,0 +1,291 @@
+package linkcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// Status is the outcome of a URL check.
+type Status string
+
+const (
+	// StatusAlive means the URL responded with a success or redirect status.
+	StatusAlive Status = "alive"
+	// StatusDead means the URL does not exist: it responded with 404 Not Found or 410 Gone, or its host does not
+	// resolve.
+	StatusDead Status = "dead"
+	// StatusUnknown means the check was inconclusive, e.g. a timeout, a server error, or a request rejected by bot
+	// protection. Such URLs are neither marked dead nor cached.
+	StatusUnknown Status = "unknown"
+)
+
+// Result is the result of a URL check.
+type Result struct {
+	// Status is the outcome of the check.
+	Status Status `json:"status"`
+	// Code is the HTTP status code of the response, or 0 if the request failed.
+	Code int `json:"code,omitempty"`
+	// Checked is when the URL was checked.
+	Checked time.Time `json:"checked"`
+}
+
+// Verifier checks the URLs of attributions.
+type Verifier struct {
+	// Client is the HTTP client used for checks. Defaults to a client with a 10 second timeout.
+	Client *http.Client
+	// Concurrency is the number of URLs checked in parallel. Defaults to 4.
+	Concurrency int
+	// Interval is the minimum time between the start of two requests, across all parallel checks, to stay below the
+	// rate limits of package registries. Zero means no rate limiting.
+	Interval time.Duration
+	// Cache is optional; if set, cached results are reused and new results are recorded in it.
+	Cache *Cache
+	// MaxAge is how long cached results are reused. Zero means they never expire.
+	MaxAge time.Duration
+	// Offline guarantees that no requests are made: only cached results are used, and uncached URLs are
+	// StatusUnknown.
+	Offline bool
+	// Fallback replaces a dead primary URL with the first other URL of the attribution that is alive (see
+	// attribution.Attribution.URLs). Every URL of the attribution is checked, instead of only the primary one.
+	Fallback bool
+	// Logger is optional; set to nil to disable logging.
+	Logger *slog.Logger
+
+	// limiter enforces Interval.
+	limiter limiter
+}
+
+// Verify returns a copy of attributions with their primary URLs checked (and, with Fallback, all their URLs).
+// Dead URLs are marked as such in URLs, and an attribution whose primary URL is dead gets a note. With Fallback, the
+// dead primary URL is replaced by the first other URL that is alive, if any.
+// Returns an error only if the context is canceled.
+func (v *Verifier) Verify(
+	ctx context.Context,
+	attributions []attribution.Attribution,
+) ([]attribution.Attribution, error) {
+	results := v.CheckAll(ctx, v.candidates(attributions))
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("verify URLs: %w", err)
+	}
+
+	verified := make([]attribution.Attribution, 0, len(attributions))
+	for _, a := range attributions {
+		verified = append(verified, v.apply(a, results))
+	}
+	return verified, nil
+}
+
+// Check checks a single URL, reusing the cached result if there is one.
+// The URL is requested with HEAD, falling back to GET for servers that don't support HEAD. Offline, an uncached URL
+// is not requested and its status is StatusUnknown.
+func (v *Verifier) Check(ctx context.Context, url string) Result {
+	if v.Cache != nil {
+		if r, ok := v.Cache.Lookup(url, v.MaxAge, time.Now()); ok {
+			return r
+		}
+	}
+	if v.Offline {
+		return Result{Status: StatusUnknown}
+	}
+
+	r := v.request(ctx, http.MethodHead, url)
+	if r.Code == http.StatusMethodNotAllowed || r.Code == http.StatusNotImplemented {
+		r = v.request(ctx, http.MethodGet, url)
+	}
+
+	if v.Logger != nil {
+		v.Logger.DebugContext(ctx, "checked URL", "url", url, "status", r.Status, "code", r.Code)
+	}
+	// Inconclusive results are not cached, so they are retried next time
+	if v.Cache != nil && ctx.Err() == nil && r.Status != StatusUnknown {
+		v.Cache.Store(url, r)
+	}
+	return r
+}
+
+// candidates returns the unique URLs to check, in order of first appearance: the primary URLs, and with Fallback
+// every URL of the attributions.
+func (v *Verifier) candidates(attributions []attribution.Attribution) []string {
+	var urls []string
+	for _, a := range attributions {
+		if a.URL != nil && !slices.Contains(urls, *a.URL) {
+			urls = append(urls, *a.URL)
+		}
+		if !v.Fallback {
+			continue
+		}
+		for _, u := range a.URLs {
+			if !slices.Contains(urls, u.URL) {
+				urls = append(urls, u.URL)
+			}
+		}
+	}
+	return urls
+}
+
+// CheckAll checks the URLs in parallel (see Check) and returns their results by URL. If the context is canceled, the
+// URLs not checked yet are missing from the results.
+func (v *Verifier) CheckAll(ctx context.Context, urls []string) map[string]Result {
+	const defaultConcurrency = 4
+	concurrency := v.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]Result, len(urls))
+		queue   = make(chan string)
+	)
+	for range min(concurrency, len(urls)) {
+		wg.Go(func() {
+			for url := range queue {
+				r := v.Check(ctx, url)
+				mu.Lock()
+				results[url] = r
+				mu.Unlock()
+			}
+		})
+	}
+
+	for _, url := range urls {
+		if ctx.Err() != nil {
+			break
+		}
+		queue <- url
+	}
+	close(queue)
+	wg.Wait()
+
+	return results
+}
+
+// apply returns a copy of the attribution with its dead URLs marked, and its primary URL replaced or noted if dead.
+func (v *Verifier) apply(a attribution.Attribution, results map[string]Result) attribution.Attribution {
+	a.URLs = slices.Clone(a.URLs)
+	for i, u := range a.URLs {
+		if results[u.URL].Status == StatusDead {
+			a.URLs[i].Dead = true
+		}
+	}
+	if a.URL == nil || results[*a.URL].Status != StatusDead {
+		return a
+	}
+
+	dead := *a.URL
+	a.Notes = slices.Clone(a.Notes)
+	if v.Fallback {
+		for _, u := range a.URLs {
+			if u.URL == dead || results[u.URL].Status != StatusAlive {
+				continue
+			}
+			if v.Logger != nil {
+				v.Logger.Info("replaced dead URL", "name", a.Name, "purl", a.Purl, "url", dead, "fallback", u.URL)
+			}
+			url := u.URL
+			a.URL = &url
+			a.Notes = append(a.Notes, "dead URL "+dead+" replaced by the "+string(u.Kind)+" URL")
+			return a.WithProvenance(attribution.FieldURL, urlProvenance(u.Kind))
+		}
+	}
+
+	if v.Logger != nil {
+		v.Logger.Warn("dead URL", "name", a.Name, "purl", a.Purl, "url", dead)
+	}
+	a.Notes = append(a.Notes, "dead URL: "+dead)
+	return a
+}
+
+// request requests a URL with the given method and classifies the response.
+func (v *Verifier) request(ctx context.Context, method, url string) Result {
+	r := Result{Status: StatusUnknown, Checked: time.Now().UTC()}
+	if err := v.limiter.wait(ctx, v.Interval); err != nil {
+		return r
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return r
+	}
+	req.Header.Set("User-Agent", "sbomattr")
+
+	client := v.Client
+	if client == nil {
+		const defaultTimeout = 10 * time.Second
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			r.Status = StatusDead
+		}
+		return r
+	}
+	defer resp.Body.Close()
+	// Drain a little of the body so the connection can be reused, without downloading whole pages
+	const maxDrain = 64 << 10
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrain))
+
+	r.Code = resp.StatusCode
+	switch {
+	case resp.StatusCode < http.StatusBadRequest:
+		r.Status = StatusAlive
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		r.Status = StatusDead
+	}
+	return r
+}
+
+// urlProvenance returns the provenance of an attribution URL of the given kind: generated for the kinds generated
+// from the purl and CPE, extracted for the others.
+func urlProvenance(kind attribution.URLKind) attribution.Provenance {
+	if kind == attribution.URLKindRegistry || kind == attribution.URLKindSearch {
+		return attribution.ProvenanceGenerated
+	}
+	return attribution.ProvenanceExtracted
+}
+
+// limiter spaces out requests by a minimum interval.
+type limiter struct {
+	mu sync.Mutex
+	// next is the earliest time the next request may start.
+	next time.Time
+}
+
+// wait blocks until a request may start, at least interval after the previous one, or until the context is canceled.
+func (l *limiter) wait(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(interval)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(start))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,240 @@
+package linkcheck_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/linkcheck"
+)
+
+// newServer returns a test server whose /alive path exists, /missing path does not, /head-unsupported path only
+// supports GET, and /broken path fails. It counts the requests it receives.
+func newServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/alive":
+			w.WriteHeader(http.StatusOK)
+		case "/head-unsupported":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		case "/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestVerifier_Check tests that responses are classified as alive, dead, or unknown.
+func TestVerifier_Check(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := newServer(t, &requests)
+	verifier := &linkcheck.Verifier{}
+
+	tests := []struct {
+		path     string
+		want     linkcheck.Status
+		wantCode int
+	}{
+		{path: "/alive", want: linkcheck.StatusAlive, wantCode: http.StatusOK},
+		{path: "/missing", want: linkcheck.StatusDead, wantCode: http.StatusNotFound},
+		{path: "/head-unsupported", want: linkcheck.StatusAlive, wantCode: http.StatusOK},
+		{path: "/broken", want: linkcheck.StatusUnknown, wantCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+			got := verifier.Check(context.Background(), server.URL+tt.path)
+			if got.Status != tt.want || got.Code != tt.wantCode {
+				t.Errorf("Check(%s) = %s (%d), want %s (%d)", tt.path, got.Status, got.Code, tt.want, tt.wantCode)
+			}
+		})
+	}
+}
+
+// TestVerifier_Verify tests that dead primary URLs are noted, and replaced by the next alive URL with Fallback.
+func TestVerifier_Verify(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := newServer(t, &requests)
+	missing, alive := server.URL+"/missing", server.URL+"/alive"
+	input := []attribution.Attribution{
+		{
+			Name: "left-pad",
+			URL:  &missing,
+			URLs: []attribution.TypedURL{
+				{Kind: attribution.URLKindRegistry, URL: missing},
+				{Kind: attribution.URLKindSearch, URL: server.URL + "/broken"},
+				{Kind: attribution.URLKindSource, URL: alive},
+			},
+			Provenance: map[string]attribution.Provenance{attribution.FieldURL: attribution.ProvenanceGenerated},
+		},
+		{Name: "lodash", URL: &alive},
+		{Name: "local"},
+	}
+
+	got, err := (&linkcheck.Verifier{}).Verify(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Verify() unexpected error: %v", err)
+	}
+	if *got[0].URL != missing || !slices.Equal(got[0].Notes, []string{"dead URL: " + missing}) {
+		t.Errorf("Verify()[0] = %s %v, want the dead URL kept and noted", *got[0].URL, got[0].Notes)
+	}
+	if !got[0].URLs[0].Dead || got[0].URLs[2].Dead || input[0].URLs[0].Dead {
+		t.Errorf("Verify()[0].URLs = %v, want only the copy of the dead URL marked", got[0].URLs)
+	}
+	if len(got[1].Notes) != 0 || got[2].URL != nil {
+		t.Errorf("Verify() = %+v, want alive and missing URLs left alone", got[1:])
+	}
+
+	got, err = (&linkcheck.Verifier{Fallback: true}).Verify(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Verify() unexpected error: %v", err)
+	}
+	if *got[0].URL != alive || got[0].Provenance[attribution.FieldURL] != attribution.ProvenanceExtracted {
+		t.Errorf("Verify() with fallback URL = %s (%s), want the alive source URL, extracted", *got[0].URL,
+			got[0].Provenance[attribution.FieldURL])
+	}
+	if *input[0].URL != missing {
+		t.Errorf("Verify() modified the input URL: %s", *input[0].URL)
+	}
+}
+
+// TestVerifier_CheckAll tests that every URL is checked once, in parallel.
+func TestVerifier_CheckAll(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := newServer(t, &requests)
+	verifier := &linkcheck.Verifier{Concurrency: 2}
+
+	urls := []string{server.URL + "/alive", server.URL + "/missing", server.URL + "/broken"}
+	results := verifier.CheckAll(context.Background(), urls)
+
+	want := []linkcheck.Status{linkcheck.StatusAlive, linkcheck.StatusDead, linkcheck.StatusUnknown}
+	if len(results) != len(urls) {
+		t.Fatalf("CheckAll() returned %d results, want %d", len(results), len(urls))
+	}
+	for i, url := range urls {
+		if results[url].Status != want[i] {
+			t.Errorf("CheckAll() status of %s = %s, want %s", url, results[url].Status, want[i])
+		}
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("server received %d requests, want 3", got)
+	}
+}
+
+// TestVerifier_Cache tests that cached results are reused, and only conclusive results are cached.
+func TestVerifier_Cache(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := newServer(t, &requests)
+	verifier := &linkcheck.Verifier{Cache: linkcheck.NewCache(), MaxAge: time.Hour}
+
+	for range 2 {
+		verifier.Check(context.Background(), server.URL+"/missing")
+		verifier.Check(context.Background(), server.URL+"/broken")
+	}
+
+	if got := requests.Load(); got != 3 {
+		t.Errorf("server received %d requests, want 3 (the dead URL once, the broken URL twice)", got)
+	}
+}
+
+// TestVerifier_CacheExpired tests that results cached longer than MaxAge ago are checked again.
+func TestVerifier_CacheExpired(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := newServer(t, &requests)
+	cache := linkcheck.NewCache()
+	stale := linkcheck.Result{Status: linkcheck.StatusDead, Checked: time.Now().Add(-2 * time.Hour)}
+	cache.Store(server.URL+"/alive", stale)
+	verifier := &linkcheck.Verifier{Cache: cache, MaxAge: time.Hour}
+
+	if r := verifier.Check(context.Background(), server.URL+"/alive"); r.Status != linkcheck.StatusAlive {
+		t.Errorf("Check() = %+v, want the URL checked again", r)
+	}
+	if got := requests.Load(); got == 0 {
+		t.Error("server received no requests, want the expired result checked again")
+	}
+}
+
+// TestVerifier_Offline tests that offline checks only use cached results and send no requests.
+func TestVerifier_Offline(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := newServer(t, &requests)
+	cache := linkcheck.NewCache()
+	cache.Store(server.URL+"/missing", linkcheck.Result{Status: linkcheck.StatusDead, Checked: time.Now()})
+	verifier := &linkcheck.Verifier{Cache: cache, Offline: true}
+
+	if r := verifier.Check(context.Background(), server.URL+"/missing"); r.Status != linkcheck.StatusDead {
+		t.Errorf("Check(cached) = %+v, want the cached dead result", r)
+	}
+	if r := verifier.Check(context.Background(), server.URL+"/alive"); r.Status != linkcheck.StatusUnknown {
+		t.Errorf("Check(uncached) = %+v, want unknown", r)
+	}
+	if got := requests.Load(); got != 0 {
+		t.Errorf("server received %d requests offline, want none", got)
+	}
+}
+
+// TestVerifier_Interval tests that requests are spaced out by the interval.
+func TestVerifier_Interval(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := newServer(t, &requests)
+	const interval = 20 * time.Millisecond
+	verifier := &linkcheck.Verifier{Concurrency: 3, Interval: interval}
+
+	input := make([]attribution.Attribution, 0, 3)
+	for _, path := range []string{"/alive", "/missing", "/head-unsupported"} {
+		url := server.URL + path
+		input = append(input, attribution.Attribution{Name: path, URL: &url})
+	}
+
+	start := time.Now()
+	if _, err := verifier.Verify(context.Background(), input); err != nil {
+		t.Fatalf("Verify() unexpected error: %v", err)
+	}
+	// Four requests (the HEAD-less URL takes two), each at least an interval after the previous one
+	if elapsed := time.Since(start); elapsed < 3*interval {
+		t.Errorf("Verify() took %s, want at least %s", elapsed, 3*interval)
+	}
+}
+
+// TestVerifier_Canceled tests that Verify fails if the context is canceled.
+func TestVerifier_Canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	url := "https://example.com"
+	if _, err := (&linkcheck.Verifier{}).Verify(ctx, []attribution.Attribution{{URL: &url}}); err == nil {
+		t.Error("Verify() with a canceled context succeeded, want an error")
+	}
+}
@@ -0,0 +1,53 @@
+package lockfileextract
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// extractCargoLock extracts the registry packages of a Cargo.lock, in file order.
+// Packages without a source (the workspace's own crates) are skipped.
+func extractCargoLock(data []byte) []attribution.Attribution {
+	packages := []attribution.Attribution{}
+
+	var name, version, source string
+	flush := func() {
+		if name != "" && source != "" {
+			packages = append(packages, newAttribution(packageurl.TypeCargo, "", name, version))
+		}
+		name, version, source = "", "", ""
+	}
+
+	for line := range strings.Lines(string(data)) {
+		line = strings.TrimSpace(line)
+		// Every table header ([[package]], [metadata], ...) ends the current package
+		if strings.HasPrefix(line, "[") {
+			flush()
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		unquoted, err := strconv.Unquote(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "name":
+			name = unquoted
+		case "version":
+			version = unquoted
+		case "source":
+			source = unquoted
+		}
+	}
+	flush()
+
+	return packages
+}
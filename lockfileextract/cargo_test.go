@@ -0,0 +1,46 @@
+package lockfileextract_test
+
+import (
+	"testing"
+
+	"github.com/boringbin/sbomattr/lockfileextract"
+)
+
+// TestExtract_CargoLock tests extracting a Cargo.lock.
+func TestExtract_CargoLock(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`# This file is automatically @generated by Cargo.
+version = 3
+
+[[package]]
+name = "my-app"
+version = "0.1.0"
+dependencies = [
+ "serde",
+]
+
+[[package]]
+name = "serde"
+version = "1.0.193"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+checksum = "25dd9975e68d0cb5aa1120c288333fc98731bd1dd12f561e468ea4728c042b89"
+
+[[package]]
+name = "itoa"
+version = "1.0.9"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[metadata]
+`)
+
+	got, err := lockfileextract.Extract("Cargo.lock", data)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	checkAttributions(t, got, []want{
+		{name: "serde", version: "1.0.193", purl: "pkg:cargo/serde@1.0.193"},
+		{name: "itoa", version: "1.0.9", purl: "pkg:cargo/itoa@1.0.9"},
+	})
+}
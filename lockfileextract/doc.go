@@ -0,0 +1,7 @@
+// Package lockfileextract provides first-pass attribution extraction from package manager lockfiles, for projects
+// that do not produce SBOMs yet.
+//
+// Supported lockfiles: package-lock.json (npm), go.mod and go.sum (Go modules), requirements.txt (pip), and
+// Cargo.lock (Cargo). Every extracted attribution records ProvenanceLockfile for its purl, so lockfile-derived
+// entries stay distinguishable from SBOM-derived ones.
+package lockfileextract
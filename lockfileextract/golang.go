@@ -0,0 +1,86 @@
+package lockfileextract
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/gobinextract"
+)
+
+// extractGoMod extracts the required modules of a go.mod, in file order.
+// Both single-line and block require directives are supported; replace directives are ignored.
+func extractGoMod(data []byte) []attribution.Attribution {
+	packages := []attribution.Attribution{}
+	inRequireBlock := false
+
+	for line := range strings.Lines(string(data)) {
+		// Drop comments, including "// indirect" markers
+		line, _, _ = strings.Cut(line, "//")
+		fields := strings.Fields(line)
+
+		switch {
+		case len(fields) == 0:
+			continue
+		case inRequireBlock && fields[0] == ")":
+			inRequireBlock = false
+			continue
+		case !inRequireBlock && fields[0] == "require" && len(fields) == 2 && fields[1] == "(":
+			inRequireBlock = true
+			continue
+		case !inRequireBlock && fields[0] == "require":
+			fields = fields[1:]
+		case !inRequireBlock:
+			continue
+		}
+
+		const moduleFields = 2 // <path> <version>
+		if len(fields) == moduleFields {
+			packages = append(packages, goModuleAttribution(fields[0], fields[1]))
+		}
+	}
+
+	return packages
+}
+
+// extractGoSum extracts the modules listed in a go.sum, sorted by path and version.
+// Entries that only cover a module's go.mod file are skipped, since the module's code is not used.
+func extractGoSum(data []byte) []attribution.Attribution {
+	type module struct{ path, version string }
+	var modules []module
+
+	for line := range strings.Lines(string(data)) {
+		const sumFields = 3 // <path> <version> <hash>
+		fields := strings.Fields(line)
+		if len(fields) != sumFields || strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+		m := module{path: fields[0], version: fields[1]}
+		if !slices.Contains(modules, m) {
+			modules = append(modules, m)
+		}
+	}
+
+	slices.SortFunc(modules, func(a, b module) int {
+		return strings.Compare(a.path+"@"+a.version, b.path+"@"+b.version)
+	})
+
+	packages := make([]attribution.Attribution, 0, len(modules))
+	for _, m := range modules {
+		packages = append(packages, goModuleAttribution(m.path, m.version))
+	}
+	return packages
+}
+
+// goModuleAttribution returns the attribution of a Go module.
+func goModuleAttribution(path, version string) attribution.Attribution {
+	a := attribution.Attribution{
+		Name:    path,
+		Version: version,
+		Purl:    gobinextract.ModulePurl(path, version),
+	}
+	a = a.WithProvenance(attribution.FieldPurl, attribution.ProvenanceLockfile)
+
+	// URL generation is best-effort
+	return a.WithGeneratedURLs()
+}
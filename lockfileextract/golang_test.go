@@ -0,0 +1,67 @@
+package lockfileextract_test
+
+import (
+	"testing"
+
+	"github.com/boringbin/sbomattr/lockfileextract"
+)
+
+// TestExtract_GoMod tests extracting a go.mod.
+func TestExtract_GoMod(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`module example.com/app
+
+go 1.24
+
+require github.com/package-url/packageurl-go v0.1.3
+
+require (
+	golang.org/x/text v0.14.0 // indirect
+	// github.com/commented/out v1.0.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+replace golang.org/x/text => ../text
+`)
+
+	got, err := lockfileextract.Extract("go.mod", data)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	checkAttributions(t, got, []want{
+		{
+			name:    "github.com/package-url/packageurl-go",
+			version: "v0.1.3",
+			purl:    "pkg:golang/github.com/package-url/packageurl-go@v0.1.3",
+		},
+		{name: "golang.org/x/text", version: "v0.14.0", purl: "pkg:golang/golang.org/x/text@v0.14.0"},
+		{name: "gopkg.in/yaml.v3", version: "v3.0.1", purl: "pkg:golang/gopkg.in/yaml.v3@v3.0.1"},
+	})
+}
+
+// TestExtract_GoSum tests extracting a go.sum.
+func TestExtract_GoSum(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`golang.org/x/text v0.14.0 h1:ScX5w1eTa3QqT8oi6+ziP7dTV1S2+ALU0bI+0zXKWiQ=
+golang.org/x/text v0.14.0/go.mod h1:18ZOQIKpY8NJVqYksKHtTdi31H5itFRjB5/qKTNYzSU=
+github.com/only/gomod v1.0.0/go.mod h1:aaaa=
+github.com/package-url/packageurl-go v0.1.3 h1:4juMED3hHiz0set3Vq3KeQ75KD1avthoXLtmE3I0PLs=
+`)
+
+	got, err := lockfileextract.Extract("go.sum", data)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	checkAttributions(t, got, []want{
+		{
+			name:    "github.com/package-url/packageurl-go",
+			version: "v0.1.3",
+			purl:    "pkg:golang/github.com/package-url/packageurl-go@v0.1.3",
+		},
+		{name: "golang.org/x/text", version: "v0.14.0", purl: "pkg:golang/golang.org/x/text@v0.14.0"},
+	})
+}
@@ -0,0 +1,90 @@
+package lockfileextract
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/package-url/packageurl-go"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// ErrUnsupportedLockfile is returned when a file is not a supported lockfile.
+var ErrUnsupportedLockfile = errors.New("unsupported lockfile")
+
+// Kind identifies a supported lockfile type.
+type Kind string
+
+// Supported lockfile kinds, named after their file names.
+const (
+	// KindPackageLock is an npm package-lock.json.
+	KindPackageLock Kind = "package-lock.json"
+	// KindGoMod is a Go go.mod.
+	KindGoMod Kind = "go.mod"
+	// KindGoSum is a Go go.sum.
+	KindGoSum Kind = "go.sum"
+	// KindRequirements is a pip requirements.txt.
+	KindRequirements Kind = "requirements.txt"
+	// KindCargoLock is a Cargo Cargo.lock.
+	KindCargoLock Kind = "Cargo.lock"
+)
+
+// Kinds returns the supported lockfile kinds.
+func Kinds() []Kind {
+	return []Kind{KindPackageLock, KindGoMod, KindGoSum, KindRequirements, KindCargoLock}
+}
+
+// Detect returns the lockfile kind of a file from its base name.
+// Returns ok as false if the file is not a supported lockfile.
+func Detect(filename string) (Kind, bool) {
+	kind := Kind(filepath.Base(filename))
+	if !slices.Contains(Kinds(), kind) {
+		return "", false
+	}
+	return kind, true
+}
+
+// Extract extracts attributions from the contents of a lockfile, detecting its kind from the file name.
+// Returns ErrUnsupportedLockfile if the file is not a supported lockfile.
+func Extract(filename string, data []byte) ([]attribution.Attribution, error) {
+	kind, ok := Detect(filename)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedLockfile, filepath.Base(filename))
+	}
+
+	switch kind {
+	case KindPackageLock:
+		return extractPackageLock(data)
+	case KindGoMod:
+		return extractGoMod(data), nil
+	case KindGoSum:
+		return extractGoSum(data), nil
+	case KindRequirements:
+		return extractRequirements(data), nil
+	case KindCargoLock:
+		return extractCargoLock(data), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedLockfile, kind)
+	}
+}
+
+// newAttribution returns a lockfile-derived attribution for a package, with its purl and generated URL.
+// The namespace may be empty.
+func newAttribution(purlType, namespace, name, version string) attribution.Attribution {
+	fullName := name
+	if namespace != "" {
+		fullName = namespace + "/" + name
+	}
+
+	a := attribution.Attribution{
+		Name:    fullName,
+		Version: version,
+		Purl:    packageurl.NewPackageURL(purlType, namespace, name, version, nil, "").ToString(),
+	}
+	a = a.WithProvenance(attribution.FieldPurl, attribution.ProvenanceLockfile)
+
+	// URL generation is best-effort - unsupported types get no URL
+	return a.WithGeneratedURLs()
+}
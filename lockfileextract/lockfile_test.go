@@ -0,0 +1,76 @@
+package lockfileextract_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/lockfileextract"
+)
+
+// TestDetect tests the Detect function.
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		filename string
+		want     lockfileextract.Kind
+		wantOK   bool
+	}{
+		{filename: "app/package-lock.json", want: lockfileextract.KindPackageLock, wantOK: true},
+		{filename: "go.mod", want: lockfileextract.KindGoMod, wantOK: true},
+		{filename: "/src/go.sum", want: lockfileextract.KindGoSum, wantOK: true},
+		{filename: "requirements.txt", want: lockfileextract.KindRequirements, wantOK: true},
+		{filename: "Cargo.lock", want: lockfileextract.KindCargoLock, wantOK: true},
+		{filename: "sbom.json", wantOK: false},
+		{filename: "yarn.lock", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := lockfileextract.Detect(tt.filename)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Detect(%q) = %q, %v, want %q, %v", tt.filename, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestExtract_Unsupported tests that unsupported files are rejected.
+func TestExtract_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	_, err := lockfileextract.Extract("yarn.lock", nil)
+	if !errors.Is(err, lockfileextract.ErrUnsupportedLockfile) {
+		t.Errorf("Extract() error = %v, want ErrUnsupportedLockfile", err)
+	}
+}
+
+// want describes an expected extracted attribution.
+type want struct {
+	name    string
+	version string
+	purl    string
+}
+
+// checkAttributions checks extracted attributions against the expected ones, and that they are labeled as
+// lockfile-derived.
+func checkAttributions(t *testing.T, got []attribution.Attribution, wants []want) {
+	t.Helper()
+
+	if len(got) != len(wants) {
+		t.Fatalf("got %d attributions, want %d: %+v", len(got), len(wants), got)
+	}
+	for i, w := range wants {
+		if got[i].Name != w.name || got[i].Version != w.version || got[i].Purl != w.purl {
+			t.Errorf("attribution %d = %s %s %s, want %s %s %s",
+				i, got[i].Name, got[i].Version, got[i].Purl, w.name, w.version, w.purl)
+		}
+		if got[i].Provenance[attribution.FieldPurl] != attribution.ProvenanceLockfile {
+			t.Errorf("attribution %d purl provenance = %q, want %q",
+				i, got[i].Provenance[attribution.FieldPurl], attribution.ProvenanceLockfile)
+		}
+	}
+}
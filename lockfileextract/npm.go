@@ -0,0 +1,82 @@
+package lockfileextract
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// packageLock is the subset of an npm package-lock.json used for extraction.
+// Lockfile versions 2 and 3 list packages by install path; version 1 nests dependencies.
+type packageLock struct {
+	Packages     map[string]packageLockEntry `json:"packages"`
+	Dependencies map[string]packageLockEntry `json:"dependencies"`
+}
+
+// packageLockEntry is a package entry of a package-lock.json.
+type packageLockEntry struct {
+	Name         string                      `json:"name"`
+	Version      string                      `json:"version"`
+	License      string                      `json:"license"`
+	Link         bool                        `json:"link"`
+	Dependencies map[string]packageLockEntry `json:"dependencies"`
+}
+
+// extractPackageLock extracts the installed packages of an npm package-lock.json, sorted by install path.
+// The root project and workspace links are skipped.
+func extractPackageLock(data []byte) ([]attribution.Attribution, error) {
+	var lock packageLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("parse package-lock.json: %w", err)
+	}
+
+	packages := []attribution.Attribution{}
+
+	if lock.Packages != nil {
+		for _, path := range slices.Sorted(maps.Keys(lock.Packages)) {
+			entry := lock.Packages[path]
+			const nodeModules = "node_modules/"
+			i := strings.LastIndex(path, nodeModules)
+			if i < 0 || entry.Link {
+				continue
+			}
+			name := path[i+len(nodeModules):]
+			packages = append(packages, npmAttribution(name, entry))
+		}
+		return packages, nil
+	}
+
+	// Lockfile version 1
+	var walk func(deps map[string]packageLockEntry)
+	walk = func(deps map[string]packageLockEntry) {
+		for _, name := range slices.Sorted(maps.Keys(deps)) {
+			packages = append(packages, npmAttribution(name, deps[name]))
+			walk(deps[name].Dependencies)
+		}
+	}
+	walk(lock.Dependencies)
+
+	return packages, nil
+}
+
+// npmAttribution returns the attribution of an npm package, named with its scope (e.g. "@babel/core").
+func npmAttribution(name string, entry packageLockEntry) attribution.Attribution {
+	namespace := ""
+	if scope, pkg, ok := strings.Cut(name, "/"); ok {
+		namespace, name = scope, pkg
+	}
+
+	a := newAttribution(packageurl.TypeNPM, namespace, name, entry.Version)
+	if entry.License != "" {
+		license := entry.License
+		a.License = &license
+		a = a.WithProvenance(attribution.FieldLicense, attribution.ProvenanceLockfile)
+	}
+	return a
+}
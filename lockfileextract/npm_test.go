@@ -0,0 +1,74 @@
+package lockfileextract_test
+
+import (
+	"testing"
+
+	"github.com/boringbin/sbomattr/lockfileextract"
+)
+
+// TestExtract_PackageLock tests extracting a version 3 package-lock.json.
+func TestExtract_PackageLock(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{
+		"name": "app",
+		"lockfileVersion": 3,
+		"packages": {
+			"": {"name": "app", "version": "1.0.0"},
+			"node_modules/@babel/core": {"version": "7.22.5", "license": "MIT"},
+			"node_modules/lodash": {"version": "4.17.21", "license": "MIT"},
+			"node_modules/a/node_modules/lodash": {"version": "3.10.1"},
+			"node_modules/workspace-pkg": {"resolved": "packages/workspace-pkg", "link": true}
+		}
+	}`)
+
+	got, err := lockfileextract.Extract("package-lock.json", data)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	checkAttributions(t, got, []want{
+		{name: "@babel/core", version: "7.22.5", purl: "pkg:npm/%40babel/core@7.22.5"},
+		{name: "lodash", version: "3.10.1", purl: "pkg:npm/lodash@3.10.1"},
+		{name: "lodash", version: "4.17.21", purl: "pkg:npm/lodash@4.17.21"},
+	})
+	if got[1].License != nil {
+		t.Errorf("nested lodash license = %v, want nil", *got[1].License)
+	}
+	if got[2].License == nil || *got[2].License != "MIT" {
+		t.Errorf("lodash license = %v, want MIT", got[2].License)
+	}
+}
+
+// TestExtract_PackageLockV1 tests extracting a version 1 package-lock.json with nested dependencies.
+func TestExtract_PackageLockV1(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{
+		"lockfileVersion": 1,
+		"dependencies": {
+			"express": {"version": "4.18.2", "dependencies": {"debug": {"version": "2.6.9"}}},
+			"react": {"version": "18.2.0"}
+		}
+	}`)
+
+	got, err := lockfileextract.Extract("package-lock.json", data)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	checkAttributions(t, got, []want{
+		{name: "express", version: "4.18.2", purl: "pkg:npm/express@4.18.2"},
+		{name: "debug", version: "2.6.9", purl: "pkg:npm/debug@2.6.9"},
+		{name: "react", version: "18.2.0", purl: "pkg:npm/react@18.2.0"},
+	})
+}
+
+// TestExtract_PackageLockInvalid tests that invalid JSON is rejected.
+func TestExtract_PackageLockInvalid(t *testing.T) {
+	t.Parallel()
+
+	if _, err := lockfileextract.Extract("package-lock.json", []byte("{")); err == nil {
+		t.Error("Extract() error = nil, want error")
+	}
+}
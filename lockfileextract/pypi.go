@@ -0,0 +1,51 @@
+package lockfileextract
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// requirementPattern matches a requirement specifier: a project name, optional extras, and an optional version
+// specifier, e.g. "requests[socks]==2.31.0".
+//
+//nolint:gochecknoglobals // Compiled once, since it is matched against every line of a requirements file.
+var requirementPattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\[[^\]]*\])?\s*` +
+	`(?:(===?)\s*([^\s;,]+))?`)
+
+// pypiSeparatorPattern matches the separator runs replaced when normalizing Python project names.
+//
+//nolint:gochecknoglobals // Compiled once, since it is matched against every requirement name.
+var pypiSeparatorPattern = regexp.MustCompile(`[-_.]+`)
+
+// extractRequirements extracts the requirements of a pip requirements.txt, in file order.
+// Only exact pins (== or ===) record a version. Options (-r, -e, --hash, ...), URLs, and comments are skipped.
+func extractRequirements(data []byte) []attribution.Attribution {
+	packages := []attribution.Attribution{}
+
+	for line := range strings.Lines(string(data)) {
+		line, _, _ = strings.Cut(line, "#")
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "-") || strings.Contains(line, "://") {
+			continue
+		}
+
+		match := requirementPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		packages = append(packages, newAttribution(packageurl.TypePyPi, "", normalizePyPIName(match[1]), match[3]))
+	}
+
+	return packages
+}
+
+// normalizePyPIName normalizes a Python project name as required by the pypi purl type: lowercase, with runs of
+// "-", "_", and "." replaced by "-".
+func normalizePyPIName(name string) string {
+	return pypiSeparatorPattern.ReplaceAllString(strings.ToLower(name), "-")
+}
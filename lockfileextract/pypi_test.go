@@ -0,0 +1,32 @@
+package lockfileextract_test
+
+import (
+	"testing"
+
+	"github.com/boringbin/sbomattr/lockfileextract"
+)
+
+// TestExtract_Requirements tests extracting a requirements.txt.
+func TestExtract_Requirements(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`# Production dependencies
+-r base.txt
+--index-url https://pypi.org/simple
+requests[socks]==2.31.0 ; python_version >= "3.8"
+Flask_SQLAlchemy==3.1.1  # pinned
+numpy>=1.24
+git+https://github.com/example/pkg.git#egg=pkg
+`)
+
+	got, err := lockfileextract.Extract("requirements.txt", data)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	checkAttributions(t, got, []want{
+		{name: "requests", version: "2.31.0", purl: "pkg:pypi/requests@2.31.0"},
+		{name: "flask-sqlalchemy", version: "3.1.1", purl: "pkg:pypi/flask-sqlalchemy@3.1.1"},
+		{name: "numpy", version: "", purl: "pkg:pypi/numpy"},
+	})
+}
@@ -0,0 +1,3 @@
+// Package manifest records the content digest and extracted attributions of each input file of a run, so a later
+// run can skip unchanged files and reuse their cached results (incremental mode).
+package manifest
@@ -0,0 +1,115 @@
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// currentVersion is the manifest format version. Manifests with another version are ignored on Load.
+// Version 2 added the root component of each file.
+const currentVersion = 2
+
+// ErrUnsupportedVersion is returned by Load when the manifest was written by an incompatible version of sbomattr.
+var ErrUnsupportedVersion = errors.New("unsupported manifest version")
+
+// Entry is the cached result of one input file.
+type Entry struct {
+	// Digest is the content digest of the file, as returned by Digest.
+	Digest string `json:"digest"`
+	// Format is the detected format of the file (e.g. "spdx" or "cyclonedx"), if recorded.
+	Format string `json:"format,omitempty"`
+	// Subject is the name of the root component the SBOM describes, if recorded.
+	Subject string `json:"subject,omitempty"`
+	// Root is the attribution of the root component the SBOM describes (CycloneDX metadata.component), if recorded.
+	// It is not part of Attributions.
+	Root *attribution.Attribution `json:"root,omitempty"`
+	// Attributions are the attributions extracted from the file, before deduplication.
+	Attributions []attribution.Attribution `json:"attributions"`
+	// Edges are the dependencies between the attributions declared by the file, if they were requested.
+	Edges []attribution.Edge `json:"edges,omitempty"`
+}
+
+// Manifest maps input file names to their cached results.
+type Manifest struct {
+	// Version is the manifest format version.
+	Version int `json:"version"`
+	// Extraction identifies the extraction options the results were produced with (e.g. SPDX relationship filtering),
+	// or is empty for the default options. Results produced with other options must not be reused.
+	Extraction string `json:"extraction,omitempty"`
+	// Files maps each input file name to its cached result.
+	Files map[string]Entry `json:"files"`
+}
+
+// New returns an empty manifest.
+func New() *Manifest {
+	return &Manifest{Version: currentVersion, Files: make(map[string]Entry)}
+}
+
+// Load reads a manifest from a JSON file.
+// Returns an empty manifest if the file does not exist, so the first run of incremental mode processes every file.
+// Returns ErrUnsupportedVersion if the manifest has an unknown format version.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return New(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+
+	var m Manifest
+	if err = json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("decode manifest: %w", err)
+	}
+	if m.Version != currentVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, m.Version)
+	}
+	if m.Files == nil {
+		m.Files = make(map[string]Entry)
+	}
+
+	return &m, nil
+}
+
+// Save writes the manifest to a JSON file, replacing it atomically.
+func (m *Manifest) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode manifest: %w", err)
+	}
+
+	// Write to a temporary file first so a failed write never leaves a truncated manifest behind
+	tmp := path + ".tmp"
+	const filePerm = 0o644
+	if err = os.WriteFile(tmp, data, filePerm); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	if err = os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+
+	return nil
+}
+
+// Lookup returns the cached result of a file if its content digest is unchanged.
+// Returns ok as false if the file is not in the manifest or has changed.
+func (m *Manifest) Lookup(filename, digest string) (Entry, bool) {
+	entry, ok := m.Files[filename]
+	if !ok || entry.Digest != digest {
+		return Entry{}, false
+	}
+	return entry, true
+}
+
+// Digest returns the content digest of a file, e.g. "sha256:<hex>".
+func Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
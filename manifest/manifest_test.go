@@ -0,0 +1,104 @@
+package manifest_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/manifest"
+)
+
+// TestLoad_Missing tests that a missing manifest loads as an empty one.
+func TestLoad_Missing(t *testing.T) {
+	t.Parallel()
+
+	m, err := manifest.Load(filepath.Join(t.TempDir(), "manifest.json"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if m.Files == nil || len(m.Files) != 0 {
+		t.Errorf("Load() files = %v, want empty map", m.Files)
+	}
+}
+
+// TestManifest_SaveLoad tests that a saved manifest loads back unchanged.
+func TestManifest_SaveLoad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	m := manifest.New()
+	m.Files["sbom.json"] = manifest.Entry{
+		Digest:       manifest.Digest([]byte("{}")),
+		Subject:      "app",
+		Attributions: []attribution.Attribution{{Name: "lodash", Purl: "pkg:npm/lodash@4.17.21"}},
+	}
+
+	if err := m.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Save() left a temporary file behind: %v", err)
+	}
+
+	loaded, err := manifest.Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	entry, ok := loaded.Lookup("sbom.json", manifest.Digest([]byte("{}")))
+	if !ok {
+		t.Fatal("Lookup() ok = false, want true for unchanged file")
+	}
+	if entry.Subject != "app" || len(entry.Attributions) != 1 || entry.Attributions[0].Name != "lodash" {
+		t.Errorf("Lookup() = %+v, want the saved entry", entry)
+	}
+
+	if _, changed := loaded.Lookup("sbom.json", manifest.Digest([]byte("[]"))); changed {
+		t.Error("Lookup() ok = true, want false for changed file")
+	}
+	if _, missing := loaded.Lookup("other.json", manifest.Digest([]byte("{}"))); missing {
+		t.Error("Lookup() ok = true, want false for unknown file")
+	}
+}
+
+// TestLoad_Errors tests that invalid manifests are rejected.
+func TestLoad_Errors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte("not json"), 0600); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+	if _, err := manifest.Load(invalid); err == nil {
+		t.Error("Load() error = nil, want error for invalid JSON")
+	}
+
+	future := filepath.Join(dir, "future.json")
+	if err := os.WriteFile(future, []byte(`{"version": 99, "files": {}}`), 0600); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+	if _, err := manifest.Load(future); !errors.Is(err, manifest.ErrUnsupportedVersion) {
+		t.Errorf("Load() error = %v, want ErrUnsupportedVersion", err)
+	}
+}
+
+// TestDigest tests that digests are stable and content-dependent.
+func TestDigest(t *testing.T) {
+	t.Parallel()
+
+	a := manifest.Digest([]byte("a"))
+	if !strings.HasPrefix(a, "sha256:") || len(a) != len("sha256:")+64 {
+		t.Errorf("Digest() = %q, want sha256:<hex>", a)
+	}
+	if a != manifest.Digest([]byte("a")) {
+		t.Error("Digest() is not stable")
+	}
+	if a == manifest.Digest([]byte("b")) {
+		t.Error("Digest() is the same for different content")
+	}
+}
@@ -0,0 +1,2 @@
+// Package notify posts notifications about new policy violations to webhooks (Slack or generic JSON).
+package notify
@@ -0,0 +1,116 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/boringbin/sbomattr/policy"
+)
+
+// ErrUnexpectedStatus is returned when a webhook responds with a non-2xx status code.
+var ErrUnexpectedStatus = errors.New("unexpected webhook response status")
+
+// Format is the payload format of a webhook.
+type Format string
+
+const (
+	// FormatJSON posts the Notification as JSON.
+	FormatJSON Format = "json"
+	// FormatSlack posts a Slack incoming webhook message.
+	FormatSlack Format = "slack"
+)
+
+// ParseFormat parses a webhook payload format name.
+// Returns an error for unknown formats.
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case FormatJSON:
+		return FormatJSON, nil
+	case FormatSlack:
+		return FormatSlack, nil
+	default:
+		return "", fmt.Errorf("unknown webhook format %q (valid formats: json, slack)", s)
+	}
+}
+
+// Notification reports the new policy violations of a run.
+type Notification struct {
+	// Product is the product of the run.
+	Product string `json:"product"`
+	// Version is the product version of the run.
+	Version string `json:"version"`
+	// Violations are the policy violations that are not in the baseline.
+	Violations []policy.Violation `json:"violations"`
+}
+
+// Webhook posts notifications to a URL.
+type Webhook struct {
+	// URL is the webhook URL.
+	URL string
+	// Format is the payload format. Defaults to FormatJSON.
+	Format Format
+	// Client is the HTTP client used to post notifications. Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// Notify posts the notification to the webhook.
+// Returns ErrUnexpectedStatus if the webhook responds with a non-2xx status code.
+func (w Webhook) Notify(ctx context.Context, n Notification) error {
+	var payload any = n
+	if w.Format == FormatSlack {
+		payload = slackMessage{Text: slackText(n)}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: %s", ErrUnexpectedStatus, resp.Status)
+	}
+	return nil
+}
+
+// slackMessage is a Slack incoming webhook message.
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// slackText formats a notification as a Slack message text, one line per violation.
+func slackText(n Notification) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d new license policy violation(s) in %s %s:", len(n.Violations), n.Product, n.Version)
+	for _, v := range n.Violations {
+		name := v.Attribution.Name
+		if v.Attribution.Purl != "" {
+			name = v.Attribution.Purl
+		}
+		fmt.Fprintf(&b, "\n• %s (%s)", name, v.License)
+	}
+	return b.String()
+}
@@ -0,0 +1,118 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/notify"
+	"github.com/boringbin/sbomattr/policy"
+)
+
+// testNotification returns the notification used by the webhook tests.
+func testNotification() notify.Notification {
+	return notify.Notification{
+		Product: "acme",
+		Version: "v1.2.0",
+		Violations: []policy.Violation{{
+			Attribution: attribution.Attribution{Name: "ghostscript", Purl: "pkg:generic/ghostscript@10.0"},
+			License:     "AGPL-3.0-only",
+		}},
+	}
+}
+
+// newRecorder starts a test server that records the last request body and responds with status.
+func newRecorder(t *testing.T, status int, body *string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		data, _ := io.ReadAll(r.Body)
+		*body = string(data)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestWebhook_NotifyJSON tests posting a generic JSON notification.
+func TestWebhook_NotifyJSON(t *testing.T) {
+	t.Parallel()
+
+	var body string
+	server := newRecorder(t, http.StatusOK, &body)
+
+	webhook := notify.Webhook{URL: server.URL, Client: server.Client()}
+	if err := webhook.Notify(context.Background(), testNotification()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	var got notify.Notification
+	if err := json.Unmarshal([]byte(body), &got); err != nil {
+		t.Fatalf("payload is not valid JSON: %v", err)
+	}
+	if got.Product != "acme" || len(got.Violations) != 1 || got.Violations[0].License != "AGPL-3.0-only" {
+		t.Errorf("payload = %+v, want the notification", got)
+	}
+}
+
+// TestWebhook_NotifySlack tests posting a Slack notification.
+func TestWebhook_NotifySlack(t *testing.T) {
+	t.Parallel()
+
+	var body string
+	server := newRecorder(t, http.StatusOK, &body)
+
+	webhook := notify.Webhook{URL: server.URL, Format: notify.FormatSlack, Client: server.Client()}
+	if err := webhook.Notify(context.Background(), testNotification()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	var got struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal([]byte(body), &got); err != nil {
+		t.Fatalf("payload is not valid JSON: %v", err)
+	}
+	want := "1 new license policy violation(s) in acme v1.2.0:\n• pkg:generic/ghostscript@10.0 (AGPL-3.0-only)"
+	if got.Text != want {
+		t.Errorf("text = %q, want %q", got.Text, want)
+	}
+}
+
+// TestWebhook_NotifyError tests that a non-2xx response is reported as an error.
+func TestWebhook_NotifyError(t *testing.T) {
+	t.Parallel()
+
+	var body string
+	server := newRecorder(t, http.StatusInternalServerError, &body)
+
+	webhook := notify.Webhook{URL: server.URL, Client: server.Client()}
+	err := webhook.Notify(context.Background(), testNotification())
+	if !errors.Is(err, notify.ErrUnexpectedStatus) {
+		t.Errorf("Notify() error = %v, want ErrUnexpectedStatus", err)
+	}
+	if !strings.Contains(err.Error(), "500") {
+		t.Errorf("Notify() error = %v, want the status code", err)
+	}
+}
+
+// TestParseFormat tests the ParseFormat function.
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	if got, err := notify.ParseFormat("Slack"); err != nil || got != notify.FormatSlack {
+		t.Errorf("ParseFormat(Slack) = %q, %v, want slack, nil", got, err)
+	}
+	if _, err := notify.ParseFormat("teams"); err == nil {
+		t.Error("ParseFormat(teams) error = nil, want error")
+	}
+}
@@ -0,0 +1,122 @@
+package policy
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/package-url/packageurl-go"
+)
+
+// Corrections replace the licenses that scanners got wrong with the ones verified by hand, e.g. by a legal review.
+type Corrections struct {
+	// Corrections lists the corrected packages. The first correction matching a package applies.
+	Corrections []Correction `json:"corrections"`
+}
+
+// Correction declares the verified license of a package, identified either by purl or by name and version.
+type Correction struct {
+	// Purl identifies the corrected package. It must match the purl of an attribution, once both are canonicalized
+	// (see attribution.CanonicalPurl); without a version, it matches every version of the package.
+	Purl string `json:"purl,omitempty"`
+	// Name identifies the corrected package if Purl is empty, qualified by its group (see
+	// attribution.Attribution.QualifiedName). It is matched ignoring case.
+	Name string `json:"name,omitempty"`
+	// Version restricts a Name correction to one version. Empty matches every version.
+	Version string `json:"version,omitempty"`
+	// License is the verified license expression.
+	License string `json:"license"`
+	// Reason optionally explains the correction, e.g. "verified by legal, see LEGAL-123". It is added to the notes of
+	// the corrected attributions.
+	Reason string `json:"reason,omitempty"`
+}
+
+// LoadCorrections reads corrections from a JSON file.
+// Returns an error if a correction has no license, or neither a purl nor a name.
+func LoadCorrections(path string) (Corrections, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Corrections{}, fmt.Errorf("read corrections: %w", err)
+	}
+
+	var corrections Corrections
+	if err = json.Unmarshal(data, &corrections); err != nil {
+		return Corrections{}, fmt.Errorf("decode corrections: %w", err)
+	}
+	for i, c := range corrections.Corrections {
+		if strings.TrimSpace(c.License) == "" || (c.Purl == "" && c.Name == "") {
+			return Corrections{}, fmt.Errorf("invalid correction %d: a license and a purl or name are required", i+1)
+		}
+	}
+	return corrections, nil
+}
+
+// Apply returns a copy of attributions where the license of every corrected package is replaced by the verified one.
+// Corrected licenses are flagged as overridden in the provenance (see attribution.ProvenanceOverridden), and noted
+// along with the replaced license and the reason of the correction. Licenses that already match are left untouched.
+// The logger parameter is optional; pass nil to disable logging.
+func (c Corrections) Apply(attributions []attribution.Attribution, logger *slog.Logger) []attribution.Attribution {
+	result := make([]attribution.Attribution, 0, len(attributions))
+
+	for _, a := range attributions {
+		correction, ok := c.find(a)
+		if !ok || (a.Exception == nil && deref(a.License) == correction.License) {
+			result = append(result, a)
+			continue
+		}
+
+		if logger != nil {
+			logger.Debug("corrected license", "name", a.Name, "purl", a.Purl, "license", deref(a.License),
+				"corrected", correction.License)
+		}
+		replaced := cmp.Or(a.LicenseExpression(), attribution.LicenseNoAssertion)
+		note := fmt.Sprintf("license corrected from %q to %q", replaced, correction.License)
+		if correction.Reason != "" {
+			note += ": " + correction.Reason
+		}
+		a.License = &correction.License
+		a.Exception = nil
+		a.Notes = append(a.Notes[:len(a.Notes):len(a.Notes)], note)
+		result = append(result, a.WithProvenance(attribution.FieldLicense, attribution.ProvenanceOverridden))
+	}
+
+	return result
+}
+
+// find returns the first correction matching an attribution. Returns ok as false if there is none.
+func (c Corrections) find(a attribution.Attribution) (Correction, bool) {
+	for _, correction := range c.Corrections {
+		if correction.Purl != "" {
+			if a.Purl != "" && samePackage(correction.Purl, a.Purl) {
+				return correction, true
+			}
+			continue
+		}
+		if strings.EqualFold(correction.Name, a.QualifiedName()) &&
+			(correction.Version == "" || correction.Version == a.Version) {
+			return correction, true
+		}
+	}
+	return Correction{}, false
+}
+
+// samePackage reports whether the purl of a correction matches the purl of an attribution, ignoring the version and
+// qualifiers of the attribution purl if the correction purl has no version.
+func samePackage(correctionPurl, purl string) bool {
+	want, err := packageurl.FromString(correctionPurl)
+	if err != nil || want.Version != "" {
+		return attribution.CanonicalPurl(correctionPurl) == attribution.CanonicalPurl(purl)
+	}
+	got, err := packageurl.FromString(purl)
+	if err != nil {
+		return false
+	}
+	got.Version = ""
+	got.Qualifiers = nil
+	want.Qualifiers = nil
+	return attribution.CanonicalPurl(want.ToString()) == attribution.CanonicalPurl(got.ToString())
+}
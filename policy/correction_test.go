@@ -0,0 +1,86 @@
+package policy_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/policy"
+)
+
+// TestCorrections_Apply tests that licenses are corrected by purl, or by name and version, and flagged as overridden.
+func TestCorrections_Apply(t *testing.T) {
+	t.Parallel()
+
+	attrs := []attribution.Attribution{
+		{Name: "lodash", License: strPtr("NOASSERTION"), Purl: "pkg:npm/lodash@4.17.21"},
+		{Name: "core", Group: "@angular", License: strPtr("BUSL-1.1"), Purl: "pkg:npm/%40angular/core@17.0.0"},
+		{Name: "zlib", Version: "1.3", License: strPtr("Zlib")},
+		{Name: "libfoo", Version: "2.0", License: strPtr("GPL-2.0-only"), Exception: strPtr("Classpath-exception-2.0")},
+		{Name: "libfoo", Version: "3.0", License: strPtr("GPL-2.0-only")},
+	}
+	corrections := policy.Corrections{Corrections: []policy.Correction{
+		{Purl: "pkg:npm/Lodash@4.17.21", License: "MIT", Reason: "verified by legal"},
+		{Purl: "pkg:npm/%40angular/core", License: "MIT"},
+		{Name: "zlib", License: "Zlib"},
+		{Name: "LIBFOO", Version: "2.0", License: "GPL-2.0-only"},
+	}}
+
+	result := corrections.Apply(attrs, nil)
+
+	wantLicenses := []string{"MIT", "MIT", "Zlib", "GPL-2.0-only", "GPL-2.0-only"}
+	wantOverridden := []bool{true, true, false, true, false}
+	for i, a := range result {
+		if *a.License != wantLicenses[i] {
+			t.Errorf("Apply() license of %s = %q, want %q", a.Name, *a.License, wantLicenses[i])
+		}
+		overridden := a.Provenance[attribution.FieldLicense] == attribution.ProvenanceOverridden
+		if overridden != wantOverridden[i] {
+			t.Errorf("Apply() overridden %s = %v, want %v", a.Name, overridden, wantOverridden[i])
+		}
+	}
+
+	wantNote := `license corrected from "NOASSERTION" to "MIT": verified by legal`
+	if !slices.Equal(result[0].Notes, []string{wantNote}) {
+		t.Errorf("Apply() notes = %q, want %q", result[0].Notes, wantNote)
+	}
+	if result[3].Exception != nil {
+		t.Errorf("Apply() kept the exception %q of a corrected license", *result[3].Exception)
+	}
+	if *attrs[0].License != "NOASSERTION" || attrs[0].Provenance != nil {
+		t.Error("Apply() modified its input")
+	}
+}
+
+// TestLoadCorrections tests that corrections are read from JSON, and incomplete corrections are rejected.
+func TestLoadCorrections(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{name: "valid", content: `{"corrections": [{"name": "zlib", "version": "1.3", "license": "Zlib"}]}`},
+		{name: "no license", content: `{"corrections": [{"purl": "pkg:npm/lodash"}]}`, wantErr: true},
+		{name: "no package", content: `{"corrections": [{"license": "MIT"}]}`, wantErr: true},
+		{name: "malformed", content: `{"corrections": `, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(dir, tt.name+".json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := policy.LoadCorrections(path); (err != nil) != tt.wantErr {
+				t.Errorf("LoadCorrections() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// Package policy checks attributions against license policies, forbidden license categories, and pinned values, and
+// reports violations and deviations.
+package policy
@@ -0,0 +1,36 @@
+package policy
+
+import (
+	"slices"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// Forbid is a license gate for release pipelines: it rejects every attribution whose license falls in one of the
+// forbidden categories (see attribution.Attribution.LicenseCategory).
+type Forbid struct {
+	// Categories lists the forbidden license categories.
+	Categories []attribution.LicenseCategory
+}
+
+// Check returns the attributions whose license falls in a forbidden category, in input order.
+// The License of each violation is the full license expression of the attribution, including a split exception (or
+// NONE or NOASSERTION for packages without a license or with an unknown license), and its Category the category it
+// falls in.
+func (f Forbid) Check(attributions []attribution.Attribution) []Violation {
+	var violations []Violation
+
+	for _, a := range attributions {
+		category := a.LicenseCategory()
+		if !slices.Contains(f.Categories, category) {
+			continue
+		}
+		license, special := specialLicense(a.LicenseStatus())
+		if !special {
+			license = a.LicenseExpression()
+		}
+		violations = append(violations, Violation{Attribution: a, License: license, Category: category})
+	}
+
+	return violations
+}
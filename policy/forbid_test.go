@@ -0,0 +1,58 @@
+package policy_test
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/policy"
+)
+
+// TestForbid_Check tests that attributions are rejected by the category of their license.
+func TestForbid_Check(t *testing.T) {
+	t.Parallel()
+
+	attrs := []attribution.Attribution{
+		{Name: "lodash", License: strPtr("MIT")},
+		{Name: "readline", License: strPtr("GPL-3.0-or-later")},
+		{Name: "dual", License: strPtr("MIT OR GPL-2.0-only")},
+		{Name: "glibc", License: strPtr("LGPL-2.1-only AND GPL-2.0-only")},
+		{Name: "mystery"},
+	}
+
+	tests := []struct {
+		name       string
+		categories []attribution.LicenseCategory
+		want       []string
+	}{
+		{
+			name:       "strong copyleft",
+			categories: []attribution.LicenseCategory{attribution.LicenseCategoryStrongCopyleft},
+			want:       []string{"readline:GPL-3.0-or-later", "glibc:LGPL-2.1-only AND GPL-2.0-only"},
+		},
+		{
+			name:       "unknown",
+			categories: []attribution.LicenseCategory{attribution.LicenseCategoryUnknown},
+			want:       []string{"mystery:NOASSERTION"},
+		},
+		{name: "nothing forbidden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			for _, v := range (policy.Forbid{Categories: tt.categories}).Check(attrs) {
+				if !slices.Contains(tt.categories, v.Category) {
+					t.Errorf("Check() category = %q, want one of %q", v.Category, tt.categories)
+				}
+				got = append(got, v.Attribution.Name+":"+v.License)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
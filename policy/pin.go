@@ -0,0 +1,112 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// Pins freeze the attribution of specific packages, so upstream changes (e.g. a dependency changing its license
+// between releases) are caught instead of silently flowing into the notice.
+type Pins struct {
+	// Pins lists the pinned packages.
+	Pins []Pin `json:"pins"`
+}
+
+// Pin declares the exact values the attribution of a package must have. Only the fields that are set are checked.
+type Pin struct {
+	// Purl identifies the pinned package. It must match the purl of an attribution, once both are canonicalized (see
+	// attribution.CanonicalPurl).
+	Purl string `json:"purl"`
+	// Name is the expected package name, qualified by its group (see attribution.Attribution.QualifiedName).
+	Name *string `json:"name,omitempty"`
+	// License is the expected license expression.
+	License *string `json:"license,omitempty"`
+	// URL is the expected package URL.
+	URL *string `json:"url,omitempty"`
+	// Supplier is the expected supplier name.
+	Supplier *string `json:"supplier,omitempty"`
+}
+
+// Deviation is a difference between a pin and the attribution of the pinned package.
+type Deviation struct {
+	// Purl identifies the pinned package.
+	Purl string `json:"purl"`
+	// Field is the deviating field, or "purl" if no attribution has the pinned purl.
+	Field string `json:"field"`
+	// Want is the pinned value.
+	Want string `json:"want"`
+	// Got is the extracted value.
+	Got string `json:"got"`
+}
+
+// LoadPins reads pins from a JSON file.
+func LoadPins(path string) (Pins, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Pins{}, fmt.Errorf("read pins: %w", err)
+	}
+
+	var pins Pins
+	if err = json.Unmarshal(data, &pins); err != nil {
+		return Pins{}, fmt.Errorf("decode pins: %w", err)
+	}
+	return pins, nil
+}
+
+// Check returns the deviations of the attributions from the pins, in pin order.
+// A pinned purl that is missing from the attributions is a deviation of the "purl" field, since a version bump
+// changes the purl and must be reviewed too.
+func (p Pins) Check(attributions []attribution.Attribution) []Deviation {
+	byPurl := make(map[string]attribution.Attribution, len(attributions))
+	for _, a := range attributions {
+		if a.Purl == "" {
+			continue
+		}
+		key := attribution.CanonicalPurl(a.Purl)
+		if _, dup := byPurl[key]; !dup {
+			byPurl[key] = a
+		}
+	}
+
+	var deviations []Deviation
+	for _, pin := range p.Pins {
+		a, ok := byPurl[attribution.CanonicalPurl(pin.Purl)]
+		if !ok {
+			deviations = append(deviations, Deviation{Purl: pin.Purl, Field: "purl", Want: pin.Purl})
+			continue
+		}
+
+		for _, field := range []struct {
+			name string
+			want *string
+			got  string
+		}{
+			{name: "name", want: pin.Name, got: a.QualifiedName()},
+			{name: "license", want: pin.License, got: deref(a.License)},
+			{name: "url", want: pin.URL, got: deref(a.URL)},
+			{name: "supplier", want: pin.Supplier, got: a.Supplier},
+		} {
+			if field.want != nil && *field.want != field.got {
+				deviations = append(deviations, Deviation{
+					Purl:  pin.Purl,
+					Field: field.name,
+					Want:  *field.want,
+					Got:   field.got,
+				})
+			}
+		}
+	}
+
+	return deviations
+}
+
+// deref returns the value of s, or an empty string if s is nil.
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
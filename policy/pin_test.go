@@ -0,0 +1,64 @@
+package policy_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/policy"
+)
+
+// TestPins_Check tests the Check method.
+func TestPins_Check(t *testing.T) {
+	t.Parallel()
+
+	attrs := []attribution.Attribution{
+		{Name: "lodash", License: strPtr("MIT"), Purl: "pkg:npm/lodash@4.17.21"},
+		{Name: "core", Group: "@angular", License: strPtr("BUSL-1.1"), Purl: "pkg:npm/%40angular/core@17.0.0"},
+	}
+	pins := policy.Pins{Pins: []policy.Pin{
+		{Purl: "pkg:npm/lodash@4.17.21", License: strPtr("MIT")},
+		{Purl: "pkg:npm/%40angular/core@17.0.0", Name: strPtr("@angular/core"), License: strPtr("MIT")},
+		{Purl: "pkg:npm/react@18.2.0", License: strPtr("MIT")},
+		{Purl: "pkg:npm/Lodash@4.17.21", License: strPtr("MIT")},
+	}}
+
+	want := []policy.Deviation{
+		{Purl: "pkg:npm/%40angular/core@17.0.0", Field: "license", Want: "MIT", Got: "BUSL-1.1"},
+		{Purl: "pkg:npm/react@18.2.0", Field: "purl", Want: "pkg:npm/react@18.2.0"},
+	}
+	got := pins.Check(attrs)
+	if len(got) != len(want) {
+		t.Fatalf("Check() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Check()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+// TestLoadPins tests the LoadPins function.
+func TestLoadPins(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pins.json")
+	data := `{"pins": [{"purl": "pkg:npm/lodash@4.17.21", "license": "MIT"}]}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	pins, err := policy.LoadPins(path)
+	if err != nil {
+		t.Fatalf("LoadPins() error = %v", err)
+	}
+	if len(pins.Pins) != 1 || pins.Pins[0].License == nil || *pins.Pins[0].License != "MIT" || pins.Pins[0].URL != nil {
+		t.Errorf("LoadPins() = %+v, want one MIT pin", pins)
+	}
+
+	if _, err = policy.LoadPins(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("LoadPins() of a missing file: expected error, got nil")
+	}
+}
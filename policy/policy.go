@@ -0,0 +1,130 @@
+package policy
+
+import (
+	"strings"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// Policy is a license policy for a product.
+type Policy struct {
+	// DenyLicenses lists the denied license identifiers. Each entry is matched as a case-insensitive prefix of the
+	// identifiers referenced by a license expression, so "GPL" denies "GPL-2.0-only" and "GPL-3.0-or-later", but not
+	// "LGPL-2.1-only".
+	//
+	// A license with a linking exception (see attribution.IsLinkingException) is a different license: "GPL" does not
+	// deny "GPL-2.0-only WITH Classpath-exception-2.0". Such combinations are denied by entries naming the exception,
+	// e.g. "GPL WITH Classpath-exception-2.0" (the exception is matched exactly, ignoring case). Other exceptions only
+	// grant additional permissions and are matched like their license.
+	//
+	// The special entries NONE and NOASSERTION (matched exactly, ignoring case) deny packages explicitly without a
+	// license, which grant no rights to use them, and packages whose license is unknown (missing, empty, or
+	// NOASSERTION), which still need review. Prefixes never match these special values.
+	DenyLicenses []string
+}
+
+// Violation is an attribution that violates a policy.
+type Violation struct {
+	// Attribution is the violating attribution.
+	Attribution attribution.Attribution `json:"attribution"`
+	// License is the denied license identifier referenced by the attribution's license expression, along with its
+	// exception if any (e.g. "GPL-2.0-only WITH Classpath-exception-2.0"), or NONE or NOASSERTION for packages without
+	// a license or with an unknown license.
+	License string `json:"license"`
+	// Category is the forbidden license category the attribution falls in, for violations reported by Forbid.
+	Category attribution.LicenseCategory `json:"category,omitempty"`
+}
+
+// Check returns the attributions violating the policy, in input order.
+// An attribution is reported once, for the first denied license it references. Deprecated license identifiers are
+// matched in their current form (see attribution.RemapDeprecatedLicense), so entries written against modern
+// identifiers match old SBOM data: "GPL-2.0-only" denies "GPL-2.0", and "GPL" does not deny
+// "GPL-2.0-with-classpath-exception".
+func (p Policy) Check(attributions []attribution.Attribution) []Violation {
+	var violations []Violation
+
+	for _, a := range attributions {
+		status := a.LicenseStatus()
+		for _, denied := range p.DenyLicenses {
+			if special, ok := specialLicense(status); ok {
+				if strings.EqualFold(strings.TrimSpace(denied), special) {
+					violations = append(violations, Violation{Attribution: a, License: special})
+					break
+				}
+				continue
+			}
+			if term, ok := findDenied(currentLicense(a.LicenseExpression()), denied); ok {
+				violations = append(violations, Violation{Attribution: a, License: term.String()})
+				break
+			}
+		}
+	}
+
+	return violations
+}
+
+// findDenied returns the first license term of an expression denied by an entry of Policy.DenyLicenses: a license ID
+// prefix, optionally followed by "WITH <exception>". Returns ok as false if no term is denied.
+func findDenied(expression, denied string) (attribution.LicenseTerm, bool) {
+	prefix, exception, withException := attribution.ParseLicenseException(denied)
+	if !withException {
+		prefix = strings.TrimSpace(denied)
+	}
+
+	for _, term := range attribution.LicenseTerms(expression) {
+		if !attribution.HasLicensePrefix(term.License, prefix) {
+			continue
+		}
+		if withException && strings.EqualFold(term.Exception, exception) {
+			return term, true
+		}
+		if !withException && !attribution.IsLinkingException(term.Exception) {
+			return term, true
+		}
+	}
+	return attribution.LicenseTerm{}, false
+}
+
+// currentLicense returns a license expression with its deprecated license identifiers replaced by their current form.
+func currentLicense(expression string) string {
+	if current, ok := attribution.RemapDeprecatedLicense(expression); ok {
+		return current
+	}
+	return expression
+}
+
+// specialLicense returns the special SPDX license value (NONE or NOASSERTION) that a license status is denied by.
+// Returns ok as false for known licenses.
+func specialLicense(status attribution.LicenseStatus) (string, bool) {
+	switch status {
+	case attribution.LicenseStatusNone:
+		return attribution.LicenseNone, true
+	case attribution.LicenseStatusUnknown:
+		return attribution.LicenseNoAssertion, true
+	default:
+		return "", false
+	}
+}
+
+// NewViolations returns the violations that are not in the baseline, e.g. the violations of a previous run.
+// Violations are matched by canonical purl (falling back to name, see attribution.DedupKey) and license, so a package
+// spelled differently by another SBOM generator is still recognized.
+func NewViolations(violations, baseline []Violation) []Violation {
+	known := make(map[string]bool, len(baseline))
+	for _, v := range baseline {
+		known[violationKey(v)] = true
+	}
+
+	var result []Violation
+	for _, v := range violations {
+		if !known[violationKey(v)] {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// violationKey returns the key used to match violations across runs.
+func violationKey(v Violation) string {
+	return attribution.DedupKey(v.Attribution) + "\x00" + v.License
+}
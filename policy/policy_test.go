@@ -0,0 +1,184 @@
+package policy_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/policy"
+)
+
+// TestPolicy_Check tests the Check method.
+func TestPolicy_Check(t *testing.T) {
+	t.Parallel()
+
+	attrs := []attribution.Attribution{
+		{Name: "express", License: strPtr("MIT")},
+		{Name: "readline", License: strPtr("GPL-3.0-only")},
+		{Name: "glibc", License: strPtr("LGPL-2.1-or-later")},
+		{Name: "ghostscript", License: strPtr("AGPL-3.0-only OR GPL-3.0-only")},
+		{Name: "unknown", License: nil},
+	}
+
+	p := policy.Policy{DenyLicenses: []string{"AGPL", "GPL"}}
+	violations := p.Check(attrs)
+
+	if len(violations) != 2 {
+		t.Fatalf("Check() returned %d violations, want 2: %+v", len(violations), violations)
+	}
+	if violations[0].Attribution.Name != "readline" || violations[0].License != "GPL-3.0-only" {
+		t.Errorf("Check()[0] = %+v, want readline/GPL-3.0-only", violations[0])
+	}
+	if violations[1].Attribution.Name != "ghostscript" || violations[1].License != "AGPL-3.0-only" {
+		t.Errorf("Check()[1] = %+v, want ghostscript/AGPL-3.0-only", violations[1])
+	}
+
+	if got := (policy.Policy{}).Check(attrs); len(got) != 0 {
+		t.Errorf("empty policy Check() = %+v, want no violations", got)
+	}
+}
+
+// TestPolicy_Check_Exceptions tests that a license with a linking exception is only denied by entries naming the
+// exception, while other exceptions are denied like their license.
+func TestPolicy_Check_Exceptions(t *testing.T) {
+	t.Parallel()
+
+	attrs := []attribution.Attribution{
+		{Name: "openjdk", License: strPtr("GPL-2.0-only WITH Classpath-exception-2.0")},
+		{Name: "libgcc", License: strPtr("GPL-3.0-or-later"), Exception: strPtr("GCC-exception-3.1")},
+		{Name: "openvpn", License: strPtr("GPL-2.0-only WITH openvpn-openssl-exception")},
+	}
+
+	tests := []struct {
+		name string
+		deny []string
+		want []string
+	}{
+		{name: "plain prefix", deny: []string{"GPL"}, want: []string{"GPL-2.0-only WITH openvpn-openssl-exception"}},
+		{
+			name: "with exception",
+			deny: []string{"gpl with classpath-exception-2.0", "GPL-3.0 WITH GCC-exception-3.1"},
+			want: []string{"GPL-2.0-only WITH Classpath-exception-2.0", "GPL-3.0-or-later WITH GCC-exception-3.1"},
+		},
+		{name: "exception is not a license", deny: []string{"Classpath"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			for _, v := range (policy.Policy{DenyLicenses: tt.deny}).Check(attrs) {
+				got = append(got, v.License)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Check() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestPolicy_Check_Deprecated tests that deprecated license identifiers are matched in their current form.
+func TestPolicy_Check_Deprecated(t *testing.T) {
+	t.Parallel()
+
+	attrs := []attribution.Attribution{
+		{Name: "readline", License: strPtr("GPL-2.0")},
+		{Name: "openjdk", License: strPtr("GPL-2.0-with-classpath-exception")},
+	}
+
+	var got []string
+	for _, v := range (policy.Policy{DenyLicenses: []string{"GPL-2.0-only"}}).Check(attrs) {
+		got = append(got, v.Attribution.Name+": "+v.License)
+	}
+	if want := []string{"readline: GPL-2.0-only"}; !slices.Equal(got, want) {
+		t.Errorf("Check() = %q, want %q", got, want)
+	}
+}
+
+// TestPolicy_Check_SpecialLicenses tests that NONE and NOASSERTION deny packages without a license and with an
+// unknown license, and that prefixes never match them.
+func TestPolicy_Check_SpecialLicenses(t *testing.T) {
+	t.Parallel()
+
+	attrs := []attribution.Attribution{
+		{Name: "express", License: strPtr("MIT")},
+		{Name: "proprietary", License: strPtr("NONE")},
+		{Name: "mystery", License: strPtr("NOASSERTION")},
+		{Name: "vendored", License: nil},
+	}
+
+	tests := []struct {
+		name  string
+		deny  []string
+		names []string
+		want  string
+	}{
+		{name: "none", deny: []string{"none"}, names: []string{"proprietary"}, want: "NONE"},
+		{
+			name:  "noassertion",
+			deny:  []string{"NOASSERTION"},
+			names: []string{"mystery", "vendored"},
+			want:  "NOASSERTION",
+		},
+		{name: "prefix", deny: []string{"NO", "N"}, names: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			violations := policy.Policy{DenyLicenses: tt.deny}.Check(attrs)
+			var names []string
+			for _, v := range violations {
+				names = append(names, v.Attribution.Name)
+				if v.License != tt.want {
+					t.Errorf("Check() license = %q, want %q", v.License, tt.want)
+				}
+			}
+			if !slices.Equal(names, tt.names) {
+				t.Errorf("Check() = %v, want %v", names, tt.names)
+			}
+		})
+	}
+}
+
+// TestNewViolations tests the NewViolations function.
+func TestNewViolations(t *testing.T) {
+	t.Parallel()
+
+	baseline := []policy.Violation{
+		violation("readline", "pkg:deb/debian/readline@8.1", "GPL-3.0-only"),
+		violation("vendored", "", "GPL-2.0-only"),
+		violation("pyqt5", "pkg:pypi/pyqt5@5.15.0", "GPL-3.0-only"),
+	}
+	current := []policy.Violation{
+		violation("readline", "pkg:deb/debian/readline@8.1", "GPL-3.0-only"),
+		violation("vendored", "", "GPL-2.0-only"),
+		violation("vendored", "", "AGPL-3.0-only"),
+		violation("ghostscript", "pkg:generic/ghostscript@10.0", "AGPL-3.0-only"),
+		violation("PyQt5", "pkg:pypi/PyQt5@5.15.0", "GPL-3.0-only"),
+	}
+
+	got := policy.NewViolations(current, baseline)
+
+	if len(got) != 2 {
+		t.Fatalf("NewViolations() returned %d violations, want 2: %+v", len(got), got)
+	}
+	if got[0].Attribution.Name != "vendored" || got[0].License != "AGPL-3.0-only" {
+		t.Errorf("NewViolations()[0] = %+v, want vendored/AGPL-3.0-only", got[0])
+	}
+	if got[1].Attribution.Name != "ghostscript" {
+		t.Errorf("NewViolations()[1] = %+v, want ghostscript", got[1])
+	}
+}
+
+// violation returns a violation of the given license by the attribution with the given name and purl.
+func violation(name, purl, license string) policy.Violation {
+	return policy.Violation{Attribution: attribution.Attribution{Name: name, Purl: purl}, License: license}
+}
+
+// strPtr returns a pointer to s.
+func strPtr(s string) *string {
+	return &s
+}
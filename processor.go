@@ -0,0 +1,227 @@
+package sbomattr
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/cyclonedxextract"
+	"github.com/boringbin/sbomattr/manifest"
+	"github.com/boringbin/sbomattr/spdxextract"
+)
+
+// DedupStrategy merges the attributions extracted from several SBOMs, recording its decisions in the audit if it is
+// not nil. attribution.DeduplicateAudited is the default strategy.
+type DedupStrategy func(
+	attributions []attribution.Attribution,
+	audit *attribution.DedupAudit,
+	logger *slog.Logger,
+) []attribution.Attribution
+
+// DedupByMode returns the strategy deduplicating attributions with the given mode (see attribution.DeduplicateMode),
+// e.g. to list every version of a package in one attribution.
+func DedupByMode(mode attribution.DedupMode) DedupStrategy {
+	return func(
+		attributions []attribution.Attribution,
+		audit *attribution.DedupAudit,
+		logger *slog.Logger,
+	) []attribution.Attribution {
+		return attribution.DeduplicateMode(attributions, mode, audit, logger)
+	}
+}
+
+// Enricher adds information to deduplicated attributions, such as the registry metadata of enrich.Enricher.
+type Enricher interface {
+	// Enrich returns the attributions with the added information, or an error that stops processing.
+	Enrich(ctx context.Context, attributions []attribution.Attribution) ([]attribution.Attribution, error)
+}
+
+// EnricherFunc adapts a function, such as the Fill method of licensefetch.Fetcher, to an Enricher.
+type EnricherFunc func(ctx context.Context, attributions []attribution.Attribution) ([]attribution.Attribution, error)
+
+// Enrich calls f.
+func (f EnricherFunc) Enrich(
+	ctx context.Context,
+	attributions []attribution.Attribution,
+) ([]attribution.Attribution, error) {
+	return f(ctx, attributions)
+}
+
+// Processor extracts attributions from SBOMs with the configuration of the ProcessorOptions it was created with, so
+// new settings can be added without changing the signatures of its methods. Create one with NewProcessor.
+//
+// The results collected by WithAudit, WithGraph, WithUnknownLicenses, and WithLicenseConflicts describe the last call
+// only, so a Processor using them must not be used concurrently.
+type Processor struct {
+	opts   Options
+	logger *slog.Logger
+}
+
+// ProcessorOption configures a Processor.
+type ProcessorOption func(*Processor)
+
+// NewProcessor returns a Processor configured by the options. Without options, it processes SBOMs like ProcessFiles.
+func NewProcessor(options ...ProcessorOption) *Processor {
+	p := &Processor{}
+	for _, option := range options {
+		option(p)
+	}
+	return p
+}
+
+// WithLogger logs processing details and skipped files to logger. By default, nothing is logged.
+func WithLogger(logger *slog.Logger) ProcessorOption {
+	return func(p *Processor) {
+		p.logger = logger
+	}
+}
+
+// WithConcurrency parses up to n files in parallel (see Options.Concurrency).
+func WithConcurrency(n int) ProcessorOption {
+	return func(p *Processor) {
+		p.opts.Concurrency = n
+	}
+}
+
+// WithDedupStrategy merges the attributions of all inputs with strategy instead of attribution.DeduplicateAudited.
+func WithDedupStrategy(strategy DedupStrategy) ProcessorOption {
+	return func(p *Processor) {
+		p.opts.Dedup = strategy
+	}
+}
+
+// WithTransforms adds transforms that run, in order, on each extracted attribution before deduplication. It can be
+// given more than once.
+func WithTransforms(transforms ...Transform) ProcessorOption {
+	return func(p *Processor) {
+		p.opts.Transforms = append(p.opts.Transforms, transforms...)
+	}
+}
+
+// WithEnrichers adds enrichers that run, in order, on the deduplicated attributions. It can be given more than once.
+func WithEnrichers(enrichers ...Enricher) ProcessorOption {
+	return func(p *Processor) {
+		p.opts.Enrichers = append(p.opts.Enrichers, enrichers...)
+	}
+}
+
+// WithFilters limits the packages of SPDX documents and the components of CycloneDX BOMs by their dependency graphs
+// (see spdxextract.Filter and cyclonedxextract.Filter).
+func WithFilters(spdx spdxextract.Filter, cdx cyclonedxextract.Filter) ProcessorOption {
+	return func(p *Processor) {
+		p.opts.SPDXFilter = spdx
+		p.opts.CycloneDXFilter = cdx
+	}
+}
+
+// WithRootMode selects whether the root component each SBOM describes is attributed (see RootMode).
+func WithRootMode(mode RootMode) ProcessorOption {
+	return func(p *Processor) {
+		p.opts.Root = mode
+	}
+}
+
+// WithManifest reuses the results cached in m for unchanged files and records the results of the others, like
+// ProcessFilesIncremental.
+func WithManifest(m *manifest.Manifest) ProcessorOption {
+	return func(p *Processor) {
+		p.opts.Manifest = m
+	}
+}
+
+// WithAudit records every deduplication decision in audit.
+func WithAudit(audit *attribution.DedupAudit) ProcessorOption {
+	return func(p *Processor) {
+		p.opts.Audit = audit
+	}
+}
+
+// WithGraph collects the dependencies between the returned attributions declared by the SBOMs in graph.
+func WithGraph(graph *attribution.Graph) ProcessorOption {
+	return func(p *Processor) {
+		p.opts.Graph = graph
+	}
+}
+
+// WithUnknownLicenses reports the returned attributions whose license could not be determined to report.
+func WithUnknownLicenses(report *attribution.UnknownLicenseReport) ProcessorOption {
+	return func(p *Processor) {
+		p.opts.UnknownLicenses = report
+	}
+}
+
+// WithLicenseConflicts reports the packages found with different licenses in different inputs to report.
+func WithLicenseConflicts(report *attribution.LicenseConflictReport) ProcessorOption {
+	return func(p *Processor) {
+		p.opts.LicenseConflicts = report
+	}
+}
+
+// WithReport records the outcome of each input file in report (see ProcessReport).
+func WithReport(report *ProcessReport) ProcessorOption {
+	return func(p *Processor) {
+		p.opts.Report = report
+	}
+}
+
+// WithStrict fails processing if any input file cannot be read or processed (see Options.Strict).
+func WithStrict() ProcessorOption {
+	return func(p *Processor) {
+		p.opts.Strict = true
+	}
+}
+
+// WithProgress calls fn with the progress of processing files (see Options.Progress).
+func WithProgress(fn func(Progress)) ProcessorOption {
+	return func(p *Processor) {
+		p.opts.Progress = fn
+	}
+}
+
+// Process processes a single SBOM provided as a byte slice, like the Process function, then transforms, deduplicates,
+// and enriches its attributions like ProcessFiles. The manifest, progress function, and report of the processor are
+// not used.
+func (p *Processor) Process(ctx context.Context, data []byte) ([]attribution.Attribution, error) {
+	entry, err := process(ctx, data, p.opts, p.logger)
+	if err != nil {
+		return nil, err
+	}
+	attrs := p.opts.transform(applyRootMode(ctx, entry, p.opts.Root, p.logger))
+	return p.opts.aggregate(ctx, attrs, entry.Edges, p.logger)
+}
+
+// ProcessFiles processes multiple SBOM files from the filesystem, like the ProcessFiles function.
+//
+// Returns the deduplicated attributions or an error if no valid attributions could be extracted.
+func (p *Processor) ProcessFiles(ctx context.Context, filenames []string) ([]attribution.Attribution, error) {
+	return processFiles(ctx, filenames, os.ReadFile, p.opts, p.logger)
+}
+
+// ProcessFS processes every ".json" file in fsys, recursively and in lexical order, like ProcessFiles. Attribution
+// sources are the paths of the files in fsys.
+//
+// Returns the deduplicated attributions or an error if no valid attributions could be extracted.
+func (p *Processor) ProcessFS(ctx context.Context, fsys fs.FS) ([]attribution.Attribution, error) {
+	var filenames []string
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(d.Name(), ".json") {
+			filenames = append(filenames, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find SBOM files: %w", err)
+	}
+
+	readFile := func(name string) ([]byte, error) {
+		return fs.ReadFile(fsys, name)
+	}
+	return processFiles(ctx, filenames, readFile, p.opts, p.logger)
+}
@@ -0,0 +1,197 @@
+package sbomattr_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/boringbin/sbomattr"
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// duplicateSPDX is an SPDX document listing lodash twice, once with a license and once without.
+const duplicateSPDX = `{"spdxVersion": "SPDX-2.3", "SPDXID": "SPDXRef-DOCUMENT", "packages": [` +
+	`{"SPDXID": "SPDXRef-a", "name": "lodash", "versionInfo": "4.17.21", "licenseConcluded": "MIT"}, ` +
+	`{"SPDXID": "SPDXRef-b", "name": "lodash", "versionInfo": "4.17.21"}]}`
+
+func TestNewProcessor_Defaults(t *testing.T) {
+	t.Parallel()
+
+	filenames := []string{"testdata/example-spdx.json", "testdata/example-cyclonedx.json"}
+	want, err := sbomattr.ProcessFiles(context.Background(), filenames, nil)
+	if err != nil {
+		t.Fatalf("ProcessFiles() unexpected error: %v", err)
+	}
+
+	got, err := sbomattr.NewProcessor().ProcessFiles(context.Background(), filenames)
+	if err != nil {
+		t.Fatalf("Processor.ProcessFiles() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Processor.ProcessFiles() = %v, want %v", got, want)
+	}
+}
+
+func TestProcessor_Process(t *testing.T) {
+	t.Parallel()
+
+	var unknown attribution.UnknownLicenseReport
+	attrs, err := sbomattr.NewProcessor(sbomattr.WithUnknownLicenses(&unknown)).
+		Process(context.Background(), []byte(duplicateSPDX))
+	if err != nil {
+		t.Fatalf("Process() unexpected error: %v", err)
+	}
+
+	if len(attrs) != 1 || attrs[0].License == nil || *attrs[0].License != "MIT" {
+		t.Errorf("Process() = %v, want lodash deduplicated with its license", attrs)
+	}
+	if len(unknown.Packages) != 0 {
+		t.Errorf("unknown licenses = %v, want none", unknown.Packages)
+	}
+}
+
+func TestWithDedupStrategy(t *testing.T) {
+	t.Parallel()
+
+	var called bool
+	keepAll := sbomattr.DedupStrategy(
+		func(attrs []attribution.Attribution, _ *attribution.DedupAudit, _ *slog.Logger) []attribution.Attribution {
+			called = true
+			return attrs
+		})
+
+	attrs, err := sbomattr.NewProcessor(sbomattr.WithDedupStrategy(keepAll)).
+		Process(context.Background(), []byte(duplicateSPDX))
+	if err != nil {
+		t.Fatalf("Process() unexpected error: %v", err)
+	}
+	if !called || len(attrs) != 2 {
+		t.Errorf("Process() = %v, want both lodash packages kept by the strategy", attrs)
+	}
+}
+
+func TestDedupByMode(t *testing.T) {
+	t.Parallel()
+
+	content := `{"spdxVersion": "SPDX-2.3", "SPDXID": "SPDXRef-DOCUMENT", "packages": [` +
+		`{"SPDXID": "SPDXRef-a", "name": "lodash", "versionInfo": "4.17.20"}, ` +
+		`{"SPDXID": "SPDXRef-b", "name": "lodash", "versionInfo": "4.17.21"}]}`
+
+	processor := sbomattr.NewProcessor(sbomattr.WithDedupStrategy(sbomattr.DedupByMode(attribution.DedupPerName)))
+	attrs, err := processor.Process(context.Background(), []byte(content))
+	if err != nil {
+		t.Fatalf("Process() unexpected error: %v", err)
+	}
+	if want := []string{"4.17.20", "4.17.21"}; len(attrs) != 1 || !slices.Equal(attrs[0].Versions, want) {
+		t.Errorf("Process() = %v, want lodash with versions %v", attrs, want)
+	}
+}
+
+func TestWithLicenseConflicts(t *testing.T) {
+	t.Parallel()
+
+	content := `{"spdxVersion": "SPDX-2.3", "SPDXID": "SPDXRef-DOCUMENT", "packages": [` +
+		`{"SPDXID": "SPDXRef-a", "name": "lodash", "versionInfo": "4.17.21", "licenseConcluded": "MIT"}, ` +
+		`{"SPDXID": "SPDXRef-b", "name": "lodash", "versionInfo": "4.17.21", "licenseConcluded": "Apache-2.0"}]}`
+
+	var conflicts attribution.LicenseConflictReport
+	attrs, err := sbomattr.NewProcessor(sbomattr.WithLicenseConflicts(&conflicts)).
+		Process(context.Background(), []byte(content))
+	if err != nil {
+		t.Fatalf("Process() unexpected error: %v", err)
+	}
+
+	if len(attrs) != 1 || attrs[0].License == nil || *attrs[0].License != "MIT" {
+		t.Errorf("Process() = %v, want lodash deduplicated with the first license", attrs)
+	}
+	if len(conflicts.Conflicts) != 1 || len(conflicts.Conflicts[0].Licenses) != 2 {
+		t.Errorf("license conflicts = %+v, want lodash with MIT and Apache-2.0", conflicts.Conflicts)
+	}
+}
+
+func TestWithEnrichers(t *testing.T) {
+	t.Parallel()
+
+	var order []string
+	enricher := func(name string) sbomattr.Enricher {
+		return sbomattr.EnricherFunc(
+			func(_ context.Context, attrs []attribution.Attribution) ([]attribution.Attribution, error) {
+				order = append(order, name)
+				for i := range attrs {
+					attrs[i].Notes = append(attrs[i].Notes, name)
+				}
+				return attrs, nil
+			})
+	}
+
+	processor := sbomattr.NewProcessor(
+		sbomattr.WithEnrichers(enricher("first")),
+		sbomattr.WithEnrichers(enricher("second")),
+	)
+	attrs, err := processor.Process(context.Background(), []byte(duplicateSPDX))
+	if err != nil {
+		t.Fatalf("Process() unexpected error: %v", err)
+	}
+
+	want := []string{"first", "second"}
+	if !slices.Equal(order, want) {
+		t.Errorf("enrichers ran in order %v, want %v", order, want)
+	}
+	if len(attrs) != 1 || !slices.Equal(attrs[0].Notes, want) {
+		t.Errorf("Process() = %v, want lodash with notes %v", attrs, want)
+	}
+}
+
+func TestWithEnrichers_Error(t *testing.T) {
+	t.Parallel()
+
+	errFailed := errors.New("registry unavailable")
+	failing := sbomattr.EnricherFunc(
+		func(context.Context, []attribution.Attribution) ([]attribution.Attribution, error) {
+			return nil, errFailed
+		})
+
+	_, err := sbomattr.NewProcessor(sbomattr.WithEnrichers(failing)).
+		ProcessFiles(context.Background(), []string{"testdata/example-spdx.json"})
+	if !errors.Is(err, errFailed) {
+		t.Errorf("ProcessFiles() error = %v, want %v", err, errFailed)
+	}
+}
+
+func TestProcessor_ProcessFS(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"sboms/app.spdx.json": {Data: []byte(duplicateSPDX)},
+		"sboms/README.md":     {Data: []byte("not an SBOM")},
+	}
+
+	var progress []sbomattr.Progress
+	processor := sbomattr.NewProcessor(sbomattr.WithConcurrency(1), sbomattr.WithProgress(func(p sbomattr.Progress) {
+		progress = append(progress, p)
+	}))
+	attrs, err := processor.ProcessFS(context.Background(), fsys)
+	if err != nil {
+		t.Fatalf("ProcessFS() unexpected error: %v", err)
+	}
+
+	if len(attrs) != 1 || !slices.Equal(attrs[0].Sources, []string{"sboms/app.spdx.json"}) {
+		t.Errorf("ProcessFS() = %v, want lodash from sboms/app.spdx.json", attrs)
+	}
+	if len(progress) != 2 || progress[1].Files != 1 {
+		t.Errorf("progress = %v, want one JSON file processed", progress)
+	}
+}
+
+func TestProcessor_ProcessFS_NoSBOMs(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{"README.md": {Data: []byte("not an SBOM")}}
+	if _, err := sbomattr.NewProcessor().ProcessFS(context.Background(), fsys); err == nil {
+		t.Error("ProcessFS() expected an error without SBOM files")
+	}
+}
@@ -0,0 +1,47 @@
+package sbomattr
+
+import "sync"
+
+// Progress reports how far ProcessFiles has come, so long-running aggregations can drive a progress bar or log
+// heartbeats (see Options.Progress).
+type Progress struct {
+	// Files is the number of files to process.
+	Files int
+	// Processed is the number of files processed so far, including the files that could not be read or processed.
+	Processed int
+	// Attributions is the number of attributions extracted so far, before deduplication.
+	Attributions int
+	// File is the file that was just processed. It is empty in the first report, made before any file is processed.
+	File string
+}
+
+// progressReporter reports the progress of parseFiles to an Options.Progress function, one report at a time.
+type progressReporter struct {
+	mu       sync.Mutex
+	report   func(Progress)
+	progress Progress
+}
+
+// newProgressReporter returns a reporter for the given number of files, and reports that none is processed yet. The
+// report function is optional; if nil, nothing is reported.
+func newProgressReporter(report func(Progress), files int) *progressReporter {
+	r := &progressReporter{report: report, progress: Progress{Files: files}}
+	if report != nil {
+		report(r.progress)
+	}
+	return r
+}
+
+// done reports that a file was processed with the given result. It is safe for concurrent use.
+func (r *progressReporter) done(filename string, result fileResult) {
+	if r.report == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.progress.Processed++
+	r.progress.Attributions += len(result.entry.Attributions)
+	r.progress.File = filename
+	r.report(r.progress)
+}
@@ -0,0 +1,61 @@
+package sbomattr_test
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/boringbin/sbomattr"
+)
+
+func TestProcessFilesWithOptions_Progress(t *testing.T) {
+	t.Parallel()
+
+	filenames := []string{
+		"testdata/example-spdx.json",
+		filepath.Join(t.TempDir(), "missing.json"),
+		"testdata/example-cyclonedx.json",
+	}
+
+	var mu sync.Mutex
+	var reports []sbomattr.Progress
+	opts := sbomattr.Options{
+		Concurrency: 2,
+		Progress: func(p sbomattr.Progress) {
+			mu.Lock()
+			defer mu.Unlock()
+			reports = append(reports, p)
+		},
+	}
+	attrs, err := sbomattr.ProcessFilesWithOptions(context.Background(), filenames, opts, nil)
+	if err != nil {
+		t.Fatalf("ProcessFilesWithOptions() unexpected error: %v", err)
+	}
+
+	if len(reports) != len(filenames)+1 {
+		t.Fatalf("got %d reports, want %d: %v", len(reports), len(filenames)+1, reports)
+	}
+	if first := reports[0]; first != (sbomattr.Progress{Files: len(filenames)}) {
+		t.Errorf("first report = %+v, want no file processed", first)
+	}
+	var files []string
+	for i, p := range reports[1:] {
+		if p.Files != len(filenames) || p.Processed != i+1 {
+			t.Errorf("report %d = %+v, want %d of %d files processed", i+1, p, i+1, len(filenames))
+		}
+		if p.Attributions < reports[i].Attributions {
+			t.Errorf("report %d = %+v, attributions decreased from %d", i+1, p, reports[i].Attributions)
+		}
+		files = append(files, p.File)
+	}
+	slices.Sort(files)
+	want := slices.Sorted(slices.Values(filenames))
+	if !slices.Equal(files, want) {
+		t.Errorf("reported files = %v, want %v", files, want)
+	}
+	if last := reports[len(reports)-1]; last.Attributions < len(attrs) {
+		t.Errorf("last report = %+v, want at least the %d deduplicated attributions", last, len(attrs))
+	}
+}
@@ -0,0 +1,74 @@
+package sbomattr
+
+import "errors"
+
+// FileStatus is the outcome of processing one input file.
+type FileStatus string
+
+const (
+	// FileProcessed is a file whose attributions were extracted.
+	FileProcessed FileStatus = "processed"
+	// FileCached is an unchanged file whose attributions were reused from the manifest.
+	FileCached FileStatus = "cached"
+	// FileSkipped is a file skipped as binary content that cannot be an SBOM (see ErrBinaryInput).
+	FileSkipped FileStatus = "skipped"
+	// FileFailed is a file that could not be read or processed.
+	FileFailed FileStatus = "failed"
+)
+
+// FileReport describes the outcome of processing one input file.
+type FileReport struct {
+	// File is the name of the file, as given.
+	File string `json:"file"`
+	// Status is the outcome of processing the file.
+	Status FileStatus `json:"status"`
+	// Format is the detected SBOM format (e.g. "spdx" or "cyclonedx"), if it could be detected. It is empty for
+	// results reused from manifests written before formats were recorded.
+	Format string `json:"format,omitempty"`
+	// Packages is the number of attributions extracted from the file, before deduplication.
+	Packages int `json:"packages"`
+	// Errors are the errors that made the file skipped or failed.
+	Errors []string `json:"errors,omitempty"`
+	// Warnings are the problems found in a processed file, such as listing no packages.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ProcessReport describes the outcome of processing each input file, so callers can tell which SBOM in a batch was
+// broken (see Options.Report).
+type ProcessReport struct {
+	// Files are the reports of the input files, in input order.
+	Files []FileReport `json:"files"`
+}
+
+// Failed returns the reports of the files that were skipped or failed.
+func (r ProcessReport) Failed() []FileReport {
+	var failed []FileReport
+	for _, file := range r.Files {
+		if file.Status == FileSkipped || file.Status == FileFailed {
+			failed = append(failed, file)
+		}
+	}
+	return failed
+}
+
+// newFileReport returns the report of a file processed with the given result, from which packages attributions were
+// kept.
+func newFileReport(filename string, result fileResult, packages int) FileReport {
+	report := FileReport{File: filename, Status: FileProcessed, Format: result.entry.Format, Packages: packages}
+	failure := result.failure()
+	switch {
+	case errors.Is(failure, ErrBinaryInput):
+		report.Status = FileSkipped
+	case failure != nil:
+		report.Status = FileFailed
+	case result.cached:
+		report.Status = FileCached
+	}
+	if failure != nil {
+		report.Errors = []string{failure.Error()}
+	}
+	if len(report.Errors) == 0 && packages == 0 {
+		report.Warnings = append(report.Warnings, "no packages found")
+	}
+	return report
+}
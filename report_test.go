@@ -0,0 +1,73 @@
+package sbomattr_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/boringbin/sbomattr"
+	"github.com/boringbin/sbomattr/manifest"
+)
+
+func TestProcessFilesWithOptions_Report(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		return path
+	}
+	valid := "testdata/example-spdx.json"
+	empty := write("empty.json", `{"bomFormat": "CycloneDX", "specVersion": "1.4", "components": []}`)
+	broken := write("broken.json", `{"bomFormat": "CycloneDX", "specVersion": "1.4", "components": 1}`)
+	binary := write("binary.json", "%PDF-1.7\n\x00")
+	missing := filepath.Join(dir, "missing.json")
+	filenames := []string{valid, empty, broken, binary, missing}
+
+	var report sbomattr.ProcessReport
+	opts := sbomattr.Options{Manifest: manifest.New(), Report: &report}
+	if _, err := sbomattr.ProcessFilesWithOptions(context.Background(), filenames, opts, nil); err != nil {
+		t.Fatalf("ProcessFilesWithOptions() unexpected error: %v", err)
+	}
+
+	want := []struct {
+		status   sbomattr.FileStatus
+		format   string
+		errors   int
+		warnings int
+	}{
+		{status: sbomattr.FileProcessed, format: "spdx"},
+		{status: sbomattr.FileProcessed, format: "cyclonedx", warnings: 1},
+		{status: sbomattr.FileFailed, format: "cyclonedx", errors: 1},
+		{status: sbomattr.FileSkipped, errors: 1},
+		{status: sbomattr.FileFailed, errors: 1},
+	}
+	if len(report.Files) != len(want) {
+		t.Fatalf("report = %+v, want %d files", report, len(want))
+	}
+	for i, w := range want {
+		got := report.Files[i]
+		if got.File != filenames[i] || got.Status != w.status || got.Format != w.format ||
+			len(got.Errors) != w.errors || len(got.Warnings) != w.warnings {
+			t.Errorf("report of %s = %+v, want %+v", filenames[i], got, w)
+		}
+	}
+	if report.Files[0].Packages == 0 {
+		t.Errorf("report of %s = %+v, want its packages counted", valid, report.Files[0])
+	}
+	if failed := report.Failed(); len(failed) != 3 {
+		t.Errorf("Failed() = %+v, want the broken, binary, and missing files", failed)
+	}
+
+	// Unchanged files reuse the results cached in the manifest
+	if _, err := sbomattr.ProcessFilesWithOptions(context.Background(), filenames[:1], opts, nil); err != nil {
+		t.Fatalf("ProcessFilesWithOptions() unexpected error: %v", err)
+	}
+	if got := report.Files[0]; len(report.Files) != 1 || got.Status != sbomattr.FileCached || got.Format != "spdx" {
+		t.Errorf("report = %+v, want %s cached as spdx", report, valid)
+	}
+}
@@ -0,0 +1,86 @@
+package sbomattr
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/manifest"
+)
+
+// RootMode selects whether the root component an SBOM describes (CycloneDX metadata.component), usually the product
+// itself, is attributed.
+type RootMode string
+
+const (
+	// RootAsGenerated attributes the root component only if the SBOM generator also lists it among the components.
+	RootAsGenerated RootMode = ""
+	// RootInclude always attributes the root component, so notices for libraries do not miss it.
+	RootInclude RootMode = "include"
+	// RootExclude never attributes the root component, dropping the components that repeat it, so notices do not
+	// attribute the product to itself.
+	RootExclude RootMode = "exclude"
+)
+
+// ParseRootMode parses a root mode name ("include" or "exclude"), ignoring case. An empty name is RootAsGenerated.
+// Returns an error for unknown modes.
+func ParseRootMode(s string) (RootMode, error) {
+	s = strings.TrimSpace(s)
+	for _, mode := range []RootMode{RootAsGenerated, RootInclude, RootExclude} {
+		if strings.EqualFold(string(mode), s) {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("unknown root mode %q (valid modes: include, exclude)", s)
+}
+
+// applyRootMode returns the attributions of a processed file with the root component included or excluded according
+// to the mode. A component repeats the root if it has the same purl or, lacking purls, the same name and version.
+func applyRootMode(
+	ctx context.Context,
+	entry manifest.Entry,
+	mode RootMode,
+	logger *slog.Logger,
+) []attribution.Attribution {
+	if entry.Root == nil || mode == RootAsGenerated {
+		return entry.Attributions
+	}
+	root := *entry.Root
+
+	switch mode {
+	case RootInclude:
+		repeated := slices.ContainsFunc(entry.Attributions, func(a attribution.Attribution) bool {
+			return sameComponent(a, root)
+		})
+		if repeated {
+			return entry.Attributions
+		}
+		return append(slices.Clone(entry.Attributions), root)
+	case RootExclude:
+		result := make([]attribution.Attribution, 0, len(entry.Attributions))
+		for _, a := range entry.Attributions {
+			if sameComponent(a, root) {
+				if logger != nil {
+					logger.DebugContext(ctx, "excluding root component", "name", a.QualifiedName(), "purl", a.Purl)
+				}
+				continue
+			}
+			result = append(result, a)
+		}
+		return result
+	default:
+		return entry.Attributions
+	}
+}
+
+// sameComponent reports whether two attributions describe the same component: the same purl if both have one,
+// otherwise the same qualified name and version.
+func sameComponent(a, b attribution.Attribution) bool {
+	if a.Purl != "" && b.Purl != "" {
+		return a.Purl == b.Purl
+	}
+	return a.QualifiedName() == b.QualifiedName() && a.Version == b.Version
+}
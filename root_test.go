@@ -0,0 +1,100 @@
+package sbomattr_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/boringbin/sbomattr"
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// TestParseRootMode tests the ParseRootMode function.
+func TestParseRootMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    sbomattr.RootMode
+		wantErr bool
+	}{
+		{input: "", want: sbomattr.RootAsGenerated},
+		{input: "include", want: sbomattr.RootInclude},
+		{input: " Exclude ", want: sbomattr.RootExclude},
+		{input: "skip", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := sbomattr.ParseRootMode(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseRootMode(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseRootMode(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+// TestProcessFilesWithOptions_Root tests including and excluding the root component of CycloneDX SBOMs, whether or
+// not the generator repeats it among the components.
+func TestProcessFilesWithOptions_Root(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	write := func(name, components string) string {
+		path := filepath.Join(dir, name)
+		content := `{"bomFormat": "CycloneDX", "specVersion": "1.4", "metadata": {"component": {"name": "my-app", ` +
+			`"version": "1.0.0", "purl": "pkg:npm/my-app@1.0.0"}}, "components": [` + components + `]}`
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		return path
+	}
+	lodash := `{"name": "lodash", "purl": "pkg:npm/lodash@4.17.21"}`
+	plain := write("plain.json", lodash)
+	repeated := write("repeated.json", lodash+`, {"name": "my-app", "purl": "pkg:npm/my-app@1.0.0"}`)
+
+	tests := []struct {
+		name     string
+		filename string
+		mode     sbomattr.RootMode
+		wantRoot bool
+	}{
+		{name: "as generated", filename: plain, mode: sbomattr.RootAsGenerated, wantRoot: false},
+		{name: "as generated repeated", filename: repeated, mode: sbomattr.RootAsGenerated, wantRoot: true},
+		{name: "include", filename: plain, mode: sbomattr.RootInclude, wantRoot: true},
+		{name: "include repeated", filename: repeated, mode: sbomattr.RootInclude, wantRoot: true},
+		{name: "exclude", filename: plain, mode: sbomattr.RootExclude, wantRoot: false},
+		{name: "exclude repeated", filename: repeated, mode: sbomattr.RootExclude, wantRoot: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := sbomattr.Options{Root: tt.mode}
+			attrs, err := sbomattr.ProcessFilesWithOptions(context.Background(), []string{tt.filename}, opts, nil)
+			if err != nil {
+				t.Fatalf("ProcessFilesWithOptions() unexpected error: %v", err)
+			}
+
+			var roots []attribution.Attribution
+			for _, a := range attrs {
+				if a.Purl == "pkg:npm/my-app@1.0.0" {
+					roots = append(roots, a)
+				}
+			}
+			if tt.wantRoot && (len(roots) != 1 || len(roots[0].Sources) != 1) {
+				t.Errorf("ProcessFilesWithOptions() roots = %+v, want the root once with its source", roots)
+			}
+			if !tt.wantRoot && len(roots) != 0 {
+				t.Errorf("ProcessFilesWithOptions() roots = %+v, want none", roots)
+			}
+			if len(attrs)-len(roots) != 1 {
+				t.Errorf("ProcessFilesWithOptions() = %+v, want lodash besides the root", attrs)
+			}
+		})
+	}
+}
@@ -5,6 +5,8 @@
 //   - SPDX 2.3 (JSON)
 //   - CycloneDX 1.4 (JSON)
 //   - GitHub-wrapped SBOMs (JSON)
+//   - Go binaries without an SBOM (module metadata embedded by the Go toolchain)
+//   - sbomattr's own JSON or NDJSON output, to aggregate attribution files without the original SBOMs
 package sbomattr
 
 import (
@@ -13,80 +15,529 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
 
+	"github.com/boringbin/sbomattr/attrextract"
 	"github.com/boringbin/sbomattr/attribution"
 	"github.com/boringbin/sbomattr/cyclonedxextract"
+	"github.com/boringbin/sbomattr/gobinextract"
 	"github.com/boringbin/sbomattr/internal/sbom"
+	"github.com/boringbin/sbomattr/lockfileextract"
+	"github.com/boringbin/sbomattr/manifest"
 	"github.com/boringbin/sbomattr/spdxextract"
 )
 
+// ErrBinaryInput is returned for binary content that can never be an SBOM, such as PDFs, images, or encrypted blobs
+// (Go executables excepted), so callers scanning directories can tell such junk apart from real parse failures.
+var ErrBinaryInput = errors.New("binary input is not an SBOM")
+
+// ErrInputFailed is returned in strict mode (see Options.Strict) for each input file that could not be read or
+// processed.
+var ErrInputFailed = errors.New("input file failed")
+
 // Process processes a single SBOM file provided as a byte slice.
-// It automatically detects the SBOM format (SPDX or CycloneDX), parses it,
+// It automatically detects the SBOM format (SPDX or CycloneDX, a Go binary, or sbomattr's own JSON output), parses it,
 // and extracts attribution information.
 //
 // The context parameter can be used for cancellation.
 // The logger parameter is optional; pass nil to disable logging.
 //
-// Returns a slice of Attribution structs or an error if the SBOM cannot be processed.
+// Returns a slice of Attribution structs, sorted like the result of ProcessFiles, or an error if the SBOM cannot be
+// processed, wrapping ErrBinaryInput if the data is binary content that cannot be an SBOM.
 func Process(ctx context.Context, data []byte, logger *slog.Logger) ([]attribution.Attribution, error) {
+	entry, err := process(ctx, data, Options{}, logger)
+	if err != nil {
+		return nil, err
+	}
+	return sortAttributions(entry.Attributions), nil
+}
+
+// process processes a single SBOM like Process with the extraction options of opts, also returning the name of the
+// root component the SBOM describes (its subject) and, for CycloneDX, the attribution of that component, if the SBOM
+// records them. If opts.Graph is set, the dependencies declared by the SBOM are returned too. The digest of the
+// returned entry is not set; its format is set even if the SBOM fails to parse.
+func process(ctx context.Context, data []byte, opts Options, logger *slog.Logger) (manifest.Entry, error) {
 	// Check for cancellation
 	select {
 	case <-ctx.Done():
-		return nil, ctx.Err()
+		return manifest.Entry{}, ctx.Err()
 	default:
 	}
 
+	// Skip binary junk early, rather than reporting it as malformed JSON
+	if contentType, binary := sbom.DetectBinary(data); binary {
+		return manifest.Entry{}, fmt.Errorf("%w (%s)", ErrBinaryInput, contentType)
+	}
+
 	// Detect format
 	format, err := sbom.DetectFormat(data)
 	if err != nil {
-		return nil, fmt.Errorf("detect format: %w", err)
+		return manifest.Entry{}, fmt.Errorf("detect format: %w", err)
 	}
 
 	if logger != nil {
 		logger.DebugContext(ctx, "detected SBOM format", "format", format)
 	}
 
-	// Extract attributions based on format
+	entry, err := extract(data, format, opts)
+	entry.Format = format
+	return entry, err
+}
+
+// extract extracts the attributions of an SBOM in the given format, detected by sbom.DetectFormat, for process.
+func extract(data []byte, format string, opts Options) (manifest.Entry, error) {
 	switch format {
 	case "spdx":
 		doc, parseErr := spdxextract.ParseSBOM(data)
 		if parseErr != nil {
-			return nil, fmt.Errorf("parse SPDX: %w", parseErr)
+			return manifest.Entry{}, fmt.Errorf("parse SPDX: %w", parseErr)
+		}
+		extractOpts := spdxextract.ExtractOptions{Filter: opts.SPDXFilter}
+		entry := manifest.Entry{
+			Subject:      spdxextract.Subject(doc),
+			Attributions: spdxextract.ExtractPackagesWithOptions(doc, extractOpts),
+		}
+		if opts.Graph != nil {
+			entry.Edges = spdxextract.Dependencies(doc)
 		}
-		return spdxextract.ExtractPackages(doc), nil
+		return entry, nil
 	case "cyclonedx":
 		bom, parseErr := cyclonedxextract.ParseSBOM(data)
 		if parseErr != nil {
-			return nil, fmt.Errorf("parse CycloneDX: %w", parseErr)
+			return manifest.Entry{}, fmt.Errorf("parse CycloneDX: %w", parseErr)
+		}
+		entry := manifest.Entry{
+			Subject: cyclonedxextract.Subject(bom),
+			Root:    cyclonedxextract.Root(bom),
+			Attributions: cyclonedxextract.ExtractPackagesWithOptions(bom, cyclonedxextract.ExtractOptions{
+				Filter: opts.CycloneDXFilter,
+			}),
+		}
+		if opts.Graph != nil {
+			entry.Edges = cyclonedxextract.Dependencies(bom)
+		}
+		return entry, nil
+	case "go-binary":
+		info, parseErr := gobinextract.ParseBinary(data)
+		if parseErr != nil {
+			return manifest.Entry{}, fmt.Errorf("parse Go binary: %w", parseErr)
+		}
+		return manifest.Entry{
+			Subject:      gobinextract.Subject(info),
+			Attributions: gobinextract.ExtractPackages(info),
+		}, nil
+	case "sbomattr":
+		attrs, parseErr := attrextract.Extract(data)
+		if parseErr != nil {
+			return manifest.Entry{}, fmt.Errorf("parse sbomattr output: %w", parseErr)
 		}
-		return cyclonedxextract.ExtractPackages(bom), nil
+		return manifest.Entry{Attributions: attrs}, nil
 	default:
-		return nil, fmt.Errorf("unsupported SBOM format: %s", format)
+		return manifest.Entry{}, fmt.Errorf("unsupported SBOM format: %s", format)
 	}
 }
 
 // ProcessFiles processes multiple SBOM files from the filesystem.
 // It reads each file, processes the SBOM, aggregates the results, and deduplicates
 // attributions based on Package URL (purl) or name if purl is not available.
+// Each attribution's Sources lists the files it was found in.
+// Files are parsed in parallel (see Options.Concurrency), but merged in the order they were given, so the result does
+// not depend on which file finishes first. The result is sorted by purl, then by name (attributions without a purl
+// first), so repeated runs produce identical output. ProcessFilesWithOptions can also report the progress of long runs
+// (see Options.Progress).
+//
+// If the SBOMs describe different root components (CycloneDX metadata.component, SPDX documentDescribes), a warning
+// is logged, since aggregating unrelated products into one notice is usually a mistake.
 //
 // The context parameter can be used for cancellation.
 // The logger parameter is optional; pass nil to disable logging.
-// Errors processing individual files are logged but do not stop processing of other files.
+// Errors processing individual files are logged but do not stop processing of other files. Binary inputs (see
+// ErrBinaryInput) are logged as skipped and counted separately from files that failed to parse.
 //
 // Returns the deduplicated attributions or an error if no valid attributions could be extracted.
 func ProcessFiles(ctx context.Context, filenames []string, logger *slog.Logger) ([]attribution.Attribution, error) {
+	return processFiles(ctx, filenames, os.ReadFile, Options{}, logger)
+}
+
+// Options configures ProcessFilesWithOptions and Processor. The zero value processes every file like ProcessFiles.
+type Options struct {
+	// Manifest, if set, caches the results of each file like ProcessFilesIncremental.
+	Manifest *manifest.Manifest
+	// Audit, if set, records every deduplication decision (see attribution.DeduplicateAudited).
+	Audit *attribution.DedupAudit
+	// Root selects whether the root component each SBOM describes is attributed. Defaults to RootAsGenerated.
+	Root RootMode
+	// SPDXFilter limits the packages of SPDX documents by their relationships (see spdxextract.Filter). Defaults to
+	// every package.
+	SPDXFilter spdxextract.Filter
+	// CycloneDXFilter limits the components of CycloneDX BOMs by their dependency graph (see cyclonedxextract.Filter).
+	// Defaults to every component.
+	CycloneDXFilter cyclonedxextract.Filter
+	// Graph, if set, collects the dependencies between the returned attributions declared by the SBOMs (SPDX
+	// DEPENDS_ON relationships, CycloneDX dependencies).
+	Graph *attribution.Graph
+	// UnknownLicenses, if set, receives the returned attributions whose license could not be determined, with the files
+	// they were found in (see attribution.ReportUnknownLicenses).
+	UnknownLicenses *attribution.UnknownLicenseReport
+	// Concurrency is the number of files read and parsed in parallel. Defaults to the number of CPUs
+	// (runtime.GOMAXPROCS). The results do not depend on it: files are merged in the order they were given.
+	Concurrency int
+	// Progress, if set, is called before any file is processed and after each file, with the progress so far. Calls
+	// are made one at a time, but from the goroutines parsing the files, so it should return quickly.
+	Progress func(Progress)
+	// Dedup merges the attributions of all files. Defaults to attribution.DeduplicateAudited.
+	Dedup DedupStrategy
+	// Transforms rewrite or drop each extracted attribution before deduplication, in order (see Transform).
+	Transforms []Transform
+	// Enrichers add information to the deduplicated attributions, in order (see Enricher).
+	Enrichers []Enricher
+	// Report, if set, receives the outcome of each file (see ProcessReport), even if no attributions could be
+	// extracted from any file.
+	Report *ProcessReport
+	// LicenseConflicts, if set, receives the packages found with different licenses in different files, before
+	// deduplication keeps the first (see attribution.ReportLicenseConflicts).
+	LicenseConflicts *attribution.LicenseConflictReport
+	// Strict makes any file that cannot be read or processed, including binary inputs, fail the whole run with
+	// ErrInputFailed instead of being skipped, so a corrupt SBOM cannot silently produce an incomplete notice. Every
+	// file is still processed, so the error (and Report) lists all the failures.
+	Strict bool
+}
+
+// extractionKey identifies the options that change the attributions extracted from a file, so results cached in a
+// manifest with other options are not reused. It is empty for the default options.
+func (o Options) extractionKey() string {
+	var parts []string
+	if o.SPDXFilter != spdxextract.FilterAll {
+		parts = append(parts, "spdx-filter="+string(o.SPDXFilter))
+	}
+	if o.CycloneDXFilter != cyclonedxextract.FilterAll {
+		parts = append(parts, "cyclonedx-filter="+string(o.CycloneDXFilter))
+	}
+	if o.Graph != nil {
+		// Results cached without a graph have no edges
+		parts = append(parts, "graph")
+	}
+	return strings.Join(parts, ",")
+}
+
+// ProcessFilesWithOptions processes multiple SBOM files like ProcessFiles, with the given options.
+//
+// The context parameter can be used for cancellation.
+// The logger parameter is optional; pass nil to disable logging.
+// Errors processing individual files are logged but do not stop processing of other files.
+//
+// Returns the deduplicated attributions or an error if no valid attributions could be extracted.
+func ProcessFilesWithOptions(
+	ctx context.Context,
+	filenames []string,
+	opts Options,
+	logger *slog.Logger,
+) ([]attribution.Attribution, error) {
+	return processFiles(ctx, filenames, os.ReadFile, opts, logger)
+}
+
+// ProcessFilesIncremental processes multiple SBOM files like ProcessFiles, but reuses the results cached in m for
+// files whose content is unchanged since the manifest was written, and records the results of the others in m.
+// Entries for files that are not part of this run are removed, so m describes exactly this run when saved.
+//
+// The context parameter can be used for cancellation.
+// The logger parameter is optional; pass nil to disable logging.
+// Errors processing individual files are logged but do not stop processing of other files.
+//
+// Returns the deduplicated attributions or an error if no valid attributions could be extracted.
+func ProcessFilesIncremental(
+	ctx context.Context,
+	filenames []string,
+	m *manifest.Manifest,
+	logger *slog.Logger,
+) ([]attribution.Attribution, error) {
+	if m == nil {
+		return nil, errors.New("manifest is required")
+	}
+	return processFiles(ctx, filenames, os.ReadFile, Options{Manifest: m}, logger)
+}
+
+// processFiles implements ProcessFiles, ProcessFilesIncremental, ProcessFilesWithOptions, and the Processor methods,
+// reading the files with readFile.
+func processFiles(
+	ctx context.Context,
+	filenames []string,
+	readFile func(name string) ([]byte, error),
+	opts Options,
+	logger *slog.Logger,
+) ([]attribution.Attribution, error) {
+	m := opts.Manifest
+	if key := opts.extractionKey(); m != nil && m.Extraction != key {
+		// Results cached with other extraction options are stale
+		m.Files = make(map[string]manifest.Entry)
+		m.Extraction = key
+	}
 	var allAttributions []attribution.Attribution
+	var edges []attribution.Edge
+	subjects := make(map[string][]string)
+	entries := make(map[string]manifest.Entry)
+	var skipped skippedFiles
 
-	for _, filename := range filenames {
-		// Check for cancellation
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
+	results := parseFiles(ctx, filenames, readFile, opts, logger)
+	// Files skipped because of cancellation have no result
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	reports := make([]FileReport, 0, len(filenames))
+	var failures []error
+	for i, result := range results {
+		filename := filenames[i]
+		var attrs []attribution.Attribution
+		if skipped.skip(ctx, filename, result, logger) {
+			failures = append(failures, fmt.Errorf("%w: %s: %w", ErrInputFailed, filename, result.failure()))
+		} else {
+			entry := result.entry
+			entries[filename] = entry
+			if entry.Subject != "" {
+				subjects[entry.Subject] = append(subjects[entry.Subject], filename)
+			}
+			attrs = opts.transform(applyRootMode(ctx, entry, opts.Root, logger))
+			allAttributions = append(allAttributions, attrs...)
+			edges = append(edges, entry.Edges...)
 		}
+		reports = append(reports, newFileReport(filename, result, len(attrs)))
+	}
+	if opts.Report != nil {
+		opts.Report.Files = reports
+	}
+	if opts.Strict && len(failures) > 0 {
+		return nil, errors.Join(failures...)
+	}
 
+	if m != nil {
+		m.Files = entries
+	}
+
+	if (skipped.binary > 0 || skipped.failed > 0) && logger != nil {
+		logger.WarnContext(ctx, "skipped files", "binary", skipped.binary, "failed", skipped.failed,
+			"total", len(filenames))
+	}
+
+	if len(allAttributions) == 0 {
+		return nil, errors.New("no attributions extracted from any file")
+	}
+
+	if len(subjects) > 1 && logger != nil {
+		logger.WarnContext(ctx, "aggregating SBOMs that describe different products into one notice",
+			"subjects", mixedSubjects(subjects))
+	}
+
+	return opts.aggregate(ctx, allAttributions, edges, logger)
+}
+
+// aggregate deduplicates the attributions extracted from the inputs with the strategy of o and enriches them with the
+// enrichers of o. The license conflict report of o, if set, is filled from the attributions before deduplication, and
+// the graph and unknown license report from the result.
+func (o Options) aggregate(
+	ctx context.Context,
+	attributions []attribution.Attribution,
+	edges []attribution.Edge,
+	logger *slog.Logger,
+) ([]attribution.Attribution, error) {
+	if o.LicenseConflicts != nil {
+		*o.LicenseConflicts = attribution.ReportLicenseConflicts(attributions)
+	}
+
+	dedup := o.Dedup
+	if dedup == nil {
+		dedup = attribution.DeduplicateAudited
+	}
+	result := dedup(attributions, o.Audit, logger)
+
+	for _, enricher := range o.Enrichers {
+		var err error
+		if result, err = enricher.Enrich(ctx, result); err != nil {
+			return nil, err
+		}
+	}
+
+	result = sortAttributions(result)
+
+	if o.Graph != nil {
+		graph := attribution.Graph{Edges: append(o.Graph.Edges, edges...)}
+		*o.Graph = graph.Restrict(result)
+	}
+	if o.UnknownLicenses != nil {
+		*o.UnknownLicenses = attribution.ReportUnknownLicenses(result)
+	}
+
+	return result, nil
+}
+
+// sortAttributions returns the attributions in the order documented for the results of Process, ProcessFiles, and
+// ProcessLockfiles: by purl, then by name (qualified by group) and version (see attribution.Sort), so repeated runs
+// produce identical output whatever the order packages are listed in or files finish parsing.
+func sortAttributions(attributions []attribution.Attribution) []attribution.Attribution {
+	return attribution.Sort(attributions, attribution.SortOptions{Key: attribution.SortByPurl})
+}
+
+// fileResult is the result of reading and processing one file in parseFiles.
+type fileResult struct {
+	entry   manifest.Entry
+	cached  bool
+	readErr error
+	err     error
+}
+
+// failure returns the error that made the file fail, or nil if it was processed.
+func (r fileResult) failure() error {
+	if r.readErr != nil {
+		return fmt.Errorf("read file: %w", r.readErr)
+	}
+	return r.err
+}
+
+// parseFiles reads the files with readFile and processes them in parallel, with up to opts.Concurrency files at a
+// time, and returns their results in the order of filenames, reporting progress to opts.Progress. Files not processed
+// because the context was canceled have a zero result. The manifest of opts is only read, so it must not be modified
+// until parseFiles returns.
+func parseFiles(
+	ctx context.Context,
+	filenames []string,
+	readFile func(name string) ([]byte, error),
+	opts Options,
+	logger *slog.Logger,
+) []fileResult {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	results := make([]fileResult, len(filenames))
+	progress := newProgressReporter(opts.Progress, len(filenames))
+	var wg sync.WaitGroup
+	queue := make(chan int)
+	for range min(concurrency, len(filenames)) {
+		wg.Go(func() {
+			for i := range queue {
+				results[i] = parseFile(ctx, filenames[i], readFile, opts, logger)
+				progress.done(filenames[i], results[i])
+			}
+		})
+	}
+	for i := range filenames {
+		if ctx.Err() != nil {
+			break
+		}
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+	return results
+}
+
+// parseFile reads and processes one file for parseFiles.
+func parseFile(
+	ctx context.Context,
+	filename string,
+	readFile func(name string) ([]byte, error),
+	opts Options,
+	logger *slog.Logger,
+) fileResult {
+	if logger != nil {
+		logger.DebugContext(ctx, "processing file", "file", filename)
+	}
+
+	data, err := readFile(filename)
+	if err != nil {
+		return fileResult{readErr: err}
+	}
+	entry, cached, err := processFile(ctx, filename, data, opts, logger)
+	return fileResult{entry: entry, cached: cached, err: err}
+}
+
+// skippedFiles counts the files processFiles skipped.
+type skippedFiles struct {
+	binary int
+	failed int
+}
+
+// skip reports whether the file with the given result is skipped because it could not be read or processed, logging
+// and counting it if so.
+func (s *skippedFiles) skip(ctx context.Context, filename string, result fileResult, logger *slog.Logger) bool {
+	switch {
+	case result.readErr != nil:
+		s.failed++
+		if logger != nil {
+			logger.ErrorContext(ctx, "failed to read file", "file", filename, "error", result.readErr)
+		}
+	case errors.Is(result.err, ErrBinaryInput):
+		s.binary++
+		if logger != nil {
+			logger.WarnContext(ctx, "skipping binary file", "file", filename, "error", result.err)
+		}
+	case result.err != nil:
+		s.failed++
 		if logger != nil {
-			logger.DebugContext(ctx, "processing file", "file", filename)
+			logger.ErrorContext(ctx, "failed to process file", "file", filename, "error", result.err)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// processFile processes the contents of one SBOM file, reusing the result cached in the manifest of opts if the file
+// is unchanged, and reports whether it did. The manifest is optional; leave it nil to always process the file. If the
+// file fails to process, the returned entry only records its format.
+func processFile(
+	ctx context.Context,
+	filename string,
+	data []byte,
+	opts Options,
+	logger *slog.Logger,
+) (manifest.Entry, bool, error) {
+	digest := manifest.Digest(data)
+	if m := opts.Manifest; m != nil {
+		if entry, ok := m.Lookup(filename, digest); ok {
+			if logger != nil {
+				logger.DebugContext(ctx, "reusing cached results for unchanged file", "file", filename)
+			}
+			return entry, true, nil
+		}
+	}
+
+	entry, err := process(ctx, data, opts, logger)
+	if err != nil {
+		return manifest.Entry{Format: entry.Format}, false, err
+	}
+
+	// Record the originating file so results can be grouped per SBOM
+	for i := range entry.Attributions {
+		entry.Attributions[i].Sources = []string{filename}
+	}
+	if entry.Root != nil {
+		entry.Root.Sources = []string{filename}
+	}
+
+	entry.Digest = digest
+	return entry, false, nil
+}
+
+// ProcessLockfiles processes package manager lockfiles (see lockfileextract for the supported files), for projects
+// without SBOMs. The results are aggregated, deduplicated, and sorted like ProcessFiles, and every attribution records
+// attribution.ProvenanceLockfile so it stays distinguishable from SBOM-derived data.
+//
+// The context parameter can be used for cancellation.
+// The logger parameter is optional; pass nil to disable logging.
+// Errors processing individual files are logged but do not stop processing of other files.
+//
+// Returns the deduplicated attributions or an error if no valid attributions could be extracted.
+func ProcessLockfiles(ctx context.Context, filenames []string, logger *slog.Logger) ([]attribution.Attribution, error) {
+	var allAttributions []attribution.Attribution
+
+	for _, filename := range filenames {
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
 
 		data, err := os.ReadFile(filename)
@@ -97,23 +548,38 @@ func ProcessFiles(ctx context.Context, filenames []string, logger *slog.Logger)
 			continue
 		}
 
-		attrs, err := Process(ctx, data, logger)
+		attrs, err := lockfileextract.Extract(filename, data)
 		if err != nil {
 			if logger != nil {
-				logger.ErrorContext(ctx, "failed to process file", "file", filename, "error", err)
+				logger.ErrorContext(ctx, "failed to process lockfile", "file", filename, "error", err)
 			}
 			continue
 		}
 
+		if logger != nil {
+			logger.WarnContext(ctx, "using lockfile-derived attributions (first pass, not an SBOM)",
+				"file", filename, "count", len(attrs))
+		}
+
+		for i := range attrs {
+			attrs[i].Sources = []string{filename}
+		}
 		allAttributions = append(allAttributions, attrs...)
 	}
 
 	if len(allAttributions) == 0 {
-		return nil, errors.New("no attributions extracted from any file")
+		return nil, errors.New("no attributions extracted from any lockfile")
 	}
 
-	// Deduplicate attributions
-	deduplicated := attribution.Deduplicate(allAttributions, logger)
+	return sortAttributions(attribution.Deduplicate(allAttributions, logger)), nil
+}
 
-	return deduplicated, nil
+// mixedSubjects describes the subjects of aggregated SBOMs as sorted "<subject> (<files>)" strings.
+func mixedSubjects(subjects map[string][]string) []string {
+	described := make([]string, 0, len(subjects))
+	for subject, files := range subjects {
+		described = append(described, subject+" ("+strings.Join(files, ", ")+")")
+	}
+	slices.Sort(described)
+	return described
 }
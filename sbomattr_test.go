@@ -4,12 +4,20 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/boringbin/sbomattr"
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/format"
+	"github.com/boringbin/sbomattr/manifest"
+	"github.com/boringbin/sbomattr/spdxextract"
 )
 
 func TestProcess(t *testing.T) {
@@ -62,6 +70,30 @@ func TestProcess(t *testing.T) {
 	}
 }
 
+func TestProcess_GoBinary(t *testing.T) {
+	t.Parallel()
+
+	// The test binary itself is a Go executable without an SBOM
+	path, err := os.Executable()
+	if err != nil {
+		t.Fatalf("failed to locate test binary: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read test binary: %v", err)
+	}
+
+	attrs, err := sbomattr.Process(context.Background(), data, nil)
+	if err != nil {
+		t.Fatalf("Process() unexpected error: %v", err)
+	}
+	if !slices.ContainsFunc(attrs, func(a attribution.Attribution) bool {
+		return a.Name == "github.com/package-url/packageurl-go"
+	}) {
+		t.Error("Process() did not extract the packageurl-go dependency")
+	}
+}
+
 func TestProcess_InvalidData(t *testing.T) {
 	t.Parallel()
 
@@ -111,6 +143,32 @@ func TestProcessFiles(t *testing.T) {
 	}
 }
 
+func TestProcessFiles_Sources(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	filenames := []string{
+		"testdata/example-spdx.json",
+		"testdata/example-cyclonedx.json",
+	}
+
+	attrs, err := sbomattr.ProcessFiles(ctx, filenames, nil)
+	if err != nil {
+		t.Fatalf("ProcessFiles() unexpected error: %v", err)
+	}
+
+	for _, a := range attrs {
+		if len(a.Sources) == 0 {
+			t.Errorf("ProcessFiles() attribution %q has no sources", a.Name)
+		}
+		for _, source := range a.Sources {
+			if !slices.Contains(filenames, source) {
+				t.Errorf("ProcessFiles() attribution %q has unexpected source %q", a.Name, source)
+			}
+		}
+	}
+}
+
 func TestProcessFiles_WithInvalidFiles(t *testing.T) {
 	t.Parallel()
 
@@ -313,3 +371,459 @@ func indexString(s, substr string) int {
 	}
 	return -1
 }
+
+func TestProcessLockfiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	goMod := filepath.Join(dir, "go.mod")
+	data := []byte("module example.com/app\n\nrequire golang.org/x/text v0.14.0\n")
+	if err := os.WriteFile(goMod, data, 0600); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	attrs, err := sbomattr.ProcessLockfiles(context.Background(), []string{goMod, filepath.Join(dir, "go.sum")}, nil)
+	if err != nil {
+		t.Fatalf("ProcessLockfiles() unexpected error: %v", err)
+	}
+
+	if len(attrs) != 1 || attrs[0].Purl != "pkg:golang/golang.org/x/text@v0.14.0" {
+		t.Fatalf("ProcessLockfiles() = %+v, want golang.org/x/text", attrs)
+	}
+	if attrs[0].Provenance[attribution.FieldPurl] != attribution.ProvenanceLockfile {
+		t.Errorf("ProcessLockfiles() provenance = %v, want lockfile", attrs[0].Provenance)
+	}
+	if !slices.Equal(attrs[0].Sources, []string{goMod}) {
+		t.Errorf("ProcessLockfiles() sources = %v, want [%s]", attrs[0].Sources, goMod)
+	}
+}
+
+func TestProcessLockfiles_NoAttributions(t *testing.T) {
+	t.Parallel()
+
+	_, err := sbomattr.ProcessLockfiles(context.Background(), []string{"testdata/example-spdx.json"}, nil)
+	if err == nil {
+		t.Error("ProcessLockfiles() error = nil, want error for non-lockfile input")
+	}
+}
+
+// TestProcessFiles_MixedSubjects tests that aggregating SBOMs of different products logs a warning.
+func TestProcessFiles_MixedSubjects(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	write := func(name, subject string) string {
+		path := filepath.Join(dir, name)
+		content := `{"bomFormat": "CycloneDX", "specVersion": "1.4", "metadata": {"component": {"name": "` + subject +
+			`"}}, "components": [{"name": "lodash", "purl": "pkg:npm/lodash@4.17.21"}]}`
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		return path
+	}
+	app1 := write("app1.json", "app")
+	app2 := write("app2.json", "app")
+	other := write("other.json", "other-product")
+
+	tests := []struct {
+		name      string
+		filenames []string
+		wantWarn  bool
+	}{
+		{name: "same subject", filenames: []string{app1, app2}, wantWarn: false},
+		{name: "mixed subjects", filenames: []string{app1, other}, wantWarn: true},
+		{name: "unknown subject", filenames: []string{app1, "testdata/example-spdx.json"}, wantWarn: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var logBuf bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&logBuf, nil))
+
+			if _, err := sbomattr.ProcessFiles(context.Background(), tt.filenames, logger); err != nil {
+				t.Fatalf("ProcessFiles() unexpected error: %v", err)
+			}
+
+			gotWarn := contains(logBuf.String(), "describe different products")
+			if gotWarn != tt.wantWarn {
+				t.Errorf("ProcessFiles() warned = %v, want %v; log: %s", gotWarn, tt.wantWarn, logBuf.String())
+			}
+			if tt.wantWarn && !contains(logBuf.String(), "other-product ("+other+")") {
+				t.Errorf("ProcessFiles() warning should list subjects and files, got: %s", logBuf.String())
+			}
+		})
+	}
+}
+
+// TestProcessFiles_BinaryInputs tests that binary inputs are skipped and counted apart from parse failures.
+func TestProcessFiles_BinaryInputs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	pdf := filepath.Join(dir, "report.json")
+	if err := os.WriteFile(pdf, []byte("%PDF-1.7\n\x00\xe2\xe3\xcf\xd3"), 0600); err != nil {
+		t.Fatalf("failed to write PDF: %v", err)
+	}
+	malformed := filepath.Join(dir, "malformed.json")
+	if err := os.WriteFile(malformed, []byte(`{"bomFormat": `), 0600); err != nil {
+		t.Fatalf("failed to write malformed SBOM: %v", err)
+	}
+
+	if _, err := sbomattr.Process(context.Background(), []byte("%PDF-1.7\n\x00"), nil); !errors.Is(
+		err, sbomattr.ErrBinaryInput) {
+		t.Errorf("Process() error = %v, want ErrBinaryInput", err)
+	}
+
+	var logBuf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logBuf, nil))
+
+	filenames := []string{"testdata/example-spdx.json", pdf, malformed}
+	if _, err := sbomattr.ProcessFiles(context.Background(), filenames, logger); err != nil {
+		t.Fatalf("ProcessFiles() unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"skipping binary file",
+		"application/pdf",
+		"msg=\"skipped files\" binary=1 failed=1 total=3",
+	} {
+		if !contains(logBuf.String(), want) {
+			t.Errorf("ProcessFiles() log missing %q, got: %s", want, logBuf.String())
+		}
+	}
+}
+
+// TestProcessFiles_AttributionOutput tests that the JSON output of a previous run is accepted as an input.
+func TestProcessFiles_AttributionOutput(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile("testdata/example-spdx.json")
+	if err != nil {
+		t.Fatalf("failed to read SBOM: %v", err)
+	}
+	want, err := sbomattr.Process(context.Background(), data, nil)
+	if err != nil {
+		t.Fatalf("Process() unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err = format.JSON(&buf, want); err != nil {
+		t.Fatalf("JSON() unexpected error: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "team-a.json")
+	if err = os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		t.Fatalf("failed to write attribution file: %v", err)
+	}
+
+	got, err := sbomattr.ProcessFiles(context.Background(), []string{path}, nil)
+	if err != nil {
+		t.Fatalf("ProcessFiles() unexpected error: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ProcessFiles() returned %d attributions, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].Purl != want[i].Purl || !slices.Equal(got[i].Sources, []string{path}) {
+			t.Errorf("ProcessFiles()[%d] = %+v, want %s from %s", i, got[i], want[i].Purl, path)
+		}
+	}
+}
+
+// TestProcessFilesWithOptions_SPDXFilter tests that results cached with other extraction options are not reused.
+func TestProcessFilesWithOptions_SPDXFilter(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "sbom.json")
+	content := `{"spdxVersion": "SPDX-2.3", "SPDXID": "SPDXRef-DOCUMENT", "documentDescribes": ["SPDXRef-app"], ` +
+		`"packages": [{"SPDXID": "SPDXRef-app", "name": "app"}, {"SPDXID": "SPDXRef-lodash", "name": "lodash"}, ` +
+		`{"SPDXID": "SPDXRef-junk", "name": "junk"}], "relationships": [{"spdxElementId": "SPDXRef-app", ` +
+		`"relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-lodash"}]}`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write SBOM: %v", err)
+	}
+
+	m := manifest.New()
+	for _, tt := range []struct {
+		filter spdxextract.Filter
+		want   int
+	}{
+		{filter: spdxextract.FilterAll, want: 3},
+		{filter: spdxextract.FilterDependsOn, want: 2},
+		{filter: spdxextract.FilterAll, want: 3},
+	} {
+		opts := sbomattr.Options{Manifest: m, SPDXFilter: tt.filter}
+		attrs, err := sbomattr.ProcessFilesWithOptions(context.Background(), []string{path}, opts, nil)
+		if err != nil {
+			t.Fatalf("ProcessFilesWithOptions() unexpected error: %v", err)
+		}
+		if len(attrs) != tt.want {
+			t.Errorf("ProcessFilesWithOptions() with filter %q = %d attributions, want %d",
+				tt.filter, len(attrs), tt.want)
+		}
+	}
+}
+
+// TestProcessFilesWithOptions_Graph tests that the dependencies declared by SBOMs are collected between the returned
+// attributions, and that results cached without a graph are not reused.
+func TestProcessFilesWithOptions_Graph(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "sbom.json")
+	content := `{"spdxVersion": "SPDX-2.3", "SPDXID": "SPDXRef-DOCUMENT", "documentDescribes": ["SPDXRef-app"], ` +
+		`"packages": [{"SPDXID": "SPDXRef-app", "name": "app"}, {"SPDXID": "SPDXRef-lodash", "name": "lodash"}], ` +
+		`"relationships": [{"spdxElementId": "SPDXRef-lodash", "relationshipType": "DEPENDENCY_OF", ` +
+		`"relatedSpdxElement": "SPDXRef-app"}]}`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write SBOM: %v", err)
+	}
+
+	m := manifest.New()
+	if _, err := sbomattr.ProcessFilesWithOptions(context.Background(), []string{path},
+		sbomattr.Options{Manifest: m}, nil); err != nil {
+		t.Fatalf("ProcessFilesWithOptions() unexpected error: %v", err)
+	}
+
+	graph := &attribution.Graph{}
+	opts := sbomattr.Options{Manifest: m, Graph: graph}
+	if _, err := sbomattr.ProcessFilesWithOptions(context.Background(), []string{path}, opts, nil); err != nil {
+		t.Fatalf("ProcessFilesWithOptions() unexpected error: %v", err)
+	}
+
+	want := []attribution.Edge{{From: "app", To: "lodash"}}
+	if !slices.Equal(graph.Edges, want) {
+		t.Errorf("graph = %v, want %v", graph.Edges, want)
+	}
+}
+
+// TestProcessFilesWithOptions_Concurrency tests that parsing files in parallel merges them in the order they were
+// given, like parsing them one at a time.
+func TestProcessFilesWithOptions_Concurrency(t *testing.T) {
+	t.Parallel()
+
+	const files = 20
+	dir := t.TempDir()
+	filenames := make([]string, 0, files)
+	for i := range files {
+		path := filepath.Join(dir, fmt.Sprintf("sbom-%02d.json", i))
+		content := fmt.Sprintf(`{"spdxVersion": "SPDX-2.3", "SPDXID": "SPDXRef-DOCUMENT", "packages": [`+
+			`{"SPDXID": "SPDXRef-shared", "name": "shared", "licenseConcluded": "MIT"}, `+
+			`{"SPDXID": "SPDXRef-own", "name": "own-%02d", "licenseConcluded": "Apache-2.0"}]}`, i)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write SBOM: %v", err)
+		}
+		filenames = append(filenames, path)
+	}
+
+	sequential, err := sbomattr.ProcessFilesWithOptions(context.Background(), filenames,
+		sbomattr.Options{Concurrency: 1}, nil)
+	if err != nil {
+		t.Fatalf("ProcessFilesWithOptions() unexpected error: %v", err)
+	}
+	parallel, err := sbomattr.ProcessFilesWithOptions(context.Background(), filenames,
+		sbomattr.Options{Concurrency: 8}, nil)
+	if err != nil {
+		t.Fatalf("ProcessFilesWithOptions() unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(parallel, sequential) {
+		t.Errorf("parallel = %v, want %v", parallel, sequential)
+	}
+	if len(parallel) != files+1 || parallel[files].Name != "shared" {
+		t.Fatalf("attributions = %v, want one per file and shared", parallel)
+	}
+	if !slices.Equal(parallel[files].Sources, filenames) {
+		t.Errorf("shared sources = %v, want %v", parallel[files].Sources, filenames)
+	}
+}
+
+// TestProcessFiles_Order tests that the attributions of SBOMs and lockfiles are sorted by purl, then name, whatever
+// the order of the files and of the packages they list.
+func TestProcessFiles_Order(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	write := func(name string, packages ...string) string {
+		var listed []string
+		for i, p := range packages {
+			listed = append(listed, fmt.Sprintf(`{"SPDXID": "SPDXRef-%d", %s}`, i, p))
+		}
+		path := filepath.Join(dir, name)
+		content := `{"spdxVersion": "SPDX-2.3", "SPDXID": "SPDXRef-DOCUMENT", "packages": [` +
+			strings.Join(listed, ", ") + `]}`
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write SBOM: %v", err)
+		}
+		return path
+	}
+	react := `"name": "react", "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", ` +
+		`"referenceType": "purl", "referenceLocator": "pkg:npm/react@18.2.0"}]`
+	express := `"name": "express", "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", ` +
+		`"referenceType": "purl", "referenceLocator": "pkg:npm/express@4.18.2"}]`
+	web := write("web.json", react, `"name": "zlib"`)
+	api := write("api.json", express, `"name": "internal"`)
+
+	want := []string{"internal", "zlib", "express", "react"}
+	for _, filenames := range [][]string{{web, api}, {api, web}} {
+		attrs, err := sbomattr.ProcessFiles(context.Background(), filenames, nil)
+		if err != nil {
+			t.Fatalf("ProcessFiles() unexpected error: %v", err)
+		}
+		var got []string
+		for _, a := range attrs {
+			got = append(got, a.Name)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("ProcessFiles(%v) = %v, want %v", filenames, got, want)
+		}
+	}
+
+	goMod := filepath.Join(dir, "go.mod")
+	data := "module example.com/app\n\nrequire (\n\tgolang.org/x/text v0.14.0\n\tgithub.com/pkg/errors v0.9.1\n)\n"
+	if err := os.WriteFile(goMod, []byte(data), 0600); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+	attrs, err := sbomattr.ProcessLockfiles(context.Background(), []string{goMod}, nil)
+	if err != nil {
+		t.Fatalf("ProcessLockfiles() unexpected error: %v", err)
+	}
+	var got []string
+	for _, a := range attrs {
+		got = append(got, a.Purl)
+	}
+	want = []string{"pkg:golang/github.com/pkg/errors@v0.9.1", "pkg:golang/golang.org/x/text@v0.14.0"}
+	if !slices.Equal(got, want) {
+		t.Errorf("ProcessLockfiles() = %v, want %v", got, want)
+	}
+}
+
+// TestProcessFilesWithOptions_Strict tests that strict mode fails on any file that cannot be read or processed,
+// listing every failure.
+func TestProcessFilesWithOptions_Strict(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	malformed := filepath.Join(dir, "malformed.json")
+	if err := os.WriteFile(malformed, []byte(`{"bomFormat": `), 0600); err != nil {
+		t.Fatalf("failed to write malformed SBOM: %v", err)
+	}
+	missing := filepath.Join(dir, "missing.json")
+	filenames := []string{"testdata/example-spdx.json", malformed, missing}
+
+	var report sbomattr.ProcessReport
+	opts := sbomattr.Options{Strict: true, Report: &report}
+	attrs, err := sbomattr.ProcessFilesWithOptions(context.Background(), filenames, opts, nil)
+	if !errors.Is(err, sbomattr.ErrInputFailed) {
+		t.Fatalf("ProcessFilesWithOptions() error = %v, want ErrInputFailed", err)
+	}
+	if attrs != nil {
+		t.Errorf("ProcessFilesWithOptions() = %v, want no attributions", attrs)
+	}
+	for _, filename := range []string{malformed, missing} {
+		if !strings.Contains(err.Error(), filename) {
+			t.Errorf("ProcessFilesWithOptions() error = %v, want %s listed", err, filename)
+		}
+	}
+	if len(report.Failed()) != 2 {
+		t.Errorf("report = %+v, want both failures", report)
+	}
+
+	if _, err = sbomattr.ProcessFilesWithOptions(context.Background(), filenames[:1], opts, nil); err != nil {
+		t.Errorf("ProcessFilesWithOptions() unexpected error without failures: %v", err)
+	}
+}
+
+// TestProcessFilesWithOptions_UnknownLicenses tests that packages whose license could not be determined are reported
+// with the files they were found in.
+func TestProcessFilesWithOptions_UnknownLicenses(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	web := filepath.Join(dir, "web.json")
+	api := filepath.Join(dir, "api.json")
+	files := map[string]string{
+		web: `{"spdxVersion": "SPDX-2.3", "packages": [` +
+			`{"SPDXID": "SPDXRef-a", "name": "left-pad", "licenseConcluded": "NOASSERTION"}, ` +
+			`{"SPDXID": "SPDXRef-b", "name": "lodash", "licenseConcluded": "MIT"}]}`,
+		api: `{"spdxVersion": "SPDX-2.3", "packages": [{"SPDXID": "SPDXRef-a", "name": "left-pad"}]}`,
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write SBOM: %v", err)
+		}
+	}
+
+	report := &attribution.UnknownLicenseReport{}
+	opts := sbomattr.Options{UnknownLicenses: report}
+	if _, err := sbomattr.ProcessFilesWithOptions(context.Background(), []string{web, api}, opts, nil); err != nil {
+		t.Fatalf("ProcessFilesWithOptions() unexpected error: %v", err)
+	}
+
+	if len(report.Packages) != 1 {
+		t.Fatalf("report = %+v, want only left-pad", report.Packages)
+	}
+	if got := report.Packages[0]; got.Name != "left-pad" || !slices.Equal(got.Sources, []string{web, api}) {
+		t.Errorf("report[0] = %+v, want left-pad found in %s and %s", got, web, api)
+	}
+}
+
+// TestProcessFilesWithOptions_Audit tests that deduplication decisions across files are audited.
+func TestProcessFilesWithOptions_Audit(t *testing.T) {
+	t.Parallel()
+
+	filenames := []string{"testdata/example-spdx.json", "testdata/example-spdx.json"}
+	audit := &attribution.DedupAudit{}
+
+	attrs, err := sbomattr.ProcessFilesWithOptions(context.Background(), filenames, sbomattr.Options{Audit: audit}, nil)
+	if err != nil {
+		t.Fatalf("ProcessFilesWithOptions() unexpected error: %v", err)
+	}
+
+	if len(audit.Decisions) != len(attrs) {
+		t.Errorf("audit has %d decisions, want one per attribution (%d)", len(audit.Decisions), len(attrs))
+	}
+}
+
+// TestProcessFilesIncremental tests that changed files are reprocessed and removed files are dropped from the
+// manifest.
+func TestProcessFilesIncremental(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sbom.json")
+	write := func(purl string) {
+		content := `{"bomFormat": "CycloneDX", "components": [{"name": "pkg", "purl": "` + purl + `"}]}`
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write SBOM: %v", err)
+		}
+	}
+
+	m := manifest.New()
+	m.Files["removed.json"] = manifest.Entry{Digest: "sha256:stale"}
+	ctx := context.Background()
+
+	write("pkg:npm/pkg@1.0.0")
+	if _, err := sbomattr.ProcessFilesIncremental(ctx, []string{path}, m, nil); err != nil {
+		t.Fatalf("ProcessFilesIncremental() error = %v", err)
+	}
+	if _, ok := m.Files["removed.json"]; ok {
+		t.Error("ProcessFilesIncremental() kept the entry of a file that is no longer an input")
+	}
+
+	write("pkg:npm/pkg@2.0.0")
+	attrs, err := sbomattr.ProcessFilesIncremental(ctx, []string{path}, m, nil)
+	if err != nil {
+		t.Fatalf("ProcessFilesIncremental() error = %v", err)
+	}
+	if len(attrs) != 1 || attrs[0].Purl != "pkg:npm/pkg@2.0.0" {
+		t.Errorf("ProcessFilesIncremental() = %+v, want the changed file reprocessed", attrs)
+	}
+	if !slices.Equal(attrs[0].Sources, []string{path}) {
+		t.Errorf("ProcessFilesIncremental() sources = %v, want [%s]", attrs[0].Sources, path)
+	}
+
+	if _, err = sbomattr.ProcessFilesIncremental(ctx, []string{path}, nil, nil); err == nil {
+		t.Error("ProcessFilesIncremental() error = nil, want error for nil manifest")
+	}
+}
@@ -0,0 +1,275 @@
+package sbomcheck
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+)
+
+// Severity is the severity of an issue.
+type Severity string
+
+const (
+	// SeverityError is a violation of the schema of the SBOM format, which other tools may reject.
+	SeverityError Severity = "error"
+	// SeverityWarning is a valid SBOM construct that degrades the attribution notice, such as a missing purl.
+	SeverityWarning Severity = "warning"
+)
+
+// The rules reported by Check.
+const (
+	// RuleSyntax is an input that is not a JSON document, or not an SPDX or CycloneDX SBOM.
+	RuleSyntax = "syntax"
+	// RuleSchema is a violation of the JSON schema of the SBOM format.
+	RuleSchema = "schema"
+	// RuleMissingPurl is a package or component without a purl, which cannot be deduplicated or linked reliably.
+	RuleMissingPurl = "missing-purl"
+	// RuleInvalidPurl is a purl that cannot be parsed.
+	RuleInvalidPurl = "invalid-purl"
+	// RuleUnknownLicense is a package or component without a license, or with a NOASSERTION license.
+	RuleUnknownLicense = "unknown-license"
+)
+
+// Issue is a problem found in an SBOM.
+type Issue struct {
+	// Severity is the severity of the issue.
+	Severity Severity `json:"severity"`
+	// Rule is the rule that found the issue (e.g. RuleSchema or RuleMissingPurl).
+	Rule string `json:"rule"`
+	// Path is the JSON pointer (RFC 6901) of the offending value, e.g. "/packages/3", or "" for the whole document.
+	Path string `json:"path"`
+	// Message describes the issue.
+	Message string `json:"message"`
+}
+
+// String returns the issue as "<severity> <path>: <message> (<rule>)".
+func (i Issue) String() string {
+	return fmt.Sprintf("%s %s: %s (%s)", i.Severity, cmp.Or(i.Path, "/"), i.Message, i.Rule)
+}
+
+// Result is the result of checking an SBOM.
+type Result struct {
+	// Format is the format of the SBOM, "spdx" or "cyclonedx", or empty if it could not be detected.
+	Format string `json:"format,omitempty"`
+	// Issues are the problems found, errors and warnings alike.
+	Issues []Issue `json:"issues"`
+}
+
+// Errors returns the number of issues with SeverityError.
+func (r Result) Errors() int {
+	return r.count(SeverityError)
+}
+
+// Warnings returns the number of issues with SeverityWarning.
+func (r Result) Warnings() int {
+	return r.count(SeverityWarning)
+}
+
+// count returns the number of issues with the given severity.
+func (r Result) count(severity Severity) int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// Check checks an SPDX or CycloneDX JSON SBOM, which may be wrapped like the SBOMs exported by GitHub
+// ({"sbom": {...}}). Inputs that are not JSON, or neither SPDX nor CycloneDX, are reported as a RuleSyntax error.
+func Check(data []byte) Result {
+	var document any
+	if err := json.Unmarshal(data, &document); err != nil {
+		return Result{Issues: []Issue{{
+			Severity: SeverityError, Rule: RuleSyntax, Message: "invalid JSON: " + err.Error(),
+		}}}
+	}
+
+	root, ok := document.(map[string]any)
+	if !ok {
+		return Result{Issues: []Issue{{
+			Severity: SeverityError, Rule: RuleSyntax, Message: "not an SPDX or CycloneDX SBOM: expected a JSON object",
+		}}}
+	}
+	path := ""
+	if wrapped, isWrapped := root["sbom"].(map[string]any); isWrapped {
+		root, path = wrapped, "/sbom"
+	}
+
+	c := &checker{}
+	var format string
+	switch {
+	case root["spdxVersion"] != nil || root["SPDXID"] != nil:
+		format = "spdx"
+		c.spdxDocument(root, path)
+	case root["bomFormat"] != nil:
+		format = "cyclonedx"
+		c.cdxBOM(root, path)
+	default:
+		c.issue(SeverityError, RuleSyntax, path,
+			"not an SPDX or CycloneDX SBOM: no spdxVersion, SPDXID, or bomFormat property")
+	}
+	return Result{Format: format, Issues: c.issues}
+}
+
+// checker collects the issues found in a decoded document.
+type checker struct {
+	issues []Issue
+}
+
+// issue records an issue at path.
+func (c *checker) issue(severity Severity, rule, path, format string, args ...any) {
+	c.issues = append(c.issues, Issue{
+		Severity: severity, Rule: rule, Path: path, Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// schema records a schema violation at path.
+func (c *checker) schema(path, format string, args ...any) {
+	c.issue(SeverityError, RuleSchema, path, format, args...)
+}
+
+// required reports the keys missing from obj, the object at path.
+func (c *checker) required(obj map[string]any, path string, keys ...string) {
+	for _, key := range keys {
+		if _, ok := obj[key]; !ok {
+			c.schema(path, "missing required property %q", key)
+		}
+	}
+}
+
+// str returns the string at key in obj, the object at path, reporting a value of another type. It returns false if
+// the key is missing or not a string.
+func (c *checker) str(obj map[string]any, path, key string) (string, bool) {
+	value, ok := obj[key]
+	if !ok {
+		return "", false
+	}
+	s, ok := value.(string)
+	if !ok {
+		c.schema(pointer(path, key), "expected a string, got %s", typeName(value))
+	}
+	return s, ok
+}
+
+// enum reports the string at key in obj, the object at path, if it is not one of values.
+func (c *checker) enum(obj map[string]any, path, key string, values []string) {
+	s, ok := c.str(obj, path, key)
+	if ok && !slices.Contains(values, s) {
+		c.schema(pointer(path, key), "%q is not one of the allowed values", s)
+	}
+}
+
+// element is an object in an array, with its JSON pointer.
+type element struct {
+	path  string
+	value map[string]any
+}
+
+// objects returns the objects of the array at key in obj, the object at path, reporting a value that is not an array
+// and items that are not objects.
+func (c *checker) objects(obj map[string]any, path, key string) []element {
+	items, ok := c.array(obj, path, key)
+	if !ok {
+		return nil
+	}
+	var elements []element
+	for i, item := range items {
+		itemPath := pointer(pointer(path, key), strconv.Itoa(i))
+		object, isObject := item.(map[string]any)
+		if !isObject {
+			c.schema(itemPath, "expected an object, got %s", typeName(item))
+			continue
+		}
+		elements = append(elements, element{path: itemPath, value: object})
+	}
+	return elements
+}
+
+// stringArray reports the items of the array at key in obj, the object at path, that are not strings.
+func (c *checker) stringArray(obj map[string]any, path, key string) {
+	items, ok := c.array(obj, path, key)
+	if !ok {
+		return
+	}
+	for i, item := range items {
+		if _, isString := item.(string); !isString {
+			c.schema(pointer(pointer(path, key), strconv.Itoa(i)), "expected a string, got %s", typeName(item))
+		}
+	}
+}
+
+// array returns the array at key in obj, the object at path, reporting a value of another type.
+func (c *checker) array(obj map[string]any, path, key string) ([]any, bool) {
+	value, ok := obj[key]
+	if !ok {
+		return nil, false
+	}
+	items, ok := value.([]any)
+	if !ok {
+		c.schema(pointer(path, key), "expected an array, got %s", typeName(value))
+	}
+	return items, ok
+}
+
+// object returns the object at key in obj, the object at path, reporting a value of another type.
+func (c *checker) object(obj map[string]any, path, key string) (map[string]any, bool) {
+	value, ok := obj[key]
+	if !ok {
+		return nil, false
+	}
+	object, ok := value.(map[string]any)
+	if !ok {
+		c.schema(pointer(path, key), "expected an object, got %s", typeName(value))
+	}
+	return object, ok
+}
+
+// purl lints the purl of the package or component named name at path: a missing purl (empty) or one that cannot be
+// parsed.
+func (c *checker) purl(path, name, purl string) {
+	if purl == "" {
+		c.issue(SeverityWarning, RuleMissingPurl, path, "%s has no purl", name)
+		return
+	}
+	if _, err := packageurl.FromString(purl); err != nil {
+		c.issue(SeverityWarning, RuleInvalidPurl, path, "%s has an invalid purl %q: %v", name, purl, err)
+	}
+}
+
+// pointer returns the JSON pointer of key in the value at path, escaping it as RFC 6901 requires.
+func pointer(path, key string) string {
+	return path + "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// typeName returns the JSON type of a decoded value, for messages.
+func typeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case float64:
+		return "a number"
+	case string:
+		return "a string"
+	case []any:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
+
+// describe returns the name of a package or component for messages, e.g. `package "lodash"`.
+func describe(kind string, obj map[string]any) string {
+	if name, ok := obj["name"].(string); ok && name != "" {
+		return fmt.Sprintf("%s %q", kind, name)
+	}
+	return kind
+}
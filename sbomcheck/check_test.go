@@ -0,0 +1,81 @@
+package sbomcheck_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/boringbin/sbomattr/sbomcheck"
+)
+
+// issueKey identifies an issue by its rule and path, ignoring its message.
+type issueKey struct {
+	rule string
+	path string
+}
+
+// assertIssues asserts that the result has exactly the wanted issues, in order.
+func assertIssues(t *testing.T, result sbomcheck.Result, want []issueKey) {
+	t.Helper()
+
+	if len(result.Issues) != len(want) {
+		t.Fatalf("Check() issues = %v, want %v", result.Issues, want)
+	}
+	for i, issue := range result.Issues {
+		if got := (issueKey{rule: issue.Rule, path: issue.Path}); got != want[i] {
+			t.Errorf("issue %d = %v, want %v", i, issue, want[i])
+		}
+	}
+}
+
+func TestCheck_Testdata(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		file   string
+		format string
+	}{
+		{file: "../testdata/example-spdx.json", format: "spdx"},
+		{file: "../testdata/github-wrapped-spdx.json", format: "spdx"},
+		{file: "../testdata/example-cyclonedx.json", format: "cyclonedx"},
+	} {
+		data, err := os.ReadFile(tt.file)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", tt.file, err)
+		}
+		result := sbomcheck.Check(data)
+		if result.Format != tt.format || result.Errors() != 0 {
+			t.Errorf("Check(%s) = %+v, want a valid %s SBOM", tt.file, result, tt.format)
+		}
+	}
+}
+
+func TestCheck_Syntax(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{`{"packages": [`, `[1, 2]`, `{"name": "lodash"}`} {
+		result := sbomcheck.Check([]byte(input))
+		if result.Format != "" {
+			t.Errorf("Check(%s) format = %q, want none", input, result.Format)
+		}
+		assertIssues(t, result, []issueKey{{rule: sbomcheck.RuleSyntax}})
+	}
+}
+
+func TestIssue_String(t *testing.T) {
+	t.Parallel()
+
+	issue := sbomcheck.Issue{
+		Severity: sbomcheck.SeverityWarning,
+		Rule:     sbomcheck.RuleMissingPurl,
+		Path:     "/packages/0",
+		Message:  `package "lodash" has no purl`,
+	}
+	if got, want := issue.String(), `warning /packages/0: package "lodash" has no purl (missing-purl)`; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	issue.Path = ""
+	if got, want := issue.String(), `warning /: package "lodash" has no purl (missing-purl)`; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
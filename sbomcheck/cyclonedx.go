@@ -0,0 +1,162 @@
+package sbomcheck
+
+import (
+	"math"
+	"slices"
+	"strings"
+)
+
+// cdxBOM checks a CycloneDX BOM at path.
+func (c *checker) cdxBOM(bom map[string]any, path string) {
+	c.required(bom, path, "bomFormat", "specVersion")
+	c.enum(bom, path, "bomFormat", []string{"CycloneDX"})
+	if version, ok := c.str(bom, path, "specVersion"); ok && !slices.Contains(cdxSpecVersions(), version) {
+		c.schema(pointer(path, "specVersion"), "%q is not a CycloneDX JSON version (1.2 to 1.6)", version)
+	}
+	if serial, ok := c.str(bom, path, "serialNumber"); ok && !validSerialNumber(serial) {
+		c.schema(pointer(path, "serialNumber"), "%q is not a UUID URN (urn:uuid:<uuid>)", serial)
+	}
+	if version, ok := bom["version"]; ok {
+		if n, isNumber := version.(float64); !isNumber || n < 1 || n != math.Trunc(n) {
+			c.schema(pointer(path, "version"), "expected an integer of at least 1, got %v", version)
+		}
+	}
+
+	if metadata, ok := c.object(bom, path, "metadata"); ok {
+		metadataPath := pointer(path, "metadata")
+		if component, isObject := c.object(metadata, metadataPath, "component"); isObject {
+			// The root component is the product itself, which is not attributed, so it is not linted
+			c.cdxComponent(element{path: pointer(metadataPath, "component"), value: component}, false)
+		}
+	}
+	for _, component := range c.objects(bom, path, "components") {
+		c.cdxComponent(component, true)
+	}
+	for _, dependency := range c.objects(bom, path, "dependencies") {
+		c.required(dependency.value, dependency.path, "ref")
+		c.str(dependency.value, dependency.path, "ref")
+		c.stringArray(dependency.value, dependency.path, "dependsOn")
+	}
+}
+
+// cdxComponent checks a CycloneDX component and its nested components. If lint is set, their purls and licenses are
+// linted too.
+func (c *checker) cdxComponent(component element, lint bool) {
+	c.required(component.value, component.path, "type", "name")
+	c.enum(component.value, component.path, "type", cdxComponentTypes())
+	c.enum(component.value, component.path, "scope", []string{"required", "optional", "excluded"})
+	for _, key := range []string{
+		"name", "version", "group", "bom-ref", "purl", "cpe", "description", "publisher", "author", "copyright",
+	} {
+		c.str(component.value, component.path, key)
+	}
+	for _, hash := range c.objects(component.value, component.path, "hashes") {
+		c.required(hash.value, hash.path, "alg", "content")
+		c.enum(hash.value, hash.path, "alg", cdxHashAlgorithms())
+		c.str(hash.value, hash.path, "content")
+	}
+	for _, ref := range c.objects(component.value, component.path, "externalReferences") {
+		c.required(ref.value, ref.path, "url", "type")
+		c.str(ref.value, ref.path, "url")
+		c.str(ref.value, ref.path, "type")
+	}
+	licenses := c.cdxLicenses(component)
+
+	if lint {
+		name := describe("component", component.value)
+		purl, _ := component.value["purl"].(string)
+		c.purl(component.path, name, purl)
+		switch {
+		case len(licenses) == 0:
+			c.issue(SeverityWarning, RuleUnknownLicense, component.path, "%s has no license", name)
+		case !slices.ContainsFunc(licenses, func(license string) bool { return license != "NOASSERTION" }):
+			c.issue(SeverityWarning, RuleUnknownLicense, component.path, "%s has a NOASSERTION license", name)
+		}
+	}
+
+	for _, child := range c.objects(component.value, component.path, "components") {
+		c.cdxComponent(child, lint)
+	}
+}
+
+// cdxLicenses checks the licenses of a CycloneDX component, each either a license with an id or a name, or an
+// expression. It returns the license IDs, names, and expressions found.
+func (c *checker) cdxLicenses(component element) []string {
+	var licenses []string
+	for _, choice := range c.objects(component.value, component.path, "licenses") {
+		_, hasLicense := choice.value["license"]
+		_, hasExpression := choice.value["expression"]
+		if hasLicense == hasExpression {
+			c.schema(choice.path, "expected either a license or an expression")
+			continue
+		}
+		if hasExpression {
+			if expression, ok := c.str(choice.value, choice.path, "expression"); ok {
+				licenses = append(licenses, expression)
+			}
+			continue
+		}
+
+		license, ok := c.object(choice.value, choice.path, "license")
+		if !ok {
+			continue
+		}
+		licensePath := pointer(choice.path, "license")
+		_, hasID := license["id"]
+		_, hasName := license["name"]
+		if hasID == hasName {
+			c.schema(licensePath, "expected either an id or a name")
+			continue
+		}
+		key := "id"
+		if hasName {
+			key = "name"
+		}
+		if s, isString := c.str(license, licensePath, key); isString {
+			licenses = append(licenses, s)
+		}
+	}
+	return licenses
+}
+
+// validSerialNumber reports whether serial is a UUID URN, as the serialNumber of a BOM must be.
+func validSerialNumber(serial string) bool {
+	uuid, ok := strings.CutPrefix(serial, "urn:uuid:")
+	if !ok || len(uuid) != len("00000000-0000-0000-0000-000000000000") {
+		return false
+	}
+	for i, r := range uuid {
+		switch i {
+		case 8, 13, 18, 23:
+			if r != '-' {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// cdxSpecVersions returns the CycloneDX versions with a JSON schema.
+func cdxSpecVersions() []string {
+	return []string{"1.2", "1.3", "1.4", "1.5", "1.6"}
+}
+
+// cdxComponentTypes returns the component types allowed by the CycloneDX schemas, up to 1.6.
+func cdxComponentTypes() []string {
+	return []string{
+		"application", "framework", "library", "container", "platform", "operating-system", "device",
+		"device-driver", "firmware", "file", "machine-learning-model", "data", "cryptographic-asset",
+	}
+}
+
+// cdxHashAlgorithms returns the hash algorithms allowed by the CycloneDX schemas.
+func cdxHashAlgorithms() []string {
+	return []string{
+		"MD5", "SHA-1", "SHA-256", "SHA-384", "SHA-512", "SHA3-256", "SHA3-384", "SHA3-512", "BLAKE2b-256",
+		"BLAKE2b-384", "BLAKE2b-512", "BLAKE3",
+	}
+}
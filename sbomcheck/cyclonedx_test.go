@@ -0,0 +1,61 @@
+package sbomcheck_test
+
+import (
+	"testing"
+
+	"github.com/boringbin/sbomattr/sbomcheck"
+)
+
+func TestCheck_CycloneDXSchema(t *testing.T) {
+	t.Parallel()
+
+	content := `{"bomFormat": "CycloneDX", "specVersion": "1.1", "serialNumber": "1234", "version": 0,
+		"metadata": {"component": {"type": "application"}},
+		"components": [
+			{"type": "library", "name": "lodash", "purl": "pkg:npm/lodash@4.17.21", "scope": "runtime",
+				"licenses": [{"license": {"id": "MIT", "name": "MIT License"}}],
+				"hashes": [{"alg": "SHA-256"}],
+				"components": [{"type": "module", "name": "lodash.merge", "purl": "pkg:npm/lodash.merge@4.6.2",
+					"licenses": [{"expression": "MIT"}]}]},
+			{"type": "library", "name": "express", "purl": "pkg:npm/express@4.18.2",
+				"licenses": [{"expression": "MIT", "license": {"id": "MIT"}}]}
+		],
+		"dependencies": [{"ref": "lodash", "dependsOn": [1]}]}`
+
+	result := sbomcheck.Check([]byte(content))
+	if result.Format != "cyclonedx" {
+		t.Errorf("Check() format = %q, want cyclonedx", result.Format)
+	}
+	assertIssues(t, result, []issueKey{
+		{rule: sbomcheck.RuleSchema, path: "/specVersion"},
+		{rule: sbomcheck.RuleSchema, path: "/serialNumber"},
+		{rule: sbomcheck.RuleSchema, path: "/version"},
+		{rule: sbomcheck.RuleSchema, path: "/metadata/component"},
+		{rule: sbomcheck.RuleSchema, path: "/components/0/scope"},
+		{rule: sbomcheck.RuleSchema, path: "/components/0/hashes/0"},
+		{rule: sbomcheck.RuleSchema, path: "/components/0/licenses/0/license"},
+		{rule: sbomcheck.RuleUnknownLicense, path: "/components/0"},
+		{rule: sbomcheck.RuleSchema, path: "/components/0/components/0/type"},
+		{rule: sbomcheck.RuleSchema, path: "/components/1/licenses/0"},
+		{rule: sbomcheck.RuleUnknownLicense, path: "/components/1"},
+		{rule: sbomcheck.RuleSchema, path: "/dependencies/0/dependsOn/0"},
+	})
+}
+
+func TestCheck_CycloneDXLint(t *testing.T) {
+	t.Parallel()
+
+	content := `{"bomFormat": "CycloneDX", "specVersion": "1.5",
+		"serialNumber": "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79", "version": 1,
+		"metadata": {"component": {"type": "application", "name": "app"}},
+		"components": [
+			{"type": "library", "name": "lodash", "licenses": [{"license": {"name": "NOASSERTION"}}]},
+			{"type": "library", "name": "express", "purl": "pkg:npm/express@4.18.2",
+				"licenses": [{"license": {"id": "MIT"}}]}
+		]}`
+
+	assertIssues(t, sbomcheck.Check([]byte(content)), []issueKey{
+		{rule: sbomcheck.RuleMissingPurl, path: "/components/0"},
+		{rule: sbomcheck.RuleUnknownLicense, path: "/components/0"},
+	})
+}
@@ -0,0 +1,8 @@
+// Package sbomcheck validates SBOMs before they are processed: it checks SPDX 2.3 and CycloneDX JSON documents
+// against the rules of their official JSON schemas (required properties, property types, and enumerated values), and
+// lints them for the problems that degrade attribution notices, such as packages without a purl or with a
+// NOASSERTION license.
+//
+// The schema checks are built in rather than driven by the schema files, so they cover the parts of the schemas that
+// sbomattr and common SBOM tooling rely on, not every constraint (e.g. string formats) the schemas declare.
+package sbomcheck
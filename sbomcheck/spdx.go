@@ -0,0 +1,147 @@
+package sbomcheck
+
+import (
+	"strings"
+)
+
+// spdxDocument checks an SPDX document at path.
+func (c *checker) spdxDocument(doc map[string]any, path string) {
+	c.required(doc, path, "SPDXID", "creationInfo", "dataLicense", "name", "spdxVersion")
+	if version, ok := c.str(doc, path, "spdxVersion"); ok && !strings.HasPrefix(version, "SPDX-2.") {
+		c.schema(pointer(path, "spdxVersion"), "%q is not an SPDX 2.x version", version)
+	}
+	if id, ok := c.str(doc, path, "SPDXID"); ok && id != "SPDXRef-DOCUMENT" {
+		c.schema(pointer(path, "SPDXID"), "the SPDXID of the document must be \"SPDXRef-DOCUMENT\", got %q", id)
+	}
+	c.enum(doc, path, "dataLicense", []string{"CC0-1.0"})
+	c.str(doc, path, "name")
+	c.str(doc, path, "documentNamespace")
+	c.stringArray(doc, path, "documentDescribes")
+
+	if info, ok := c.object(doc, path, "creationInfo"); ok {
+		infoPath := pointer(path, "creationInfo")
+		c.required(info, infoPath, "created", "creators")
+		c.str(info, infoPath, "created")
+		c.stringArray(info, infoPath, "creators")
+	}
+
+	for _, pkg := range c.objects(doc, path, "packages") {
+		c.spdxPackage(pkg)
+	}
+	for _, rel := range c.objects(doc, path, "relationships") {
+		c.required(rel.value, rel.path, "spdxElementId", "relatedSpdxElement", "relationshipType")
+		c.str(rel.value, rel.path, "spdxElementId")
+		c.str(rel.value, rel.path, "relatedSpdxElement")
+		c.enum(rel.value, rel.path, "relationshipType", spdxRelationshipTypes())
+	}
+}
+
+// spdxPackage checks an SPDX package and lints its purl and license.
+func (c *checker) spdxPackage(pkg element) {
+	c.required(pkg.value, pkg.path, "SPDXID", "downloadLocation", "name")
+	if id, ok := c.str(pkg.value, pkg.path, "SPDXID"); ok && !validSPDXID(id) {
+		c.schema(pointer(pkg.path, "SPDXID"), "%q is not a valid SPDX identifier (SPDXRef-<id>)", id)
+	}
+	for _, key := range []string{
+		"name", "versionInfo", "downloadLocation", "homepage", "supplier", "originator",
+		"licenseConcluded", "licenseDeclared", "copyrightText",
+	} {
+		c.str(pkg.value, pkg.path, key)
+	}
+	if analyzed, ok := pkg.value["filesAnalyzed"]; ok {
+		if _, isBool := analyzed.(bool); !isBool {
+			c.schema(pointer(pkg.path, "filesAnalyzed"), "expected a boolean, got %s", typeName(analyzed))
+		}
+	}
+	c.enum(pkg.value, pkg.path, "primaryPackagePurpose", spdxPackagePurposes())
+
+	for _, checksum := range c.objects(pkg.value, pkg.path, "checksums") {
+		c.required(checksum.value, checksum.path, "algorithm", "checksumValue")
+		c.enum(checksum.value, checksum.path, "algorithm", spdxChecksumAlgorithms())
+		c.str(checksum.value, checksum.path, "checksumValue")
+	}
+
+	var purl string
+	for _, ref := range c.objects(pkg.value, pkg.path, "externalRefs") {
+		c.required(ref.value, ref.path, "referenceCategory", "referenceLocator", "referenceType")
+		c.enum(ref.value, ref.path, "referenceCategory", spdxReferenceCategories())
+		refType, _ := c.str(ref.value, ref.path, "referenceType")
+		locator, _ := c.str(ref.value, ref.path, "referenceLocator")
+		if refType == "purl" && purl == "" {
+			purl = locator
+		}
+	}
+
+	name := describe("package", pkg.value)
+	c.purl(pkg.path, name, purl)
+	c.spdxLicense(pkg, name)
+}
+
+// spdxLicense lints the license of the SPDX package named name: neither its concluded nor its declared license is
+// set to a license expression.
+func (c *checker) spdxLicense(pkg element, name string) {
+	concluded, _ := pkg.value["licenseConcluded"].(string)
+	declared, _ := pkg.value["licenseDeclared"].(string)
+	known := func(license string) bool {
+		return license != "" && license != "NOASSERTION"
+	}
+	switch {
+	case known(concluded) || known(declared):
+	case concluded == "" && declared == "":
+		c.issue(SeverityWarning, RuleUnknownLicense, pkg.path, "%s has no license", name)
+	default:
+		c.issue(SeverityWarning, RuleUnknownLicense, pkg.path, "%s has a NOASSERTION license", name)
+	}
+}
+
+// validSPDXID reports whether id is an SPDX element identifier: "SPDXRef-" followed by letters, digits, "." or "-".
+func validSPDXID(id string) bool {
+	suffix, ok := strings.CutPrefix(id, "SPDXRef-")
+	if !ok || suffix == "" {
+		return false
+	}
+	for _, r := range suffix {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '.' && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// spdxRelationshipTypes returns the relationship types allowed by the SPDX 2.3 schema.
+func spdxRelationshipTypes() []string {
+	return []string{
+		"DESCRIBES", "DESCRIBED_BY", "CONTAINS", "CONTAINED_BY", "DEPENDS_ON", "DEPENDENCY_OF",
+		"DEPENDENCY_MANIFEST_OF", "BUILD_DEPENDENCY_OF", "DEV_DEPENDENCY_OF", "OPTIONAL_DEPENDENCY_OF",
+		"PROVIDED_DEPENDENCY_OF", "TEST_DEPENDENCY_OF", "RUNTIME_DEPENDENCY_OF", "EXAMPLE_OF", "GENERATES",
+		"GENERATED_FROM", "ANCESTOR_OF", "DESCENDANT_OF", "VARIANT_OF", "DISTRIBUTION_ARTIFACT", "PATCH_FOR",
+		"PATCH_APPLIED", "COPY_OF", "FILE_ADDED", "FILE_DELETED", "FILE_MODIFIED", "EXPANDED_FROM_ARCHIVE",
+		"DYNAMIC_LINK", "STATIC_LINK", "DATA_FILE_OF", "TEST_CASE_OF", "BUILD_TOOL_OF", "DEV_TOOL_OF", "TEST_OF",
+		"TEST_TOOL_OF", "DOCUMENTATION_OF", "OPTIONAL_COMPONENT_OF", "METAFILE_OF", "PACKAGE_OF", "AMENDS",
+		"PREREQUISITE_FOR", "HAS_PREREQUISITE", "REQUIREMENT_DESCRIPTION_FOR", "SPECIFICATION_FOR", "OTHER",
+	}
+}
+
+// spdxPackagePurposes returns the primary package purposes allowed by the SPDX 2.3 schema.
+func spdxPackagePurposes() []string {
+	return []string{
+		"APPLICATION", "FRAMEWORK", "LIBRARY", "CONTAINER", "OPERATING-SYSTEM", "DEVICE", "FIRMWARE", "SOURCE",
+		"ARCHIVE", "FILE", "INSTALL", "OTHER",
+	}
+}
+
+// spdxChecksumAlgorithms returns the checksum algorithms allowed by the SPDX 2.3 schema.
+func spdxChecksumAlgorithms() []string {
+	return []string{
+		"SHA1", "BLAKE3", "SM3", "SHA224", "SHA384", "BLAKE2b-256", "BLAKE2b-512", "SHA256", "SHA3-256", "SHA3-384",
+		"SHA3-512", "BLAKE2b-384", "SHA512", "MD2", "MD4", "MD5", "MD6", "ADLER32",
+	}
+}
+
+// spdxReferenceCategories returns the external reference categories allowed by the SPDX 2.3 schema, which accepts
+// both the hyphenated names of SPDX 2.2 and the underscored names of SPDX 2.3.
+func spdxReferenceCategories() []string {
+	return []string{
+		"OTHER", "PERSISTENT-ID", "PERSISTENT_ID", "SECURITY", "PACKAGE-MANAGER", "PACKAGE_MANAGER",
+	}
+}
@@ -0,0 +1,92 @@
+package sbomcheck_test
+
+import (
+	"testing"
+
+	"github.com/boringbin/sbomattr/sbomcheck"
+)
+
+func TestCheck_SPDXSchema(t *testing.T) {
+	t.Parallel()
+
+	content := `{"spdxVersion": "SPDX-3.0", "SPDXID": "SPDXRef-DOCUMENT", "dataLicense": "MIT", "name": "app",
+		"creationInfo": {"created": "2024-01-01T00:00:00Z", "creators": ["Tool: test", 1]},
+		"packages": [
+			{"SPDXID": "SPDXRef-lodash", "name": "lodash", "downloadLocation": "NOASSERTION",
+				"licenseConcluded": "MIT", "filesAnalyzed": "false",
+				"externalRefs": [{"referenceCategory": "PACKAGE_MANAGER", "referenceType": "purl",
+					"referenceLocator": "pkg:npm/lodash@4.17.21"}],
+				"checksums": [{"algorithm": "CRC32", "checksumValue": "abc"}]},
+			"express"
+		],
+		"relationships": [
+			{"spdxElementId": "SPDXRef-DOCUMENT", "relatedSpdxElement": "SPDXRef-lodash", "relationshipType": "USES"}
+		]}`
+
+	result := sbomcheck.Check([]byte(content))
+	if result.Format != "spdx" {
+		t.Errorf("Check() format = %q, want spdx", result.Format)
+	}
+	assertIssues(t, result, []issueKey{
+		{rule: sbomcheck.RuleSchema, path: "/spdxVersion"},
+		{rule: sbomcheck.RuleSchema, path: "/dataLicense"},
+		{rule: sbomcheck.RuleSchema, path: "/creationInfo/creators/1"},
+		{rule: sbomcheck.RuleSchema, path: "/packages/1"},
+		{rule: sbomcheck.RuleSchema, path: "/packages/0/filesAnalyzed"},
+		{rule: sbomcheck.RuleSchema, path: "/packages/0/checksums/0/algorithm"},
+		{rule: sbomcheck.RuleSchema, path: "/relationships/0/relationshipType"},
+	})
+}
+
+func TestCheck_SPDXRequired(t *testing.T) {
+	t.Parallel()
+
+	content := `{"sbom": {"spdxVersion": "SPDX-2.3", "SPDXID": "DOCUMENT",
+		"packages": [{"SPDXID": "SPDXRef-lodash@4", "name": "lodash", "licenseDeclared": "MIT",
+			"externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl",
+				"referenceLocator": "pkg:npm/lodash@4.17.21"}]}]}}`
+
+	assertIssues(t, sbomcheck.Check([]byte(content)), []issueKey{
+		{rule: sbomcheck.RuleSchema, path: "/sbom"},
+		{rule: sbomcheck.RuleSchema, path: "/sbom"},
+		{rule: sbomcheck.RuleSchema, path: "/sbom"},
+		{rule: sbomcheck.RuleSchema, path: "/sbom/SPDXID"},
+		{rule: sbomcheck.RuleSchema, path: "/sbom/packages/0"},
+		{rule: sbomcheck.RuleSchema, path: "/sbom/packages/0/SPDXID"},
+	})
+}
+
+func TestCheck_SPDXLint(t *testing.T) {
+	t.Parallel()
+
+	content := `{"spdxVersion": "SPDX-2.3", "SPDXID": "SPDXRef-DOCUMENT", "dataLicense": "CC0-1.0", "name": "app",
+		"creationInfo": {"created": "2024-01-01T00:00:00Z", "creators": ["Tool: test"]},
+		"packages": [
+			{"SPDXID": "SPDXRef-a", "name": "lodash", "downloadLocation": "NOASSERTION",
+				"licenseConcluded": "NOASSERTION", "licenseDeclared": "MIT"},
+			{"SPDXID": "SPDXRef-b", "name": "express", "downloadLocation": "NOASSERTION",
+				"licenseConcluded": "NOASSERTION", "licenseDeclared": "NOASSERTION",
+				"externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl",
+					"referenceLocator": "npm/express"}]},
+			{"SPDXID": "SPDXRef-c", "name": "left-pad", "downloadLocation": "NOASSERTION",
+				"externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl",
+					"referenceLocator": "pkg:npm/left-pad@1.3.0"}]}
+		]}`
+
+	result := sbomcheck.Check([]byte(content))
+	if result.Errors() != 0 || result.Warnings() != 4 {
+		t.Errorf("Check() = %d errors, %d warnings, want 0 and 4", result.Errors(), result.Warnings())
+	}
+	assertIssues(t, result, []issueKey{
+		{rule: sbomcheck.RuleMissingPurl, path: "/packages/0"},
+		{rule: sbomcheck.RuleInvalidPurl, path: "/packages/1"},
+		{rule: sbomcheck.RuleUnknownLicense, path: "/packages/1"},
+		{rule: sbomcheck.RuleUnknownLicense, path: "/packages/2"},
+	})
+	if got, want := result.Issues[2].Message, `package "express" has a NOASSERTION license`; got != want {
+		t.Errorf("issue message = %q, want %q", got, want)
+	}
+	if got, want := result.Issues[3].Message, `package "left-pad" has no license`; got != want {
+		t.Errorf("issue message = %q, want %q", got, want)
+	}
+}
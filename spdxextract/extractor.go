@@ -1,6 +1,9 @@
 package spdxextract
 
 import (
+	"cmp"
+	"strings"
+
 	"github.com/boringbin/sbomattr/attribution"
 )
 
@@ -12,17 +15,49 @@ func ExtractPackages(doc *Document) []attribution.Attribution {
 	}
 
 	packages := make([]attribution.Attribution, 0, len(doc.Packages))
+	licenseURLs := extractedLicenseURLs(doc.ExtractedLicenses)
+	licenseTexts := extractedLicenseTexts(doc.ExtractedLicenses)
 
 	for _, pkg := range doc.Packages {
 		// Prefer concluded license, fall back to declared license
 		license := pkg.LicenseConcluded
-		if license == "" || license == "NOASSERTION" {
+		if license == "" || license == attribution.LicenseNoAssertion {
 			license = pkg.LicenseDeclared
 		}
 
+		// Prefer the detailed description, fall back to the summary
 		p := attribution.Attribution{
-			Name:    pkg.Name,
-			License: &license,
+			Name:        pkg.Name,
+			Version:     pkg.VersionInfo,
+			Description: cmp.Or(pkg.Description, pkg.Summary),
+			License:     &license,
+		}
+
+		if supplier, ok := attribution.ParseSPDXParty(pkg.Supplier); ok {
+			p.Supplier = supplier.Name
+			p.SupplierParty = &supplier
+		}
+		if originator, ok := attribution.ParseSPDXParty(pkg.Originator); ok {
+			p.Author = originator.Name
+			p.Originator = &originator
+		}
+
+		if license != "" {
+			p = p.WithProvenance(attribution.FieldLicense, attribution.ProvenanceExtracted)
+		}
+
+		p.LicenseURL = licenseURLs[strings.TrimSpace(license)]
+		if text, ok := licenseTexts[strings.TrimSpace(license)]; ok {
+			p.LicenseText = text
+			p = p.WithProvenance(attribution.FieldLicenseText, attribution.ProvenanceExtracted)
+		}
+
+		// Carry both raw values so disagreements between them stay visible
+		if pkg.LicenseDeclared != "" {
+			p.LicenseDeclared = &pkg.LicenseDeclared
+		}
+		if pkg.LicenseConcluded != "" {
+			p.LicenseConcluded = &pkg.LicenseConcluded
 		}
 
 		// Extract purl from external references
@@ -33,19 +68,157 @@ func ExtractPackages(doc *Document) []attribution.Attribution {
 			}
 		}
 
-		// Construct URL: prefer homepage, fall back to purl conversion
-		if pkg.Homepage != "" && pkg.Homepage != "NONE" && pkg.Homepage != "NOASSERTION" {
-			p.URL = &pkg.Homepage
-		} else if p.Purl != "" {
-			// URL generation is best-effort - ignore expected errors (empty purl, unsupported types)
-			url, err := attribution.PurlToURL(p.Purl, nil)
-			if err == nil {
-				p.URL = url
+		p.CPE = findCPE(pkg.ExternalRefs)
+
+		// Attribution texts are reproduced verbatim, so only blank ones are dropped
+		for _, text := range pkg.AttributionTexts {
+			if strings.TrimSpace(text) != "" {
+				p.AttributionTexts = append(p.AttributionTexts, text)
+			}
+		}
+
+		for _, checksum := range pkg.Checksums {
+			if checksum.Algorithm == "" || checksum.ChecksumValue == "" {
+				continue
+			}
+			if p.Hashes == nil {
+				p.Hashes = make(map[string]string)
 			}
+			p.Hashes[attribution.NormalizeHashAlgorithm(checksum.Algorithm)] = checksum.ChecksumValue
 		}
 
+		p = withURLs(p, pkg)
+
 		packages = append(packages, p)
 	}
 
 	return packages
 }
+
+// withURLs returns the attribution of a package with its URLs: the homepage, the download location (a source URL if
+// it is a VCS location), the source repository, and the URLs generated from the purl and CPE. The primary URL is the
+// homepage, falling back to purl conversion, then to the download location, which is often a tarball, and to the CPE
+// as a last resort (e.g. for OS packages without a purl).
+func withURLs(p attribution.Attribution, pkg Package) attribution.Attribution {
+	if homepage, ok := attribution.NormalizeURL(pkg.Homepage); ok {
+		p = p.WithURL(attribution.URLKindHomepage, homepage)
+		p.URL = &homepage
+		p = p.WithProvenance(attribution.FieldURL, attribution.ProvenanceExtracted)
+	}
+	location := strings.TrimSpace(pkg.DownloadLocation)
+	if url, ok := downloadURL(location); ok {
+		kind := attribution.URLKindDownload
+		if isVCSLocation(location) {
+			kind = attribution.URLKindSource
+		}
+		p = p.WithURL(kind, url)
+		if _, err := attribution.PurlToURL(p.Purl, nil); p.URL == nil && (p.Purl == "" || err != nil) {
+			p.URL = &url
+			p = p.WithProvenance(attribution.FieldURL, attribution.ProvenanceExtracted)
+		}
+	}
+
+	if repo, ok := sourceRepo(pkg); ok {
+		p.SourceRepo = repo
+		p = p.WithURL(attribution.URLKindSource, repo)
+	}
+	return p.WithGeneratedURLs()
+}
+
+// extractedLicenseURLs maps the identifiers of extracted licenses to the first of their seeAlso URLs that is a usable
+// web URL (see attribution.NormalizeURL).
+func extractedLicenseURLs(licenses []ExtractedLicense) map[string]string {
+	urls := make(map[string]string)
+	for _, license := range licenses {
+		for _, seeAlso := range license.SeeAlsos {
+			if licenseURL, ok := attribution.NormalizeURL(seeAlso); ok {
+				urls[license.LicenseID] = licenseURL
+				break
+			}
+		}
+	}
+	return urls
+}
+
+// extractedLicenseTexts maps the identifiers of extracted licenses to their text, if it is recorded.
+func extractedLicenseTexts(licenses []ExtractedLicense) map[string]string {
+	texts := make(map[string]string)
+	for _, license := range licenses {
+		if text := strings.TrimSpace(license.ExtractedText); text != "" && text != attribution.LicenseNoAssertion {
+			texts[license.LicenseID] = license.ExtractedText
+		}
+	}
+	return texts
+}
+
+// sourceRepo returns the source code repository of an SPDX package: the downloadLocation if it is a VCS location,
+// or else the first URL in the free-form sourceInfo (e.g. "built from git+https://github.com/acme/lib@v1.2.0"),
+// reduced to the repository URL like downloadURL does. Returns ok as false if neither names a repository.
+func sourceRepo(pkg Package) (string, bool) {
+	if location := strings.TrimSpace(pkg.DownloadLocation); isVCSLocation(location) {
+		return downloadURL(location)
+	}
+	for _, field := range strings.Fields(pkg.SourceInfo) {
+		if !strings.Contains(field, "://") && !strings.HasPrefix(field, "git@") {
+			continue
+		}
+		if repo, ok := downloadURL(field); ok {
+			return repo, true
+		}
+	}
+	return "", false
+}
+
+// isVCSLocation reports whether an SPDX download location names a version control system, e.g.
+// "git+https://github.com/lodash/lodash.git".
+func isVCSLocation(location string) bool {
+	for _, vcs := range vcsPrefixes() {
+		if strings.HasPrefix(location, vcs) {
+			return true
+		}
+	}
+	return false
+}
+
+// vcsPrefixes returns the VCS tool prefixes of SPDX download locations.
+func vcsPrefixes() []string {
+	return []string{"git+", "hg+", "svn+", "bzr+"}
+}
+
+// downloadURL converts an SPDX downloadLocation to a browsable URL, reducing VCS locations to their repository URL
+// (see attribution.NormalizeVCSURL). Returns ok as false for NONE, NOASSERTION, and locations that are not HTTP(S).
+func downloadURL(location string) (string, bool) {
+	return attribution.NormalizeVCSURL(location)
+}
+
+// findCPE returns the CPE identifier from external references, preferring CPE 2.3 over CPE 2.2.
+// Returns an empty string if there is none.
+func findCPE(refs []ExternalRef) string {
+	for _, refType := range []string{"cpe23Type", "cpe22Type"} {
+		for _, ref := range refs {
+			if ref.ReferenceType == refType && ref.ReferenceLocator != "" {
+				return ref.ReferenceLocator
+			}
+		}
+	}
+	return ""
+}
+
+// Subject returns the name of the package the SPDX document describes, from its documentDescribes field or DESCRIBES
+// relationships. If several packages are described, the first one is returned.
+// Returns an empty string if the document does not record a described package.
+func Subject(doc *Document) string {
+	if doc == nil {
+		return ""
+	}
+
+	for _, id := range describedIDs(doc) {
+		for _, pkg := range doc.Packages {
+			if pkg.SPDXID == id && pkg.Name != "" {
+				return pkg.Name
+			}
+		}
+	}
+
+	return ""
+}
@@ -1,8 +1,12 @@
 package spdxextract_test
 
 import (
+	"maps"
+	"slices"
+	"strings"
 	"testing"
 
+	"github.com/boringbin/sbomattr/attribution"
 	"github.com/boringbin/sbomattr/spdxextract"
 )
 
@@ -97,6 +101,10 @@ func TestExtractPackages_WithConcludedLicense(t *testing.T) {
 		t.Errorf("Expected name 'lodash', got %q", attr.Name)
 	}
 
+	if attr.Version != "4.17.21" {
+		t.Errorf("Expected version '4.17.21', got %q", attr.Version)
+	}
+
 	if attr.Purl != "pkg:npm/lodash@4.17.21" {
 		t.Errorf("Expected purl 'pkg:npm/lodash@4.17.21', got %q", attr.Purl)
 	}
@@ -119,7 +127,8 @@ func TestExtractPackages_WithConcludedLicense(t *testing.T) {
 	}
 }
 
-// TestExtractPackages_WithDeclaredLicense_ConcludedIsNOASSERTION tests the ExtractPackages function with a declared license and concluded is NOASSERTION.
+// TestExtractPackages_WithDeclaredLicense_ConcludedIsNOASSERTION tests the ExtractPackages function with a declared
+// license and concluded is NOASSERTION.
 func TestExtractPackages_WithDeclaredLicense_ConcludedIsNOASSERTION(t *testing.T) {
 	t.Parallel()
 
@@ -162,7 +171,8 @@ func TestExtractPackages_WithDeclaredLicense_ConcludedIsNOASSERTION(t *testing.T
 	}
 }
 
-// TestExtractPackages_WithDeclaredLicense_ConcludedIsEmpty tests the ExtractPackages function with a declared license and concluded is empty.
+// TestExtractPackages_WithDeclaredLicense_ConcludedIsEmpty tests the ExtractPackages function with a declared license
+// and concluded is empty.
 func TestExtractPackages_WithDeclaredLicense_ConcludedIsEmpty(t *testing.T) {
 	t.Parallel()
 
@@ -644,3 +654,580 @@ func TestExtractPackages_WithHomepageNoPurl(t *testing.T) {
 		t.Errorf("Expected URL to be homepage 'https://example.com/custom-lib', got %q", *attr.URL)
 	}
 }
+
+// TestExtractPackages_WithDownloadLocation tests that downloadLocation is used as a URL after homepage and before
+// the purl-generated URL.
+func TestExtractPackages_WithDownloadLocation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		pkg      spdxextract.Package
+		wantURL  string
+		wantProv attribution.Provenance
+	}{
+		{
+			name:     "tarball",
+			pkg:      spdxextract.Package{DownloadLocation: "https://example.com/lib-1.0.0.tar.gz"},
+			wantURL:  "https://example.com/lib-1.0.0.tar.gz",
+			wantProv: attribution.ProvenanceExtracted,
+		},
+		{
+			name: "vcs with revision and subpath",
+			pkg: spdxextract.Package{
+				DownloadLocation: "git+https://git@github.com/lodash/lodash.git@4.17.21#packages/core",
+			},
+			wantURL:  "https://github.com/lodash/lodash",
+			wantProv: attribution.ProvenanceExtracted,
+		},
+		{
+			name: "purl preferred over tarball",
+			pkg: spdxextract.Package{
+				DownloadLocation: "https://registry.npmjs.org/lodash/-/lodash-4.17.21.tgz",
+				ExternalRefs: []spdxextract.ExternalRef{
+					{ReferenceType: "purl", ReferenceLocator: "pkg:npm/lodash@4.17.21"},
+				},
+			},
+			wantURL:  "https://www.npmjs.com/package/lodash/v/4.17.21",
+			wantProv: attribution.ProvenanceGenerated,
+		},
+		{
+			name: "purl preferred over vcs",
+			pkg: spdxextract.Package{
+				DownloadLocation: "git+https://github.com/lodash/lodash",
+				ExternalRefs: []spdxextract.ExternalRef{
+					{ReferenceType: "purl", ReferenceLocator: "pkg:npm/lodash@4.17.21"},
+				},
+			},
+			wantURL:  "https://www.npmjs.com/package/lodash/v/4.17.21",
+			wantProv: attribution.ProvenanceGenerated,
+		},
+		{
+			name: "unsupported purl falls back to download location",
+			pkg: spdxextract.Package{
+				DownloadLocation: "https://example.com/lib-1.0.0.tar.gz",
+				ExternalRefs: []spdxextract.ExternalRef{
+					{ReferenceType: "purl", ReferenceLocator: "pkg:generic/lib@1.0.0"},
+				},
+			},
+			wantURL:  "https://example.com/lib-1.0.0.tar.gz",
+			wantProv: attribution.ProvenanceExtracted,
+		},
+		{
+			name: "homepage preferred",
+			pkg: spdxextract.Package{
+				Homepage:         "https://lodash.com",
+				DownloadLocation: "https://example.com/lodash.tgz",
+			},
+			wantURL:  "https://lodash.com",
+			wantProv: attribution.ProvenanceExtracted,
+		},
+		{
+			name: "NOASSERTION falls back to purl",
+			pkg: spdxextract.Package{
+				DownloadLocation: "NOASSERTION",
+				ExternalRefs: []spdxextract.ExternalRef{
+					{ReferenceType: "purl", ReferenceLocator: "pkg:npm/lodash@4.17.21"},
+				},
+			},
+			wantURL:  "https://www.npmjs.com/package/lodash/v/4.17.21",
+			wantProv: attribution.ProvenanceGenerated,
+		},
+		{
+			name: "non-HTTP location is ignored",
+			pkg:  spdxextract.Package{DownloadLocation: "git+ssh://git@github.com/lodash/lodash.git"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.pkg.Name = "lodash"
+			got := spdxextract.ExtractPackages(&spdxextract.Document{Packages: []spdxextract.Package{tt.pkg}})[0]
+
+			if tt.wantURL == "" {
+				if got.URL != nil {
+					t.Errorf("Expected no URL, got %q", *got.URL)
+				}
+				return
+			}
+			if got.URL == nil || *got.URL != tt.wantURL {
+				t.Fatalf("Expected URL %q, got %+v", tt.wantURL, got.URLs)
+			}
+			if got.Provenance[attribution.FieldURL] != tt.wantProv {
+				t.Errorf("Expected URL provenance %q, got %q", tt.wantProv, got.Provenance[attribution.FieldURL])
+			}
+			if location := tt.pkg.DownloadLocation; strings.HasSuffix(location, ".tgz") &&
+				got.URLOf(attribution.URLKindDownload) != location {
+				t.Errorf("Expected download URL %q, got %+v", location, got.URLs)
+			}
+		})
+	}
+}
+
+// TestExtractPackages_DeclaredAndConcludedLicenses tests that both raw license values are carried separately.
+func TestExtractPackages_DeclaredAndConcludedLicenses(t *testing.T) {
+	t.Parallel()
+
+	doc := &spdxextract.Document{
+		SPDXVersion: "SPDX-2.3",
+		SPDXID:      "SPDXRef-DOCUMENT",
+		Packages: []spdxextract.Package{
+			{
+				Name:             "disagreeing",
+				LicenseConcluded: "MIT",
+				LicenseDeclared:  "Apache-2.0",
+			},
+			{
+				Name:            "declared-only",
+				LicenseDeclared: "BSD-3-Clause",
+			},
+		},
+	}
+
+	result := spdxextract.ExtractPackages(doc)
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 attributions, got %d", len(result))
+	}
+
+	disagreeing := result[0]
+	if disagreeing.LicenseDeclared == nil || *disagreeing.LicenseDeclared != "Apache-2.0" {
+		t.Errorf("Expected declared license 'Apache-2.0', got %v", disagreeing.LicenseDeclared)
+	}
+	if disagreeing.LicenseConcluded == nil || *disagreeing.LicenseConcluded != "MIT" {
+		t.Errorf("Expected concluded license 'MIT', got %v", disagreeing.LicenseConcluded)
+	}
+
+	declaredOnly := result[1]
+	if declaredOnly.LicenseDeclared == nil || *declaredOnly.LicenseDeclared != "BSD-3-Clause" {
+		t.Errorf("Expected declared license 'BSD-3-Clause', got %v", declaredOnly.LicenseDeclared)
+	}
+	if declaredOnly.LicenseConcluded != nil {
+		t.Errorf("Expected nil concluded license, got %q", *declaredOnly.LicenseConcluded)
+	}
+}
+
+// TestExtractPackages_Provenance tests that the provenance of extracted and generated fields is recorded.
+func TestExtractPackages_Provenance(t *testing.T) {
+	t.Parallel()
+
+	doc := &spdxextract.Document{
+		Packages: []spdxextract.Package{
+			{
+				Name:             "with-homepage",
+				Homepage:         "https://example.com",
+				LicenseConcluded: "MIT",
+			},
+			{
+				Name: "with-purl",
+				ExternalRefs: []spdxextract.ExternalRef{
+					{ReferenceType: "purl", ReferenceLocator: "pkg:npm/lodash@4.17.21"},
+				},
+			},
+		},
+	}
+
+	result := spdxextract.ExtractPackages(doc)
+
+	if got := result[0].Provenance[attribution.FieldLicense]; got != attribution.ProvenanceExtracted {
+		t.Errorf("Expected extracted license provenance, got %q", got)
+	}
+	if got := result[0].Provenance[attribution.FieldURL]; got != attribution.ProvenanceExtracted {
+		t.Errorf("Expected extracted URL provenance, got %q", got)
+	}
+	if _, ok := result[1].Provenance[attribution.FieldLicense]; ok {
+		t.Errorf("Expected no license provenance for missing license, got %v", result[1].Provenance)
+	}
+	if got := result[1].Provenance[attribution.FieldURL]; got != attribution.ProvenanceGenerated {
+		t.Errorf("Expected generated URL provenance, got %q", got)
+	}
+}
+
+// TestSubject tests finding the described package of an SPDX document.
+func TestSubject(t *testing.T) {
+	t.Parallel()
+
+	packages := []spdxextract.Package{
+		{SPDXID: "SPDXRef-lodash", Name: "lodash"},
+		{SPDXID: "SPDXRef-app", Name: "my-app"},
+	}
+
+	tests := []struct {
+		name string
+		doc  *spdxextract.Document
+		want string
+	}{
+		{name: "nil document", doc: nil, want: ""},
+		{name: "nothing described", doc: &spdxextract.Document{Packages: packages}, want: ""},
+		{
+			name: "documentDescribes",
+			doc:  &spdxextract.Document{DocumentDescribes: []string{"SPDXRef-app"}, Packages: packages},
+			want: "my-app",
+		},
+		{
+			name: "DESCRIBES relationship",
+			doc: &spdxextract.Document{
+				SPDXID:   "SPDXRef-DOCUMENT",
+				Packages: packages,
+				Relationships: []spdxextract.Relationship{
+					{
+						SPDXElementID:      "SPDXRef-app",
+						RelationshipType:   "DEPENDS_ON",
+						RelatedSPDXElement: "SPDXRef-lodash",
+					},
+					{
+						SPDXElementID:      "SPDXRef-DOCUMENT",
+						RelationshipType:   "DESCRIBES",
+						RelatedSPDXElement: "SPDXRef-app",
+					},
+				},
+			},
+			want: "my-app",
+		},
+		{
+			name: "described package missing",
+			doc:  &spdxextract.Document{DocumentDescribes: []string{"SPDXRef-other"}, Packages: packages},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := spdxextract.Subject(tt.doc); got != tt.want {
+				t.Errorf("Subject() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestExtractPackages_Description tests that the description falls back to the summary.
+func TestExtractPackages_Description(t *testing.T) {
+	t.Parallel()
+
+	doc := &spdxextract.Document{
+		Packages: []spdxextract.Package{
+			{Name: "both", Description: "Detailed description", Summary: "Summary"},
+			{Name: "summary-only", Summary: "Summary"},
+			{Name: "none"},
+		},
+	}
+
+	result := spdxextract.ExtractPackages(doc)
+
+	for i, want := range []string{"Detailed description", "Summary", ""} {
+		if result[i].Description != want {
+			t.Errorf("Expected description %q for %s, got %q", want, result[i].Name, result[i].Description)
+		}
+	}
+}
+
+// TestExtractPackages_AttributionTexts tests that attribution texts are carried verbatim, without blank ones.
+func TestExtractPackages_AttributionTexts(t *testing.T) {
+	t.Parallel()
+
+	texts := []string{"Copyright (c) 2024 Acme Inc.\nAll rights reserved.", " ", "Portions by Example Corp."}
+	doc := &spdxextract.Document{
+		Packages: []spdxextract.Package{{Name: "acme", AttributionTexts: texts}, {Name: "none"}},
+	}
+
+	result := spdxextract.ExtractPackages(doc)
+
+	if want := []string{texts[0], texts[2]}; !slices.Equal(result[0].AttributionTexts, want) {
+		t.Errorf("Expected attribution texts %q, got %q", want, result[0].AttributionTexts)
+	}
+	if result[1].AttributionTexts != nil {
+		t.Errorf("Expected no attribution texts, got %q", result[1].AttributionTexts)
+	}
+}
+
+// TestExtractPackages_LicenseURL tests that the first usable seeAlso of an extracted license is the license URL of the
+// packages licensed under it.
+func TestExtractPackages_LicenseURL(t *testing.T) {
+	t.Parallel()
+
+	doc := &spdxextract.Document{
+		Packages: []spdxextract.Package{
+			{Name: "acme", LicenseConcluded: "LicenseRef-Acme"},
+			{Name: "mit", LicenseConcluded: "MIT"},
+			{Name: "combined", LicenseConcluded: "MIT OR LicenseRef-Acme"},
+		},
+		ExtractedLicenses: []spdxextract.ExtractedLicense{
+			{LicenseID: "LicenseRef-Acme", SeeAlsos: []string{"NOASSERTION", "https://acme.example/eula"}},
+		},
+	}
+
+	result := spdxextract.ExtractPackages(doc)
+
+	want := []string{"https://acme.example/eula", "", ""}
+	for i := range want {
+		if result[i].LicenseURL != want[i] {
+			t.Errorf("Expected license URL %q for %s, got %q", want[i], result[i].Name, result[i].LicenseURL)
+		}
+	}
+}
+
+// TestExtractPackages_LicenseText tests that the text of an extracted license is the license text of the packages
+// licensed under it.
+func TestExtractPackages_LicenseText(t *testing.T) {
+	t.Parallel()
+
+	doc := &spdxextract.Document{
+		Packages: []spdxextract.Package{
+			{Name: "acme", LicenseConcluded: "LicenseRef-Acme"},
+			{Name: "unknown", LicenseConcluded: "LicenseRef-Unknown"},
+			{Name: "mit", LicenseConcluded: "MIT"},
+		},
+		ExtractedLicenses: []spdxextract.ExtractedLicense{
+			{LicenseID: "LicenseRef-Acme", ExtractedText: "Acme may be used freely."},
+			{LicenseID: "LicenseRef-Unknown", ExtractedText: "NOASSERTION"},
+		},
+	}
+
+	result := spdxextract.ExtractPackages(doc)
+
+	want := []string{"Acme may be used freely.", "", ""}
+	for i := range want {
+		if result[i].LicenseText != want[i] {
+			t.Errorf("Expected license text %q for %s, got %q", want[i], result[i].Name, result[i].LicenseText)
+		}
+	}
+	if got := result[0].Provenance[attribution.FieldLicenseText]; got != attribution.ProvenanceExtracted {
+		t.Errorf("Expected license text provenance %q, got %q", attribution.ProvenanceExtracted, got)
+	}
+}
+
+// TestExtractPackages_SourceRepo tests that the source repository is extracted from a VCS downloadLocation, or else
+// from a URL in sourceInfo, separately from the homepage.
+func TestExtractPackages_SourceRepo(t *testing.T) {
+	t.Parallel()
+
+	doc := &spdxextract.Document{
+		Packages: []spdxextract.Package{
+			{
+				Name:             "lodash",
+				Homepage:         "https://lodash.com",
+				DownloadLocation: "git+https://github.com/lodash/lodash.git@4.17.21",
+			},
+			{
+				Name:             "acme",
+				DownloadLocation: "https://example.com/acme-1.2.0.tgz",
+				SourceInfo:       "built from git+https://github.com/acme/lib.git@v1.2.0, patched",
+			},
+			{Name: "scp", SourceInfo: "mirror of git@gitlab.com:acme/scp.git"},
+			{Name: "tarball", DownloadLocation: "https://example.com/tarball.tgz", SourceInfo: "vendored by /usr/lib"},
+		},
+	}
+
+	result := spdxextract.ExtractPackages(doc)
+
+	want := []string{
+		"https://github.com/lodash/lodash", "https://github.com/acme/lib", "https://gitlab.com/acme/scp", "",
+	}
+	for i := range want {
+		if result[i].SourceRepo != want[i] {
+			t.Errorf("Expected source repository %q for %s, got %q", want[i], result[i].Name, result[i].SourceRepo)
+		}
+	}
+	if result[0].URL == nil || *result[0].URL != "https://lodash.com" {
+		t.Errorf("Expected the homepage to stay the URL, got %v", result[0].URL)
+	}
+}
+
+// TestExtractPackages_URLs tests that the homepage, download location, source repository, and generated URLs are all
+// kept with their kind.
+func TestExtractPackages_URLs(t *testing.T) {
+	t.Parallel()
+
+	doc := &spdxextract.Document{
+		Packages: []spdxextract.Package{
+			{
+				Name:             "lodash",
+				Homepage:         "https://lodash.com",
+				DownloadLocation: "https://registry.npmjs.org/lodash/-/lodash-4.17.21.tgz",
+				SourceInfo:       "built from git+https://github.com/lodash/lodash.git@4.17.21",
+				ExternalRefs: []spdxextract.ExternalRef{
+					{ReferenceType: "purl", ReferenceLocator: "pkg:npm/lodash@4.17.21"},
+				},
+			},
+		},
+	}
+
+	got := spdxextract.ExtractPackages(doc)[0]
+
+	want := []attribution.TypedURL{
+		{Kind: attribution.URLKindHomepage, URL: "https://lodash.com"},
+		{Kind: attribution.URLKindDownload, URL: "https://registry.npmjs.org/lodash/-/lodash-4.17.21.tgz"},
+		{Kind: attribution.URLKindSource, URL: "https://github.com/lodash/lodash"},
+		{Kind: attribution.URLKindRegistry, URL: "https://www.npmjs.com/package/lodash/v/4.17.21"},
+	}
+	if !slices.Equal(got.URLs, want) {
+		t.Errorf("Expected URLs %v, got %v", want, got.URLs)
+	}
+	if got.URL == nil || *got.URL != "https://lodash.com" {
+		t.Errorf("Expected the homepage to be the primary URL, got %v", got.URL)
+	}
+}
+
+// TestExtractPackages_CPE tests that CPE 2.3 identifiers are preferred over CPE 2.2 ones.
+func TestExtractPackages_CPE(t *testing.T) {
+	t.Parallel()
+
+	doc := &spdxextract.Document{
+		Packages: []spdxextract.Package{
+			{
+				Name: "openssl",
+				ExternalRefs: []spdxextract.ExternalRef{
+					{
+						ReferenceCategory: "SECURITY",
+						ReferenceType:     "cpe22Type",
+						ReferenceLocator:  "cpe:/a:openssl:openssl:3.0.0",
+					},
+					{
+						ReferenceCategory: "SECURITY",
+						ReferenceType:     "cpe23Type",
+						ReferenceLocator:  "cpe:2.3:a:openssl:openssl:3.0.0:*:*:*:*:*:*:*",
+					},
+				},
+			},
+			{
+				Name: "legacy",
+				ExternalRefs: []spdxextract.ExternalRef{
+					{
+						ReferenceCategory: "SECURITY",
+						ReferenceType:     "cpe22Type",
+						ReferenceLocator:  "cpe:/a:legacy:legacy:1.0",
+					},
+				},
+			},
+			{Name: "none"},
+		},
+	}
+
+	result := spdxextract.ExtractPackages(doc)
+
+	want := []string{"cpe:2.3:a:openssl:openssl:3.0.0:*:*:*:*:*:*:*", "cpe:/a:legacy:legacy:1.0", ""}
+	for i := range want {
+		if result[i].CPE != want[i] {
+			t.Errorf("Expected CPE %q for %s, got %q", want[i], result[i].Name, result[i].CPE)
+		}
+	}
+}
+
+// TestExtractPackages_CPEURL tests that packages without a homepage, download location, or supported purl get a URL
+// generated from their CPE.
+func TestExtractPackages_CPEURL(t *testing.T) {
+	t.Parallel()
+
+	cpeRef := spdxextract.ExternalRef{
+		ReferenceCategory: "SECURITY",
+		ReferenceType:     "cpe23Type",
+		ReferenceLocator:  "cpe:2.3:a:gnu:bash:5.2:*:*:*:*:*:*:*",
+	}
+	doc := &spdxextract.Document{
+		Packages: []spdxextract.Package{
+			{Name: "bash", ExternalRefs: []spdxextract.ExternalRef{cpeRef}},
+			{
+				Name:         "homepage",
+				Homepage:     "https://www.gnu.org/software/bash",
+				ExternalRefs: []spdxextract.ExternalRef{cpeRef},
+			},
+		},
+	}
+
+	result := spdxextract.ExtractPackages(doc)
+
+	want := "https://nvd.nist.gov/products/cpe/search/results?namingFormat=2.3&keyword=cpe%3A2.3%3Aa%3Agnu%3Abash"
+	if result[0].URL == nil || *result[0].URL != want {
+		t.Errorf("Expected CPE URL %q, got %v", want, result[0].URL)
+	}
+	if got := result[0].Provenance[attribution.FieldURL]; got != attribution.ProvenanceGenerated {
+		t.Errorf("Expected URL provenance %q, got %q", attribution.ProvenanceGenerated, got)
+	}
+	if result[1].URL == nil || *result[1].URL != "https://www.gnu.org/software/bash" {
+		t.Errorf("Expected the homepage to win over the CPE, got %v", result[1].URL)
+	}
+}
+
+// TestExtractPackages_Checksums tests that checksums are extracted with normalized algorithm names.
+func TestExtractPackages_Checksums(t *testing.T) {
+	t.Parallel()
+
+	doc := &spdxextract.Document{
+		Packages: []spdxextract.Package{
+			{
+				Name: "lodash",
+				Checksums: []spdxextract.Checksum{
+					{Algorithm: "SHA256", ChecksumValue: "abc123"},
+					{Algorithm: "BLAKE2b-256", ChecksumValue: "def456"},
+					{Algorithm: "MD5", ChecksumValue: ""},
+				},
+			},
+			{Name: "none"},
+		},
+	}
+
+	result := spdxextract.ExtractPackages(doc)
+
+	want := map[string]string{"SHA256": "abc123", "BLAKE2B-256": "def456"}
+	if !maps.Equal(result[0].Hashes, want) {
+		t.Errorf("Expected hashes %v, got %v", want, result[0].Hashes)
+	}
+	if result[1].Hashes != nil {
+		t.Errorf("Expected nil hashes without checksums, got %v", result[1].Hashes)
+	}
+}
+
+// TestExtractPackages_Supplier tests that the supplier name is extracted without the entity type and email.
+func TestExtractPackages_Supplier(t *testing.T) {
+	t.Parallel()
+
+	doc := &spdxextract.Document{
+		Packages: []spdxextract.Package{
+			{Name: "a", Supplier: "Organization: Acme Corp"},
+			{Name: "b", Supplier: "Person: Jane Doe (jane@example.com)"},
+			{Name: "c", Supplier: "NOASSERTION"},
+		},
+	}
+
+	result := spdxextract.ExtractPackages(doc)
+
+	for i, want := range []string{"Acme Corp", "Jane Doe", ""} {
+		if result[i].Supplier != want {
+			t.Errorf("Expected supplier %q for %s, got %q", want, result[i].Name, result[i].Supplier)
+		}
+	}
+	want := attribution.Party{Kind: attribution.PartyPerson, Name: "Jane Doe", Email: "jane@example.com"}
+	if result[1].SupplierParty == nil || *result[1].SupplierParty != want {
+		t.Errorf("Expected supplier party %+v, got %+v", want, result[1].SupplierParty)
+	}
+	if result[2].SupplierParty != nil {
+		t.Errorf("Expected no supplier party for NOASSERTION, got %+v", result[2].SupplierParty)
+	}
+}
+
+// TestExtractPackages_Originator tests that the originator is extracted as the author.
+func TestExtractPackages_Originator(t *testing.T) {
+	t.Parallel()
+
+	doc := &spdxextract.Document{
+		Packages: []spdxextract.Package{
+			{Name: "a", Originator: "Organization: Acme Corp (oss@acme.example)"},
+			{Name: "b"},
+		},
+	}
+
+	result := spdxextract.ExtractPackages(doc)
+
+	want := attribution.Party{Kind: attribution.PartyOrganization, Name: "Acme Corp", Email: "oss@acme.example"}
+	if result[0].Author != "Acme Corp" || result[0].Originator == nil || *result[0].Originator != want {
+		t.Errorf("Expected author Acme Corp with originator %+v, got %q, %+v", want, result[0].Author,
+			result[0].Originator)
+	}
+	if result[1].Author != "" || result[1].Originator != nil {
+		t.Errorf("Expected no author, got %q, %+v", result[1].Author, result[1].Originator)
+	}
+}
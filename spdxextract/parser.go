@@ -3,6 +3,9 @@ package spdxextract
 import (
 	"encoding/json"
 	"fmt"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/internal/sbom"
 )
 
 // unwrapGitHubSBOM checks if the data is wrapped in GitHub's {"sbom": {...}} format and returns the unwrapped SPDX
@@ -41,3 +44,61 @@ func ParseSBOM(data []byte) (*Document, error) {
 
 	return &doc, nil
 }
+
+// UnmarshalJSON decodes an SPDX document, accepting documentDescribes encoded as a single string or an array.
+func (d *Document) UnmarshalJSON(data []byte) error {
+	type plainDocument Document
+	var raw struct {
+		plainDocument
+
+		DocumentDescribes json.RawMessage `json:"documentDescribes"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	describes, err := sbom.UnmarshalStrings(raw.DocumentDescribes)
+	if err != nil {
+		return fmt.Errorf("documentDescribes: %w", err)
+	}
+
+	*d = Document(raw.plainDocument)
+	d.DocumentDescribes = describes
+	return nil
+}
+
+// UnmarshalJSON decodes an SPDX package, accepting licenseConcluded, licenseDeclared, and attributionTexts encoded as
+// a single string or an array of strings, as some generators do. Multiple licenses are joined with AND, since the
+// package is subject to all of them.
+func (p *Package) UnmarshalJSON(data []byte) error {
+	type plainPackage Package
+	var raw struct {
+		plainPackage
+
+		LicenseConcluded json.RawMessage `json:"licenseConcluded"`
+		LicenseDeclared  json.RawMessage `json:"licenseDeclared"`
+		AttributionTexts json.RawMessage `json:"attributionTexts"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	concluded, err := sbom.UnmarshalStrings(raw.LicenseConcluded)
+	if err != nil {
+		return fmt.Errorf("licenseConcluded: %w", err)
+	}
+	declared, err := sbom.UnmarshalStrings(raw.LicenseDeclared)
+	if err != nil {
+		return fmt.Errorf("licenseDeclared: %w", err)
+	}
+	texts, err := sbom.UnmarshalStrings(raw.AttributionTexts)
+	if err != nil {
+		return fmt.Errorf("attributionTexts: %w", err)
+	}
+
+	*p = Package(raw.plainPackage)
+	p.LicenseConcluded = attribution.JoinLicenses(concluded)
+	p.LicenseDeclared = attribution.JoinLicenses(declared)
+	p.AttributionTexts = texts
+	return nil
+}
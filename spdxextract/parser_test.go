@@ -2,6 +2,7 @@ package spdxextract_test
 
 import (
 	"os"
+	"slices"
 	"testing"
 
 	"github.com/boringbin/sbomattr/spdxextract"
@@ -100,3 +101,53 @@ func TestParseSBOM_EmptyWrapper(t *testing.T) {
 		t.Errorf("Expected 0 packages, got %d", len(doc.Packages))
 	}
 }
+
+// TestParseSBOM_StringOrArrayFields tests that fields some generators encode as arrays are accepted.
+func TestParseSBOM_StringOrArrayFields(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{
+		"spdxVersion": "SPDX-2.3",
+		"SPDXID": "SPDXRef-DOCUMENT",
+		"documentDescribes": "SPDXRef-app",
+		"packages": [
+			{"SPDXID": "SPDXRef-app", "name": "app", "licenseConcluded": ["MIT", "Apache-2.0 OR BSD-3-Clause"]},
+			{"name": "single", "licenseDeclared": ["ISC"], "licenseConcluded": null},
+			{"name": "plain", "licenseConcluded": "MIT", "versionInfo": "1.0.0", "attributionTexts": "Copyright Acme"}
+		]
+	}`)
+
+	doc, err := spdxextract.ParseSBOM(data)
+	if err != nil {
+		t.Fatalf("ParseSBOM failed: %v", err)
+	}
+
+	if len(doc.DocumentDescribes) != 1 || doc.DocumentDescribes[0] != "SPDXRef-app" {
+		t.Errorf("Expected documentDescribes [SPDXRef-app], got %v", doc.DocumentDescribes)
+	}
+	if want := "MIT AND (Apache-2.0 OR BSD-3-Clause)"; doc.Packages[0].LicenseConcluded != want {
+		t.Errorf("Expected concluded license %q, got %q", want, doc.Packages[0].LicenseConcluded)
+	}
+	if doc.Packages[0].SPDXID != "SPDXRef-app" {
+		t.Errorf("Expected SPDXID to be kept, got %q", doc.Packages[0].SPDXID)
+	}
+	if doc.Packages[1].LicenseDeclared != "ISC" || doc.Packages[1].LicenseConcluded != "" {
+		t.Errorf("Expected declared ISC and no concluded license, got %+v", doc.Packages[1])
+	}
+	if doc.Packages[2].LicenseConcluded != "MIT" || doc.Packages[2].VersionInfo != "1.0.0" {
+		t.Errorf("Expected plain string fields to be kept, got %+v", doc.Packages[2])
+	}
+	if want := []string{"Copyright Acme"}; !slices.Equal(doc.Packages[2].AttributionTexts, want) {
+		t.Errorf("Expected attribution texts %v, got %v", want, doc.Packages[2].AttributionTexts)
+	}
+}
+
+// TestParseSBOM_InvalidLicenseField tests that license fields of the wrong type are still rejected.
+func TestParseSBOM_InvalidLicenseField(t *testing.T) {
+	t.Parallel()
+
+	_, err := spdxextract.ParseSBOM([]byte(`{"spdxVersion": "SPDX-2.3", "packages": [{"licenseConcluded": 42}]}`))
+	if err == nil {
+		t.Error("Expected error for numeric license, got nil")
+	}
+}
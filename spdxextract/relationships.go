@@ -0,0 +1,157 @@
+package spdxextract
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// Filter selects which packages ExtractPackagesWithOptions keeps, based on the relationships of the document.
+type Filter string
+
+const (
+	// FilterAll keeps every package.
+	FilterAll Filter = ""
+	// FilterReachable keeps the packages reachable from the described packages through any relationship, excluding
+	// unrelated analysis artifacts some scanners dump into packages.
+	FilterReachable Filter = "reachable"
+	// FilterDependsOn keeps the packages reachable from the described packages through DEPENDS_ON relationships
+	// (or DEPENDENCY_OF in the other direction) only.
+	FilterDependsOn Filter = "depends-on"
+)
+
+// ParseFilter parses a filter name ("reachable" or "depends-on"), ignoring case. An empty name is FilterAll.
+// Returns an error for unknown filters.
+func ParseFilter(s string) (Filter, error) {
+	s = strings.TrimSpace(s)
+	for _, filter := range []Filter{FilterAll, FilterReachable, FilterDependsOn} {
+		if strings.EqualFold(string(filter), s) {
+			return filter, nil
+		}
+	}
+	return "", fmt.Errorf("unknown SPDX relationship filter %q (valid filters: reachable, depends-on)", s)
+}
+
+// ExtractOptions configures ExtractPackagesWithOptions.
+type ExtractOptions struct {
+	// Filter limits the packages to those related to the described packages. Defaults to FilterAll.
+	Filter Filter
+}
+
+// ExtractPackagesWithOptions extracts packages like ExtractPackages, keeping only the packages selected by the filter.
+// The described packages themselves are always kept. If the document does not record a described package, the
+// relationships have no starting point, so every package is kept.
+func ExtractPackagesWithOptions(doc *Document, opts ExtractOptions) []attribution.Attribution {
+	if doc == nil || opts.Filter == FilterAll {
+		return ExtractPackages(doc)
+	}
+
+	roots := describedIDs(doc)
+	if len(roots) == 0 {
+		return ExtractPackages(doc)
+	}
+
+	edges := make(map[string][]string)
+	for _, rel := range doc.Relationships {
+		if from, to, ok := relationshipEdge(rel, opts.Filter); ok {
+			edges[from] = append(edges[from], to)
+		}
+	}
+
+	reachable := make(map[string]bool)
+	queue := roots
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if reachable[id] {
+			continue
+		}
+		reachable[id] = true
+		queue = append(queue, edges[id]...)
+	}
+
+	filtered := *doc
+	filtered.Packages = slices.DeleteFunc(slices.Clone(doc.Packages), func(pkg Package) bool {
+		return !reachable[pkg.SPDXID]
+	})
+	return ExtractPackages(&filtered)
+}
+
+// relationshipEdge returns the direction in which a relationship leads away from the described packages, if the
+// filter follows it. Relationship types ending in _OF, _BY, or _FOR (e.g. CONTAINED_BY, DEPENDENCY_OF) point from the
+// dependency to the dependent, so they are followed in reverse.
+func relationshipEdge(rel Relationship, filter Filter) (string, string, bool) {
+	relType := strings.ToUpper(strings.TrimSpace(rel.RelationshipType))
+	if rel.SPDXElementID == "" || rel.RelatedSPDXElement == "" {
+		return "", "", false
+	}
+
+	switch filter {
+	case FilterDependsOn:
+		switch relType {
+		case "DEPENDS_ON":
+			return rel.SPDXElementID, rel.RelatedSPDXElement, true
+		case "DEPENDENCY_OF":
+			return rel.RelatedSPDXElement, rel.SPDXElementID, true
+		default:
+			return "", "", false
+		}
+	case FilterReachable:
+		for _, inverse := range []string{"_OF", "_BY", "_FOR"} {
+			if strings.HasSuffix(relType, inverse) {
+				return rel.RelatedSPDXElement, rel.SPDXElementID, true
+			}
+		}
+		return rel.SPDXElementID, rel.RelatedSPDXElement, true
+	default:
+		return "", "", false
+	}
+}
+
+// describedIDs returns the SPDX IDs of the packages the document describes, from its documentDescribes field and
+// DESCRIBES (or DESCRIBED_BY) relationships, in document order.
+func describedIDs(doc *Document) []string {
+	documentID := cmp.Or(doc.SPDXID, "SPDXRef-DOCUMENT")
+	described := slices.Clone(doc.DocumentDescribes)
+	for _, rel := range doc.Relationships {
+		switch {
+		case rel.SPDXElementID == documentID && rel.RelationshipType == "DESCRIBES":
+			described = append(described, rel.RelatedSPDXElement)
+		case rel.RelatedSPDXElement == documentID && rel.RelationshipType == "DESCRIBED_BY":
+			described = append(described, rel.SPDXElementID)
+		}
+	}
+	return described
+}
+
+// Dependencies returns the dependencies between the packages of the document declared by DEPENDS_ON (or
+// DEPENDENCY_OF) relationships, identified by the attribution.DedupKey of the attributions ExtractPackages returns
+// for them. Relationships with elements that are not packages (e.g. files) are skipped.
+func Dependencies(doc *Document) []attribution.Edge {
+	if doc == nil {
+		return nil
+	}
+
+	attributions := ExtractPackages(doc)
+	keys := make(map[string]string, len(doc.Packages))
+	for i, pkg := range doc.Packages {
+		keys[pkg.SPDXID] = attribution.DedupKey(attributions[i])
+	}
+
+	var edges []attribution.Edge
+	for _, rel := range doc.Relationships {
+		from, to, ok := relationshipEdge(rel, FilterDependsOn)
+		if !ok {
+			continue
+		}
+		fromKey, fromFound := keys[from]
+		toKey, toFound := keys[to]
+		if fromFound && toFound {
+			edges = append(edges, attribution.Edge{From: fromKey, To: toKey})
+		}
+	}
+	return edges
+}
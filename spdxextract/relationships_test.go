@@ -0,0 +1,141 @@
+package spdxextract_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/spdxextract"
+)
+
+// TestExtractPackagesWithOptions tests limiting packages to those related to the described package.
+func TestExtractPackagesWithOptions(t *testing.T) {
+	t.Parallel()
+
+	doc := &spdxextract.Document{
+		SPDXID: "SPDXRef-DOCUMENT",
+		Packages: []spdxextract.Package{
+			{SPDXID: "SPDXRef-app", Name: "app"},
+			{SPDXID: "SPDXRef-lodash", Name: "lodash"},
+			{SPDXID: "SPDXRef-react", Name: "react"},
+			{SPDXID: "SPDXRef-vendored", Name: "vendored"},
+			{SPDXID: "SPDXRef-scanner-artifact", Name: "scanner-artifact"},
+			{SPDXID: "SPDXRef-loose-types", Name: "loose-types"},
+		},
+		Relationships: []spdxextract.Relationship{
+			{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: "SPDXRef-app"},
+			{SPDXElementID: "SPDXRef-app", RelationshipType: "DEPENDS_ON", RelatedSPDXElement: "SPDXRef-lodash"},
+			{SPDXElementID: "SPDXRef-react", RelationshipType: "DEPENDENCY_OF", RelatedSPDXElement: "SPDXRef-app"},
+			{SPDXElementID: "SPDXRef-vendored", RelationshipType: "CONTAINED_BY", RelatedSPDXElement: "SPDXRef-app"},
+			{
+				SPDXElementID:      "SPDXRef-loose-types",
+				RelationshipType:   "DEPENDENCY_OF",
+				RelatedSPDXElement: "SPDXRef-vendored",
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		filter spdxextract.Filter
+		want   []string
+	}{
+		{
+			name:   "all",
+			filter: spdxextract.FilterAll,
+			want:   []string{"app", "lodash", "react", "vendored", "scanner-artifact", "loose-types"},
+		},
+		{
+			name:   "reachable",
+			filter: spdxextract.FilterReachable,
+			want:   []string{"app", "lodash", "react", "vendored", "loose-types"},
+		},
+		{name: "depends on", filter: spdxextract.FilterDependsOn, want: []string{"app", "lodash", "react"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			attrs := spdxextract.ExtractPackagesWithOptions(doc, spdxextract.ExtractOptions{Filter: tt.filter})
+			var got []string
+			for _, a := range attrs {
+				got = append(got, a.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ExtractPackagesWithOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestExtractPackagesWithOptions_NoDescribedPackage tests that every package is kept if the document does not record
+// a described package.
+func TestExtractPackagesWithOptions_NoDescribedPackage(t *testing.T) {
+	t.Parallel()
+
+	doc := &spdxextract.Document{
+		Packages: []spdxextract.Package{{SPDXID: "SPDXRef-a", Name: "a"}, {SPDXID: "SPDXRef-b", Name: "b"}},
+	}
+
+	got := spdxextract.ExtractPackagesWithOptions(doc, spdxextract.ExtractOptions{Filter: spdxextract.FilterDependsOn})
+	if len(got) != 2 {
+		t.Errorf("ExtractPackagesWithOptions() = %+v, want both packages", got)
+	}
+	if got := spdxextract.ExtractPackagesWithOptions(nil, spdxextract.ExtractOptions{}); len(got) != 0 {
+		t.Errorf("ExtractPackagesWithOptions(nil) = %+v, want none", got)
+	}
+}
+
+// TestDependencies tests the Dependencies function.
+func TestDependencies(t *testing.T) {
+	t.Parallel()
+
+	doc := &spdxextract.Document{
+		Packages: []spdxextract.Package{
+			{SPDXID: "SPDXRef-app", Name: "app"},
+			{
+				SPDXID: "SPDXRef-lodash",
+				Name:   "lodash",
+				ExternalRefs: []spdxextract.ExternalRef{
+					{ReferenceType: "purl", ReferenceLocator: "pkg:npm/lodash@4.17.21"},
+				},
+			},
+			{SPDXID: "SPDXRef-react", Name: "react"},
+		},
+		Relationships: []spdxextract.Relationship{
+			{SPDXElementID: "SPDXRef-app", RelationshipType: "DEPENDS_ON", RelatedSPDXElement: "SPDXRef-lodash"},
+			{SPDXElementID: "SPDXRef-react", RelationshipType: "DEPENDENCY_OF", RelatedSPDXElement: "SPDXRef-app"},
+			{SPDXElementID: "SPDXRef-app", RelationshipType: "CONTAINS", RelatedSPDXElement: "SPDXRef-react"},
+			{SPDXElementID: "SPDXRef-app", RelationshipType: "DEPENDS_ON", RelatedSPDXElement: "SPDXRef-file"},
+		},
+	}
+
+	got := spdxextract.Dependencies(doc)
+
+	want := []attribution.Edge{{From: "app", To: "pkg:npm/lodash@4.17.21"}, {From: "app", To: "react"}}
+	if !slices.Equal(got, want) {
+		t.Errorf("Dependencies() = %v, want %v", got, want)
+	}
+	if got := spdxextract.Dependencies(nil); len(got) != 0 {
+		t.Errorf("Dependencies(nil) = %v, want none", got)
+	}
+}
+
+// TestParseFilter tests the ParseFilter function.
+func TestParseFilter(t *testing.T) {
+	t.Parallel()
+
+	for input, want := range map[string]spdxextract.Filter{
+		"":            spdxextract.FilterAll,
+		"Reachable":   spdxextract.FilterReachable,
+		" depends-on": spdxextract.FilterDependsOn,
+	} {
+		if got, err := spdxextract.ParseFilter(input); err != nil || got != want {
+			t.Errorf("ParseFilter(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := spdxextract.ParseFilter("contains"); err == nil {
+		t.Error(`ParseFilter("contains") expected error, got nil`)
+	}
+}
@@ -4,19 +4,45 @@ package spdxextract
 
 // Document represents a minimal SPDX document with only the fields we need.
 type Document struct {
-	SPDXVersion string    `json:"spdxVersion"`
-	SPDXID      string    `json:"SPDXID"`
-	Packages    []Package `json:"packages"`
+	SPDXVersion       string         `json:"spdxVersion"`
+	SPDXID            string         `json:"SPDXID"`
+	DocumentDescribes []string       `json:"documentDescribes"`
+	Packages          []Package      `json:"packages"`
+	Relationships     []Relationship `json:"relationships"`
+	// ExtractedLicenses are the licenses referenced by LicenseRef- identifiers.
+	ExtractedLicenses []ExtractedLicense `json:"hasExtractedLicensingInfos"`
+}
+
+// ExtractedLicense represents a license that is not on the SPDX license list, referenced by a LicenseRef- identifier.
+type ExtractedLicense struct {
+	LicenseID     string   `json:"licenseId"`
+	ExtractedText string   `json:"extractedText"`
+	SeeAlsos      []string `json:"seeAlsos"`
 }
 
 // Package represents a minimal SPDX package with only the fields we need.
 type Package struct {
+	SPDXID           string        `json:"SPDXID"`
 	Name             string        `json:"name"`
 	VersionInfo      string        `json:"versionInfo"`
+	Description      string        `json:"description"`
+	Summary          string        `json:"summary"`
 	Homepage         string        `json:"homepage"`
+	DownloadLocation string        `json:"downloadLocation"`
+	SourceInfo       string        `json:"sourceInfo"`
+	Supplier         string        `json:"supplier"`
+	Originator       string        `json:"originator"`
 	LicenseConcluded string        `json:"licenseConcluded"`
 	LicenseDeclared  string        `json:"licenseDeclared"`
+	AttributionTexts []string      `json:"attributionTexts"`
 	ExternalRefs     []ExternalRef `json:"externalRefs"`
+	Checksums        []Checksum    `json:"checksums"`
+}
+
+// Checksum represents a package checksum.
+type Checksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
 }
 
 // ExternalRef represents an external reference (like purl).
@@ -25,3 +51,10 @@ type ExternalRef struct {
 	ReferenceType     string `json:"referenceType"`
 	ReferenceLocator  string `json:"referenceLocator"`
 }
+
+// Relationship represents a relationship between two SPDX elements (like DESCRIBES).
+type Relationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
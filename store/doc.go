@@ -0,0 +1,6 @@
+// Package store persists aggregated attribution results across runs, keyed by product and version, and provides
+// query helpers for longitudinal views such as "when did GPL code first appear in product X".
+//
+// Store is the storage interface; FileStore is the filesystem JSON backend. Other backends (for example SQLite or
+// object storage) can be plugged in by implementing Store.
+package store
@@ -0,0 +1,130 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// FileStore is a Store backed by JSON files on the local filesystem.
+// Each run is stored as <dir>/<product>/<version>.json, with product and version path-escaped.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore returns a FileStore rooted at dir. The directory is created on the first Save.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+// Save stores a run as a JSON file, replacing any stored run for the same product and version.
+// Returns ErrInvalidRun if the run has no product.
+func (s *FileStore) Save(ctx context.Context, run Run) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if run.Product == "" {
+		return fmt.Errorf("%w: product is required", ErrInvalidRun)
+	}
+
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode run: %w", err)
+	}
+
+	productDir := filepath.Join(s.dir, url.PathEscape(run.Product))
+	const dirPerm = 0o755
+	if err = os.MkdirAll(productDir, dirPerm); err != nil {
+		return fmt.Errorf("create product directory: %w", err)
+	}
+
+	// Write to a temporary file first so a failed write never leaves a truncated run behind
+	path := filepath.Join(productDir, url.PathEscape(run.Version)+".json")
+	tmp := path + ".tmp"
+	const filePerm = 0o644
+	if err = os.WriteFile(tmp, data, filePerm); err != nil {
+		return fmt.Errorf("write run: %w", err)
+	}
+	if err = os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("write run: %w", err)
+	}
+
+	return nil
+}
+
+// Runs returns the stored runs of a product, oldest first.
+// Returns an empty slice if the product has no stored runs.
+func (s *FileStore) Runs(ctx context.Context, product string) ([]Run, error) {
+	productDir := filepath.Join(s.dir, url.PathEscape(product))
+	entries, err := os.ReadDir(productDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []Run{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read product directory: %w", err)
+	}
+
+	runs := make([]Run, 0, len(entries))
+	for _, entry := range entries {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		data, readErr := os.ReadFile(filepath.Join(productDir, entry.Name()))
+		if readErr != nil {
+			return nil, fmt.Errorf("read run: %w", readErr)
+		}
+		var run Run
+		if err = json.Unmarshal(data, &run); err != nil {
+			return nil, fmt.Errorf("decode run %s: %w", entry.Name(), err)
+		}
+		runs = append(runs, run)
+	}
+
+	slices.SortStableFunc(runs, func(a, b Run) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+
+	return runs, nil
+}
+
+// Products returns the names of the products with stored runs, sorted.
+// Returns an empty slice if the store directory does not exist yet.
+func (s *FileStore) Products(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read store directory: %w", err)
+	}
+
+	products := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		product, unescapeErr := url.PathUnescape(entry.Name())
+		if unescapeErr != nil {
+			continue
+		}
+		products = append(products, product)
+	}
+
+	slices.Sort(products)
+	return products, nil
+}
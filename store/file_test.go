@@ -0,0 +1,118 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/store"
+)
+
+// TestFileStore_SaveAndRuns tests saving runs and reading them back.
+func TestFileStore_SaveAndRuns(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := store.NewFileStore(t.TempDir())
+
+	license := "MIT"
+	older := store.Run{
+		Product:      "acme/server",
+		Version:      "v1.0.0",
+		Timestamp:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Attributions: []attribution.Attribution{{Name: "left-pad", License: &license, Purl: "pkg:npm/left-pad@1.3.0"}},
+	}
+	newer := store.Run{
+		Product:   "acme/server",
+		Version:   "v1.1.0",
+		Timestamp: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	// Save out of order to check sorting
+	for _, run := range []store.Run{newer, older} {
+		if err := s.Save(ctx, run); err != nil {
+			t.Fatalf("Save(%s) error = %v", run.Version, err)
+		}
+	}
+
+	runs, err := s.Runs(ctx, "acme/server")
+	if err != nil {
+		t.Fatalf("Runs() error = %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("Runs() returned %d runs, want 2", len(runs))
+	}
+	if runs[0].Version != "v1.0.0" || runs[1].Version != "v1.1.0" {
+		t.Errorf("Runs() versions = %s, %s, want v1.0.0, v1.1.0", runs[0].Version, runs[1].Version)
+	}
+	if len(runs[0].Attributions) != 1 || runs[0].Attributions[0].Name != "left-pad" {
+		t.Errorf("Runs()[0].Attributions = %+v, want left-pad", runs[0].Attributions)
+	}
+
+	products, err := s.Products(ctx)
+	if err != nil {
+		t.Fatalf("Products() error = %v", err)
+	}
+	if len(products) != 1 || products[0] != "acme/server" {
+		t.Errorf("Products() = %v, want [acme/server]", products)
+	}
+}
+
+// TestFileStore_SaveReplaces tests that saving the same product version replaces the stored run.
+func TestFileStore_SaveReplaces(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := store.NewFileStore(t.TempDir())
+
+	for _, name := range []string{"first", "second"} {
+		run := store.Run{
+			Product:      "app",
+			Version:      "v1",
+			Attributions: []attribution.Attribution{{Name: name}},
+		}
+		if err := s.Save(ctx, run); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	runs, err := s.Runs(ctx, "app")
+	if err != nil {
+		t.Fatalf("Runs() error = %v", err)
+	}
+	if len(runs) != 1 || runs[0].Attributions[0].Name != "second" {
+		t.Errorf("Runs() = %+v, want a single run with the second result", runs)
+	}
+}
+
+// TestFileStore_SaveInvalid tests that a run without a product is rejected.
+func TestFileStore_SaveInvalid(t *testing.T) {
+	t.Parallel()
+
+	s := store.NewFileStore(t.TempDir())
+
+	err := s.Save(context.Background(), store.Run{Version: "v1"})
+	if !errors.Is(err, store.ErrInvalidRun) {
+		t.Errorf("Save() error = %v, want ErrInvalidRun", err)
+	}
+}
+
+// TestFileStore_Empty tests reading from a store that does not exist yet.
+func TestFileStore_Empty(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := store.NewFileStore(t.TempDir() + "/missing")
+
+	products, err := s.Products(ctx)
+	if err != nil || len(products) != 0 {
+		t.Errorf("Products() = %v, %v, want empty, nil", products, err)
+	}
+
+	runs, err := s.Runs(ctx, "app")
+	if err != nil || len(runs) != 0 {
+		t.Errorf("Runs() = %v, %v, want empty, nil", runs, err)
+	}
+}
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// Match is an attribution found in a stored run, with its product and version context.
+type Match struct {
+	// Product is the product of the run the attribution was found in.
+	Product string `json:"product"`
+	// Version is the product version of the run the attribution was found in.
+	Version string `json:"version"`
+	// Timestamp is when the run happened.
+	Timestamp time.Time `json:"timestamp"`
+	// Attribution is the matching attribution.
+	Attribution attribution.Attribution `json:"attribution"`
+}
+
+// MatchLicense returns a predicate matching attributions whose license expression references a license identifier
+// starting with id, ignoring case. For example, "GPL" matches "GPL-2.0-only" and "MIT OR GPL-3.0-or-later", but not
+// "LGPL-2.1-only".
+func MatchLicense(id string) func(attribution.Attribution) bool {
+	return func(a attribution.Attribution) bool {
+		if a.License == nil {
+			return false
+		}
+		_, ok := attribution.FindLicenseID(*a.License, id)
+		return ok
+	}
+}
+
+// Search returns the attributions matching the predicate across the stored runs of a product, oldest run first.
+// An empty product searches every product.
+func Search(
+	ctx context.Context,
+	s Store,
+	product string,
+	match func(attribution.Attribution) bool,
+) ([]Match, error) {
+	products := []string{product}
+	if product == "" {
+		var err error
+		if products, err = s.Products(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	matches := []Match{}
+	for _, p := range products {
+		runs, err := s.Runs(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		for _, run := range runs {
+			for _, a := range run.Attributions {
+				if match(a) {
+					matches = append(matches, Match{
+						Product:     run.Product,
+						Version:     run.Version,
+						Timestamp:   run.Timestamp,
+						Attribution: a,
+					})
+				}
+			}
+		}
+	}
+
+	return matches, nil
+}
+
+// FirstAppearance returns the oldest of the runs (as returned by Store.Runs) containing an attribution matching the
+// predicate, together with the matching attributions. Returns ok as false if no run matches.
+func FirstAppearance(runs []Run, match func(attribution.Attribution) bool) (Run, []attribution.Attribution, bool) {
+	for _, run := range runs {
+		var matched []attribution.Attribution
+		for _, a := range run.Attributions {
+			if match(a) {
+				matched = append(matched, a)
+			}
+		}
+		if len(matched) > 0 {
+			return run, matched, true
+		}
+	}
+	return Run{}, nil, false
+}
@@ -0,0 +1,129 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/store"
+)
+
+// TestMatchLicense tests the MatchLicense predicate.
+func TestMatchLicense(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		id      string
+		license *string
+		want    bool
+	}{
+		{id: "GPL", license: strPtr("GPL-2.0-only"), want: true},
+		{id: "GPL", license: strPtr("MIT OR GPL-3.0-or-later"), want: true},
+		{id: "GPL", license: strPtr("LGPL-2.1-only"), want: false},
+		{id: "agpl-3.0", license: strPtr("AGPL-3.0-only"), want: true},
+		{id: "MIT", license: nil, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			t.Parallel()
+
+			got := store.MatchLicense(tt.id)(attribution.Attribution{License: tt.license})
+			if got != tt.want {
+				t.Errorf("MatchLicense(%q)(%v) = %v, want %v", tt.id, deref(tt.license), got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSearch tests searching a single product and all products.
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := store.NewFileStore(t.TempDir())
+
+	runs := []store.Run{
+		{Product: "api", Version: "v1", Attributions: []attribution.Attribution{
+			{Name: "a", License: strPtr("MIT")},
+			{Name: "b", License: strPtr("AGPL-3.0-only")},
+		}},
+		{Product: "web", Version: "v2", Attributions: []attribution.Attribution{
+			{Name: "c", License: strPtr("AGPL-3.0-or-later")},
+		}},
+	}
+	for _, run := range runs {
+		if err := s.Save(ctx, run); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	matches, err := store.Search(ctx, s, "", store.MatchLicense("AGPL-3.0"))
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("Search() returned %d matches, want 2", len(matches))
+	}
+	if matches[0].Product != "api" || matches[0].Attribution.Name != "b" {
+		t.Errorf("Search()[0] = %+v, want api/b", matches[0])
+	}
+	if matches[1].Product != "web" || matches[1].Version != "v2" {
+		t.Errorf("Search()[1] = %+v, want web v2", matches[1])
+	}
+
+	matches, err = store.Search(ctx, s, "web", store.MatchLicense("MIT"))
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("Search(web, MIT) = %+v, want no matches", matches)
+	}
+}
+
+// TestFirstAppearance tests finding the first run containing a matching attribution.
+func TestFirstAppearance(t *testing.T) {
+	t.Parallel()
+
+	runs := []store.Run{
+		{Version: "v1", Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Attributions: []attribution.Attribution{
+			{Name: "a", License: strPtr("MIT")},
+		}},
+		{Version: "v2", Timestamp: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Attributions: []attribution.Attribution{
+			{Name: "a", License: strPtr("MIT")},
+			{Name: "b", License: strPtr("GPL-3.0-only")},
+		}},
+		{Version: "v3", Timestamp: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Attributions: []attribution.Attribution{
+			{Name: "b", License: strPtr("GPL-3.0-only")},
+		}},
+	}
+
+	run, matched, ok := store.FirstAppearance(runs, store.MatchLicense("GPL"))
+	if !ok {
+		t.Fatal("FirstAppearance() ok = false, want true")
+	}
+	if run.Version != "v2" {
+		t.Errorf("FirstAppearance() version = %s, want v2", run.Version)
+	}
+	if len(matched) != 1 || matched[0].Name != "b" {
+		t.Errorf("FirstAppearance() matched = %+v, want [b]", matched)
+	}
+
+	if _, _, ok = store.FirstAppearance(runs, store.MatchLicense("BSD")); ok {
+		t.Error("FirstAppearance(BSD) ok = true, want false")
+	}
+}
+
+// strPtr returns a pointer to s.
+func strPtr(s string) *string {
+	return &s
+}
+
+// deref returns the value of s, or "<nil>" if s is nil.
+func deref(s *string) string {
+	if s == nil {
+		return "<nil>"
+	}
+	return *s
+}
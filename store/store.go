@@ -0,0 +1,35 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/boringbin/sbomattr/attribution"
+)
+
+// ErrInvalidRun is returned when a run cannot be stored, e.g. because it has no product.
+var ErrInvalidRun = errors.New("invalid run")
+
+// Run is the aggregated attribution result of one run for a product version.
+type Run struct {
+	// Product identifies the product the SBOMs belong to, e.g. "acme-server".
+	Product string `json:"product"`
+	// Version is the product version, e.g. "v1.2.0".
+	Version string `json:"version"`
+	// Timestamp is when the run happened.
+	Timestamp time.Time `json:"timestamp"`
+	// Attributions is the aggregated attribution result of the run.
+	Attributions []attribution.Attribution `json:"attributions"`
+}
+
+// Store persists runs keyed by product and version.
+// Saving a run for a product version that is already stored replaces it.
+type Store interface {
+	// Save stores a run, replacing any stored run for the same product and version.
+	Save(ctx context.Context, run Run) error
+	// Runs returns the stored runs of a product, oldest first.
+	Runs(ctx context.Context, product string) ([]Run, error)
+	// Products returns the names of the products with stored runs, sorted.
+	Products(ctx context.Context) ([]string, error)
+}
@@ -0,0 +1,39 @@
+package sbomattr
+
+import "github.com/boringbin/sbomattr/attribution"
+
+// Transform rewrites an attribution extracted from an SBOM before deduplication, returning the rewritten attribution
+// and whether to keep it, so organizations can encode their own post-processing rules in Go: renaming packages,
+// rewriting URLs, injecting fields, or dropping internal packages (see Options.Transforms).
+//
+// Results cached in a manifest are transformed again on every run, so a transform must not modify the slices or
+// pointers of the attribution it receives in place; it should replace them instead. Dependencies (Options.Graph) refer
+// to attributions by name, so renamed attributions lose theirs.
+type Transform func(a attribution.Attribution) (attribution.Attribution, bool)
+
+// transform runs the transforms of o on each attribution, in order, and returns the attributions they keep. An
+// attribution dropped by a transform is not passed to the next ones.
+func (o Options) transform(attributions []attribution.Attribution) []attribution.Attribution {
+	if len(o.Transforms) == 0 {
+		return attributions
+	}
+
+	result := make([]attribution.Attribution, 0, len(attributions))
+	for _, a := range attributions {
+		if transformed, keep := o.transformOne(a); keep {
+			result = append(result, transformed)
+		}
+	}
+	return result
+}
+
+// transformOne runs the transforms of o on one attribution, stopping at the first transform that drops it.
+func (o Options) transformOne(a attribution.Attribution) (attribution.Attribution, bool) {
+	for _, transform := range o.Transforms {
+		var keep bool
+		if a, keep = transform(a); !keep {
+			return attribution.Attribution{}, false
+		}
+	}
+	return a, true
+}
@@ -0,0 +1,68 @@
+package sbomattr_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/boringbin/sbomattr"
+	"github.com/boringbin/sbomattr/attribution"
+	"github.com/boringbin/sbomattr/manifest"
+)
+
+func TestWithTransforms(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "sbom.json")
+	content := `{"spdxVersion": "SPDX-2.3", "SPDXID": "SPDXRef-DOCUMENT", "packages": [` +
+		`{"SPDXID": "SPDXRef-a", "name": "lodash", "versionInfo": "4.17.21", ` +
+		`"homepage": "http://lodash.com", "licenseConcluded": "MIT"}, ` +
+		`{"SPDXID": "SPDXRef-b", "name": "@acme/internal-ui", "versionInfo": "1.0.0"}]}`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write SBOM: %v", err)
+	}
+
+	var calls []string
+	dropInternal := func(a attribution.Attribution) (attribution.Attribution, bool) {
+		calls = append(calls, "drop "+a.Name)
+		return a, !strings.HasPrefix(a.Name, "@acme/")
+	}
+	rewriteURL := func(a attribution.Attribution) (attribution.Attribution, bool) {
+		calls = append(calls, "rewrite "+a.Name)
+		if a.URL != nil {
+			url := strings.Replace(*a.URL, "http://", "https://", 1)
+			a.URL = &url
+		}
+		a.Supplier = "Acme Mirror"
+		return a, true
+	}
+
+	m := manifest.New()
+	processor := sbomattr.NewProcessor(
+		sbomattr.WithManifest(m),
+		sbomattr.WithTransforms(dropInternal),
+		sbomattr.WithTransforms(rewriteURL),
+	)
+	attrs, err := processor.ProcessFiles(context.Background(), []string{path})
+	if err != nil {
+		t.Fatalf("ProcessFiles() unexpected error: %v", err)
+	}
+
+	if len(attrs) != 1 || attrs[0].Name != "lodash" {
+		t.Fatalf("ProcessFiles() = %v, want only lodash", attrs)
+	}
+	if attrs[0].URL == nil || *attrs[0].URL != "https://lodash.com" || attrs[0].Supplier != "Acme Mirror" {
+		t.Errorf("ProcessFiles() = %+v, want the URL rewritten and the supplier injected", attrs[0])
+	}
+	want := []string{"drop lodash", "rewrite lodash", "drop @acme/internal-ui"}
+	if strings.Join(calls, ", ") != strings.Join(want, ", ") {
+		t.Errorf("transform calls = %v, want %v", calls, want)
+	}
+
+	// The manifest caches the extracted attributions, so a later run transforms them again
+	if cached := m.Files[path].Attributions; len(cached) != 2 {
+		t.Errorf("cached attributions = %v, want both packages untransformed", cached)
+	}
+}